@@ -48,6 +48,22 @@ func (c *Client) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	return results, err
 }
 
+// Operations takes a list of operation ids, and returns each operation
+// with its actions.
+func (c *Client) Operations(arg params.OperationIds) (params.OperationResults, error) {
+	results := params.OperationResults{}
+	err := c.facade.FacadeCall("Operations", arg, &results)
+	return results, err
+}
+
+// ListActions returns the model's actions that match the given
+// filters, most recently enqueued first.
+func (c *Client) ListActions(arg params.ActionQueryArgs) (params.ActionResults, error) {
+	results := params.ActionResults{}
+	err := c.facade.FacadeCall("ListActions", arg, &results)
+	return results, err
+}
+
 // FindActionsByNames takes a list of action names and returns actions for
 // every name.
 func (c *Client) FindActionsByNames(arg params.FindActionsByNames) (params.ActionsByNames, error) {
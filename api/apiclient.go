@@ -33,7 +33,9 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/compression"
 	"github.com/juju/juju/rpc/jsoncodec"
+	"github.com/juju/juju/rpc/msgpackcodec"
 	"github.com/juju/juju/utils/proxy"
 )
 
@@ -67,6 +69,10 @@ type state struct {
 	conn   *websocket.Conn
 	clock  clock.Clock
 
+	// compression, if not nil, compresses the connection's messages;
+	// it is told when the client has logged in.
+	compression *compression.Conn
+
 	// addr is the address used to connect to the API server.
 	addr string
 
@@ -194,7 +200,8 @@ func open(
 		return nil, errors.Trace(err)
 	}
 
-	client := rpc.NewConn(jsoncodec.NewWebsocket(dialResult.conn), observer.None())
+	codec, compressed := newRPCCodec(dialResult.conn, dialResult.compressed)
+	client := rpc.NewConn(codec, observer.None())
 	client.Start()
 
 	bakeryClient := opts.BakeryClient
@@ -225,10 +232,11 @@ func open(
 	}
 
 	st := &state{
-		client: client,
-		conn:   dialResult.conn,
-		clock:  clock,
-		addr:   apiHost,
+		client:      client,
+		conn:        dialResult.conn,
+		clock:       clock,
+		compression: compressed,
+		addr:        apiHost,
 		cookieURL: &url.URL{
 			Scheme: "https",
 			Host:   apiHost,
@@ -526,9 +534,10 @@ func tagToString(tag names.Tag) string {
 }
 
 type dialResult struct {
-	conn      *websocket.Conn
-	urlStr    string
-	tlsConfig *tls.Config
+	conn       *websocket.Conn
+	urlStr     string
+	compressed bool
+	tlsConfig  *tls.Config
 }
 
 // dialAPI establishes a websocket connection to the RPC
@@ -582,12 +591,12 @@ func dialAPI(info *Info, opts DialOpts) (*dialResult, error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	conn, urlStr, err := dialWebsocketMulti(info.Addrs, path, opts)
+	wrapper, err := dialWebsocketMulti(info.Addrs, path, opts)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
-	logger.Infof("connection established to %q", urlStr)
-	return &dialResult{conn, urlStr, tlsConfig}, nil
+	logger.Infof("connection established to %q", wrapper.urlStr)
+	return &dialResult{wrapper.conn, wrapper.urlStr, wrapper.compressed, tlsConfig}, nil
 }
 
 type websocketDialerAdapter struct {
@@ -604,7 +613,7 @@ func (a *websocketDialerAdapter) Dial(urlStr string, tlsConfig *tls.Config, requ
 // specified URL path, TLS configuration, and dial options. Each of the
 // specified addresses will be attempted concurrently, and the first
 // successful connection will be returned.
-func dialWebsocketMulti(addrs []string, path string, opts DialOpts) (*websocket.Conn, string, error) {
+func dialWebsocketMulti(addrs []string, path string, opts DialOpts) (*connWrapper, error) {
 	// Dial all addresses at reasonable intervals.
 	try := parallel.NewTry(0, nil)
 	defer try.Kill()
@@ -614,7 +623,7 @@ func dialWebsocketMulti(addrs []string, path string, opts DialOpts) (*websocket.
 			break
 		}
 		if err != nil {
-			return nil, "", errors.Trace(err)
+			return nil, errors.Trace(err)
 		}
 		select {
 		case <-time.After(opts.DialAddressInterval):
@@ -624,10 +633,9 @@ func dialWebsocketMulti(addrs []string, path string, opts DialOpts) (*websocket.
 	try.Close()
 	result, err := try.Result()
 	if err != nil {
-		return nil, "", errors.Trace(err)
+		return nil, errors.Trace(err)
 	}
-	wrapper := result.(*connWrapper)
-	return wrapper.conn, wrapper.urlStr, nil
+	return result.(*connWrapper), nil
 }
 
 // startDialWebsocket starts websocket connection to a single address
@@ -643,10 +651,12 @@ func startDialWebsocket(try *parallel.Try, addr, path string, opts DialOpts) err
 // connWrapper contains the *websocket.Conn and the urlStr that was used
 // to connect to it. The gorilla/websocket code does not remember the URL
 // that was used to connect to it, and many internal parts of Juju assume
-// that it does.
+// that it does. It also records whether the server agreed to compress
+// the connection's messages.
 type connWrapper struct {
-	conn   *websocket.Conn
-	urlStr string
+	conn       *websocket.Conn
+	urlStr     string
+	compressed bool
 }
 
 // This is defined for the parallel try to close other results.
@@ -676,11 +686,10 @@ func newWebsocketDialer(urlStr string, opts DialOpts) func(<-chan struct{}) (io.
 			default:
 			}
 			logger.Debugf("dialing %q", urlStr)
-			// Not passing through any extra header information
-			conn, _, err := opts.DialWebsocket(urlStr, opts.tlsConfig, nil)
+			conn, resp, err := opts.DialWebsocket(urlStr, opts.tlsConfig, rpcRequestHeader())
 			if err == nil {
 				logger.Debugf("successfully dialed %q", urlStr)
-				return &connWrapper{conn, urlStr}, nil
+				return &connWrapper{conn, urlStr, compressionAccepted(resp)}, nil
 			}
 			if isCertErr := isX509Error(err); !a.HasNext() || isCertErr {
 				// We won't reconnect when there's an X509
@@ -698,6 +707,40 @@ func newWebsocketDialer(urlStr string, opts DialOpts) func(<-chan struct{}) (io.
 	}
 }
 
+// rpcRequestHeader returns the header for requests to dial the RPC API.
+// It asks the server to use the MessagePack codec and to compress
+// messages; servers that do not support either ignore the request and
+// use uncompressed JSON.
+func rpcRequestHeader() http.Header {
+	return http.Header{
+		"Sec-Websocket-Protocol": {msgpackcodec.WebsocketSubprotocol},
+		compression.Header:       {compression.Deflate},
+	}
+}
+
+// compressionAccepted reports whether the server's response to the
+// websocket handshake accepts compression of messages.
+func compressionAccepted(resp *http.Response) bool {
+	return resp != nil && compression.Offered(resp.Header)
+}
+
+// newRPCCodec returns an RPC codec for the given websocket connection,
+// using the codec negotiated as its subprotocol, and compressing
+// messages if compress is true. The returned compression.Conn, which is
+// nil if compress is false, must be told when the client has logged in.
+func newRPCCodec(conn *websocket.Conn, compress bool) (rpc.Codec, *compression.Conn) {
+	var wsConn jsoncodec.WebsocketConn = conn
+	var compressed *compression.Conn
+	if compress {
+		compressed = compression.NewClientConn(conn, nil)
+		wsConn = compressed
+	}
+	if conn.Subprotocol() == msgpackcodec.WebsocketSubprotocol {
+		return msgpackcodec.NewWebsocket(wsConn), compressed
+	}
+	return jsoncodec.NewWebsocket(wsConn), compressed
+}
+
 // isX509Error reports whether the given websocket error
 // results from an X509 problem.
 func isX509Error(err error) bool {
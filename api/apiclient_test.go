@@ -27,6 +27,8 @@ import (
 	jjtesting "github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/compression"
+	"github.com/juju/juju/rpc/msgpackcodec"
 	jtesting "github.com/juju/juju/testing"
 	jujuversion "github.com/juju/juju/version"
 )
@@ -172,6 +174,36 @@ func (s *apiclientSuite) TestDialWebsocketStopped(c *gc.C) {
 	c.Assert(result, gc.IsNil)
 }
 
+func (s *apiclientSuite) TestDialRequestsMsgpackCodec(c *gc.C) {
+	info := s.APIInfo(c)
+	var requestHeaders []http.Header
+	fakeDialer := func(urlStr string, tlsConfig *tls.Config, requestHeader http.Header) (*websocket.Conn, *http.Response, error) {
+		requestHeaders = append(requestHeaders, requestHeader)
+		return nil, nil, errors.New("nope")
+	}
+	_, err := api.Open(info, api.DialOpts{
+		DialWebsocket: fakeDialer,
+	})
+	c.Assert(err, gc.ErrorMatches, `unable to connect to API: nope`)
+	c.Assert(requestHeaders, gc.HasLen, 1)
+	c.Assert(requestHeaders[0].Get("Sec-Websocket-Protocol"), gc.Equals, msgpackcodec.WebsocketSubprotocol)
+}
+
+func (s *apiclientSuite) TestDialOffersCompression(c *gc.C) {
+	info := s.APIInfo(c)
+	var requestHeaders []http.Header
+	fakeDialer := func(urlStr string, tlsConfig *tls.Config, requestHeader http.Header) (*websocket.Conn, *http.Response, error) {
+		requestHeaders = append(requestHeaders, requestHeader)
+		return nil, nil, errors.New("nope")
+	}
+	_, err := api.Open(info, api.DialOpts{
+		DialWebsocket: fakeDialer,
+	})
+	c.Assert(err, gc.ErrorMatches, `unable to connect to API: nope`)
+	c.Assert(requestHeaders, gc.HasLen, 1)
+	c.Assert(requestHeaders[0].Get(compression.Header), gc.Equals, compression.Deflate)
+}
+
 type apiDialInfo struct {
 	location   string
 	hasRootCAs bool
@@ -0,0 +1,106 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package auditlog provides a client for the AuditLog facade, used to
+// query the audit entries recorded by a controller.
+package auditlog
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/audit"
+)
+
+// Filter holds the criteria used to select audit entries. Zero
+// valued fields do not restrict the result.
+type Filter struct {
+	// ModelUUID restricts the entries to those recorded against
+	// the given model.
+	ModelUUID string
+
+	// User restricts the entries to those triggered by the
+	// given user.
+	User names.UserTag
+
+	// Operation restricts the entries to those whose operation
+	// contains the given text.
+	Operation string
+
+	// After restricts the entries to those recorded at or after
+	// the given time.
+	After time.Time
+
+	// Before restricts the entries to those recorded before the
+	// given time.
+	Before time.Time
+
+	// Limit is the maximum number of entries to return. When
+	// more entries match, the most recent are returned.
+	Limit int
+}
+
+// Client provides access to the AuditLog facade.
+type Client struct {
+	base.ClientFacade
+	facade base.FacadeCaller
+}
+
+// NewClient returns a new AuditLog client.
+func NewClient(caller base.APICallCloser) *Client {
+	frontend, backend := base.NewClientFacade(caller, "AuditLog")
+	return &Client{ClientFacade: frontend, facade: backend}
+}
+
+// Query returns the audit entries matching the filter, oldest first.
+func (c *Client) Query(filter Filter) ([]audit.AuditEntry, error) {
+	args := params.AuditLogQueryArgs{
+		Operation: filter.Operation,
+		Limit:     filter.Limit,
+	}
+	if filter.ModelUUID != "" {
+		args.ModelTag = names.NewModelTag(filter.ModelUUID).String()
+	}
+	if filter.User.Id() != "" {
+		args.OriginName = filter.User.String()
+	}
+	if !filter.After.IsZero() {
+		after := filter.After.UTC()
+		args.After = &after
+	}
+	if !filter.Before.IsZero() {
+		before := filter.Before.UTC()
+		args.Before = &before
+	}
+	var results params.AuditLogQueryResults
+	if err := c.facade.FacadeCall("Query", args, &results); err != nil {
+		return nil, errors.Trace(err)
+	}
+	entries := make([]audit.AuditEntry, len(results.Entries))
+	for i, result := range results.Entries {
+		serverVersion, err := version.Parse(result.JujuServerVersion)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		modelTag, err := names.ParseModelTag(result.ModelTag)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		entries[i] = audit.AuditEntry{
+			JujuServerVersion: serverVersion,
+			ModelUUID:         modelTag.Id(),
+			Timestamp:         result.Timestamp.UTC(),
+			RemoteAddress:     result.RemoteAddress,
+			OriginType:        result.OriginType,
+			OriginName:        result.OriginName,
+			Operation:         result.Operation,
+			Data:              result.Data,
+		}
+	}
+	return entries, nil
+}
@@ -0,0 +1,87 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/auditlog"
+	apitesting "github.com/juju/juju/api/base/testing"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/audit"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type clientSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&clientSuite{})
+
+func (s *clientSuite) TestQuery(c *gc.C) {
+	t0 := time.Date(2017, time.March, 7, 10, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Hour)
+	var called bool
+	apiCaller := apitesting.APICallerFunc(func(objType string, version int, id, request string, arg, result interface{}) error {
+		called = true
+		c.Check(objType, gc.Equals, "AuditLog")
+		c.Check(request, gc.Equals, "Query")
+		c.Check(arg, jc.DeepEquals, params.AuditLogQueryArgs{
+			ModelTag:   coretesting.ModelTag.String(),
+			OriginName: "user-bob",
+			Operation:  "Destroy",
+			After:      &t0,
+			Before:     &t1,
+			Limit:      20,
+		})
+		*(result.(*params.AuditLogQueryResults)) = params.AuditLogQueryResults{
+			Entries: []params.AuditLogEntry{{
+				JujuServerVersion: "2.2.0",
+				ModelTag:          coretesting.ModelTag.String(),
+				Timestamp:         t0,
+				RemoteAddress:     "10.0.0.1",
+				OriginType:        "API request",
+				OriginName:        "user-bob",
+				Operation:         "Application:v3 - Destroy",
+			}},
+		}
+		return nil
+	})
+	client := auditlog.NewClient(apiCaller)
+	entries, err := client.Query(auditlog.Filter{
+		ModelUUID: coretesting.ModelTag.Id(),
+		User:      names.NewUserTag("bob"),
+		Operation: "Destroy",
+		After:     t0,
+		Before:    t1,
+		Limit:     20,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(called, jc.IsTrue)
+	c.Assert(entries, jc.DeepEquals, []audit.AuditEntry{{
+		JujuServerVersion: version.MustParse("2.2.0"),
+		ModelUUID:         coretesting.ModelTag.Id(),
+		Timestamp:         t0,
+		RemoteAddress:     "10.0.0.1",
+		OriginType:        "API request",
+		OriginName:        "user-bob",
+		Operation:         "Application:v3 - Destroy",
+	}})
+}
+
+func (s *clientSuite) TestQueryError(c *gc.C) {
+	apiCaller := apitesting.APICallerFunc(func(string, int, string, string, interface{}, interface{}) error {
+		return errors.New("boom")
+	})
+	client := auditlog.NewClient(apiCaller)
+	_, err := client.Query(auditlog.Filter{})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
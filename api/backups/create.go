@@ -10,10 +10,19 @@ import (
 )
 
 // Create sends a request to create a backup of juju's state.  It
-// returns the metadata associated with the resulting backup.
-func (c *Client) Create(notes string) (*params.BackupsMetadataResult, error) {
+// returns the metadata associated with the resulting backup. If
+// encryptionKey is not empty, the controller encrypts the backup
+// archive with it; it should be a public key or a secret, never a
+// private key.
+func (c *Client) Create(notes string, encryptionKey []byte) (*params.BackupsMetadataResult, error) {
+	if len(encryptionKey) > 0 && c.facade.BestAPIVersion() < 2 {
+		return nil, errors.NotSupportedf("backup encryption by this controller")
+	}
 	var result params.BackupsMetadataResult
-	args := params.BackupsCreateArgs{Notes: notes}
+	args := params.BackupsCreateArgs{
+		Notes:         notes,
+		EncryptionKey: string(encryptionKey),
+	}
 	if err := c.facade.FacadeCall("Create", args, &result); err != nil {
 		return nil, errors.Trace(err)
 	}
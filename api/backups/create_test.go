@@ -4,6 +4,7 @@
 package backups_test
 
 import (
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
@@ -39,9 +40,37 @@ func (s *createSuite) TestCreate(c *gc.C) {
 	)
 	defer cleanup()
 
-	result, err := s.client.Create("important")
+	result, err := s.client.Create("important", nil)
 	c.Assert(err, jc.ErrorIsNil)
 
 	meta := backupstesting.UpdateNotes(s.Meta, "important")
 	s.checkMetadataResult(c, result, meta)
 }
+
+func (s *createSuite) TestCreateEncrypted(c *gc.C) {
+	cleanup := backups.PatchClientFacadeCallVersion(s.client, 2,
+		func(req string, paramsIn interface{}, resp interface{}) error {
+			p := paramsIn.(params.BackupsCreateArgs)
+			c.Check(p.EncryptionKey, gc.Equals, "correct horse battery staple")
+			*resp.(*params.BackupsMetadataResult) = apiserverbackups.ResultFromMetadata(s.Meta)
+			return nil
+		},
+	)
+	defer cleanup()
+
+	_, err := s.client.Create("", []byte("correct horse battery staple"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *createSuite) TestCreateEncryptedNotSupported(c *gc.C) {
+	cleanup := backups.PatchClientFacadeCallVersion(s.client, 1,
+		func(string, interface{}, interface{}) error {
+			c.Fatalf("unexpected call")
+			return nil
+		},
+	)
+	defer cleanup()
+
+	_, err := s.client.Create("", []byte("correct horse battery staple"))
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
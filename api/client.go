@@ -12,6 +12,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/juju/errors"
@@ -98,6 +99,74 @@ func (c *Client) StatusHistory(kind status.HistoryKind, tag names.Tag, filter st
 	return history, nil
 }
 
+func (c *Client) queryStatusHistory(query status.HistoryQuery, aggregate bool) (params.StatusHistoryQueryResult, error) {
+	var result params.StatusHistoryQueryResult
+	if c.facade.BestAPIVersion() < 2 {
+		return result, errors.NotSupportedf("querying status history across a model")
+	}
+	args := params.StatusHistoryQuery{
+		Application: query.Application,
+		FromDate:    query.FromDate,
+		ToDate:      query.ToDate,
+		Exclude:     query.Exclude.Values(),
+		After:       query.After,
+		Limit:       query.Limit,
+		Aggregate:   aggregate,
+	}
+	if err := c.facade.FacadeCall("QueryStatusHistory", args, &result); err != nil {
+		return result, errors.Trace(err)
+	}
+	return result, nil
+}
+
+// QueryStatusHistory returns a page of the status history entries of
+// the units and machines in the model, or of an application's units,
+// that match the query, oldest first. If there are more entries, it
+// also returns the query's After value for the next page.
+func (c *Client) QueryStatusHistory(query status.HistoryQuery) ([]status.HistoryEntry, string, error) {
+	result, err := c.queryStatusHistory(query, false)
+	if err != nil {
+		return nil, "", errors.Trace(err)
+	}
+	entries := make([]status.HistoryEntry, len(result.Entries))
+	for i, entry := range result.Entries {
+		entries[i] = status.HistoryEntry{
+			Id:      entry.Id,
+			Entity:  entry.Entity,
+			Kind:    status.HistoryKind(entry.Kind),
+			Status:  status.Status(entry.Status),
+			Message: entry.Info,
+			Since:   entry.Since,
+		}
+	}
+	return entries, result.Next, nil
+}
+
+// AggregateStatusHistory returns, for each entity and kind matching
+// the query, the time spent in each status and the number of status
+// transitions.
+func (c *Client) AggregateStatusHistory(query status.HistoryQuery) ([]status.HistoryAggregate, error) {
+	result, err := c.queryStatusHistory(query, true)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	aggregates := make([]status.HistoryAggregate, len(result.Aggregates))
+	for i, aggregate := range result.Aggregates {
+		durations := make(map[status.Status]time.Duration)
+		for s, d := range aggregate.Durations {
+			durations[status.Status(s)] = d
+		}
+		aggregates[i] = status.HistoryAggregate{
+			Entity:           aggregate.Entity,
+			Kind:             status.HistoryKind(aggregate.Kind),
+			Durations:        durations,
+			Transitions:      aggregate.Transitions,
+			ErrorTransitions: aggregate.ErrorTransitions,
+		}
+	}
+	return aggregates, nil
+}
+
 // Resolved clears errors on a unit.
 func (c *Client) Resolved(unit string, retry bool) error {
 	p := params.Resolved{
@@ -11,7 +11,8 @@ package api
 // New facades should start at 1.
 // Facades that existed before versioning start at 0.
 var facadeVersions = map[string]int{
-	"Action":                       2,
+	"Action":                       4,
+	"ActionPruner":                 1,
 	"Agent":                        2,
 	"AgentTools":                   1,
 	"AllModelWatcher":              2,
@@ -20,13 +21,14 @@ var facadeVersions = map[string]int{
 	"Application":                  3,
 	"ApplicationScaler":            1,
 	"ApplicationOffers":            1,
-	"Backups":                      1,
+	"AuditLog":                     1,
+	"Backups":                      2,
 	"Block":                        2,
 	"Bundle":                       1,
 	"CharmRevisionUpdater":         2,
 	"Charms":                       2,
 	"Cleaner":                      2,
-	"Client":                       1,
+	"Client":                       2,
 	"Cloud":                        1,
 	"Controller":                   3,
 	"CrossModelRelations":          1,
@@ -61,7 +63,7 @@ var facadeVersions = map[string]int{
 	"MigrationStatusWatcher":       1,
 	"MigrationTarget":              1,
 	"ModelConfig":                  1,
-	"ModelManager":                 2,
+	"ModelManager":                 3,
 	"NotifyWatcher":                1,
 	"OfferedApplications":          1,
 	"Payloads":                     1,
@@ -87,7 +89,7 @@ var facadeVersions = map[string]int{
 	"Subnets":                      2,
 	"Undertaker":                   1,
 	"UnitAssigner":                 1,
-	"Uniter":                       4,
+	"Uniter":                       6,
 	"Upgrader":                     1,
 	"UserManager":                  1,
 	"VolumeAttachmentsWatcher":     2,
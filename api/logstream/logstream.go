@@ -155,6 +155,16 @@ func recordFromAPI(apiRec params.LogStreamRecord, controllerUUID string) (logfwd
 	}
 	rec.Level = level
 
+	if apiRec.Audit != nil {
+		rec.Audit = &logfwd.Audit{
+			ID:            apiRec.Audit.ID,
+			OriginType:    apiRec.Audit.OriginType,
+			Operation:     apiRec.Audit.Operation,
+			RemoteAddress: apiRec.Audit.RemoteAddress,
+			Data:          apiRec.Audit.Data,
+		}
+	}
+
 	if err := rec.Validate(); err != nil {
 		return rec, errors.Trace(err)
 	}
@@ -143,6 +143,86 @@ func (s *LogReaderSuite) TestNextOneRecord(c *gc.C) {
 	}
 }
 
+func (s *LogReaderSuite) TestNextAuditRecord(c *gc.C) {
+	ts := time.Now()
+	apiRec := params.LogStreamRecord{
+		ID:        ts.UnixNano(),
+		ModelUUID: "deadbeef-2f18-4fd2-967d-db9663db7bea",
+		Entity:    "user-bob",
+		Version:   version.Current.String(),
+		Timestamp: ts,
+		Module:    "juju.audit",
+		Level:     loggo.INFO.String(),
+		Message:   "Application:v3 - Destroy",
+		Audit: &params.LogStreamAuditInfo{
+			ID:            "58be8510a8f5ba0b9e3c5a4e",
+			OriginType:    "API request",
+			Operation:     "Application:v3 - Destroy",
+			RemoteAddress: "10.0.0.1",
+			Data:          map[string]interface{}{"request-body": "mysql"},
+		},
+	}
+	cUUID := "feebdaed-2f18-4fd2-967d-db9663db7bea"
+	stub := &testing.Stub{}
+	conn := &mockConnector{stub: stub}
+	jsonReader := mockStream{stub: stub}
+	logsCh := make(chan params.LogStreamRecords, 1)
+	logsCh <- params.LogStreamRecords{
+		Records: []params.LogStreamRecord{apiRec},
+	}
+	jsonReader.ReturnReadJSON = logsCh
+	conn.ReturnConnectStream = jsonReader
+	cfg := params.LogStreamConfig{AllModels: true, Audit: true}
+	stream, err := logstream.Open(conn, cfg, cUUID)
+	c.Assert(err, gc.IsNil)
+
+	var records []logfwd.Record
+	done := make(chan struct{})
+	go func() {
+		records, err = stream.Next()
+		c.Assert(err, jc.ErrorIsNil)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(coretesting.LongWait):
+		c.Errorf("timed out waiting for record")
+	}
+	c.Assert(records, gc.HasLen, 1)
+	c.Check(records[0], jc.DeepEquals, logfwd.Record{
+		ID: ts.UnixNano(),
+		Origin: logfwd.Origin{
+			ControllerUUID: cUUID,
+			ModelUUID:      "deadbeef-2f18-4fd2-967d-db9663db7bea",
+			Type:           logfwd.OriginTypeUser,
+			Name:           "bob",
+			Software: logfwd.Software{
+				PrivateEnterpriseNumber: 28978,
+				Name:    "juju",
+				Version: version.Current,
+			},
+		},
+		Timestamp: ts,
+		Level:     loggo.INFO,
+		Location: logfwd.SourceLocation{
+			Module: "juju.audit",
+			Line:   -1,
+		},
+		Message: "Application:v3 - Destroy",
+		Audit: &logfwd.Audit{
+			ID:            "58be8510a8f5ba0b9e3c5a4e",
+			OriginType:    "API request",
+			Operation:     "Application:v3 - Destroy",
+			RemoteAddress: "10.0.0.1",
+			Data:          map[string]interface{}{"request-body": "mysql"},
+		},
+	})
+	stub.CheckCall(c, 0, "ConnectStream", `/logstream`, url.Values{
+		"all":   []string{"true"},
+		"audit": []string{"true"},
+	})
+}
+
 func (s *LogReaderSuite) TestNextError(c *gc.C) {
 	cUUID := "feebdaed-2f18-4fd2-967d-db9663db7bea"
 	stub := &testing.Stub{}
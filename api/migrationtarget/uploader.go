@@ -0,0 +1,53 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package migrationtarget
+
+import (
+	"io"
+
+	"github.com/juju/version"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/resource"
+	"github.com/juju/juju/tools"
+)
+
+// Uploader sends the binaries for a single model being imported to
+// the target controller. It implements the uploader interfaces used
+// by migration.UploadBinaries.
+type Uploader struct {
+	client    *Client
+	modelUUID string
+}
+
+// NewUploader returns an Uploader which sends binaries for the
+// identified model using the client.
+func NewUploader(client *Client, modelUUID string) *Uploader {
+	return &Uploader{client, modelUUID}
+}
+
+// UploadTools prepends the model UUID to the args passed to the migration client.
+func (u *Uploader) UploadTools(r io.ReadSeeker, vers version.Binary, additionalSeries ...string) (tools.List, error) {
+	return u.client.UploadTools(u.modelUUID, r, vers, additionalSeries...)
+}
+
+// UploadCharm prepends the model UUID to the args passed to the migration client.
+func (u *Uploader) UploadCharm(curl *charm.URL, content io.ReadSeeker) (*charm.URL, error) {
+	return u.client.UploadCharm(u.modelUUID, curl, content)
+}
+
+// UploadResource prepends the model UUID to the args passed to the migration client.
+func (u *Uploader) UploadResource(res resource.Resource, content io.ReadSeeker) error {
+	return u.client.UploadResource(u.modelUUID, res, content)
+}
+
+// SetPlaceholderResource prepends the model UUID to the args passed to the migration client.
+func (u *Uploader) SetPlaceholderResource(res resource.Resource) error {
+	return u.client.SetPlaceholderResource(u.modelUUID, res)
+}
+
+// SetUnitResource prepends the model UUID to the args passed to the migration client.
+func (u *Uploader) SetUnitResource(unitName string, res resource.Resource) error {
+	return u.client.SetUnitResource(u.modelUUID, unitName, res)
+}
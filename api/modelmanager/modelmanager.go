@@ -216,6 +216,32 @@ func (c *Client) DumpModel(model names.ModelTag) (map[string]interface{}, error)
 	return result.Result, nil
 }
 
+// ExportModel returns the serialized model, as used for migration,
+// along with the charms, tools and resources it uses.
+func (c *Client) ExportModel(model names.ModelTag) (params.SerializedModel, error) {
+	var empty params.SerializedModel
+	if c.facade.BestAPIVersion() < 3 {
+		return empty, errors.NotSupportedf("exporting models on this controller")
+	}
+	var results params.SerializedModelResults
+	entities := params.Entities{
+		Entities: []params.Entity{{Tag: model.String()}},
+	}
+
+	err := c.facade.FacadeCall("ExportModels", entities, &results)
+	if err != nil {
+		return empty, errors.Trace(err)
+	}
+	if count := len(results.Results); count != 1 {
+		return empty, errors.Errorf("unexpected result count: %d", count)
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return empty, result.Error
+	}
+	return result.Result, nil
+}
+
 // DumpModelDB returns all relevant mongo documents for the model.
 func (c *Client) DumpModelDB(model names.ModelTag) (map[string]interface{}, error) {
 	var results params.MapResults
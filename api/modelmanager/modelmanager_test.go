@@ -4,6 +4,7 @@
 package modelmanager_test
 
 import (
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	gc "gopkg.in/check.v1"
@@ -335,3 +336,63 @@ func (s *dumpModelSuite) TestDumpModelDBError(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "fake error")
 	c.Assert(out, gc.IsNil)
 }
+
+// bestVersionCaller reports the given facade version, so that
+// version-dependent client methods can be tested.
+type bestVersionCaller struct {
+	basetesting.APICallerFunc
+	version int
+}
+
+func (c bestVersionCaller) BestFacadeVersion(facade string) int {
+	return c.version
+}
+
+func (s *dumpModelSuite) TestExportModel(c *gc.C) {
+	expected := params.SerializedModel{
+		Bytes:  []byte("model-uuid: some-uuid\n"),
+		Charms: []string{"cs:xenial/mysql-1"},
+	}
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, args, result interface{}) error {
+			c.Check(objType, gc.Equals, "ModelManager")
+			c.Check(request, gc.Equals, "ExportModels")
+			c.Check(args, jc.DeepEquals, params.Entities{[]params.Entity{{testing.ModelTag.String()}}})
+			res, ok := result.(*params.SerializedModelResults)
+			c.Assert(ok, jc.IsTrue)
+			*res = params.SerializedModelResults{[]params.SerializedModelResult{{
+				Result: expected,
+			}}}
+			return nil
+		})
+	client := modelmanager.NewClient(bestVersionCaller{apiCaller, 3})
+	out, err := client.ExportModel(testing.ModelTag)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, jc.DeepEquals, expected)
+}
+
+func (s *dumpModelSuite) TestExportModelError(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, args, result interface{}) error {
+			res, ok := result.(*params.SerializedModelResults)
+			c.Assert(ok, jc.IsTrue)
+			*res = params.SerializedModelResults{[]params.SerializedModelResult{{
+				Error: &params.Error{Message: "fake error"},
+			}}}
+			return nil
+		})
+	client := modelmanager.NewClient(bestVersionCaller{apiCaller, 3})
+	_, err := client.ExportModel(testing.ModelTag)
+	c.Assert(err, gc.ErrorMatches, "fake error")
+}
+
+func (s *dumpModelSuite) TestExportModelNotSupported(c *gc.C) {
+	apiCaller := basetesting.APICallerFunc(
+		func(objType string, version int, id, request string, args, result interface{}) error {
+			c.Fatalf("unexpected call to %s", request)
+			return nil
+		})
+	client := modelmanager.NewClient(bestVersionCaller{apiCaller, 2})
+	_, err := client.ExportModel(testing.ModelTag)
+	c.Assert(err, jc.Satisfies, errors.IsNotSupported)
+}
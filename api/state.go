@@ -187,6 +187,11 @@ func (st *state) setLoginResult(p loginResultParams) error {
 		st.facadeVersions[facade.Name] = facade.Versions
 	}
 
+	if st.compression != nil {
+		// The server accepts compressed messages now that it has
+		// logged us in.
+		st.compression.LoggedIn()
+	}
 	st.setLoggedIn()
 	return nil
 }
@@ -3,10 +3,15 @@
 
 package uniter
 
+import "time"
+
 // Action represents a single instance of an Action call, by name and params.
 type Action struct {
-	name   string
-	params map[string]interface{}
+	name    string
+	params  map[string]interface{}
+	timeout time.Duration
+
+	streamOutput bool
 }
 
 // NewAction makes a new Action with specified name and params map.
@@ -23,3 +28,16 @@ func (a *Action) Name() string {
 func (a *Action) Params() map[string]interface{} {
 	return a.params
 }
+
+// Timeout retrieves the time the Action may run for, with the
+// charm's default applied if none was given when it was enqueued. Zero
+// means the Action may run indefinitely.
+func (a *Action) Timeout() time.Duration {
+	return a.timeout
+}
+
+// StreamOutput reports whether the output of the Action should be
+// logged as messages while it runs.
+func (a *Action) StreamOutput() bool {
+	return a.streamOutput
+}
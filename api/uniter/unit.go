@@ -595,6 +595,33 @@ func (u *Unit) WatchActionNotifications() (watcher.StringsWatcher, error) {
 	return w, nil
 }
 
+// WatchActionCancellations returns a StringsWatcher for observing the
+// ids of the Unit's running Actions whose cancellation has been
+// requested. The initial event contains the ids of any Actions being
+// cancelled at the time the Watcher is made.
+func (u *Unit) WatchActionCancellations() (watcher.StringsWatcher, error) {
+	if u.st.facade.BestAPIVersion() < 5 {
+		return nil, errors.NotImplementedf("WatchActionCancellations() (need V5+)")
+	}
+	var results params.StringsWatchResults
+	args := params.Entities{
+		Entities: []params.Entity{{Tag: u.tag.String()}},
+	}
+	err := u.st.facade.FacadeCall("WatchActionCancellations", args, &results)
+	if err != nil {
+		return nil, err
+	}
+	if len(results.Results) != 1 {
+		return nil, fmt.Errorf("expected 1 result, got %d", len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return nil, result.Error
+	}
+	w := apiwatcher.NewStringsWatcher(u.st.facade.RawAPICaller(), result)
+	return w, nil
+}
+
 // RequestReboot sets the reboot flag for its machine agent
 func (u *Unit) RequestReboot() error {
 	machineId, err := u.AssignedMachine()
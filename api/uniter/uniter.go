@@ -217,8 +217,11 @@ func (st *State) Action(tag names.ActionTag) (*Action, error) {
 		return nil, err
 	}
 	return &Action{
-		name:   result.Action.Name,
-		params: result.Action.Parameters,
+		name:    result.Action.Name,
+		params:  result.Action.Parameters,
+		timeout: result.Action.Timeout,
+
+		streamOutput: result.Action.StreamOutput,
 	}, nil
 }
 
@@ -275,6 +278,44 @@ func (st *State) ActionFinish(tag names.ActionTag, status string, results map[st
 	return nil
 }
 
+// LogActionMessage records a progress message against a running action.
+func (st *State) LogActionMessage(tag names.ActionTag, message string) error {
+	return st.LogActionMessages(tag, []string{message})
+}
+
+// LogActionMessages records progress messages against a running action,
+// in a single call.
+func (st *State) LogActionMessages(tag names.ActionTag, messages []string) error {
+	if st.BestAPIVersion() < 6 {
+		// LogActionsMessages() was introduced in UniterAPIV6.
+		return errors.NotImplementedf("LogActionMessages() (need V6+)")
+	}
+	var outcome params.ErrorResults
+	args := params.ActionMessageArgs{
+		Messages: make([]params.ActionMessageArg, len(messages)),
+	}
+	for i, message := range messages {
+		args.Messages[i] = params.ActionMessageArg{
+			ActionTag: tag.String(),
+			Message:   message,
+		}
+	}
+
+	err := st.facade.FacadeCall("LogActionsMessages", args, &outcome)
+	if err != nil {
+		return err
+	}
+	if len(outcome.Results) != len(messages) {
+		return fmt.Errorf("expected %d results, got %d", len(messages), len(outcome.Results))
+	}
+	for _, result := range outcome.Results {
+		if result.Error != nil {
+			return result.Error
+		}
+	}
+	return nil
+}
+
 // RelationById returns the existing relation with the given id.
 func (st *State) RelationById(id int) (*Relation, error) {
 	var results params.RelationResults
@@ -16,6 +16,8 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Action", 2, NewActionAPI)
+	common.RegisterStandardFacade("Action", 3, NewActionAPI) // Adds application receivers to Enqueue, and Operations.
+	common.RegisterStandardFacade("Action", 4, NewActionAPI) // Adds ListActions.
 }
 
 // ActionAPI implements the client API for interacting with Actions
@@ -158,7 +160,9 @@ func (a *ActionAPI) FindActionsByNames(arg params.FindActionsByNames) (params.Ac
 // Enqueue takes a list of Actions and queues them up to be executed by
 // the designated ActionReceiver, returning the params.Action for each
 // enqueued Action, or an error if there was a problem enqueueing the
-// Action.
+// Action. An Action whose receiver is an application is enqueued on the
+// application's units as an operation, and its result holds the
+// operation's id.
 func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	if err := a.checkCanWrite(); err != nil {
 		return params.ActionResults{}, errors.Trace(err)
@@ -172,12 +176,28 @@ func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	response := params.ActionResults{Results: make([]params.ActionResult, len(arg.Actions))}
 	for i, action := range arg.Actions {
 		currentResult := &response.Results[i]
+		if tag, err := names.ParseApplicationTag(action.Receiver); err == nil {
+			result, err := a.enqueueOperation(tag, action)
+			if err != nil {
+				currentResult.Error = common.ServerError(err)
+				continue
+			}
+			response.Results[i] = result
+			continue
+		}
+		if action.Leader || action.BatchSize != 0 || action.BatchPercentage != 0 {
+			currentResult.Error = common.ServerError(errors.NotValidf("leader or batch size for receiver %q", action.Receiver))
+			continue
+		}
 		receiver, err := tagToActionReceiver(action.Receiver)
 		if err != nil {
 			currentResult.Error = common.ServerError(err)
 			continue
 		}
-		enqueued, err := receiver.AddAction(action.Name, action.Parameters)
+		enqueued, err := receiver.AddActionWithOptions(action.Name, action.Parameters, state.ActionOptions{
+			Timeout:      action.Timeout,
+			StreamOutput: action.StreamOutput,
+		})
 		if err != nil {
 			currentResult.Error = common.ServerError(err)
 			continue
@@ -188,6 +208,190 @@ func (a *ActionAPI) Enqueue(arg params.Actions) (params.ActionResults, error) {
 	return response, nil
 }
 
+// enqueueOperation enqueues the action on the units of the given
+// application, or on its leader only, as an operation.
+func (a *ActionAPI) enqueueOperation(tag names.ApplicationTag, action params.Action) (params.ActionResult, error) {
+	if action.BatchSize != 0 && action.BatchPercentage != 0 {
+		return params.ActionResult{}, errors.NotValidf("both batch size and batch percentage")
+	}
+	if action.BatchPercentage < 0 || action.BatchPercentage > 100 {
+		return params.ActionResult{}, errors.NotValidf("batch percentage %d", action.BatchPercentage)
+	}
+	application, err := a.state.Application(tag.Id())
+	if err != nil {
+		return params.ActionResult{}, errors.Trace(err)
+	}
+	var units []*state.Unit
+	if action.Leader {
+		leaders, err := a.state.ApplicationLeaders()
+		if err != nil {
+			return params.ActionResult{}, errors.Trace(err)
+		}
+		leader, ok := leaders[application.Name()]
+		if !ok {
+			return params.ActionResult{}, errors.NotFoundf("leader of application %q", application.Name())
+		}
+		unit, err := a.state.Unit(leader)
+		if err != nil {
+			return params.ActionResult{}, errors.Trace(err)
+		}
+		units = []*state.Unit{unit}
+	} else {
+		units, err = application.AllUnits()
+		if err != nil {
+			return params.ActionResult{}, errors.Trace(err)
+		}
+	}
+	if len(units) == 0 {
+		return params.ActionResult{}, errors.Errorf("application %q has no units", application.Name())
+	}
+	batchSize := action.BatchSize
+	if action.BatchPercentage > 0 {
+		// Round up, so that there is always at least one unit in a
+		// batch.
+		batchSize = (len(units)*action.BatchPercentage + 99) / 100
+	}
+	op, err := a.state.EnqueueOperation(state.OperationArgs{
+		Application: application.Name(),
+		Units:       units,
+		ActionName:  action.Name,
+		Parameters:  action.Parameters,
+		Options: state.ActionOptions{
+			Timeout:      action.Timeout,
+			StreamOutput: action.StreamOutput,
+		},
+		BatchSize: batchSize,
+	})
+	if err != nil {
+		return params.ActionResult{}, errors.Trace(err)
+	}
+	return params.ActionResult{
+		Action: &params.Action{
+			Receiver:     tag.String(),
+			Name:         action.Name,
+			Parameters:   action.Parameters,
+			Timeout:      action.Timeout,
+			StreamOutput: action.StreamOutput,
+			Leader:       action.Leader,
+			BatchSize:    op.BatchSize(),
+		},
+		Operation: op.Id(),
+		Status:    params.ActionPending,
+		Enqueued:  op.Enqueued(),
+	}, nil
+}
+
+// Operations takes a list of operation ids, and returns each operation
+// with its actions and a summary of their status.
+func (a *ActionAPI) Operations(arg params.OperationIds) (params.OperationResults, error) {
+	if err := a.checkCanRead(); err != nil {
+		return params.OperationResults{}, errors.Trace(err)
+	}
+
+	response := params.OperationResults{Results: make([]params.OperationResult, len(arg.Ids))}
+	for i, id := range arg.Ids {
+		currentResult := &response.Results[i]
+		currentResult.Id = id
+		op, err := a.state.Operation(id)
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		actions, err := op.Actions()
+		if err != nil {
+			currentResult.Error = common.ServerError(err)
+			continue
+		}
+		currentResult.Application = op.Application()
+		currentResult.ActionName = op.ActionName()
+		currentResult.Enqueued = op.Enqueued()
+		currentResult.BatchSize = op.BatchSize()
+		currentResult.Actions = make([]params.ActionResult, len(actions))
+		for j, action := range actions {
+			receiverTag, err := names.ActionReceiverTag(action.Receiver())
+			if err != nil {
+				currentResult.Actions[j] = params.ActionResult{Error: common.ServerError(err)}
+				continue
+			}
+			currentResult.Actions[j] = common.MakeActionResult(receiverTag, action)
+		}
+		currentResult.Status = operationStatus(currentResult.Actions)
+	}
+	return response, nil
+}
+
+// operationStatus summarises the status of an operation's actions. An
+// operation is pending until any of its actions start, and running
+// until they have all finished; it is then completed if they all
+// completed, and failed otherwise.
+func operationStatus(actions []params.ActionResult) string {
+	var pending, unfinished, unsuccessful int
+	for _, action := range actions {
+		switch action.Status {
+		case params.ActionPending:
+			pending++
+			unfinished++
+		case params.ActionRunning, params.ActionAborting:
+			unfinished++
+		case params.ActionCompleted:
+		default:
+			unsuccessful++
+		}
+	}
+	switch {
+	case pending == len(actions):
+		return params.ActionPending
+	case unfinished > 0:
+		return params.ActionRunning
+	case unsuccessful > 0:
+		return params.ActionFailed
+	}
+	return params.ActionCompleted
+}
+
+// ListActions returns a page of the model's actions that match the
+// given filters, most recently enqueued first. At most 100 actions are
+// returned unless the query asks for more, up to 1000; the next page
+// follows the action whose tag is given as After.
+func (a *ActionAPI) ListActions(arg params.ActionQueryArgs) (params.ActionResults, error) {
+	if err := a.checkCanRead(); err != nil {
+		return params.ActionResults{}, errors.Trace(err)
+	}
+
+	query := state.ActionQuery{
+		Applications:   arg.Applications,
+		ActionNames:    arg.ActionNames,
+		EnqueuedAfter:  arg.EnqueuedAfter,
+		EnqueuedBefore: arg.EnqueuedBefore,
+		Limit:          arg.Limit,
+	}
+	if arg.After != "" {
+		tag, err := names.ParseActionTag(arg.After)
+		if err != nil {
+			return params.ActionResults{}, errors.Trace(err)
+		}
+		query.After = tag.Id()
+	}
+	for _, status := range arg.Statuses {
+		query.Statuses = append(query.Statuses, state.ActionStatus(status))
+	}
+	actions, err := a.state.QueryActions(query)
+	if err != nil {
+		return params.ActionResults{}, errors.Trace(err)
+	}
+
+	response := params.ActionResults{Results: make([]params.ActionResult, len(actions))}
+	for i, action := range actions {
+		receiverTag, err := names.ActionReceiverTag(action.Receiver())
+		if err != nil {
+			response.Results[i] = params.ActionResult{Error: common.ServerError(err)}
+			continue
+		}
+		response.Results[i] = common.MakeActionResult(receiverTag, action)
+	}
+	return response, nil
+}
+
 // ListAll takes a list of Entities representing ActionReceivers and
 // returns all of the Actions that have been enqueued or run by each of
 // those Entities.
@@ -232,7 +436,9 @@ func (a *ActionAPI) ListCompleted(arg params.Entities) (params.ActionsByReceiver
 	return a.internalList(arg, completedActions)
 }
 
-// Cancel attempts to cancel enqueued Actions from running.
+// Cancel attempts to cancel enqueued Actions from running. Actions
+// already running on units are marked as aborting, and are stopped and
+// cancelled by the unit agent.
 func (a *ActionAPI) Cancel(arg params.Entities) (params.ActionResults, error) {
 	if err := a.checkCanWrite(); err != nil {
 		return params.ActionResults{}, errors.Trace(err)
@@ -260,12 +466,18 @@ func (a *ActionAPI) Cancel(arg params.Entities) (params.ActionResults, error) {
 			currentResult.Error = common.ServerError(err)
 			continue
 		}
-		result, err := action.Finish(state.ActionResults{Status: state.ActionCancelled, Message: "action cancelled via the API"})
+		receiverTag, err := names.ActionReceiverTag(action.Receiver())
 		if err != nil {
 			currentResult.Error = common.ServerError(err)
 			continue
 		}
-		receiverTag, err := names.ActionReceiverTag(result.Receiver())
+		var result state.Action
+		if receiverTag.Kind() == names.UnitTagKind {
+			// Running actions on units are stopped by the unit agent.
+			result, err = action.Cancel("action cancelled via the API")
+		} else {
+			result, err = action.Finish(state.ActionResults{Status: state.ActionCancelled, Message: "action cancelled via the API"})
+		}
 		if err != nil {
 			currentResult.Error = common.ServerError(err)
 			continue
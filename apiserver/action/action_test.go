@@ -6,6 +6,7 @@ package action_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -233,8 +234,8 @@ func (s *actionSuite) TestEnqueue(c *gc.C) {
 			{Name: "fakeaction"},
 			// Good.
 			{Receiver: s.wordpressUnit.Tag().String(), Name: expectedName, Parameters: expectedParameters},
-			// Service tag instead of Unit tag.
-			{Receiver: s.wordpress.Tag().String(), Name: "fakeaction"},
+			// Unknown application.
+			{Receiver: names.NewApplicationTag("missing").String(), Name: "fakeaction"},
 			// Missing name.
 			{Receiver: s.mysqlUnit.Tag().String(), Parameters: expectedParameters},
 		},
@@ -253,7 +254,10 @@ func (s *actionSuite) TestEnqueue(c *gc.C) {
 	c.Assert(res.Results[1].Action.Receiver, gc.Equals, s.wordpressUnit.Tag().String())
 	c.Assert(res.Results[1].Action.Tag, gc.Not(gc.Equals), emptyActionTag)
 
-	c.Assert(res.Results[2].Error, gc.DeepEquals, expectedError)
+	c.Assert(res.Results[2].Error, gc.DeepEquals, &params.Error{
+		Message: `application "missing" not found`,
+		Code:    "not found",
+	})
 	c.Assert(res.Results[2].Action, gc.IsNil)
 
 	c.Assert(res.Results[3].Error, gc.ErrorMatches, "no action name given")
@@ -273,6 +277,160 @@ func (s *actionSuite) TestEnqueue(c *gc.C) {
 	c.Assert(actions, gc.HasLen, 0)
 }
 
+func (s *actionSuite) TestEnqueueWithTimeout(c *gc.C) {
+	arg := params.Actions{
+		Actions: []params.Action{{
+			Receiver: s.wordpressUnit.Tag().String(),
+			Name:     "fakeaction",
+			Timeout:  5 * time.Minute,
+		}, {
+			Receiver: s.wordpressUnit.Tag().String(),
+			Name:     "fakeaction",
+			Timeout:  -time.Minute,
+		}},
+	}
+	res, err := s.action.Enqueue(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 2)
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	c.Assert(res.Results[0].Action.Timeout, gc.Equals, 5*time.Minute)
+	c.Assert(res.Results[1].Error, gc.ErrorMatches, "negative timeout -1m0s not valid")
+
+	actions, err := s.wordpressUnit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].Timeout(), gc.Equals, 5*time.Minute)
+}
+
+func (s *actionSuite) TestEnqueueWithStreamOutput(c *gc.C) {
+	arg := params.Actions{
+		Actions: []params.Action{{
+			Receiver:     s.wordpressUnit.Tag().String(),
+			Name:         "fakeaction",
+			StreamOutput: true,
+		}},
+	}
+	res, err := s.action.Enqueue(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 1)
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	c.Assert(res.Results[0].Action.StreamOutput, gc.Equals, true)
+
+	actions, err := s.wordpressUnit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].StreamOutput(), gc.Equals, true)
+}
+
+func (s *actionSuite) TestEnqueueApplication(c *gc.C) {
+	factory := jujuFactory.NewFactory(s.State)
+	wordpressUnit2 := factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Application: s.wordpress,
+		Machine:     s.machine1,
+	})
+	arg := params.Actions{
+		Actions: []params.Action{{
+			Receiver:        s.wordpress.Tag().String(),
+			Name:            "fakeaction",
+			BatchPercentage: 50,
+		}, {
+			Receiver:  s.wordpressUnit.Tag().String(),
+			Name:      "fakeaction",
+			BatchSize: 1,
+		}, {
+			Receiver:        s.wordpress.Tag().String(),
+			Name:            "fakeaction",
+			BatchSize:       1,
+			BatchPercentage: 50,
+		}},
+	}
+	res, err := s.action.Enqueue(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results, gc.HasLen, 3)
+	c.Assert(res.Results[0].Error, gc.IsNil)
+	c.Assert(res.Results[0].Operation, gc.Not(gc.Equals), "")
+	c.Assert(res.Results[0].Status, gc.Equals, params.ActionPending)
+	c.Assert(res.Results[0].Action.Receiver, gc.Equals, s.wordpress.Tag().String())
+	c.Assert(res.Results[0].Action.BatchSize, gc.Equals, 1)
+	c.Assert(res.Results[1].Error, gc.ErrorMatches, `leader or batch size for receiver "unit-wordpress-0" not valid`)
+	c.Assert(res.Results[2].Error, gc.ErrorMatches, "both batch size and batch percentage not valid")
+
+	for _, unit := range []*state.Unit{s.wordpressUnit, wordpressUnit2} {
+		actions, err := unit.Actions()
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(actions, gc.HasLen, 1)
+		c.Assert(actions[0].Operation(), gc.Equals, res.Results[0].Operation)
+	}
+
+	ops, err := s.action.Operations(params.OperationIds{Ids: []string{res.Results[0].Operation, "42"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ops.Results, gc.HasLen, 2)
+	op := ops.Results[0]
+	c.Assert(op.Error, gc.IsNil)
+	c.Assert(op.Application, gc.Equals, "wordpress")
+	c.Assert(op.ActionName, gc.Equals, "fakeaction")
+	c.Assert(op.BatchSize, gc.Equals, 1)
+	c.Assert(op.Status, gc.Equals, params.ActionPending)
+	c.Assert(op.Actions, gc.HasLen, 2)
+	c.Assert(op.Actions[0].Action.Receiver, gc.Equals, s.wordpressUnit.Tag().String())
+	c.Assert(op.Actions[1].Action.Receiver, gc.Equals, wordpressUnit2.Tag().String())
+	c.Assert(ops.Results[1].Error, gc.ErrorMatches, `operation "42" not found`)
+
+	// Once an action finishes, the operation is running until they
+	// all have.
+	tag, err := names.ParseActionTag(op.Actions[0].Action.Tag)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err := s.State.ActionByTag(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = action.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	ops, err = s.action.Operations(params.OperationIds{Ids: []string{op.Id}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ops.Results[0].Status, gc.Equals, params.ActionRunning)
+
+	tag, err = names.ParseActionTag(op.Actions[1].Action.Tag)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err = s.State.ActionByTag(tag)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = action.Finish(state.ActionResults{Status: state.ActionFailed})
+	c.Assert(err, jc.ErrorIsNil)
+	ops, err = s.action.Operations(params.OperationIds{Ids: []string{op.Id}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ops.Results[0].Status, gc.Equals, params.ActionFailed)
+}
+
+func (s *actionSuite) TestEnqueueApplicationLeader(c *gc.C) {
+	factory := jujuFactory.NewFactory(s.State)
+	wordpressUnit2 := factory.MakeUnit(c, &jujuFactory.UnitParams{
+		Application: s.wordpress,
+		Machine:     s.machine1,
+	})
+	arg := params.Actions{
+		Actions: []params.Action{{
+			Receiver: s.wordpress.Tag().String(),
+			Name:     "fakeaction",
+			Leader:   true,
+		}},
+	}
+	res, err := s.action.Enqueue(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results[0].Error, gc.ErrorMatches, `leader of application "wordpress" not found`)
+
+	err = s.State.LeadershipClaimer().ClaimLeadership("wordpress", wordpressUnit2.Name(), time.Minute)
+	c.Assert(err, jc.ErrorIsNil)
+	res, err = s.action.Enqueue(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res.Results[0].Error, gc.IsNil)
+
+	actions, err := s.wordpressUnit.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 0)
+	actions, err = wordpressUnit2.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 1)
+	c.Assert(actions[0].Operation(), gc.Equals, res.Results[0].Operation)
+}
+
 type testCaseAction struct {
 	Name       string
 	Parameters map[string]interface{}
@@ -562,6 +720,48 @@ func (s *actionSuite) TestListCompleted(c *gc.C) {
 	}
 }
 
+func (s *actionSuite) TestListActions(c *gc.C) {
+	arg := params.Actions{Actions: []params.Action{
+		{Receiver: s.wordpressUnit.Tag().String(), Name: "juju-run", Parameters: map[string]interface{}{"command": "boo", "timeout": 5}},
+		{Receiver: s.mysqlUnit.Tag().String(), Name: "juju-run", Parameters: map[string]interface{}{"command": "boo", "timeout": 5}},
+	}}
+	r, err := s.action.Enqueue(arg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(r.Results, gc.HasLen, 2)
+
+	results, err := s.action.ListActions(params.ActionQueryArgs{Applications: []string{"mysql"}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Action.Receiver, gc.Equals, s.mysqlUnit.Tag().String())
+	c.Assert(results.Results[0].Action.Tag, gc.Equals, r.Results[1].Action.Tag)
+
+	results, err = s.action.ListActions(params.ActionQueryArgs{
+		ActionNames: []string{"juju-run"},
+		Statuses:    []string{params.ActionPending},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 2)
+
+	results, err = s.action.ListActions(params.ActionQueryArgs{Statuses: []string{params.ActionCompleted}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 0)
+
+	results, err = s.action.ListActions(params.ActionQueryArgs{Limit: 1})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	results, err = s.action.ListActions(params.ActionQueryArgs{After: results.Results[0].Action.Tag})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+
+	_, err = s.action.ListActions(params.ActionQueryArgs{Limit: -1})
+	c.Assert(err, gc.ErrorMatches, "negative limit -1 not valid")
+	_, err = s.action.ListActions(params.ActionQueryArgs{Limit: 1001})
+	c.Assert(err, gc.ErrorMatches, "limit 1001 greater than 1000 not valid")
+	_, err = s.action.ListActions(params.ActionQueryArgs{After: "unit-mysql-0"})
+	c.Assert(err, gc.ErrorMatches, `"unit-mysql-0" is not a valid action tag`)
+}
+
 func (s *actionSuite) TestCancel(c *gc.C) {
 	// Make sure no Actions already exist on wordpress Unit.
 	actions, err := s.wordpressUnit.Actions()
@@ -630,6 +830,22 @@ func (s *actionSuite) TestCancel(c *gc.C) {
 	c.Assert(myActions[1].Status, gc.Equals, params.ActionCancelled)
 }
 
+func (s *actionSuite) TestCancelRunning(c *gc.C) {
+	action, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	results, err := s.action.Cancel(params.Entities{
+		Entities: []params.Entity{{Tag: action.Tag().String()}},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results.Results, gc.HasLen, 1)
+	c.Assert(results.Results[0].Error, gc.IsNil)
+	c.Assert(results.Results[0].Status, gc.Equals, params.ActionAborting)
+	c.Assert(results.Results[0].Message, gc.Equals, "action cancelled via the API")
+}
+
 func (s *actionSuite) TestApplicationsCharmsActions(c *gc.C) {
 	actionSchemas := map[string]map[string]interface{}{
 		"snapshot": {
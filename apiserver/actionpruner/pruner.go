@@ -0,0 +1,41 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionpruner
+
+import (
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("ActionPruner", 1, NewAPI)
+}
+
+// API is the concrete implementation of the ActionPruner endpoint.
+type API struct {
+	*common.ModelWatcher
+	st         *state.State
+	authorizer facade.Authorizer
+}
+
+// NewAPI returns an API Instance.
+func NewAPI(st *state.State, resources facade.Resources, auth facade.Authorizer) (*API, error) {
+	if !auth.AuthController() {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		ModelWatcher: common.NewModelWatcher(st, resources, auth),
+		st:           st,
+		authorizer:   auth,
+	}, nil
+}
+
+// Prune removes the model's finished actions until only the ones that
+// finished after now - p.MaxHistoryTime remain and they use no more
+// than p.MaxHistoryMB.
+func (api *API) Prune(p params.ActionPruneArgs) error {
+	return state.PruneActions(api.st, p.MaxHistoryTime, p.MaxHistoryMB)
+}
@@ -205,7 +205,14 @@ func (a *admin) login(req params.LoginRequest, loginVersion int) (params.LoginRe
 		apiRoot = restrictRoot(apiRoot, modelFacadesOnly)
 	}
 
+	if a.srv.requestLimiter != nil && !controllerMachineLogin {
+		a.root.rpcConn.SetRequestLimiter(a.srv.requestLimiter.ForEntity(entity.Tag()))
+	}
 	a.root.rpcConn.ServeRoot(apiRoot, serverError)
+	if a.root.compression != nil {
+		// The client compresses its messages once it has our reply.
+		a.root.compression.LoggedIn()
+	}
 
 	return loginResult, nil
 }
@@ -35,8 +35,9 @@ import (
 	"github.com/juju/juju/apiserver/common/apihttp"
 	"github.com/juju/juju/apiserver/observer"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/ratelimit"
 	"github.com/juju/juju/rpc"
-	"github.com/juju/juju/rpc/jsoncodec"
+	"github.com/juju/juju/rpc/compression"
 	"github.com/juju/juju/state"
 )
 
@@ -71,6 +72,10 @@ type Server struct {
 	tlsConfig         *tls.Config
 	allowModelAccess  bool
 	logSinkWriter     io.WriteCloser
+	requestLimiter    *ratelimit.Limiter
+
+	websocketCompression bool
+	compressionCounter   compression.Counter
 
 	// mu guards the fields below it.
 	mu sync.Mutex
@@ -126,13 +131,28 @@ type ServerConfig struct {
 	// notified of key events during API requests.
 	NewObserver observer.ObserverFactory
 
+	// RequestLimiter, if non-nil, limits the rate at which each
+	// authenticated entity may make API requests. Controller machines
+	// logging in to other models are not limited.
+	RequestLimiter *ratelimit.Limiter
+
+	// WebsocketCompression holds whether API connections from clients
+	// that offer it will have their messages compressed.
+	WebsocketCompression bool
+
+	// CompressionCounter, if non-nil, is told the sizes of the
+	// messages on compressed API connections.
+	CompressionCounter compression.Counter
+
 	// StatePool is created by the machine agent and passed in.
 	StatePool *state.StatePool
 
 	// RegisterIntrospectionHandlers is a function that will
 	// call a function with (path, http.Handler) tuples. This
 	// is to support registering the handlers underneath the
-	// "/introspection" prefix.
+	// "/introspection" prefix. The "metrics" handler may be
+	// accessed by users with read access on the controller
+	// model; the others require controller superuser access.
 	RegisterIntrospectionHandlers func(func(string, http.Handler))
 }
 
@@ -208,6 +228,9 @@ func newServer(s *state.State, lis net.Listener, cfg ServerConfig) (_ *Server, e
 		centralHub:                    cfg.Hub,
 		certChanged:                   cfg.CertChanged,
 		allowModelAccess:              cfg.AllowModelAccess,
+		requestLimiter:                cfg.RequestLimiter,
+		websocketCompression:          cfg.WebsocketCompression,
+		compressionCounter:            cfg.CompressionCounter,
 		registerIntrospectionHandlers: cfg.RegisterIntrospectionHandlers,
 	}
 
@@ -533,8 +556,11 @@ func (srv *Server) endpoints() []apihttp.Endpoint {
 		handle := func(subpath string, handler http.Handler) {
 			add(path.Join("/introspection/", subpath),
 				introspectionHandler{
-					httpCtxt,
-					handler,
+					ctx:     httpCtxt,
+					handler: handler,
+					allowReadAccess: introspectionReadAccessPaths.Contains(
+						strings.Trim(subpath, "/"),
+					),
 				},
 			)
 		}
@@ -647,18 +673,25 @@ func (srv *Server) apiHandler(w http.ResponseWriter, req *http.Request) {
 	apiObserver.Join(req, connectionID)
 	defer apiObserver.Leave()
 
+	var responseHeader http.Header
+	compress := srv.websocketCompression && compression.Offered(req.Header)
+	if compress {
+		responseHeader = http.Header{
+			compression.Header: {compression.Deflate},
+		}
+	}
 	handler := func(conn *websocket.Conn) {
 		modelUUID := req.URL.Query().Get(":modeluuid")
 		logger.Tracef("got a request for model %q", modelUUID)
-		if err := srv.serveConn(conn, modelUUID, apiObserver, req.Host); err != nil {
+		codec, compressed := newRPCCodec(conn, compress, srv.compressionCounter)
+		if err := srv.serveConn(codec, compressed, modelUUID, apiObserver, req.Host); err != nil {
 			logger.Errorf("error serving RPCs: %v", err)
 		}
 	}
-	websocketServer(w, req, handler)
+	upgradeWebsocket(&apiWebsocketUpgrader, w, req, responseHeader, handler)
 }
 
-func (srv *Server) serveConn(wsConn *websocket.Conn, modelUUID string, apiObserver observer.Observer, host string) error {
-	codec := jsoncodec.NewWebsocket(wsConn)
+func (srv *Server) serveConn(codec rpc.Codec, compressed *compression.Conn, modelUUID string, apiObserver observer.Observer, host string) error {
 	conn := rpc.NewConn(codec, apiObserver)
 
 	// Note that we don't overwrite modelUUID here because
@@ -681,6 +714,9 @@ func (srv *Server) serveConn(wsConn *websocket.Conn, modelUUID string, apiObserv
 		defer releaser()
 		h, err = newAPIHandler(srv, st, conn, modelUUID, host)
 	}
+	if err == nil {
+		h.compression = compressed
+	}
 
 	if err != nil {
 		conn.ServeRoot(&errRoot{errors.Trace(err)}, serverError)
@@ -0,0 +1,105 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package auditlog defines an API end point for querying the audit
+// entries recorded by the controller.
+package auditlog
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/facade"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/permission"
+	"github.com/juju/juju/state"
+)
+
+func init() {
+	common.RegisterStandardFacade("AuditLog", 1, func(st *state.State, _ facade.Resources, auth facade.Authorizer) (*API, error) {
+		return NewAPI(st, auth)
+	})
+}
+
+// maxQueryLimit is the maximum number of entries returned by a
+// single query, regardless of the limit requested.
+const maxQueryLimit = 10000
+
+// Backend exposes the state functionality needed by the AuditLog
+// facade.
+type Backend interface {
+	ControllerTag() names.ControllerTag
+	AuditEntries(state.AuditEntryFilter) ([]audit.AuditEntry, error)
+}
+
+// API is the concrete implementation of the AuditLog facade.
+type API struct {
+	backend    Backend
+	authorizer facade.Authorizer
+}
+
+// NewAPI returns a new AuditLog facade. Only controller
+// superusers may use it.
+func NewAPI(backend Backend, authorizer facade.Authorizer) (*API, error) {
+	if !authorizer.AuthClient() {
+		return nil, common.ErrPerm
+	}
+	isAdmin, err := authorizer.HasPermission(permission.SuperuserAccess, backend.ControllerTag())
+	if err != nil && !errors.IsNotFound(err) {
+		return nil, errors.Trace(err)
+	}
+	if !isAdmin {
+		return nil, common.ErrPerm
+	}
+	return &API{
+		backend:    backend,
+		authorizer: authorizer,
+	}, nil
+}
+
+// Query returns the audit entries matching the supplied arguments,
+// oldest first.
+func (api *API) Query(args params.AuditLogQueryArgs) (params.AuditLogQueryResults, error) {
+	var results params.AuditLogQueryResults
+	filter := state.AuditEntryFilter{
+		OriginName: args.OriginName,
+		Operation:  args.Operation,
+		Limit:      args.Limit,
+	}
+	if args.ModelTag != "" {
+		modelTag, err := names.ParseModelTag(args.ModelTag)
+		if err != nil {
+			return results, errors.Trace(err)
+		}
+		filter.ModelUUID = modelTag.Id()
+	}
+	if args.After != nil {
+		filter.After = *args.After
+	}
+	if args.Before != nil {
+		filter.Before = *args.Before
+	}
+	if filter.Limit <= 0 || filter.Limit > maxQueryLimit {
+		filter.Limit = maxQueryLimit
+	}
+	entries, err := api.backend.AuditEntries(filter)
+	if err != nil {
+		return results, errors.Trace(err)
+	}
+	results.Entries = make([]params.AuditLogEntry, len(entries))
+	for i, entry := range entries {
+		results.Entries[i] = params.AuditLogEntry{
+			JujuServerVersion: entry.JujuServerVersion.String(),
+			ModelTag:          names.NewModelTag(entry.ModelUUID).String(),
+			Timestamp:         entry.Timestamp,
+			RemoteAddress:     entry.RemoteAddress,
+			OriginType:        entry.OriginType,
+			OriginName:        entry.OriginName,
+			Operation:         entry.Operation,
+			Data:              entry.Data,
+		}
+	}
+	return results, nil
+}
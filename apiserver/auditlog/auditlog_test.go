@@ -0,0 +1,136 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package auditlog_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/auditlog"
+	"github.com/juju/juju/apiserver/params"
+	apiservertesting "github.com/juju/juju/apiserver/testing"
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type AuditLogSuite struct {
+	testing.IsolationSuite
+
+	backend    *stubBackend
+	authorizer apiservertesting.FakeAuthorizer
+}
+
+var _ = gc.Suite(&AuditLogSuite{})
+
+func (s *AuditLogSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.backend = &stubBackend{}
+	s.authorizer = apiservertesting.FakeAuthorizer{
+		Tag: names.NewUserTag("superuser-bob"),
+	}
+}
+
+func (s *AuditLogSuite) TestRefusesNonSuperuser(c *gc.C) {
+	s.authorizer.Tag = names.NewUserTag("admin-bob")
+	_, err := auditlog.NewAPI(s.backend, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *AuditLogSuite) TestRefusesAgent(c *gc.C) {
+	s.authorizer.Tag = names.NewMachineTag("0")
+	_, err := auditlog.NewAPI(s.backend, s.authorizer)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+func (s *AuditLogSuite) TestQuery(c *gc.C) {
+	t0 := time.Date(2017, time.March, 7, 10, 0, 0, 0, time.UTC)
+	s.backend.entries = []audit.AuditEntry{{
+		JujuServerVersion: version.MustParse("2.2.0"),
+		ModelUUID:         coretesting.ModelTag.Id(),
+		Timestamp:         t0,
+		RemoteAddress:     "10.0.0.1",
+		OriginType:        "API request",
+		OriginName:        "user-bob",
+		Operation:         "Application:v3 - Destroy",
+		Data:              map[string]interface{}{"request-body": "mysql"},
+	}}
+	api, err := auditlog.NewAPI(s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	before := t0.Add(time.Hour)
+	results, err := api.Query(params.AuditLogQueryArgs{
+		ModelTag:   coretesting.ModelTag.String(),
+		OriginName: "user-bob",
+		Operation:  "Destroy",
+		After:      &t0,
+		Before:     &before,
+		Limit:      5,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.backend.filter, jc.DeepEquals, state.AuditEntryFilter{
+		ModelUUID:  coretesting.ModelTag.Id(),
+		OriginName: "user-bob",
+		Operation:  "Destroy",
+		After:      t0,
+		Before:     before,
+		Limit:      5,
+	})
+	c.Check(results, jc.DeepEquals, params.AuditLogQueryResults{
+		Entries: []params.AuditLogEntry{{
+			JujuServerVersion: "2.2.0",
+			ModelTag:          coretesting.ModelTag.String(),
+			Timestamp:         t0,
+			RemoteAddress:     "10.0.0.1",
+			OriginType:        "API request",
+			OriginName:        "user-bob",
+			Operation:         "Application:v3 - Destroy",
+			Data:              map[string]interface{}{"request-body": "mysql"},
+		}},
+	})
+}
+
+func (s *AuditLogSuite) TestQueryDefaultLimit(c *gc.C) {
+	api, err := auditlog.NewAPI(s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.Query(params.AuditLogQueryArgs{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.backend.filter, jc.DeepEquals, state.AuditEntryFilter{Limit: 10000})
+}
+
+func (s *AuditLogSuite) TestQueryInvalidModelTag(c *gc.C) {
+	api, err := auditlog.NewAPI(s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.Query(params.AuditLogQueryArgs{ModelTag: "machine-0"})
+	c.Assert(err, gc.ErrorMatches, `"machine-0" is not a valid model tag`)
+}
+
+func (s *AuditLogSuite) TestQueryError(c *gc.C) {
+	s.backend.err = errors.New("boom")
+	api, err := auditlog.NewAPI(s.backend, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = api.Query(params.AuditLogQueryArgs{})
+	c.Assert(err, gc.ErrorMatches, "boom")
+}
+
+type stubBackend struct {
+	filter  state.AuditEntryFilter
+	entries []audit.AuditEntry
+	err     error
+}
+
+func (b *stubBackend) ControllerTag() names.ControllerTag {
+	return coretesting.ControllerTag
+}
+
+func (b *stubBackend) AuditEntries(filter state.AuditEntryFilter) ([]audit.AuditEntry, error) {
+	b.filter = filter
+	return b.entries, b.err
+}
@@ -10,13 +10,35 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/backups/encryption"
 )
 
 var waitUntilReady = replicaset.WaitUntilReady
 
 // Create is the API method that requests juju to create a new backup
-// of its state.  It returns the metadata for that backup.
+// of its state. Version 1 of the facade does not support encryption.
+func (a *APIv1) Create(args params.BackupsCreateArgs) (params.BackupsMetadataResult, error) {
+	if args.EncryptionKey != "" {
+		return params.BackupsMetadataResult{}, errors.NotSupportedf("encryption in version 1 of the Backups facade")
+	}
+	return a.API.Create(args)
+}
+
+// Create is the API method that requests juju to create a new backup
+// of its state.  It returns the metadata for that backup. If an
+// encryption key is supplied, the archive is encrypted with it.
 func (a *API) Create(args params.BackupsCreateArgs) (p params.BackupsMetadataResult, err error) {
+	var key *encryption.Key
+	if args.EncryptionKey != "" {
+		key, err = encryption.ParseKey([]byte(args.EncryptionKey))
+		if err != nil {
+			return p, errors.Annotate(err, "invalid encryption key")
+		}
+		if key.IsPrivateKey() {
+			return p, errors.New("invalid encryption key: private keys must not be sent to the controller")
+		}
+	}
+
 	backupsMethods, closer := newBackups(a.backend)
 	defer closer.Close()
 
@@ -53,7 +75,7 @@ func (a *API) Create(args params.BackupsCreateArgs) (p params.BackupsMetadataRes
 	}
 	meta.Notes = args.Notes
 
-	err = backupsMethods.Create(meta, a.paths, dbInfo)
+	err = backupsMethods.Create(meta, a.paths, dbInfo, key)
 	if err != nil {
 		return p, errors.Trace(err)
 	}
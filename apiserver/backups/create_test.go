@@ -42,6 +42,43 @@ func (s *backupsSuite) TestCreateNotes(c *gc.C) {
 	c.Check(result, gc.DeepEquals, expected)
 }
 
+func (s *backupsSuite) TestCreateEncrypted(c *gc.C) {
+	s.PatchValue(backups.WaitUntilReady,
+		func(*mgo.Session, int) error { return nil },
+	)
+	fake := s.setBackups(c, s.meta, "")
+	args := params.BackupsCreateArgs{
+		EncryptionKey: "correct horse battery staple",
+	}
+	_, err := s.api.Create(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(fake.KeyArg, gc.NotNil)
+}
+
+func (s *backupsSuite) TestCreateV1NotEncrypted(c *gc.C) {
+	fake := s.setBackups(c, s.meta, "")
+	api := &backups.APIv1{API: s.api}
+	args := params.BackupsCreateArgs{
+		EncryptionKey: "correct horse battery staple",
+	}
+	_, err := api.Create(args)
+
+	c.Check(err, gc.ErrorMatches, "encryption in version 1 of the Backups facade not supported")
+	c.Check(fake.Calls, gc.HasLen, 0)
+}
+
+func (s *backupsSuite) TestCreateInvalidEncryptionKey(c *gc.C) {
+	fake := s.setBackups(c, s.meta, "")
+	args := params.BackupsCreateArgs{
+		EncryptionKey: "short",
+	}
+	_, err := s.api.Create(args)
+
+	c.Check(err, gc.ErrorMatches, `invalid encryption key: encryption key too short \(need at least 16 bytes\)`)
+	c.Check(fake.Calls, gc.HasLen, 0)
+}
+
 func (s *backupsSuite) TestCreateError(c *gc.C) {
 	s.setBackups(c, nil, "failed!")
 	s.PatchValue(backups.WaitUntilReady,
@@ -17,7 +17,8 @@ import (
 // *trivially* correct, you would be Doing It Wrong.
 
 func init() {
-	common.RegisterStandardFacade("Backups", 1, newAPI)
+	common.RegisterStandardFacade("Backups", 1, newAPIv1)
+	common.RegisterStandardFacade("Backups", 2, newAPI) // Adds encryption.
 }
 
 type stateShim struct {
@@ -36,3 +37,11 @@ func (s *stateShim) MachineSeries(id string) (string, error) {
 func newAPI(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*API, error) {
 	return NewAPI(&stateShim{st}, resources, authorizer)
 }
+
+func newAPIv1(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*APIv1, error) {
+	api, err := newAPI(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &APIv1{api}, nil
+}
@@ -31,6 +31,7 @@ import (
 
 func init() {
 	common.RegisterStandardFacade("Client", 1, newClient)
+	common.RegisterStandardFacade("Client", 2, newClient)
 }
 
 var logger = loggo.GetLogger("juju.apiserver.client")
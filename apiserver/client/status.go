@@ -7,6 +7,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/utils/featureflag"
@@ -163,6 +164,99 @@ func (c *Client) StatusHistory(request params.StatusHistoryRequests) params.Stat
 	return results
 }
 
+// QueryStatusHistory returns a page of the status history entries of
+// the units and machines in the model, or of the units of an
+// application, within the requested time bounds. If requested, a
+// summary of each entity's history is returned instead; the summary
+// covers every matching entry, so it may not be paged.
+func (c *Client) QueryStatusHistory(args params.StatusHistoryQuery) (params.StatusHistoryQueryResult, error) {
+	if err := c.checkCanRead(); err != nil {
+		return params.StatusHistoryQueryResult{}, err
+	}
+	if args.Application != "" && !names.IsValidApplication(args.Application) {
+		return params.StatusHistoryQueryResult{}, errors.NotValidf("application name %q", args.Application)
+	}
+	query := status.HistoryQuery{
+		Application: args.Application,
+		FromDate:    args.FromDate,
+		ToDate:      args.ToDate,
+		Exclude:     set.NewStrings(args.Exclude...),
+		After:       args.After,
+		Limit:       args.Limit,
+	}
+	if args.Aggregate {
+		if args.After != "" || args.Limit != 0 {
+			return params.StatusHistoryQueryResult{}, errors.NotValidf("paging aggregated status history")
+		}
+		return c.aggregateStatusHistory(query)
+	}
+
+	entries, next, err := c.api.stateAccessor.QueryStatusHistory(query)
+	if err != nil {
+		return params.StatusHistoryQueryResult{}, errors.Trace(err)
+	}
+	result := params.StatusHistoryQueryResult{
+		Entries: make([]params.StatusHistoryEntry, len(entries)),
+		Next:    next,
+	}
+	for i, entry := range entries {
+		result.Entries[i] = params.StatusHistoryEntry{
+			Id:     entry.Id,
+			Entity: entry.Entity,
+			Kind:   string(entry.Kind),
+			Status: string(entry.Status),
+			Info:   entry.Message,
+			Since:  entry.Since,
+		}
+	}
+	return result, nil
+}
+
+// aggregateStatusHistory summarises each entity's status history
+// matching the query, reading the entries a page at a time.
+func (c *Client) aggregateStatusHistory(query status.HistoryQuery) (params.StatusHistoryQueryResult, error) {
+	var start time.Time
+	if query.FromDate != nil {
+		start = *query.FromDate
+	}
+	aggregator := status.NewHistoryAggregator(start)
+	query.Limit = status.MaxHistoryQueryLimit
+	for {
+		entries, next, err := c.api.stateAccessor.QueryStatusHistory(query)
+		if err != nil {
+			return params.StatusHistoryQueryResult{}, errors.Trace(err)
+		}
+		for _, entry := range entries {
+			aggregator.Add(entry)
+		}
+		if next == "" {
+			break
+		}
+		query.After = next
+	}
+
+	// Each entity's last status is held until the end of the query.
+	end := time.Now()
+	if query.ToDate != nil && query.ToDate.Before(end) {
+		end = *query.ToDate
+	}
+	var result params.StatusHistoryQueryResult
+	for _, aggregate := range aggregator.Results(end) {
+		durations := make(map[string]time.Duration)
+		for s, d := range aggregate.Durations {
+			durations[string(s)] = d
+		}
+		result.Aggregates = append(result.Aggregates, params.StatusHistoryAggregate{
+			Entity:           aggregate.Entity,
+			Kind:             string(aggregate.Kind),
+			Durations:        durations,
+			Transitions:      aggregate.Transitions,
+			ErrorTransitions: aggregate.ErrorTransitions,
+		})
+	}
+	return result, nil
+}
+
 // FullStatus gives the information needed for juju status over the api
 func (c *Client) FullStatus(args params.StatusParams) (params.FullStatus, error) {
 	if err := c.checkCanRead(); err != nil {
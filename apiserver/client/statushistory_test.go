@@ -4,6 +4,7 @@
 package client_test
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/juju/errors"
@@ -218,10 +219,145 @@ func (s *statusHistoryTestSuite) TestStatusHistoryCombined(c *gc.C) {
 	checkStatusInfo(c, h.Results[0].History.Statuses, expected)
 }
 
+func (s *statusHistoryTestSuite) TestQueryStatusHistory(c *gc.C) {
+	start := time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC)
+	s.st.history = []status.HistoryEntry{
+		{Id: "a", Entity: "mysql/0", Kind: status.KindWorkload, Status: status.Maintenance, Message: "installing", Since: start},
+		{Id: "b", Entity: "mysql/0", Kind: status.KindWorkload, Status: status.Active, Since: start.Add(time.Minute)},
+		{Id: "c", Entity: "mysql/0", Kind: status.KindWorkload, Status: status.Blocked, Since: start.Add(2 * time.Minute)},
+	}
+	result, err := s.api.QueryStatusHistory(params.StatusHistoryQuery{
+		Application: "mysql",
+		FromDate:    &start,
+		Exclude:     []string{"running update-status hook"},
+		Limit:       2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.st.query.Application, gc.Equals, "mysql")
+	c.Assert(s.st.query.FromDate, gc.Equals, &start)
+	c.Assert(s.st.query.Exclude.Values(), jc.DeepEquals, []string{"running update-status hook"})
+	c.Assert(s.st.query.Limit, gc.Equals, 2)
+	c.Assert(result.Aggregates, gc.HasLen, 0)
+	c.Assert(result.Entries, jc.DeepEquals, []params.StatusHistoryEntry{
+		{Id: "a", Entity: "mysql/0", Kind: "workload", Status: "maintenance", Info: "installing", Since: start},
+		{Id: "b", Entity: "mysql/0", Kind: "workload", Status: "active", Since: start.Add(time.Minute)},
+	})
+	c.Assert(result.Next, gc.Equals, "b")
+
+	result, err = s.api.QueryStatusHistory(params.StatusHistoryQuery{
+		Application: "mysql",
+		After:       result.Next,
+		Limit:       2,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.st.query.After, gc.Equals, "b")
+	c.Assert(result.Entries, jc.DeepEquals, []params.StatusHistoryEntry{
+		{Id: "c", Entity: "mysql/0", Kind: "workload", Status: "blocked", Since: start.Add(2 * time.Minute)},
+	})
+	c.Assert(result.Next, gc.Equals, "")
+}
+
+func (s *statusHistoryTestSuite) TestQueryStatusHistoryAggregate(c *gc.C) {
+	start := time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour)
+	s.st.history = []status.HistoryEntry{
+		{Entity: "mysql/0", Kind: status.KindWorkload, Status: status.Error, Since: start},
+		{Entity: "mysql/0", Kind: status.KindWorkload, Status: status.Active, Since: start.Add(10 * time.Minute)},
+	}
+	result, err := s.api.QueryStatusHistory(params.StatusHistoryQuery{
+		ToDate:    &end,
+		Aggregate: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Entries, gc.HasLen, 0)
+	c.Assert(result.Aggregates, jc.DeepEquals, []params.StatusHistoryAggregate{{
+		Entity: "mysql/0",
+		Kind:   "workload",
+		Durations: map[string]time.Duration{
+			"error":  10 * time.Minute,
+			"active": 50 * time.Minute,
+		},
+		Transitions:      1,
+		ErrorTransitions: 1,
+	}})
+}
+
+func (s *statusHistoryTestSuite) TestQueryStatusHistoryAggregatePages(c *gc.C) {
+	start := time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC)
+	from := start.Add(30 * time.Minute)
+	end := start.Add(time.Hour)
+	// The first entry is from before the query's start, and gives the
+	// status at that time.
+	for i := 0; i <= status.MaxHistoryQueryLimit; i++ {
+		entryStatus := status.Active
+		if i%2 == 0 {
+			entryStatus = status.Error
+		}
+		s.st.history = append(s.st.history, status.HistoryEntry{
+			Id:     fmt.Sprint(i),
+			Entity: "mysql/0",
+			Kind:   status.KindWorkload,
+			Status: entryStatus,
+			Since:  start,
+		})
+	}
+	result, err := s.api.QueryStatusHistory(params.StatusHistoryQuery{
+		FromDate:  &from,
+		ToDate:    &end,
+		Aggregate: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.st.queries, gc.Equals, 2)
+	c.Assert(result.Aggregates, jc.DeepEquals, []params.StatusHistoryAggregate{{
+		Entity: "mysql/0",
+		Kind:   "workload",
+		Durations: map[string]time.Duration{
+			"error": 30 * time.Minute,
+		},
+		Transitions:      status.MaxHistoryQueryLimit,
+		ErrorTransitions: status.MaxHistoryQueryLimit/2 + 1,
+	}})
+}
+
+func (s *statusHistoryTestSuite) TestQueryStatusHistoryAggregateNotPaged(c *gc.C) {
+	_, err := s.api.QueryStatusHistory(params.StatusHistoryQuery{Aggregate: true, Limit: 10})
+	c.Assert(err, gc.ErrorMatches, "paging aggregated status history not valid")
+}
+
+func (s *statusHistoryTestSuite) TestQueryStatusHistoryInvalidApplication(c *gc.C) {
+	_, err := s.api.QueryStatusHistory(params.StatusHistoryQuery{Application: "mysql/0"})
+	c.Assert(err, gc.ErrorMatches, `application name "mysql/0" not valid`)
+}
+
 type mockState struct {
 	client.Backend
 	unitHistory  []status.StatusInfo
 	agentHistory []status.StatusInfo
+	history      []status.HistoryEntry
+	query        status.HistoryQuery
+	queries      int
+}
+
+func (m *mockState) QueryStatusHistory(query status.HistoryQuery) ([]status.HistoryEntry, string, error) {
+	m.query = query
+	m.queries++
+	entries := m.history
+	if query.After != "" {
+		for i, entry := range entries {
+			if entry.Id == query.After {
+				entries = entries[i+1:]
+				break
+			}
+		}
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = status.DefaultHistoryQueryLimit
+	}
+	if len(entries) > limit {
+		return entries[:limit], entries[limit-1].Id, nil
+	}
+	return entries, "", nil
 }
 
 func (m *mockState) ModelUUID() string {
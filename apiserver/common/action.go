@@ -27,6 +27,8 @@ func ParamsActionExecutionResultsToStateActionResults(arg params.ActionExecution
 		status = state.ActionFailed
 	case params.ActionPending:
 		status = state.ActionPending
+	case params.ActionTimedOut:
+		status = state.ActionTimedOut
 	default:
 		return state.ActionResults{}, errors.Errorf("unrecognized action status '%s'", arg.Status)
 	}
@@ -151,14 +153,49 @@ func Actions(args params.Entities, actionFn func(string) (state.Action, error))
 			continue
 		}
 		results.Results[i].Action = &params.Action{
-			Name:       action.Name(),
-			Parameters: action.Parameters(),
+			Name:         action.Name(),
+			Parameters:   action.Parameters(),
+			Timeout:      action.Timeout(),
+			StreamOutput: action.StreamOutput(),
 		}
 	}
 
 	return results
 }
 
+// LogActionsMessages adds the given progress messages to running Actions.
+// Consecutive messages for the same Action are logged together, so that
+// a batch of output costs one write.
+// It's a helper function currently used by the uniter.
+// It needs an actionFn that can fetch an action from state using it's id that's usually created by AuthAndActionFromTagFn
+func LogActionsMessages(args params.ActionMessageArgs, actionFn func(string) (state.Action, error)) params.ErrorResults {
+	results := params.ErrorResults{Results: make([]params.ErrorResult, len(args.Messages))}
+
+	for start := 0; start < len(args.Messages); {
+		tag := args.Messages[start].ActionTag
+		end := start + 1
+		for end < len(args.Messages) && args.Messages[end].ActionTag == tag {
+			end++
+		}
+		messages := make([]string, 0, end-start)
+		for _, arg := range args.Messages[start:end] {
+			messages = append(messages, arg.Message)
+		}
+		action, err := actionFn(tag)
+		if err == nil {
+			err = action.Log(messages...)
+		}
+		if err != nil {
+			for i := start; i < end; i++ {
+				results.Results[i].Error = ServerError(err)
+			}
+		}
+		start = end
+	}
+
+	return results
+}
+
 // WatchOneActionReceiverNotifications to create a watcher for one receiver.
 // It needs a tagToActionReceiver function and a registerFunc to register
 // resources.
@@ -235,16 +272,35 @@ func MakeActionResult(actionReceiverTag names.Tag, action state.Action) params.A
 	output, message := action.Results()
 	return params.ActionResult{
 		Action: &params.Action{
-			Receiver:   actionReceiverTag.String(),
-			Tag:        action.ActionTag().String(),
-			Name:       action.Name(),
-			Parameters: action.Parameters(),
+			Receiver:     actionReceiverTag.String(),
+			Tag:          action.ActionTag().String(),
+			Name:         action.Name(),
+			Parameters:   action.Parameters(),
+			Timeout:      action.Timeout(),
+			StreamOutput: action.StreamOutput(),
 		},
 		Status:    string(action.Status()),
 		Message:   message,
 		Output:    output,
+		Log:       convertActionMessages(action.Messages()),
+		Operation: action.Operation(),
 		Enqueued:  action.Enqueued(),
 		Started:   action.Started(),
 		Completed: action.Completed(),
 	}
 }
+
+func convertActionMessages(messages []state.ActionMessage) []params.ActionMessage {
+	if len(messages) == 0 {
+		return nil
+	}
+	result := make([]params.ActionMessage, len(messages))
+	for i, m := range messages {
+		result[i] = params.ActionMessage{
+			Sequence:  m.Sequence,
+			Timestamp: m.Timestamp,
+			Message:   m.Message,
+		}
+	}
+	return result
+}
@@ -37,6 +37,9 @@ func (f *fakeControllerAccessor) ControllerConfig() (controller.Config, error) {
 		controller.CACertKey:         testing.CACert,
 		controller.APIPort:           4321,
 		controller.StatePort:         1234,
+
+		controller.BackupStoreS3AccessKey: "access",
+		controller.BackupStoreS3SecretKey: "secret",
 	}, nil
 }
 
@@ -56,6 +59,9 @@ func (*controllerConfigSuite) TestControllerConfigSuccess(c *gc.C) {
 		"controller-uuid": "deadbeef-1bad-500d-9000-4b1d0d06f00d",
 		"state-port":      1234,
 		"api-port":        4321,
+
+		// The secret key is not revealed.
+		"backup-store-s3-access-key": "access",
 	})
 }
 
@@ -113,6 +113,17 @@ func OperationBlockedError(msg string) error {
 	}
 }
 
+// RateLimitedError returns an error which signifies that a request
+// was rejected because the caller exceeded its API request rate
+// limit; the message should describe the limit and when the request
+// may be retried.
+func RateLimitedError(msg string) error {
+	return &params.Error{
+		Message: msg,
+		Code:    params.CodeRateLimited,
+	}
+}
+
 var singletonErrorCodes = map[error]string{
 	state.ErrCannotEnterScopeYet: params.CodeCannotEnterScopeYet,
 	state.ErrCannotEnterScope:    params.CodeCannotEnterScope,
@@ -184,6 +195,8 @@ func ServerErrorAndStatus(err error) (*params.Error, int) {
 		status = http.StatusUnauthorized
 	case params.CodeRetry:
 		status = http.StatusServiceUnavailable
+	case params.CodeRateLimited:
+		status = http.StatusTooManyRequests
 	}
 	return err1, status
 }
@@ -148,6 +148,11 @@ var errorTransformTests = []struct {
 	code:       params.CodeOperationBlocked,
 	status:     http.StatusBadRequest,
 	helperFunc: params.IsCodeOperationBlocked,
+}, {
+	err:        common.RateLimitedError("test"),
+	code:       params.CodeRateLimited,
+	status:     http.StatusTooManyRequests,
+	helperFunc: params.IsCodeRateLimited,
 }, {
 	err:        errors.NotSupportedf("needed feature"),
 	code:       params.CodeNotSupported,
@@ -241,8 +246,9 @@ func (s *errorsSuite) TestErrorTransform(c *gc.C) {
 			params.CodeModelNotFound,
 			params.CodeRetry:
 			continue
-		case params.CodeOperationBlocked:
-			// ServerError doesn't actually have a case for this code.
+		case params.CodeOperationBlocked,
+			params.CodeRateLimited:
+			// ServerError doesn't actually have a case for these codes.
 			continue
 		}
 
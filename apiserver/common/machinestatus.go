@@ -24,30 +24,14 @@ func MachineStatus(machine MachineStatusGetter) (status.StatusInfo, error) {
 	if err != nil {
 		return status.StatusInfo{}, err
 	}
-
-	if !canMachineBeDown(machineStatus) {
-		// The machine still being provisioned - there's no point in
-		// enquiring about the agent liveness.
-		return machineStatus, nil
-	}
-
-	agentAlive, err := machine.AgentPresence()
-	if err != nil {
-		// We don't want any presence errors affecting status.
-		logger.Debugf("error determining presence for machine %s: %v", machine.Id(), err)
-		return machineStatus, nil
-	}
-	if machine.Life() != state.Dead && !agentAlive {
-		machineStatus.Status = status.Down
-		machineStatus.Message = "agent is not communicating with the server"
-	}
-	return machineStatus, nil
-}
-
-func canMachineBeDown(machineStatus status.StatusInfo) bool {
-	switch machineStatus.Status {
-	case status.Pending, status.Stopped:
-		return false
+	agentAlive := func() bool {
+		alive, err := machine.AgentPresence()
+		if err != nil {
+			// We don't want any presence errors affecting status.
+			logger.Debugf("error determining presence for machine %s: %v", machine.Id(), err)
+			return true
+		}
+		return alive
 	}
-	return true
+	return status.MachineStatusWithPresence(machineStatus, machine.Life() == state.Dead, agentAlive), nil
 }
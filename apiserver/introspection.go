@@ -7,6 +7,7 @@ import (
 	"net/http"
 
 	"github.com/juju/errors"
+	"github.com/juju/utils/set"
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
@@ -18,8 +19,18 @@ import (
 type introspectionHandler struct {
 	ctx     httpContext
 	handler http.Handler
+
+	// allowReadAccess is true if users with "read" access on
+	// the controller model, and not just controller superusers,
+	// may access the endpoint. This allows a dedicated user to
+	// scrape the metrics without being able to profile the agent.
+	allowReadAccess bool
 }
 
+// introspectionReadAccessPaths holds the introspection endpoints that
+// users with read access on the controller model may access.
+var introspectionReadAccessPaths = set.NewStrings("metrics")
+
 // ServeHTTP is part of the http.Handler interface.
 func (h introspectionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if err := h.checkAuth(r); err != nil {
@@ -38,9 +49,9 @@ func (h introspectionHandler) checkAuth(r *http.Request) error {
 	}
 	defer releaser()
 
-	// Users with "superuser" access on the controller,
-	// or "read" access on the controller model, can
-	// access these endpoints.
+	// Users with "superuser" access on the controller can access
+	// all of these endpoints, and users with "read" access on the
+	// controller model can access those that allow it.
 
 	ok, err := common.HasPermission(
 		st.UserAccess,
@@ -54,6 +65,12 @@ func (h introspectionHandler) checkAuth(r *http.Request) error {
 	if ok {
 		return nil
 	}
+	if !h.allowReadAccess {
+		return &params.Error{
+			Code:    params.CodeForbidden,
+			Message: "access denied",
+		}
+	}
 
 	controllerModel, err := st.ControllerModel()
 	if err != nil {
@@ -29,15 +29,13 @@ func (s *introspectionSuite) SetUpTest(c *gc.C) {
 	s.bob = bob
 }
 
-func (s *introspectionSuite) url(c *gc.C) string {
+func (s *introspectionSuite) url(c *gc.C, subpath string) string {
 	url := s.baseURL(c)
-	url.Path = "/introspection/navel"
+	url.Path = "/introspection/" + subpath
 	return url.String()
 }
 
-func (s *introspectionSuite) TestAccess(c *gc.C) {
-	s.testAccess(c, "user-admin", "dummy-secret")
-
+func (s *introspectionSuite) addReadAccess(c *gc.C) {
 	_, err := s.BackingState.AddModelUser(
 		s.BackingState.ModelTag().Id(),
 		state.UserAccessSpec{
@@ -47,13 +45,22 @@ func (s *introspectionSuite) TestAccess(c *gc.C) {
 		},
 	)
 	c.Assert(err, jc.ErrorIsNil)
-	s.testAccess(c, "user-bob", "hunter2")
 }
 
-func (s *introspectionSuite) testAccess(c *gc.C, tag, password string) {
+func (s *introspectionSuite) TestAccess(c *gc.C) {
+	s.testAccess(c, "user-admin", "dummy-secret", "navel", "gazing")
+	s.testAccess(c, "user-admin", "dummy-secret", "metrics", "counting")
+}
+
+func (s *introspectionSuite) TestMetricsReadAccess(c *gc.C) {
+	s.addReadAccess(c)
+	s.testAccess(c, "user-bob", "hunter2", "metrics", "counting")
+}
+
+func (s *introspectionSuite) testAccess(c *gc.C, tag, password, subpath, expect string) {
 	resp := s.sendRequest(c, httpRequestParams{
 		method:   "GET",
-		url:      s.url(c),
+		url:      s.url(c, subpath),
 		tag:      tag,
 		password: password,
 	})
@@ -61,16 +68,28 @@ func (s *introspectionSuite) testAccess(c *gc.C, tag, password string) {
 	c.Assert(resp.StatusCode, gc.Equals, http.StatusOK)
 	content, err := ioutil.ReadAll(resp.Body)
 	c.Assert(err, jc.ErrorIsNil)
-	c.Assert(string(content), gc.Equals, "gazing")
+	c.Assert(string(content), gc.Equals, expect)
 }
 
-func (s *introspectionSuite) TestAccessDenied(c *gc.C) {
+func (s *introspectionSuite) testAccessDenied(c *gc.C, subpath string) {
 	resp := s.sendRequest(c, httpRequestParams{
 		method:   "GET",
-		url:      s.url(c),
+		url:      s.url(c, subpath),
 		tag:      "user-bob",
 		password: "hunter2",
 	})
 	defer resp.Body.Close()
 	c.Assert(resp.StatusCode, gc.Equals, http.StatusForbidden)
 }
+
+func (s *introspectionSuite) TestAccessDenied(c *gc.C) {
+	s.testAccessDenied(c, "navel")
+	s.testAccessDenied(c, "metrics")
+}
+
+func (s *introspectionSuite) TestReadAccessDeniedForReports(c *gc.C) {
+	// Only controller superusers may access the reports other
+	// than the metrics, such as the profiles.
+	s.addReadAccess(c)
+	s.testAccessDenied(c, "navel")
+}
@@ -10,10 +10,12 @@ import (
 	"github.com/gorilla/schema"
 	"github.com/gorilla/websocket"
 	"github.com/juju/errors"
+	"github.com/juju/loggo"
 	"github.com/juju/utils/clock"
 	"github.com/juju/utils/featureflag"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/audit"
 	"github.com/juju/juju/feature"
 	"github.com/juju/juju/state"
 )
@@ -21,6 +23,7 @@ import (
 type logStreamSource interface {
 	getStart(sink string, allModels bool) (time.Time, error)
 	newTailer(*state.LogTailerParams) (state.LogTailer, error)
+	newAuditTailer(state.AuditTailerParams) (state.AuditTailer, error)
 }
 
 type messageWriter interface {
@@ -29,6 +32,10 @@ type messageWriter interface {
 
 type closerFunc func()
 
+// auditModule is the module reported for audit entries sent over
+// the log stream.
+const auditModule = "juju.audit"
+
 // logStreamEndpointHandler takes requests to stream logs from the DB.
 type logStreamEndpointHandler struct {
 	stopCh    <-chan struct{}
@@ -54,6 +61,7 @@ func newLogStreamEndpointHandler(ctxt httpContext) *logStreamEndpointHandler {
 // Args for the HTTP request are as follows:
 //   all -> string - one of [true, false], if true, include records from all models
 //   sink -> string - the name of the the log forwarding target
+//   audit -> string - one of [true, false], if true, stream audit entries instead of logs
 func (h *logStreamEndpointHandler) ServeHTTP(w http.ResponseWriter, req *http.Request) {
 	logger.Infof("log stream request handler starting")
 	handler := func(conn *websocket.Conn) {
@@ -95,36 +103,49 @@ func (h *logStreamEndpointHandler) newLogStreamRequestHandler(conn messageWriter
 		return nil, errors.Annotate(err, "decoding schema")
 	}
 
-	tailer, err := h.newTailer(source, cfg, clock)
-	if err != nil {
-		return nil, errors.Annotate(err, "creating new tailer")
-	}
-
 	reqHandler := &logStreamRequestHandler{
 		conn:          conn,
 		req:           req,
-		tailer:        tailer,
 		closer:        closer,
 		sendModelUUID: cfg.AllModels,
 	}
+	if cfg.Audit {
+		reqHandler.auditTailer, err = h.newAuditTailer(source, cfg, clock)
+		if err != nil {
+			return nil, errors.Annotate(err, "creating new audit tailer")
+		}
+		return reqHandler, nil
+	}
+	reqHandler.tailer, err = h.newTailer(source, cfg, clock)
+	if err != nil {
+		return nil, errors.Annotate(err, "creating new tailer")
+	}
 	return reqHandler, nil
 }
 
-func (h *logStreamEndpointHandler) newTailer(source logStreamSource, cfg params.LogStreamConfig, clock clock.Clock) (state.LogTailer, error) {
+func (h *logStreamEndpointHandler) getStart(source logStreamSource, cfg params.LogStreamConfig, clock clock.Clock) (time.Time, error) {
 	start, err := source.getStart(cfg.Sink, cfg.AllModels)
 	if err != nil {
-		return nil, errors.Annotate(err, "getting log start position")
+		return time.Time{}, errors.Annotate(err, "getting log start position")
 	}
 	if cfg.MaxLookbackDuration != "" {
 		d, err := time.ParseDuration(cfg.MaxLookbackDuration)
 		if err != nil {
-			return nil, errors.Annotatef(err, "invalid lookback duration")
+			return time.Time{}, errors.Annotatef(err, "invalid lookback duration")
 		}
 		now := clock.Now()
 		if now.Sub(start) > d {
 			start = now.Add(-1 * d)
 		}
 	}
+	return start, nil
+}
+
+func (h *logStreamEndpointHandler) newTailer(source logStreamSource, cfg params.LogStreamConfig, clock clock.Clock) (state.LogTailer, error) {
+	start, err := h.getStart(source, cfg, clock)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	tailerArgs := &state.LogTailerParams{
 		StartTime:    start,
@@ -138,6 +159,27 @@ func (h *logStreamEndpointHandler) newTailer(source logStreamSource, cfg params.
 	return tailer, nil
 }
 
+func (h *logStreamEndpointHandler) newAuditTailer(source logStreamSource, cfg params.LogStreamConfig, clock clock.Clock) (state.AuditTailer, error) {
+	start, err := h.getStart(source, cfg, clock)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	// Audit entries are tracked by their timestamp, and more than
+	// one entry may share it, so resume with the entries at the start
+	// timestamp. As with log records, the last entries sent before
+	// are sent again; they can be recognised by their audit ID. The
+	// tailer never returns the same entry twice within a stream.
+	tailer, err := source.newAuditTailer(state.AuditTailerParams{
+		StartTime: start,
+		AllModels: cfg.AllModels,
+		Clock:     clock,
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "tailing audit entries")
+	}
+	return tailer, nil
+}
+
 // sendError sends a JSON-encoded error response.
 func (h *logStreamEndpointHandler) sendError(ws *websocket.Conn, req *http.Request, err error) {
 	// There is no need to log the error for normal operators as there is nothing
@@ -153,7 +195,7 @@ func (h *logStreamEndpointHandler) sendError(ws *websocket.Conn, req *http.Reque
 
 // logStreamState is an implementation of logStreamSource.
 type logStreamState struct {
-	state.LogTailerState
+	*state.State
 }
 
 func (st logStreamState) getStart(sink string, allModels bool) (time.Time, error) {
@@ -194,10 +236,21 @@ func (st logStreamState) newTailer(args *state.LogTailerParams) (state.LogTailer
 	return tailer, nil
 }
 
+func (st logStreamState) newAuditTailer(args state.AuditTailerParams) (state.AuditTailer, error) {
+	tailer, err := state.NewAuditTailer(st, args)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return tailer, nil
+}
+
+// logStreamRequestHandler streams either log records or audit
+// entries; exactly one of tailer and auditTailer is set.
 type logStreamRequestHandler struct {
 	conn          messageWriter
 	req           *http.Request
 	tailer        state.LogTailer
+	auditTailer   state.AuditTailer
 	sendModelUUID bool
 	closer        closerFunc
 }
@@ -205,30 +258,51 @@ type logStreamRequestHandler struct {
 func (h *logStreamRequestHandler) serveWebsocket(stop <-chan struct{}) {
 	logger.Infof("log stream request handler starting")
 
+	// One of these will remain nil, and so never be selected.
+	var logs <-chan *state.LogRecord
+	var auditEntries <-chan audit.AuditEntry
+	if h.auditTailer != nil {
+		auditEntries = h.auditTailer.Entries()
+	} else {
+		logs = h.tailer.Logs()
+	}
+
 	// TODO(wallyworld) - we currently only send one record at a time, but the API allows for
 	// sending batches of records, so we need to batch up the output from tailer.Logs().
 	for {
+		var err error
 		select {
 		case <-stop:
 			return
-		case rec, ok := <-h.tailer.Logs():
+		case rec, ok := <-logs:
 			if !ok {
 				logger.Errorf("tailer stopped: %v", h.tailer.Err())
 				return
 			}
-			if err := h.sendRecords([]*state.LogRecord{rec}, h.sendModelUUID); err != nil {
-				if isBrokenPipe(err) {
-					logger.Tracef("logstream handler stopped (client disconnected)")
-				} else {
-					logger.Errorf("logstream handler error: %v", err)
-				}
+			err = h.sendRecords([]*state.LogRecord{rec}, h.sendModelUUID)
+		case entry, ok := <-auditEntries:
+			if !ok {
+				logger.Errorf("audit tailer stopped: %v", h.auditTailer.Err())
+				return
+			}
+			err = h.sendAuditEntries([]audit.AuditEntry{entry})
+		}
+		if err != nil {
+			if isBrokenPipe(err) {
+				logger.Tracef("logstream handler stopped (client disconnected)")
+			} else {
+				logger.Errorf("logstream handler error: %v", err)
 			}
 		}
 	}
 }
 
 func (h *logStreamRequestHandler) close() {
-	h.tailer.Stop()
+	if h.auditTailer != nil {
+		h.auditTailer.Stop()
+	} else {
+		h.tailer.Stop()
+	}
 	h.closer()
 }
 
@@ -237,6 +311,11 @@ func (h *logStreamRequestHandler) sendRecords(rec []*state.LogRecord, sendModelU
 	return errors.Trace(h.conn.WriteJSON(apiRec))
 }
 
+func (h *logStreamRequestHandler) sendAuditEntries(entries []audit.AuditEntry) error {
+	apiRec := h.apiFromAuditEntries(entries)
+	return errors.Trace(h.conn.WriteJSON(apiRec))
+}
+
 func (h *logStreamRequestHandler) apiFromRecords(records []*state.LogRecord, sendModelUUID bool) params.LogStreamRecords {
 	var result params.LogStreamRecords
 	result.Records = make([]params.LogStreamRecord, len(records))
@@ -258,3 +337,33 @@ func (h *logStreamRequestHandler) apiFromRecords(records []*state.LogRecord, sen
 	}
 	return result
 }
+
+// apiFromAuditEntries converts audit entries into log stream records.
+// The entry's timestamp is used as the record ID, as that is what is
+// used to track the last entry sent; the entry's own ID is sent with
+// the audit details. The model UUID is always sent, as audit entries
+// always identify their model.
+func (h *logStreamRequestHandler) apiFromAuditEntries(entries []audit.AuditEntry) params.LogStreamRecords {
+	var result params.LogStreamRecords
+	result.Records = make([]params.LogStreamRecord, len(entries))
+	for i, entry := range entries {
+		result.Records[i] = params.LogStreamRecord{
+			ID:        entry.Timestamp.UnixNano(),
+			ModelUUID: entry.ModelUUID,
+			Entity:    entry.OriginName,
+			Version:   entry.JujuServerVersion.String(),
+			Timestamp: entry.Timestamp,
+			Module:    auditModule,
+			Level:     loggo.INFO.String(),
+			Message:   entry.Operation,
+			Audit: &params.LogStreamAuditInfo{
+				ID:            entry.ID,
+				OriginType:    entry.OriginType,
+				Operation:     entry.Operation,
+				RemoteAddress: entry.RemoteAddress,
+				Data:          entry.Data,
+			},
+		}
+	}
+	return result
+}
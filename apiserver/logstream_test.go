@@ -21,6 +21,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/audit"
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
@@ -61,6 +62,70 @@ func (s *LogStreamIntSuite) TestParamConversion(c *gc.C) {
 	})
 }
 
+func (s *LogStreamIntSuite) TestParamConversionAudit(c *gc.C) {
+	cfg := params.LogStreamConfig{
+		AllModels: true,
+		Sink:      "spam-audit",
+		Audit:     true,
+	}
+	req := s.newReq(c, cfg)
+
+	stub := &testing.Stub{}
+	source := &stubSource{stub: stub}
+	source.ReturnGetStart = 10
+	handler := logStreamEndpointHandler{
+		stopCh:    nil,
+		newSource: source.newSource,
+	}
+
+	reqHandler, err := handler.newLogStreamRequestHandler(nil, req, clock.WallClock)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(reqHandler.tailer, gc.IsNil)
+	stub.CheckCallNames(c, "newSource", "getStart", "newAuditTailer")
+	stub.CheckCall(c, 1, "getStart", "spam-audit", true)
+	stub.CheckCall(c, 2, "newAuditTailer", state.AuditTailerParams{
+		StartTime: time.Unix(10, 0),
+		AllModels: true,
+		Clock:     clock.WallClock,
+	})
+}
+
+func (s *LogStreamIntSuite) TestAPIFromAuditEntries(c *gc.C) {
+	t0 := time.Date(2017, time.March, 7, 10, 0, 0, 0, time.UTC)
+	h := &logStreamRequestHandler{}
+	records := h.apiFromAuditEntries([]audit.AuditEntry{{
+		ID:                "58be8510a8f5ba0b9e3c5a4e",
+		JujuServerVersion: version.Current,
+		ModelUUID:         coretesting.ModelTag.Id(),
+		Timestamp:         t0,
+		RemoteAddress:     "10.0.0.1",
+		OriginType:        "API request",
+		OriginName:        "user-bob",
+		Operation:         "Application:v3 - Destroy",
+		Data:              map[string]interface{}{"request-body": "mysql"},
+	}})
+	c.Assert(records, jc.DeepEquals, params.LogStreamRecords{
+		Records: []params.LogStreamRecord{{
+			ID:        t0.UnixNano(),
+			ModelUUID: coretesting.ModelTag.Id(),
+			Entity:    "user-bob",
+			Version:   version.Current.String(),
+			Timestamp: t0,
+			Module:    "juju.audit",
+			Level:     "INFO",
+			Message:   "Application:v3 - Destroy",
+			Audit: &params.LogStreamAuditInfo{
+				ID:            "58be8510a8f5ba0b9e3c5a4e",
+				OriginType:    "API request",
+				Operation:     "Application:v3 - Destroy",
+				RemoteAddress: "10.0.0.1",
+				Data:          map[string]interface{}{"request-body": "mysql"},
+			},
+		}},
+	})
+}
+
 type mockClock struct {
 	clock.Clock
 	now time.Time
@@ -255,8 +320,9 @@ func (s *LogStreamIntSuite) newReq(c *gc.C, cfg params.LogStreamConfig) *http.Re
 type stubSource struct {
 	stub *testing.Stub
 
-	ReturnGetStart  int64
-	ReturnNewTailer state.LogTailer
+	ReturnGetStart       int64
+	ReturnNewTailer      state.LogTailer
+	ReturnNewAuditTailer state.AuditTailer
 }
 
 func (s *stubSource) newSource(req *http.Request) (logStreamSource, closerFunc, error) {
@@ -289,6 +355,15 @@ func (s *stubSource) newTailer(args *state.LogTailerParams) (state.LogTailer, er
 	return s.ReturnNewTailer, nil
 }
 
+func (s *stubSource) newAuditTailer(args state.AuditTailerParams) (state.AuditTailer, error) {
+	s.stub.AddCall("newAuditTailer", args)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	return s.ReturnNewAuditTailer, nil
+}
+
 type stubLogTailer struct {
 	state.LogTailer
 	stub *testing.Stub
@@ -376,6 +376,14 @@ type fakeModelDescription struct {
 	UUID string `yaml:"model-uuid"`
 }
 
+func (*fakeModelDescription) Applications() []description.Application {
+	return nil
+}
+
+func (*fakeModelDescription) Machines() []description.Machine {
+	return nil
+}
+
 func (st *mockState) Export() (description.Model, error) {
 	return &fakeModelDescription{UUID: st.modelUUID}, nil
 }
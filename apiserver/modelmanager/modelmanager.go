@@ -38,6 +38,7 @@ var logger = loggo.GetLogger("juju.apiserver.modelmanager")
 
 func init() {
 	common.RegisterStandardFacade("ModelManager", 2, newFacade)
+	common.RegisterStandardFacade("ModelManager", 3, newFacade)
 }
 
 // ModelManager defines the methods on the modelmanager API endpoint.
@@ -45,6 +46,7 @@ type ModelManager interface {
 	CreateModel(args params.ModelCreateArgs) (params.ModelInfo, error)
 	DumpModels(args params.Entities) params.MapResults
 	DumpModelsDB(args params.Entities) params.MapResults
+	ExportModels(args params.Entities) params.SerializedModelResults
 	ListModels(user params.Entity) (params.UserModelList, error)
 	DestroyModels(args params.Entities) (params.ErrorResults, error)
 }
@@ -407,6 +409,40 @@ func (m *ModelManagerAPI) dumpModelDB(args params.Entity) (map[string]interface{
 	return st.DumpAll()
 }
 
+func (m *ModelManagerAPI) exportModel(args params.Entity) (params.SerializedModel, error) {
+	var empty params.SerializedModel
+	modelTag, err := names.ParseModelTag(args.Tag)
+	if err != nil {
+		return empty, errors.Trace(err)
+	}
+
+	isModelAdmin, err := m.authorizer.HasPermission(permission.AdminAccess, modelTag)
+	if err != nil {
+		return empty, errors.Trace(err)
+	}
+	if !isModelAdmin && !m.isAdmin {
+		return empty, common.ErrPerm
+	}
+
+	st := m.state
+	if st.ModelTag() != modelTag {
+		st, err = m.state.ForModel(modelTag)
+		if err != nil {
+			if errors.IsNotFound(err) {
+				return empty, errors.Trace(common.ErrBadId)
+			}
+			return empty, errors.Trace(err)
+		}
+		defer st.Close()
+	}
+
+	model, err := st.Export()
+	if err != nil {
+		return empty, errors.Trace(err)
+	}
+	return common.SerializeModel(model)
+}
+
 // DumpModels will export the models into the database agnostic
 // representation. The user needs to either be a controller admin, or have
 // admin privileges on the model itself.
@@ -443,6 +479,26 @@ func (m *ModelManagerAPI) DumpModelsDB(args params.Entities) params.MapResults {
 	return results
 }
 
+// ExportModels serializes the specified models, as for migration,
+// along with the charms, tools and resources each model uses. The
+// result is everything needed to recreate the model later. The user
+// needs to either be a controller admin, or have admin privileges on
+// the model itself.
+func (m *ModelManagerAPI) ExportModels(args params.Entities) params.SerializedModelResults {
+	results := params.SerializedModelResults{
+		Results: make([]params.SerializedModelResult, len(args.Entities)),
+	}
+	for i, entity := range args.Entities {
+		serialized, err := m.exportModel(entity)
+		if err != nil {
+			results.Results[i].Error = common.ServerError(err)
+			continue
+		}
+		results.Results[i].Result = serialized
+	}
+	return results
+}
+
 // ListModels returns the models that the specified user
 // has access to in the current server.  Only that controller owner
 // can list models for any user (at this stage).  Other users
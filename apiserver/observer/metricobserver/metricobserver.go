@@ -14,6 +14,7 @@ import (
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/apiserver/observer"
+	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/rpc"
 )
 
@@ -31,6 +32,12 @@ var metricLabelNames = []string{
 	errorCodeLabel,
 }
 
+var rateLimitedLabelNames = []string{
+	facadeLabel,
+	versionLabel,
+	methodLabel,
+}
+
 // Config contains the configuration for an Observer.
 type Config struct {
 	// Clock is the clock to use for all time-related operations.
@@ -74,6 +81,13 @@ func NewObserverFactory(config Config) (observer.ObserverFactory, error) {
 		Help:      "Latency of Juju API requests in seconds.",
 	}, metricLabelNames)
 
+	apiRequestsRateLimited := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: "juju",
+		Subsystem: "api",
+		Name:      "requests_rate_limited_total",
+		Help:      "Number of Juju API requests rejected because the caller exceeded its rate limit.",
+	}, rateLimitedLabelNames)
+
 	config.PrometheusRegisterer.Unregister(apiRequestsTotal)
 	if err := config.PrometheusRegisterer.Register(apiRequestsTotal); err != nil {
 		return nil, errors.Trace(err)
@@ -84,14 +98,20 @@ func NewObserverFactory(config Config) (observer.ObserverFactory, error) {
 		return nil, errors.Trace(err)
 	}
 
+	config.PrometheusRegisterer.Unregister(apiRequestsRateLimited)
+	if err := config.PrometheusRegisterer.Register(apiRequestsRateLimited); err != nil {
+		return nil, errors.Trace(err)
+	}
+
 	// Observer is currently stateless, so we return the same one for each
 	// API connection. Individual RPC requests still get their own RPC
 	// observers.
 	o := &Observer{
 		clock: config.Clock,
 		metrics: metrics{
-			apiRequestDuration: apiRequestDuration,
-			apiRequestsTotal:   apiRequestsTotal,
+			apiRequestDuration:     apiRequestDuration,
+			apiRequestsTotal:       apiRequestsTotal,
+			apiRequestsRateLimited: apiRequestsRateLimited,
 		},
 	}
 	return func() observer.Observer {
@@ -106,8 +126,9 @@ type Observer struct {
 }
 
 type metrics struct {
-	apiRequestDuration *prometheus.SummaryVec
-	apiRequestsTotal   *prometheus.CounterVec
+	apiRequestDuration     *prometheus.SummaryVec
+	apiRequestsTotal       *prometheus.CounterVec
+	apiRequestsRateLimited *prometheus.CounterVec
 }
 
 // Login is part of the observer.Observer interface.
@@ -149,4 +170,11 @@ func (o *rpcObserver) ServerReply(req rpc.Request, hdr *rpc.Header, body interfa
 	duration := o.clock.Now().Sub(o.requestStart)
 	o.metrics.apiRequestDuration.With(labels).Observe(duration.Seconds())
 	o.metrics.apiRequestsTotal.With(labels).Inc()
+	if hdr.ErrorCode == params.CodeRateLimited {
+		o.metrics.apiRequestsRateLimited.With(prometheus.Labels{
+			facadeLabel:  req.Type,
+			versionLabel: strconv.Itoa(req.Version),
+			methodLabel:  req.Action,
+		}).Inc()
+	}
 }
@@ -120,3 +120,33 @@ func (s *observerSuite) TestRPCObserver(c *gc.C) {
 		}},
 	}})
 }
+
+func (s *observerSuite) TestRPCObserverRateLimited(c *gc.C) {
+	o := s.factory().RPCObserver()
+	req := rpc.Request{
+		Type:    "api-facade",
+		Version: 42,
+		Action:  "api-method",
+	}
+	for _, errorCode := range []string{"", "rate limited", "rate limited"} {
+		o.ServerRequest(&rpc.Header{Request: req}, nil)
+		o.ServerReply(req, &rpc.Header{ErrorCode: errorCode}, nil)
+	}
+
+	metricFamilies, err := s.registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metricFamilies, gc.HasLen, 3)
+	rateLimited := metricFamilies[1]
+	c.Assert(rateLimited.GetName(), gc.Equals, "juju_api_requests_rate_limited_total")
+	c.Assert(rateLimited.Metric, gc.HasLen, 1)
+	c.Assert(rateLimited.Metric[0].GetCounter().GetValue(), gc.Equals, float64(2))
+	var labels []string
+	for _, label := range rateLimited.Metric[0].GetLabel() {
+		labels = append(labels, label.GetName()+"="+label.GetValue())
+	}
+	c.Assert(labels, jc.DeepEquals, []string{
+		"facade=api-facade",
+		"method=api-method",
+		"version=42",
+	})
+}
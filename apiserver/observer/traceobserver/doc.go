@@ -0,0 +1,9 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package traceobserver provides an implementation of
+// apiserver/observer.ObserverFactory that traces each API
+// request, recording its latency and payload sizes as Prometheus
+// histograms and keeping the slowest recent calls for an
+// introspection report.
+package traceobserver
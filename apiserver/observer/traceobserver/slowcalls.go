@@ -0,0 +1,114 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package traceobserver
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"sync"
+	"text/tabwriter"
+	"time"
+)
+
+// slowCalls keeps the slowest calls that finished within a rolling
+// window. While it is full, a call is only kept if it is slower than
+// the fastest call held, so the report is approximate: a call may be
+// dropped in favour of one that expires before it would have.
+type slowCalls struct {
+	size   int
+	window time.Duration
+
+	mu    sync.Mutex
+	calls []slowCall
+}
+
+type slowCall struct {
+	Call
+	end time.Time
+}
+
+func newSlowCalls(size int, window time.Duration) *slowCalls {
+	return &slowCalls{
+		size:   size,
+		window: window,
+	}
+}
+
+// add records the call, which finished at the given time.
+func (s *slowCalls) add(call Call, end time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(end)
+	n := len(s.calls)
+	if n == s.size && call.Duration <= s.calls[n-1].Duration {
+		return
+	}
+	i := sort.Search(n, func(i int) bool {
+		return s.calls[i].Duration < call.Duration
+	})
+	s.calls = append(s.calls, slowCall{})
+	copy(s.calls[i+1:], s.calls[i:])
+	s.calls[i] = slowCall{call, end}
+	if len(s.calls) > s.size {
+		s.calls = s.calls[:s.size]
+	}
+}
+
+// get returns the calls held at the given time, slowest first.
+func (s *slowCalls) get(now time.Time) []Call {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire(now)
+	calls := make([]Call, len(s.calls))
+	for i, call := range s.calls {
+		calls[i] = call.Call
+	}
+	return calls
+}
+
+// expire drops the calls that finished before the window preceding
+// the given time.
+func (s *slowCalls) expire(now time.Time) {
+	cutoff := now.Add(-s.window)
+	calls := s.calls[:0]
+	for _, call := range s.calls {
+		if !call.end.Before(cutoff) {
+			calls = append(calls, call)
+		}
+	}
+	s.calls = calls
+}
+
+// IntrospectionReport is part of the introspection.IntrospectionReporter
+// interface. It reports the slowest recent calls, slowest first.
+func (t *Tracer) IntrospectionReport() string {
+	calls := t.SlowCalls()
+	buff := &bytes.Buffer{}
+	fmt.Fprintf(buff, "Slowest %d calls in the last %v\n\n", t.slowCalls.size, t.slowCalls.window)
+	if len(calls) == 0 {
+		fmt.Fprintln(buff, "No calls traced.")
+		return buff.String()
+	}
+	w := tabwriter.NewWriter(buff, 0, 1, 1, ' ', 0)
+	fmt.Fprintln(w, "DURATION\tFACADE\tMETHOD\tCALLER\tREQUEST-BYTES\tRESPONSE-BYTES\tERROR-CODE\tSTARTED")
+	for _, call := range calls {
+		caller := call.Caller
+		if caller == "" {
+			caller = "-"
+		}
+		fmt.Fprintf(w, "%v\t%s(%d)\t%s\t%s\t%d\t%d\t%s\t%s\n",
+			call.Duration,
+			call.Facade, call.Version,
+			call.Method,
+			caller,
+			call.RequestSize,
+			call.ResponseSize,
+			call.ErrorCode,
+			call.Start.UTC().Format(time.RFC3339),
+		)
+	}
+	w.Flush()
+	return buff.String()
+}
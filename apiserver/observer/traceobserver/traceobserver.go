@@ -0,0 +1,252 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package traceobserver
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"github.com/prometheus/client_golang/prometheus"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/observer"
+	"github.com/juju/juju/rpc"
+)
+
+const (
+	facadeLabel = "facade"
+	methodLabel = "method"
+)
+
+var metricLabelNames = []string{
+	facadeLabel,
+	methodLabel,
+}
+
+var (
+	// durationBuckets cover API calls taking from 5ms to 5 minutes.
+	durationBuckets = []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 300}
+
+	// sizeBuckets cover payloads from 64 bytes to 4MiB.
+	sizeBuckets = prometheus.ExponentialBuckets(64, 4, 9)
+)
+
+// Config contains the configuration for a Tracer.
+type Config struct {
+	// Clock is the clock to use for all time-related operations.
+	Clock clock.Clock
+
+	// PrometheusRegisterer is the prometheus.Registerer in which metric
+	// collectors will be registered.
+	PrometheusRegisterer prometheus.Registerer
+
+	// SlowCalls is the maximum number of calls kept for the
+	// slowest calls report.
+	SlowCalls int
+
+	// SlowCallsWindow is the period over which the slowest calls
+	// are kept. Calls that finished longer ago than this are dropped
+	// from the report.
+	SlowCallsWindow time.Duration
+}
+
+// Validate validates the tracer configuration.
+func (cfg Config) Validate() error {
+	if cfg.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if cfg.PrometheusRegisterer == nil {
+		return errors.NotValidf("nil PrometheusRegisterer")
+	}
+	if cfg.SlowCalls <= 0 {
+		return errors.NotValidf("non-positive SlowCalls")
+	}
+	if cfg.SlowCallsWindow <= 0 {
+		return errors.NotValidf("non-positive SlowCallsWindow")
+	}
+	return nil
+}
+
+// Call describes a single traced API call.
+type Call struct {
+	Facade  string
+	Version int
+	Method  string
+
+	// Caller is the tag of the entity that made the call, or empty
+	// if the connection was not logged in.
+	Caller string
+
+	// Start is the time at which the request was received.
+	Start time.Time
+
+	// Duration is the time taken to serve the request.
+	Duration time.Duration
+
+	// RequestSize and ResponseSize are the sizes of the request and
+	// response messages, in bytes, as read and written by the
+	// connection's codec.
+	RequestSize  int
+	ResponseSize int
+
+	// ErrorCode holds the error code of the response, if any.
+	ErrorCode string
+}
+
+// Tracer traces API calls. Its NewObserver method is an
+// observer.ObserverFactory, and its IntrospectionReport method reports
+// the slowest recent calls.
+type Tracer struct {
+	clock     clock.Clock
+	metrics   metrics
+	slowCalls *slowCalls
+}
+
+type metrics struct {
+	requestDuration *prometheus.HistogramVec
+	requestSize     *prometheus.HistogramVec
+	responseSize    *prometheus.HistogramVec
+}
+
+// NewTracer returns a new Tracer, registering its API call histograms
+// with the configured Prometheus registerer.
+func NewTracer(config Config) (*Tracer, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Annotate(err, "validating config")
+	}
+
+	requestDuration := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "juju",
+		Subsystem: "api_trace",
+		Name:      "request_duration_seconds",
+		Help:      "Latency of traced Juju API requests in seconds.",
+		Buckets:   durationBuckets,
+	}, metricLabelNames)
+
+	requestSize := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "juju",
+		Subsystem: "api_trace",
+		Name:      "request_size_bytes",
+		Help:      "Size of traced Juju API requests in bytes.",
+		Buckets:   sizeBuckets,
+	}, metricLabelNames)
+
+	responseSize := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: "juju",
+		Subsystem: "api_trace",
+		Name:      "response_size_bytes",
+		Help:      "Size of traced Juju API responses in bytes.",
+		Buckets:   sizeBuckets,
+	}, metricLabelNames)
+
+	for _, collector := range []prometheus.Collector{
+		requestDuration, requestSize, responseSize,
+	} {
+		config.PrometheusRegisterer.Unregister(collector)
+		if err := config.PrometheusRegisterer.Register(collector); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+
+	return &Tracer{
+		clock: config.Clock,
+		metrics: metrics{
+			requestDuration: requestDuration,
+			requestSize:     requestSize,
+			responseSize:    responseSize,
+		},
+		slowCalls: newSlowCalls(config.SlowCalls, config.SlowCallsWindow),
+	}, nil
+}
+
+// NewObserver returns a new Observer for an API connection. It is an
+// observer.ObserverFactory.
+func (t *Tracer) NewObserver() observer.Observer {
+	return &Observer{tracer: t}
+}
+
+// SlowCalls returns the slowest calls that finished within the
+// configured window, slowest first.
+func (t *Tracer) SlowCalls() []Call {
+	return t.slowCalls.get(t.clock.Now())
+}
+
+// record updates the metrics and the slowest calls with the given call.
+func (t *Tracer) record(call Call) {
+	labels := prometheus.Labels{
+		facadeLabel: call.Facade,
+		methodLabel: call.Method,
+	}
+	t.metrics.requestDuration.With(labels).Observe(call.Duration.Seconds())
+	t.metrics.requestSize.With(labels).Observe(float64(call.RequestSize))
+	t.metrics.responseSize.With(labels).Observe(float64(call.ResponseSize))
+	t.slowCalls.add(call, call.Start.Add(call.Duration))
+}
+
+// Observer is an API server request observer that traces the calls
+// made on a single API connection.
+type Observer struct {
+	tracer *Tracer
+
+	mu     sync.Mutex
+	caller string
+}
+
+// Login is part of the observer.Observer interface.
+func (o *Observer) Login(entity names.Tag, _ names.ModelTag, _ bool, _ string) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.caller = entity.String()
+}
+
+// Join is part of the observer.Observer interface.
+func (*Observer) Join(req *http.Request, connectionID uint64) {}
+
+// Leave is part of the observer.Observer interface.
+func (*Observer) Leave() {}
+
+// RPCObserver is part of the observer.Observer interface.
+func (o *Observer) RPCObserver() rpc.Observer {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return &rpcObserver{
+		tracer: o.tracer,
+		caller: o.caller,
+	}
+}
+
+type rpcObserver struct {
+	tracer      *Tracer
+	caller      string
+	start       time.Time
+	requestSize int
+}
+
+// ServerRequest is part of the rpc.Observer interface.
+func (o *rpcObserver) ServerRequest(hdr *rpc.Header, body interface{}) {
+	o.start = o.tracer.clock.Now()
+	o.requestSize = hdr.Size
+}
+
+// ServerReply is part of the rpc.Observer interface. The call is
+// recorded once the reply is written, when its size is known.
+func (o *rpcObserver) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{}) {}
+
+// ServerReplyWritten is part of the rpc.ReplyWrittenObserver interface.
+func (o *rpcObserver) ServerReplyWritten(req rpc.Request, hdr *rpc.Header) {
+	o.tracer.record(Call{
+		Facade:       req.Type,
+		Version:      req.Version,
+		Method:       req.Action,
+		Caller:       o.caller,
+		Start:        o.start,
+		Duration:     o.tracer.clock.Now().Sub(o.start),
+		RequestSize:  o.requestSize,
+		ResponseSize: hdr.Size,
+		ErrorCode:    hdr.ErrorCode,
+	})
+}
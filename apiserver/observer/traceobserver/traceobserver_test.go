@@ -0,0 +1,210 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package traceobserver_test
+
+import (
+	"errors"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/observer/traceobserver"
+	"github.com/juju/juju/rpc"
+)
+
+type tracerSuite struct {
+	testing.IsolationSuite
+	clock    *testing.Clock
+	registry *prometheus.Registry
+	tracer   *traceobserver.Tracer
+}
+
+var _ = gc.Suite(&tracerSuite{})
+
+var start = time.Date(2017, 5, 1, 10, 0, 0, 0, time.UTC)
+
+func (s *tracerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(start)
+	s.registry = prometheus.NewPedanticRegistry()
+
+	var err error
+	s.tracer, err = traceobserver.NewTracer(s.config())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *tracerSuite) config() traceobserver.Config {
+	return traceobserver.Config{
+		Clock:                s.clock,
+		PrometheusRegisterer: s.registry,
+		SlowCalls:            2,
+		SlowCallsWindow:      time.Hour,
+	}
+}
+
+func (s *tracerSuite) TestValidateInvalid(c *gc.C) {
+	for i, test := range []struct {
+		mutate func(*traceobserver.Config)
+		expect string
+	}{{
+		func(cfg *traceobserver.Config) { cfg.Clock = nil },
+		"nil Clock not valid",
+	}, {
+		func(cfg *traceobserver.Config) { cfg.PrometheusRegisterer = nil },
+		"nil PrometheusRegisterer not valid",
+	}, {
+		func(cfg *traceobserver.Config) { cfg.SlowCalls = 0 },
+		"non-positive SlowCalls not valid",
+	}, {
+		func(cfg *traceobserver.Config) { cfg.SlowCallsWindow = -time.Second },
+		"non-positive SlowCallsWindow not valid",
+	}} {
+		c.Logf("test %d", i)
+		cfg := s.config()
+		test.mutate(&cfg)
+		c.Check(cfg.Validate(), gc.ErrorMatches, test.expect)
+		_, err := traceobserver.NewTracer(cfg)
+		c.Check(err, gc.ErrorMatches, "validating config: "+test.expect)
+	}
+}
+
+func (s *tracerSuite) TestNewTracerRegisterError(c *gc.C) {
+	registerer := &fakePrometheusRegisterer{}
+	registerer.SetErrors(nil, errors.New("oy vey"))
+	cfg := s.config()
+	cfg.PrometheusRegisterer = registerer
+	_, err := traceobserver.NewTracer(cfg)
+	c.Assert(err, gc.ErrorMatches, "oy vey")
+	registerer.CheckCallNames(c, "Register", "Register")
+}
+
+// call makes a call through a new RPC observer for the given API
+// connection observer, taking the given time. The request is 100
+// bytes long, and the reply 2000.
+func (s *tracerSuite) call(o *traceobserver.Observer, method string, latency time.Duration, errorCode string) {
+	req := rpc.Request{
+		Type:    "Client",
+		Version: 1,
+		Action:  method,
+	}
+	rpcObserver := o.RPCObserver()
+	rpcObserver.ServerRequest(&rpc.Header{Request: req, Version: 1, Size: 100}, struct{}{})
+	s.clock.Advance(latency)
+	hdr := &rpc.Header{ErrorCode: errorCode, Version: 1}
+	rpcObserver.ServerReply(req, hdr, struct{}{})
+	hdr.Size = 2000
+	rpcObserver.(rpc.ReplyWrittenObserver).ServerReplyWritten(req, hdr)
+}
+
+func (s *tracerSuite) newObserver(c *gc.C) *traceobserver.Observer {
+	o, ok := s.tracer.NewObserver().(*traceobserver.Observer)
+	c.Assert(ok, jc.IsTrue)
+	return o
+}
+
+func (s *tracerSuite) TestMetrics(c *gc.C) {
+	o := s.newObserver(c)
+	s.call(o, "FullStatus", time.Second, "")
+	s.call(o, "FullStatus", 2*time.Second, "")
+
+	metricFamilies, err := s.registry.Gather()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(metricFamilies, gc.HasLen, 3)
+	names := make([]string, len(metricFamilies))
+	for i, family := range metricFamilies {
+		names[i] = family.GetName()
+		c.Assert(family.Metric, gc.HasLen, 1)
+		labels := family.Metric[0].GetLabel()
+		c.Assert(labels, gc.HasLen, 2)
+		c.Check(labels[0].GetName(), gc.Equals, "facade")
+		c.Check(labels[0].GetValue(), gc.Equals, "Client")
+		c.Check(labels[1].GetName(), gc.Equals, "method")
+		c.Check(labels[1].GetValue(), gc.Equals, "FullStatus")
+		c.Check(family.Metric[0].GetHistogram().GetSampleCount(), gc.Equals, uint64(2))
+	}
+	c.Assert(names, jc.DeepEquals, []string{
+		"juju_api_trace_request_duration_seconds",
+		"juju_api_trace_request_size_bytes",
+		"juju_api_trace_response_size_bytes",
+	})
+	c.Assert(metricFamilies[0].Metric[0].GetHistogram().GetSampleSum(), gc.Equals, float64(3))
+	c.Assert(metricFamilies[1].Metric[0].GetHistogram().GetSampleSum(), gc.Equals, float64(200))
+	c.Assert(metricFamilies[2].Metric[0].GetHistogram().GetSampleSum(), gc.Equals, float64(4000))
+}
+
+func (s *tracerSuite) TestSlowCalls(c *gc.C) {
+	anonymous := s.newObserver(c)
+	s.call(anonymous, "Login", time.Second, "")
+
+	o := s.newObserver(c)
+	o.Login(names.NewUserTag("bob"), names.NewModelTag("deadbeef-0bad-400d-8000-4b1d0d06f00d"), false, "")
+	s.call(o, "FullStatus", 5*time.Second, "")
+	s.call(o, "AddMachines", 2*time.Second, "unauthorized access")
+
+	calls := s.tracer.SlowCalls()
+	c.Assert(calls, gc.HasLen, 2)
+	c.Check(calls[0].Method, gc.Equals, "FullStatus")
+	c.Check(calls[0].Caller, gc.Equals, "user-bob")
+	c.Check(calls[0].Start, gc.Equals, start.Add(time.Second))
+	c.Check(calls[0].Duration, gc.Equals, 5*time.Second)
+	c.Check(calls[0].RequestSize, gc.Equals, 100)
+	c.Check(calls[0].ResponseSize, gc.Equals, 2000)
+	c.Check(calls[1].Method, gc.Equals, "AddMachines")
+	c.Check(calls[1].ErrorCode, gc.Equals, "unauthorized access")
+
+	// A faster call does not displace the slowest calls.
+	s.call(o, "ModelInfo", 1500*time.Millisecond, "")
+	c.Assert(s.tracer.SlowCalls(), jc.DeepEquals, calls)
+}
+
+func (s *tracerSuite) TestSlowCallsExpire(c *gc.C) {
+	o := s.newObserver(c)
+	s.call(o, "FullStatus", 5*time.Second, "")
+	s.clock.Advance(30 * time.Minute)
+	s.call(o, "ModelInfo", time.Second, "")
+	c.Assert(s.tracer.SlowCalls(), gc.HasLen, 2)
+
+	s.clock.Advance(30 * time.Minute)
+	calls := s.tracer.SlowCalls()
+	c.Assert(calls, gc.HasLen, 1)
+	c.Assert(calls[0].Method, gc.Equals, "ModelInfo")
+
+	// Once the slow call has expired, faster calls are kept.
+	s.call(o, "Status", time.Millisecond, "")
+	c.Assert(s.tracer.SlowCalls(), gc.HasLen, 2)
+}
+
+func (s *tracerSuite) TestIntrospectionReport(c *gc.C) {
+	c.Assert(s.tracer.IntrospectionReport(), gc.Equals, ""+
+		"Slowest 2 calls in the last 1h0m0s\n"+
+		"\n"+
+		"No calls traced.\n")
+
+	o := s.newObserver(c)
+	o.Login(names.NewMachineTag("0"), names.NewModelTag("deadbeef-0bad-400d-8000-4b1d0d06f00d"), false, "")
+	s.call(o, "FullStatus", 5*time.Second, "not found")
+	c.Assert(s.tracer.IntrospectionReport(), gc.Matches, ""+
+		"Slowest 2 calls in the last 1h0m0s\n"+
+		"\n"+
+		"DURATION +FACADE +METHOD +CALLER +REQUEST-BYTES +RESPONSE-BYTES +ERROR-CODE +STARTED\n"+
+		`5s +Client\(1\) +FullStatus +machine-0 +100 +2000 +not found +2017-05-01T10:00:00Z\n`)
+}
+
+type fakePrometheusRegisterer struct {
+	prometheus.Registerer
+	testing.Stub
+}
+
+func (r *fakePrometheusRegisterer) Register(c prometheus.Collector) error {
+	r.MethodCall(r, "Register", c)
+	return r.NextErr()
+}
+
+func (r *fakePrometheusRegisterer) Unregister(c prometheus.Collector) bool {
+	return true
+}
@@ -25,6 +25,14 @@ const (
 	// ActionRunning is the status of an Action that has been started but
 	// not completed yet.
 	ActionRunning string = "running"
+
+	// ActionAborting is the status of a running Action that has been
+	// cancelled, but that the unit agent has not yet stopped.
+	ActionAborting string = "aborting"
+
+	// ActionTimedOut is the status of an Action that was stopped
+	// because it ran for longer than its timeout.
+	ActionTimedOut string = "timedout"
 )
 
 // Actions is a slice of Action for bulk requests.
@@ -38,6 +46,28 @@ type Action struct {
 	Receiver   string                 `json:"receiver"`
 	Name       string                 `json:"name"`
 	Parameters map[string]interface{} `json:"parameters,omitempty"`
+
+	// Timeout, if non-zero, holds how long the action may run before
+	// it is stopped. If it is zero, the charm's default for the action
+	// applies.
+	Timeout time.Duration `json:"timeout,omitempty"`
+
+	// StreamOutput, if true, has the unit agent log the output of
+	// the action as messages while it runs.
+	StreamOutput bool `json:"stream-output,omitempty"`
+
+	// Leader, if true, runs an action whose receiver is an
+	// application on the application's leader only.
+	Leader bool `json:"leader,omitempty"`
+
+	// BatchSize, if non-zero, limits how many units of an application
+	// receiver run the action at once.
+	BatchSize int `json:"batch-size,omitempty"`
+
+	// BatchPercentage, if non-zero, limits how many units of an
+	// application receiver run the action at once, as a percentage of
+	// the units that run it.
+	BatchPercentage int `json:"batch-percentage,omitempty"`
 }
 
 // ActionResults is a slice of ActionResult for bulk requests.
@@ -54,9 +84,78 @@ type ActionResult struct {
 	Status    string                 `json:"status,omitempty"`
 	Message   string                 `json:"message,omitempty"`
 	Output    map[string]interface{} `json:"output,omitempty"`
+	Log       []ActionMessage        `json:"log,omitempty"`
+	Operation string                 `json:"operation,omitempty"`
 	Error     *Error                 `json:"error,omitempty"`
 }
 
+// ActionMessage is a progress message logged by a running Action.
+type ActionMessage struct {
+	Sequence  int       `json:"seq"`
+	Timestamp time.Time `json:"timestamp"`
+	Message   string    `json:"message"`
+}
+
+// ActionMessageArgs holds a slice of ActionMessageArg for a bulk
+// action API call.
+type ActionMessageArgs struct {
+	Messages []ActionMessageArg `json:"messages"`
+}
+
+// ActionMessageArg holds a progress message to log to the Action with
+// the given tag.
+type ActionMessageArg struct {
+	ActionTag string `json:"action-tag"`
+	Message   string `json:"message"`
+}
+
+// OperationIds holds the ids of operations.
+type OperationIds struct {
+	Ids []string `json:"ids"`
+}
+
+// OperationResults holds the results of a bulk Operations call.
+type OperationResults struct {
+	Results []OperationResult `json:"results,omitempty"`
+}
+
+// OperationResult describes an action run on several units of an
+// application at once.
+type OperationResult struct {
+	Id          string    `json:"id"`
+	Application string    `json:"application,omitempty"`
+	ActionName  string    `json:"action-name,omitempty"`
+	Enqueued    time.Time `json:"enqueued,omitempty"`
+	BatchSize   int       `json:"batch-size,omitempty"`
+
+	// Status summarises the status of the operation's actions.
+	Status string `json:"status,omitempty"`
+
+	// Actions holds the operation's actions, one for each unit.
+	Actions []ActionResult `json:"actions,omitempty"`
+	Error   *Error         `json:"error,omitempty"`
+}
+
+// ActionPruneArgs holds the arguments for pruning a model's finished
+// actions.
+type ActionPruneArgs struct {
+	MaxHistoryTime time.Duration `json:"max-history-time"`
+	MaxHistoryMB   int           `json:"max-history-mb"`
+}
+
+// ActionQueryArgs holds the filters for listing a model's actions.
+// Empty fields match every action. After, if set, is the tag of the
+// last action of the previous page of results.
+type ActionQueryArgs struct {
+	Applications   []string  `json:"applications,omitempty"`
+	ActionNames    []string  `json:"action-names,omitempty"`
+	Statuses       []string  `json:"statuses,omitempty"`
+	EnqueuedAfter  time.Time `json:"enqueued-after,omitempty"`
+	EnqueuedBefore time.Time `json:"enqueued-before,omitempty"`
+	After          string    `json:"after,omitempty"`
+	Limit          int       `json:"limit,omitempty"`
+}
+
 // ActionsByReceivers wrap a slice of Actions for API calls.
 type ActionsByReceivers struct {
 	Actions []ActionsByReceiver `json:"actions,omitempty"`
@@ -94,6 +94,7 @@ const (
 	CodeDischargeRequired         = "macaroon discharge required"
 	CodeRedirect                  = "redirection required"
 	CodeRetry                     = "retry"
+	CodeRateLimited               = "rate limited"
 )
 
 // ErrCode returns the error code associated with
@@ -239,3 +240,10 @@ func IsMethodNotAllowed(err error) bool {
 func IsRedirect(err error) bool {
 	return ErrCode(err) == CodeRedirect
 }
+
+// IsCodeRateLimited reports whether the request was rejected because
+// the caller exceeded its API request rate limit. Such requests may
+// be retried after backing off.
+func IsCodeRateLimited(err error) bool {
+	return ErrCode(err) == CodeRateLimited
+}
@@ -0,0 +1,54 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package params
+
+import "time"
+
+// AuditLogQueryArgs holds the arguments for a call to the Query
+// method of the AuditLog facade. Empty fields do not restrict
+// the result.
+type AuditLogQueryArgs struct {
+	// ModelTag restricts the entries to those recorded against the
+	// identified model.
+	ModelTag string `json:"model-tag,omitempty"`
+
+	// OriginName restricts the entries to those triggered by the
+	// named origin, e.g. "user-bob".
+	OriginName string `json:"origin-name,omitempty"`
+
+	// Operation restricts the entries to those whose operation
+	// contains the given text.
+	Operation string `json:"operation,omitempty"`
+
+	// After restricts the entries to those recorded at or after
+	// the given time.
+	After *time.Time `json:"after,omitempty"`
+
+	// Before restricts the entries to those recorded before the
+	// given time.
+	Before *time.Time `json:"before,omitempty"`
+
+	// Limit is the maximum number of entries to return. When more
+	// entries match, the most recent are returned.
+	Limit int `json:"limit,omitempty"`
+}
+
+// AuditLogEntry holds a single audit entry as returned by the
+// AuditLog facade.
+type AuditLogEntry struct {
+	JujuServerVersion string                 `json:"juju-server-version"`
+	ModelTag          string                 `json:"model-tag"`
+	Timestamp         time.Time              `json:"timestamp"`
+	RemoteAddress     string                 `json:"remote-address"`
+	OriginType        string                 `json:"origin-type"`
+	OriginName        string                 `json:"origin-name"`
+	Operation         string                 `json:"operation"`
+	Data              map[string]interface{} `json:"data,omitempty"`
+}
+
+// AuditLogQueryResults holds the results of a call to the Query
+// method of the AuditLog facade, oldest first.
+type AuditLogQueryResults struct {
+	Entries []AuditLogEntry `json:"entries"`
+}
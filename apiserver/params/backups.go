@@ -12,6 +12,10 @@ import (
 // BackupsCreateArgs holds the args for the API Create method.
 type BackupsCreateArgs struct {
 	Notes string `json:"notes"`
+
+	// EncryptionKey, if set, is the public key or secret with which
+	// the backup archive is encrypted.
+	EncryptionKey string `json:"encryption-key,omitempty"`
 }
 
 // BackupsInfoArgs holds the args for the API Info method.
@@ -70,6 +74,8 @@ type BackupsMetadataResult struct {
 
 	CACert       string `json:"ca-cert"`
 	CAPrivateKey string `json:"ca-private-key"`
+
+	Encrypted bool `json:"encrypted,omitempty"`
 }
 
 // RestoreArgs Holds the backup file or id
@@ -24,6 +24,20 @@ type LogStreamRecord struct {
 	Location  string    `json:"lo"`
 	Level     string    `json:"lv"`
 	Message   string    `json:"msg"`
+
+	// Audit holds the details of the audit entry the record was
+	// derived from, if the stream is of audit entries.
+	Audit *LogStreamAuditInfo `json:"audit,omitempty"`
+}
+
+// LogStreamAuditInfo holds the audit specific details of a streamed
+// record.
+type LogStreamAuditInfo struct {
+	ID            string                 `json:"id,omitempty"`
+	OriginType    string                 `json:"origin-type"`
+	Operation     string                 `json:"operation"`
+	RemoteAddress string                 `json:"remote-address"`
+	Data          map[string]interface{} `json:"data,omitempty"`
 }
 
 // LogStreamConfig holds all the information necessary to open a
@@ -51,4 +65,8 @@ type LogStreamConfig struct {
 
 	// MaxLookbackRecords is the maximum number of log records to stream from the past.
 	MaxLookbackRecords int `schema:"maxlookbackrecords" url:"maxlookbackrecords,omitempty"`
+
+	// Audit indicates that the controller's audit entries should be
+	// streamed instead of log records.
+	Audit bool `schema:"audit" url:"audit,omitempty"`
 }
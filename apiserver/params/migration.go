@@ -79,6 +79,19 @@ type SerializedModel struct {
 	Resources []SerializedModelResource `json:"resources"`
 }
 
+// SerializedModelResult holds the result of serializing a model, or
+// an error.
+type SerializedModelResult struct {
+	Result SerializedModel `json:"result"`
+	Error  *Error          `json:"error,omitempty"`
+}
+
+// SerializedModelResults holds the results of serializing a number of
+// models.
+type SerializedModelResults struct {
+	Results []SerializedModelResult `json:"results"`
+}
+
 // SerializedModelTools holds the version and URI for a given tools
 // version.
 type SerializedModelTools struct {
@@ -222,6 +222,53 @@ type StatusHistoryResults struct {
 	Results []StatusHistoryResult `json:"results"`
 }
 
+// StatusHistoryQuery holds the parameters for a status history
+// query spanning the units and machines of a model.
+type StatusHistoryQuery struct {
+	Application string     `json:"application,omitempty"`
+	FromDate    *time.Time `json:"from-date,omitempty"`
+	ToDate      *time.Time `json:"to-date,omitempty"`
+	Exclude     []string   `json:"exclude,omitempty"`
+	// After, if set, is the id of the last entry of the previous
+	// page of results.
+	After string `json:"after,omitempty"`
+	// Limit is the most entries returned, not counting those from
+	// before FromDate; if zero, a default limit applies.
+	Limit int `json:"limit,omitempty"`
+	// Aggregate requests a summary of each entity's history
+	// instead of the entries themselves.
+	Aggregate bool `json:"aggregate,omitempty"`
+}
+
+// StatusHistoryEntry holds a status history entry for an entity.
+type StatusHistoryEntry struct {
+	Id     string    `json:"id"`
+	Entity string    `json:"entity"`
+	Kind   string    `json:"kind"`
+	Status string    `json:"status"`
+	Info   string    `json:"info"`
+	Since  time.Time `json:"since"`
+}
+
+// StatusHistoryAggregate summarises the status history of an entity.
+type StatusHistoryAggregate struct {
+	Entity           string                   `json:"entity"`
+	Kind             string                   `json:"kind"`
+	Durations        map[string]time.Duration `json:"durations"`
+	Transitions      int                      `json:"transitions"`
+	ErrorTransitions int                      `json:"error-transitions"`
+}
+
+// StatusHistoryQueryResult holds the result of a status history
+// query.
+type StatusHistoryQueryResult struct {
+	Entries    []StatusHistoryEntry     `json:"entries,omitempty"`
+	Aggregates []StatusHistoryAggregate `json:"aggregates,omitempty"`
+	// Next, if set, is the After value that returns the next page
+	// of entries.
+	Next string `json:"next,omitempty"`
+}
+
 // StatusHistoryPruneArgs holds arguments for status history
 // prunning process.
 type StatusHistoryPruneArgs struct {
@@ -0,0 +1,225 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package ratelimit limits the rate at which authenticated entities
+// may make API requests. Each entity has a token bucket for each
+// facade it calls; users and agents have separate limits, and
+// individual facades may have limits of their own.
+package ratelimit
+
+import (
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/rpc"
+)
+
+// sweepInterval is the interval at which idle buckets are discarded.
+const sweepInterval = time.Minute
+
+// Limit describes a token bucket rate limit.
+type Limit struct {
+	// Rate is the number of requests per second that may be made
+	// over time. If it is zero, requests are not limited.
+	Rate int
+
+	// Burst is the number of requests that may be made at once
+	// after a period of inactivity. If it is zero, it is the same
+	// as Rate.
+	Burst int
+}
+
+// Validate checks that the limit is valid.
+func (l Limit) Validate() error {
+	if l.Rate < 0 {
+		return errors.NotValidf("negative Rate")
+	}
+	if l.Burst < 0 {
+		return errors.NotValidf("negative Burst")
+	}
+	return nil
+}
+
+func (l Limit) burst() float64 {
+	if l.Burst == 0 {
+		return float64(l.Rate)
+	}
+	return float64(l.Burst)
+}
+
+// Config holds the configuration for a Limiter.
+type Config struct {
+	// Clock is the clock used to refill the token buckets.
+	Clock clock.Clock
+
+	// User is the limit applied to each user for each facade.
+	User Limit
+
+	// Agent is the limit applied to each machine and unit agent for
+	// each facade.
+	Agent Limit
+
+	// Facades holds limits that override User and Agent for
+	// requests to the named facades.
+	Facades map[string]Limit
+}
+
+// Validate checks that the configuration is valid.
+func (config Config) Validate() error {
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	if err := config.User.Validate(); err != nil {
+		return errors.Annotate(err, "User")
+	}
+	if err := config.Agent.Validate(); err != nil {
+		return errors.Annotate(err, "Agent")
+	}
+	for facade, limit := range config.Facades {
+		if err := limit.Validate(); err != nil {
+			return errors.Annotatef(err, "Facades[%q]", facade)
+		}
+	}
+	return nil
+}
+
+// Limiter holds the token buckets of all the entities making API
+// requests, so that an entity's limits are shared between all of its
+// API connections.
+type Limiter struct {
+	config Config
+
+	mu        sync.Mutex
+	buckets   map[bucketKey]*bucket
+	lastSweep time.Time
+}
+
+type bucketKey struct {
+	entity string
+	facade string
+}
+
+// NewLimiter returns a new Limiter with the given configuration.
+func NewLimiter(config Config) (*Limiter, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Annotate(err, "validating config")
+	}
+	return &Limiter{
+		config:    config,
+		buckets:   make(map[bucketKey]*bucket),
+		lastSweep: config.Clock.Now(),
+	}, nil
+}
+
+// ForEntity returns an rpc.RequestLimiter that limits the requests
+// made by the entity with the given tag, or nil if the entity's
+// requests are not limited.
+func (l *Limiter) ForEntity(tag names.Tag) rpc.RequestLimiter {
+	limit := l.config.Agent
+	if _, ok := tag.(names.UserTag); ok {
+		limit = l.config.User
+	}
+	if limit.Rate == 0 && len(l.config.Facades) == 0 {
+		return nil
+	}
+	return &entityLimiter{
+		limiter: l,
+		entity:  tag.String(),
+		limit:   limit,
+	}
+}
+
+// take takes a token from the bucket for the given key. If there is
+// none, it returns false and the time until one is available.
+func (l *Limiter) take(key bucketKey, limit Limit) (bool, time.Duration) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := l.config.Clock.Now()
+	if now.Sub(l.lastSweep) >= sweepInterval {
+		l.sweep(now)
+	}
+	b, ok := l.buckets[key]
+	if !ok {
+		b = &bucket{limit: limit, tokens: limit.burst(), updated: now}
+		l.buckets[key] = b
+	}
+	return b.take(now)
+}
+
+// sweep discards the buckets that have refilled, as they are
+// indistinguishable from new ones.
+func (l *Limiter) sweep(now time.Time) {
+	for key, b := range l.buckets {
+		if b.refill(now) {
+			delete(l.buckets, key)
+		}
+	}
+	l.lastSweep = now
+}
+
+type entityLimiter struct {
+	limiter *Limiter
+	entity  string
+	limit   Limit
+}
+
+// Allow is part of the rpc.RequestLimiter interface.
+func (e *entityLimiter) Allow(req rpc.Request) error {
+	limit, ok := e.limiter.config.Facades[req.Type]
+	if !ok {
+		limit = e.limit
+	}
+	if limit.Rate == 0 {
+		return nil
+	}
+	ok, wait := e.limiter.take(bucketKey{e.entity, req.Type}, limit)
+	if ok {
+		return nil
+	}
+	return common.RateLimitedError(fmt.Sprintf(
+		"%s exceeded its rate limit of %d requests per second to the %s facade, retry in %v",
+		e.entity, limit.Rate, req.Type, wait,
+	))
+}
+
+type bucket struct {
+	limit   Limit
+	tokens  float64
+	updated time.Time
+}
+
+// refill adds the tokens accumulated since the bucket was last
+// updated, and reports whether the bucket is full.
+func (b *bucket) refill(now time.Time) bool {
+	burst := b.limit.burst()
+	if elapsed := now.Sub(b.updated); elapsed > 0 {
+		b.tokens += elapsed.Seconds() * float64(b.limit.Rate)
+		b.updated = now
+	}
+	if b.tokens >= burst {
+		b.tokens = burst
+		return true
+	}
+	return false
+}
+
+// take takes a token from the bucket. If there is none, it returns
+// false and the time, rounded up to the millisecond, until one is
+// available.
+func (b *bucket) take(now time.Time) (bool, time.Duration) {
+	b.refill(now)
+	if b.tokens >= 1 {
+		b.tokens--
+		return true, 0
+	}
+	seconds := (1 - b.tokens) / float64(b.limit.Rate)
+	wait := time.Duration(seconds * float64(time.Second))
+	wait = (wait + time.Millisecond - 1) / time.Millisecond * time.Millisecond
+	return false, wait
+}
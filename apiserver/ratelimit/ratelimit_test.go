@@ -0,0 +1,178 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package ratelimit_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/ratelimit"
+	"github.com/juju/juju/rpc"
+)
+
+type limiterSuite struct {
+	testing.IsolationSuite
+	clock   *testing.Clock
+	limiter *ratelimit.Limiter
+}
+
+var _ = gc.Suite(&limiterSuite{})
+
+var (
+	fullStatus = rpc.Request{Type: "Client", Version: 1, Action: "FullStatus"}
+	setStatus  = rpc.Request{Type: "Uniter", Version: 4, Action: "SetStatus"}
+)
+
+func (s *limiterSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Now())
+	var err error
+	s.limiter, err = ratelimit.NewLimiter(ratelimit.Config{
+		Clock: s.clock,
+		User:  ratelimit.Limit{Rate: 2, Burst: 3},
+		Agent: ratelimit.Limit{Rate: 10},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *limiterSuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		config ratelimit.Config
+		err    string
+	}{{
+		config: ratelimit.Config{},
+		err:    "nil Clock not valid",
+	}, {
+		config: ratelimit.Config{Clock: s.clock, User: ratelimit.Limit{Rate: -1}},
+		err:    "User: negative Rate not valid",
+	}, {
+		config: ratelimit.Config{Clock: s.clock, Agent: ratelimit.Limit{Burst: -1}},
+		err:    "Agent: negative Burst not valid",
+	}, {
+		config: ratelimit.Config{Clock: s.clock, Facades: map[string]ratelimit.Limit{
+			"Client": {Rate: -1},
+		}},
+		err: `Facades\["Client"\]: negative Rate not valid`,
+	}} {
+		c.Logf("test %d", i)
+		c.Check(test.config.Validate(), gc.ErrorMatches, test.err)
+		_, err := ratelimit.NewLimiter(test.config)
+		c.Check(err, gc.ErrorMatches, "validating config: "+test.err)
+	}
+}
+
+func (s *limiterSuite) TestBurstThenRate(c *gc.C) {
+	limiter := s.limiter.ForEntity(names.NewUserTag("bob"))
+	for i := 0; i < 3; i++ {
+		c.Assert(limiter.Allow(fullStatus), jc.ErrorIsNil)
+	}
+	err := limiter.Allow(fullStatus)
+	c.Assert(err, jc.Satisfies, params.IsCodeRateLimited)
+	c.Assert(err, gc.ErrorMatches, "user-bob exceeded its rate limit of 2 requests per second to the Client facade, retry in 500ms")
+
+	s.clock.Advance(250 * time.Millisecond)
+	err = limiter.Allow(fullStatus)
+	c.Assert(err, gc.ErrorMatches, ".* retry in 250ms")
+
+	s.clock.Advance(250 * time.Millisecond)
+	c.Assert(limiter.Allow(fullStatus), jc.ErrorIsNil)
+	c.Assert(limiter.Allow(fullStatus), jc.Satisfies, params.IsCodeRateLimited)
+}
+
+func (s *limiterSuite) TestLimitsPerFacade(c *gc.C) {
+	limiter := s.limiter.ForEntity(names.NewUserTag("bob"))
+	for i := 0; i < 3; i++ {
+		c.Assert(limiter.Allow(fullStatus), jc.ErrorIsNil)
+	}
+	c.Assert(limiter.Allow(fullStatus), jc.Satisfies, params.IsCodeRateLimited)
+	c.Assert(limiter.Allow(setStatus), jc.ErrorIsNil)
+}
+
+func (s *limiterSuite) TestLimitsSharedByEntity(c *gc.C) {
+	first := s.limiter.ForEntity(names.NewUserTag("bob"))
+	second := s.limiter.ForEntity(names.NewUserTag("bob"))
+	other := s.limiter.ForEntity(names.NewUserTag("mary"))
+	for i := 0; i < 3; i++ {
+		c.Assert(first.Allow(fullStatus), jc.ErrorIsNil)
+	}
+	c.Assert(second.Allow(fullStatus), jc.Satisfies, params.IsCodeRateLimited)
+	c.Assert(other.Allow(fullStatus), jc.ErrorIsNil)
+}
+
+func (s *limiterSuite) TestAgentLimit(c *gc.C) {
+	limiter := s.limiter.ForEntity(names.NewUnitTag("mysql/0"))
+	// The burst defaults to the rate.
+	for i := 0; i < 10; i++ {
+		c.Assert(limiter.Allow(setStatus), jc.ErrorIsNil)
+	}
+	err := limiter.Allow(setStatus)
+	c.Assert(err, gc.ErrorMatches, "unit-mysql-0 exceeded its rate limit of 10 requests per second to the Uniter facade, retry in 100ms")
+}
+
+func (s *limiterSuite) TestNoLimit(c *gc.C) {
+	limiter, err := ratelimit.NewLimiter(ratelimit.Config{
+		Clock: s.clock,
+		Agent: ratelimit.Limit{Rate: 10},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(limiter.ForEntity(names.NewUserTag("bob")), gc.IsNil)
+	c.Assert(limiter.ForEntity(names.NewMachineTag("0")), gc.NotNil)
+}
+
+func (s *limiterSuite) TestFacadeLimit(c *gc.C) {
+	limiter, err := ratelimit.NewLimiter(ratelimit.Config{
+		Clock: s.clock,
+		User:  ratelimit.Limit{Rate: 2, Burst: 3},
+		Facades: map[string]ratelimit.Limit{
+			"Client": {Rate: 1},
+			"Uniter": {},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	bob := limiter.ForEntity(names.NewUserTag("bob"))
+	c.Assert(bob.Allow(fullStatus), jc.ErrorIsNil)
+	err = bob.Allow(fullStatus)
+	c.Assert(err, gc.ErrorMatches, "user-bob exceeded its rate limit of 1 requests per second to the Client facade, retry in 1s")
+
+	// A zero rate lifts the limit for the facade.
+	for i := 0; i < 10; i++ {
+		c.Assert(bob.Allow(setStatus), jc.ErrorIsNil)
+	}
+}
+
+func (s *limiterSuite) TestFacadeLimitWithoutEntityLimit(c *gc.C) {
+	limiter, err := ratelimit.NewLimiter(ratelimit.Config{
+		Clock: s.clock,
+		Facades: map[string]ratelimit.Limit{
+			"Client": {Rate: 1},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	agent := limiter.ForEntity(names.NewMachineTag("0"))
+	c.Assert(agent, gc.NotNil)
+	c.Assert(agent.Allow(fullStatus), jc.ErrorIsNil)
+	c.Assert(agent.Allow(fullStatus), jc.Satisfies, params.IsCodeRateLimited)
+	for i := 0; i < 10; i++ {
+		c.Assert(agent.Allow(setStatus), jc.ErrorIsNil)
+	}
+}
+
+func (s *limiterSuite) TestIdleBucketsRefill(c *gc.C) {
+	limiter := s.limiter.ForEntity(names.NewUserTag("bob"))
+	for i := 0; i < 3; i++ {
+		c.Assert(limiter.Allow(fullStatus), jc.ErrorIsNil)
+	}
+	s.clock.Advance(time.Hour)
+	for i := 0; i < 3; i++ {
+		c.Assert(limiter.Allow(fullStatus), jc.ErrorIsNil)
+	}
+	c.Assert(limiter.Allow(fullStatus), jc.Satisfies, params.IsCodeRateLimited)
+}
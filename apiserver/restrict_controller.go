@@ -15,6 +15,7 @@ import (
 // independently of individual models.
 var controllerFacadeNames = set.NewStrings(
 	"AllModelWatcher",
+	"AuditLog",
 	"Cloud",
 	"Controller",
 	"MigrationTarget",
@@ -17,6 +17,7 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/compression"
 	"github.com/juju/juju/rpc/rpcreflect"
 	"github.com/juju/juju/state"
 )
@@ -59,6 +60,10 @@ type apiHandler struct {
 	// serverHost is the host:port of the API server that the client
 	// connected to.
 	serverHost string
+
+	// compression, if not nil, compresses the connection's messages;
+	// it accepts compressed messages once the client has logged in.
+	compression *compression.Conn
 }
 
 var _ = (*apiHandler)(nil)
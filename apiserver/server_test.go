@@ -9,6 +9,7 @@ import (
 	"fmt"
 	"net"
 	"net/http"
+	"sync"
 	"time"
 
 	"github.com/gorilla/websocket"
@@ -38,6 +39,8 @@ import (
 	"github.com/juju/juju/network"
 	"github.com/juju/juju/permission"
 	"github.com/juju/juju/pubsub/centralhub"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/msgpackcodec"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/presence"
 	coretesting "github.com/juju/juju/testing"
@@ -268,8 +271,12 @@ func (s *serverSuite) assertAlive(c *gc.C, entity presence.Agent, expectAlive bo
 }
 
 func dialWebsocket(c *gc.C, addr, path string, tlsVersion uint16) (*websocket.Conn, error) {
-	url := fmt.Sprintf("wss://%s%s", addr, path)
 	requestHeader := http.Header{"Origin": {"http://localhost/"}}
+	return dialWebsocketWithHeader(c, addr, path, tlsVersion, requestHeader)
+}
+
+func dialWebsocketWithHeader(c *gc.C, addr, path string, tlsVersion uint16, requestHeader http.Header) (*websocket.Conn, error) {
+	url := fmt.Sprintf("wss://%s%s", addr, path)
 
 	pool := x509.NewCertPool()
 	xcert, err := cert.ParseCert(coretesting.CACert)
@@ -305,6 +312,96 @@ func (s *serverSuite) TestMinTLSVersion(c *gc.C) {
 	c.Assert(conn, gc.IsNil)
 }
 
+func (s *serverSuite) TestAPICodecNegotiation(c *gc.C) {
+	_, srv := newServer(c, s.State)
+	defer assertStop(c, srv)
+
+	// We have to use 'localhost' because that is what the TLS cert says.
+	addr := fmt.Sprintf("localhost:%d", srv.Addr().Port)
+
+	// Clients that do not ask for a codec get JSON.
+	conn, err := dialWebsocket(c, addr, "/api", 0)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conn.Subprotocol(), gc.Equals, "")
+	conn.Close()
+
+	conn, err = dialWebsocketWithHeader(c, addr, "/api", 0, http.Header{
+		"Origin":                 {"http://localhost/"},
+		"Sec-Websocket-Protocol": {"unknown", msgpackcodec.WebsocketSubprotocol},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conn.Subprotocol(), gc.Equals, msgpackcodec.WebsocketSubprotocol)
+
+	// The server speaks MessagePack on the connection.
+	client := rpc.NewConn(msgpackcodec.NewWebsocket(conn), observer.None())
+	client.Start()
+	defer client.Close()
+	var result params.LoginResult
+	err = client.Call(rpc.Request{Type: "Admin", Version: 3, Action: "Login"}, &params.LoginRequest{
+		AuthTag:     s.AdminUserTag(c).String(),
+		Credentials: "wrong password",
+	}, &result)
+	c.Assert(err, jc.DeepEquals, &rpc.RequestError{
+		Message: "invalid entity name or password",
+		Code:    "unauthorized access",
+	})
+}
+
+func (s *serverSuite) TestAPIWebsocketCompression(c *gc.C) {
+	machine, password := s.Factory.MakeMachineReturningPassword(
+		c, &factory.MachineParams{Nonce: "fake_nonce"})
+	for i, enabled := range []bool{false, true} {
+		c.Logf("test %d: compression enabled %v", i, enabled)
+		var counter compressionCounter
+		cfg := defaultServerConfig(c, s.State)
+		cfg.WebsocketCompression = enabled
+		cfg.CompressionCounter = &counter
+		info, srv := newServerWithConfig(c, s.State, cfg)
+
+		info.Tag = machine.Tag()
+		info.Password = password
+		info.Nonce = "fake_nonce"
+		info.ModelTag = s.State.ModelTag()
+		st, err := api.Open(info, fastDialOpts)
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = apimachiner.NewState(st).Machine(machine.MachineTag())
+		c.Assert(err, jc.ErrorIsNil)
+		st.Close()
+		assertStop(c, srv)
+
+		counter.mu.Lock()
+		sent, received := counter.sent, counter.received
+		counter.mu.Unlock()
+		if !enabled {
+			c.Check(sent, gc.Equals, 0)
+			c.Check(received, gc.Equals, 0)
+			continue
+		}
+		// The login request was sent uncompressed; its reply, and
+		// the machine request and its reply, were compressed.
+		c.Check(sent >= 2, jc.IsTrue)
+		c.Check(received >= 1, jc.IsTrue)
+	}
+}
+
+type compressionCounter struct {
+	mu       sync.Mutex
+	sent     int
+	received int
+}
+
+func (c *compressionCounter) Sent(uncompressed, compressed int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.sent++
+}
+
+func (c *compressionCounter) Received(uncompressed, compressed int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.received++
+}
+
 func (s *serverSuite) TestNonCompatiblePathsAre404(c *gc.C) {
 	// We expose the API at '/api', '/' (controller-only), and at '/ModelUUID/api'
 	// for the correct location, but other paths should fail.
@@ -29,6 +29,8 @@ var logger = loggo.GetLogger("juju.apiserver.uniter")
 
 func init() {
 	common.RegisterStandardFacade("Uniter", 4, NewUniterAPIV4)
+	common.RegisterStandardFacade("Uniter", 5, NewUniterAPIV5) // Adds WatchActionCancellations.
+	common.RegisterStandardFacade("Uniter", 6, NewUniterAPIV6) // Adds LogActionsMessages.
 }
 
 // UniterAPIV3 implements the API version 3, used by the uniter worker.
@@ -53,8 +55,38 @@ type UniterAPIV3 struct {
 	StorageAPI
 }
 
-// NewUniterAPIV4 creates a new instance of the Uniter API, version 3.
-func NewUniterAPIV4(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV3, error) {
+// UniterAPIV5 implements the API version 5, which lacks
+// LogActionsMessages.
+type UniterAPIV5 struct {
+	*UniterAPIV3
+}
+
+// UniterAPIV4 implements the API version 4, which also lacks
+// WatchActionCancellations.
+type UniterAPIV4 struct {
+	UniterAPIV5
+}
+
+// NewUniterAPIV4 creates a new instance of the Uniter API, version 4.
+func NewUniterAPIV4(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV4, error) {
+	api, err := NewUniterAPIV5(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV4{*api}, nil
+}
+
+// NewUniterAPIV5 creates a new instance of the Uniter API, version 5.
+func NewUniterAPIV5(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV5, error) {
+	api, err := NewUniterAPIV6(st, resources, authorizer)
+	if err != nil {
+		return nil, err
+	}
+	return &UniterAPIV5{api}, nil
+}
+
+// NewUniterAPIV6 creates a new instance of the Uniter API, version 6.
+func NewUniterAPIV6(st *state.State, resources facade.Resources, authorizer facade.Authorizer) (*UniterAPIV3, error) {
 	if !authorizer.AuthUnitAgent() {
 		return nil, common.ErrPerm
 	}
@@ -814,6 +846,53 @@ func (u *UniterAPIV3) WatchActionNotifications(args params.Entities) (params.Str
 	return common.WatchActionNotifications(args, canAccess, watchOne), nil
 }
 
+// WatchActionCancellations returns a StringsWatcher for observing the
+// ids of a unit's running actions whose cancellation has been
+// requested. See also state/unit.go Unit.WatchActionCancellations().
+func (u *UniterAPIV3) WatchActionCancellations(args params.Entities) (params.StringsWatchResults, error) {
+	result := params.StringsWatchResults{
+		Results: make([]params.StringsWatchResult, len(args.Entities)),
+	}
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.StringsWatchResults{}, err
+	}
+	for i, entity := range args.Entities {
+		tag, err := names.ParseUnitTag(entity.Tag)
+		if err != nil {
+			result.Results[i].Error = common.ServerError(common.ErrPerm)
+			continue
+		}
+		err = common.ErrPerm
+		if canAccess(tag) {
+			result.Results[i], err = u.watchOneActionCancellations(tag)
+		}
+		result.Results[i].Error = common.ServerError(err)
+	}
+	return result, nil
+}
+
+// WatchActionCancellations is not available in version 4 of the API.
+// The RPC machinery ignores methods that take two arguments, so this
+// hides the method promoted from later versions.
+func (*UniterAPIV4) WatchActionCancellations(_, _ struct{}) {}
+
+func (u *UniterAPIV3) watchOneActionCancellations(tag names.UnitTag) (params.StringsWatchResult, error) {
+	unit, err := u.getUnit(tag)
+	if err != nil {
+		return params.StringsWatchResult{}, err
+	}
+	watch := unit.WatchActionCancellations()
+	// Consume the initial event and forward it to the result.
+	if changes, ok := <-watch.Changes(); ok {
+		return params.StringsWatchResult{
+			StringsWatcherId: u.resources.Register(watch),
+			Changes:          changes,
+		}, nil
+	}
+	return params.StringsWatchResult{}, watcher.EnsureErr(watch)
+}
+
 // ConfigSettings returns the complete set of service charm config
 // settings available to each given unit.
 func (u *UniterAPIV3) ConfigSettings(args params.Entities) (params.ConfigSettingsResults, error) {
@@ -952,6 +1031,21 @@ func (u *UniterAPIV3) FinishActions(args params.ActionExecutionResults) (params.
 	return common.FinishActions(args, actionFn), nil
 }
 
+// LogActionsMessages is not available in version 5 of the API.
+func (*UniterAPIV5) LogActionsMessages(_, _ struct{}) {}
+
+// LogActionsMessages records the given progress messages against the
+// running Actions they are for.
+func (u *UniterAPIV3) LogActionsMessages(args params.ActionMessageArgs) (params.ErrorResults, error) {
+	canAccess, err := u.accessUnit()
+	if err != nil {
+		return params.ErrorResults{}, err
+	}
+
+	actionFn := common.AuthAndActionFromTagFn(canAccess, u.st.ActionByTag)
+	return common.LogActionsMessages(args, actionFn), nil
+}
+
 // RelationById returns information about all given relations,
 // specified by their ids, including their key and the local
 // endpoint.
@@ -5,6 +5,7 @@ package uniter_test
 
 import (
 	"fmt"
+	"reflect"
 	"time"
 
 	"github.com/juju/errors"
@@ -21,6 +22,7 @@ import (
 	"github.com/juju/juju/apiserver/uniter"
 	"github.com/juju/juju/juju/testing"
 	"github.com/juju/juju/network"
+	"github.com/juju/juju/rpc/rpcreflect"
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/multiwatcher"
 	statetesting "github.com/juju/juju/state/testing"
@@ -114,7 +116,7 @@ func (s *uniterSuite) SetUpTest(c *gc.C) {
 	s.resources = common.NewResources()
 	s.AddCleanup(func(_ *gc.C) { s.resources.StopAll() })
 
-	uniterAPIV3, err := uniter.NewUniterAPIV4(
+	uniterAPIV3, err := uniter.NewUniterAPIV6(
 		s.State,
 		s.resources,
 		s.authorizer,
@@ -126,11 +128,30 @@ func (s *uniterSuite) SetUpTest(c *gc.C) {
 func (s *uniterSuite) TestUniterFailsWithNonUnitAgentUser(c *gc.C) {
 	anAuthorizer := s.authorizer
 	anAuthorizer.Tag = names.NewMachineTag("9")
-	_, err := uniter.NewUniterAPIV4(s.State, s.resources, anAuthorizer)
+	_, err := uniter.NewUniterAPIV6(s.State, s.resources, anAuthorizer)
 	c.Assert(err, gc.NotNil)
 	c.Assert(err, gc.ErrorMatches, "permission denied")
 }
 
+func (s *uniterSuite) TestOlderVersionsLackNewerMethods(c *gc.C) {
+	v4, err := uniter.NewUniterAPIV4(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+	v5, err := uniter.NewUniterAPIV5(s.State, s.resources, s.authorizer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	hasMethod := func(api interface{}, name string) bool {
+		_, err := rpcreflect.ObjTypeOf(reflect.TypeOf(api)).Method(name)
+		return err == nil
+	}
+	c.Check(hasMethod(v4, "WatchActionNotifications"), jc.IsTrue)
+	c.Check(hasMethod(v4, "WatchActionCancellations"), jc.IsFalse)
+	c.Check(hasMethod(v4, "LogActionsMessages"), jc.IsFalse)
+	c.Check(hasMethod(v5, "WatchActionCancellations"), jc.IsTrue)
+	c.Check(hasMethod(v5, "LogActionsMessages"), jc.IsFalse)
+	c.Check(hasMethod(s.uniter, "WatchActionCancellations"), jc.IsTrue)
+	c.Check(hasMethod(s.uniter, "LogActionsMessages"), jc.IsTrue)
+}
+
 func (s *uniterSuite) TestSetStatus(c *gc.C) {
 	now := time.Now()
 	sInfo := status.StatusInfo{
@@ -600,7 +621,7 @@ func (s *uniterSuite) TestGetPrincipal(c *gc.C) {
 	// Now try as subordinate's agent.
 	subAuthorizer := s.authorizer
 	subAuthorizer.Tag = subordinate.Tag()
-	subUniter, err := uniter.NewUniterAPIV4(s.State, s.resources, subAuthorizer)
+	subUniter, err := uniter.NewUniterAPIV6(s.State, s.resources, subAuthorizer)
 	c.Assert(err, jc.ErrorIsNil)
 
 	result, err = subUniter.GetPrincipal(args)
@@ -982,6 +1003,45 @@ func (s *uniterSuite) TestWatchActionNotifications(c *gc.C) {
 	wc.AssertNoChange()
 }
 
+func (s *uniterSuite) TestWatchActionCancellations(c *gc.C) {
+	err := s.wordpressUnit.SetCharmURL(s.wpCharm.URL())
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Assert(s.resources.Count(), gc.Equals, 0)
+
+	args := params.Entities{Entities: []params.Entity{
+		{Tag: "unit-mysql-0"},
+		{Tag: "unit-wordpress-0"},
+		{Tag: "application-wordpress"},
+	}}
+	result, err := s.uniter.WatchActionCancellations(args)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, gc.DeepEquals, params.StringsWatchResults{
+		Results: []params.StringsWatchResult{
+			{Error: apiservertesting.ErrUnauthorized},
+			{StringsWatcherId: "1"},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	c.Assert(s.resources.Count(), gc.Equals, 1)
+	resource := s.resources.Get("1")
+	defer statetesting.AssertStop(c, resource)
+
+	wc := statetesting.NewStringsWatcherC(c, s.State, resource.(state.StringsWatcher))
+	wc.AssertNoChange()
+
+	action, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err = action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.wordpressUnit.CancelAction(action)
+	c.Assert(err, jc.ErrorIsNil)
+
+	wc.AssertChange(action.Id())
+	wc.AssertNoChange()
+}
+
 func (s *uniterSuite) TestWatchPreexistingActions(c *gc.C) {
 	err := s.wordpressUnit.SetCharmURL(s.wpCharm.URL())
 	c.Assert(err, jc.ErrorIsNil)
@@ -1216,7 +1276,7 @@ func (s *uniterSuite) TestActionsWrongUnit(c *gc.C) {
 	mysqlUnitAuthorizer := apiservertesting.FakeAuthorizer{
 		Tag: s.mysqlUnit.Tag(),
 	}
-	mysqlUnitFacade, err := uniter.NewUniterAPIV4(s.State, s.resources, mysqlUnitAuthorizer)
+	mysqlUnitFacade, err := uniter.NewUniterAPIV6(s.State, s.resources, mysqlUnitAuthorizer)
 	c.Assert(err, jc.ErrorIsNil)
 
 	action, err := s.wordpressUnit.AddAction("fakeaction", nil)
@@ -1352,6 +1412,36 @@ func (s *uniterSuite) TestFinishActionsAuthAccess(c *gc.C) {
 	}
 }
 
+func (s *uniterSuite) TestLogActionsMessages(c *gc.C) {
+	good, err := s.wordpressUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	good, err = good.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	bad, err := s.mysqlUnit.AddAction("fakeaction", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	res, err := s.uniter.LogActionsMessages(params.ActionMessageArgs{
+		Messages: []params.ActionMessageArg{
+			{ActionTag: good.ActionTag().String(), Message: "half done"},
+			{ActionTag: bad.ActionTag().String(), Message: "half done"},
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(res, gc.DeepEquals, params.ErrorResults{
+		Results: []params.ErrorResult{
+			{Error: nil},
+			{Error: apiservertesting.ErrUnauthorized},
+		},
+	})
+
+	action, err := s.State.Action(good.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	messages := action.Messages()
+	c.Assert(messages, gc.HasLen, 1)
+	c.Assert(messages[0].Message, gc.Equals, "half done")
+}
+
 func (s *uniterSuite) TestBeginActions(c *gc.C) {
 	ten_seconds_ago := time.Now().Add(-10 * time.Second)
 	good, err := s.wordpressUnit.AddAction("fakeaction", nil)
@@ -2282,7 +2372,7 @@ func (s *unitMetricBatchesSuite) SetUpTest(c *gc.C) {
 		Tag: s.meteredUnit.Tag(),
 	}
 	var err error
-	s.uniter, err = uniter.NewUniterAPIV4(
+	s.uniter, err = uniter.NewUniterAPIV6(
 		s.State,
 		s.resources,
 		meteredAuthorizer,
@@ -2552,7 +2642,7 @@ func (s *uniterNetworkConfigSuite) setupUniterAPIForUnit(c *gc.C, givenUnit *sta
 	}
 
 	var err error
-	s.base.uniter, err = uniter.NewUniterAPIV4(
+	s.base.uniter, err = uniter.NewUniterAPIV6(
 		s.base.State,
 		s.base.resources,
 		s.base.authorizer,
@@ -9,6 +9,10 @@ import (
 
 	"github.com/juju/juju/apiserver/common"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/compression"
+	"github.com/juju/juju/rpc/jsoncodec"
+	"github.com/juju/juju/rpc/msgpackcodec"
 )
 
 // Use a 64k frame size for the websockets while we need to deal
@@ -24,8 +28,22 @@ var websocketUpgrader = websocket.Upgrader{
 	WriteBufferSize: websocketFrameSize,
 }
 
+// apiWebsocketUpgrader is used for RPC API connections. It offers the
+// codecs other than JSON as websocket subprotocols; clients that do
+// not ask for one of them get JSON.
+var apiWebsocketUpgrader = websocket.Upgrader{
+	CheckOrigin:     websocketUpgrader.CheckOrigin,
+	ReadBufferSize:  websocketFrameSize,
+	WriteBufferSize: websocketFrameSize,
+	Subprotocols:    []string{msgpackcodec.WebsocketSubprotocol},
+}
+
 func websocketServer(w http.ResponseWriter, req *http.Request, handler func(ws *websocket.Conn)) {
-	conn, err := websocketUpgrader.Upgrade(w, req, nil)
+	upgradeWebsocket(&websocketUpgrader, w, req, nil, handler)
+}
+
+func upgradeWebsocket(upgrader *websocket.Upgrader, w http.ResponseWriter, req *http.Request, responseHeader http.Header, handler func(ws *websocket.Conn)) {
+	conn, err := upgrader.Upgrade(w, req, responseHeader)
 	if err != nil {
 		logger.Errorf("problem initiating websocket: %v", err)
 		return
@@ -33,6 +51,24 @@ func websocketServer(w http.ResponseWriter, req *http.Request, handler func(ws *
 	handler(conn)
 }
 
+// newRPCCodec returns an RPC codec for the given websocket connection,
+// using the codec negotiated as its subprotocol. If compress is true,
+// messages are compressed, and their sizes are reported to counter if
+// that is not nil; the returned compression.Conn must then be told
+// when the client has logged in. Otherwise it is nil.
+func newRPCCodec(conn *websocket.Conn, compress bool, counter compression.Counter) (rpc.Codec, *compression.Conn) {
+	var wsConn jsoncodec.WebsocketConn = conn
+	var compressed *compression.Conn
+	if compress {
+		compressed = compression.NewServerConn(conn, counter)
+		wsConn = compressed
+	}
+	if conn.Subprotocol() == msgpackcodec.WebsocketSubprotocol {
+		return msgpackcodec.NewWebsocket(wsConn), compressed
+	}
+	return jsoncodec.NewWebsocket(wsConn), compressed
+}
+
 // sendInitialErrorV0 writes out the error as a params.ErrorResult serialized
 // with JSON with a new line character at the end.
 //
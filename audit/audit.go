@@ -18,6 +18,10 @@ type AuditEntrySinkFn func(AuditEntry) error
 
 // AuditEntry represents an auditted event.
 type AuditEntry struct {
+	// ID uniquely identifies the entry once it has been recorded. It
+	// is empty for entries that have not been read back from the
+	// audit log.
+	ID string
 	// JujuServerVersion is the version of the jujud that recorded
 	// this AuditEntry.
 	JujuServerVersion version.Number
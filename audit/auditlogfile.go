@@ -4,37 +4,109 @@
 package audit
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils"
-	"gopkg.in/natefinch/lumberjack.v2"
+	"github.com/juju/utils/clock"
 )
 
 var logger = loggo.GetLogger("juju.audit")
 
+const (
+	// LogFileName is the name of the audit log file written in the
+	// log directory.
+	LogFileName = "audit.log"
+
+	// DefaultMaxSizeMB is the default size in megabytes at which the
+	// audit log file is rotated.
+	DefaultMaxSizeMB = 300
+
+	// DefaultMaxBackups is the default number of rotated audit log
+	// files which are kept.
+	DefaultMaxBackups = 10
+)
+
+// genesisHash is the previous hash recorded by the first entry of
+// an audit log hash chain.
+var genesisHash = strings.Repeat("0", sha256.Size*2)
+
+// LogFileConfig holds the configuration for an audit log file sink.
+type LogFileConfig struct {
+	// LogDir is the directory in which the audit log files are
+	// written.
+	LogDir string
+
+	// MaxSizeMB is the size in megabytes at which the audit log file
+	// is rotated. If zero, DefaultMaxSizeMB is used.
+	MaxSizeMB int
+
+	// RotateInterval is the age at which the audit log file is
+	// rotated, regardless of its size. If zero, the file is only
+	// rotated based on its size.
+	RotateInterval time.Duration
+
+	// MaxBackups is the number of compressed, rotated audit log
+	// files which are kept. If zero, DefaultMaxBackups is used.
+	MaxBackups int
+
+	// Clock is used to decide when to rotate the audit log file. If
+	// nil, the wall clock is used.
+	Clock clock.Clock
+}
+
 // NewLogFileSink returns an audit entry sink which writes
-// to an audit.log file in the specified directory.
-func NewLogFileSink(logDir string) AuditEntrySinkFn {
-	logPath := filepath.Join(logDir, "audit.log")
-	if err := primeLogFile(logPath); err != nil {
-		// This isn't a fatal error so log and continue if priming
-		// fails.
-		logger.Errorf("Unable to prime %s (proceeding anyway): %v", logPath, err)
+// to an audit.log file in the configured directory.
+//
+// Each line written records the hash of the line before it, so
+// that VerifyLogFiles can detect lines which were altered or
+// removed. The chain is continued across restarts and rotations.
+func NewLogFileSink(config LogFileConfig) AuditEntrySinkFn {
+	logPath := filepath.Join(config.LogDir, LogFileName)
+	maxSize := config.MaxSizeMB
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSizeMB
+	}
+	maxBackups := config.MaxBackups
+	if maxBackups <= 0 {
+		maxBackups = DefaultMaxBackups
+	}
+	clk := config.Clock
+	if clk == nil {
+		clk = clock.WallClock
+	}
+	fileLogger := &rotatingFile{
+		path:       logPath,
+		maxSize:    int64(maxSize) * 1024 * 1024,
+		maxAge:     config.RotateInterval,
+		maxBackups: maxBackups,
+		clock:      clk,
+	}
+	if err := fileLogger.open(); err != nil {
+		// Writing will try to open the file again, so log and
+		// continue.
+		logger.Errorf("Unable to open %s (proceeding anyway): %v", logPath, err)
 	}
 
+	lastHash, err := lastChainHash(logPath)
+	if err != nil {
+		// The next entry will start a new chain, which verification
+		// will report, so log and continue.
+		logger.Errorf("cannot read audit log hash chain (starting a new chain): %v", err)
+		lastHash = genesisHash
+	}
 	handler := &auditLogFileSink{
-		fileLogger: &lumberjack.Logger{
-			Filename:   logPath,
-			MaxSize:    300, // MB
-			MaxBackups: 10,
-		},
+		fileLogger: fileLogger,
+		lastHash:   lastHash,
 	}
 	return handler.handle
 }
@@ -54,11 +126,31 @@ func primeLogFile(path string) error {
 }
 
 type auditLogFileSink struct {
-	fileLogger io.WriteCloser
+	mu         sync.Mutex
+	fileLogger io.Writer
+	lastHash   string
 }
 
 func (a *auditLogFileSink) handle(entry AuditEntry) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	record := formatRecord(entry)
+	hash := chainHash(a.lastHash, record)
 	_, err := a.fileLogger.Write([]byte(strings.Join([]string{
+		record, a.lastHash, hash,
+	}, ",") + "\n"))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	a.lastHash = hash
+	return nil
+}
+
+// formatRecord returns the line recorded for an entry, without its
+// hashes.
+func formatRecord(entry AuditEntry) string {
+	record := strings.Join([]string{
 		entry.Timestamp.In(time.UTC).Format("2006-01-02 15:04:05"),
 		entry.ModelUUID,
 		entry.RemoteAddress,
@@ -66,6 +158,14 @@ func (a *auditLogFileSink) handle(entry AuditEntry) error {
 		entry.OriginType,
 		entry.Operation,
 		fmt.Sprintf("%v", entry.Data),
-	}, ",") + "\n"))
-	return err
+	}, ",")
+	// Each entry must be on a single line.
+	return strings.Replace(record, "\n", `\n`, -1)
+}
+
+// chainHash returns the hash of a record, chained to the hash of
+// the record before it.
+func chainHash(prevHash, record string) string {
+	sum := sha256.Sum256([]byte(prevHash + "," + record))
+	return hex.EncodeToString(sum[:])
 }
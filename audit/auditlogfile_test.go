@@ -4,10 +4,12 @@
 package audit_test
 
 import (
+	"compress/gzip"
 	"io/ioutil"
 	"os"
 	"path/filepath"
 	"runtime"
+	"strings"
 	"time"
 
 	"github.com/juju/testing"
@@ -24,9 +26,11 @@ type auditLogFileSuite struct {
 
 var _ = gc.Suite(&auditLogFileSuite{})
 
+const zeroHash = "0000000000000000000000000000000000000000000000000000000000000000"
+
 func (s *auditLogFileSuite) TestLogging(c *gc.C) {
 	dir := c.MkDir()
-	sink := audit.NewLogFileSink(dir)
+	sink := audit.NewLogFileSink(audit.LogFileConfig{LogDir: dir})
 
 	modelUUID := coretesting.ModelTag.Id()
 	t0 := time.Date(2015, time.June, 1, 23, 2, 1, 0, time.UTC)
@@ -56,9 +60,15 @@ func (s *auditLogFileSuite) TestLogging(c *gc.C) {
 	logPath := filepath.Join(dir, "audit.log")
 	logContents, err := ioutil.ReadFile(logPath)
 	c.Assert(err, jc.ErrorIsNil)
-	line0 := "2015-06-01 23:02:01," + modelUUID + ",10.0.0.1,user-admin,API,deploy,map[foo:bar]\n"
-	line1 := "2015-06-01 23:02:02," + modelUUID + ",10.0.0.2,user-admin,API,status,map[]\n"
-	c.Assert(string(logContents), gc.Equals, line0+line1)
+	lines := strings.Split(string(logContents), "\n")
+	c.Assert(lines, gc.HasLen, 3)
+	c.Assert(lines[2], gc.Equals, "")
+	record0 := "2015-06-01 23:02:01," + modelUUID + ",10.0.0.1,user-admin,API,deploy,map[foo:bar]"
+	record1 := "2015-06-01 23:02:02," + modelUUID + ",10.0.0.2,user-admin,API,status,map[]"
+	c.Assert(strings.HasPrefix(lines[0], record0+","+zeroHash+","), jc.IsTrue)
+	hash0 := strings.TrimPrefix(lines[0], record0+","+zeroHash+",")
+	c.Assert(hash0, gc.Matches, "[0-9a-f]{64}")
+	c.Assert(strings.HasPrefix(lines[1], record1+","+hash0+","), jc.IsTrue)
 
 	// Check the file mode is as expected. This doesn't work on
 	// Windows (but this code is very unlikely to run on Windows so
@@ -69,3 +79,118 @@ func (s *auditLogFileSuite) TestLogging(c *gc.C) {
 		c.Assert(info.Mode(), gc.Equals, os.FileMode(0600))
 	}
 }
+
+func (s *auditLogFileSuite) TestChainContinuesAfterRestart(c *gc.C) {
+	dir := c.MkDir()
+	writeEntries(c, audit.NewLogFileSink(audit.LogFileConfig{LogDir: dir}), 2)
+	writeEntries(c, audit.NewLogFileSink(audit.LogFileConfig{LogDir: dir}), 2)
+
+	paths, err := audit.LogFiles(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	result, err := audit.VerifyLogFiles(paths)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, audit.VerifyResult{Entries: 4})
+}
+
+func (s *auditLogFileSuite) TestRotation(c *gc.C) {
+	dir := c.MkDir()
+	clock := testing.NewClock(time.Date(2017, time.March, 1, 0, 0, 0, 0, time.UTC))
+	sink := audit.NewLogFileSink(audit.LogFileConfig{
+		LogDir:         dir,
+		RotateInterval: time.Hour,
+		MaxBackups:     2,
+		Clock:          clock,
+	})
+	for i := 0; i < 4; i++ {
+		writeEntries(c, sink, 2)
+		clock.Advance(time.Hour)
+	}
+	writeEntries(c, sink, 1)
+
+	paths, err := audit.LogFiles(dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(paths, jc.DeepEquals, []string{
+		filepath.Join(dir, "audit-2017-03-01T03-00-00.000.log.gz"),
+		filepath.Join(dir, "audit-2017-03-01T04-00-00.000.log.gz"),
+		filepath.Join(dir, "audit.log"),
+	})
+
+	// The backups are compressed.
+	f, err := os.Open(paths[0])
+	c.Assert(err, jc.ErrorIsNil)
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	c.Assert(err, jc.ErrorIsNil)
+	content, err := ioutil.ReadAll(zr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.Count(string(content), "\n"), gc.Equals, 2)
+
+	// The chain is unbroken across the retained files.
+	result, err := audit.VerifyLogFiles(paths)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, audit.VerifyResult{Entries: 5})
+}
+
+func (s *auditLogFileSuite) TestRotationKeepsEntryIfRenameFails(c *gc.C) {
+	dir := c.MkDir()
+	clock := testing.NewClock(time.Date(2017, time.March, 1, 0, 0, 0, 0, time.UTC))
+	sink := audit.NewLogFileSink(audit.LogFileConfig{
+		LogDir:         dir,
+		RotateInterval: time.Hour,
+		Clock:          clock,
+	})
+	writeEntries(c, sink, 1)
+	clock.Advance(time.Hour)
+
+	// A non-empty directory in the way of the backup stops the
+	// current file being moved aside.
+	blocker := filepath.Join(dir, "audit-2017-03-01T01-00-00.000.log")
+	err := os.MkdirAll(filepath.Join(blocker, "blocker"), 0700)
+	c.Assert(err, jc.ErrorIsNil)
+	writeEntries(c, sink, 1)
+
+	result, err := audit.VerifyLogFiles([]string{filepath.Join(dir, "audit.log")})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, audit.VerifyResult{Entries: 2})
+}
+
+func (s *auditLogFileSuite) TestRotationKeepsEntryIfPruningFails(c *gc.C) {
+	dir := c.MkDir()
+	// A non-empty directory named like the oldest backup cannot be
+	// removed.
+	oldest := filepath.Join(dir, "audit-2017-02-01T00-00-00.000.log")
+	err := os.MkdirAll(filepath.Join(oldest, "blocker"), 0700)
+	c.Assert(err, jc.ErrorIsNil)
+
+	clock := testing.NewClock(time.Date(2017, time.March, 1, 0, 0, 0, 0, time.UTC))
+	sink := audit.NewLogFileSink(audit.LogFileConfig{
+		LogDir:         dir,
+		RotateInterval: time.Hour,
+		MaxBackups:     1,
+		Clock:          clock,
+	})
+	writeEntries(c, sink, 2)
+	clock.Advance(time.Hour)
+	writeEntries(c, sink, 1)
+
+	content, err := ioutil.ReadFile(filepath.Join(dir, "audit.log"))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(strings.Count(string(content), "\n"), gc.Equals, 1)
+	_, err = os.Stat(filepath.Join(dir, "audit-2017-03-01T01-00-00.000.log.gz"))
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func writeEntries(c *gc.C, sink audit.AuditEntrySinkFn, n int) {
+	for i := 0; i < n; i++ {
+		err := sink(audit.AuditEntry{
+			Timestamp:     time.Date(2017, time.March, 1, 0, 0, i, 0, time.UTC),
+			ModelUUID:     coretesting.ModelTag.Id(),
+			RemoteAddress: "10.0.0.1",
+			OriginType:    "API",
+			OriginName:    "user-admin",
+			Operation:     "status",
+			Data:          map[string]interface{}{"line": "one\ntwo"},
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
@@ -0,0 +1,216 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package audit
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+)
+
+const (
+	// backupTimeFormat is the format of the timestamp embedded in
+	// the names of rotated audit log files. It sorts lexically.
+	backupTimeFormat = "2006-01-02T15-04-05.000"
+
+	compressSuffix = ".gz"
+)
+
+// rotatingFile is an io.Writer which writes to a file, moving it
+// aside once it grows beyond a maximum size or age. Rotated files
+// are compressed, and only the most recent maxBackups of them are
+// kept.
+type rotatingFile struct {
+	path       string
+	maxSize    int64
+	maxAge     time.Duration
+	maxBackups int
+	clock      clock.Clock
+
+	file   *os.File
+	size   int64
+	opened time.Time
+}
+
+// Write is part of io.Writer.
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	if r.file == nil {
+		if err := r.open(); err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+	if r.needsRotation(int64(len(p))) {
+		if err := r.rotate(); err != nil {
+			return 0, errors.Annotate(err, "rotating audit log")
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, errors.Trace(err)
+}
+
+// Close closes the current file.
+func (r *rotatingFile) Close() error {
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return errors.Trace(err)
+}
+
+func (r *rotatingFile) needsRotation(writeLen int64) bool {
+	if r.size == 0 {
+		return false
+	}
+	if r.maxSize > 0 && r.size+writeLen > r.maxSize {
+		return true
+	}
+	return r.maxAge > 0 && r.clock.Now().Sub(r.opened) >= r.maxAge
+}
+
+// open opens the current file for appending. An existing file is
+// considered to have been opened when it was last modified, as its
+// creation time is not available.
+func (r *rotatingFile) open() error {
+	if err := primeLogFile(r.path); err != nil {
+		// This isn't a fatal error so log and continue if priming
+		// fails.
+		logger.Errorf("Unable to prime %s (proceeding anyway): %v", r.path, err)
+	}
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return errors.Trace(err)
+	}
+	r.file = f
+	r.size = info.Size()
+	r.opened = r.clock.Now()
+	if r.size > 0 {
+		r.opened = info.ModTime()
+	}
+	return nil
+}
+
+// rotate moves the current file aside and opens a new one. Audit
+// entries must not be lost, so it only fails if no file can be opened
+// to write them to; if the current file cannot be moved aside, writing
+// carries on to it, and rotation is tried again on the next write.
+func (r *rotatingFile) rotate() error {
+	if err := r.Close(); err != nil {
+		logger.Errorf("cannot close %s: %v", r.path, err)
+	}
+	backupPath := backupName(r.path, r.clock.Now())
+	if err := os.Rename(r.path, backupPath); err != nil {
+		logger.Errorf("cannot rotate %s (continuing with it): %v", r.path, err)
+		return errors.Trace(r.open())
+	}
+	if err := r.open(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := compressFile(backupPath); err != nil {
+		// The uncompressed backup is still usable, so carry on.
+		logger.Errorf("cannot compress %s: %v", backupPath, err)
+	}
+	if err := r.removeOldBackups(); err != nil {
+		// Extra backups take space but lose nothing, so carry on.
+		logger.Errorf("cannot remove old backups of %s: %v", r.path, err)
+	}
+	return nil
+}
+
+func (r *rotatingFile) removeOldBackups() error {
+	if r.maxBackups <= 0 {
+		return nil
+	}
+	backups, err := backupFiles(r.path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(backups) <= r.maxBackups {
+		return nil
+	}
+	for _, path := range backups[:len(backups)-r.maxBackups] {
+		if err := os.Remove(path); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// backupName returns the name the file at path is given when it is
+// rotated at time t.
+func backupName(path string, t time.Time) string {
+	dir, base := filepath.Split(path)
+	ext := filepath.Ext(base)
+	prefix := strings.TrimSuffix(base, ext)
+	return filepath.Join(dir, prefix+"-"+t.UTC().Format(backupTimeFormat)+ext)
+}
+
+// backupFiles returns the rotated files of the file at path,
+// oldest first.
+func backupFiles(path string) ([]string, error) {
+	ext := filepath.Ext(path)
+	prefix := strings.TrimSuffix(path, ext) + "-"
+	matches, err := filepath.Glob(prefix + "*" + ext + "*")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var backups []string
+	for _, match := range matches {
+		stamp := strings.TrimSuffix(strings.TrimSuffix(match, compressSuffix), ext)
+		stamp = strings.TrimPrefix(stamp, prefix)
+		if _, err := time.Parse(backupTimeFormat, stamp); err != nil {
+			continue
+		}
+		backups = append(backups, match)
+	}
+	// The embedded timestamps sort lexically, and the compression
+	// suffix doesn't affect the order of distinct timestamps.
+	sort.Strings(backups)
+	return backups, nil
+}
+
+// compressFile replaces the file at path with a gzipped copy.
+func compressFile(path string) (err error) {
+	in, err := os.Open(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer in.Close()
+
+	gzPath := path + compressSuffix
+	out, err := os.OpenFile(gzPath, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		if err != nil {
+			out.Close()
+			os.Remove(gzPath)
+		}
+	}()
+	zw := gzip.NewWriter(out)
+	if _, err := io.Copy(zw, in); err != nil {
+		return errors.Trace(err)
+	}
+	if err := zw.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := out.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(os.Remove(path))
+}
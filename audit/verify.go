@@ -0,0 +1,208 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package audit
+
+import (
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// maxLineLength is the longest audit log line which can be read.
+const maxLineLength = 1024 * 1024
+
+// LogFiles returns the paths of the audit log files in the specified
+// directory, in the order in which they were written: rotated files
+// oldest first, followed by the current file.
+func LogFiles(logDir string) ([]string, error) {
+	logPath := filepath.Join(logDir, LogFileName)
+	paths, err := backupFiles(logPath)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := os.Stat(logPath); err == nil {
+		paths = append(paths, logPath)
+	} else if !os.IsNotExist(err) {
+		return nil, errors.Trace(err)
+	}
+	return paths, nil
+}
+
+// VerifyResult describes the audit log files checked by
+// VerifyLogFiles.
+type VerifyResult struct {
+	// Entries is the number of entries whose hashes were verified.
+	Entries int
+
+	// Unchained is the number of entries at the start of the files
+	// which were written before hash chaining was introduced, and
+	// so could not be verified.
+	Unchained int
+}
+
+// ChainError is returned by VerifyLogFiles when an audit log entry
+// doesn't match the hash chain, indicating that entries were altered
+// or removed.
+type ChainError struct {
+	// Path is the file containing the entry.
+	Path string
+
+	// Line is the line number of the entry within the file.
+	Line int
+
+	// Reason describes why the entry doesn't match.
+	Reason string
+}
+
+// Error is part of the error interface.
+func (e *ChainError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.Path, e.Line, e.Reason)
+}
+
+// IsChainError reports whether the cause of err is a *ChainError.
+func IsChainError(err error) bool {
+	_, ok := errors.Cause(err).(*ChainError)
+	return ok
+}
+
+// VerifyLogFiles checks that the entries in the specified audit log
+// files, which must be given in the order in which they were written,
+// form an unbroken hash chain. Compressed files are read
+// transparently.
+//
+// The first entry is trusted to follow on from whichever entries
+// were removed by rotation, but every later entry must record the
+// hash of the entry before it.
+func VerifyLogFiles(paths []string) (VerifyResult, error) {
+	var v verifier
+	for _, path := range paths {
+		if err := v.verifyFile(path); err != nil {
+			return v.result, errors.Trace(err)
+		}
+	}
+	return v.result, nil
+}
+
+type verifier struct {
+	result   VerifyResult
+	lastHash string
+}
+
+func (v *verifier) verifyFile(path string) error {
+	return errors.Trace(readLines(path, func(lineNum int, line string) error {
+		record, prevHash, hash, ok := parseLine(line)
+		if !ok {
+			if v.lastHash != "" {
+				return &ChainError{path, lineNum, "entry has no hash"}
+			}
+			v.result.Unchained++
+			return nil
+		}
+		if v.lastHash != "" && prevHash != v.lastHash {
+			return &ChainError{path, lineNum, "previous entry hash mismatch (entries removed or altered)"}
+		}
+		if chainHash(prevHash, record) != hash {
+			return &ChainError{path, lineNum, "entry hash mismatch (entry altered)"}
+		}
+		v.lastHash = hash
+		v.result.Entries++
+		return nil
+	}))
+}
+
+// lastChainHash returns the hash of the last entry written to the
+// audit log at logPath, looking in rotated files if the current one
+// is empty. If there are no entries, or the last one predates hash
+// chaining, the genesis hash is returned.
+func lastChainHash(logPath string) (string, error) {
+	paths, err := LogFiles(filepath.Dir(logPath))
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	for i := len(paths) - 1; i >= 0; i-- {
+		var lastLine string
+		err := readLines(paths[i], func(_ int, line string) error {
+			lastLine = line
+			return nil
+		})
+		if err != nil {
+			return "", errors.Trace(err)
+		}
+		if lastLine == "" {
+			continue
+		}
+		if _, _, hash, ok := parseLine(lastLine); ok {
+			return hash, nil
+		}
+		break
+	}
+	return genesisHash, nil
+}
+
+// parseLine splits an audit log line into the record and its hashes.
+// It returns false if the line doesn't carry hashes.
+func parseLine(line string) (record, prevHash, hash string, ok bool) {
+	parts := strings.Split(line, ",")
+	if len(parts) < 3 {
+		return "", "", "", false
+	}
+	n := len(parts)
+	prevHash, hash = parts[n-2], parts[n-1]
+	if !isHash(prevHash) || !isHash(hash) {
+		return "", "", "", false
+	}
+	return strings.Join(parts[:n-2], ","), prevHash, hash, true
+}
+
+func isHash(s string) bool {
+	if len(s) != len(genesisHash) {
+		return false
+	}
+	for _, r := range s {
+		if !strings.ContainsRune("0123456789abcdef", r) {
+			return false
+		}
+	}
+	return true
+}
+
+// readLines calls f with each non-empty line of the file at path,
+// decompressing it if it was compressed by rotation.
+func readLines(path string, f func(lineNum int, line string) error) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer file.Close()
+
+	var r io.Reader = file
+	if strings.HasSuffix(path, compressSuffix) {
+		zr, err := gzip.NewReader(file)
+		if err != nil {
+			return errors.Annotatef(err, "reading %s", path)
+		}
+		defer zr.Close()
+		r = zr
+	}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, maxLineLength)
+	lineNum := 0
+	for scanner.Scan() {
+		lineNum++
+		line := scanner.Text()
+		if line == "" {
+			continue
+		}
+		if err := f(lineNum, line); err != nil {
+			return err
+		}
+	}
+	return errors.Annotatef(scanner.Err(), "reading %s", path)
+}
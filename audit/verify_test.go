@@ -0,0 +1,88 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package audit_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/audit"
+)
+
+type verifySuite struct {
+	testing.IsolationSuite
+
+	dir   string
+	path  string
+	lines []string
+}
+
+var _ = gc.Suite(&verifySuite{})
+
+func (s *verifySuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+	s.path = filepath.Join(s.dir, "audit.log")
+	writeEntries(c, audit.NewLogFileSink(audit.LogFileConfig{LogDir: s.dir}), 3)
+	content, err := ioutil.ReadFile(s.path)
+	c.Assert(err, jc.ErrorIsNil)
+	s.lines = strings.SplitAfter(string(content), "\n")
+	c.Assert(s.lines, gc.HasLen, 4)
+}
+
+func (s *verifySuite) writeLines(c *gc.C, lines ...string) {
+	err := ioutil.WriteFile(s.path, []byte(strings.Join(lines, "")), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *verifySuite) TestValid(c *gc.C) {
+	result, err := audit.VerifyLogFiles([]string{s.path})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, audit.VerifyResult{Entries: 3})
+}
+
+func (s *verifySuite) TestAlteredEntry(c *gc.C) {
+	s.writeLines(c, s.lines[0], strings.Replace(s.lines[1], "status", "deploy", 1), s.lines[2])
+
+	result, err := audit.VerifyLogFiles([]string{s.path})
+	c.Assert(err, gc.ErrorMatches, `.*audit.log:2: entry hash mismatch \(entry altered\)`)
+	c.Assert(audit.IsChainError(err), jc.IsTrue)
+	c.Assert(result, jc.DeepEquals, audit.VerifyResult{Entries: 1})
+}
+
+func (s *verifySuite) TestRemovedEntry(c *gc.C) {
+	s.writeLines(c, s.lines[0], s.lines[2])
+
+	_, err := audit.VerifyLogFiles([]string{s.path})
+	c.Assert(err, gc.ErrorMatches, `.*audit.log:2: previous entry hash mismatch \(entries removed or altered\)`)
+	c.Assert(audit.IsChainError(err), jc.IsTrue)
+}
+
+func (s *verifySuite) TestRemovedHash(c *gc.C) {
+	line := s.lines[1]
+	s.writeLines(c, s.lines[0], line[:len(line)-66]+"\n", s.lines[2])
+
+	_, err := audit.VerifyLogFiles([]string{s.path})
+	c.Assert(err, gc.ErrorMatches, `.*audit.log:2: entry has no hash`)
+}
+
+func (s *verifySuite) TestUnchainedEntriesFirst(c *gc.C) {
+	legacy := "2015-06-01 23:02:01,model,10.0.0.1,user-admin,API,deploy,map[]\n"
+	s.writeLines(c, legacy, legacy, s.lines[0], s.lines[1], s.lines[2])
+
+	result, err := audit.VerifyLogFiles([]string{s.path})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result, jc.DeepEquals, audit.VerifyResult{Entries: 3, Unchained: 2})
+}
+
+func (s *verifySuite) TestMissingFile(c *gc.C) {
+	_, err := audit.VerifyLogFiles([]string{filepath.Join(s.dir, "nope.log")})
+	c.Assert(err, gc.ErrorMatches, `.*no such file or directory`)
+	c.Assert(audit.IsChainError(err), jc.IsFalse)
+}
@@ -49,6 +49,13 @@ type APIClient interface {
 	// the ActionReceiver if necessary.
 	Actions(params.Entities) (params.ActionResults, error)
 
+	// Operations fetches operations by id, with their actions.
+	Operations(params.OperationIds) (params.OperationResults, error)
+
+	// ListActions fetches the model's actions that match the given
+	// filters, most recently enqueued first.
+	ListActions(params.ActionQueryArgs) (params.ActionResults, error)
+
 	// FindActionTagsByPrefix takes a list of string prefixes and finds
 	// corresponding ActionTags that match that prefix.
 	FindActionTagsByPrefix(params.FindTags) (params.FindTagsResults, error)
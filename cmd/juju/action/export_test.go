@@ -4,6 +4,8 @@
 package action
 
 import (
+	"time"
+
 	"github.com/juju/cmd"
 	"gopkg.in/juju/names.v2"
 
@@ -15,6 +17,7 @@ import (
 var (
 	NewActionAPIClient = &newAPIClient
 	AddValueToMap      = addValueToMap
+	WatchPollInterval  = &watchPollInterval
 )
 
 type ShowOutputCommand struct {
@@ -29,8 +32,8 @@ type RunCommand struct {
 	*runCommand
 }
 
-func (c *RunCommand) UnitTag() names.UnitTag {
-	return c.unitTag
+func (c *RunCommand) ReceiverTag() names.Tag {
+	return c.receiverTag
 }
 
 func (c *RunCommand) ActionName() string {
@@ -41,6 +44,22 @@ func (c *RunCommand) ParseStrings() bool {
 	return c.parseStrings
 }
 
+func (c *RunCommand) Leader() bool {
+	return c.leader
+}
+
+func (c *RunCommand) BatchSize() int {
+	return c.batchSize
+}
+
+func (c *RunCommand) BatchPercentage() int {
+	return c.batchPercentage
+}
+
+func (c *RunCommand) Timeout() time.Duration {
+	return c.timeout
+}
+
 func (c *RunCommand) ParamsYAML() cmd.FileVar {
 	return c.paramsYAML
 }
@@ -67,6 +86,12 @@ func NewShowOutputCommandForTest(store jujuclient.ClientStore) (cmd.Command, *Sh
 	return modelcmd.Wrap(c), &ShowOutputCommand{c}
 }
 
+func NewShowOperationCommandForTest(store jujuclient.ClientStore) cmd.Command {
+	c := &showOperationCommand{}
+	c.SetClientStore(store)
+	return modelcmd.Wrap(c)
+}
+
 func NewStatusCommandForTest(store jujuclient.ClientStore) (cmd.Command, *StatusCommand) {
 	c := &statusCommand{}
 	c.SetClientStore(store)
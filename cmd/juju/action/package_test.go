@@ -127,6 +127,9 @@ type fakeAPIClient struct {
 	actionTagMatches   params.FindTagsResults
 	actionsByNames     params.ActionsByNames
 	charmActions       map[string]params.ActionSpec
+	operationResults   []params.OperationResult
+	operationIds       params.OperationIds
+	actionQuery        params.ActionQueryArgs
 	apiErr             error
 }
 
@@ -202,6 +205,16 @@ func (c *fakeAPIClient) Actions(args params.Entities) (params.ActionResults, err
 	}
 }
 
+func (c *fakeAPIClient) Operations(args params.OperationIds) (params.OperationResults, error) {
+	c.operationIds = args
+	return params.OperationResults{Results: c.operationResults}, c.apiErr
+}
+
+func (c *fakeAPIClient) ListActions(args params.ActionQueryArgs) (params.ActionResults, error) {
+	c.actionQuery = args
+	return params.ActionResults{Results: c.actionResults}, c.apiErr
+}
+
 func (c *fakeAPIClient) FindActionTagsByPrefix(arg params.FindTags) (params.FindTagsResults, error) {
 	return c.actionTagMatches, c.apiErr
 }
@@ -5,6 +5,7 @@ package action
 
 import (
 	"regexp"
+	"strconv"
 	"strings"
 	"time"
 
@@ -26,17 +27,23 @@ func NewRunCommand() cmd.Command {
 	return modelcmd.Wrap(&runCommand{})
 }
 
-// runCommand enqueues an Action for running on the given unit with given
-// params
+// runCommand enqueues an Action for running on the given unit, or on
+// the units of the given application, with given params
 type runCommand struct {
 	ActionCommandBase
-	unitTag      names.UnitTag
-	actionName   string
-	paramsYAML   cmd.FileVar
-	parseStrings bool
-	wait         waitFlag
-	out          cmd.Output
-	args         [][]string
+	receiverTag     names.Tag
+	actionName      string
+	paramsYAML      cmd.FileVar
+	parseStrings    bool
+	wait            waitFlag
+	timeout         time.Duration
+	streamOutput    bool
+	leader          bool
+	batch           string
+	batchSize       int
+	batchPercentage int
+	out             cmd.Output
+	args            [][]string
 }
 
 const runDoc = `
@@ -56,6 +63,23 @@ is a boolean true in YAML.
 If --params is passed, along with key.key...=value explicit arguments, the
 explicit arguments will override the parameter file.
 
+The --timeout flag limits how long the action may run once started; if
+it runs for longer, the unit agent kills it and its status becomes
+"timedout". Without --timeout, a "timeout" given for the action in the
+charm's actions.yaml applies.
+
+The --stream-output flag has the unit agent log each line of the action's
+output as a progress message while it runs, to be followed with
+'juju show-action-output --watch <ID>'.
+
+Giving an application instead of a unit queues the Action on each of the
+application's units at once, or on its leader only with --leader. The
+--batch flag limits how many of the units run the Action at once, given
+either as a number of units or as a percentage of them; each time a unit
+finishes the Action, the next unit is given it. An operation ID is
+returned for use with 'juju show-operation <ID>', which shows the status
+and results of the Action on each unit.
+
 Examples:
 
 $ juju run-action mysql/3 backup --wait
@@ -116,6 +140,26 @@ $ juju run-action sleeper/0 pause time=1000
 $ juju run-action sleeper/0 pause --string-args time=1000
 ...
 The value for the "time" param will be the string literal "1000".
+
+$ juju run-action mysql/3 backup --timeout 30m
+...
+The backup will be killed if it runs for longer than 30 minutes.
+
+$ juju run-action mysql/3 backup --stream-output
+$ juju show-action-output --watch <ID>
+...
+The output of the backup is shown as it runs.
+
+$ juju run-action mysql backup --batch 10%
+Operation queued with id: <ID>
+
+$ juju show-operation <ID>
+...
+The backup runs on one in ten of the mysql units at a time.
+
+$ juju run-action mysql backup --leader
+...
+The backup runs on the leader of the mysql application only.
 `
 
 // ActionNameRule describes the format an action name must match to be valid.
@@ -128,36 +172,51 @@ func (c *runCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(&c.paramsYAML, "params", "Path to yaml-formatted params file")
 	f.BoolVar(&c.parseStrings, "string-args", false, "Use raw string values of CLI args")
 	f.Var(&c.wait, "wait", "Wait for results, with optional timeout")
+	f.DurationVar(&c.timeout, "timeout", 0, "Kill the action if it runs for longer than this")
+	f.BoolVar(&c.streamOutput, "stream-output", false, "Log the output of the action as progress messages while it runs")
+	f.BoolVar(&c.leader, "leader", false, "Run the action on the application's leader only")
+	f.StringVar(&c.batch, "batch", "", "Run the action on at most this many of the application's units at once, or this percentage of them if it ends in %")
 }
 
 func (c *runCommand) Info() *cmd.Info {
 	return &cmd.Info{
 		Name:    "run-action",
-		Args:    "<unit> <action name> [key.key.key...=value]",
+		Args:    "<unit>|<application> <action name> [key.key.key...=value]",
 		Purpose: "Queue an action for execution.",
 		Doc:     runDoc,
 	}
 }
 
-// Init gets the unit tag, and checks for other correct args.
+// Init gets the unit or application tag, and checks for other correct
+// args.
 func (c *runCommand) Init(args []string) error {
+	if c.timeout < 0 {
+		return errors.New("timeout must not be negative")
+	}
 	switch len(args) {
 	case 0:
-		return errors.New("no unit specified")
+		return errors.New("no unit or application specified")
 	case 1:
 		return errors.New("no action specified")
 	default:
-		// Grab and verify the unit and action names.
-		unitName := args[0]
-		if !names.IsValidUnit(unitName) {
-			return errors.Errorf("invalid unit name %q", unitName)
+		// Grab and verify the receiver and action names.
+		receiverName := args[0]
+		switch {
+		case names.IsValidUnit(receiverName):
+			c.receiverTag = names.NewUnitTag(receiverName)
+		case names.IsValidApplication(receiverName):
+			c.receiverTag = names.NewApplicationTag(receiverName)
+		default:
+			return errors.Errorf("invalid unit or application name %q", receiverName)
 		}
 		ActionName := args[1]
 		if valid := ActionNameRule.MatchString(ActionName); !valid {
 			return errors.Errorf("invalid action name %q", ActionName)
 		}
-		c.unitTag = names.NewUnitTag(unitName)
 		c.actionName = ActionName
+		if err := c.initOperation(); err != nil {
+			return err
+		}
 		if len(args) == 2 {
 			return nil
 		}
@@ -182,6 +241,41 @@ func (c *runCommand) Init(args []string) error {
 	}
 }
 
+// initOperation checks the flags that apply when the action is run on
+// an application's units, and parses the batch size.
+func (c *runCommand) initOperation() error {
+	if c.receiverTag.Kind() != names.ApplicationTagKind {
+		if c.leader || c.batch != "" {
+			return errors.New("--leader and --batch need an application")
+		}
+		return nil
+	}
+	if c.wait.forever || c.wait.d > 0 {
+		return errors.New("--wait is not supported for an application; use juju show-operation")
+	}
+	if c.batch == "" {
+		return nil
+	}
+	if c.leader {
+		return errors.New("--batch cannot be used with --leader")
+	}
+	size := c.batch
+	percentage := strings.HasSuffix(size, "%")
+	if percentage {
+		size = strings.TrimSuffix(size, "%")
+	}
+	n, err := strconv.Atoi(size)
+	if err != nil || n <= 0 || (percentage && n > 100) {
+		return errors.Errorf("invalid batch size %q", c.batch)
+	}
+	if percentage {
+		c.batchPercentage = n
+	} else {
+		c.batchSize = n
+	}
+	return nil
+}
+
 func (c *runCommand) Run(ctx *cmd.Context) error {
 	api, err := c.NewActionAPIClient()
 	if err != nil {
@@ -244,9 +338,14 @@ func (c *runCommand) Run(ctx *cmd.Context) error {
 
 	actionParam := params.Actions{
 		Actions: []params.Action{{
-			Receiver:   c.unitTag.String(),
-			Name:       c.actionName,
-			Parameters: actionParams,
+			Receiver:        c.receiverTag.String(),
+			Name:            c.actionName,
+			Parameters:      actionParams,
+			Timeout:         c.timeout,
+			StreamOutput:    c.streamOutput,
+			Leader:          c.leader,
+			BatchSize:       c.batchSize,
+			BatchPercentage: c.batchPercentage,
 		}},
 	}
 
@@ -268,6 +367,11 @@ func (c *runCommand) Run(ctx *cmd.Context) error {
 		return errors.New("action failed to enqueue")
 	}
 
+	if result.Operation != "" {
+		output := map[string]string{"Operation queued with id": result.Operation}
+		return c.out.Write(ctx, output)
+	}
+
 	tag, err := names.ParseActionTag(result.Action.Tag)
 	if err != nil {
 		return err
@@ -7,6 +7,7 @@ import (
 	"bytes"
 	"errors"
 	"strings"
+	"time"
 	"unicode/utf8"
 
 	jc "github.com/juju/testing/checkers"
@@ -59,17 +60,21 @@ func (s *RunSuite) TestInit(c *gc.C) {
 	tests := []struct {
 		should               string
 		args                 []string
-		expectUnit           names.UnitTag
+		expectReceiver       names.Tag
 		expectAction         string
 		expectParamsYamlPath string
 		expectParseStrings   bool
 		expectKVArgs         [][]string
+		expectTimeout        time.Duration
+		expectLeader         bool
+		expectBatchSize      int
+		expectBatchPct       int
 		expectOutput         string
 		expectError          string
 	}{{
 		should:      "fail with missing args",
 		args:        []string{},
-		expectError: "no unit specified",
+		expectError: "no unit or application specified",
 	}, {
 		should:      "fail with no action specified",
 		args:        []string{validUnitId},
@@ -77,7 +82,7 @@ func (s *RunSuite) TestInit(c *gc.C) {
 	}, {
 		should:      "fail with invalid unit tag",
 		args:        []string{invalidUnitId, "valid-action-name"},
-		expectError: "invalid unit name \"something-strange-\"",
+		expectError: "invalid unit or application name \"something-strange-\"",
 	}, {
 		should:      "fail with invalid action name",
 		args:        []string{validUnitId, "BadName"},
@@ -95,33 +100,43 @@ func (s *RunSuite) TestInit(c *gc.C) {
 		args:        []string{validUnitId, "valid-action-name", "no-go?od=3"},
 		expectError: "key \"no-go\\?od\" must start and end with lowercase alphanumeric, and contain only lowercase alphanumeric and hyphens",
 	}, {
-		should:       "work with empty values",
-		args:         []string{validUnitId, "valid-action-name", "ok="},
-		expectUnit:   names.NewUnitTag(validUnitId),
-		expectAction: "valid-action-name",
-		expectKVArgs: [][]string{{"ok", ""}},
+		should:         "work with empty values",
+		args:           []string{validUnitId, "valid-action-name", "ok="},
+		expectReceiver: names.NewUnitTag(validUnitId),
+		expectAction:   "valid-action-name",
+		expectKVArgs:   [][]string{{"ok", ""}},
 	}, {
 		should:             "handle --parse-strings",
 		args:               []string{validUnitId, "valid-action-name", "--string-args"},
-		expectUnit:         names.NewUnitTag(validUnitId),
+		expectReceiver:     names.NewUnitTag(validUnitId),
 		expectAction:       "valid-action-name",
 		expectParseStrings: true,
 	}, {
 		// cf. worker/uniter/runner/jujuc/action-set_test.go per @fwereade
-		should:       "work with multiple '=' signs",
-		args:         []string{validUnitId, "valid-action-name", "ok=this=is=weird="},
-		expectUnit:   names.NewUnitTag(validUnitId),
-		expectAction: "valid-action-name",
-		expectKVArgs: [][]string{{"ok", "this=is=weird="}},
+		should:         "work with multiple '=' signs",
+		args:           []string{validUnitId, "valid-action-name", "ok=this=is=weird="},
+		expectReceiver: names.NewUnitTag(validUnitId),
+		expectAction:   "valid-action-name",
+		expectKVArgs:   [][]string{{"ok", "this=is=weird="}},
 	}, {
-		should:       "init properly with no params",
-		args:         []string{validUnitId, "valid-action-name"},
-		expectUnit:   names.NewUnitTag(validUnitId),
-		expectAction: "valid-action-name",
+		should:         "handle --timeout",
+		args:           []string{validUnitId, "valid-action-name", "--timeout", "90s"},
+		expectReceiver: names.NewUnitTag(validUnitId),
+		expectAction:   "valid-action-name",
+		expectTimeout:  90 * time.Second,
+	}, {
+		should:      "fail with negative --timeout",
+		args:        []string{validUnitId, "valid-action-name", "--timeout", "-1s"},
+		expectError: "timeout must not be negative",
+	}, {
+		should:         "init properly with no params",
+		args:           []string{validUnitId, "valid-action-name"},
+		expectReceiver: names.NewUnitTag(validUnitId),
+		expectAction:   "valid-action-name",
 	}, {
 		should:               "handle --params properly",
 		args:                 []string{validUnitId, "valid-action-name", "--params=foo.yml"},
-		expectUnit:           names.NewUnitTag(validUnitId),
+		expectReceiver:       names.NewUnitTag(validUnitId),
 		expectAction:         "valid-action-name",
 		expectParamsYamlPath: "foo.yml",
 	}, {
@@ -134,7 +149,7 @@ func (s *RunSuite) TestInit(c *gc.C) {
 			"foo.baz.bo=3",
 			"bar.foo=hello",
 		},
-		expectUnit:           names.NewUnitTag(validUnitId),
+		expectReceiver:       names.NewUnitTag(validUnitId),
 		expectAction:         "valid-action-name",
 		expectParamsYamlPath: "foo.yml",
 		expectKVArgs: [][]string{
@@ -151,13 +166,52 @@ func (s *RunSuite) TestInit(c *gc.C) {
 			"foo.baz.bo=y",
 			"bar.foo=hello",
 		},
-		expectUnit:   names.NewUnitTag(validUnitId),
-		expectAction: "valid-action-name",
+		expectReceiver: names.NewUnitTag(validUnitId),
+		expectAction:   "valid-action-name",
 		expectKVArgs: [][]string{
 			{"foo", "bar", "2"},
 			{"foo", "baz", "bo", "y"},
 			{"bar", "foo", "hello"},
 		},
+	}, {
+		should:         "init properly with an application",
+		args:           []string{"mysql", "valid-action-name"},
+		expectReceiver: names.NewApplicationTag("mysql"),
+		expectAction:   "valid-action-name",
+	}, {
+		should:         "handle --leader",
+		args:           []string{"mysql", "valid-action-name", "--leader"},
+		expectReceiver: names.NewApplicationTag("mysql"),
+		expectAction:   "valid-action-name",
+		expectLeader:   true,
+	}, {
+		should:          "handle --batch",
+		args:            []string{"mysql", "valid-action-name", "--batch", "5"},
+		expectReceiver:  names.NewApplicationTag("mysql"),
+		expectAction:    "valid-action-name",
+		expectBatchSize: 5,
+	}, {
+		should:         "handle --batch with a percentage",
+		args:           []string{"mysql", "valid-action-name", "--batch", "25%"},
+		expectReceiver: names.NewApplicationTag("mysql"),
+		expectAction:   "valid-action-name",
+		expectBatchPct: 25,
+	}, {
+		should:      "fail with an invalid --batch",
+		args:        []string{"mysql", "valid-action-name", "--batch", "150%"},
+		expectError: `invalid batch size "150%"`,
+	}, {
+		should:      "fail with --batch and --leader",
+		args:        []string{"mysql", "valid-action-name", "--batch", "2", "--leader"},
+		expectError: "--batch cannot be used with --leader",
+	}, {
+		should:      "fail with --leader for a unit",
+		args:        []string{validUnitId, "valid-action-name", "--leader"},
+		expectError: "--leader and --batch need an application",
+	}, {
+		should:      "fail with --wait for an application",
+		args:        []string{"mysql", "valid-action-name", "--wait"},
+		expectError: "--wait is not supported for an application; use juju show-operation",
 	}}
 
 	for i, t := range tests {
@@ -168,11 +222,15 @@ func (s *RunSuite) TestInit(c *gc.C) {
 			args := append([]string{modelFlag, "admin"}, t.args...)
 			err := testing.InitCommand(wrappedCommand, args)
 			if t.expectError == "" {
-				c.Check(command.UnitTag(), gc.Equals, t.expectUnit)
+				c.Check(command.ReceiverTag(), gc.Equals, t.expectReceiver)
 				c.Check(command.ActionName(), gc.Equals, t.expectAction)
 				c.Check(command.ParamsYAML().Path, gc.Equals, t.expectParamsYamlPath)
 				c.Check(command.Args(), jc.DeepEquals, t.expectKVArgs)
 				c.Check(command.ParseStrings(), gc.Equals, t.expectParseStrings)
+				c.Check(command.Timeout(), gc.Equals, t.expectTimeout)
+				c.Check(command.Leader(), gc.Equals, t.expectLeader)
+				c.Check(command.BatchSize(), gc.Equals, t.expectBatchSize)
+				c.Check(command.BatchPercentage(), gc.Equals, t.expectBatchPct)
 			} else {
 				c.Check(err, gc.ErrorMatches, t.expectError)
 			}
@@ -252,6 +310,30 @@ func (s *RunSuite) TestRun(c *gc.C) {
 			Parameters: map[string]interface{}{},
 			Receiver:   names.NewUnitTag(validUnitId).String(),
 		},
+	}, {
+		should:   "enqueue an action with a timeout",
+		withArgs: []string{validUnitId, "some-action", "--timeout", "10m"},
+		withActionResults: []params.ActionResult{{
+			Action: &params.Action{Tag: validActionTagString},
+		}},
+		expectedActionEnqueued: params.Action{
+			Name:       "some-action",
+			Parameters: map[string]interface{}{},
+			Receiver:   names.NewUnitTag(validUnitId).String(),
+			Timeout:    10 * time.Minute,
+		},
+	}, {
+		should:   "enqueue an action that streams its output",
+		withArgs: []string{validUnitId, "some-action", "--stream-output"},
+		withActionResults: []params.ActionResult{{
+			Action: &params.Action{Tag: validActionTagString},
+		}},
+		expectedActionEnqueued: params.Action{
+			Name:         "some-action",
+			Parameters:   map[string]interface{}{},
+			Receiver:     names.NewUnitTag(validUnitId).String(),
+			StreamOutput: true,
+		},
 	}, {
 		should: "enqueue an action with some explicit params",
 		withArgs: []string{validUnitId, "some-action",
@@ -399,3 +481,28 @@ func (s *RunSuite) TestRun(c *gc.C) {
 		}
 	}
 }
+
+func (s *RunSuite) TestRunApplication(c *gc.C) {
+	fakeClient := &fakeAPIClient{
+		actionResults: []params.ActionResult{{
+			Action:    &params.Action{Receiver: "application-mysql"},
+			Operation: "7",
+		}},
+	}
+	restore := s.patchAPIClient(fakeClient)
+	defer restore()
+
+	wrappedCommand, _ := action.NewRunCommandForTest(s.store)
+	ctx, err := testing.RunCommand(c, wrappedCommand, "-m", "admin", validServiceId, "some-action", "--batch", "10%")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, "Operation queued with id: \"7\"\n")
+
+	enqueued := fakeClient.EnqueuedActions()
+	c.Assert(enqueued.Actions, gc.HasLen, 1)
+	c.Check(enqueued.Actions[0], jc.DeepEquals, params.Action{
+		Name:            "some-action",
+		Parameters:      map[string]interface{}{},
+		Receiver:        names.NewApplicationTag(validServiceId).String(),
+		BatchPercentage: 10,
+	})
+}
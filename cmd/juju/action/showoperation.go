@@ -0,0 +1,160 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action
+
+import (
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+func NewShowOperationCommand() cmd.Command {
+	return modelcmd.Wrap(&showOperationCommand{})
+}
+
+// showOperationCommand shows the status and results of an operation by
+// ID.
+type showOperationCommand struct {
+	ActionCommandBase
+	out         cmd.Output
+	operationId string
+}
+
+const showOperationDoc = `
+Show the status and results of an operation with the given ID. An
+operation is an action queued on the units of an application at once,
+with 'juju run-action <application> <action name>'.
+
+The status of the operation summarises the status of its actions: it is
+"pending" until any unit starts the action, "running" until all the units
+have finished it, and then "completed" if the action completed on every
+unit, or "failed" otherwise. The status, message and results of the
+action on each unit are shown by unit.
+
+Examples:
+
+$ juju run-action mysql backup --batch 2
+Operation queued with id: "3"
+
+$ juju show-operation 3
+action: backup
+application: mysql
+batch-size: 2
+operation: "3"
+status: running
+summary:
+  completed: 1
+  pending: 1
+  running: 2
+units:
+  mysql/0:
+    id: <ID>
+    results:
+      file: /var/backups/mysql/dump.sql
+    status: completed
+  ...
+
+See also:
+    run-action
+    show-action-output
+`
+
+// SetFlags offers an option for YAML output.
+func (c *showOperationCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ActionCommandBase.SetFlags(f)
+	c.out.AddFlags(f, "yaml", output.DefaultFormatters)
+}
+
+func (c *showOperationCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "show-operation",
+		Args:    "<operation ID>",
+		Purpose: "Show the status and results of an operation by ID.",
+		Doc:     showOperationDoc,
+	}
+}
+
+// Init validates the operation ID.
+func (c *showOperationCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+		return errors.New("no operation ID specified")
+	case 1:
+		c.operationId = args[0]
+		return nil
+	default:
+		return cmd.CheckEmpty(args[1:])
+	}
+}
+
+// Run issues the API call to get the operation by ID.
+func (c *showOperationCommand) Run(ctx *cmd.Context) error {
+	api, err := c.NewActionAPIClient()
+	if err != nil {
+		return err
+	}
+	defer api.Close()
+
+	results, err := api.Operations(params.OperationIds{Ids: []string{c.operationId}})
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results.Results) != 1 {
+		return errors.Errorf("expected 1 result for operation %s, got %d", c.operationId, len(results.Results))
+	}
+	result := results.Results[0]
+	if result.Error != nil {
+		return result.Error
+	}
+	return c.out.Write(ctx, FormatOperationResult(result))
+}
+
+// FormatOperationResult inserts the values of the given OperationResult
+// in a map[string]interface{} for cmd.Output to write in an
+// easy-to-read format, with the action on each unit keyed by the unit's
+// name.
+func FormatOperationResult(result params.OperationResult) map[string]interface{} {
+	response := map[string]interface{}{
+		"operation":   result.Id,
+		"application": result.Application,
+		"action":      result.ActionName,
+		"status":      result.Status,
+	}
+	if result.BatchSize > 0 {
+		response["batch-size"] = result.BatchSize
+	}
+	summary := make(map[string]int)
+	units := make(map[string]interface{})
+	for _, action := range result.Actions {
+		if action.Error != nil {
+			summary["error"]++
+			continue
+		}
+		summary[action.Status]++
+		if action.Action == nil {
+			continue
+		}
+		unit := action.Action.Receiver
+		if tag, err := names.ParseUnitTag(unit); err == nil {
+			unit = tag.Id()
+		}
+		formatted := FormatActionResult(action)
+		if tag, err := names.ParseActionTag(action.Action.Tag); err == nil {
+			formatted["id"] = tag.Id()
+		}
+		units[unit] = formatted
+	}
+	if len(summary) > 0 {
+		response["summary"] = summary
+	}
+	if len(units) > 0 {
+		response["units"] = units
+	}
+	return response
+}
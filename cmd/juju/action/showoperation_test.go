@@ -0,0 +1,115 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package action_test
+
+import (
+	"errors"
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/common"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/action"
+	"github.com/juju/juju/testing"
+)
+
+type ShowOperationSuite struct {
+	BaseActionSuite
+}
+
+var _ = gc.Suite(&ShowOperationSuite{})
+
+func (s *ShowOperationSuite) TestInit(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		expectError string
+	}{{
+		args:        []string{},
+		expectError: "no operation ID specified",
+	}, {
+		args:        []string{"1", "2"},
+		expectError: `unrecognized args: \["2"\]`,
+	}, {
+		args: []string{"1"},
+	}} {
+		c.Logf("test %d: juju show-operation %v", i, test.args)
+		cmd := action.NewShowOperationCommandForTest(s.store)
+		args := append([]string{"-m", "admin"}, test.args...)
+		err := testing.InitCommand(cmd, args)
+		if test.expectError == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.expectError)
+		}
+	}
+}
+
+func (s *ShowOperationSuite) TestRun(c *gc.C) {
+	completed := time.Date(2015, time.February, 14, 8, 15, 0, 0, time.UTC)
+	client := &fakeAPIClient{
+		operationResults: []params.OperationResult{{
+			Id:          "3",
+			Application: "mysql",
+			ActionName:  "backup",
+			BatchSize:   1,
+			Status:      params.ActionRunning,
+			Actions: []params.ActionResult{{
+				Action: &params.Action{
+					Tag:      validActionTagString,
+					Receiver: "unit-mysql-0",
+				},
+				Status:    params.ActionCompleted,
+				Output:    map[string]interface{}{"file": "dump.sql"},
+				Completed: completed,
+			}, {
+				Action: &params.Action{
+					Receiver: "unit-mysql-1",
+				},
+				Status: params.ActionPending,
+			}},
+		}},
+	}
+	restore := s.patchAPIClient(client)
+	defer restore()
+
+	ctx, err := testing.RunCommand(c, action.NewShowOperationCommandForTest(s.store), "-m", "admin", "3")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(client.operationIds, jc.DeepEquals, params.OperationIds{Ids: []string{"3"}})
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+action: backup
+application: mysql
+batch-size: 1
+operation: "3"
+status: running
+summary:
+  completed: 1
+  pending: 1
+units:
+  mysql/0:
+    id: `[1:]+validActionId+`
+    results:
+      file: dump.sql
+    status: completed
+    timing:
+      completed: `+completed.String()+`
+  mysql/1:
+    status: pending
+`)
+}
+
+func (s *ShowOperationSuite) TestRunError(c *gc.C) {
+	client := &fakeAPIClient{
+		operationResults: []params.OperationResult{{
+			Id:    "42",
+			Error: common.ServerError(errors.New(`operation "42" not found`)),
+		}},
+	}
+	restore := s.patchAPIClient(client)
+	defer restore()
+
+	_, err := testing.RunCommand(c, action.NewShowOperationCommandForTest(s.store), "-m", "admin", "42")
+	c.Assert(err, gc.ErrorMatches, `operation "42" not found`)
+}
@@ -4,6 +4,7 @@
 package action
 
 import (
+	"fmt"
 	"regexp"
 	"time"
 
@@ -27,6 +28,7 @@ type showOutputCommand struct {
 	requestedId string
 	fullSchema  bool
 	wait        string
+	watch       bool
 }
 
 const showOutputDoc = `
@@ -38,6 +40,15 @@ to wait indefinitely.  If units are left off, seconds are assumed.
 The default behavior without --wait is to immediately check and return; if
 the results are "pending" then only the available information will be
 displayed.  This is also the behavior when any negative time is given.
+
+The --watch flag shows the progress messages of the action as they are
+logged, on standard error, until the action finishes; the results are then
+shown as usual. Actions log progress messages with the action-log hook tool,
+and also log their output if they were queued with run-action
+--stream-output. With --watch, --wait limits how long to watch for; without
+it, the action is watched until it finishes.
+
+An action that ran for longer than its timeout has the status "timedout".
 `
 
 // Set up the output.
@@ -45,6 +56,7 @@ func (c *showOutputCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ActionCommandBase.SetFlags(f)
 	c.out.AddFlags(f, "yaml", output.DefaultFormatters)
 	f.StringVar(&c.wait, "wait", "-1s", "Wait for results")
+	f.BoolVar(&c.watch, "watch", false, "Show progress messages as they are logged, until the action finishes")
 }
 
 func (c *showOutputCommand) Info() *cmd.Info {
@@ -90,6 +102,11 @@ func (c *showOutputCommand) Run(ctx *cmd.Context) error {
 	}
 	defer api.Close()
 
+	if c.watch && waitDur < 0 {
+		// Watching without a limit lasts until the action finishes.
+		waitDur = 0
+	}
+
 	wait := time.NewTimer(0 * time.Second)
 
 	switch {
@@ -104,7 +121,12 @@ func (c *showOutputCommand) Run(ctx *cmd.Context) error {
 		wait = time.NewTimer(waitDur)
 	}
 
-	result, err := GetActionResult(api, c.requestedId, wait)
+	var result params.ActionResult
+	if c.watch {
+		result, err = watchActionResult(ctx, api, c.requestedId, wait)
+	} else {
+		result, err = GetActionResult(api, c.requestedId, wait)
+	}
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -144,7 +166,7 @@ func timerLoop(api APIClient, requestedId string, wait, tick *time.Timer) (param
 		// Whether or not we're waiting for a result, if a completed
 		// result arrives, we're done.
 		switch result.Status {
-		case params.ActionRunning, params.ActionPending:
+		case params.ActionRunning, params.ActionPending, params.ActionAborting:
 		default:
 			return result, nil
 		}
@@ -160,6 +182,54 @@ func timerLoop(api APIClient, requestedId string, wait, tick *time.Timer) (param
 	}
 }
 
+// watchPollInterval is how often watchActionResult queries the API.
+var watchPollInterval = time.Second
+
+// watchActionResult repeatedly fetches an action, writing any new
+// progress messages to the context's stderr, until the action is
+// finished or "wait" times out. It returns the latest action status.
+func watchActionResult(ctx *cmd.Context, api APIClient, requestedId string, wait *time.Timer) (params.ActionResult, error) {
+	lastSeen := 0
+	for {
+		result, err := fetchResult(api, requestedId)
+		if err != nil {
+			return result, err
+		}
+		for _, message := range unseenMessages(result.Log, lastSeen) {
+			fmt.Fprintln(ctx.Stderr, formatActionMessage(message))
+			lastSeen = message.Sequence
+		}
+
+		switch result.Status {
+		case params.ActionRunning, params.ActionPending, params.ActionAborting:
+		default:
+			return result, nil
+		}
+
+		select {
+		case <-wait.C:
+			return result, nil
+		case <-time.After(watchPollInterval):
+		}
+	}
+}
+
+// unseenMessages returns the messages logged after the one numbered
+// lastSeen. Messages are numbered in the order they were logged, so
+// this holds even when older messages have since been dropped.
+func unseenMessages(log []params.ActionMessage, lastSeen int) []params.ActionMessage {
+	for i, message := range log {
+		if message.Sequence > lastSeen {
+			return log[i:]
+		}
+	}
+	return nil
+}
+
+func formatActionMessage(message params.ActionMessage) string {
+	return fmt.Sprintf("%s %s", message.Timestamp.UTC().Format(time.RFC3339), message.Message)
+}
+
 // fetchResult queries the given API for the given Action ID prefix, and
 // makes sure the results are acceptable, returning an error if they are not.
 func fetchResult(api APIClient, requestedId string) (params.ActionResult, error) {
@@ -204,6 +274,16 @@ func FormatActionResult(result params.ActionResult) map[string]interface{} {
 	if len(result.Output) != 0 {
 		response["results"] = result.Output
 	}
+	if result.Action != nil && result.Action.Timeout > 0 {
+		response["timeout"] = result.Action.Timeout.String()
+	}
+	if len(result.Log) != 0 {
+		log := make([]string, len(result.Log))
+		for i, message := range result.Log {
+			log[i] = formatActionMessage(message)
+		}
+		response["log"] = log
+	}
 
 	if result.Enqueued.IsZero() && result.Started.IsZero() && result.Completed.IsZero() {
 		return response
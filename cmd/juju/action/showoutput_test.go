@@ -290,6 +290,87 @@ timing:
 	}
 }
 
+// sequenceAPIClient returns each of its results in turn from Actions,
+// and then the last one for ever.
+type sequenceAPIClient struct {
+	*fakeAPIClient
+	results []params.ActionResult
+}
+
+func (c *sequenceAPIClient) Actions(args params.Entities) (params.ActionResults, error) {
+	result := c.results[0]
+	if len(c.results) > 1 {
+		c.results = c.results[1:]
+	}
+	return params.ActionResults{Results: []params.ActionResult{result}}, nil
+}
+
+func (s *ShowOutputSuite) TestWatch(c *gc.C) {
+	s.PatchValue(action.WatchPollInterval, time.Millisecond)
+	first := params.ActionMessage{
+		Sequence:  1,
+		Timestamp: time.Date(2015, time.February, 14, 8, 14, 0, 0, time.UTC),
+		Message:   "dumping tables",
+	}
+	second := params.ActionMessage{
+		Sequence:  2,
+		Timestamp: time.Date(2015, time.February, 14, 8, 14, 30, 0, time.UTC),
+		Message:   "compressing",
+	}
+	// A message may repeat one logged in the same second; only its
+	// number tells them apart.
+	third := params.ActionMessage{
+		Sequence:  3,
+		Timestamp: time.Date(2015, time.February, 14, 8, 14, 30, 0, time.UTC),
+		Message:   "compressing",
+	}
+	client := &sequenceAPIClient{
+		fakeAPIClient: &fakeAPIClient{
+			actionTagMatches: tagsForIdPrefix(validActionId, validActionTagString),
+		},
+		results: []params.ActionResult{{
+			Status: params.ActionPending,
+		}, {
+			Status: params.ActionRunning,
+			Log:    []params.ActionMessage{first},
+		}, {
+			Status: params.ActionRunning,
+			Log:    []params.ActionMessage{first},
+		}, {
+			Status: params.ActionRunning,
+			Log:    []params.ActionMessage{first, second},
+		}, {
+			// The oldest message has been dropped.
+			Status: params.ActionRunning,
+			Log:    []params.ActionMessage{second, third},
+		}, {
+			Status:    params.ActionCompleted,
+			Log:       []params.ActionMessage{second, third},
+			Completed: time.Date(2015, time.February, 14, 8, 15, 30, 0, time.UTC),
+		}},
+	}
+	s.PatchValue(action.NewActionAPIClient, func(*action.ActionCommandBase) (action.APIClient, error) {
+		return client, nil
+	})
+
+	cmd, _ := action.NewShowOutputCommandForTest(s.store)
+	ctx, err := testing.RunCommand(c, cmd, "-m", "admin", validActionId, "--watch")
+	c.Assert(err, gc.IsNil)
+	c.Check(testing.Stderr(ctx), gc.Equals, `
+2015-02-14T08:14:00Z dumping tables
+2015-02-14T08:14:30Z compressing
+2015-02-14T08:14:30Z compressing
+`[1:])
+	c.Check(testing.Stdout(ctx), gc.Equals, `
+log:
+- 2015-02-14T08:14:30Z compressing
+- 2015-02-14T08:14:30Z compressing
+status: completed
+timing:
+  completed: 2015-02-14 08:15:30 +0000 UTC
+`[1:])
+}
+
 func testRunHelper(c *gc.C, s *ShowOutputSuite, client *fakeAPIClient, expectedErr, expectedOutput, wait, query, modelFlag string) {
 	unpatch := s.BaseActionSuite.patchAPIClient(client)
 	defer unpatch()
@@ -4,6 +4,8 @@
 package action
 
 import (
+	"time"
+
 	"github.com/juju/cmd"
 	errors "github.com/juju/errors"
 	"github.com/juju/gnuflag"
@@ -21,21 +23,65 @@ func NewStatusCommand() cmd.Command {
 // statusCommand shows the status of an Action by ID.
 type statusCommand struct {
 	ActionCommandBase
-	out         cmd.Output
-	requestedId string
-	name        string
+	out          cmd.Output
+	requestedId  string
+	name         string
+	applications []string
+	statuses     []string
+	since        time.Duration
+	limit        int
+	after        string
 }
 
 const statusDoc = `
 Show the status of Actions matching given ID, partial ID prefix, or all Actions if no ID is supplied.
 If --name <name> is provided the search will be done by name rather than by ID.
+
+An Action's status is one of "pending", "running", "aborting",
+"completed", "failed", "cancelled" or "timedout". An Action is
+"aborting" when it was cancelled while running and the unit agent is
+stopping it, and "timedout" when it was killed because it ran for
+longer than its timeout.
+
+The --application, --status, --since and --limit options filter all of
+the model's Actions instead, most recently enqueued first; they may be
+combined with each other and with --name. At most 100 Actions are shown
+unless --limit asks for more, up to 1000; to see the next page, pass the
+ID of the last Action shown to --after. Finished Actions are pruned
+according to the actions-max-age and actions-max-size model config.
+
+Examples:
+
+    juju show-action-status --application mysql --status failed
+    juju show-action-status --name backup --since 24h --limit 10
+    juju show-action-status --status completed --after 7a5a9b12-c4e0-4c5e-8f3a-3c6a1b8f6c2d
 `
 
+// defaultActionPageSize is the number of Actions the controller lists
+// when --limit is not given.
+const defaultActionPageSize = 100
+
+// validActionStatuses holds the statuses that --status accepts.
+var validActionStatuses = []string{
+	params.ActionPending,
+	params.ActionRunning,
+	params.ActionAborting,
+	params.ActionCompleted,
+	params.ActionFailed,
+	params.ActionCancelled,
+	params.ActionTimedOut,
+}
+
 // Set up the output.
 func (c *statusCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ActionCommandBase.SetFlags(f)
 	c.out.AddFlags(f, "yaml", output.DefaultFormatters)
 	f.StringVar(&c.name, "name", "", "Action name")
+	f.Var(cmd.NewStringsValue(nil, &c.applications), "application", "Only show Actions run on these applications' units")
+	f.Var(cmd.NewStringsValue(nil, &c.statuses), "status", "Only show Actions with these statuses")
+	f.DurationVar(&c.since, "since", 0, "Only show Actions enqueued within this duration, e.g. 24h")
+	f.IntVar(&c.limit, "limit", 0, "Show at most this many Actions")
+	f.StringVar(&c.after, "after", "", "Only show Actions listed after the Action with this ID")
 }
 
 func (c *statusCommand) Info() *cmd.Info {
@@ -51,13 +97,46 @@ func (c *statusCommand) Init(args []string) error {
 	switch len(args) {
 	case 0:
 		c.requestedId = ""
-		return nil
 	case 1:
 		c.requestedId = args[0]
-		return nil
 	default:
 		return cmd.CheckEmpty(args[1:])
 	}
+	if !c.filtered() {
+		return nil
+	}
+	if c.requestedId != "" {
+		return errors.New("cannot filter by action ID and by --application, --status, --since, --limit or --after")
+	}
+	for _, status := range c.statuses {
+		if !isValidActionStatus(status) {
+			return errors.Errorf("invalid status %q", status)
+		}
+	}
+	if c.since < 0 {
+		return errors.Errorf("invalid --since %v: negative duration", c.since)
+	}
+	if c.limit < 0 {
+		return errors.Errorf("invalid --limit %d: negative limit", c.limit)
+	}
+	if c.after != "" && !names.IsValidAction(c.after) {
+		return errors.Errorf("invalid --after %q: not an action ID", c.after)
+	}
+	return nil
+}
+
+// filtered reports whether any of the filter options is set.
+func (c *statusCommand) filtered() bool {
+	return len(c.applications) > 0 || len(c.statuses) > 0 || c.since != 0 || c.limit != 0 || c.after != ""
+}
+
+func isValidActionStatus(status string) bool {
+	for _, valid := range validActionStatuses {
+		if status == valid {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *statusCommand) Run(ctx *cmd.Context) error {
@@ -67,6 +146,10 @@ func (c *statusCommand) Run(ctx *cmd.Context) error {
 	}
 	defer api.Close()
 
+	if c.filtered() {
+		return c.listActions(ctx, api)
+	}
+
 	if c.name != "" {
 		actions, err := GetActionsByName(api, c.name)
 		if err != nil {
@@ -105,6 +188,45 @@ func (c *statusCommand) Run(ctx *cmd.Context) error {
 	return c.out.Write(ctx, resultsToMap(actions.Results))
 }
 
+// listActions shows the model's actions that match the filter options.
+func (c *statusCommand) listActions(ctx *cmd.Context, api APIClient) error {
+	query := params.ActionQueryArgs{
+		Applications: c.applications,
+		Statuses:     c.statuses,
+		Limit:        c.limit,
+	}
+	if c.name != "" {
+		query.ActionNames = []string{c.name}
+	}
+	if c.since > 0 {
+		query.EnqueuedAfter = time.Now().Add(-c.since)
+	}
+	if c.after != "" {
+		query.After = names.NewActionTag(c.after).String()
+	}
+	results, err := api.ListActions(query)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(results.Results) < 1 {
+		return errors.Errorf("no actions found")
+	}
+	if err := c.out.Write(ctx, resultsToMap(results.Results)); err != nil {
+		return errors.Trace(err)
+	}
+	pageSize := c.limit
+	if pageSize == 0 {
+		pageSize = defaultActionPageSize
+	}
+	last := results.Results[len(results.Results)-1]
+	if len(results.Results) == pageSize && last.Action != nil {
+		if tag, err := names.ParseActionTag(last.Action.Tag); err == nil {
+			ctx.Infof("showing %d actions; use --after %s to see more", pageSize, tag.Id())
+		}
+	}
+	return nil
+}
+
 // resultsToMap is a helper function that takes in a []params.ActionResult
 // and returns a map[string]interface{} ready to be served to the
 // formatter for printing.
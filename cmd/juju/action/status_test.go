@@ -90,6 +90,99 @@ func (s *StatusSuite) TestRun(c *gc.C) {
 	}
 }
 
+func (s *StatusSuite) TestInitFiltered(c *gc.C) {
+	for i, test := range []struct {
+		args        []string
+		expectError string
+	}{{
+		args: []string{"--application", "mysql,wordpress", "--status", "failed", "--since", "24h", "--limit", "10"},
+	}, {
+		args: []string{"--after", "f47ac10b-58cc-4372-a567-0e02b2c3d479"},
+	}, {
+		args:        []string{"deadbeef", "--application", "mysql"},
+		expectError: "cannot filter by action ID and by --application, --status, --since, --limit or --after",
+	}, {
+		args:        []string{"--status", "broken"},
+		expectError: `invalid status "broken"`,
+	}, {
+		args:        []string{"--since", "-1h"},
+		expectError: "invalid --since -1h0m0s: negative duration",
+	}, {
+		args:        []string{"--limit", "-1"},
+		expectError: "invalid --limit -1: negative limit",
+	}, {
+		args:        []string{"--after", "deadbeef"},
+		expectError: `invalid --after "deadbeef": not an action ID`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command, _ := action.NewStatusCommandForTest(s.store)
+		args := append([]string{"-m", "admin"}, test.args...)
+		err := testing.InitCommand(command, args)
+		if test.expectError == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.expectError)
+		}
+	}
+}
+
+func (s *StatusSuite) TestRunFiltered(c *gc.C) {
+	results := []params.ActionResult{{Status: params.ActionFailed}, {Status: params.ActionFailed}}
+	client := &fakeAPIClient{actionResults: results}
+	restore := s.patchAPIClient(client)
+	defer restore()
+
+	start := time.Now()
+	command, _ := action.NewStatusCommandForTest(s.store)
+	ctx, err := testing.RunCommand(c, command, "-m", "admin",
+		"--name", "backup", "--application", "mysql,wordpress",
+		"--status", "failed", "--since", "1h", "--limit", "2",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	query := client.actionQuery
+	c.Check(query.Applications, jc.DeepEquals, []string{"mysql", "wordpress"})
+	c.Check(query.ActionNames, jc.DeepEquals, []string{"backup"})
+	c.Check(query.Statuses, jc.DeepEquals, []string{"failed"})
+	c.Check(query.Limit, gc.Equals, 2)
+	c.Check(query.After, gc.Equals, "")
+	c.Check(query.EnqueuedAfter.Before(start.Add(-time.Hour)), jc.IsFalse)
+	c.Check(query.EnqueuedAfter.After(time.Now().Add(-time.Hour)), jc.IsFalse)
+
+	out := &bytes.Buffer{}
+	err = cmd.FormatYaml(out, action.ActionResultsToMap(results))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stdout(ctx), gc.Equals, out.String())
+}
+
+func (s *StatusSuite) TestRunFilteredNextPage(c *gc.C) {
+	results := []params.ActionResult{{
+		Action: &params.Action{Tag: "action-f47ac10b-58cc-4372-a567-0e02b2c3d479"},
+		Status: params.ActionCompleted,
+	}}
+	client := &fakeAPIClient{actionResults: results}
+	restore := s.patchAPIClient(client)
+	defer restore()
+
+	command, _ := action.NewStatusCommandForTest(s.store)
+	ctx, err := testing.RunCommand(c, command, "-m", "admin",
+		"--limit", "1", "--after", "0e02b2c3-58cc-4372-a567-f47ac10bd479",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(client.actionQuery.After, gc.Equals, "action-0e02b2c3-58cc-4372-a567-f47ac10bd479")
+	c.Check(testing.Stderr(ctx), gc.Equals,
+		"showing 1 actions; use --after f47ac10b-58cc-4372-a567-0e02b2c3d479 to see more\n")
+}
+
+func (s *StatusSuite) TestRunFilteredNoActions(c *gc.C) {
+	restore := s.patchAPIClient(&fakeAPIClient{})
+	defer restore()
+
+	command, _ := action.NewStatusCommandForTest(s.store)
+	_, err := testing.RunCommand(c, command, "-m", "admin", "--status", "pending")
+	c.Assert(err, gc.ErrorMatches, "no actions found")
+}
+
 func (s *StatusSuite) runTestCase(c *gc.C, tc statusTestCase) {
 	for _, modelFlag := range s.modelFlags {
 		fakeClient := makeFakeClient(
@@ -4,8 +4,10 @@
 package backups
 
 import (
+	"bufio"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"os"
 
 	"github.com/juju/cmd"
@@ -17,14 +19,16 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/cmd/modelcmd"
 	statebackups "github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/backups/encryption"
 )
 
 // APIClient represents the backups API client functionality used by
 // the backups command.
 type APIClient interface {
 	io.Closer
-	// Create sends an RPC request to create a new backup.
-	Create(notes string) (*params.BackupsMetadataResult, error)
+	// Create sends an RPC request to create a new backup, encrypted
+	// with the key if one is given.
+	Create(notes string, encryptionKey []byte) (*params.BackupsMetadataResult, error)
 	// Info gets the backup's metadata.
 	Info(id string) (*params.BackupsMetadataResult, error)
 	// List gets all stored metadata.
@@ -85,6 +89,46 @@ func (c *CommandBase) dumpMetadata(ctx *cmd.Context, result *params.BackupsMetad
 	fmt.Fprintf(ctx.Stdout, "machine ID:      %q\n", result.Machine)
 	fmt.Fprintf(ctx.Stdout, "created on host: %q\n", result.Hostname)
 	fmt.Fprintf(ctx.Stdout, "juju version:    %v\n", result.Version)
+	if result.Encrypted {
+		fmt.Fprintf(ctx.Stdout, "encrypted:       true\n")
+	}
+}
+
+// readKeyFile returns the backup encryption key held in the file.
+func readKeyFile(filename string) (*encryption.Key, error) {
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		return nil, errors.Annotate(err, "reading key file")
+	}
+	key, err := encryption.ParseKey(data)
+	if err != nil {
+		return nil, errors.Annotatef(err, "invalid key in %q", filename)
+	}
+	return key, nil
+}
+
+// decryptToTempFile decrypts the archive read from r into a new
+// temporary file, and returns the file's name. The caller is
+// responsible for removing the file.
+func decryptToTempFile(r io.Reader, key *encryption.Key) (_ string, err error) {
+	decrypted, err := encryption.NewReader(r, key)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	file, err := ioutil.TempFile("", "juju-backup-")
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer func() {
+		file.Close()
+		if err != nil {
+			os.Remove(file.Name())
+		}
+	}()
+	if _, err := io.Copy(file, decrypted); err != nil {
+		return "", errors.Annotate(err, "decrypting archive")
+	}
+	return file.Name(), nil
 }
 
 // ArchiveReader can read a backup archive.
@@ -104,13 +148,19 @@ func getArchive(filename string) (rc ArchiveReader, metaResult *params.BackupsMe
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
+	if encryption.IsEncrypted(bufio.NewReader(archive)) {
+		return nil, nil, errors.New("backup archive is encrypted; a decryption key is needed")
+	}
+	if _, err := archive.Seek(0, io.SeekStart); err != nil {
+		return nil, nil, errors.Trace(err)
+	}
 
 	// Extract the metadata.
 	ad, err := statebackups.NewArchiveDataReader(archive)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
-	_, err = archive.Seek(0, os.SEEK_SET)
+	_, err = archive.Seek(0, io.SeekStart)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
@@ -143,7 +193,7 @@ func getArchive(filename string) (rc ArchiveReader, metaResult *params.BackupsMe
 	if meta.Finished == nil || meta.Finished.IsZero() {
 		meta.Finished = fileMeta.Finished
 	}
-	_, err = archive.Seek(0, os.SEEK_SET)
+	_, err = archive.Seek(0, io.SeekStart)
 	if err != nil {
 		return nil, nil, errors.Trace(err)
 	}
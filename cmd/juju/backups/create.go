@@ -44,6 +44,13 @@ Therefore, you should use the --download or --filename options, or use:
 to get a local copy of the backup archive.
 This local copy can then be used to restore an model even if that
 model was already destroyed or is otherwise unavailable.
+
+Backups contain credentials, so they should be encrypted at rest. The
+--encryption-key option names a file holding either a PEM-encoded RSA
+public key or a secret of at least 16 bytes; the controller encrypts the
+archive with it. Only the public part of an RSA key is sent to the
+controller. Keep the private key or secret safe: it is needed to
+download, verify or restore the backup, and cannot be recovered.
 `
 
 // NewCreateCommand returns a command used to create backups.
@@ -60,6 +67,9 @@ type createCommand struct {
 	Filename string
 	// Notes is the custom message to associated with the new backup.
 	Notes string
+	// KeyFile is the file holding the key with which to encrypt the
+	// backup.
+	KeyFile string
 }
 
 // Info implements Command.Info.
@@ -77,6 +87,7 @@ func (c *createCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.CommandBase.SetFlags(f)
 	f.BoolVar(&c.NoDownload, "no-download", false, "Do not download the archive")
 	f.StringVar(&c.Filename, "filename", notset, "Download to this file")
+	f.StringVar(&c.KeyFile, "encryption-key", "", "Encrypt the backup with the public key or secret in this file")
 }
 
 // Init implements Command.Init.
@@ -110,7 +121,18 @@ func (c *createCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
-	result, err := client.Create(c.Notes)
+	var encryptionKey []byte
+	if c.KeyFile != "" {
+		key, err := readKeyFile(c.KeyFile)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if encryptionKey, err = key.EncryptionOnly(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
+	result, err := client.Create(c.Notes, encryptionKey)
 	if err != nil {
 		return errors.Trace(err)
 	}
@@ -5,6 +5,8 @@ package backups_test
 
 import (
 	"bytes"
+	"io/ioutil"
+	"path/filepath"
 	"strings"
 
 	"github.com/juju/cmd"
@@ -139,3 +141,27 @@ func (s *createSuite) TestError(c *gc.C) {
 
 	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
 }
+
+func (s *createSuite) TestEncryptionKey(c *gc.C) {
+	client := s.BaseBackupsSuite.setDownload()
+	keyFile := filepath.Join(c.MkDir(), "backup.key")
+	err := ioutil.WriteFile(keyFile, []byte("correct horse battery staple\n"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = testing.RunCommand(c, s.wrappedCommand, "--quiet", "--no-download", "--encryption-key", keyFile)
+	c.Assert(err, jc.ErrorIsNil)
+
+	client.Check(c, "", "", "Create")
+	c.Check(string(client.encryptionKey), gc.Equals, "correct horse battery staple")
+}
+
+func (s *createSuite) TestEncryptionKeyInvalid(c *gc.C) {
+	client := s.BaseBackupsSuite.setDownload()
+	keyFile := filepath.Join(c.MkDir(), "backup.key")
+	err := ioutil.WriteFile(keyFile, []byte("short"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = testing.RunCommand(c, s.wrappedCommand, "--quiet", "--no-download", "--encryption-key", keyFile)
+	c.Assert(err, gc.ErrorMatches, `invalid key in ".*backup.key": encryption key too short \(need at least 16 bytes\)`)
+	c.Check(client.calls, gc.HasLen, 0)
+}
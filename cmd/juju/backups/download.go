@@ -14,6 +14,7 @@ import (
 
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/backups/encryption"
 )
 
 const downloadDoc = `
@@ -21,6 +22,10 @@ download-backup retrieves a backup archive file.
 
 If --filename is not used, the archive is downloaded to a temporary
 location and the filename is printed to stdout.
+
+If the backup was encrypted, --decryption-key may name a file holding
+the RSA private key or secret needed to decrypt it as it is downloaded.
+Otherwise the archive is saved still encrypted.
 `
 
 // NewDownloadCommand returns a commant used to download backups.
@@ -35,6 +40,9 @@ type downloadCommand struct {
 	Filename string
 	// ID is the backup ID to download.
 	ID string
+	// KeyFile is the file holding the key with which to decrypt the
+	// archive.
+	KeyFile string
 }
 
 // Info implements Command.Info.
@@ -51,6 +59,7 @@ func (c *downloadCommand) Info() *cmd.Info {
 func (c *downloadCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.CommandBase.SetFlags(f)
 	f.StringVar(&c.Filename, "filename", "", "Download target")
+	f.StringVar(&c.KeyFile, "decryption-key", "", "Decrypt the archive with the private key or secret in this file")
 }
 
 // Init implements Command.Init.
@@ -79,12 +88,25 @@ func (c *downloadCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
+	var key *encryption.Key
+	if c.KeyFile != "" {
+		if key, err = readKeyFile(c.KeyFile); err != nil {
+			return errors.Trace(err)
+		}
+	}
+
 	// Download the archive.
 	resultArchive, err := client.Download(c.ID)
 	if err != nil {
 		return errors.Trace(err)
 	}
 	defer resultArchive.Close()
+	var source io.Reader = resultArchive
+	if key != nil {
+		if source, err = encryption.NewReader(resultArchive, key); err != nil {
+			return errors.Trace(err)
+		}
+	}
 
 	// Prepare the local archive.
 	filename := c.ResolveFilename()
@@ -95,8 +117,11 @@ func (c *downloadCommand) Run(ctx *cmd.Context) error {
 	defer archive.Close()
 
 	// Write out the archive.
-	_, err = io.Copy(archive, resultArchive)
+	_, err = io.Copy(archive, source)
 	if err != nil {
+		// Don't leave a partial (possibly partly decrypted) archive.
+		archive.Close()
+		os.Remove(filename)
 		return errors.Annotate(err, "while creating local archive file")
 	}
 
@@ -4,12 +4,17 @@
 package backups_test
 
 import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+
 	"github.com/juju/cmd"
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/cmd/juju/backups"
+	"github.com/juju/juju/state/backups/encryption"
 	"github.com/juju/juju/testing"
 )
 
@@ -71,3 +76,37 @@ func (s *downloadSuite) TestError(c *gc.C) {
 	_, err := testing.RunCommand(c, s.wrappedCommand, s.metaresult.ID)
 	c.Check(errors.Cause(err), gc.ErrorMatches, "failed!")
 }
+
+func (s *downloadSuite) TestDecryptionKey(c *gc.C) {
+	keyFile := filepath.Join(c.MkDir(), "backup.key")
+	err := ioutil.WriteFile(keyFile, []byte("correct horse battery staple"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	key, err := encryption.ParseKey([]byte("correct horse battery staple"))
+	c.Assert(err, jc.ErrorIsNil)
+	var encrypted bytes.Buffer
+	w, err := encryption.NewWriter(&encrypted, key)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = w.Write([]byte(s.data))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.Close(), jc.ErrorIsNil)
+
+	client := s.setSuccess()
+	client.archive = ioutil.NopCloser(&encrypted)
+	ctx, err := testing.RunCommand(c, s.wrappedCommand, s.metaresult.ID, "--decryption-key", keyFile)
+	c.Check(err, jc.ErrorIsNil)
+
+	s.filename = "juju-backup-" + s.metaresult.ID + ".tar.gz"
+	s.checkStd(c, ctx, s.filename+"\n", "")
+	s.checkArchive(c)
+}
+
+func (s *downloadSuite) TestDecryptionKeyWrong(c *gc.C) {
+	keyFile := filepath.Join(c.MkDir(), "backup.key")
+	err := ioutil.WriteFile(keyFile, []byte("correct horse battery staple"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The archive isn't encrypted at all.
+	s.setSuccess()
+	_, err = testing.RunCommand(c, s.wrappedCommand, s.metaresult.ID, "--decryption-key", keyFile)
+	c.Check(err, gc.ErrorMatches, "backup archive is not encrypted")
+}
@@ -7,6 +7,7 @@ import (
 	"crypto/rand"
 	"fmt"
 	"io"
+	"os"
 	"path/filepath"
 	"strings"
 	"time"
@@ -54,6 +55,7 @@ type restoreCommand struct {
 	backupId       string
 	bootstrap      bool
 	buildAgent     bool
+	keyFile        string
 
 	newAPIClientFunc         func() (RestoreAPI, error)
 	newEnvironFunc           func(environs.OpenParams) (environs.Environ, error)
@@ -72,6 +74,9 @@ type RestoreAPI interface {
 
 	// RestoreReader is taken from backups.Client.
 	RestoreReader(r io.ReadSeeker, meta *params.BackupsMetadataResult, newClient backups.ClientConnection) error
+
+	// Download is taken from backups.Client.
+	Download(backupId string) (io.ReadCloser, error)
 }
 
 var restoreDoc = `
@@ -89,6 +94,11 @@ If the provided state cannot be restored, this command will fail with
 an appropriate message.  For instance, if the existing bootstrap
 instance is already running then the command will fail with a message
 to that effect.
+
+An encrypted backup is restored by naming the file holding its RSA
+private key or secret with --decryption-key. The archive is decrypted
+locally; an encrypted backup stored by the controller is downloaded,
+decrypted and uploaded again for restoring.
 `
 
 var BootstrapFunc = bootstrap.Bootstrap
@@ -111,6 +121,7 @@ func (c *restoreCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.StringVar(&c.filename, "file", "", "Provide a file to be used as the backup.")
 	f.StringVar(&c.backupId, "id", "", "Provide the name of the backup to be restored")
 	f.BoolVar(&c.buildAgent, "build-agent", false, "Build binary agent if bootstraping a new machine")
+	f.StringVar(&c.keyFile, "decryption-key", "", "Decrypt the backup with the private key or secret in this file")
 }
 
 // Init is where the preconditions for this commands can be checked.
@@ -388,8 +399,17 @@ func (c *restoreCommand) Run(ctx *cmd.Context) error {
 		// we'll need the info later regardless if
 		// we need it now to rebootstrap.
 		target = c.filename
+		filename := c.filename
+		if c.keyFile != "" {
+			decrypted, err := c.decryptFile(c.filename)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			defer os.Remove(decrypted)
+			filename = decrypted
+		}
 		var err error
-		archive, meta, err = c.getArchiveFunc(c.filename)
+		archive, meta, err = c.getArchiveFunc(filename)
 		if err != nil {
 			return errors.Trace(err)
 		}
@@ -408,9 +428,24 @@ func (c *restoreCommand) Run(ctx *cmd.Context) error {
 	}
 	defer client.Close()
 
+	// An encrypted backup stored by the controller can't be restored
+	// in place, so fetch and decrypt it, then restore from that.
+	if c.backupId != "" && c.keyFile != "" {
+		decrypted, err := c.downloadAndDecrypt(client)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer os.Remove(decrypted)
+		archive, meta, err = c.getArchiveFunc(decrypted)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer archive.Close()
+	}
+
 	// We have a backup client, now use the relevant method
 	// to restore the backup.
-	if c.filename != "" {
+	if archive != nil {
 		err = client.RestoreReader(archive, meta, c.newClient)
 	} else {
 		err = client.Restore(c.backupId, c.newClient)
@@ -421,3 +456,35 @@ func (c *restoreCommand) Run(ctx *cmd.Context) error {
 	fmt.Fprintf(ctx.Stdout, "restore from %q completed\n", target)
 	return nil
 }
+
+// decryptFile decrypts the archive in the file to a temporary file,
+// and returns its name.
+func (c *restoreCommand) decryptFile(filename string) (string, error) {
+	key, err := readKeyFile(c.keyFile)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	encrypted, err := os.Open(filename)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer encrypted.Close()
+	decrypted, err := decryptToTempFile(encrypted, key)
+	return decrypted, errors.Trace(err)
+}
+
+// downloadAndDecrypt downloads the backup being restored and decrypts
+// it to a temporary file, returning its name.
+func (c *restoreCommand) downloadAndDecrypt(client RestoreAPI) (string, error) {
+	key, err := readKeyFile(c.keyFile)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	encrypted, err := client.Download(c.backupId)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer encrypted.Close()
+	decrypted, err := decryptToTempFile(encrypted, key)
+	return decrypted, errors.Trace(err)
+}
@@ -0,0 +1,152 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/base64"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/backups/encryption"
+)
+
+// NewVerifyCommand returns a command used to check a backup archive
+// without restoring it.
+func NewVerifyCommand() cmd.Command {
+	return &verifyCommand{}
+}
+
+// verifyCommand checks that a local backup archive is intact.
+type verifyCommand struct {
+	cmd.CommandBase
+
+	filename string
+	checksum string
+	keyFile  string
+}
+
+const verifyDoc = `
+Checks that a backup archive is intact and could be restored, without
+restoring it or contacting a controller.
+
+If --checksum is given, the archive file's checksum (as shown by
+show-backup, and printed when the backup is created) must match it.
+An encrypted archive is decrypted with the RSA private key or secret
+in the file given by --decryption-key.
+
+The archive is then unpacked into a temporary directory, and checked:
+its metadata must be readable and of a format this client supports,
+the archive must hold the juju database dump with every dumped
+collection complete, and every file must match the checksum recorded
+in the archive's manifest. Archives made before manifests were added
+only get the structural checks.
+
+Examples:
+
+    juju verify-backup juju-backup-20170501-120000.tar.gz
+    juju verify-backup --checksum Rx8/0xhN4JmCbKgVm3CCsRJXFtQ= backup.tar.gz
+    juju verify-backup --decryption-key backup.key backup.tar.gz
+
+See also:
+    create-backup
+    download-backup
+    restore-backup
+`
+
+// Info implements Command.Info.
+func (c *verifyCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "verify-backup",
+		Args:    "<file>",
+		Purpose: "Verifies that a backup archive is intact.",
+		Doc:     strings.TrimSpace(verifyDoc),
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *verifyCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.CommandBase.SetFlags(f)
+	f.StringVar(&c.checksum, "checksum", "", "Expected SHA-1 checksum (base64 encoded) of the archive file")
+	f.StringVar(&c.keyFile, "decryption-key", "", "Decrypt the archive with the private key or secret in this file")
+}
+
+// Init implements Command.Init.
+func (c *verifyCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing backup archive file")
+	}
+	filename, args := args[0], args[1:]
+	if err := cmd.CheckEmpty(args); err != nil {
+		return errors.Trace(err)
+	}
+	c.filename = filename
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *verifyCommand) Run(ctx *cmd.Context) error {
+	filename := ctx.AbsPath(c.filename)
+	if c.checksum != "" {
+		checksum, err := fileChecksum(filename)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if checksum != c.checksum {
+			return errors.Errorf("checksum mismatch: archive has %q, expected %q", checksum, c.checksum)
+		}
+		ctx.Infof("checksum verified")
+	}
+
+	archive, err := os.Open(filename)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer archive.Close()
+
+	source := bufio.NewReader(archive)
+	var decrypted io.Reader = source
+	if c.keyFile != "" {
+		key, err := readKeyFile(ctx.AbsPath(c.keyFile))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if decrypted, err = encryption.NewReader(source, key); err != nil {
+			return errors.Trace(err)
+		}
+	} else if encryption.IsEncrypted(source) {
+		return errors.New("backup archive is encrypted; use --decryption-key")
+	}
+
+	meta, err := backups.VerifyArchive(decrypted)
+	if err != nil {
+		return errors.Annotate(err, "backup verification failed")
+	}
+	ctx.Infof("backup of model %s created %s by juju %s verified",
+		meta.Origin.Model, meta.Started.Format("2006-01-02 15:04:05"), meta.Origin.Version)
+	return nil
+}
+
+// fileChecksum returns the base64-encoded SHA-1 sum of the file, as
+// recorded in backup metadata.
+func fileChecksum(filename string) (string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer file.Close()
+
+	hasher := sha1.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", errors.Trace(err)
+	}
+	return base64.StdEncoding.EncodeToString(hasher.Sum(nil)), nil
+}
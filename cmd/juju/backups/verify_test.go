@@ -0,0 +1,122 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"path/filepath"
+
+	"github.com/juju/cmd"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/backups"
+	"github.com/juju/juju/state/backups/encryption"
+	"github.com/juju/juju/testing"
+)
+
+type verifySuite struct {
+	testing.BaseSuite
+
+	dir string
+}
+
+var _ = gc.Suite(&verifySuite{})
+
+func (s *verifySuite) SetUpTest(c *gc.C) {
+	s.BaseSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+}
+
+// legacyArchive returns a minimal backup archive in the format used
+// before manifests were added.
+func legacyArchive(c *gc.C) []byte {
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for _, dir := range []string{"juju-backup", "juju-backup/dump", "juju-backup/dump/juju"} {
+		err := tw.WriteHeader(&tar.Header{Name: dir + "/", Typeflag: tar.TypeDir, Mode: 0755})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	for _, file := range []struct{ name, content string }{
+		{"juju-backup/metadata.json", `{"Environment":"some-model","Version":"2.2.0"}`},
+		{"juju-backup/root.tar", "<files bundle>"},
+		{"juju-backup/dump/juju/machines.bson", "<machines>"},
+		{"juju-backup/dump/juju/machines.metadata.json", "{}"},
+	} {
+		err := tw.WriteHeader(&tar.Header{
+			Name:     file.name,
+			Typeflag: tar.TypeReg,
+			Mode:     0644,
+			Size:     int64(len(file.content)),
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = tw.Write([]byte(file.content))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(tw.Close(), jc.ErrorIsNil)
+	c.Assert(gzw.Close(), jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func (s *verifySuite) writeFile(c *gc.C, name string, data []byte) string {
+	filename := filepath.Join(s.dir, name)
+	err := ioutil.WriteFile(filename, data, 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	return filename
+}
+
+func (s *verifySuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, backups.NewVerifyCommand(), args...)
+}
+
+func (s *verifySuite) TestMissingFile(c *gc.C) {
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "missing backup archive file")
+}
+
+func (s *verifySuite) TestVerify(c *gc.C) {
+	archive := s.writeFile(c, "backup.tar.gz", legacyArchive(c))
+	ctx, err := s.run(c, archive)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(testing.Stderr(ctx), gc.Matches, "backup of model some-model created .* by juju 2.2.0 verified\n")
+}
+
+func (s *verifySuite) TestChecksum(c *gc.C) {
+	archive := s.writeFile(c, "backup.tar.gz", []byte("<archive>"))
+	_, err := s.run(c, archive, "--checksum", "wrong")
+	c.Assert(err, gc.ErrorMatches, `checksum mismatch: archive has "\S+", expected "wrong"`)
+}
+
+func (s *verifySuite) TestEncrypted(c *gc.C) {
+	keyFile := s.writeFile(c, "backup.key", []byte("correct horse battery staple"))
+	key, err := encryption.ParseKey([]byte("correct horse battery staple"))
+	c.Assert(err, jc.ErrorIsNil)
+	var encrypted bytes.Buffer
+	w, err := encryption.NewWriter(&encrypted, key)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = w.Write(legacyArchive(c))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.Close(), jc.ErrorIsNil)
+	archive := s.writeFile(c, "backup.tar.gz", encrypted.Bytes())
+
+	_, err = s.run(c, archive)
+	c.Assert(err, gc.ErrorMatches, "backup archive is encrypted; use --decryption-key")
+
+	_, err = s.run(c, archive, "--decryption-key", keyFile)
+	c.Assert(err, jc.ErrorIsNil)
+
+	wrongKeyFile := s.writeFile(c, "wrong.key", []byte("incorrect horse battery staple"))
+	_, err = s.run(c, archive, "--decryption-key", wrongKeyFile)
+	c.Assert(err, gc.ErrorMatches, ".*cannot decrypt backup archive: wrong key or corrupt archive")
+}
+
+func (s *verifySuite) TestIncomplete(c *gc.C) {
+	archive := s.writeFile(c, "backup.tar.gz", []byte("<not an archive>"))
+	_, err := s.run(c, archive)
+	c.Assert(err, gc.ErrorMatches, "backup verification failed: while unpacking archive: .*")
+}
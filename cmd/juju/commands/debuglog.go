@@ -4,9 +4,11 @@
 package commands
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"regexp"
 	"time"
 
 	"github.com/juju/ansiterm"
@@ -48,13 +50,33 @@ The '--include-module' and '--exclude-module' options filter by (dotted)
 logging module name. The module name can be truncated such that all loggers
 with the prefix will match.
 
+The '--grep' and '--exclude-grep' options filter by message, using regular
+expressions which are evaluated by the controller. Messages spanning several
+lines are matched as a whole.
+
 The filtering options combine as follows:
 * All --include options are logically ORed together.
 * All --exclude options are logically ORed together.
 * All --include-module options are logically ORed together.
 * All --exclude-module options are logically ORed together.
-* The combined --include, --exclude, --include-module and --exclude-module
-  selections are logically ANDed to form the complete filter.
+* All --grep options are logically ORed together.
+* All --exclude-grep options are logically ORed together.
+* The combined --include, --exclude, --include-module, --exclude-module,
+  --grep and --exclude-grep selections are logically ANDed to form the
+  complete filter.
+
+The '--since' and '--until' options restrict the messages shown to those
+logged within a time window. Each takes either an absolute time in RFC3339
+format (2006-01-02T15:04:05Z), or a duration such as 90m or 2h30m, which is
+taken to be that long ago. Giving '--since' implies '--replay', so that the
+whole window is shown. Once the '--until' time is reached no more messages
+are shown, and the command exits.
+
+With '--format json', each message is written as a JSON object on a line of
+its own, with the fields "model", "entity", "timestamp", "level", "module",
+"location" and "message". Timestamps are in UTC, with nanosecond precision,
+and newlines within messages are escaped, so the output is suitable for
+processing by other tools.
 
 Examples:
 
@@ -93,6 +115,21 @@ new WARNING and ERROR messages as they are logged:
 
     juju debug-log --replay --level WARNING
 
+Show all messages logged between 10:00 and 10:30 UTC on 7th March 2017:
+
+    juju debug-log --since 2017-03-07T10:00:00Z --until 2017-03-07T10:30:00Z
+
+Show all ERROR messages from the last hour, and then stop:
+
+    juju debug-log --since 1h --no-tail --level ERROR
+
+Show all messages mentioning hook failures, except those from the
+update-status hook, as JSON, and then stop:
+
+    juju debug-log --replay --no-tail --format json \
+        --grep "hook failed" \
+        --exclude-grep "update-status"
+
 See also: 
     status
     ssh`
@@ -128,10 +165,19 @@ type debugLogCommand struct {
 	notail bool
 	color  bool
 
-	format string
-	tz     *time.Location
+	since string
+	until string
+
+	format       string
+	outputFormat string
+	tz           *time.Location
 }
 
+const (
+	debugLogFormatText = "text"
+	debugLogFormatJSON = "json"
+)
+
 func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
 	f.Var(cmd.NewAppendStringsValue(&c.params.IncludeEntity), "i", "Only show log messages for these entities")
@@ -140,6 +186,8 @@ func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.Var(cmd.NewAppendStringsValue(&c.params.ExcludeEntity), "exclude", "Do not show log messages for these entities")
 	f.Var(cmd.NewAppendStringsValue(&c.params.IncludeModule), "include-module", "Only show log messages for these logging modules")
 	f.Var(cmd.NewAppendStringsValue(&c.params.ExcludeModule), "exclude-module", "Do not show log messages for these logging modules")
+	f.Var(cmd.NewAppendStringsValue(&c.params.IncludeMessage), "grep", "Only show log messages matching these regular expressions")
+	f.Var(cmd.NewAppendStringsValue(&c.params.ExcludeMessage), "exclude-grep", "Do not show log messages matching these regular expressions")
 
 	f.StringVar(&c.level, "l", "", "Log level to show, one of [TRACE, DEBUG, INFO, WARNING, ERROR]")
 	f.StringVar(&c.level, "level", "", "")
@@ -148,6 +196,8 @@ func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.UintVar(&c.params.Backlog, "lines", defaultLineCount, "")
 	f.UintVar(&c.params.Limit, "limit", 0, "Exit once this many of the most recent (possibly filtered) lines are shown")
 	f.BoolVar(&c.params.Replay, "replay", false, "Show the entire (possibly filtered) log and continue to append")
+	f.StringVar(&c.since, "since", "", "Only show log messages logged since this time or duration ago")
+	f.StringVar(&c.until, "until", "", "Only show log messages logged before this time or duration ago, then exit")
 
 	f.BoolVar(&c.notail, "no-tail", false, "Stop after returning existing log messages")
 	f.BoolVar(&c.tail, "tail", false, "Wait for new logs")
@@ -157,6 +207,7 @@ func (c *debugLogCommand) SetFlags(f *gnuflag.FlagSet) {
 	f.BoolVar(&c.location, "location", false, "Show filename and line numbers")
 	f.BoolVar(&c.date, "date", false, "Show dates as well as times")
 	f.BoolVar(&c.ms, "ms", false, "Show times to millisecond precision")
+	f.StringVar(&c.outputFormat, "format", debugLogFormatText, "Specify output format (text|json)")
 }
 
 func (c *debugLogCommand) Init(args []string) error {
@@ -171,6 +222,38 @@ func (c *debugLogCommand) Init(args []string) error {
 	if c.tail && c.notail {
 		return errors.NotValidf("setting --tail and --no-tail")
 	}
+	switch c.outputFormat {
+	case debugLogFormatText, debugLogFormatJSON:
+	default:
+		return errors.Errorf("format value %q is not one of %q, %q",
+			c.outputFormat, debugLogFormatText, debugLogFormatJSON)
+	}
+	now := time.Now()
+	if c.since != "" {
+		since, err := parseLogTime(c.since, now)
+		if err != nil {
+			return errors.Annotate(err, "invalid --since value")
+		}
+		c.params.StartTime = since
+		c.params.Replay = true
+	}
+	if c.until != "" {
+		until, err := parseLogTime(c.until, now)
+		if err != nil {
+			return errors.Annotate(err, "invalid --until value")
+		}
+		if !c.params.StartTime.IsZero() && !until.After(c.params.StartTime) {
+			return errors.New("--until must be later than --since")
+		}
+		c.params.EndTime = until
+	}
+	for _, patterns := range [][]string{c.params.IncludeMessage, c.params.ExcludeMessage} {
+		for _, pattern := range patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return errors.Annotatef(err, "invalid regular expression %q", pattern)
+			}
+		}
+	}
 	if c.utc {
 		c.tz = time.UTC
 	}
@@ -185,6 +268,22 @@ func (c *debugLogCommand) Init(args []string) error {
 	return cmd.CheckEmpty(args)
 }
 
+// parseLogTime parses value as either an RFC3339 time, or a duration
+// before now.
+func parseLogTime(value string, now time.Time) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return t, nil
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return time.Time{}, errors.Errorf("%q is neither an RFC3339 time nor a duration", value)
+	}
+	if d < 0 {
+		return time.Time{}, errors.Errorf("duration %q must not be negative", value)
+	}
+	return now.Add(-d), nil
+}
+
 type DebugLogAPI interface {
 	WatchDebugLog(params common.DebugLogParams) (<-chan common.LogMessage, error)
 	Close() error
@@ -223,6 +322,9 @@ func (c *debugLogCommand) Run(ctx *cmd.Context) (err error) {
 	if err != nil {
 		return err
 	}
+	if c.outputFormat == debugLogFormatJSON {
+		return c.writeJSONRecords(ctx.Stdout, messages)
+	}
 	writer := ansiterm.NewWriter(ctx.Stdout)
 	if c.color {
 		writer.SetColorCapable(true)
@@ -238,6 +340,38 @@ func (c *debugLogCommand) Run(ctx *cmd.Context) (err error) {
 	return nil
 }
 
+// jsonLogRecord is the form of a log message written by
+// debug-log --format json.
+type jsonLogRecord struct {
+	ModelUUID string    `json:"model,omitempty"`
+	Entity    string    `json:"entity"`
+	Timestamp time.Time `json:"timestamp"`
+	Level     string    `json:"level"`
+	Module    string    `json:"module"`
+	Location  string    `json:"location"`
+	Message   string    `json:"message"`
+}
+
+func (c *debugLogCommand) writeJSONRecords(w io.Writer, messages <-chan common.LogMessage) error {
+	// Encode writes each record followed by a newline.
+	encoder := json.NewEncoder(w)
+	for msg := range messages {
+		err := encoder.Encode(jsonLogRecord{
+			ModelUUID: msg.ModelUUID,
+			Entity:    msg.Entity,
+			Timestamp: msg.Timestamp.UTC(),
+			Level:     msg.Severity,
+			Module:    msg.Module,
+			Location:  msg.Location,
+			Message:   msg.Message,
+		})
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
 var SeverityColor = map[string]*ansiterm.Context{
 	"TRACE":   ansiterm.Foreground(ansiterm.Default),
 	"DEBUG":   ansiterm.Foreground(ansiterm.Green),
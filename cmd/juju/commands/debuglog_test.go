@@ -71,6 +71,41 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 				ExcludeModule: []string{"juju.foo", "unit"},
 				Backlog:       10,
 			},
+		}, {
+			args: []string{"--grep", "hook failed", "--grep", "^error"},
+			expected: common.DebugLogParams{
+				IncludeMessage: []string{"hook failed", "^error"},
+				Backlog:        10,
+			},
+		}, {
+			args: []string{"--exclude-grep", "update-status"},
+			expected: common.DebugLogParams{
+				ExcludeMessage: []string{"update-status"},
+				Backlog:        10,
+			},
+		}, {
+			args:     []string{"--grep", "foo("},
+			errMatch: `invalid regular expression "foo\(": .*`,
+		}, {
+			args:     []string{"--format", "yaml"},
+			errMatch: `format value "yaml" is not one of "text", "json"`,
+		}, {
+			args: []string{"--since", "2017-03-07T10:00:00Z", "--until", "2017-03-07T10:30:00.5+01:00"},
+			expected: common.DebugLogParams{
+				Backlog:   10,
+				Replay:    true,
+				StartTime: time.Date(2017, 3, 7, 10, 0, 0, 0, time.UTC),
+				EndTime:   time.Date(2017, 3, 7, 9, 30, 0, 500000000, time.UTC),
+			},
+		}, {
+			args:     []string{"--since", "2017-03-07T10:00:00Z", "--until", "2017-03-07T09:00:00Z"},
+			errMatch: `--until must be later than --since`,
+		}, {
+			args:     []string{"--since", "yesterday"},
+			errMatch: `invalid --since value: "yesterday" is neither an RFC3339 time nor a duration`,
+		}, {
+			args:     []string{"--until=-1h"},
+			errMatch: `invalid --until value: duration "-1h" must not be negative`,
 		}, {
 			args: []string{"--replay"},
 			expected: common.DebugLogParams{
@@ -93,6 +128,10 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 		err := testing.InitCommand(modelcmd.Wrap(command), test.args)
 		if test.errMatch == "" {
 			c.Check(err, jc.ErrorIsNil)
+			c.Check(command.params.StartTime.Equal(test.expected.StartTime), jc.IsTrue)
+			c.Check(command.params.EndTime.Equal(test.expected.EndTime), jc.IsTrue)
+			command.params.StartTime = test.expected.StartTime
+			command.params.EndTime = test.expected.EndTime
 			c.Check(command.params, jc.DeepEquals, test.expected)
 		} else {
 			c.Check(err, gc.ErrorMatches, test.errMatch)
@@ -100,6 +139,17 @@ func (s *DebugLogSuite) TestArgParsing(c *gc.C) {
 	}
 }
 
+func (s *DebugLogSuite) TestRelativeTimes(c *gc.C) {
+	command := &debugLogCommand{}
+	before := time.Now()
+	err := testing.InitCommand(modelcmd.Wrap(command), []string{"--since", "2h", "--until", "90m"})
+	c.Assert(err, jc.ErrorIsNil)
+	after := time.Now()
+	c.Check(command.params.StartTime, jc.TimeBetween(before.Add(-2*time.Hour), after.Add(-2*time.Hour)))
+	c.Check(command.params.EndTime, jc.TimeBetween(before.Add(-90*time.Minute), after.Add(-90*time.Minute)))
+	c.Check(command.params.Replay, jc.IsTrue)
+}
+
 func (s *DebugLogSuite) TestParamsPassed(c *gc.C) {
 	fake := &fakeDebugLogAPI{}
 	s.PatchValue(&getDebugLogAPI, func(_ *debugLogCommand) (DebugLogAPI, error) {
@@ -165,6 +215,34 @@ func (s *DebugLogSuite) TestLogOutput(c *gc.C) {
 		"machine-0: 14:15:23 INFO test.module somefile.go:123 this is the log output\n")
 }
 
+func (s *DebugLogSuite) TestJSONOutput(c *gc.C) {
+	tz := time.FixedZone("test", 6*60*60)
+	s.PatchValue(&getDebugLogAPI, func(_ *debugLogCommand) (DebugLogAPI, error) {
+		return &fakeDebugLogAPI{log: []common.LogMessage{
+			{
+				ModelUUID: "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+				Entity:    "unit-mysql-0",
+				Timestamp: time.Date(2016, 10, 9, 14, 15, 23, 345000000, tz),
+				Severity:  "ERROR",
+				Module:    "juju.worker.uniter",
+				Location:  "uniter.go:42",
+				Message:   "hook failed:\nexit status 1",
+			},
+		}}, nil
+	})
+	ctx, err := testing.RunCommand(c, newDebugLogCommandTZ(tz), "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(ctx), gc.Equals, `{`+
+		`"model":"deadbeef-0bad-400d-8000-4b1d0d06f00d",`+
+		`"entity":"unit-mysql-0",`+
+		`"timestamp":"2016-10-09T08:15:23.345Z",`+
+		`"level":"ERROR",`+
+		`"module":"juju.worker.uniter",`+
+		`"location":"uniter.go:42",`+
+		`"message":"hook failed:\nexit status 1"`+
+		"}\n")
+}
+
 type fakeDebugLogAPI struct {
 	log    []common.LogMessage
 	params common.DebugLogParams
@@ -403,6 +403,7 @@ var commandNames = []string{
 	"agreements",
 	"allocate",
 	"attach-storage",
+	"audit-log",
 	"autoload-credentials",
 	"backups",
 	"bootstrap",
@@ -416,6 +417,7 @@ var commandNames = []string{
 	"controllers",
 	"create-backup",
 	"create-budget",
+	"create-model-backup",
 	"create-storage-pool",
 	"credentials",
 	"controller-config",
@@ -434,6 +436,7 @@ var commandNames = []string{
 	"enable-command",
 	"enable-destroy-controller",
 	"enable-user",
+	"export-status-log",
 	"expose",
 	"get-constraints",
 	"get-model-constraints",
@@ -486,6 +489,7 @@ var commandNames = []string{
 	"remove-unit",
 	"resolved",
 	"restore-backup",
+	"restore-model-backup",
 	"retry-provisioning",
 	"revoke",
 	"run",
@@ -506,6 +510,7 @@ var commandNames = []string{
 	"show-controller",
 	"show-machine",
 	"show-model",
+	"show-operation",
 	"show-status",
 	"show-status-log",
 	"show-storage",
@@ -529,7 +534,10 @@ var commandNames = []string{
 	"upgrade-gui",
 	"upgrade-juju",
 	"users",
+	"verify-audit-log",
+	"verify-backup",
 	"version",
+	"wait",
 	"whoami",
 }
 
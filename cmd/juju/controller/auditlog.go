@@ -0,0 +1,232 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"io"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/auditlog"
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/cmd/output"
+)
+
+// NewAuditLogCommand returns a command to query the controller's
+// audit log.
+func NewAuditLogCommand() cmd.Command {
+	return modelcmd.WrapController(&auditLogCommand{})
+}
+
+// auditLogCommand displays the audit entries recorded by a controller.
+type auditLogCommand struct {
+	modelcmd.ControllerCommandBase
+	api AuditLogAPI
+	out cmd.Output
+
+	model     string
+	user      string
+	operation string
+	after     string
+	before    string
+	limit     int
+
+	filter auditlog.Filter
+}
+
+const auditLogHelpDoc = `
+Shows the audit entries recorded by the controller for API requests
+made by users, oldest first. Entries may be filtered by model, user,
+operation and time range; when more entries match than the limit,
+the most recent ones are shown.
+
+Times may be given as RFC3339 timestamps (2006-01-02T15:04:05Z) or
+as dates (2006-01-02), which are taken to be midnight UTC.
+
+Only controller superusers may view the audit log.
+
+Examples:
+
+    juju audit-log
+    juju audit-log --user bob --model prod
+    juju audit-log --operation Destroy --after 2017-03-07 --before 2017-03-08
+    juju audit-log --limit 1000 --format json
+
+See also:
+    controller-config
+`
+
+// AuditLogAPI defines the methods on the AuditLog API that the
+// audit-log command calls.
+type AuditLogAPI interface {
+	Close() error
+	Query(auditlog.Filter) ([]audit.AuditEntry, error)
+}
+
+// Info implements Command.Info.
+func (c *auditLogCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "audit-log",
+		Purpose: "Displays the controller's audit log.",
+		Doc:     strings.TrimSpace(auditLogHelpDoc),
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *auditLogCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ControllerCommandBase.SetFlags(f)
+	f.StringVar(&c.model, "model", "", "Only show entries for the named model (or model UUID)")
+	f.StringVar(&c.user, "user", "", "Only show entries for requests made by the named user")
+	f.StringVar(&c.operation, "operation", "", "Only show entries whose operation contains the given text")
+	f.StringVar(&c.after, "after", "", "Only show entries recorded at or after the given time")
+	f.StringVar(&c.before, "before", "", "Only show entries recorded before the given time")
+	f.IntVar(&c.limit, "limit", 100, "The maximum number of entries to show")
+	c.out.AddFlags(f, "tabular", map[string]cmd.Formatter{
+		"yaml":    cmd.FormatYaml,
+		"json":    cmd.FormatJson,
+		"tabular": formatAuditLogTabular,
+	})
+}
+
+// Init implements Command.Init.
+func (c *auditLogCommand) Init(args []string) error {
+	if c.user != "" {
+		if !names.IsValidUser(c.user) {
+			return errors.NotValidf("user name %q", c.user)
+		}
+		c.filter.User = names.NewUserTag(c.user)
+	}
+	if c.limit <= 0 {
+		return errors.New("--limit must be a positive number")
+	}
+	c.filter.Limit = c.limit
+	c.filter.Operation = c.operation
+	var err error
+	if c.filter.After, err = parseAuditLogTime(c.after); err != nil {
+		return errors.Annotate(err, "invalid --after value")
+	}
+	if c.filter.Before, err = parseAuditLogTime(c.before); err != nil {
+		return errors.Annotate(err, "invalid --before value")
+	}
+	if !c.filter.After.IsZero() && !c.filter.Before.IsZero() && !c.filter.After.Before(c.filter.Before) {
+		return errors.New("--after must be earlier than --before")
+	}
+	return cmd.CheckEmpty(args)
+}
+
+func parseAuditLogTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t.UTC(), nil
+	}
+	t, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return time.Time{}, errors.Errorf("expected RFC3339 timestamp or YYYY-MM-DD date, got %q", value)
+	}
+	return t, nil
+}
+
+func (c *auditLogCommand) getAPI() (AuditLogAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return auditlog.NewClient(root), nil
+}
+
+// Run implements Command.Run.
+func (c *auditLogCommand) Run(ctx *cmd.Context) error {
+	if c.model != "" {
+		if utils.IsValidUUIDString(c.model) {
+			c.filter.ModelUUID = c.model
+		} else {
+			uuids, err := c.ModelUUIDs([]string{c.model})
+			if err != nil {
+				return errors.Trace(err)
+			}
+			c.filter.ModelUUID = uuids[0]
+		}
+	}
+
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	entries, err := client.Query(c.filter)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if len(entries) == 0 && c.out.Name() == "tabular" {
+		ctx.Infof("No audit entries to display.")
+		return nil
+	}
+	return c.out.Write(ctx, formatAuditEntries(entries))
+}
+
+// auditEntry is the serialisation format for an audit entry.
+type auditEntry struct {
+	Timestamp     time.Time              `yaml:"timestamp" json:"timestamp"`
+	ModelUUID     string                 `yaml:"model-uuid" json:"model-uuid"`
+	User          string                 `yaml:"user" json:"user"`
+	OriginType    string                 `yaml:"origin-type" json:"origin-type"`
+	RemoteAddress string                 `yaml:"remote-address" json:"remote-address"`
+	Operation     string                 `yaml:"operation" json:"operation"`
+	ServerVersion string                 `yaml:"server-version" json:"server-version"`
+	Data          map[string]interface{} `yaml:"data,omitempty" json:"data,omitempty"`
+}
+
+func formatAuditEntries(entries []audit.AuditEntry) []auditEntry {
+	result := make([]auditEntry, len(entries))
+	for i, entry := range entries {
+		user := entry.OriginName
+		if tag, err := names.ParseUserTag(user); err == nil {
+			user = tag.Id()
+		}
+		result[i] = auditEntry{
+			Timestamp:     entry.Timestamp,
+			ModelUUID:     entry.ModelUUID,
+			User:          user,
+			OriginType:    entry.OriginType,
+			RemoteAddress: entry.RemoteAddress,
+			Operation:     entry.Operation,
+			ServerVersion: entry.JujuServerVersion.String(),
+			Data:          entry.Data,
+		}
+	}
+	return result
+}
+
+func formatAuditLogTabular(writer io.Writer, value interface{}) error {
+	entries, ok := value.([]auditEntry)
+	if !ok {
+		return errors.Errorf("expected value of type %T, got %T", entries, value)
+	}
+	tw := output.TabWriter(writer)
+	w := output.Wrapper{tw}
+	w.Println("Time", "Model", "User", "Address", "Operation")
+	for _, entry := range entries {
+		w.Println(
+			entry.Timestamp.Format("2006-01-02 15:04:05"),
+			entry.ModelUUID,
+			entry.User,
+			entry.RemoteAddress,
+			entry.Operation,
+		)
+	}
+	return tw.Flush()
+}
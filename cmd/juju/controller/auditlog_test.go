@@ -0,0 +1,164 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api/auditlog"
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/cmd/juju/controller"
+	"github.com/juju/juju/testing"
+)
+
+type AuditLogSuite struct {
+	baseControllerSuite
+	api *fakeAuditLogAPI
+}
+
+var _ = gc.Suite(&AuditLogSuite{})
+
+func (s *AuditLogSuite) SetUpTest(c *gc.C) {
+	s.baseControllerSuite.SetUpTest(c)
+	s.createTestClientStore(c)
+	t0 := time.Date(2017, time.March, 7, 10, 0, 0, 0, time.UTC)
+	s.api = &fakeAuditLogAPI{
+		entries: []audit.AuditEntry{{
+			JujuServerVersion: version.MustParse("2.2.0"),
+			ModelUUID:         "def",
+			Timestamp:         t0,
+			RemoteAddress:     "10.0.0.1",
+			OriginType:        "API request",
+			OriginName:        "user-bob",
+			Operation:         "Application:v3 - Destroy",
+		}, {
+			JujuServerVersion: version.MustParse("2.2.0"),
+			ModelUUID:         "def",
+			Timestamp:         t0.Add(time.Minute),
+			RemoteAddress:     "10.0.0.2",
+			OriginType:        "API request",
+			OriginName:        "user-mary",
+			Operation:         "Client:v1 - FullStatus",
+		}},
+	}
+}
+
+func (s *AuditLogSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	command := controller.NewAuditLogCommandForTest(s.api, s.store)
+	return testing.RunCommand(c, command, args...)
+}
+
+func (s *AuditLogSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"extra"},
+		err:  `unrecognized args: \["extra"\]`,
+	}, {
+		args: []string{"--user", "not a user"},
+		err:  `user name "not a user" not valid`,
+	}, {
+		args: []string{"--limit", "0"},
+		err:  `--limit must be a positive number`,
+	}, {
+		args: []string{"--after", "yesterday"},
+		err:  `invalid --after value: expected RFC3339 timestamp or YYYY-MM-DD date, got "yesterday"`,
+	}, {
+		args: []string{"--after", "2017-03-08", "--before", "2017-03-07"},
+		err:  `--after must be earlier than --before`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := controller.NewAuditLogCommandForTest(s.api, s.store)
+		err := testing.InitCommand(command, test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *AuditLogSuite) TestFilter(c *gc.C) {
+	_, err := s.run(c,
+		"--model", "my-model",
+		"--user", "bob",
+		"--operation", "Destroy",
+		"--after", "2017-03-07",
+		"--before", "2017-03-08T12:00:00+02:00",
+		"--limit", "5",
+	)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.filter, jc.DeepEquals, auditlog.Filter{
+		ModelUUID: "def",
+		User:      names.NewUserTag("bob"),
+		Operation: "Destroy",
+		After:     time.Date(2017, time.March, 7, 0, 0, 0, 0, time.UTC),
+		Before:    time.Date(2017, time.March, 8, 10, 0, 0, 0, time.UTC),
+		Limit:     5,
+	})
+}
+
+func (s *AuditLogSuite) TestDefaultLimit(c *gc.C) {
+	_, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.api.filter, jc.DeepEquals, auditlog.Filter{Limit: 100})
+}
+
+func (s *AuditLogSuite) TestTabular(c *gc.C) {
+	context, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, `
+Time                 Model  User  Address   Operation
+2017-03-07 10:00:00  def    bob   10.0.0.1  Application:v3 - Destroy
+2017-03-07 10:01:00  def    mary  10.0.0.2  Client:v1 - FullStatus
+`[1:])
+}
+
+func (s *AuditLogSuite) TestYAML(c *gc.C) {
+	s.api.entries = s.api.entries[:1]
+	context, err := s.run(c, "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, `
+- timestamp: 2017-03-07T10:00:00Z
+  model-uuid: def
+  user: bob
+  origin-type: API request
+  remote-address: 10.0.0.1
+  operation: Application:v3 - Destroy
+  server-version: 2.2.0
+`[1:])
+}
+
+func (s *AuditLogSuite) TestNoEntries(c *gc.C) {
+	s.api.entries = nil
+	context, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stdout(context), gc.Equals, "")
+	c.Assert(testing.Stderr(context), gc.Equals, "No audit entries to display.\n")
+}
+
+func (s *AuditLogSuite) TestAPIError(c *gc.C) {
+	s.api.err = errors.New("permission denied")
+	_, err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "permission denied")
+}
+
+type fakeAuditLogAPI struct {
+	filter  auditlog.Filter
+	entries []audit.AuditEntry
+	err     error
+}
+
+func (f *fakeAuditLogAPI) Close() error {
+	return nil
+}
+
+func (f *fakeAuditLogAPI) Query(filter auditlog.Filter) ([]audit.AuditEntry, error) {
+	f.filter = filter
+	return f.entries, f.err
+}
@@ -156,6 +156,14 @@ func NewGetConfigCommandForTest(api controllerAPI, store jujuclient.ClientStore)
 	return modelcmd.WrapController(c)
 }
 
+// NewAuditLogCommandForTest returns an auditLogCommand with the
+// api and clientstore provided as specified.
+func NewAuditLogCommandForTest(api AuditLogAPI, store jujuclient.ClientStore) cmd.Command {
+	c := &auditLogCommand{api: api}
+	c.SetClientStore(store)
+	return modelcmd.WrapController(c)
+}
+
 type CtrData ctrData
 type ModelData modelData
 
@@ -0,0 +1,117 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller
+
+import (
+	"os"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/audit"
+)
+
+// defaultAuditLogDir is where controller agents write the audit log.
+const defaultAuditLogDir = "/var/log/juju"
+
+// NewVerifyAuditLogCommand returns a command to check that a
+// controller's audit log files have not been tampered with.
+func NewVerifyAuditLogCommand() cmd.Command {
+	return &verifyAuditLogCommand{}
+}
+
+// verifyAuditLogCommand checks the hash chain of audit log files.
+type verifyAuditLogCommand struct {
+	cmd.CommandBase
+
+	dir   string
+	paths []string
+}
+
+const verifyAuditLogHelpDoc = `
+Checks that the audit log files written by a controller agent have not
+been altered, and that no entries have been removed from them.
+
+Each entry in the audit log records a hash of the entry before it.
+This command recomputes the hashes, reporting the first entry which
+doesn't match. Entries written before hash chaining was introduced
+are counted but cannot be verified.
+
+Given a directory, all of the audit log files within it, including
+compressed rotated files, are checked in the order they were written.
+Individual files may be given instead, oldest first. By default the
+controller's log directory, /var/log/juju, is checked, so the command
+is typically run on a controller machine or against a copy of its
+audit log files.
+
+Examples:
+
+    juju verify-audit-log
+    juju verify-audit-log ./controller-0-logs
+    juju verify-audit-log audit-2017-03-07T10-00-00.000.log.gz audit.log
+
+See also:
+    audit-log
+    controller-config
+`
+
+// Info implements Command.Info.
+func (c *verifyAuditLogCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "verify-audit-log",
+		Args:    "[<directory> | <file> ...]",
+		Purpose: "Verifies that a controller's audit log files are intact.",
+		Doc:     strings.TrimSpace(verifyAuditLogHelpDoc),
+	}
+}
+
+// Init implements Command.Init.
+func (c *verifyAuditLogCommand) Init(args []string) error {
+	if len(args) == 0 {
+		c.dir = defaultAuditLogDir
+		return nil
+	}
+	if len(args) == 1 {
+		info, err := os.Stat(args[0])
+		if err == nil && info.IsDir() {
+			c.dir = args[0]
+			return nil
+		}
+	}
+	c.paths = args
+	return nil
+}
+
+// Run implements Command.Run.
+func (c *verifyAuditLogCommand) Run(ctx *cmd.Context) error {
+	paths := c.paths
+	if c.dir != "" {
+		var err error
+		paths, err = audit.LogFiles(ctx.AbsPath(c.dir))
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if len(paths) == 0 {
+			return errors.Errorf("no audit log files found in %s", c.dir)
+		}
+	} else {
+		for i, path := range paths {
+			paths[i] = ctx.AbsPath(path)
+		}
+	}
+
+	result, err := audit.VerifyLogFiles(paths)
+	if audit.IsChainError(err) {
+		ctx.Infof("%d entries verified before tampering was detected", result.Entries)
+		return errors.Annotate(err, "audit log verification failed")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	if result.Unchained > 0 {
+		ctx.Infof("%d entries predate hash chaining and could not be verified", result.Unchained)
+	}
+	ctx.Infof("%d entries in %d files verified", result.Entries, len(paths))
+	return nil
+}
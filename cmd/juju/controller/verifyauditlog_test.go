@@ -0,0 +1,75 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package controller_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/cmd/juju/controller"
+	"github.com/juju/juju/testing"
+)
+
+type VerifyAuditLogSuite struct {
+	gitjujutesting.IsolationSuite
+
+	dir string
+}
+
+var _ = gc.Suite(&VerifyAuditLogSuite{})
+
+func (s *VerifyAuditLogSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dir = c.MkDir()
+	sink := audit.NewLogFileSink(audit.LogFileConfig{LogDir: s.dir})
+	for i := 0; i < 3; i++ {
+		err := sink(audit.AuditEntry{
+			Timestamp:     time.Date(2017, time.March, 7, 10, 0, i, 0, time.UTC),
+			ModelUUID:     testing.ModelTag.Id(),
+			RemoteAddress: "10.0.0.1",
+			OriginType:    "API request",
+			OriginName:    "user-bob",
+			Operation:     "Client:v1 - FullStatus",
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+}
+
+func (s *VerifyAuditLogSuite) TestVerifyDirectory(c *gc.C) {
+	ctx, err := testing.RunCommand(c, controller.NewVerifyAuditLogCommand(), s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "3 entries in 1 files verified\n")
+}
+
+func (s *VerifyAuditLogSuite) TestVerifyFiles(c *gc.C) {
+	path := filepath.Join(s.dir, "audit.log")
+	ctx, err := testing.RunCommand(c, controller.NewVerifyAuditLogCommand(), path)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "3 entries in 1 files verified\n")
+}
+
+func (s *VerifyAuditLogSuite) TestTampered(c *gc.C) {
+	path := filepath.Join(s.dir, "audit.log")
+	content, err := ioutil.ReadFile(path)
+	c.Assert(err, jc.ErrorIsNil)
+	tampered := strings.Replace(string(content), "user-bob", "user-mary", 1)
+	err = ioutil.WriteFile(path, []byte(tampered), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	ctx, err := testing.RunCommand(c, controller.NewVerifyAuditLogCommand(), s.dir)
+	c.Assert(err, gc.ErrorMatches, `audit log verification failed: .*audit.log:1: entry hash mismatch \(entry altered\)`)
+	c.Assert(testing.Stderr(ctx), gc.Equals, "0 entries verified before tampering was detected\n")
+}
+
+func (s *VerifyAuditLogSuite) TestNoFiles(c *gc.C) {
+	_, err := testing.RunCommand(c, controller.NewVerifyAuditLogCommand(), c.MkDir())
+	c.Assert(err, gc.ErrorMatches, `no audit log files found in .*`)
+}
@@ -0,0 +1,190 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/api/modelmanager"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/migration/modelarchive"
+	resourceapi "github.com/juju/juju/resource/api"
+	jujuversion "github.com/juju/juju/version"
+)
+
+// NewCreateBackupCommand returns a command used to back up a single
+// model.
+func NewCreateBackupCommand() cmd.Command {
+	return modelcmd.Wrap(&createBackupCommand{})
+}
+
+// createBackupCommand writes a self-contained archive of a model.
+type createBackupCommand struct {
+	modelcmd.ModelCommandBase
+
+	exportAPI ExportModelAPI
+	source    ModelBinarySource
+
+	filename string
+}
+
+// ExportModelAPI defines the controller API methods used to back up
+// a model.
+type ExportModelAPI interface {
+	Close() error
+	ExportModel(names.ModelTag) (params.SerializedModel, error)
+}
+
+// ModelBinarySource supplies the charms, tools and resources used by
+// the model being backed up.
+type ModelBinarySource interface {
+	modelarchive.Source
+	Close() error
+}
+
+const createBackupDoc = `
+Writes a backup of a single model to a local archive file.
+
+Unlike create-backup, which captures the whole controller, the archive
+holds just the one model: its description, exported in the same way as
+for a model migration, along with the charms, agent binaries and
+resources it uses. The archive can be imported as a new model with
+restore-model-backup, for example to roll a model back after a failed
+charm upgrade.
+
+The archive is written to the file given by --filename, or to a file
+named after the model and the current time.
+
+Examples:
+
+    juju create-model-backup
+    juju create-model-backup -m mymodel --filename mymodel.tar.gz
+
+See also:
+    restore-model-backup
+    create-backup
+    dump-model
+`
+
+// Info implements Command.Info.
+func (c *createBackupCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "create-model-backup",
+		Purpose: "Writes a backup of a single model to a local file.",
+		Doc:     strings.TrimSpace(createBackupDoc),
+	}
+}
+
+// SetFlags implements Command.SetFlags.
+func (c *createBackupCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.filename, "filename", "", "Write the archive to this file")
+}
+
+// Init implements Command.Init.
+func (c *createBackupCommand) Init(args []string) error {
+	return cmd.CheckEmpty(args)
+}
+
+func (c *createBackupCommand) getExportAPI() (ExportModelAPI, error) {
+	if c.exportAPI != nil {
+		return c.exportAPI, nil
+	}
+	root, err := c.NewControllerAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return modelmanager.NewClient(root), nil
+}
+
+func (c *createBackupCommand) getSource() (ModelBinarySource, error) {
+	if c.source != nil {
+		return c.source, nil
+	}
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return binarySource{client}, nil
+}
+
+// Run implements Command.Run.
+func (c *createBackupCommand) Run(ctx *cmd.Context) error {
+	modelDetails, err := c.ClientStore().ModelByName(c.ControllerName(), c.ModelName())
+	if err != nil {
+		return errors.Annotate(err, "getting model details")
+	}
+
+	exportAPI, err := c.getExportAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer exportAPI.Close()
+	serialized, err := exportAPI.ExportModel(names.NewModelTag(modelDetails.ModelUUID))
+	if err != nil {
+		return errors.Annotate(err, "cannot export model")
+	}
+
+	source, err := c.getSource()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer source.Close()
+
+	now := time.Now().UTC()
+	meta := modelarchive.Metadata{
+		ModelUUID:   modelDetails.ModelUUID,
+		ModelName:   c.ModelName(),
+		Created:     now,
+		JujuVersion: jujuversion.Current,
+		Charms:      serialized.Charms,
+		Tools:       serialized.Tools,
+		Resources:   serialized.Resources,
+	}
+	filename := c.filename
+	if filename == "" {
+		name := strings.Replace(c.ModelName(), "/", "-", -1)
+		filename = fmt.Sprintf("juju-model-backup-%s-%s.tar.gz", name, now.Format("20060102-150405"))
+	}
+	filename = ctx.AbsPath(filename)
+
+	archive, err := os.Create(filename)
+	if err != nil {
+		return errors.Annotate(err, "while creating local archive file")
+	}
+	err = modelarchive.Write(archive, meta, serialized.Bytes, source)
+	if closeErr := archive.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		// Don't leave an incomplete archive behind.
+		os.Remove(filename)
+		return errors.Annotate(err, "while writing model backup")
+	}
+
+	// Print the local filename.
+	fmt.Fprintln(ctx.Stdout, filename)
+	return nil
+}
+
+// binarySource supplies a model's binaries through its API connection.
+type binarySource struct {
+	*api.Client
+}
+
+// OpenResource implements modelarchive.Source.
+func (s binarySource) OpenResource(application, name string) (io.ReadCloser, error) {
+	return s.OpenURI(resourceapi.NewEndpointPath(application, name), nil)
+}
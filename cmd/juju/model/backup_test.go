@@ -0,0 +1,152 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/migration/modelarchive"
+	"github.com/juju/juju/testing"
+)
+
+type CreateBackupSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api    fakeExportAPI
+	source fakeBinarySource
+	store  *jujuclienttesting.MemStore
+}
+
+var _ = gc.Suite(&CreateBackupSuite{})
+
+type fakeExportAPI struct {
+	gitjujutesting.Stub
+}
+
+func (f *fakeExportAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeExportAPI) ExportModel(model names.ModelTag) (params.SerializedModel, error) {
+	f.MethodCall(f, "ExportModel", model)
+	return params.SerializedModel{
+		Bytes:  []byte("model-uuid: fake uuid\n"),
+		Charms: []string{"cs:xenial/mysql-1"},
+	}, f.NextErr()
+}
+
+type fakeBinarySource struct {
+	gitjujutesting.Stub
+}
+
+func (f *fakeBinarySource) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeBinarySource) OpenCharm(curl *charm.URL) (io.ReadCloser, error) {
+	f.MethodCall(f, "OpenCharm", curl.String())
+	if err := f.NextErr(); err != nil {
+		return nil, err
+	}
+	return ioutil.NopCloser(bytes.NewBufferString("<charm>")), nil
+}
+
+func (f *fakeBinarySource) OpenURI(uri string, query url.Values) (io.ReadCloser, error) {
+	f.MethodCall(f, "OpenURI", uri)
+	return nil, errors.NotImplementedf("OpenURI")
+}
+
+func (f *fakeBinarySource) OpenResource(application, name string) (io.ReadCloser, error) {
+	f.MethodCall(f, "OpenResource", application, name)
+	return nil, errors.NotImplementedf("OpenResource")
+}
+
+func (s *CreateBackupSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = fakeExportAPI{}
+	s.source = fakeBinarySource{}
+	s.store = jujuclienttesting.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+	err := s.store.UpdateModel("testing", "admin/mymodel", jujuclient.ModelDetails{
+		testing.ModelTag.Id(),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.store.Models["testing"].CurrentModel = "admin/mymodel"
+}
+
+func (s *CreateBackupSuite) run(c *gc.C, args ...string) (*cmd.Context, error) {
+	return testing.RunCommand(c, model.NewCreateBackupCommandForTest(&s.api, &s.source, s.store), args...)
+}
+
+func (s *CreateBackupSuite) TestCreate(c *gc.C) {
+	ctx, err := s.run(c, "--filename", "backup.tar.gz")
+	c.Assert(err, jc.ErrorIsNil)
+	filename := filepath.Join(ctx.Dir, "backup.tar.gz")
+	c.Assert(testing.Stdout(ctx), gc.Equals, filename+"\n")
+	s.api.CheckCalls(c, []gitjujutesting.StubCall{
+		{"ExportModel", []interface{}{testing.ModelTag}},
+		{"Close", nil},
+	})
+	s.source.CheckCalls(c, []gitjujutesting.StubCall{
+		{"OpenCharm", []interface{}{"cs:xenial/mysql-1"}},
+		{"Close", nil},
+	})
+
+	file, err := os.Open(filename)
+	c.Assert(err, jc.ErrorIsNil)
+	defer file.Close()
+	archive, err := modelarchive.Open(file)
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+	c.Check(archive.Metadata.ModelUUID, gc.Equals, testing.ModelTag.Id())
+	c.Check(archive.Metadata.ModelName, gc.Equals, "admin/mymodel")
+	c.Check(archive.Metadata.Charms, jc.DeepEquals, []string{"cs:xenial/mysql-1"})
+	c.Check(string(archive.Model), gc.Equals, "model-uuid: fake uuid\n")
+}
+
+func (s *CreateBackupSuite) TestCreateDefaultFilename(c *gc.C) {
+	ctx, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	filename := strings.TrimSpace(testing.Stdout(ctx))
+	c.Assert(filename, gc.Matches, regexp.QuoteMeta(ctx.Dir)+`/juju-model-backup-admin-mymodel-\d{8}-\d{6}\.tar\.gz`)
+	c.Assert(filename, jc.IsNonEmptyFile)
+}
+
+func (s *CreateBackupSuite) TestCreateExportError(c *gc.C) {
+	s.api.SetErrors(errors.New("boom"))
+	ctx, err := s.run(c, "--filename", "backup.tar.gz")
+	c.Assert(err, gc.ErrorMatches, "cannot export model: boom")
+	c.Assert(filepath.Join(ctx.Dir, "backup.tar.gz"), jc.DoesNotExist)
+}
+
+func (s *CreateBackupSuite) TestCreateRemovesIncompleteArchive(c *gc.C) {
+	s.source.SetErrors(errors.New("boom"))
+	ctx, err := s.run(c, "--filename", "backup.tar.gz")
+	c.Assert(err, gc.ErrorMatches, "while writing model backup: cannot archive charm cs:xenial/mysql-1: boom")
+	c.Assert(filepath.Join(ctx.Dir, "backup.tar.gz"), jc.DoesNotExist)
+}
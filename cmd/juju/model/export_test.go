@@ -12,6 +12,7 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/cmd/modelcmd"
 	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/migration"
 )
 
 // NewConfigCommandForTest returns a configCommand with the api
@@ -107,3 +108,23 @@ func NewRevokeCommandForTest(api RevokeModelAPI, store jujuclient.ClientStore) (
 	cmd.SetClientStore(store)
 	return modelcmd.WrapController(cmd), &RevokeCommand{cmd}
 }
+
+// NewCreateBackupCommandForTest returns a createBackupCommand with the
+// api and binary source provided as specified.
+func NewCreateBackupCommandForTest(api ExportModelAPI, source ModelBinarySource, store jujuclient.ClientStore) cmd.Command {
+	cmd := &createBackupCommand{exportAPI: api, source: source}
+	cmd.SetClientStore(store)
+	return modelcmd.Wrap(cmd)
+}
+
+// NewRestoreBackupCommandForTest returns a restoreBackupCommand with
+// the api and binary uploading function provided as specified.
+func NewRestoreBackupCommandForTest(
+	api RestoreModelAPI,
+	uploadBinaries func(migration.UploadBinariesConfig) error,
+	store jujuclient.ClientStore,
+) cmd.Command {
+	cmd := &restoreBackupCommand{api: api, uploadBinaries: uploadBinaries}
+	cmd.SetClientStore(store)
+	return modelcmd.WrapController(cmd)
+}
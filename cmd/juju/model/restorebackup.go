@@ -0,0 +1,228 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model
+
+import (
+	"os"
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/api"
+	apicommon "github.com/juju/juju/api/common"
+	"github.com/juju/juju/api/migrationtarget"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/migration/modelarchive"
+)
+
+// NewRestoreBackupCommand returns a command used to restore a model
+// from a model backup.
+func NewRestoreBackupCommand() cmd.Command {
+	return modelcmd.WrapController(&restoreBackupCommand{
+		uploadBinaries: migration.UploadBinaries,
+	})
+}
+
+// restoreBackupCommand imports a model backup as a new model.
+type restoreBackupCommand struct {
+	modelcmd.ControllerCommandBase
+
+	api            RestoreModelAPI
+	uploadBinaries func(migration.UploadBinariesConfig) error
+
+	filename string
+}
+
+// RestoreModelAPI defines the controller API methods used to restore
+// a model backup. They are the ones a migration target provides.
+type RestoreModelAPI interface {
+	Close() error
+	Import(bytes []byte) error
+	Activate(modelUUID string) error
+	Abort(modelUUID string) error
+	NewUploader(modelUUID string) ModelUploader
+}
+
+// ModelUploader sends a restored model's binaries to the controller.
+type ModelUploader interface {
+	migration.CharmUploader
+	migration.ToolsUploader
+	migration.ResourceUploader
+}
+
+const restoreBackupDoc = `
+Imports a model backup, written by create-model-backup, into the
+controller as a new model.
+
+The model is imported in the same way as for a model migration. It
+keeps the UUID, name and owner it had when it was backed up, so any
+model with the same UUID must have been removed from the controller
+first. The charms, agent binaries and resources stored in the backup
+are then uploaded, and the model is activated. If any step fails, or
+the command is interrupted, the partly imported model is removed
+again.
+
+The restored model's machines refer to the cloud instances that the
+original model's machines ran on; no instances are created. The
+original model must therefore have been destroyed, with all of its
+machines, before its backup is restored, on this controller or any
+other, or both models would manage the same instances. Restored
+machines whose instances no longer exist must be removed with
+"juju remove-machine --force", and their units deployed again.
+
+Restoring a model backup requires superuser access to the controller.
+
+Examples:
+
+    juju restore-model-backup juju-model-backup-admin-mymodel-20170501-120000.tar.gz
+    juju restore-model-backup -c mycontroller mymodel.tar.gz
+
+See also:
+    create-model-backup
+    restore-backup
+`
+
+// Info implements Command.Info.
+func (c *restoreBackupCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "restore-model-backup",
+		Args:    "<file>",
+		Purpose: "Imports a model backup as a new model.",
+		Doc:     strings.TrimSpace(restoreBackupDoc),
+	}
+}
+
+// Init implements Command.Init.
+func (c *restoreBackupCommand) Init(args []string) error {
+	if len(args) == 0 {
+		return errors.New("missing model backup file")
+	}
+	filename, args := args[0], args[1:]
+	if err := cmd.CheckEmpty(args); err != nil {
+		return errors.Trace(err)
+	}
+	c.filename = filename
+	return nil
+}
+
+func (c *restoreBackupCommand) getAPI() (RestoreModelAPI, error) {
+	if c.api != nil {
+		return c.api, nil
+	}
+	root, err := c.NewAPIRoot()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return restoreAPI{migrationtarget.NewClient(root), root}, nil
+}
+
+// Run implements Command.Run.
+func (c *restoreBackupCommand) Run(ctx *cmd.Context) error {
+	file, err := os.Open(ctx.AbsPath(c.filename))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer file.Close()
+	archive, err := modelarchive.Open(file)
+	if err != nil {
+		return errors.Annotate(err, "cannot read model backup")
+	}
+	defer archive.Close()
+	serialized, err := apicommon.ConvertSerializedModel(archive.SerializedModel())
+	if err != nil {
+		return errors.Annotate(err, "cannot read model backup")
+	}
+	modelUUID := archive.Metadata.ModelUUID
+
+	client, err := c.getAPI()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	// Once the import has started, an interrupt removes the partly
+	// imported model rather than leaving it behind.
+	interrupted := make(chan os.Signal, 1)
+	ctx.InterruptNotify(interrupted)
+	defer ctx.StopInterruptNotify(interrupted)
+
+	ctx.Infof("importing model %q", archive.Metadata.ModelName)
+	if err := client.Import(serialized.Bytes); err != nil {
+		return errors.Annotate(err, "cannot import model")
+	}
+	select {
+	case <-interrupted:
+		abortImport(client, modelUUID)
+		return errInterrupted
+	default:
+	}
+
+	ctx.Infof("uploading model binaries")
+	uploader := client.NewUploader(modelUUID)
+	uploaded := make(chan error, 1)
+	go func() {
+		uploaded <- c.uploadBinaries(migration.UploadBinariesConfig{
+			Charms:          serialized.Charms,
+			CharmDownloader: archive,
+			CharmUploader:   uploader,
+
+			Tools:           serialized.Tools,
+			ToolsDownloader: archive,
+			ToolsUploader:   uploader,
+
+			Resources:          serialized.Resources,
+			ResourceDownloader: archive,
+			ResourceUploader:   uploader,
+		})
+	}()
+	select {
+	case <-interrupted:
+		// The upload fails once the model is removed and the
+		// connection closed.
+		abortImport(client, modelUUID)
+		return errInterrupted
+	case err := <-uploaded:
+		if err != nil {
+			abortImport(client, modelUUID)
+			return errors.Annotate(err, "cannot upload model binaries")
+		}
+	}
+
+	if err := client.Activate(modelUUID); err != nil {
+		abortImport(client, modelUUID)
+		return errors.Annotate(err, "cannot activate model")
+	}
+	ctx.Infof("model %q restored", archive.Metadata.ModelName)
+	return nil
+}
+
+// errInterrupted is returned when the command is interrupted while
+// restoring a model.
+var errInterrupted = errors.New("interrupted; partly imported model removed")
+
+// abortImport removes a partly imported model.
+func abortImport(client RestoreModelAPI, modelUUID string) {
+	if err := client.Abort(modelUUID); err != nil {
+		logger.Errorf("cannot remove partly imported model: %v", err)
+	}
+}
+
+// restoreAPI adapts the migration target client for restoring a
+// model backup.
+type restoreAPI struct {
+	*migrationtarget.Client
+	conn api.Connection
+}
+
+// Close implements RestoreModelAPI.
+func (a restoreAPI) Close() error {
+	return a.conn.Close()
+}
+
+// NewUploader implements RestoreModelAPI.
+func (a restoreAPI) NewUploader(modelUUID string) ModelUploader {
+	return migrationtarget.NewUploader(a.Client, modelUUID)
+}
@@ -0,0 +1,189 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package model_test
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	gitjujutesting "github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/juju/model"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/migration"
+	"github.com/juju/juju/migration/modelarchive"
+	"github.com/juju/juju/testing"
+)
+
+type RestoreBackupSuite struct {
+	testing.FakeJujuXDGDataHomeSuite
+	api      fakeRestoreAPI
+	store    *jujuclienttesting.MemStore
+	uploaded *migration.UploadBinariesConfig
+	filename string
+}
+
+var _ = gc.Suite(&RestoreBackupSuite{})
+
+type fakeRestoreAPI struct {
+	gitjujutesting.Stub
+}
+
+// fakeUploader is returned by fakeRestoreAPI.NewUploader; the
+// uploading itself is done by the patched UploadBinaries.
+type fakeUploader struct {
+	model.ModelUploader
+}
+
+func (f *fakeRestoreAPI) Close() error {
+	f.MethodCall(f, "Close")
+	return f.NextErr()
+}
+
+func (f *fakeRestoreAPI) Import(bytes []byte) error {
+	f.MethodCall(f, "Import", string(bytes))
+	return f.NextErr()
+}
+
+func (f *fakeRestoreAPI) Activate(modelUUID string) error {
+	f.MethodCall(f, "Activate", modelUUID)
+	return f.NextErr()
+}
+
+func (f *fakeRestoreAPI) Abort(modelUUID string) error {
+	f.MethodCall(f, "Abort", modelUUID)
+	return f.NextErr()
+}
+
+func (f *fakeRestoreAPI) NewUploader(modelUUID string) model.ModelUploader {
+	f.MethodCall(f, "NewUploader", modelUUID)
+	return fakeUploader{}
+}
+
+func (s *RestoreBackupSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.api = fakeRestoreAPI{}
+	s.uploaded = nil
+	s.store = jujuclienttesting.NewMemStore()
+	s.store.CurrentControllerName = "testing"
+	s.store.Controllers["testing"] = jujuclient.ControllerDetails{}
+	s.store.Accounts["testing"] = jujuclient.AccountDetails{
+		User: "admin",
+	}
+
+	s.filename = filepath.Join(c.MkDir(), "backup.tar.gz")
+	file, err := os.Create(s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+	defer file.Close()
+	err = modelarchive.Write(file, modelarchive.Metadata{
+		ModelUUID: testing.ModelTag.Id(),
+		ModelName: "admin/mymodel",
+		Charms:    []string{"cs:xenial/mysql-1"},
+	}, []byte("model-uuid: fake uuid\n"), &fakeBinarySource{})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *RestoreBackupSuite) run(c *gc.C, uploadErr error, args ...string) (*cmd.Context, error) {
+	uploadBinaries := func(config migration.UploadBinariesConfig) error {
+		s.uploaded = &config
+		return uploadErr
+	}
+	command := model.NewRestoreBackupCommandForTest(&s.api, uploadBinaries, s.store)
+	return testing.RunCommand(c, command, args...)
+}
+
+func (s *RestoreBackupSuite) TestInitMissingFile(c *gc.C) {
+	_, err := s.run(c, nil)
+	c.Assert(err, gc.ErrorMatches, "missing model backup file")
+}
+
+func (s *RestoreBackupSuite) TestRestore(c *gc.C) {
+	ctx, err := s.run(c, nil, s.filename)
+	c.Assert(err, jc.ErrorIsNil)
+	s.api.CheckCalls(c, []gitjujutesting.StubCall{
+		{"Import", []interface{}{"model-uuid: fake uuid\n"}},
+		{"NewUploader", []interface{}{testing.ModelTag.Id()}},
+		{"Activate", []interface{}{testing.ModelTag.Id()}},
+		{"Close", nil},
+	})
+	c.Check(testing.Stderr(ctx), gc.Equals, ""+
+		"importing model \"admin/mymodel\"\n"+
+		"uploading model binaries\n"+
+		"model \"admin/mymodel\" restored\n")
+
+	c.Assert(s.uploaded, gc.NotNil)
+	c.Check(s.uploaded.Charms, jc.DeepEquals, []string{"cs:xenial/mysql-1"})
+	c.Check(s.uploaded.CharmUploader, gc.Equals, fakeUploader{})
+	archive, ok := s.uploaded.CharmDownloader.(*modelarchive.Archive)
+	c.Assert(ok, jc.IsTrue)
+	c.Check(s.uploaded.ToolsDownloader, gc.Equals, archive)
+	c.Check(s.uploaded.ResourceDownloader, gc.Equals, archive)
+}
+
+func (s *RestoreBackupSuite) TestRestoreImportFails(c *gc.C) {
+	s.api.SetErrors(errors.New("model with UUID deadbeef already exists"))
+	_, err := s.run(c, nil, s.filename)
+	c.Assert(err, gc.ErrorMatches, "cannot import model: model with UUID deadbeef already exists")
+	c.Check(s.uploaded, gc.IsNil)
+	s.api.CheckCallNames(c, "Import", "Close")
+}
+
+func (s *RestoreBackupSuite) TestRestoreUploadFails(c *gc.C) {
+	_, err := s.run(c, errors.New("boom"), s.filename)
+	c.Assert(err, gc.ErrorMatches, "cannot upload model binaries: boom")
+	s.api.CheckCalls(c, []gitjujutesting.StubCall{
+		{"Import", []interface{}{"model-uuid: fake uuid\n"}},
+		{"NewUploader", []interface{}{testing.ModelTag.Id()}},
+		{"Abort", []interface{}{testing.ModelTag.Id()}},
+		{"Close", nil},
+	})
+}
+
+func (s *RestoreBackupSuite) TestRestoreInterrupted(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("sending an interrupt is not supported on windows")
+	}
+	unblock := make(chan struct{})
+	defer close(unblock)
+	uploadBinaries := func(migration.UploadBinariesConfig) error {
+		proc, err := os.FindProcess(os.Getpid())
+		c.Check(err, jc.ErrorIsNil)
+		defer proc.Release()
+		c.Check(proc.Signal(os.Interrupt), jc.ErrorIsNil)
+		<-unblock
+		return nil
+	}
+	command := model.NewRestoreBackupCommandForTest(&s.api, uploadBinaries, s.store)
+	_, err := testing.RunCommand(c, command, s.filename)
+	c.Assert(err, gc.ErrorMatches, "interrupted; partly imported model removed")
+	s.api.CheckCalls(c, []gitjujutesting.StubCall{
+		{"Import", []interface{}{"model-uuid: fake uuid\n"}},
+		{"NewUploader", []interface{}{testing.ModelTag.Id()}},
+		{"Abort", []interface{}{testing.ModelTag.Id()}},
+		{"Close", nil},
+	})
+}
+
+func (s *RestoreBackupSuite) TestRestoreActivateFails(c *gc.C) {
+	s.api.SetErrors(nil, errors.New("boom"))
+	_, err := s.run(c, nil, s.filename)
+	c.Assert(err, gc.ErrorMatches, "cannot activate model: boom")
+	s.api.CheckCallNames(c, "Import", "NewUploader", "Activate", "Abort", "Close")
+}
+
+func (s *RestoreBackupSuite) TestRestoreNotAnArchive(c *gc.C) {
+	filename := filepath.Join(c.MkDir(), "junk")
+	err := ioutil.WriteFile(filename, []byte("junk"), 0600)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.run(c, nil, filename)
+	c.Assert(err, gc.ErrorMatches, "cannot read model backup: while unpacking archive: .*")
+	c.Check(s.api.Calls(), gc.HasLen, 0)
+}
@@ -0,0 +1,265 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/status"
+)
+
+type exportStatusLogAPI interface {
+	QueryStatusHistory(status.HistoryQuery) ([]status.HistoryEntry, string, error)
+	AggregateStatusHistory(status.HistoryQuery) ([]status.HistoryAggregate, error)
+	Close() error
+}
+
+// NewExportStatusLogCommand returns a command that exports the status
+// history of the units and machines in a model.
+func NewExportStatusLogCommand() cmd.Command {
+	return modelcmd.Wrap(&exportStatusLogCommand{})
+}
+
+type exportStatusLogCommand struct {
+	modelcmd.ModelCommandBase
+	out cmd.Output
+
+	application          string
+	fromDate             string
+	toDate               string
+	aggregate            bool
+	includeStatusUpdates bool
+
+	query status.HistoryQuery
+}
+
+var exportStatusLogDoc = `
+Exports the status history of every unit and machine in the model, or of
+the units of the given application, oldest first. Each entry records the
+time, the entity, the type of status (see show-status-log) and the status
+and message it changed to.
+
+With --aggregate, a summary of each entity's history is exported instead:
+the number of status transitions, the number of transitions into the error
+status and the time spent in each status. The last status of each entity is
+counted up to the --to-date, or the current time.
+
+Dates may be given as YYYY-MM-DD or in RFC3339 format. A --to-date given as
+YYYY-MM-DD includes the whole of that day.
+
+The update-status hook messages are excluded unless --include-status-updates
+is given.
+
+Examples:
+    juju export-status-log > history.csv
+    juju export-status-log mysql --from-date 2017-05-01 --format json
+    juju export-status-log --aggregate --from-date 2017-05-01 --to-date 2017-05-31
+
+See also:
+    show-status-log
+`
+
+func (c *exportStatusLogCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "export-status-log",
+		Args:    "[<application>]",
+		Purpose: "Exports the status history of the model or an application.",
+		Doc:     exportStatusLogDoc,
+	}
+}
+
+func (c *exportStatusLogCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.fromDate, "from-date", "", "Exclude entries before this date")
+	f.StringVar(&c.toDate, "to-date", "", "Exclude entries after this date")
+	f.BoolVar(&c.aggregate, "aggregate", false, "Export the time spent in each status and the transition counts for each entity")
+	f.BoolVar(&c.includeStatusUpdates, "include-status-updates", false, "Include update status hook messages in the exported entries")
+	c.out.AddFlags(f, "csv", map[string]cmd.Formatter{
+		"yaml": cmd.FormatYaml,
+		"json": cmd.FormatJson,
+		"csv":  formatStatusLogCSV,
+	})
+}
+
+func (c *exportStatusLogCommand) Init(args []string) error {
+	switch len(args) {
+	case 0:
+	case 1:
+		c.application = args[0]
+		if !names.IsValidApplication(c.application) {
+			return errors.NotValidf("application name %q", c.application)
+		}
+	default:
+		return errors.New("unexpected arguments after application name")
+	}
+	c.query = status.HistoryQuery{Application: c.application}
+	if c.fromDate != "" {
+		from, _, err := parseStatusLogDate(c.fromDate)
+		if err != nil {
+			return errors.Annotate(err, "parsing --from-date")
+		}
+		c.query.FromDate = &from
+	}
+	if c.toDate != "" {
+		to, dateOnly, err := parseStatusLogDate(c.toDate)
+		if err != nil {
+			return errors.Annotate(err, "parsing --to-date")
+		}
+		if dateOnly {
+			to = to.Add(24*time.Hour - time.Nanosecond)
+		}
+		c.query.ToDate = &to
+	}
+	if !c.includeStatusUpdates {
+		c.query.Exclude = set.NewStrings(runningHookMSG)
+	}
+	return c.query.Validate()
+}
+
+// parseStatusLogDate parses a date in YYYY-MM-DD or RFC3339 format,
+// and reports whether it was a date without a time.
+func parseStatusLogDate(value string) (time.Time, bool, error) {
+	if t, err := time.Parse("2006-01-02", value); err == nil {
+		return t, true, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, false, errors.Errorf("expected YYYY-MM-DD or RFC3339 date, got %q", value)
+	}
+	return t, false, nil
+}
+
+var newAPIClientForExportStatusLog = func(c *exportStatusLogCommand) (exportStatusLogAPI, error) {
+	return c.NewAPIClient()
+}
+
+func (c *exportStatusLogCommand) Run(ctx *cmd.Context) error {
+	client, err := newAPIClientForExportStatusLog(c)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	if c.aggregate {
+		aggregates, err := client.AggregateStatusHistory(c.query)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		result := make([]statusLogAggregate, len(aggregates))
+		for i, aggregate := range aggregates {
+			seconds := make(map[string]float64)
+			for s, d := range aggregate.Durations {
+				seconds[string(s)] = d.Seconds()
+			}
+			result[i] = statusLogAggregate{
+				Entity:           aggregate.Entity,
+				Type:             string(aggregate.Kind),
+				Transitions:      aggregate.Transitions,
+				ErrorTransitions: aggregate.ErrorTransitions,
+				Seconds:          seconds,
+			}
+		}
+		return c.out.Write(ctx, result)
+	}
+
+	var result []statusLogEntry
+	query := c.query
+	for {
+		entries, next, err := client.QueryStatusHistory(query)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		for _, entry := range entries {
+			result = append(result, statusLogEntry{
+				Time:    entry.Since.UTC(),
+				Entity:  entry.Entity,
+				Type:    string(entry.Kind),
+				Status:  string(entry.Status),
+				Message: entry.Message,
+			})
+		}
+		if next == "" {
+			break
+		}
+		query.After = next
+	}
+	return c.out.Write(ctx, result)
+}
+
+type statusLogEntry struct {
+	Time    time.Time `json:"time" yaml:"time"`
+	Entity  string    `json:"entity" yaml:"entity"`
+	Type    string    `json:"type" yaml:"type"`
+	Status  string    `json:"status" yaml:"status"`
+	Message string    `json:"message,omitempty" yaml:"message,omitempty"`
+}
+
+type statusLogAggregate struct {
+	Entity           string `json:"entity" yaml:"entity"`
+	Type             string `json:"type" yaml:"type"`
+	Transitions      int    `json:"transitions" yaml:"transitions"`
+	ErrorTransitions int    `json:"error-transitions" yaml:"error-transitions"`
+	// Seconds holds the time spent in each status.
+	Seconds map[string]float64 `json:"seconds" yaml:"seconds"`
+}
+
+// formatStatusLogCSV writes status log entries or aggregates as CSV,
+// with a header row. Aggregates have a column for each status that
+// any entity spent time in.
+func formatStatusLogCSV(writer io.Writer, value interface{}) error {
+	w := csv.NewWriter(writer)
+	switch value := value.(type) {
+	case []statusLogEntry:
+		w.Write([]string{"TIME", "ENTITY", "TYPE", "STATUS", "MESSAGE"})
+		for _, entry := range value {
+			w.Write([]string{
+				entry.Time.Format(time.RFC3339),
+				entry.Entity,
+				entry.Type,
+				entry.Status,
+				entry.Message,
+			})
+		}
+	case []statusLogAggregate:
+		statuses := set.NewStrings()
+		for _, aggregate := range value {
+			for s := range aggregate.Seconds {
+				statuses.Add(s)
+			}
+		}
+		columns := statuses.SortedValues()
+		header := []string{"ENTITY", "TYPE", "TRANSITIONS", "ERROR-TRANSITIONS"}
+		for _, s := range columns {
+			header = append(header, strings.ToUpper(s)+"-SECONDS")
+		}
+		w.Write(header)
+		for _, aggregate := range value {
+			row := []string{
+				aggregate.Entity,
+				aggregate.Type,
+				strconv.Itoa(aggregate.Transitions),
+				strconv.Itoa(aggregate.ErrorTransitions),
+			}
+			for _, s := range columns {
+				row = append(row, strconv.FormatFloat(aggregate.Seconds[s], 'f', -1, 64))
+			}
+			w.Write(row)
+		}
+	default:
+		return errors.Errorf("expected status log entries or aggregates, got %T", value)
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,186 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ExportStatusLogSuite struct {
+	coretesting.FakeJujuXDGDataHomeSuite
+	client *fakeExportStatusLogClient
+}
+
+var _ = gc.Suite(&ExportStatusLogSuite{})
+
+type fakeExportStatusLogClient struct {
+	query      status.HistoryQuery
+	afters     []string
+	entries    []status.HistoryEntry
+	aggregates []status.HistoryAggregate
+}
+
+func (f *fakeExportStatusLogClient) QueryStatusHistory(query status.HistoryQuery) ([]status.HistoryEntry, string, error) {
+	f.query = query
+	f.afters = append(f.afters, query.After)
+	// Return the entries one page at a time.
+	entries := f.entries
+	for i, entry := range entries {
+		if entry.Id == query.After {
+			entries = entries[i+1:]
+			break
+		}
+	}
+	if len(entries) > 1 {
+		return entries[:1], entries[0].Id, nil
+	}
+	return entries, "", nil
+}
+
+func (f *fakeExportStatusLogClient) AggregateStatusHistory(query status.HistoryQuery) ([]status.HistoryAggregate, error) {
+	f.query = query
+	return f.aggregates, nil
+}
+
+func (f *fakeExportStatusLogClient) Close() error {
+	return nil
+}
+
+func (s *ExportStatusLogSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	start := time.Date(2017, 5, 1, 10, 0, 0, 0, time.UTC)
+	s.client = &fakeExportStatusLogClient{
+		entries: []status.HistoryEntry{
+			{Id: "1", Entity: "mysql/0", Kind: status.KindWorkload, Status: status.Maintenance, Message: "installing, please wait", Since: start},
+			{Id: "2", Entity: "mysql/0", Kind: status.KindWorkload, Status: status.Active, Since: start.Add(time.Minute)},
+		},
+		aggregates: []status.HistoryAggregate{{
+			Entity: "0",
+			Kind:   status.KindMachine,
+			Durations: map[status.Status]time.Duration{
+				status.Running: time.Hour,
+			},
+		}, {
+			Entity: "mysql/0",
+			Kind:   status.KindWorkload,
+			Durations: map[status.Status]time.Duration{
+				status.Error:  90 * time.Second,
+				status.Active: time.Hour,
+			},
+			Transitions:      2,
+			ErrorTransitions: 1,
+		}},
+	}
+	s.PatchValue(&newAPIClientForExportStatusLog, func(_ *exportStatusLogCommand) (exportStatusLogAPI, error) {
+		return s.client, nil
+	})
+}
+
+func (s *ExportStatusLogSuite) run(c *gc.C, args ...string) (string, error) {
+	command := modelcmd.Wrap(&exportStatusLogCommand{})
+	command.SetClientStore(newTestStore(c))
+	ctx, err := coretesting.RunCommand(c, command, args...)
+	if err != nil {
+		return "", err
+	}
+	return coretesting.Stdout(ctx), nil
+}
+
+func (s *ExportStatusLogSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"mysql/0"},
+		err:  `application name "mysql/0" not valid`,
+	}, {
+		args: []string{"mysql", "wordpress"},
+		err:  "unexpected arguments after application name",
+	}, {
+		args: []string{"--from-date", "yesterday"},
+		err:  `parsing --from-date: expected YYYY-MM-DD or RFC3339 date, got "yesterday"`,
+	}, {
+		args: []string{"--from-date", "2017-05-02", "--to-date", "2017-05-01"},
+		err:  "ToDate before FromDate not valid",
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := modelcmd.Wrap(&exportStatusLogCommand{})
+		command.SetClientStore(newTestStore(c))
+		err := coretesting.InitCommand(command, test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *ExportStatusLogSuite) TestQuery(c *gc.C) {
+	_, err := s.run(c, "mysql", "--from-date", "2017-05-01", "--to-date", "2017-05-01")
+	c.Assert(err, jc.ErrorIsNil)
+	from := time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24*time.Hour - time.Nanosecond)
+	c.Assert(s.client.query.Application, gc.Equals, "mysql")
+	c.Assert(*s.client.query.FromDate, gc.Equals, from)
+	c.Assert(*s.client.query.ToDate, gc.Equals, to)
+	c.Assert(s.client.query.Exclude.Values(), jc.DeepEquals, []string{runningHookMSG})
+
+	_, err = s.run(c, "--from-date", "2017-05-01T12:00:00Z", "--include-status-updates")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(*s.client.query.FromDate, gc.Equals, from.Add(12*time.Hour))
+	c.Assert(s.client.query.ToDate, gc.IsNil)
+	c.Assert(s.client.query.Exclude.IsEmpty(), jc.IsTrue)
+}
+
+func (s *ExportStatusLogSuite) TestEntriesCSV(c *gc.C) {
+	out, err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+	// The entries are read a page at a time.
+	c.Assert(s.client.afters, jc.DeepEquals, []string{"", "1"})
+	c.Assert(out, gc.Equals, ""+
+		"TIME,ENTITY,TYPE,STATUS,MESSAGE\n"+
+		`2017-05-01T10:00:00Z,mysql/0,workload,maintenance,"installing, please wait"`+"\n"+
+		"2017-05-01T10:01:00Z,mysql/0,workload,active,\n")
+}
+
+func (s *ExportStatusLogSuite) TestEntriesJSON(c *gc.C) {
+	out, err := s.run(c, "--format", "json")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `[`+
+		`{"time":"2017-05-01T10:00:00Z","entity":"mysql/0","type":"workload","status":"maintenance","message":"installing, please wait"},`+
+		`{"time":"2017-05-01T10:01:00Z","entity":"mysql/0","type":"workload","status":"active"}`+
+		"]\n")
+}
+
+func (s *ExportStatusLogSuite) TestAggregateCSV(c *gc.C) {
+	out, err := s.run(c, "--aggregate")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, ""+
+		"ENTITY,TYPE,TRANSITIONS,ERROR-TRANSITIONS,ACTIVE-SECONDS,ERROR-SECONDS,RUNNING-SECONDS\n"+
+		"0,machine,0,0,0,0,3600\n"+
+		"mysql/0,workload,2,1,3600,90,0\n")
+}
+
+func (s *ExportStatusLogSuite) TestAggregateYAML(c *gc.C) {
+	out, err := s.run(c, "--aggregate", "--format", "yaml")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(out, gc.Equals, `
+- entity: "0"
+  type: machine
+  transitions: 0
+  error-transitions: 0
+  seconds:
+    running: 3600
+- entity: mysql/0
+  type: workload
+  transitions: 2
+  error-transitions: 1
+  seconds:
+    active: 3600
+    error: 90
+`[1:])
+}
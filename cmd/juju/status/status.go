@@ -23,6 +23,7 @@ var logger = loggo.GetLogger("juju.cmd.juju.status")
 
 type statusAPI interface {
 	Status(patterns []string) (*params.FullStatus, error)
+	WatchAll() (statusWatcher, error)
 	Close() error
 }
 
@@ -40,6 +41,9 @@ type statusCommand struct {
 	api      statusAPI
 
 	color bool
+
+	watch       bool
+	changesOnly bool
 }
 
 var usageSummary = `
@@ -73,10 +77,22 @@ The available output formats are:
 - json: Displays information about the model, machines, applications, and units
       in structured JSON format.
 
+With --watch, the status is displayed and then followed: changes to
+machines, applications and units reported by the controller are applied
+to it, and it is displayed again after each set of changes, until the
+command is interrupted. With --watch-changes-only, just a line for each
+change is displayed after the initial status, for example:
+
+    unit mysql/1 workload: maintenance -> active
+
+While watching, relations are shown as they were when the command started.
+
 Examples:
     juju show-status
     juju show-status mysql
     juju show-status nova-*
+    juju show-status --watch
+    juju show-status --watch-changes-only mysql
 
 See also:
     machines
@@ -99,6 +115,8 @@ func (c *statusCommand) SetFlags(f *gnuflag.FlagSet) {
 	c.ModelCommandBase.SetFlags(f)
 	f.BoolVar(&c.isoTime, "utc", false, "Display time as UTC in RFC3339 format")
 	f.BoolVar(&c.color, "color", false, "Force use of ANSI color codes")
+	f.BoolVar(&c.watch, "watch", false, "Display the status again whenever it changes")
+	f.BoolVar(&c.changesOnly, "watch-changes-only", false, "Display each change to the status as it happens")
 
 	defaultFormat := "tabular"
 
@@ -115,6 +133,9 @@ func (c *statusCommand) SetFlags(f *gnuflag.FlagSet) {
 
 func (c *statusCommand) Init(args []string) error {
 	c.patterns = args
+	if c.changesOnly {
+		c.watch = true
+	}
 	// If use of ISO time not specified on command line,
 	// check env var.
 	if !c.isoTime {
@@ -130,7 +151,11 @@ func (c *statusCommand) Init(args []string) error {
 }
 
 var newAPIClientForStatus = func(c *statusCommand) (statusAPI, error) {
-	return c.NewAPIClient()
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return statusClient{client}, nil
 }
 
 func (c *statusCommand) Run(ctx *cmd.Context) error {
@@ -152,6 +177,16 @@ func (c *statusCommand) Run(ctx *cmd.Context) error {
 		return errors.Errorf("unable to obtain the current status")
 	}
 
+	if err := c.write(ctx, status); err != nil {
+		return err
+	}
+	if !c.watch {
+		return nil
+	}
+	return c.watchStatus(ctx, apiclient, status)
+}
+
+func (c *statusCommand) write(ctx *cmd.Context, status *params.FullStatus) error {
 	formatter := newStatusFormatter(status, c.ControllerName(), c.isoTime)
 	formatted, err := formatter.format()
 	if err != nil {
@@ -160,6 +195,39 @@ func (c *statusCommand) Run(ctx *cmd.Context) error {
 	return c.out.Write(ctx, formatted)
 }
 
+// watchStatus follows changes to the model, applying them to the
+// given status and writing it out again, or writing out just the
+// changes, until the watcher fails.
+func (c *statusCommand) watchStatus(ctx *cmd.Context, apiclient statusAPI, status *params.FullStatus) error {
+	watcher, err := apiclient.WatchAll()
+	if err != nil {
+		return errors.Annotate(err, "cannot watch status")
+	}
+	defer watcher.Stop()
+
+	tracker := newStatusTracker(status, len(c.patterns) > 0)
+	for {
+		deltas, err := watcher.Next()
+		if err != nil {
+			return errors.Annotate(err, "watching status")
+		}
+		changes, updated := tracker.apply(deltas)
+		if c.changesOnly {
+			for _, change := range changes {
+				fmt.Fprintln(ctx.Stdout, change)
+			}
+			continue
+		}
+		if !updated {
+			continue
+		}
+		fmt.Fprintln(ctx.Stdout)
+		if err := c.write(ctx, tracker.status); err != nil {
+			return err
+		}
+	}
+}
+
 func (c *statusCommand) FormatTabular(writer io.Writer, value interface{}) error {
 	return FormatTabular(writer, c.color, value)
 }
@@ -14,6 +14,7 @@ import (
 	"time"
 
 	"github.com/juju/cmd"
+	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils"
 	"github.com/juju/version"
@@ -3708,6 +3709,10 @@ func (a *fakeAPIClient) Status(patterns []string) (*params.FullStatus, error) {
 	return a.statusReturn, nil
 }
 
+func (a *fakeAPIClient) WatchAll() (statusWatcher, error) {
+	return nil, errors.NotSupportedf("WatchAll")
+}
+
 func (a *fakeAPIClient) Close() error {
 	a.closeCalled = true
 	return nil
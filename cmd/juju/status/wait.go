@@ -0,0 +1,341 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	"github.com/juju/gnuflag"
+	"github.com/juju/utils/clock"
+	"github.com/juju/utils/set"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/status"
+)
+
+type waitAPI interface {
+	WatchAll() (statusWatcher, error)
+	Close() error
+}
+
+// NewWaitCommand returns a command that blocks until the model, or
+// the given applications, units and machines, reach a target state.
+func NewWaitCommand() cmd.Command {
+	return modelcmd.Wrap(&waitCommand{clock: clock.WallClock})
+}
+
+type waitCommand struct {
+	modelcmd.ModelCommandBase
+	clock clock.Clock
+
+	applications set.Strings
+	units        set.Strings
+	machines     set.Strings
+
+	workload string
+	agent    string
+	idleFor  time.Duration
+	timeout  time.Duration
+}
+
+var waitDoc = `
+Waits until the model, or the given applications, units and machines,
+reach a target state, and then exits. This is intended for scripts that
+need a deployment to settle before continuing.
+
+With no arguments, every unit in the model must reach the target
+workload and agent statuses, which are active and idle by default, and
+every machine must be started. Application and unit names restrict the
+units that are checked, and machine ids restrict the machines. A named
+entity that does not exist yet is waited for.
+
+With --idle-for, the target state must also hold, with no changes to
+the units and machines being waited for, for the given duration; use
+this to wait until no hooks have run for a while.
+
+The command fails as soon as a unit being waited for reports an error
+workload status or a failed agent status, or a machine being waited
+for reports an error status, unless that status is the target. It also
+fails if the target state is not reached within the --timeout duration.
+
+Examples:
+    juju wait
+    juju wait --timeout 30m
+    juju wait mysql wordpress/0 --idle-for 1m
+    juju wait mysql --workload blocked
+    juju wait 0 1 0/lxd/0
+
+See also:
+    show-status
+`
+
+func (c *waitCommand) Info() *cmd.Info {
+	return &cmd.Info{
+		Name:    "wait",
+		Args:    "[<application>|<unit>|<machine> ...]",
+		Purpose: "Waits until the model, applications, units or machines reach a target state.",
+		Doc:     waitDoc,
+	}
+}
+
+func (c *waitCommand) SetFlags(f *gnuflag.FlagSet) {
+	c.ModelCommandBase.SetFlags(f)
+	f.StringVar(&c.workload, "workload", string(status.Active), "The workload status units must reach")
+	f.StringVar(&c.agent, "agent", string(status.Idle), "The agent status units must reach")
+	f.DurationVar(&c.idleFor, "idle-for", 0, "How long the target state must hold without changes")
+	f.DurationVar(&c.timeout, "timeout", 0, "Fail if the target state is not reached in this time (default: wait forever)")
+}
+
+func (c *waitCommand) Init(args []string) error {
+	if !status.Status(c.workload).KnownWorkloadStatus() {
+		return errors.NotValidf("workload status %q", c.workload)
+	}
+	if !status.Status(c.agent).KnownAgentStatus() {
+		return errors.NotValidf("agent status %q", c.agent)
+	}
+	if c.idleFor < 0 {
+		return errors.NotValidf("negative --idle-for")
+	}
+	if c.timeout < 0 {
+		return errors.NotValidf("negative --timeout")
+	}
+	c.applications = set.NewStrings()
+	c.units = set.NewStrings()
+	c.machines = set.NewStrings()
+	for _, arg := range args {
+		switch {
+		case names.IsValidMachine(arg):
+			c.machines.Add(arg)
+		case names.IsValidUnit(arg):
+			c.units.Add(arg)
+		case names.IsValidApplication(arg):
+			c.applications.Add(arg)
+		default:
+			return errors.Errorf("invalid application, unit or machine name %q", arg)
+		}
+	}
+	return nil
+}
+
+var newAPIClientForWait = func(c *waitCommand) (waitAPI, error) {
+	client, err := c.NewAPIClient()
+	if err != nil {
+		return nil, err
+	}
+	return statusClient{client}, nil
+}
+
+func (c *waitCommand) Run(ctx *cmd.Context) error {
+	client, err := newAPIClientForWait(c)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer client.Close()
+
+	watcher, err := client.WatchAll()
+	if err != nil {
+		return errors.Annotate(err, "cannot watch model")
+	}
+	defer watcher.Stop()
+
+	// Next blocks, so it is called from another goroutine, which is
+	// released when the command finishes.
+	done := make(chan struct{})
+	defer close(done)
+	deltasc := make(chan []multiwatcher.Delta)
+	errc := make(chan error, 1)
+	go func() {
+		for {
+			deltas, err := watcher.Next()
+			if err != nil {
+				errc <- err
+				return
+			}
+			select {
+			case deltasc <- deltas:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var timeout <-chan time.Time
+	if c.timeout > 0 {
+		timeout = c.clock.After(c.timeout)
+	}
+	var settled <-chan time.Time
+	model := newWaitModel()
+	primed := false
+	for {
+		select {
+		case deltas := <-deltasc:
+			// The first set of deltas describes the whole model, so
+			// is always checked, even if nothing is selected.
+			if !model.apply(deltas, c.selected) && primed {
+				continue
+			}
+			primed = true
+			pending, err := c.check(model)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			settled = nil
+			if len(pending) > 0 {
+				continue
+			}
+			if c.idleFor == 0 {
+				return nil
+			}
+			settled = c.clock.After(c.idleFor)
+		case <-settled:
+			return nil
+		case <-timeout:
+			pending, _ := c.check(model)
+			if len(pending) == 0 {
+				return errors.Errorf("timed out after %v waiting for no changes for %v", c.timeout, c.idleFor)
+			}
+			return errors.Errorf("timed out after %v waiting for %s", c.timeout, strings.Join(pending, ", "))
+		case err := <-errc:
+			return errors.Annotate(err, "watching model")
+		}
+	}
+}
+
+// selected reports whether the entity is being waited for.
+func (c *waitCommand) selected(info multiwatcher.EntityInfo) bool {
+	all := c.applications.IsEmpty() && c.units.IsEmpty() && c.machines.IsEmpty()
+	switch info := info.(type) {
+	case *multiwatcher.UnitInfo:
+		if all {
+			return true
+		}
+		return c.units.Contains(info.Name) || c.applications.Contains(info.Application)
+	case *multiwatcher.MachineInfo:
+		return all || c.machines.Contains(info.Id)
+	case *multiwatcher.ApplicationInfo:
+		return c.applications.Contains(info.Name)
+	}
+	return false
+}
+
+// check returns a description of each entity that has not reached
+// its target state, or an error if any has failed.
+func (c *waitCommand) check(model *waitModel) ([]string, error) {
+	var pending []string
+	for _, name := range model.sortedUnits() {
+		unit := model.units[name]
+		workload := unit.WorkloadStatus
+		if workload.Current == status.Error && c.workload != string(status.Error) {
+			return nil, errors.Errorf("unit %s is in error state: %s", name, workload.Message)
+		}
+		agent := unit.AgentStatus
+		if agent.Current == status.Failed && c.agent != string(status.Failed) {
+			return nil, errors.Errorf("unit %s agent has failed: %s", name, agent.Message)
+		}
+		if string(workload.Current) != c.workload || string(agent.Current) != c.agent {
+			pending = append(pending, fmt.Sprintf("unit %s (workload %s, agent %s)", name, workload.Current, agent.Current))
+		}
+	}
+	for _, id := range model.sortedMachines() {
+		machine := model.machines[id]
+		for _, s := range []multiwatcher.StatusInfo{machine.AgentStatus, machine.InstanceStatus} {
+			if s.Current == status.Error || s.Current == status.ProvisioningError {
+				return nil, errors.Errorf("machine %s is in error state: %s", id, s.Message)
+			}
+		}
+		if machine.AgentStatus.Current != status.Started {
+			pending = append(pending, fmt.Sprintf("machine %s (%s)", id, machine.AgentStatus.Current))
+		}
+	}
+	for _, name := range c.applications.SortedValues() {
+		if _, ok := model.applications[name]; !ok {
+			pending = append(pending, fmt.Sprintf("application %s (not deployed)", name))
+		}
+	}
+	for _, name := range c.units.SortedValues() {
+		if _, ok := model.units[name]; !ok {
+			pending = append(pending, fmt.Sprintf("unit %s (not deployed)", name))
+		}
+	}
+	for _, id := range c.machines.SortedValues() {
+		if _, ok := model.machines[id]; !ok {
+			pending = append(pending, fmt.Sprintf("machine %s (not provisioned)", id))
+		}
+	}
+	return pending, nil
+}
+
+// waitModel holds the state of the entities being waited for, as
+// reported by the AllWatcher.
+type waitModel struct {
+	applications map[string]*multiwatcher.ApplicationInfo
+	units        map[string]*multiwatcher.UnitInfo
+	machines     map[string]*multiwatcher.MachineInfo
+}
+
+func newWaitModel() *waitModel {
+	return &waitModel{
+		applications: make(map[string]*multiwatcher.ApplicationInfo),
+		units:        make(map[string]*multiwatcher.UnitInfo),
+		machines:     make(map[string]*multiwatcher.MachineInfo),
+	}
+}
+
+// apply records the deltas for the selected entities, and reports
+// whether any of them changed.
+func (m *waitModel) apply(deltas []multiwatcher.Delta, selected func(multiwatcher.EntityInfo) bool) bool {
+	changed := false
+	for _, delta := range deltas {
+		if !selected(delta.Entity) {
+			continue
+		}
+		changed = true
+		switch info := delta.Entity.(type) {
+		case *multiwatcher.ApplicationInfo:
+			if delta.Removed {
+				delete(m.applications, info.Name)
+			} else {
+				m.applications[info.Name] = info
+			}
+		case *multiwatcher.UnitInfo:
+			if delta.Removed {
+				delete(m.units, info.Name)
+			} else {
+				m.units[info.Name] = info
+			}
+		case *multiwatcher.MachineInfo:
+			if delta.Removed {
+				delete(m.machines, info.Id)
+			} else {
+				m.machines[info.Id] = info
+			}
+		}
+	}
+	return changed
+}
+
+func (m *waitModel) sortedUnits() []string {
+	var result []string
+	for name := range m.units {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
+
+func (m *waitModel) sortedMachines() []string {
+	var result []string
+	for id := range m.machines {
+		result = append(result, id)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,291 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/names.v2"
+
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type WaitSuite struct {
+	coretesting.FakeJujuXDGDataHomeSuite
+	clock   *testing.Clock
+	watcher *fakeWaitWatcher
+}
+
+var _ = gc.Suite(&WaitSuite{})
+
+type fakeWaitClient struct {
+	watcher *fakeWaitWatcher
+}
+
+func (a *fakeWaitClient) WatchAll() (statusWatcher, error) {
+	return a.watcher, nil
+}
+
+func (a *fakeWaitClient) Close() error {
+	return nil
+}
+
+// fakeWaitWatcher returns the deltas sent on its channel until it is
+// stopped.
+type fakeWaitWatcher struct {
+	deltas chan []multiwatcher.Delta
+	stop   chan struct{}
+}
+
+func (w *fakeWaitWatcher) Next() ([]multiwatcher.Delta, error) {
+	select {
+	case deltas := <-w.deltas:
+		return deltas, nil
+	case <-w.stop:
+		return nil, errors.New("watcher was stopped")
+	}
+}
+
+func (w *fakeWaitWatcher) Stop() error {
+	select {
+	case <-w.stop:
+	default:
+		close(w.stop)
+	}
+	return nil
+}
+
+func (s *WaitSuite) SetUpTest(c *gc.C) {
+	s.FakeJujuXDGDataHomeSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Now())
+	s.watcher = &fakeWaitWatcher{
+		deltas: make(chan []multiwatcher.Delta, 10),
+		stop:   make(chan struct{}),
+	}
+	s.PatchValue(&newAPIClientForWait, func(_ *waitCommand) (waitAPI, error) {
+		return &fakeWaitClient{s.watcher}, nil
+	})
+}
+
+func (s *WaitSuite) send(deltas ...multiwatcher.Delta) {
+	s.watcher.deltas <- deltas
+}
+
+func (s *WaitSuite) run(c *gc.C, args ...string) error {
+	command := modelcmd.Wrap(&waitCommand{clock: s.clock})
+	command.SetClientStore(newTestStore(c))
+	_, err := coretesting.RunCommand(c, command, args...)
+	return err
+}
+
+// start runs the command in the background, returning a channel
+// that receives its result.
+func (s *WaitSuite) start(c *gc.C, args ...string) <-chan error {
+	command := modelcmd.Wrap(&waitCommand{clock: s.clock})
+	command.SetClientStore(newTestStore(c))
+	result := make(chan error, 1)
+	go func() {
+		_, err := coretesting.RunCommand(c, command, args...)
+		result <- err
+	}()
+	return result
+}
+
+func (s *WaitSuite) waitAlarm(c *gc.C) {
+	select {
+	case <-s.clock.Alarms():
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for test clock After call")
+	}
+}
+
+func (s *WaitSuite) checkResult(c *gc.C, result <-chan error) error {
+	select {
+	case err := <-result:
+		return err
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for command to finish")
+	}
+	panic("unreachable")
+}
+
+func (s *WaitSuite) checkRunning(c *gc.C, result <-chan error) {
+	select {
+	case err := <-result:
+		c.Fatalf("command finished unexpectedly: %v", err)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func waitUnit(name string, workload, agent status.Status) multiwatcher.Delta {
+	application, _ := names.UnitApplication(name)
+	return multiwatcher.Delta{Entity: &multiwatcher.UnitInfo{
+		Name:           name,
+		Application:    application,
+		WorkloadStatus: multiwatcher.StatusInfo{Current: workload},
+		AgentStatus:    multiwatcher.StatusInfo{Current: agent},
+	}}
+}
+
+func waitMachine(id string, agent status.Status) multiwatcher.Delta {
+	return multiwatcher.Delta{Entity: &multiwatcher.MachineInfo{
+		Id:          id,
+		AgentStatus: multiwatcher.StatusInfo{Current: agent},
+	}}
+}
+
+func (s *WaitSuite) TestInitErrors(c *gc.C) {
+	for i, test := range []struct {
+		args []string
+		err  string
+	}{{
+		args: []string{"foo!"},
+		err:  `invalid application, unit or machine name "foo!"`,
+	}, {
+		args: []string{"--workload", "bogus"},
+		err:  `workload status "bogus" not valid`,
+	}, {
+		args: []string{"--agent", "bogus"},
+		err:  `agent status "bogus" not valid`,
+	}, {
+		args: []string{"--timeout", "-1s"},
+		err:  `negative --timeout not valid`,
+	}} {
+		c.Logf("test %d: %v", i, test.args)
+		command := modelcmd.Wrap(&waitCommand{})
+		command.SetClientStore(newTestStore(c))
+		err := coretesting.InitCommand(command, test.args)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
+
+func (s *WaitSuite) TestAlreadySettled(c *gc.C) {
+	s.send(
+		waitMachine("0", status.Started),
+		waitUnit("mysql/0", status.Active, status.Idle),
+	)
+	err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *WaitSuite) TestEmptyModel(c *gc.C) {
+	s.send()
+	err := s.run(c)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *WaitSuite) TestWaitsForUnits(c *gc.C) {
+	s.send(
+		waitMachine("0", status.Started),
+		waitUnit("mysql/0", status.Maintenance, status.Executing),
+	)
+	result := s.start(c)
+	s.checkRunning(c, result)
+	s.send(waitUnit("mysql/0", status.Active, status.Idle))
+	c.Assert(s.checkResult(c, result), jc.ErrorIsNil)
+}
+
+func (s *WaitSuite) TestWaitsForMachines(c *gc.C) {
+	s.send(waitMachine("0", status.Pending))
+	result := s.start(c, "0")
+	s.checkRunning(c, result)
+	s.send(waitMachine("0", status.Started))
+	c.Assert(s.checkResult(c, result), jc.ErrorIsNil)
+}
+
+func (s *WaitSuite) TestTargetStatus(c *gc.C) {
+	s.send(
+		waitUnit("mysql/0", status.Blocked, status.Idle),
+		waitUnit("wordpress/0", status.Maintenance, status.Executing),
+	)
+	err := s.run(c, "mysql", "--workload", "blocked")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *WaitSuite) TestUnitErrorState(c *gc.C) {
+	delta := waitUnit("mysql/0", status.Error, status.Idle)
+	delta.Entity.(*multiwatcher.UnitInfo).WorkloadStatus.Message = `hook failed: "install"`
+	s.send(delta)
+	err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, `unit mysql/0 is in error state: hook failed: "install"`)
+}
+
+func (s *WaitSuite) TestUnselectedErrorIgnored(c *gc.C) {
+	s.send(
+		waitUnit("mysql/0", status.Active, status.Idle),
+		waitUnit("wordpress/0", status.Error, status.Idle),
+	)
+	err := s.run(c, "mysql")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *WaitSuite) TestMachineErrorState(c *gc.C) {
+	delta := waitMachine("0", status.Pending)
+	delta.Entity.(*multiwatcher.MachineInfo).InstanceStatus = multiwatcher.StatusInfo{
+		Current: status.ProvisioningError,
+		Message: "no matching tools",
+	}
+	s.send(delta)
+	err := s.run(c)
+	c.Assert(err, gc.ErrorMatches, "machine 0 is in error state: no matching tools")
+}
+
+func (s *WaitSuite) TestTimeout(c *gc.C) {
+	s.send(waitUnit("wordpress/0", status.Active, status.Idle))
+	result := s.start(c, "--timeout", "1m", "mysql")
+	s.waitAlarm(c)
+	s.clock.Advance(time.Minute)
+	err := s.checkResult(c, result)
+	c.Assert(err, gc.ErrorMatches, `timed out after 1m0s waiting for application mysql \(not deployed\)`)
+}
+
+func (s *WaitSuite) TestCheckPending(c *gc.C) {
+	command := &waitCommand{}
+	err := coretesting.InitCommand(command, []string{"mysql", "wordpress/0", "1"})
+	c.Assert(err, jc.ErrorIsNil)
+	model := newWaitModel()
+	model.apply([]multiwatcher.Delta{
+		waitUnit("wordpress/0", status.Maintenance, status.Executing),
+		waitMachine("0", status.Pending),
+	}, command.selected)
+	pending, err := command.check(model)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, jc.DeepEquals, []string{
+		"unit wordpress/0 (workload maintenance, agent executing)",
+		"application mysql (not deployed)",
+		"machine 1 (not provisioned)",
+	})
+}
+
+func (s *WaitSuite) TestIdleFor(c *gc.C) {
+	s.send(waitUnit("mysql/0", status.Active, status.Idle))
+	result := s.start(c, "--idle-for", "1m")
+	s.waitAlarm(c)
+	s.clock.Advance(30 * time.Second)
+
+	// A change restarts the wait.
+	s.send(waitUnit("mysql/0", status.Active, status.Idle))
+	s.waitAlarm(c)
+	s.clock.Advance(45 * time.Second)
+	s.checkRunning(c, result)
+
+	s.clock.Advance(15 * time.Second)
+	c.Assert(s.checkResult(c, result), jc.ErrorIsNil)
+}
+
+func (s *WaitSuite) TestWatcherError(c *gc.C) {
+	s.send(waitUnit("mysql/0", status.Maintenance, status.Executing))
+	result := s.start(c)
+	s.checkRunning(c, result)
+	close(s.watcher.stop)
+	err := s.checkResult(c, result)
+	c.Assert(err, gc.ErrorMatches, "watching model: watcher was stopped")
+}
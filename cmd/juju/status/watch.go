@@ -0,0 +1,328 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/api"
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/instance"
+	"github.com/juju/juju/network"
+	"github.com/juju/juju/state/multiwatcher"
+)
+
+// statusWatcher is the part of the AllWatcher used to follow
+// changes to the model.
+type statusWatcher interface {
+	Next() ([]multiwatcher.Delta, error)
+	Stop() error
+}
+
+// statusClient adapts the API client for use by the status command.
+type statusClient struct {
+	*api.Client
+}
+
+// WatchAll implements statusAPI.
+func (c statusClient) WatchAll() (statusWatcher, error) {
+	watcher, err := c.Client.WatchAll()
+	if err != nil {
+		return nil, err
+	}
+	return watcher, nil
+}
+
+// statusTracker keeps a status snapshot up to date by applying the
+// deltas reported by the AllWatcher, so status can be re-rendered
+// without fetching the full status again.
+//
+// Only machines, applications and units are tracked; relations and
+// remote applications keep the state they had in the snapshot.
+type statusTracker struct {
+	status *params.FullStatus
+
+	// filtered is true when the snapshot was restricted by patterns,
+	// in which case entities missing from it are ignored.
+	filtered bool
+
+	// seen holds the last reported information for each entity.
+	seen map[multiwatcher.EntityId]multiwatcher.EntityInfo
+
+	// primed is set once the first set of deltas, which describes
+	// the whole model, has been applied.
+	primed bool
+}
+
+func newStatusTracker(status *params.FullStatus, filtered bool) *statusTracker {
+	if status.Machines == nil {
+		status.Machines = make(map[string]params.MachineStatus)
+	}
+	if status.Applications == nil {
+		status.Applications = make(map[string]params.ApplicationStatus)
+	}
+	return &statusTracker{
+		status:   status,
+		filtered: filtered,
+		seen:     make(map[multiwatcher.EntityId]multiwatcher.EntityInfo),
+	}
+}
+
+// apply updates the status with the given deltas. It returns a
+// description of each change made, and whether any tracked entity
+// was updated. No changes are reported for the first set of deltas,
+// as it just describes the current model.
+func (t *statusTracker) apply(deltas []multiwatcher.Delta) (changes []string, updated bool) {
+	for _, delta := range deltas {
+		id := delta.Entity.EntityId()
+		old := t.seen[id]
+		if delta.Removed {
+			delete(t.seen, id)
+		} else {
+			t.seen[id] = delta.Entity
+		}
+		if !t.update(delta) || !t.primed {
+			continue
+		}
+		updated = true
+		changes = append(changes, describeDelta(old, delta)...)
+	}
+	t.primed = true
+	return changes, updated
+}
+
+// update applies a single delta to the status, and reports whether
+// the entity is tracked.
+func (t *statusTracker) update(delta multiwatcher.Delta) bool {
+	switch info := delta.Entity.(type) {
+	case *multiwatcher.MachineInfo:
+		return t.updateMachine(info, delta.Removed)
+	case *multiwatcher.ApplicationInfo:
+		return t.updateApplication(info, delta.Removed)
+	case *multiwatcher.UnitInfo:
+		return t.updateUnit(info, delta.Removed)
+	}
+	return false
+}
+
+func (t *statusTracker) updateMachine(info *multiwatcher.MachineInfo, removed bool) bool {
+	machines := machineMap(t.status.Machines, info.Id)
+	if machines == nil {
+		return false
+	}
+	machine, ok := machines[info.Id]
+	if removed {
+		delete(machines, info.Id)
+		return ok
+	}
+	if !ok {
+		if t.filtered {
+			return false
+		}
+		machine = params.MachineStatus{
+			Id:         info.Id,
+			Containers: make(map[string]params.MachineStatus),
+		}
+	}
+	updateDetailedStatus(&machine.AgentStatus, info.AgentStatus, info.Life)
+	updateDetailedStatus(&machine.InstanceStatus, info.InstanceStatus, "")
+	machine.InstanceId = instance.Id(info.InstanceId)
+	machine.Series = info.Series
+	machine.Jobs = info.Jobs
+	machine.HasVote = info.HasVote
+	machine.WantsVote = info.WantsVote
+	if addr, ok := network.SelectPublicAddress(networkAddresses(info.Addresses)); ok {
+		machine.DNSName = addr.Value
+	}
+	machines[info.Id] = machine
+	return true
+}
+
+// machineMap returns the map holding the status of the machine with
+// the given id; containers are held by their host machine.
+func machineMap(machines map[string]params.MachineStatus, id string) map[string]params.MachineStatus {
+	parts := strings.Split(id, "/")
+	if len(parts) < 3 {
+		return machines
+	}
+	hostId := strings.Join(parts[:len(parts)-2], "/")
+	hosts := machineMap(machines, hostId)
+	if hosts == nil {
+		return nil
+	}
+	host, ok := hosts[hostId]
+	if !ok {
+		return nil
+	}
+	if host.Containers == nil {
+		host.Containers = make(map[string]params.MachineStatus)
+		hosts[hostId] = host
+	}
+	return host.Containers
+}
+
+func (t *statusTracker) updateApplication(info *multiwatcher.ApplicationInfo, removed bool) bool {
+	application, ok := t.status.Applications[info.Name]
+	if removed {
+		delete(t.status.Applications, info.Name)
+		return ok
+	}
+	if !ok {
+		if t.filtered {
+			return false
+		}
+		application = params.ApplicationStatus{
+			Units: make(map[string]params.UnitStatus),
+		}
+		if curl, err := charm.ParseURL(info.CharmURL); err == nil {
+			application.Series = curl.Series
+		}
+	}
+	application.Charm = info.CharmURL
+	application.Exposed = info.Exposed
+	application.Life = lifeString(info.Life)
+	updateDetailedStatus(&application.Status, info.Status, "")
+	t.status.Applications[info.Name] = application
+	return true
+}
+
+func (t *statusTracker) updateUnit(info *multiwatcher.UnitInfo, removed bool) bool {
+	units := t.unitMap(info)
+	if units == nil {
+		return false
+	}
+	unit, ok := units[info.Name]
+	if removed {
+		delete(units, info.Name)
+		return ok
+	}
+	if !ok && t.filtered {
+		return false
+	}
+	updateDetailedStatus(&unit.WorkloadStatus, info.WorkloadStatus, "")
+	updateDetailedStatus(&unit.AgentStatus, info.AgentStatus, "")
+	if !info.Subordinate {
+		unit.Machine = info.MachineId
+	}
+	unit.PublicAddress = info.PublicAddress
+	unit.OpenedPorts = nil
+	for _, p := range info.PortRanges {
+		portRange := network.PortRange{
+			FromPort: p.FromPort,
+			ToPort:   p.ToPort,
+			Protocol: p.Protocol,
+		}
+		unit.OpenedPorts = append(unit.OpenedPorts, portRange.String())
+	}
+	// The charm is only reported while it differs from the
+	// application's, i.e. while the unit is being upgraded.
+	unit.Charm = ""
+	if application, ok := t.status.Applications[info.Application]; ok && info.CharmURL != "" && info.CharmURL != application.Charm {
+		unit.Charm = info.CharmURL
+	}
+	units[info.Name] = unit
+	return true
+}
+
+// unitMap returns the map holding the status of the given unit.
+// Subordinate units are held by their principal, which is not
+// reported by the AllWatcher, so new subordinates are not tracked.
+func (t *statusTracker) unitMap(info *multiwatcher.UnitInfo) map[string]params.UnitStatus {
+	if info.Subordinate {
+		for _, application := range t.status.Applications {
+			for _, unit := range application.Units {
+				if _, ok := unit.Subordinates[info.Name]; ok {
+					return unit.Subordinates
+				}
+			}
+		}
+		return nil
+	}
+	application, ok := t.status.Applications[info.Application]
+	if !ok {
+		return nil
+	}
+	if application.Units == nil {
+		application.Units = make(map[string]params.UnitStatus)
+		t.status.Applications[info.Application] = application
+	}
+	return application.Units
+}
+
+// updateDetailedStatus copies the status reported by the AllWatcher,
+// keeping the version recorded in the snapshot.
+func updateDetailedStatus(out *params.DetailedStatus, info multiwatcher.StatusInfo, life multiwatcher.Life) {
+	out.Err = info.Err
+	out.Status = string(info.Current)
+	out.Info = info.Message
+	out.Since = info.Since
+	out.Data = info.Data
+	out.Life = lifeString(life)
+}
+
+// lifeString returns the life as reported by status, which omits
+// alive as the usual state.
+func lifeString(life multiwatcher.Life) string {
+	if life == multiwatcher.Life("alive") {
+		return ""
+	}
+	return string(life)
+}
+
+func networkAddresses(addresses []multiwatcher.Address) []network.Address {
+	out := make([]network.Address, len(addresses))
+	for i, addr := range addresses {
+		out[i] = network.Address{
+			Value: addr.Value,
+			Type:  network.AddressType(addr.Type),
+			Scope: network.Scope(addr.Scope),
+		}
+	}
+	return out
+}
+
+// describeDelta returns a line describing each change made by the
+// delta, given the previous information for the entity.
+func describeDelta(old multiwatcher.EntityInfo, delta multiwatcher.Delta) []string {
+	id := delta.Entity.EntityId()
+	name := fmt.Sprintf("%s %s", id.Kind, id.Id)
+	switch {
+	case delta.Removed:
+		return []string{name + " removed"}
+	case old == nil:
+		return []string{name + " added"}
+	}
+	var changes []string
+	changed := func(what, from, to string) {
+		if from != to {
+			changes = append(changes, fmt.Sprintf("%s %s: %s -> %s", name, what, from, to))
+		}
+	}
+	switch info := delta.Entity.(type) {
+	case *multiwatcher.MachineInfo:
+		prev := old.(*multiwatcher.MachineInfo)
+		changed("agent", describeStatus(prev.AgentStatus), describeStatus(info.AgentStatus))
+		changed("instance", describeStatus(prev.InstanceStatus), describeStatus(info.InstanceStatus))
+	case *multiwatcher.ApplicationInfo:
+		prev := old.(*multiwatcher.ApplicationInfo)
+		changed("status", describeStatus(prev.Status), describeStatus(info.Status))
+		changed("charm", prev.CharmURL, info.CharmURL)
+		changed("exposed", fmt.Sprint(prev.Exposed), fmt.Sprint(info.Exposed))
+	case *multiwatcher.UnitInfo:
+		prev := old.(*multiwatcher.UnitInfo)
+		changed("workload", describeStatus(prev.WorkloadStatus), describeStatus(info.WorkloadStatus))
+		changed("agent", describeStatus(prev.AgentStatus), describeStatus(info.AgentStatus))
+	}
+	return changes
+}
+
+func describeStatus(info multiwatcher.StatusInfo) string {
+	if info.Message == "" {
+		return string(info.Current)
+	}
+	return fmt.Sprintf("%s (%s)", info.Current, info.Message)
+}
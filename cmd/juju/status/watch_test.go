@@ -0,0 +1,266 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"strings"
+
+	"github.com/juju/cmd"
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/cmd/modelcmd"
+	"github.com/juju/juju/jujuclient"
+	"github.com/juju/juju/jujuclient/jujuclienttesting"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/status"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type WatchSuite struct {
+	coretesting.FakeJujuXDGDataHomeSuite
+}
+
+var _ = gc.Suite(&WatchSuite{})
+
+type fakeWatchClient struct {
+	fakeAPIClient
+	watcher *fakeStatusWatcher
+}
+
+func (a *fakeWatchClient) WatchAll() (statusWatcher, error) {
+	return a.watcher, nil
+}
+
+// fakeStatusWatcher returns each set of deltas in turn, and then
+// fails.
+type fakeStatusWatcher struct {
+	deltas  [][]multiwatcher.Delta
+	stopped bool
+}
+
+func (w *fakeStatusWatcher) Next() ([]multiwatcher.Delta, error) {
+	if len(w.deltas) == 0 {
+		return nil, errors.New("watcher stopped")
+	}
+	deltas := w.deltas[0]
+	w.deltas = w.deltas[1:]
+	return deltas, nil
+}
+
+func (w *fakeStatusWatcher) Stop() error {
+	w.stopped = true
+	return nil
+}
+
+func testFullStatus() *params.FullStatus {
+	return &params.FullStatus{
+		Model: params.ModelStatusInfo{
+			Name:     "controller",
+			CloudTag: "cloud-dummy",
+			Version:  "2.2.0",
+		},
+		Machines: map[string]params.MachineStatus{
+			"0": {
+				Id:          "0",
+				AgentStatus: params.DetailedStatus{Status: "started", Version: "2.2.0"},
+				Containers:  map[string]params.MachineStatus{},
+			},
+		},
+		Applications: map[string]params.ApplicationStatus{
+			"mysql": {
+				Charm:  "cs:xenial/mysql-1",
+				Series: "xenial",
+				Status: params.DetailedStatus{Status: "maintenance"},
+				Units: map[string]params.UnitStatus{
+					"mysql/1": {
+						Machine:        "0",
+						WorkloadStatus: params.DetailedStatus{Status: "maintenance"},
+						AgentStatus:    params.DetailedStatus{Status: "executing", Version: "2.2.0"},
+					},
+				},
+			},
+		},
+	}
+}
+
+func unitInfo(name string, workload, agent status.Status) *multiwatcher.UnitInfo {
+	return &multiwatcher.UnitInfo{
+		Name:           name,
+		Application:    "mysql",
+		CharmURL:       "cs:xenial/mysql-1",
+		MachineId:      "0",
+		WorkloadStatus: multiwatcher.StatusInfo{Current: workload},
+		AgentStatus:    multiwatcher.StatusInfo{Current: agent},
+	}
+}
+
+func machineInfo(id string, agent status.Status) *multiwatcher.MachineInfo {
+	return &multiwatcher.MachineInfo{
+		Id:          id,
+		Life:        multiwatcher.Life("alive"),
+		AgentStatus: multiwatcher.StatusInfo{Current: agent},
+	}
+}
+
+func initialDeltas() []multiwatcher.Delta {
+	return []multiwatcher.Delta{
+		{Entity: machineInfo("0", status.Started)},
+		{Entity: &multiwatcher.ApplicationInfo{
+			Name:     "mysql",
+			CharmURL: "cs:xenial/mysql-1",
+			Life:     multiwatcher.Life("alive"),
+			Status:   multiwatcher.StatusInfo{Current: status.Maintenance},
+		}},
+		{Entity: unitInfo("mysql/1", status.Maintenance, status.Executing)},
+	}
+}
+
+func (s *WatchSuite) TestTrackerInitialDeltas(c *gc.C) {
+	tracker := newStatusTracker(testFullStatus(), false)
+	changes, updated := tracker.apply(initialDeltas())
+	c.Assert(changes, gc.HasLen, 0)
+	c.Assert(updated, jc.IsFalse)
+	c.Assert(tracker.status, jc.DeepEquals, testFullStatus())
+}
+
+func (s *WatchSuite) TestTrackerUnitChanges(c *gc.C) {
+	tracker := newStatusTracker(testFullStatus(), false)
+	tracker.apply(initialDeltas())
+	changes, updated := tracker.apply([]multiwatcher.Delta{
+		{Entity: unitInfo("mysql/1", status.Active, status.Idle)},
+	})
+	c.Assert(updated, jc.IsTrue)
+	c.Assert(changes, jc.DeepEquals, []string{
+		"unit mysql/1 workload: maintenance -> active",
+		"unit mysql/1 agent: executing -> idle",
+	})
+	unit := tracker.status.Applications["mysql"].Units["mysql/1"]
+	c.Assert(unit.WorkloadStatus.Status, gc.Equals, "active")
+	c.Assert(unit.AgentStatus.Status, gc.Equals, "idle")
+	c.Assert(unit.AgentStatus.Version, gc.Equals, "2.2.0")
+}
+
+func (s *WatchSuite) TestTrackerStatusMessages(c *gc.C) {
+	tracker := newStatusTracker(testFullStatus(), false)
+	tracker.apply(initialDeltas())
+	unit := unitInfo("mysql/1", status.Maintenance, status.Executing)
+	unit.WorkloadStatus.Message = "installing"
+	changes, _ := tracker.apply([]multiwatcher.Delta{{Entity: unit}})
+	c.Assert(changes, jc.DeepEquals, []string{
+		"unit mysql/1 workload: maintenance -> maintenance (installing)",
+	})
+	c.Assert(tracker.status.Applications["mysql"].Units["mysql/1"].WorkloadStatus.Info, gc.Equals, "installing")
+}
+
+func (s *WatchSuite) TestTrackerAddAndRemove(c *gc.C) {
+	tracker := newStatusTracker(testFullStatus(), false)
+	tracker.apply(initialDeltas())
+	changes, updated := tracker.apply([]multiwatcher.Delta{
+		{Entity: machineInfo("1", status.Pending)},
+		{Entity: unitInfo("mysql/2", status.Waiting, status.Allocating)},
+		{Entity: unitInfo("mysql/1", status.Maintenance, status.Executing), Removed: true},
+	})
+	c.Assert(updated, jc.IsTrue)
+	c.Assert(changes, jc.DeepEquals, []string{
+		"machine 1 added",
+		"unit mysql/2 added",
+		"unit mysql/1 removed",
+	})
+	c.Assert(tracker.status.Machines["1"].AgentStatus.Status, gc.Equals, "pending")
+	units := tracker.status.Applications["mysql"].Units
+	c.Assert(units, gc.HasLen, 1)
+	c.Assert(units["mysql/2"].WorkloadStatus.Status, gc.Equals, "waiting")
+}
+
+func (s *WatchSuite) TestTrackerContainers(c *gc.C) {
+	tracker := newStatusTracker(testFullStatus(), false)
+	tracker.apply(initialDeltas())
+	tracker.apply([]multiwatcher.Delta{
+		{Entity: machineInfo("0/lxd/0", status.Pending)},
+	})
+	changes, _ := tracker.apply([]multiwatcher.Delta{
+		{Entity: machineInfo("0/lxd/0", status.Started)},
+	})
+	c.Assert(changes, jc.DeepEquals, []string{
+		"machine 0/lxd/0 agent: pending -> started",
+	})
+	container := tracker.status.Machines["0"].Containers["0/lxd/0"]
+	c.Assert(container.AgentStatus.Status, gc.Equals, "started")
+}
+
+func (s *WatchSuite) TestTrackerFiltered(c *gc.C) {
+	tracker := newStatusTracker(testFullStatus(), true)
+	tracker.apply(initialDeltas())
+	changes, updated := tracker.apply([]multiwatcher.Delta{
+		{Entity: machineInfo("1", status.Pending)},
+		{Entity: &multiwatcher.ApplicationInfo{Name: "wordpress"}},
+	})
+	c.Assert(updated, jc.IsFalse)
+	c.Assert(changes, gc.HasLen, 0)
+	c.Assert(tracker.status.Machines, gc.HasLen, 1)
+	c.Assert(tracker.status.Applications, gc.HasLen, 1)
+}
+
+// newTestStore returns a client store whose current model is
+// admin/controller.
+func newTestStore(c *gc.C) *jujuclienttesting.MemStore {
+	store := jujuclienttesting.NewMemStore()
+	store.CurrentControllerName = "kontroll"
+	store.Controllers["kontroll"] = jujuclient.ControllerDetails{}
+	store.Accounts["kontroll"] = jujuclient.AccountDetails{User: "admin"}
+	err := store.UpdateModel("kontroll", "admin/controller", jujuclient.ModelDetails{ModelUUID: "uuid"})
+	c.Assert(err, jc.ErrorIsNil)
+	store.Models["kontroll"].CurrentModel = "admin/controller"
+	return store
+}
+
+func (s *WatchSuite) runWatch(c *gc.C, args ...string) (*cmd.Context, *fakeStatusWatcher, error) {
+	watcher := &fakeStatusWatcher{
+		deltas: [][]multiwatcher.Delta{
+			initialDeltas(),
+			{{Entity: &multiwatcher.AnnotationInfo{Tag: "unit-mysql-1"}}},
+			{{Entity: unitInfo("mysql/1", status.Active, status.Idle)}},
+		},
+	}
+	client := &fakeWatchClient{
+		fakeAPIClient: fakeAPIClient{statusReturn: testFullStatus()},
+		watcher:       watcher,
+	}
+	s.PatchValue(&newAPIClientForStatus, func(_ *statusCommand) (statusAPI, error) {
+		return client, nil
+	})
+
+	command := modelcmd.Wrap(&statusCommand{})
+	command.SetClientStore(newTestStore(c))
+	ctx, err := coretesting.RunCommand(c, command, args...)
+	c.Assert(client.closeCalled, jc.IsTrue)
+	return ctx, watcher, err
+}
+
+func (s *WatchSuite) TestWatchChangesOnly(c *gc.C) {
+	ctx, watcher, err := s.runWatch(c, "--format", "yaml", "--watch-changes-only")
+	c.Assert(err, gc.ErrorMatches, "watching status: watcher stopped")
+	c.Assert(watcher.stopped, jc.IsTrue)
+	stdout := coretesting.Stdout(ctx)
+	c.Assert(strings.Count(stdout, "model:"), gc.Equals, 1)
+	c.Assert(stdout, jc.HasSuffix, "\n"+
+		"unit mysql/1 workload: maintenance -> active\n"+
+		"unit mysql/1 agent: executing -> idle\n")
+}
+
+func (s *WatchSuite) TestWatch(c *gc.C) {
+	ctx, watcher, err := s.runWatch(c, "--format", "yaml", "--watch")
+	c.Assert(err, gc.ErrorMatches, "watching status: watcher stopped")
+	c.Assert(watcher.stopped, jc.IsTrue)
+	// The status is written again only when a unit changes.
+	outputs := strings.Split(coretesting.Stdout(ctx), "\n\n")
+	c.Assert(outputs, gc.HasLen, 2)
+	c.Assert(outputs[0], jc.Contains, "current: maintenance")
+	c.Assert(outputs[0], gc.Not(jc.Contains), "current: active")
+	c.Assert(outputs[1], jc.Contains, "current: active")
+	c.Assert(outputs[1], jc.Contains, "current: idle")
+}
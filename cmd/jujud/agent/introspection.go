@@ -6,6 +6,7 @@ package agent
 import (
 	"os"
 	"runtime"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/prometheus/client_golang/prometheus"
@@ -17,6 +18,22 @@ import (
 	"github.com/juju/juju/worker/introspection"
 )
 
+// engineHistorySize is the number of worker state changes that each
+// agent's dependency engine remembers for each manifold, for the
+// introspection history report.
+const engineHistorySize = 20
+
+const (
+	// apiTracingSlowCalls is the number of the slowest API calls
+	// reported by the introspection RPC trace report, when API
+	// tracing is enabled.
+	apiTracingSlowCalls = 50
+
+	// apiTracingSlowCallsWindow is the period over which the slowest
+	// API calls are reported.
+	apiTracingSlowCallsWindow = time.Hour
+)
+
 // DefaultIntrospectionSocketName returns the socket name to use for the
 // abstract domain socket that the introspection worker serves requests
 // over.
@@ -30,6 +47,7 @@ type introspectionConfig struct {
 	Agent              agent.Agent
 	Engine             *dependency.Engine
 	StatePoolReporter  introspection.IntrospectionReporter
+	RPCTracerReporter  introspection.IntrospectionReporter
 	PrometheusGatherer prometheus.Gatherer
 	NewSocketName      func(names.Tag) string
 	WorkerFunc         func(config introspection.Config) (worker.Worker, error)
@@ -52,6 +70,7 @@ func startIntrospection(cfg introspectionConfig) error {
 		SocketName:         socketName,
 		DepEngine:          cfg.Engine,
 		StatePool:          cfg.StatePoolReporter,
+		RPCTracer:          cfg.RPCTracerReporter,
 		PrometheusGatherer: cfg.PrometheusGatherer,
 	})
 	if err != nil {
@@ -89,3 +108,10 @@ func (h *statePoolHolder) IntrospectionReport() string {
 	}
 	return h.pool.IntrospectionReport()
 }
+
+func (h *rpcTracerHolder) IntrospectionReport() string {
+	if h.tracer == nil {
+		return "API tracing is not enabled"
+	}
+	return h.tracer.IntrospectionReport()
+}
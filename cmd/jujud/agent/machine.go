@@ -50,7 +50,9 @@ import (
 	"github.com/juju/juju/apiserver"
 	"github.com/juju/juju/apiserver/observer"
 	"github.com/juju/juju/apiserver/observer/metricobserver"
+	"github.com/juju/juju/apiserver/observer/traceobserver"
 	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/apiserver/ratelimit"
 	"github.com/juju/juju/audit"
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/cmd/jujud/agent/machine"
@@ -68,9 +70,11 @@ import (
 	"github.com/juju/juju/mongo"
 	"github.com/juju/juju/mongo/txnmetrics"
 	"github.com/juju/juju/pubsub/centralhub"
+	"github.com/juju/juju/rpc/compression/compressionmetrics"
 	"github.com/juju/juju/service"
 	"github.com/juju/juju/service/common"
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
 	"github.com/juju/juju/state/multiwatcher"
 	"github.com/juju/juju/state/stateenvirons"
 	"github.com/juju/juju/state/statemetrics"
@@ -80,6 +84,7 @@ import (
 	"github.com/juju/juju/watcher"
 	jworker "github.com/juju/juju/worker"
 	"github.com/juju/juju/worker/apicaller"
+	"github.com/juju/juju/worker/backupscheduler"
 	"github.com/juju/juju/worker/certupdater"
 	"github.com/juju/juju/worker/conv2state"
 	"github.com/juju/juju/worker/dblogpruner"
@@ -309,8 +314,10 @@ func NewMachineAgent(
 		newIntrospectionSocketName:  newIntrospectionSocketName,
 		prometheusRegistry:          prometheusRegistry,
 		txnmetricsCollector:         txnmetrics.New(),
+		compressionCollector:        compressionmetrics.New(),
 		preUpgradeSteps:             preUpgradeSteps,
 		statePool:                   &statePoolHolder{},
+		rpcTracer:                   &rpcTracerHolder{},
 	}
 	if err := a.prometheusRegistry.Register(
 		logsendermetrics.BufferedLogWriterMetrics{bufferedLogger},
@@ -320,6 +327,9 @@ func NewMachineAgent(
 	if err := a.prometheusRegistry.Register(a.txnmetricsCollector); err != nil {
 		return nil, errors.Trace(err)
 	}
+	if err := a.prometheusRegistry.Register(a.compressionCollector); err != nil {
+		return nil, errors.Trace(err)
+	}
 	return a, nil
 }
 
@@ -355,6 +365,7 @@ type MachineAgent struct {
 	newIntrospectionSocketName func(names.Tag) string
 	prometheusRegistry         *prometheus.Registry
 	txnmetricsCollector        *txnmetrics.Collector
+	compressionCollector       *compressionmetrics.Collector
 	preUpgradeSteps            upgrades.PreUpgradeStepsFunc
 
 	// Only API servers have hubs. This is temporary until the apiserver and
@@ -367,12 +378,22 @@ type MachineAgent struct {
 	// worker can have a single thing to hold that can report on the state pool.
 	// The content of the state pool holder is updated as the pool changes.
 	statePool *statePoolHolder
+
+	// The rpcTracer holder holds a reference to the API request tracer,
+	// if API tracing is enabled in the controller config. Like the
+	// statePool holder, it lets the introspection worker report on
+	// the tracer of the current API server.
+	rpcTracer *rpcTracerHolder
 }
 
 type statePoolHolder struct {
 	pool *state.StatePool
 }
 
+type rpcTracerHolder struct {
+	tracer *traceobserver.Tracer
+}
+
 // IsRestorePreparing returns bool representing if we are in restore mode
 // but not running restore.
 func (a *MachineAgent) IsRestorePreparing() bool {
@@ -517,6 +538,8 @@ func (a *MachineAgent) makeEngineCreator(previousAgentVersion version.Number) fu
 			WorstError:  cmdutil.MoreImportantError,
 			ErrorDelay:  3 * time.Second,
 			BounceDelay: 10 * time.Millisecond,
+			Clock:       clock.WallClock,
+			HistorySize: engineHistorySize,
 		}
 		engine, err := dependency.NewEngine(config)
 		if err != nil {
@@ -556,6 +579,7 @@ func (a *MachineAgent) makeEngineCreator(previousAgentVersion version.Number) fu
 			Agent:              a,
 			Engine:             engine,
 			StatePoolReporter:  a.statePool,
+			RPCTracerReporter:  a.rpcTracer,
 			NewSocketName:      a.newIntrospectionSocketName,
 			PrometheusGatherer: a.prometheusRegistry,
 			WorkerFunc:         introspection.NewWorker,
@@ -1083,6 +1107,17 @@ func (a *MachineAgent) startStateWorkers(
 			a.startWorkerAfterUpgrade(singularRunner, "txnpruner", func() (worker.Worker, error) {
 				return txnpruner.New(st, time.Hour*2, clock.WallClock), nil
 			})
+
+			a.startWorkerAfterUpgrade(singularRunner, "backupscheduler", func() (worker.Worker, error) {
+				// The manager restarts the scheduler whenever the
+				// controller config changes.
+				paths := backups.Paths{
+					DataDir: agentConfig.DataDir(),
+					LogsDir: agentConfig.LogDir(),
+				}
+				source := backupscheduler.NewStateConfigSource(st, m.Id(), paths)
+				return backupscheduler.NewManager(source)
+			})
 		default:
 			return nil, errors.Errorf("unknown job type %q", job)
 		}
@@ -1104,6 +1139,8 @@ func (a *MachineAgent) startModelWorkers(controllerUUID, modelUUID string) (work
 		Filter:      model.IgnoreErrRemoved,
 		ErrorDelay:  3 * time.Second,
 		BounceDelay: 10 * time.Millisecond,
+		Clock:       clock.WallClock,
+		HistorySize: engineHistorySize,
 	})
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -1122,6 +1159,9 @@ func (a *MachineAgent) startModelWorkers(controllerUUID, modelUUID string) (work
 		StatusHistoryPrunerMaxHistoryTime: 336 * time.Hour, // 2 weeks
 		StatusHistoryPrunerMaxHistoryMB:   5120,            // 5G
 		StatusHistoryPrunerInterval:       5 * time.Minute,
+		ActionPrunerMaxHistoryTime:        336 * time.Hour, // 2 weeks
+		ActionPrunerMaxHistoryMB:          5120,            // 5G
+		ActionPrunerInterval:              5 * time.Minute,
 		SpacesImportedGate:                a.discoverSpacesComplete,
 		NewEnvironFunc:                    newEnvirons,
 		NewMigrationMaster:                migrationmaster.NewWorker,
@@ -1198,18 +1238,48 @@ func (a *MachineAgent) newAPIserverWorker(
 		return nil, errors.Annotate(err, "cannot fetch the controller config")
 	}
 
+	var rpcTracer *traceobserver.Tracer
+	if controllerConfig.APITracingEnabled() {
+		rpcTracer, err = traceobserver.NewTracer(traceobserver.Config{
+			Clock:                clock.WallClock,
+			PrometheusRegisterer: a.prometheusRegistry,
+			SlowCalls:            apiTracingSlowCalls,
+			SlowCallsWindow:      apiTracingSlowCallsWindow,
+		})
+		if err != nil {
+			return nil, errors.Annotate(err, "cannot create RPC tracer")
+		}
+	}
+	a.rpcTracer.tracer = rpcTracer
+
 	newObserver, err := newObserverFn(
 		controllerConfig,
 		clock.WallClock,
 		jujuversion.Current,
 		agentConfig.Model().Id(),
-		newAuditEntrySink(st, logDir),
+		newAuditEntrySink(st, logDir, controllerConfig),
 		auditErrorHandler,
 		a.prometheusRegistry,
+		rpcTracer,
 	)
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot create RPC observer factory")
 	}
+	requestLimiter, err := ratelimit.NewLimiter(ratelimit.Config{
+		Clock: clock.WallClock,
+		User: ratelimit.Limit{
+			Rate:  controllerConfig.APIUserRequestRate(),
+			Burst: controllerConfig.APIUserRequestBurst(),
+		},
+		Agent: ratelimit.Limit{
+			Rate:  controllerConfig.APIAgentRequestRate(),
+			Burst: controllerConfig.APIAgentRequestBurst(),
+		},
+		Facades: facadeRequestLimits(controllerConfig.APIFacadeRequestLimits()),
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot create API request limiter")
+	}
 	statePool := state.NewStatePool(st)
 	a.statePool.pool = statePool
 
@@ -1218,6 +1288,7 @@ func (a *MachineAgent) newAPIserverWorker(
 			introspection.ReportSources{
 				DependencyEngine:   dependencyReporter,
 				StatePool:          statePool,
+				RPCTracer:          a.rpcTracer,
 				PrometheusGatherer: a.prometheusRegistry,
 			}, f)
 	}
@@ -1236,6 +1307,9 @@ func (a *MachineAgent) newAPIserverWorker(
 		AutocertDNSName:               controllerConfig.AutocertDNSName(),
 		AllowModelAccess:              controllerConfig.AllowModelAccess(),
 		NewObserver:                   newObserver,
+		RequestLimiter:                requestLimiter,
+		WebsocketCompression:          controllerConfig.APIWebsocketCompression(),
+		CompressionCounter:            a.compressionCollector,
 		StatePool:                     statePool,
 		RegisterIntrospectionHandlers: registerIntrospectionHandlers,
 	})
@@ -1246,9 +1320,24 @@ func (a *MachineAgent) newAPIserverWorker(
 	return server, nil
 }
 
-func newAuditEntrySink(st *state.State, logDir string) audit.AuditEntrySinkFn {
+// facadeRequestLimits converts the per-facade API request limits in
+// the controller config to those used by the API request limiter.
+func facadeRequestLimits(limits map[string]controller.RequestLimit) map[string]ratelimit.Limit {
+	result := make(map[string]ratelimit.Limit, len(limits))
+	for facade, limit := range limits {
+		result[facade] = ratelimit.Limit{Rate: limit.Rate, Burst: limit.Burst}
+	}
+	return result
+}
+
+func newAuditEntrySink(st *state.State, logDir string, controllerConfig controller.Config) audit.AuditEntrySinkFn {
 	persistFn := st.PutAuditEntryFn()
-	fileSinkFn := audit.NewLogFileSink(logDir)
+	fileSinkFn := audit.NewLogFileSink(audit.LogFileConfig{
+		LogDir:         logDir,
+		MaxSizeMB:      controllerConfig.AuditLogMaxSizeMB(),
+		RotateInterval: controllerConfig.AuditLogRotateInterval(),
+		MaxBackups:     controllerConfig.AuditLogMaxBackups(),
+	})
 	return func(entry audit.AuditEntry) error {
 		// We don't care about auditing anything but user actions.
 		if _, err := names.ParseUserTag(entry.OriginName); err != nil {
@@ -1278,6 +1367,7 @@ func newObserverFn(
 	persistAuditEntry audit.AuditEntrySinkFn,
 	auditErrorHandler observer.ErrorHandler,
 	prometheusRegisterer prometheus.Registerer,
+	rpcTracer *traceobserver.Tracer,
 ) (observer.ObserverFactory, error) {
 
 	var observerFactories []observer.ObserverFactory
@@ -1314,6 +1404,11 @@ func newObserverFn(
 	}
 	observerFactories = append(observerFactories, metricObserver)
 
+	// Tracing observer, if API tracing is enabled.
+	if rpcTracer != nil {
+		observerFactories = append(observerFactories, rpcTracer.NewObserver)
+	}
+
 	return observer.ObserverFactoryMultiplexer(observerFactories...), nil
 
 }
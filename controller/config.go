@@ -5,6 +5,9 @@ package controller
 
 import (
 	"net/url"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -14,6 +17,8 @@ import (
 	"gopkg.in/macaroon-bakery.v1/bakery"
 
 	"github.com/juju/juju/cert"
+	"github.com/juju/juju/core/schedule"
+	"github.com/juju/juju/state/backups/encryption"
 )
 
 var logger = loggo.GetLogger("juju.controller")
@@ -29,10 +34,97 @@ const (
 	// APIPort is the port used for api connections.
 	APIPort = "api-port"
 
+	// APITracingEnabled determines whether the controller traces API
+	// requests, recording their latency and payload sizes as metrics
+	// and reporting the slowest calls through introspection.
+	APITracingEnabled = "api-tracing-enabled"
+
+	// APIUserRequestRate is the number of API requests per second
+	// that each user may make to each facade. If it is zero or
+	// unset, user requests are not rate limited.
+	APIUserRequestRate = "api-user-request-rate"
+
+	// APIUserRequestBurst is the number of API requests that each
+	// user may make to each facade at once after a period of
+	// inactivity. It defaults to the APIUserRequestRate.
+	APIUserRequestBurst = "api-user-request-burst"
+
+	// APIAgentRequestRate is the number of API requests per second
+	// that each machine or unit agent may make to each facade. If it
+	// is zero or unset, agent requests are not rate limited.
+	APIAgentRequestRate = "api-agent-request-rate"
+
+	// APIAgentRequestBurst is the number of API requests that each
+	// machine or unit agent may make to each facade at once after a
+	// period of inactivity. It defaults to the APIAgentRequestRate.
+	APIAgentRequestBurst = "api-agent-request-burst"
+
+	// APIFacadeRequestLimits holds API request rate limits for
+	// particular facades, overriding the user and agent limits. It
+	// is a comma-separated list of facade=rate or facade=rate/burst
+	// entries, for example "Client=5/10,Uniter=20". A rate of zero
+	// lifts the limit for the facade.
+	APIFacadeRequestLimits = "api-facade-request-limits"
+
+	// APIWebsocketCompression determines whether the controller
+	// compresses the messages on API connections from clients and
+	// agents that support it.
+	APIWebsocketCompression = "api-websocket-compression"
+
 	// AuditingEnabled determines whether the controller will record
 	// auditing information.
 	AuditingEnabled = "auditing-enabled"
 
+	// AuditLogMaxSize is the size at which the controller's audit log
+	// file is rotated, e.g. "300M".
+	AuditLogMaxSize = "audit-log-max-size"
+
+	// AuditLogMaxBackups is the number of rotated, compressed audit
+	// log files the controller keeps.
+	AuditLogMaxBackups = "audit-log-max-backups"
+
+	// AuditLogRotateInterval is the age at which the controller's
+	// audit log file is rotated regardless of its size, e.g. "24h".
+	AuditLogRotateInterval = "audit-log-rotate-interval"
+
+	// BackupSchedule is the schedule, in cron format, on which the
+	// controller creates backups of itself, e.g. "30 2 * * *". See
+	// the core/schedule package for the syntax.
+	BackupSchedule = "backup-schedule"
+
+	// BackupRetentionCount is the number of scheduled backups the
+	// controller keeps, both in its own storage and in the backup
+	// store.
+	BackupRetentionCount = "backup-retention-count"
+
+	// BackupStore is the URL of the store to which scheduled backups
+	// are copied. It is either a file URL naming a directory, which
+	// may be an NFS mount, or an s3 URL naming a bucket and an
+	// optional key prefix, e.g. s3://backups/juju.
+	BackupStore = "backup-store"
+
+	// BackupStoreS3Endpoint is the URL of the S3-compatible service
+	// used by an s3 backup store. It defaults to Amazon S3.
+	BackupStoreS3Endpoint = "backup-store-s3-endpoint"
+
+	// BackupStoreS3Region is the region used to sign requests to the
+	// S3-compatible service.
+	BackupStoreS3Region = "backup-store-s3-region"
+
+	// BackupStoreS3AccessKey and BackupStoreS3SecretKey are the
+	// credentials used for an s3 backup store. Like cloud credentials,
+	// the secret key is kept in the controller's database; it is never
+	// returned over the API.
+	BackupStoreS3AccessKey = "backup-store-s3-access-key"
+	BackupStoreS3SecretKey = "backup-store-s3-secret-key"
+
+	// BackupEncryptionKey, if set, is a PEM-encoded RSA public key
+	// with which scheduled backups are encrypted. Secrets are not
+	// accepted, since the controller config is kept in the same
+	// database as the backups; the private key is kept elsewhere and
+	// only needed to restore.
+	BackupEncryptionKey = "backup-encryption-key"
+
 	// StatePort is the port used for mongo connections.
 	StatePort = "state-port"
 
@@ -79,6 +171,18 @@ const (
 	// AuditingEnabled config value.
 	DefaultAuditingEnabled = false
 
+	// DefaultAuditLogMaxSize contains the default value for the
+	// AuditLogMaxSize config value.
+	DefaultAuditLogMaxSize = "300M"
+
+	// DefaultAuditLogMaxBackups contains the default value for the
+	// AuditLogMaxBackups config value.
+	DefaultAuditLogMaxBackups = 10
+
+	// DefaultBackupRetentionCount contains the default value for the
+	// BackupRetentionCount config value.
+	DefaultBackupRetentionCount = 7
+
 	// DefaultNUMAControlPolicy should not be used by default.
 	// Only use numactl if user specifically requests it
 	DefaultNUMAControlPolicy = false
@@ -98,8 +202,26 @@ const (
 var ControllerOnlyConfigAttributes = []string{
 	AllowModelAccessKey,
 	APIPort,
+	APITracingEnabled,
+	APIAgentRequestBurst,
+	APIAgentRequestRate,
+	APIFacadeRequestLimits,
+	APIUserRequestBurst,
+	APIUserRequestRate,
+	APIWebsocketCompression,
+	AuditLogMaxBackups,
+	AuditLogMaxSize,
+	AuditLogRotateInterval,
 	AutocertDNSNameKey,
 	AutocertURLKey,
+	BackupEncryptionKey,
+	BackupRetentionCount,
+	BackupSchedule,
+	BackupStore,
+	BackupStoreS3AccessKey,
+	BackupStoreS3Endpoint,
+	BackupStoreS3Region,
+	BackupStoreS3SecretKey,
 	CACertKey,
 	ControllerUUIDKey,
 	IdentityPublicKey,
@@ -109,6 +231,12 @@ var ControllerOnlyConfigAttributes = []string{
 	MongoMemoryProfile,
 }
 
+// SecretAttributes holds the names of the controller config attributes
+// whose values are not revealed over the API.
+var SecretAttributes = []string{
+	BackupStoreS3SecretKey,
+}
+
 // ControllerOnlyAttribute returns true if the specified attribute name
 // is only relevant for a controller.
 func ControllerOnlyAttribute(attr string) bool {
@@ -197,6 +325,271 @@ func (c Config) AuditingEnabled() bool {
 	return false
 }
 
+// APITracingEnabled returns whether API requests are traced. The
+// default is false.
+func (c Config) APITracingEnabled() bool {
+	value, _ := c[APITracingEnabled].(bool)
+	return value
+}
+
+// APIUserRequestRate returns the number of API requests per second
+// that each user may make to each facade, or zero if user requests
+// are not rate limited.
+func (c Config) APIUserRequestRate() int {
+	return c.optionalInt(APIUserRequestRate)
+}
+
+// APIUserRequestBurst returns the number of API requests that each
+// user may make to each facade at once, or zero if it is the same as
+// the rate.
+func (c Config) APIUserRequestBurst() int {
+	return c.optionalInt(APIUserRequestBurst)
+}
+
+// APIAgentRequestRate returns the number of API requests per second
+// that each agent may make to each facade, or zero if agent requests
+// are not rate limited.
+func (c Config) APIAgentRequestRate() int {
+	return c.optionalInt(APIAgentRequestRate)
+}
+
+// APIAgentRequestBurst returns the number of API requests that each
+// agent may make to each facade at once, or zero if it is the same as
+// the rate.
+func (c Config) APIAgentRequestBurst() int {
+	return c.optionalInt(APIAgentRequestBurst)
+}
+
+// RequestLimit describes an API request rate limit.
+type RequestLimit struct {
+	// Rate is the number of requests per second that may be made.
+	Rate int
+
+	// Burst is the number of requests that may be made at once
+	// after a period of inactivity, or zero if it is the same as
+	// the rate.
+	Burst int
+}
+
+// APIFacadeRequestLimits returns the API request rate limits for
+// particular facades, keyed by facade name.
+func (c Config) APIFacadeRequestLimits() map[string]RequestLimit {
+	// Validate has checked that the value parses.
+	limits, _ := parseRequestLimits(c.asString(APIFacadeRequestLimits))
+	return limits
+}
+
+// parseRequestLimits parses a comma-separated list of facade=rate or
+// facade=rate/burst entries.
+func parseRequestLimits(value string) (map[string]RequestLimit, error) {
+	limits := make(map[string]RequestLimit)
+	for _, entry := range strings.Split(value, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.SplitN(entry, "=", 2)
+		facade := strings.TrimSpace(parts[0])
+		if len(parts) != 2 || facade == "" {
+			return nil, errors.Errorf("expected facade=rate[/burst], got %q", entry)
+		}
+		if _, ok := limits[facade]; ok {
+			return nil, errors.Errorf("duplicate limit for facade %q", facade)
+		}
+		values := strings.SplitN(parts[1], "/", 2)
+		var limit RequestLimit
+		var err error
+		if limit.Rate, err = parseRequestLimitValue(values[0]); err != nil {
+			return nil, errors.Annotatef(err, "rate for facade %q", facade)
+		}
+		if len(values) == 2 {
+			if limit.Burst, err = parseRequestLimitValue(values[1]); err != nil {
+				return nil, errors.Annotatef(err, "burst for facade %q", facade)
+			}
+		}
+		limits[facade] = limit
+	}
+	return limits, nil
+}
+
+func parseRequestLimitValue(value string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSpace(value))
+	if err != nil {
+		return 0, errors.Errorf("expected a number, got %q", value)
+	}
+	if n < 0 {
+		return 0, errors.New("must not be negative")
+	}
+	return n, nil
+}
+
+// APIWebsocketCompression returns whether messages on API connections
+// are compressed. The default is false.
+func (c Config) APIWebsocketCompression() bool {
+	value, _ := c[APIWebsocketCompression].(bool)
+	return value
+}
+
+// optionalInt returns the named attribute as an integer, or zero if
+// it is not set.
+func (c Config) optionalInt(name string) int {
+	// Values obtained over the api are encoded as float64.
+	if value, ok := c[name].(float64); ok {
+		return int(value)
+	}
+	value, _ := c[name].(int)
+	return value
+}
+
+// AuditLogMaxSizeMB returns the size in megabytes at which the audit
+// log file is rotated.
+func (c Config) AuditLogMaxSizeMB() int {
+	size := c.asString(AuditLogMaxSize)
+	if size == "" {
+		size = DefaultAuditLogMaxSize
+	}
+	// The value has been checked by Validate.
+	mb, _ := utils.ParseSize(size)
+	return int(mb)
+}
+
+// AuditLogMaxBackups returns the number of rotated audit log files
+// to keep.
+func (c Config) AuditLogMaxBackups() int {
+	// Values obtained over the api are encoded as float64.
+	if value, ok := c[AuditLogMaxBackups].(float64); ok {
+		return int(value)
+	}
+	if value, ok := c[AuditLogMaxBackups].(int); ok {
+		return value
+	}
+	return DefaultAuditLogMaxBackups
+}
+
+// AuditLogRotateInterval returns the age at which the audit log file
+// is rotated regardless of its size, or zero if it is only rotated
+// based on size.
+func (c Config) AuditLogRotateInterval() time.Duration {
+	// The value has been checked by Validate.
+	d, _ := time.ParseDuration(c.asString(AuditLogRotateInterval))
+	return d
+}
+
+// BackupSchedule returns the schedule on which the controller creates
+// backups of itself, or nil if there is no schedule.
+func (c Config) BackupSchedule() schedule.Schedule {
+	spec := c.asString(BackupSchedule)
+	if spec == "" {
+		return nil
+	}
+	// The value has been checked by Validate.
+	sched, _ := schedule.Parse(spec)
+	return sched
+}
+
+// BackupRetentionCount returns the number of scheduled backups to
+// keep.
+func (c Config) BackupRetentionCount() int {
+	// Values obtained over the api are encoded as float64.
+	if value, ok := c[BackupRetentionCount].(float64); ok {
+		return int(value)
+	}
+	if value, ok := c[BackupRetentionCount].(int); ok {
+		return value
+	}
+	return DefaultBackupRetentionCount
+}
+
+// BackupEncryptionKey returns the key with which scheduled backups are
+// encrypted, or nil if they aren't.
+func (c Config) BackupEncryptionKey() *encryption.Key {
+	value := c.asString(BackupEncryptionKey)
+	if value == "" {
+		return nil
+	}
+	key, err := encryption.ParseKey([]byte(value))
+	if err != nil {
+		// Validate ensures this doesn't happen.
+		return nil
+	}
+	return key
+}
+
+// BackupStoreConfig returns the configuration of the store to which
+// scheduled backups are copied, and whether one is configured.
+func (c Config) BackupStoreConfig() (BackupStoreConfig, bool) {
+	url := c.asString(BackupStore)
+	if url == "" {
+		return BackupStoreConfig{}, false
+	}
+	return BackupStoreConfig{
+		URL:         url,
+		S3Endpoint:  c.asString(BackupStoreS3Endpoint),
+		S3Region:    c.asString(BackupStoreS3Region),
+		S3AccessKey: c.asString(BackupStoreS3AccessKey),
+		S3SecretKey: c.asString(BackupStoreS3SecretKey),
+	}, true
+}
+
+// BackupStoreConfig describes the store to which scheduled backups are
+// copied.
+type BackupStoreConfig struct {
+	// URL identifies the store. It is either a file URL naming a
+	// directory, e.g. file:///mnt/backups, or an s3 URL naming a
+	// bucket and an optional key prefix, e.g. s3://bucket/juju.
+	URL string
+
+	// S3Endpoint is the http or https URL of the S3-compatible
+	// service. If it is empty, Amazon S3 is used.
+	S3Endpoint string
+
+	// S3Region is the region used to sign requests. If it is empty,
+	// us-east-1 is used.
+	S3Region string
+
+	// S3AccessKey and S3SecretKey are the credentials used to sign
+	// requests to the S3-compatible service.
+	S3AccessKey string
+	S3SecretKey string
+}
+
+// Validate returns an error if the config does not describe a usable
+// backup store.
+func (cfg BackupStoreConfig) Validate() error {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return errors.Annotate(err, "parsing URL")
+	}
+	switch u.Scheme {
+	case "file":
+		if u.Host != "" && u.Host != "localhost" {
+			return errors.NotValidf("file URL %q with remote host", cfg.URL)
+		}
+		if !strings.HasPrefix(u.Path, "/") {
+			return errors.NotValidf("file URL %q without absolute path", cfg.URL)
+		}
+	case "s3":
+		if u.Host == "" {
+			return errors.NotValidf("s3 URL %q without bucket", cfg.URL)
+		}
+		if cfg.S3AccessKey == "" || cfg.S3SecretKey == "" {
+			return errors.NotValidf("s3 store without credentials")
+		}
+		if cfg.S3Endpoint != "" {
+			endpoint, err := url.Parse(cfg.S3Endpoint)
+			if err != nil {
+				return errors.Annotate(err, "parsing S3 endpoint")
+			}
+			if endpoint.Scheme != "http" && endpoint.Scheme != "https" || endpoint.Host == "" {
+				return errors.NotValidf("S3 endpoint %q", cfg.S3Endpoint)
+			}
+		}
+	default:
+		return errors.NotValidf("backup store URL %q (expected file or s3)", cfg.URL)
+	}
+	return nil
+}
+
 // ControllerUUID returns the uuid for the model's controller.
 func (c Config) ControllerUUID() string {
 	return c.mustString(ControllerUUIDKey)
@@ -310,6 +703,69 @@ func Validate(c Config) error {
 		}
 	}
 
+	if v, ok := c[AuditLogMaxSize].(string); ok {
+		if mb, err := utils.ParseSize(v); err != nil {
+			return errors.Annotatef(err, "invalid %s", AuditLogMaxSize)
+		} else if mb == 0 {
+			return errors.Errorf("invalid %s: must be greater than zero", AuditLogMaxSize)
+		}
+	}
+
+	for _, name := range []string{
+		APIUserRequestRate,
+		APIUserRequestBurst,
+		APIAgentRequestRate,
+		APIAgentRequestBurst,
+	} {
+		if c.optionalInt(name) < 0 {
+			return errors.Errorf("invalid %s: must not be negative", name)
+		}
+	}
+
+	if v, ok := c[APIFacadeRequestLimits].(string); ok {
+		if _, err := parseRequestLimits(v); err != nil {
+			return errors.Annotatef(err, "invalid %s", APIFacadeRequestLimits)
+		}
+	}
+
+	if _, ok := c[AuditLogMaxBackups]; ok && c.AuditLogMaxBackups() < 1 {
+		return errors.Errorf("invalid %s: must be greater than zero", AuditLogMaxBackups)
+	}
+
+	if v, ok := c[AuditLogRotateInterval].(string); ok {
+		if d, err := time.ParseDuration(v); err != nil {
+			return errors.Annotatef(err, "invalid %s", AuditLogRotateInterval)
+		} else if d < 0 {
+			return errors.Errorf("invalid %s: must not be negative", AuditLogRotateInterval)
+		}
+	}
+
+	if v, ok := c[BackupSchedule].(string); ok && v != "" {
+		if _, err := schedule.Parse(v); err != nil {
+			return errors.Annotatef(err, "invalid %s", BackupSchedule)
+		}
+	}
+
+	if _, ok := c[BackupRetentionCount]; ok && c.BackupRetentionCount() < 1 {
+		return errors.Errorf("invalid %s: must be greater than zero", BackupRetentionCount)
+	}
+
+	if storeConfig, ok := c.BackupStoreConfig(); ok {
+		if err := storeConfig.Validate(); err != nil {
+			return errors.Annotatef(err, "invalid %s", BackupStore)
+		}
+	}
+
+	if v, ok := c[BackupEncryptionKey].(string); ok && v != "" {
+		key, err := encryption.ParseKey([]byte(v))
+		if err != nil {
+			return errors.Annotatef(err, "invalid %s", BackupEncryptionKey)
+		}
+		if key.CanDecrypt() {
+			return errors.Errorf("invalid %s: must be a public key, not a secret or private key", BackupEncryptionKey)
+		}
+	}
+
 	return nil
 }
 
@@ -321,7 +777,25 @@ func GenerateControllerCertAndKey(caCert, caKey string, hostAddresses []string)
 
 var configChecker = schema.FieldMap(schema.Fields{
 	AuditingEnabled:         schema.Bool(),
+	AuditLogMaxSize:         schema.String(),
+	AuditLogMaxBackups:      schema.ForceInt(),
+	AuditLogRotateInterval:  schema.String(),
 	APIPort:                 schema.ForceInt(),
+	APITracingEnabled:       schema.Bool(),
+	APIUserRequestRate:      schema.ForceInt(),
+	APIUserRequestBurst:     schema.ForceInt(),
+	APIAgentRequestRate:     schema.ForceInt(),
+	APIAgentRequestBurst:    schema.ForceInt(),
+	APIFacadeRequestLimits:  schema.String(),
+	APIWebsocketCompression: schema.Bool(),
+	BackupSchedule:          schema.String(),
+	BackupRetentionCount:    schema.ForceInt(),
+	BackupStore:             schema.String(),
+	BackupStoreS3Endpoint:   schema.String(),
+	BackupStoreS3Region:     schema.String(),
+	BackupStoreS3AccessKey:  schema.String(),
+	BackupStoreS3SecretKey:  schema.String(),
+	BackupEncryptionKey:     schema.String(),
 	StatePort:               schema.ForceInt(),
 	IdentityURL:             schema.String(),
 	IdentityPublicKey:       schema.String(),
@@ -332,7 +806,25 @@ var configChecker = schema.FieldMap(schema.Fields{
 	MongoMemoryProfile:      schema.String(),
 }, schema.Defaults{
 	APIPort:                 DefaultAPIPort,
+	APITracingEnabled:       schema.Omit,
+	APIUserRequestRate:      schema.Omit,
+	APIUserRequestBurst:     schema.Omit,
+	APIAgentRequestRate:     schema.Omit,
+	APIAgentRequestBurst:    schema.Omit,
+	APIFacadeRequestLimits:  schema.Omit,
+	APIWebsocketCompression: schema.Omit,
 	AuditingEnabled:         DefaultAuditingEnabled,
+	AuditLogMaxSize:         schema.Omit,
+	AuditLogMaxBackups:      schema.Omit,
+	AuditLogRotateInterval:  schema.Omit,
+	BackupSchedule:          schema.Omit,
+	BackupRetentionCount:    schema.Omit,
+	BackupStore:             schema.Omit,
+	BackupStoreS3Endpoint:   schema.Omit,
+	BackupStoreS3Region:     schema.Omit,
+	BackupStoreS3AccessKey:  schema.Omit,
+	BackupStoreS3SecretKey:  schema.Omit,
+	BackupEncryptionKey:     schema.Omit,
 	StatePort:               DefaultStatePort,
 	IdentityURL:             schema.Omit,
 	IdentityPublicKey:       schema.Omit,
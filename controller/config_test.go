@@ -4,6 +4,11 @@
 package controller_test
 
 import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"strings"
 	stdtesting "testing"
 	"time"
 
@@ -121,8 +126,319 @@ var validateTests = []struct {
 		controller.CACertKey:         testing.CACert,
 	},
 	expectError: `invalid identity public key: wrong length for base64 key, got 3 want 32`,
+}, {
+	about: "valid audit log config",
+	config: controller.Config{
+		controller.AuditLogMaxSize:        "1G",
+		controller.AuditLogMaxBackups:     5,
+		controller.AuditLogRotateInterval: "24h",
+		controller.CACertKey:              testing.CACert,
+	},
+}, {
+	about: "invalid audit log max size",
+	config: controller.Config{
+		controller.AuditLogMaxSize: "1Q",
+		controller.CACertKey:       testing.CACert,
+	},
+	expectError: `invalid audit-log-max-size: .*`,
+}, {
+	about: "zero audit log max backups",
+	config: controller.Config{
+		controller.AuditLogMaxBackups: 0,
+		controller.CACertKey:          testing.CACert,
+	},
+	expectError: `invalid audit-log-max-backups: must be greater than zero`,
+}, {
+	about: "invalid audit log rotate interval",
+	config: controller.Config{
+		controller.AuditLogRotateInterval: "daily",
+		controller.CACertKey:              testing.CACert,
+	},
+	expectError: `invalid audit-log-rotate-interval: .*`,
+}, {
+	about: "valid backup config",
+	config: controller.Config{
+		controller.BackupSchedule:         "30 2 * * *",
+		controller.BackupRetentionCount:   3,
+		controller.BackupStore:            "s3://backups/juju",
+		controller.BackupStoreS3Endpoint:  "https://10.0.0.1:9000",
+		controller.BackupStoreS3AccessKey: "access",
+		controller.BackupStoreS3SecretKey: "secret",
+		controller.CACertKey:              testing.CACert,
+	},
+}, {
+	about: "invalid backup schedule",
+	config: controller.Config{
+		controller.BackupSchedule: "nightly",
+		controller.CACertKey:      testing.CACert,
+	},
+	expectError: `invalid backup-schedule: invalid schedule "nightly": expected 5 fields, got 1`,
+}, {
+	about: "zero backup retention count",
+	config: controller.Config{
+		controller.BackupRetentionCount: 0,
+		controller.CACertKey:            testing.CACert,
+	},
+	expectError: `invalid backup-retention-count: must be greater than zero`,
+}, {
+	about: "invalid backup store",
+	config: controller.Config{
+		controller.BackupStore: "s3://backups",
+		controller.CACertKey:   testing.CACert,
+	},
+	expectError: `invalid backup-store: s3 store without credentials not valid`,
+}, {
+	about: "valid backup encryption key",
+	config: controller.Config{
+		controller.BackupEncryptionKey: backupPublicKey,
+		controller.CACertKey:           testing.CACert,
+	},
+}, {
+	about: "secret backup encryption key",
+	config: controller.Config{
+		controller.BackupEncryptionKey: "correct horse battery staple",
+		controller.CACertKey:           testing.CACert,
+	},
+	expectError: `invalid backup-encryption-key: must be a public key, not a secret or private key`,
+}, {
+	about: "invalid backup encryption key",
+	config: controller.Config{
+		controller.BackupEncryptionKey: "short",
+		controller.CACertKey:           testing.CACert,
+	},
+	expectError: `invalid backup-encryption-key: encryption key too short \(need at least 16 bytes\)`,
+}, {
+	about: "valid API request rate limits",
+	config: controller.Config{
+		controller.APIUserRequestRate:   10,
+		controller.APIUserRequestBurst:  50,
+		controller.APIAgentRequestRate:  20,
+		controller.APIAgentRequestBurst: 0,
+		controller.CACertKey:            testing.CACert,
+	},
+}, {
+	about: "negative API request rate",
+	config: controller.Config{
+		controller.APIUserRequestRate: -1,
+		controller.CACertKey:          testing.CACert,
+	},
+	expectError: `invalid api-user-request-rate: must not be negative`,
+}, {
+	about: "valid API facade request limits",
+	config: controller.Config{
+		controller.APIFacadeRequestLimits: "Client=5/10, Uniter=0",
+		controller.CACertKey:              testing.CACert,
+	},
+}, {
+	about: "malformed API facade request limits",
+	config: controller.Config{
+		controller.APIFacadeRequestLimits: "Client",
+		controller.CACertKey:              testing.CACert,
+	},
+	expectError: `invalid api-facade-request-limits: expected facade=rate\[/burst\], got "Client"`,
+}, {
+	about: "negative API facade request burst",
+	config: controller.Config{
+		controller.APIFacadeRequestLimits: "Client=5/-1",
+		controller.CACertKey:              testing.CACert,
+	},
+	expectError: `invalid api-facade-request-limits: burst for facade "Client": must not be negative`,
+}, {
+	about: "duplicate API facade request limits",
+	config: controller.Config{
+		controller.APIFacadeRequestLimits: "Client=5,Client=6",
+		controller.CACertKey:              testing.CACert,
+	},
+	expectError: `invalid api-facade-request-limits: duplicate limit for facade "Client"`,
 }}
 
+func (s *ConfigSuite) TestAuditLogDefaults(c *gc.C) {
+	cfg := controller.Config{}
+	c.Assert(cfg.AuditLogMaxSizeMB(), gc.Equals, 300)
+	c.Assert(cfg.AuditLogMaxBackups(), gc.Equals, 10)
+	c.Assert(cfg.AuditLogRotateInterval(), gc.Equals, time.Duration(0))
+}
+
+func (s *ConfigSuite) TestAuditLogValues(c *gc.C) {
+	cfg := controller.Config{
+		controller.AuditLogMaxSize:        "1G",
+		controller.AuditLogMaxBackups:     float64(5),
+		controller.AuditLogRotateInterval: "24h",
+	}
+	c.Assert(cfg.AuditLogMaxSizeMB(), gc.Equals, 1024)
+	c.Assert(cfg.AuditLogMaxBackups(), gc.Equals, 5)
+	c.Assert(cfg.AuditLogRotateInterval(), gc.Equals, 24*time.Hour)
+}
+
+func (s *ConfigSuite) TestAPITracingEnabled(c *gc.C) {
+	cfg := controller.Config{}
+	c.Assert(cfg.APITracingEnabled(), jc.IsFalse)
+	cfg = controller.Config{controller.APITracingEnabled: true}
+	c.Assert(cfg.APITracingEnabled(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestAPIRequestRateLimits(c *gc.C) {
+	cfg := controller.Config{}
+	c.Assert(cfg.APIUserRequestRate(), gc.Equals, 0)
+	c.Assert(cfg.APIUserRequestBurst(), gc.Equals, 0)
+	c.Assert(cfg.APIAgentRequestRate(), gc.Equals, 0)
+	c.Assert(cfg.APIAgentRequestBurst(), gc.Equals, 0)
+
+	cfg = controller.Config{
+		controller.APIUserRequestRate:   float64(10),
+		controller.APIUserRequestBurst:  float64(50),
+		controller.APIAgentRequestRate:  20,
+		controller.APIAgentRequestBurst: 100,
+	}
+	c.Assert(cfg.APIUserRequestRate(), gc.Equals, 10)
+	c.Assert(cfg.APIUserRequestBurst(), gc.Equals, 50)
+	c.Assert(cfg.APIAgentRequestRate(), gc.Equals, 20)
+	c.Assert(cfg.APIAgentRequestBurst(), gc.Equals, 100)
+}
+
+func (s *ConfigSuite) TestAPIFacadeRequestLimits(c *gc.C) {
+	cfg := controller.Config{}
+	c.Assert(cfg.APIFacadeRequestLimits(), gc.HasLen, 0)
+
+	cfg = controller.Config{
+		controller.APIFacadeRequestLimits: "Client=5/10,Uniter=20",
+	}
+	c.Assert(cfg.APIFacadeRequestLimits(), jc.DeepEquals, map[string]controller.RequestLimit{
+		"Client": {Rate: 5, Burst: 10},
+		"Uniter": {Rate: 20},
+	})
+}
+
+func (s *ConfigSuite) TestAPIWebsocketCompression(c *gc.C) {
+	cfg := controller.Config{}
+	c.Assert(cfg.APIWebsocketCompression(), jc.IsFalse)
+	cfg = controller.Config{controller.APIWebsocketCompression: true}
+	c.Assert(cfg.APIWebsocketCompression(), jc.IsTrue)
+}
+
+func (s *ConfigSuite) TestBackupDefaults(c *gc.C) {
+	cfg := controller.Config{}
+	c.Assert(cfg.BackupSchedule(), gc.IsNil)
+	c.Assert(cfg.BackupRetentionCount(), gc.Equals, 7)
+	_, ok := cfg.BackupStoreConfig()
+	c.Assert(ok, jc.IsFalse)
+	c.Assert(cfg.BackupEncryptionKey(), gc.IsNil)
+}
+
+func (s *ConfigSuite) TestBackupValues(c *gc.C) {
+	cfg := controller.Config{
+		controller.BackupSchedule:         "@daily",
+		controller.BackupRetentionCount:   float64(3),
+		controller.BackupStore:            "s3://backups/juju",
+		controller.BackupStoreS3Region:    "eu-west-1",
+		controller.BackupStoreS3AccessKey: "access",
+		controller.BackupStoreS3SecretKey: "secret",
+	}
+	now := time.Date(2017, time.March, 1, 10, 0, 0, 0, time.UTC)
+	c.Assert(cfg.BackupSchedule().Next(now), gc.Equals, time.Date(2017, time.March, 2, 0, 0, 0, 0, time.UTC))
+	c.Assert(cfg.BackupRetentionCount(), gc.Equals, 3)
+	storeConfig, ok := cfg.BackupStoreConfig()
+	c.Assert(ok, jc.IsTrue)
+	c.Assert(storeConfig, jc.DeepEquals, controller.BackupStoreConfig{
+		URL:         "s3://backups/juju",
+		S3Region:    "eu-west-1",
+		S3AccessKey: "access",
+		S3SecretKey: "secret",
+	})
+}
+
+func (s *ConfigSuite) TestBackupStoreConfigValidate(c *gc.C) {
+	for i, test := range []struct {
+		cfg controller.BackupStoreConfig
+		err string
+	}{{
+		cfg: controller.BackupStoreConfig{URL: "file:///var/backups"},
+	}, {
+		cfg: controller.BackupStoreConfig{URL: "file://localhost/var/backups"},
+	}, {
+		cfg: controller.BackupStoreConfig{URL: "file://nfs-server/var/backups"},
+		err: `file URL "file://nfs-server/var/backups" with remote host not valid`,
+	}, {
+		cfg: controller.BackupStoreConfig{URL: "file:backups"},
+		err: `file URL "file:backups" without absolute path not valid`,
+	}, {
+		cfg: controller.BackupStoreConfig{
+			URL:         "s3://bucket/juju",
+			S3AccessKey: "access",
+			S3SecretKey: "secret",
+		},
+	}, {
+		cfg: controller.BackupStoreConfig{
+			URL:         "s3://bucket",
+			S3Endpoint:  "http://10.0.0.1:9000",
+			S3AccessKey: "access",
+			S3SecretKey: "secret",
+		},
+	}, {
+		cfg: controller.BackupStoreConfig{URL: "s3://bucket"},
+		err: `s3 store without credentials not valid`,
+	}, {
+		cfg: controller.BackupStoreConfig{URL: "s3:///juju", S3AccessKey: "a", S3SecretKey: "s"},
+		err: `s3 URL "s3:///juju" without bucket not valid`,
+	}, {
+		cfg: controller.BackupStoreConfig{
+			URL:         "s3://bucket",
+			S3Endpoint:  "ftp://10.0.0.1",
+			S3AccessKey: "access",
+			S3SecretKey: "secret",
+		},
+		err: `S3 endpoint "ftp://10.0.0.1" not valid`,
+	}, {
+		cfg: controller.BackupStoreConfig{URL: "/var/backups"},
+		err: `backup store URL "/var/backups" \(expected file or s3\) not valid`,
+	}} {
+		c.Logf("test %d: %q", i, test.cfg.URL)
+		err := test.cfg.Validate()
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+// backupPublicKey is an RSA public key with which backups may be
+// encrypted.
+const backupPublicKey = `
+-----BEGIN PUBLIC KEY-----
+MIGfMA0GCSqGSIb3DQEBAQUAA4GNADCBiQKBgQCyMeFW6/L9Gtwe47nbW8UtABh3
+Ks1FO3QvTSpsAg8FA8HDikUbgvmzRT4tSHDj2NCqWC4jFZosHTIEQc4ZYDLpBeYy
+kW2I8AklSTXMYk1A97UPENC03+T+ai+ftUsy6dvx+R8BjOEu8KHVmc60UIVna/8B
+4MZDMmIMMm/2pY8p6QIDAQAB
+-----END PUBLIC KEY-----
+`
+
+func (s *ConfigSuite) TestBackupEncryptionKey(c *gc.C) {
+	cfg := controller.Config{
+		controller.BackupEncryptionKey: backupPublicKey,
+	}
+	key := cfg.BackupEncryptionKey()
+	c.Assert(key, gc.NotNil)
+	c.Assert(key.CanDecrypt(), jc.IsFalse)
+	publicKey, err := key.EncryptionOnly()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(publicKey), gc.Equals, strings.TrimPrefix(backupPublicKey, "\n"))
+}
+
+func (s *ConfigSuite) TestBackupEncryptionKeyPrivate(c *gc.C) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	c.Assert(err, jc.ErrorIsNil)
+	cfg := controller.Config{
+		controller.BackupEncryptionKey: string(pem.EncodeToMemory(&pem.Block{
+			Type:  "RSA PRIVATE KEY",
+			Bytes: x509.MarshalPKCS1PrivateKey(privateKey),
+		})),
+		controller.CACertKey: testing.CACert,
+	}
+	err = cfg.Validate()
+	c.Assert(err, gc.ErrorMatches, "invalid backup-encryption-key: must be a public key, not a secret or private key")
+}
+
 func (s *ConfigSuite) TestValidate(c *gc.C) {
 	for i, test := range validateTests {
 		c.Logf("test %d: %v", i, test.about)
@@ -0,0 +1,203 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package schedule parses cron-like descriptions of when a periodic
+// task should run.
+package schedule
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// Schedule determines when a periodic task runs.
+type Schedule interface {
+	// Next returns the first time after t at which the task should
+	// run, or the zero time if it never runs again.
+	Next(t time.Time) time.Time
+}
+
+// Parse returns the schedule described by spec, which is one of:
+//
+//   - a cron expression of five space-separated fields: minute
+//     (0-59), hour (0-23), day of month (1-31), month (1-12) and day
+//     of week (0-6, with 0 being Sunday). Each field is "*", a
+//     number, a range "a-b", or a comma-separated list of those, any
+//     of which may be followed by a step "/n". For example,
+//     "30 2 * * *" runs at 02:30 every day.
+//   - "@hourly", "@daily" (or "@midnight"), "@weekly", "@monthly".
+//   - "@every <duration>", e.g. "@every 6h", which runs at multiples
+//     of the duration since the Unix epoch.
+//
+// Cron schedules are evaluated in UTC.
+func Parse(spec string) (Schedule, error) {
+	spec = strings.TrimSpace(spec)
+	if strings.HasPrefix(spec, "@every") {
+		d, err := time.ParseDuration(strings.TrimSpace(strings.TrimPrefix(spec, "@every")))
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid schedule %q", spec)
+		}
+		if d < time.Minute {
+			return nil, errors.Errorf("invalid schedule %q: interval must be at least a minute", spec)
+		}
+		return every(d), nil
+	}
+	switch spec {
+	case "@hourly":
+		spec = "0 * * * *"
+	case "@daily", "@midnight":
+		spec = "0 0 * * *"
+	case "@weekly":
+		spec = "0 0 * * 0"
+	case "@monthly":
+		spec = "0 0 1 * *"
+	}
+	fields := strings.Fields(spec)
+	if len(fields) != 5 {
+		return nil, errors.Errorf("invalid schedule %q: expected 5 fields, got %d", spec, len(fields))
+	}
+	var s cron
+	for i, f := range []struct {
+		bits     *uint64
+		min, max uint
+	}{
+		{&s.minute, 0, 59},
+		{&s.hour, 0, 23},
+		{&s.dom, 1, 31},
+		{&s.month, 1, 12},
+		{&s.dow, 0, 6},
+	} {
+		bits, err := parseField(fields[i], f.min, f.max)
+		if err != nil {
+			return nil, errors.Annotatef(err, "invalid schedule %q", spec)
+		}
+		*f.bits = bits
+	}
+	// As in cron, if both day fields are restricted a day matches
+	// if either of them does.
+	s.anyDay = fields[2] == "*" || fields[4] == "*"
+	return &s, nil
+}
+
+// every is a schedule which runs at a fixed interval.
+type every time.Duration
+
+// Next is part of the Schedule interface.
+func (e every) Next(t time.Time) time.Time {
+	// Time.Truncate rounds relative to the zero Time, not to the Unix
+	// epoch, so work out how far past a multiple of the interval t is.
+	d := time.Duration(e)
+	offset := time.Duration(t.UnixNano()) % d
+	if offset < 0 {
+		offset += d
+	}
+	return t.Add(d - offset)
+}
+
+// cron is a schedule described by a cron expression. Each field is a
+// bitmask of the values it matches.
+type cron struct {
+	minute, hour, dom, month, dow uint64
+	anyDay                        bool
+}
+
+// maxSearch bounds the search for the next matching time, so that
+// schedules which can never match (e.g. 30 February) terminate.
+const maxSearch = 5 * 366 * 24 * time.Hour
+
+// Next is part of the Schedule interface.
+func (s *cron) Next(t time.Time) time.Time {
+	t = t.UTC().Truncate(time.Minute).Add(time.Minute)
+	limit := t.Add(maxSearch)
+	for t.Before(limit) {
+		if !has(s.month, uint(t.Month())) {
+			t = time.Date(t.Year(), t.Month()+1, 1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !s.dayMatches(t) {
+			t = time.Date(t.Year(), t.Month(), t.Day()+1, 0, 0, 0, 0, time.UTC)
+			continue
+		}
+		if !has(s.hour, uint(t.Hour())) {
+			t = t.Truncate(time.Hour).Add(time.Hour)
+			continue
+		}
+		if !has(s.minute, uint(t.Minute())) {
+			t = t.Add(time.Minute)
+			continue
+		}
+		return t
+	}
+	return time.Time{}
+}
+
+func (s *cron) dayMatches(t time.Time) bool {
+	dom := has(s.dom, uint(t.Day()))
+	dow := has(s.dow, uint(t.Weekday()))
+	if s.anyDay {
+		return dom && dow
+	}
+	return dom || dow
+}
+
+func has(bits uint64, n uint) bool {
+	return bits&(1<<n) != 0
+}
+
+// parseField returns the bitmask of values matched by a single cron
+// field.
+func parseField(field string, min, max uint) (uint64, error) {
+	var bits uint64
+	for _, part := range strings.Split(field, ",") {
+		rangePart, step := part, uint(1)
+		if i := strings.Index(part, "/"); i >= 0 {
+			n, err := strconv.ParseUint(part[i+1:], 10, 8)
+			if err != nil || n == 0 {
+				return 0, errors.Errorf("invalid step in %q", part)
+			}
+			rangePart, step = part[:i], uint(n)
+		}
+		var lo, hi uint
+		switch {
+		case rangePart == "*":
+			lo, hi = min, max
+		case strings.Contains(rangePart, "-"):
+			bounds := strings.SplitN(rangePart, "-", 2)
+			var err error
+			if lo, err = parseValue(bounds[0], min, max); err != nil {
+				return 0, errors.Trace(err)
+			}
+			if hi, err = parseValue(bounds[1], min, max); err != nil {
+				return 0, errors.Trace(err)
+			}
+			if lo > hi {
+				return 0, errors.Errorf("invalid range %q", rangePart)
+			}
+		default:
+			n, err := parseValue(rangePart, min, max)
+			if err != nil {
+				return 0, errors.Trace(err)
+			}
+			lo, hi = n, n
+			if step > 1 {
+				// "n/step" means from n to the maximum.
+				hi = max
+			}
+		}
+		for n := lo; n <= hi; n += step {
+			bits |= 1 << n
+		}
+	}
+	return bits, nil
+}
+
+func parseValue(s string, min, max uint) (uint, error) {
+	n, err := strconv.ParseUint(s, 10, 8)
+	if err != nil || uint(n) < min || uint(n) > max {
+		return 0, errors.Errorf("value %q out of range %d-%d", s, min, max)
+	}
+	return uint(n), nil
+}
@@ -0,0 +1,137 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package schedule_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/schedule"
+)
+
+type scheduleSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&scheduleSuite{})
+
+// now is a Wednesday.
+var now = time.Date(2017, time.March, 1, 10, 15, 30, 0, time.UTC)
+
+func (s *scheduleSuite) TestNext(c *gc.C) {
+	for i, test := range []struct {
+		spec string
+		next []string
+	}{{
+		spec: "* * * * *",
+		next: []string{"2017-03-01T10:16:00Z", "2017-03-01T10:17:00Z"},
+	}, {
+		spec: "30 2 * * *",
+		next: []string{"2017-03-02T02:30:00Z", "2017-03-03T02:30:00Z"},
+	}, {
+		spec: "*/20 * * * *",
+		next: []string{"2017-03-01T10:20:00Z", "2017-03-01T10:40:00Z", "2017-03-01T11:00:00Z"},
+	}, {
+		spec: "5/30 9-10 * * *",
+		next: []string{"2017-03-01T10:35:00Z", "2017-03-02T09:05:00Z"},
+	}, {
+		spec: "0 0,12 * * *",
+		next: []string{"2017-03-01T12:00:00Z", "2017-03-02T00:00:00Z"},
+	}, {
+		spec: "0 0 * * 0",
+		next: []string{"2017-03-05T00:00:00Z", "2017-03-12T00:00:00Z"},
+	}, {
+		// With both day fields restricted, either may match.
+		spec: "0 0 10 * 5",
+		next: []string{"2017-03-03T00:00:00Z", "2017-03-10T00:00:00Z", "2017-03-17T00:00:00Z"},
+	}, {
+		spec: "0 0 31 * *",
+		next: []string{"2017-03-31T00:00:00Z", "2017-05-31T00:00:00Z"},
+	}, {
+		spec: "0 0 1 1 *",
+		next: []string{"2018-01-01T00:00:00Z", "2019-01-01T00:00:00Z"},
+	}, {
+		spec: "@hourly",
+		next: []string{"2017-03-01T11:00:00Z"},
+	}, {
+		spec: "@daily",
+		next: []string{"2017-03-02T00:00:00Z"},
+	}, {
+		spec: "@weekly",
+		next: []string{"2017-03-05T00:00:00Z"},
+	}, {
+		spec: "@monthly",
+		next: []string{"2017-04-01T00:00:00Z"},
+	}, {
+		spec: "@every 6h",
+		next: []string{"2017-03-01T12:00:00Z", "2017-03-01T18:00:00Z"},
+	}, {
+		// Intervals are counted from the Unix epoch.
+		spec: "@every 7h",
+		next: []string{"2017-03-01T17:00:00Z", "2017-03-02T00:00:00Z"},
+	}} {
+		c.Logf("test %d: %q", i, test.spec)
+		sched, err := schedule.Parse(test.spec)
+		c.Assert(err, jc.ErrorIsNil)
+		t := now
+		for _, expect := range test.next {
+			t = sched.Next(t)
+			c.Check(t.Format(time.RFC3339), gc.Equals, expect)
+		}
+	}
+}
+
+func (s *scheduleSuite) TestNextNever(c *gc.C) {
+	sched, err := schedule.Parse("0 0 30 2 *")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(sched.Next(now).IsZero(), jc.IsTrue)
+}
+
+func (s *scheduleSuite) TestNextConvertsToUTC(c *gc.C) {
+	sched, err := schedule.Parse("0 3 * * *")
+	c.Assert(err, jc.ErrorIsNil)
+	local := now.In(time.FixedZone("UTC+5", 5*60*60))
+	c.Assert(sched.Next(local), gc.Equals, time.Date(2017, time.March, 2, 3, 0, 0, 0, time.UTC))
+}
+
+func (s *scheduleSuite) TestParseErrors(c *gc.C) {
+	for i, test := range []struct {
+		spec string
+		err  string
+	}{{
+		spec: "",
+		err:  `invalid schedule "": expected 5 fields, got 0`,
+	}, {
+		spec: "* * * *",
+		err:  `invalid schedule "\* \* \* \*": expected 5 fields, got 4`,
+	}, {
+		spec: "60 * * * *",
+		err:  `invalid schedule "60 \* \* \* \*": value "60" out of range 0-59`,
+	}, {
+		spec: "* * 0 * *",
+		err:  `invalid schedule "\* \* 0 \* \*": value "0" out of range 1-31`,
+	}, {
+		spec: "* 5-2 * * *",
+		err:  `invalid schedule "\* 5-2 \* \* \*": invalid range "5-2"`,
+	}, {
+		spec: "*/0 * * * *",
+		err:  `invalid schedule "\*/0 \* \* \* \*": invalid step in "\*/0"`,
+	}, {
+		spec: "@yearly",
+		err:  `invalid schedule "@yearly": expected 5 fields, got 1`,
+	}, {
+		spec: "@every fortnight",
+		err:  `invalid schedule "@every fortnight": .*`,
+	}, {
+		spec: "@every 10s",
+		err:  `invalid schedule "@every 10s": interval must be at least a minute`,
+	}} {
+		c.Logf("test %d: %q", i, test.spec)
+		_, err := schedule.Parse(test.spec)
+		c.Check(err, gc.ErrorMatches, test.err)
+	}
+}
@@ -5,8 +5,10 @@ package config
 
 import (
 	"fmt"
+	"net/url"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -22,6 +24,10 @@ import (
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/environs/tags"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/gelf"
+	"github.com/juju/juju/logfwd/jsonhttp"
+	"github.com/juju/juju/logfwd/sinkconfig"
 	"github.com/juju/juju/logfwd/syslog"
 )
 
@@ -125,6 +131,43 @@ const (
 	// forwarding.
 	LogFwdSyslogClientKey = "syslog-client-key"
 
+	// LogFwdType sets the kind of log forwarding target: "syslog"
+	// (the default), "http" or "gelf".
+	LogFwdType = "logforward-type"
+
+	// LogFwdURL sets the target of http and gelf log forwarding.
+	// It is an http or https URL for http forwarding, and a
+	// tcp://host:port or tls://host:port URL for gelf forwarding.
+	LogFwdURL = "logforward-url"
+
+	// LogFwdHTTPFormat sets how batches of records are encoded for
+	// http log forwarding: "json" (the default) or "elasticsearch".
+	LogFwdHTTPFormat = "logforward-http-format"
+
+	// LogFwdCACert sets the certificate of the CA that signed the
+	// http or gelf server certificate.
+	LogFwdCACert = "logforward-ca-cert"
+
+	// LogFwdClientCert sets the client certificate for http or gelf
+	// forwarding.
+	LogFwdClientCert = "logforward-client-cert"
+
+	// LogFwdClientKey sets the client key for http or gelf
+	// forwarding.
+	LogFwdClientKey = "logforward-client-key"
+
+	// LogFwdBatchSize sets the maximum number of records forwarded
+	// at a time.
+	LogFwdBatchSize = "logforward-batch-size"
+
+	// LogFwdRetryAttempts sets the number of times a failed batch
+	// of records is resent.
+	LogFwdRetryAttempts = "logforward-retry-attempts"
+
+	// LogFwdRetryDelay sets how long to wait before resending a
+	// failed batch of records.
+	LogFwdRetryDelay = "logforward-retry-delay"
+
 	// AutomaticallyRetryHooks determines whether the uniter will
 	// automatically retry a hook that has failed
 	AutomaticallyRetryHooks = "automatically-retry-hooks"
@@ -137,6 +180,24 @@ const (
 	// is stored against the model.
 	ExtraInfoKey = "extra-info"
 
+	// LogsMaxAgeKey is the key for the maximum age of the model's log
+	// records kept in the controller's database, e.g. "72h".
+	LogsMaxAgeKey = "logs-max-age"
+
+	// LogsMaxSizeKey is the key for the maximum amount of space the
+	// model's log records may use in the controller's database,
+	// e.g. "500M".
+	LogsMaxSizeKey = "logs-max-size"
+
+	// ActionsMaxAgeKey is the key for the maximum age of the model's
+	// finished actions kept in the controller's database, e.g. "336h".
+	ActionsMaxAgeKey = "actions-max-age"
+
+	// ActionsMaxSizeKey is the key for the maximum amount of space the
+	// model's finished actions may use in the controller's database,
+	// e.g. "5G".
+	ActionsMaxSizeKey = "actions-max-size"
+
 	//
 	// Deprecated Settings Attributes
 	//
@@ -463,9 +524,32 @@ func Validate(cfg, old *Config) error {
 		}
 	}
 
-	if lfCfg, ok := cfg.LogFwdSyslog(); ok {
-		if err := lfCfg.Validate(); err != nil {
-			return errors.Annotate(err, "invalid syslog forwarding config")
+	if err := cfg.validateLogForwarding(); err != nil {
+		return errors.Trace(err)
+	}
+
+	if v := cfg.asString(LogsMaxAgeKey); v != "" {
+		if maxAge, err := time.ParseDuration(v); err != nil {
+			return errors.Annotatef(err, "invalid %s in model configuration", LogsMaxAgeKey)
+		} else if maxAge < 0 {
+			return errors.Errorf("invalid %s in model configuration: negative duration %q", LogsMaxAgeKey, v)
+		}
+	}
+	if v := cfg.asString(LogsMaxSizeKey); v != "" {
+		if _, err := utils.ParseSize(v); err != nil {
+			return errors.Annotatef(err, "invalid %s in model configuration", LogsMaxSizeKey)
+		}
+	}
+	if v := cfg.asString(ActionsMaxAgeKey); v != "" {
+		if maxAge, err := time.ParseDuration(v); err != nil {
+			return errors.Annotatef(err, "invalid %s in model configuration", ActionsMaxAgeKey)
+		} else if maxAge < 0 {
+			return errors.Errorf("invalid %s in model configuration: negative duration %q", ActionsMaxAgeKey, v)
+		}
+	}
+	if v := cfg.asString(ActionsMaxSizeKey); v != "" {
+		if _, err := utils.ParseSize(v); err != nil {
+			return errors.Annotatef(err, "invalid %s in model configuration", ActionsMaxSizeKey)
 		}
 	}
 
@@ -705,6 +789,112 @@ func (c *Config) LogFwdSyslog() (*syslog.RawConfig, bool) {
 	return &lfCfg, true
 }
 
+// LogForwardConfig returns the log forwarding config, whichever kind
+// of target it describes.
+func (c *Config) LogForwardConfig() (*sinkconfig.Config, bool) {
+	lfCfg, ok, _ := c.logForwardConfig()
+	return lfCfg, ok
+}
+
+// logForwardConfig is like LogForwardConfig, but also returns the
+// first error met parsing the attributes the config is built from.
+func (c *Config) logForwardConfig() (*sinkconfig.Config, bool, error) {
+	var parseErr error
+	syslogCfg, partial := c.LogFwdSyslog()
+	var lfCfg sinkconfig.Config
+	if partial {
+		lfCfg.Enabled = syslogCfg.Enabled
+		lfCfg.Syslog = *syslogCfg
+	}
+
+	if s, ok := c.defined[LogFwdType]; ok && s != "" {
+		partial = true
+		lfCfg.Type = sinkconfig.SinkType(s.(string))
+	}
+
+	lfCfg.TLS = logfwd.TLSConfig{
+		CACert:     c.asString(LogFwdCACert),
+		ClientCert: c.asString(LogFwdClientCert),
+		ClientKey:  c.asString(LogFwdClientKey),
+	}
+	if lfCfg.TLS != (logfwd.TLSConfig{}) {
+		partial = true
+	}
+
+	if s, ok := c.defined[LogFwdURL]; ok && s != "" {
+		partial = true
+		switch lfCfg.Type {
+		case sinkconfig.TypeHTTP:
+			lfCfg.HTTP.URL = s.(string)
+		case sinkconfig.TypeGELF:
+			gelfCfg, err := parseGELFURL(s.(string))
+			if err != nil {
+				parseErr = errors.Annotatef(err, "invalid %s", LogFwdURL)
+			}
+			lfCfg.GELF = gelfCfg
+		}
+	}
+
+	if s, ok := c.defined[LogFwdHTTPFormat]; ok && s != "" {
+		partial = true
+		lfCfg.HTTP.Format = jsonhttp.Format(s.(string))
+	}
+
+	if n, ok := c.defined[LogFwdBatchSize].(int); ok {
+		partial = true
+		lfCfg.BatchSize = n
+	}
+
+	if n, ok := c.defined[LogFwdRetryAttempts].(int); ok {
+		partial = true
+		lfCfg.RetryAttempts = n
+	}
+
+	if s, ok := c.defined[LogFwdRetryDelay]; ok && s != "" {
+		partial = true
+		delay, err := time.ParseDuration(s.(string))
+		if err != nil && parseErr == nil {
+			parseErr = errors.Annotatef(err, "invalid %s", LogFwdRetryDelay)
+		}
+		lfCfg.RetryDelay = delay
+	}
+
+	if !partial {
+		return nil, false, nil
+	}
+	return &lfCfg, true, parseErr
+}
+
+// parseGELFURL returns the GELF config described by a tcp:// or
+// tls:// URL.
+func parseGELFURL(s string) (gelf.RawConfig, error) {
+	var cfg gelf.RawConfig
+	u, err := url.Parse(s)
+	if err != nil {
+		return cfg, errors.Trace(err)
+	}
+	switch u.Scheme {
+	case "tcp":
+	case "tls":
+		cfg.TLS = true
+	default:
+		return cfg, errors.Errorf("expected tcp:// or tls:// URL, got %q", s)
+	}
+	cfg.Address = u.Host
+	return cfg, nil
+}
+
+func (c *Config) validateLogForwarding() error {
+	lfCfg, ok, err := c.logForwardConfig()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if !ok {
+		return nil
+	}
+	return errors.Trace(lfCfg.Validate())
+}
+
 // FirewallMode returns whether the firewall should
 // manage ports per machine, globally, or not at all.
 // (FwInstance, FwGlobal, or FwNone).
@@ -801,6 +991,50 @@ func (c *Config) TransmitVendorMetrics() bool {
 	}
 }
 
+// LogsMaxAge returns the maximum age of the model's log records, and
+// whether it has been set.
+func (c *Config) LogsMaxAge() (time.Duration, bool) {
+	// Validate has already checked the value.
+	maxAge, err := time.ParseDuration(c.asString(LogsMaxAgeKey))
+	if err != nil || maxAge == 0 {
+		return 0, false
+	}
+	return maxAge, true
+}
+
+// LogsMaxSizeMB returns the maximum space, in megabytes, that the
+// model's log records may use, and whether it has been set.
+func (c *Config) LogsMaxSizeMB() (int, bool) {
+	// Validate has already checked the value.
+	maxSize, err := utils.ParseSize(c.asString(LogsMaxSizeKey))
+	if err != nil || maxSize == 0 {
+		return 0, false
+	}
+	return int(maxSize), true
+}
+
+// ActionsMaxAge returns the maximum age of the model's finished
+// actions, and whether it has been set.
+func (c *Config) ActionsMaxAge() (time.Duration, bool) {
+	// Validate has already checked the value.
+	maxAge, err := time.ParseDuration(c.asString(ActionsMaxAgeKey))
+	if err != nil || maxAge == 0 {
+		return 0, false
+	}
+	return maxAge, true
+}
+
+// ActionsMaxSizeMB returns the maximum space, in megabytes, that the
+// model's finished actions may use, and whether it has been set.
+func (c *Config) ActionsMaxSizeMB() (int, bool) {
+	// Validate has already checked the value.
+	maxSize, err := utils.ParseSize(c.asString(ActionsMaxSizeKey))
+	if err != nil || maxSize == 0 {
+		return 0, false
+	}
+	return int(maxSize), true
+}
+
 // ProvisionerHarvestMode reports the harvesting methodology the
 // provisioner should take.
 func (c *Config) ProvisionerHarvestMode() HarvestMode {
@@ -963,6 +1197,15 @@ var alwaysOptional = schema.Defaults{
 	LogFwdSyslogCACert:     schema.Omit,
 	LogFwdSyslogClientCert: schema.Omit,
 	LogFwdSyslogClientKey:  schema.Omit,
+	LogFwdType:             schema.Omit,
+	LogFwdURL:              schema.Omit,
+	LogFwdHTTPFormat:       schema.Omit,
+	LogFwdCACert:           schema.Omit,
+	LogFwdClientCert:       schema.Omit,
+	LogFwdClientKey:        schema.Omit,
+	LogFwdBatchSize:        schema.Omit,
+	LogFwdRetryAttempts:    schema.Omit,
+	LogFwdRetryDelay:       schema.Omit,
 
 	// Storage related config.
 	// Environ providers will specify their own defaults.
@@ -997,6 +1240,10 @@ var alwaysOptional = schema.Defaults{
 	"test-mode":                  schema.Omit,
 	TransmitVendorMetricsKey:     schema.Omit,
 	NetBondReconfigureDelayKey:   schema.Omit,
+	LogsMaxAgeKey:                schema.Omit,
+	LogsMaxSizeKey:               schema.Omit,
+	ActionsMaxAgeKey:             schema.Omit,
+	ActionsMaxSizeKey:            schema.Omit,
 }
 
 func allowEmpty(attr string) bool {
@@ -1317,6 +1564,51 @@ global or per instance security groups.`,
 		Type:        environschema.Tstring,
 		Group:       environschema.EnvironGroup,
 	},
+	LogFwdType: {
+		Description: `The kind of log forwarding target: syslog, http or gelf (default syslog).`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdURL: {
+		Description: `The http(s) URL of an http log forwarding target, or the tcp:// or tls:// URL of a gelf target.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdHTTPFormat: {
+		Description: `How records are encoded for an http log forwarding target: json or elasticsearch (default json).`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdCACert: {
+		Description: `The certificate of the CA that signed the http or gelf server certificate, in PEM format.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdClientCert: {
+		Description: `The http or gelf log forwarding client certificate in PEM format.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdClientKey: {
+		Description: `The http or gelf log forwarding client key in PEM format.`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdBatchSize: {
+		Description: `The maximum number of records forwarded at a time (default 1).`,
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdRetryAttempts: {
+		Description: `The number of times a failed batch of forwarded records is resent (default 0).`,
+		Type:        environschema.Tint,
+		Group:       environschema.EnvironGroup,
+	},
+	LogFwdRetryDelay: {
+		Description: `How long to wait before resending a failed batch of forwarded records (default 5s).`,
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 	"ssl-hostname-verification": {
 		Description: "Whether SSL hostname verification is enabled (default true)",
 		Type:        environschema.Tbool,
@@ -1362,4 +1654,24 @@ data of the store. (default false)`,
 		Type:        environschema.Tint,
 		Group:       environschema.EnvironGroup,
 	},
+	LogsMaxAgeKey: {
+		Description: "The maximum age of the model's log records kept by the controller, e.g. 72h; it can only shorten the controller-wide limit (default: the controller-wide limit)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	LogsMaxSizeKey: {
+		Description: "The maximum space the model's log records may use in the controller's database, e.g. 500M (default: no per-model limit)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	ActionsMaxAgeKey: {
+		Description: "The maximum age of the model's finished actions kept by the controller, e.g. 336h (default: the controller-wide limit)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
+	ActionsMaxSizeKey: {
+		Description: "The maximum space the model's finished actions may use in the controller's database, e.g. 5G (default: the controller-wide limit)",
+		Type:        environschema.Tstring,
+		Group:       environschema.EnvironGroup,
+	},
 }
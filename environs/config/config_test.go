@@ -23,6 +23,11 @@ import (
 	"github.com/juju/juju/cert"
 	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/juju/osenv"
+	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/gelf"
+	"github.com/juju/juju/logfwd/jsonhttp"
+	"github.com/juju/juju/logfwd/sinkconfig"
+	"github.com/juju/juju/logfwd/syslog"
 	"github.com/juju/juju/testing"
 )
 
@@ -520,6 +525,116 @@ var configTests = []configTest{
 			"syslog-client-key":  serverKey2,
 		}),
 		err: `invalid syslog forwarding config: validating TLS config: parsing client key pair: (crypto/)?tls: private key does not match public key`,
+	}, {
+		about:       "http log forwarding",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-enabled":        true,
+			"logforward-type":           "http",
+			"logforward-url":            "https://es.example.com:9200/juju/_bulk",
+			"logforward-http-format":    "elasticsearch",
+			"logforward-ca-cert":        testing.CACert,
+			"logforward-batch-size":     100,
+			"logforward-retry-attempts": 3,
+			"logforward-retry-delay":    "10s",
+		}),
+	}, {
+		about:       "http log forwarding without URL",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-enabled": true,
+			"logforward-type":    "http",
+		}),
+		err: `invalid HTTP forwarding config: URL "" \(expected http or https\) not valid`,
+	}, {
+		about:       "Invalid http log forwarding format",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-type":        "http",
+			"logforward-url":         "https://example.com/logs",
+			"logforward-http-format": "xml",
+		}),
+		err: `invalid HTTP forwarding config: Format "xml" not valid`,
+	}, {
+		about:       "gelf log forwarding",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-enabled": true,
+			"logforward-type":    "gelf",
+			"logforward-url":     "tls://graylog.example.com:12201",
+		}),
+	}, {
+		about:       "Invalid gelf log forwarding URL",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-enabled": true,
+			"logforward-type":    "gelf",
+			"logforward-url":     "udp://graylog.example.com:12201",
+		}),
+		err: `invalid logforward-url: expected tcp:// or tls:// URL, got "udp://graylog.example.com:12201"`,
+	}, {
+		about:       "Unknown log forwarding type",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-type": "carrier-pigeon",
+		}),
+		err: `log forwarding sink type "carrier-pigeon" not valid`,
+	}, {
+		about:       "Invalid log forwarding retry delay",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logforward-retry-delay": "soon",
+		}),
+		err: `invalid logforward-retry-delay: time: invalid duration "?soon"?`,
+	}, {
+		about:       "Per-model log retention",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logs-max-age":  "168h",
+			"logs-max-size": "500M",
+		}),
+	}, {
+		about:       "Invalid logs-max-age",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logs-max-age": "a week",
+		}),
+		err: `invalid logs-max-age in model configuration: time: invalid duration "?a week"?`,
+	}, {
+		about:       "Negative logs-max-age",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logs-max-age": "-1h",
+		}),
+		err: `invalid logs-max-age in model configuration: negative duration "-1h"`,
+	}, {
+		about:       "Invalid logs-max-size",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"logs-max-size": "lots",
+		}),
+		err: `invalid logs-max-size in model configuration: .*`,
+	}, {
+		about:       "Per-model action retention",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"actions-max-age":  "336h",
+			"actions-max-size": "5G",
+		}),
+	}, {
+		about:       "Negative actions-max-age",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"actions-max-age": "-1h",
+		}),
+		err: `invalid actions-max-age in model configuration: negative duration "-1h"`,
+	}, {
+		about:       "Invalid actions-max-size",
+		useDefaults: config.UseDefaults,
+		attrs: minimalConfigAttrs.Merge(testing.Attrs{
+			"actions-max-size": "lots",
+		}),
+		err: `invalid actions-max-size in model configuration: .*`,
 	}, {
 		about:       "net-bond-reconfigure-delay value",
 		useDefaults: config.UseDefaults,
@@ -830,6 +945,123 @@ var validationTests = []validationTest{{
 	err:   "cannot change uuid from \"90168e4c-2f10-4e9c-83c2-1fb55a58e5a9\" to \"dcfbdb4a-bca2-49ad-aa7c-f011424e0fe4\"",
 }}
 
+func (s *ConfigSuite) TestLogForwardConfigNotSet(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+
+	_, ok := cfg.LogForwardConfig()
+	c.Check(ok, jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestLogForwardConfigSyslog(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"logforward-enabled": true,
+		"syslog-host":        "10.0.0.1:12345",
+		"syslog-ca-cert":     testing.CACert,
+		"syslog-client-cert": testing.ServerCert,
+		"syslog-client-key":  testing.ServerKey,
+	})
+
+	lfCfg, ok := cfg.LogForwardConfig()
+	c.Assert(ok, jc.IsTrue)
+	c.Check(lfCfg.Enabled, jc.IsTrue)
+	c.Check(lfCfg.SinkType(), gc.Equals, sinkconfig.TypeSyslog)
+	c.Check(lfCfg.Syslog, jc.DeepEquals, syslog.RawConfig{
+		Enabled:    true,
+		Host:       "10.0.0.1:12345",
+		CACert:     testing.CACert,
+		ClientCert: testing.ServerCert,
+		ClientKey:  testing.ServerKey,
+	})
+}
+
+func (s *ConfigSuite) TestLogForwardConfigHTTP(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"logforward-enabled":        true,
+		"logforward-type":           "http",
+		"logforward-url":            "https://es.example.com:9200/juju/_bulk",
+		"logforward-http-format":    "elasticsearch",
+		"logforward-ca-cert":        testing.CACert,
+		"logforward-batch-size":     100,
+		"logforward-retry-attempts": 3,
+		"logforward-retry-delay":    "10s",
+	})
+
+	lfCfg, ok := cfg.LogForwardConfig()
+	c.Assert(ok, jc.IsTrue)
+	c.Check(lfCfg.Enabled, jc.IsTrue)
+	c.Check(lfCfg.Type, gc.Equals, sinkconfig.TypeHTTP)
+	c.Check(lfCfg.HTTP, jc.DeepEquals, jsonhttp.RawConfig{
+		URL:    "https://es.example.com:9200/juju/_bulk",
+		Format: jsonhttp.FormatElasticsearch,
+	})
+	c.Check(lfCfg.TLS, jc.DeepEquals, logfwd.TLSConfig{CACert: testing.CACert})
+	c.Check(lfCfg.BatchSize, gc.Equals, 100)
+	c.Check(lfCfg.RetryAttempts, gc.Equals, 3)
+	c.Check(lfCfg.RetryDelay, gc.Equals, 10*time.Second)
+}
+
+func (s *ConfigSuite) TestLogForwardConfigGELF(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"logforward-enabled": true,
+		"logforward-type":    "gelf",
+		"logforward-url":     "tls://graylog.example.com:12201",
+	})
+
+	lfCfg, ok := cfg.LogForwardConfig()
+	c.Assert(ok, jc.IsTrue)
+	c.Check(lfCfg.Type, gc.Equals, sinkconfig.TypeGELF)
+	c.Check(lfCfg.GELF, jc.DeepEquals, gelf.RawConfig{
+		Address: "graylog.example.com:12201",
+		TLS:     true,
+	})
+}
+
+func (s *ConfigSuite) TestLogsRetentionNotSet(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+
+	_, ok := cfg.LogsMaxAge()
+	c.Check(ok, jc.IsFalse)
+	_, ok = cfg.LogsMaxSizeMB()
+	c.Check(ok, jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestLogsRetention(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"logs-max-age":  "168h",
+		"logs-max-size": "2G",
+	})
+
+	maxAge, ok := cfg.LogsMaxAge()
+	c.Check(ok, jc.IsTrue)
+	c.Check(maxAge, gc.Equals, 168*time.Hour)
+	maxSize, ok := cfg.LogsMaxSizeMB()
+	c.Check(ok, jc.IsTrue)
+	c.Check(maxSize, gc.Equals, 2048)
+}
+
+func (s *ConfigSuite) TestActionsRetentionNotSet(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{})
+
+	_, ok := cfg.ActionsMaxAge()
+	c.Check(ok, jc.IsFalse)
+	_, ok = cfg.ActionsMaxSizeMB()
+	c.Check(ok, jc.IsFalse)
+}
+
+func (s *ConfigSuite) TestActionsRetention(c *gc.C) {
+	cfg := newTestConfig(c, testing.Attrs{
+		"actions-max-age":  "336h",
+		"actions-max-size": "5G",
+	})
+
+	maxAge, ok := cfg.ActionsMaxAge()
+	c.Check(ok, jc.IsTrue)
+	c.Check(maxAge, gc.Equals, 336*time.Hour)
+	maxSize, ok := cfg.ActionsMaxSizeMB()
+	c.Check(ok, jc.IsTrue)
+	c.Check(maxSize, gc.Equals, 5120)
+}
+
 func (s *ConfigSuite) TestValidateChange(c *gc.C) {
 	files := []gitjujutesting.TestFile{
 		{".ssh/identity.pub", "identity"},
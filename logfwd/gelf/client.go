@@ -0,0 +1,209 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gelf
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"net"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/logfwd"
+)
+
+// gelfVersion is the version of the GELF payload specification that
+// messages conform to.
+const gelfVersion = "1.1"
+
+// Conn is a connection to a GELF input.
+type Conn interface {
+	io.WriteCloser
+
+	// SetWriteDeadline sets the deadline for future writes.
+	SetWriteDeadline(t time.Time) error
+}
+
+// Dialer supports connecting to a GELF input.
+type Dialer interface {
+	// Dial connects to the address, using TLS if tlsCfg is not nil.
+	Dial(address string, tlsCfg *tls.Config, timeout time.Duration) (Conn, error)
+}
+
+type dialer struct{}
+
+func (dialer) Dial(address string, tlsCfg *tls.Config, timeout time.Duration) (Conn, error) {
+	netDialer := &net.Dialer{Timeout: timeout}
+	if tlsCfg == nil {
+		conn, err := netDialer.Dial("tcp", address)
+		return conn, errors.Trace(err)
+	}
+	conn, err := tls.DialWithDialer(netDialer, "tcp", address, tlsCfg)
+	return conn, errors.Trace(err)
+}
+
+// Client sends log records to a GELF TCP input, as null-terminated
+// JSON messages.
+type Client struct {
+	address string
+	tlsCfg  *tls.Config
+	timeout time.Duration
+	dialer  Dialer
+	conn    Conn
+}
+
+// Open connects to a GELF input and wraps that connection in a new
+// client. The given TLS config is used if the config asks for TLS.
+func Open(cfg RawConfig, tlsCfg *tls.Config) (*Client, error) {
+	client, err := OpenForDialer(cfg, tlsCfg, dialer{})
+	return client, errors.Trace(err)
+}
+
+// OpenForDialer connects to a GELF input using the given dialer and
+// wraps that connection in a new client.
+func OpenForDialer(cfg RawConfig, tlsCfg *tls.Config, dialer Dialer) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	client := &Client{
+		address: cfg.hostPort(),
+		timeout: cfg.Timeout,
+		dialer:  dialer,
+	}
+	if client.timeout == 0 {
+		client.timeout = DefaultTimeout
+	}
+	if cfg.TLS {
+		if tlsCfg == nil {
+			tlsCfg = &tls.Config{}
+		}
+		client.tlsCfg = tlsCfg
+	}
+	if err := client.connect(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return client, nil
+}
+
+func (client *Client) connect() error {
+	conn, err := client.dialer.Dial(client.address, client.tlsCfg, client.timeout)
+	if err != nil {
+		return errors.Annotate(err, "opening client connection")
+	}
+	client.conn = conn
+	return nil
+}
+
+// Close closes the client's connection.
+func (client *Client) Close() error {
+	if client.conn == nil {
+		return nil
+	}
+	err := client.conn.Close()
+	client.conn = nil
+	return errors.Trace(err)
+}
+
+// Send sends the records to the GELF input. If the connection fails
+// it is closed, and the next call to Send will reconnect. Records are
+// sent one at a time, so if some but not all of them are sent, the
+// error is a *logfwd.PartialSendError.
+func (client *Client) Send(records []logfwd.Record) error {
+	if client.conn == nil {
+		if err := client.connect(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	for i, rec := range records {
+		data, err := messageFromRecord(rec)
+		if err != nil {
+			return sendError(i, errors.Trace(err))
+		}
+		if err := client.write(append(data, 0)); err != nil {
+			client.Close()
+			return sendError(i, errors.Annotate(err, "sending log record"))
+		}
+	}
+	return nil
+}
+
+// sendError returns err, noting how many records were sent before it
+// if there were any.
+func sendError(sent int, err error) error {
+	if sent == 0 {
+		return err
+	}
+	return &logfwd.PartialSendError{Sent: sent, Err: err}
+}
+
+func (client *Client) write(data []byte) error {
+	if err := client.conn.SetWriteDeadline(time.Now().Add(client.timeout)); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := client.conn.Write(data)
+	return errors.Trace(err)
+}
+
+func messageFromRecord(rec logfwd.Record) ([]byte, error) {
+	level, err := syslogLevel(rec.Level)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	host := rec.Origin.Hostname
+	if host == "" {
+		host = rec.Origin.Name
+	}
+	msg := map[string]interface{}{
+		"version":           gelfVersion,
+		"host":              host,
+		"short_message":     rec.Message,
+		"timestamp":         float64(rec.Timestamp.Unix()) + float64(rec.Timestamp.Nanosecond())/float64(time.Second),
+		"level":             level,
+		"_record_id":        rec.ID,
+		"_controller_uuid":  rec.Origin.ControllerUUID,
+		"_model_uuid":       rec.Origin.ModelUUID,
+		"_origin_type":      rec.Origin.Type.String(),
+		"_origin_name":      rec.Origin.Name,
+		"_software":         rec.Origin.Software.Name,
+		"_software_version": rec.Origin.Software.Version.String(),
+		"_module":           rec.Location.Module,
+		"_location":         rec.Location.String(),
+	}
+	if rec.Audit != nil {
+		msg["_audit_id"] = rec.Audit.ID
+		msg["_audit_origin_type"] = rec.Audit.OriginType
+		msg["_audit_operation"] = rec.Audit.Operation
+		msg["_audit_remote_address"] = rec.Audit.RemoteAddress
+		if len(rec.Audit.Data) > 0 {
+			// GELF additional fields must be strings or numbers.
+			data, err := json.Marshal(rec.Audit.Data)
+			if err != nil {
+				return nil, errors.Annotate(err, "marshalling audit data")
+			}
+			msg["_audit_data"] = string(data)
+		}
+	}
+	data, err := json.Marshal(msg)
+	return data, errors.Trace(err)
+}
+
+// syslogLevel returns the syslog severity GELF uses for the level.
+func syslogLevel(level loggo.Level) (int, error) {
+	switch level {
+	case loggo.CRITICAL:
+		return 2, nil
+	case loggo.ERROR:
+		return 3, nil
+	case loggo.WARNING:
+		return 4, nil
+	case loggo.INFO:
+		return 6, nil
+	case loggo.DEBUG, loggo.TRACE:
+		return 7, nil
+	}
+	return 0, errors.Errorf("unsupported log level %q", level)
+}
@@ -0,0 +1,247 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gelf_test
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/gelf"
+)
+
+type ClientSuite struct {
+	testing.IsolationSuite
+
+	stub   *testing.Stub
+	conn   *stubConn
+	dialer *stubDialer
+}
+
+var _ = gc.Suite(&ClientSuite{})
+
+func (s *ClientSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.stub = &testing.Stub{}
+	s.conn = &stubConn{stub: s.stub}
+	s.dialer = &stubDialer{stub: s.stub, conn: s.conn}
+}
+
+func (s *ClientSuite) TestOpen(c *gc.C) {
+	_, err := gelf.OpenForDialer(gelf.RawConfig{
+		Address: "graylog.example.com",
+	}, nil, s.dialer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCalls(c, []testing.StubCall{{
+		"Dial", []interface{}{"graylog.example.com:12201", (*tls.Config)(nil), gelf.DefaultTimeout},
+	}})
+}
+
+func (s *ClientSuite) TestOpenTLS(c *gc.C) {
+	tlsCfg := &tls.Config{}
+	_, err := gelf.OpenForDialer(gelf.RawConfig{
+		Address: "graylog.example.com:12202",
+		TLS:     true,
+		Timeout: time.Minute,
+	}, tlsCfg, s.dialer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCalls(c, []testing.StubCall{{
+		"Dial", []interface{}{"graylog.example.com:12202", tlsCfg, time.Minute},
+	}})
+}
+
+func (s *ClientSuite) TestOpenTLSIgnoredWhenDisabled(c *gc.C) {
+	_, err := gelf.OpenForDialer(gelf.RawConfig{
+		Address: "graylog.example.com",
+	}, &tls.Config{}, s.dialer)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCalls(c, []testing.StubCall{{
+		"Dial", []interface{}{"graylog.example.com:12201", (*tls.Config)(nil), gelf.DefaultTimeout},
+	}})
+}
+
+func (s *ClientSuite) TestOpenDialError(c *gc.C) {
+	s.stub.SetErrors(errors.New("boom"))
+
+	_, err := gelf.OpenForDialer(gelf.RawConfig{
+		Address: "graylog.example.com",
+	}, nil, s.dialer)
+
+	c.Check(err, gc.ErrorMatches, `opening client connection: boom`)
+}
+
+func (s *ClientSuite) TestSend(c *gc.C) {
+	client := s.open(c)
+	rec := newRecord(10, "one")
+
+	err := client.Send([]logfwd.Record{rec, newRecord(11, "two")})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "SetWriteDeadline", "Write", "SetWriteDeadline", "Write")
+	frames := bytes.Split(s.conn.written.Bytes(), []byte{0})
+	c.Assert(frames, gc.HasLen, 3)
+	c.Check(frames[2], gc.HasLen, 0)
+	var msg map[string]interface{}
+	err = json.Unmarshal(frames[0], &msg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(msg, jc.DeepEquals, map[string]interface{}{
+		"version":           "1.1",
+		"host":              "machine-99.deadbeef-2f18-4fd2-967d-db9663db7bea",
+		"short_message":     "one",
+		"timestamp":         1488362400.5,
+		"level":             float64(3),
+		"_record_id":        float64(10),
+		"_controller_uuid":  "9f484882-2f18-4fd2-967d-db9663db7bea",
+		"_model_uuid":       "deadbeef-2f18-4fd2-967d-db9663db7bea",
+		"_origin_type":      "machine",
+		"_origin_name":      "99",
+		"_software":         "jujud-machine-agent",
+		"_software_version": "2.0.1",
+		"_module":           "juju.x.y",
+		"_location":         "x/y/spam.go:42",
+	})
+}
+
+func (s *ClientSuite) TestSendAudit(c *gc.C) {
+	client := s.open(c)
+	rec := newRecord(10, "audit")
+	rec.Audit = &logfwd.Audit{
+		ID:            "58be8510a8f5ba0b9e3c5a4e",
+		OriginType:    "API",
+		Operation:     "deploy",
+		RemoteAddress: "10.0.0.1",
+		Data:          map[string]interface{}{"application": "mysql"},
+	}
+
+	err := client.Send([]logfwd.Record{rec})
+	c.Assert(err, jc.ErrorIsNil)
+
+	var msg map[string]interface{}
+	err = json.Unmarshal(bytes.TrimSuffix(s.conn.written.Bytes(), []byte{0}), &msg)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(msg["_audit_id"], gc.Equals, "58be8510a8f5ba0b9e3c5a4e")
+	c.Check(msg["_audit_origin_type"], gc.Equals, "API")
+	c.Check(msg["_audit_operation"], gc.Equals, "deploy")
+	c.Check(msg["_audit_remote_address"], gc.Equals, "10.0.0.1")
+	c.Check(msg["_audit_data"], gc.Equals, `{"application":"mysql"}`)
+}
+
+func (s *ClientSuite) TestSendReconnectsAfterError(c *gc.C) {
+	client := s.open(c)
+	s.stub.SetErrors(nil, errors.New("broken pipe"))
+
+	err := client.Send([]logfwd.Record{newRecord(10, "one")})
+	c.Check(err, gc.ErrorMatches, `sending log record: broken pipe`)
+	s.stub.CheckCallNames(c, "SetWriteDeadline", "Write", "Close")
+
+	s.stub.ResetCalls()
+	err = client.Send([]logfwd.Record{newRecord(10, "one")})
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.CheckCallNames(c, "Dial", "SetWriteDeadline", "Write")
+}
+
+func (s *ClientSuite) TestSendPartialFailure(c *gc.C) {
+	client := s.open(c)
+	s.stub.SetErrors(nil, nil, nil, errors.New("broken pipe"))
+
+	err := client.Send([]logfwd.Record{newRecord(10, "one"), newRecord(11, "two")})
+	c.Check(err, gc.ErrorMatches, `sending log record: broken pipe`)
+	partial, ok := err.(*logfwd.PartialSendError)
+	c.Assert(ok, jc.IsTrue)
+	c.Check(partial.Sent, gc.Equals, 1)
+	s.stub.CheckCallNames(c, "SetWriteDeadline", "Write", "SetWriteDeadline", "Write", "Close")
+}
+
+func (s *ClientSuite) TestClose(c *gc.C) {
+	client := s.open(c)
+
+	err := client.Close()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "Close")
+}
+
+func (s *ClientSuite) open(c *gc.C) *gelf.Client {
+	client, err := gelf.OpenForDialer(gelf.RawConfig{
+		Address: "graylog.example.com",
+	}, nil, s.dialer)
+	c.Assert(err, jc.ErrorIsNil)
+	s.stub.ResetCalls()
+	return client
+}
+
+func newRecord(id int64, msg string) logfwd.Record {
+	return logfwd.Record{
+		ID: id,
+		Origin: logfwd.Origin{
+			ControllerUUID: "9f484882-2f18-4fd2-967d-db9663db7bea",
+			ModelUUID:      "deadbeef-2f18-4fd2-967d-db9663db7bea",
+			Hostname:       "machine-99.deadbeef-2f18-4fd2-967d-db9663db7bea",
+			Type:           logfwd.OriginTypeMachine,
+			Name:           "99",
+			Software: logfwd.Software{
+				PrivateEnterpriseNumber: 28978,
+				Name:                    "jujud-machine-agent",
+				Version:                 version.MustParse("2.0.1"),
+			},
+		},
+		Timestamp: time.Date(2017, time.March, 1, 10, 0, 0, 500000000, time.UTC),
+		Level:     loggo.ERROR,
+		Location: logfwd.SourceLocation{
+			Module:   "juju.x.y",
+			Filename: "x/y/spam.go",
+			Line:     42,
+		},
+		Message: msg,
+	}
+}
+
+type stubDialer struct {
+	stub *testing.Stub
+	conn *stubConn
+}
+
+func (s *stubDialer) Dial(address string, tlsCfg *tls.Config, timeout time.Duration) (gelf.Conn, error) {
+	s.stub.AddCall("Dial", address, tlsCfg, timeout)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	return s.conn, nil
+}
+
+type stubConn struct {
+	stub    *testing.Stub
+	written bytes.Buffer
+}
+
+func (s *stubConn) SetWriteDeadline(t time.Time) error {
+	s.stub.AddCall("SetWriteDeadline")
+	return s.stub.NextErr()
+}
+
+func (s *stubConn) Write(data []byte) (int, error) {
+	s.stub.AddCall("Write")
+	if err := s.stub.NextErr(); err != nil {
+		return 0, err
+	}
+	return s.written.Write(data)
+}
+
+func (s *stubConn) Close() error {
+	s.stub.AddCall("Close")
+	return s.stub.NextErr()
+}
@@ -0,0 +1,58 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gelf
+
+import (
+	"net"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// DefaultPort is the port used when the config's address doesn't
+// specify one. It is the standard port for Graylog GELF inputs.
+const DefaultPort = "12201"
+
+// DefaultTimeout is the time allowed for connecting and for each
+// write when the config doesn't specify one.
+const DefaultTimeout = 30 * time.Second
+
+// RawConfig holds the raw configuration data for a connection to a
+// GELF forwarding target.
+type RawConfig struct {
+	// Address is the host-port of the GELF TCP input. If the port
+	// is not set then DefaultPort is used.
+	Address string
+
+	// TLS is true if the connection is made over TLS.
+	TLS bool
+
+	// Timeout is the time allowed for connecting and for each
+	// write. If it is zero, DefaultTimeout is used.
+	Timeout time.Duration
+}
+
+// Validate ensures that the config is currently valid.
+func (cfg RawConfig) Validate() error {
+	host, _, err := net.SplitHostPort(cfg.Address)
+	if err != nil {
+		host = cfg.Address
+	}
+	if host == "" {
+		return errors.NotValidf("Address %q", cfg.Address)
+	}
+	if cfg.Timeout < 0 {
+		return errors.NotValidf("negative Timeout")
+	}
+	return nil
+}
+
+// hostPort returns the configured address, with the default port
+// added if necessary.
+func (cfg RawConfig) hostPort() string {
+	if _, _, err := net.SplitHostPort(cfg.Address); err == nil {
+		return cfg.Address
+	}
+	return net.JoinHostPort(cfg.Address, DefaultPort)
+}
@@ -0,0 +1,63 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package gelf_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd/gelf"
+)
+
+type ConfigSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ConfigSuite{})
+
+func (s *ConfigSuite) TestRawValidateFull(c *gc.C) {
+	cfg := gelf.RawConfig{
+		Address: "graylog.example.com:12202",
+		TLS:     true,
+		Timeout: time.Minute,
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestRawValidateWithoutPort(c *gc.C) {
+	cfg := gelf.RawConfig{
+		Address: "graylog.example.com",
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestRawValidateMissingAddress(c *gc.C) {
+	cfg := gelf.RawConfig{
+		Address: ":12201",
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, gc.ErrorMatches, `Address ":12201" not valid`)
+}
+
+func (s *ConfigSuite) TestRawValidateNegativeTimeout(c *gc.C) {
+	cfg := gelf.RawConfig{
+		Address: "graylog.example.com",
+		Timeout: -time.Second,
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, gc.ErrorMatches, `negative Timeout not valid`)
+}
@@ -0,0 +1,242 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jsonhttp
+
+import (
+	"bytes"
+	"crypto/tls"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/logfwd"
+)
+
+// maxErrorBody is the most of a response body that is included in an
+// error, or read when checking the response.
+const maxErrorBody = 64 * 1024
+
+// Doer sends HTTP requests.
+type Doer interface {
+	// Do sends the request and returns the response.
+	Do(*http.Request) (*http.Response, error)
+}
+
+// Client sends batches of log records to an HTTP(S) endpoint.
+type Client struct {
+	// URL is the URL to which batches are POSTed.
+	URL string
+
+	// Format is the encoding used for each batch.
+	Format Format
+
+	// Doer sends the requests.
+	Doer Doer
+}
+
+// Open returns a new client for the configured endpoint, which uses
+// the given TLS config, if any, for https URLs.
+func Open(cfg RawConfig, tlsCfg *tls.Config) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	timeout := cfg.Timeout
+	if timeout == 0 {
+		timeout = DefaultTimeout
+	}
+	doer := &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: tlsCfg,
+		},
+		Timeout: timeout,
+	}
+	return OpenForDoer(cfg, doer)
+}
+
+// OpenForDoer returns a new client for the configured endpoint, which
+// uses the given Doer to send requests.
+func OpenForDoer(cfg RawConfig, doer Doer) (*Client, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	format := cfg.Format
+	if format == "" {
+		format = FormatJSON
+	}
+	return &Client{
+		URL:    cfg.URL,
+		Format: format,
+		Doer:   doer,
+	}, nil
+}
+
+// Close releases the client's resources.
+func (client *Client) Close() error {
+	if httpClient, ok := client.Doer.(*http.Client); ok {
+		if transport, ok := httpClient.Transport.(*http.Transport); ok {
+			transport.CloseIdleConnections()
+		}
+	}
+	return nil
+}
+
+// Send sends the records to the endpoint in a single request.
+func (client *Client) Send(records []logfwd.Record) error {
+	if len(records) == 0 {
+		return nil
+	}
+	body, contentType, err := client.encode(records)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req, err := http.NewRequest("POST", client.URL, bytes.NewReader(body))
+	if err != nil {
+		return errors.Trace(err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	resp, err := client.Doer.Do(req)
+	if err != nil {
+		return errors.Annotate(err, "sending log records")
+	}
+	defer resp.Body.Close()
+
+	respBody, err := ioutil.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	if err != nil {
+		return errors.Annotate(err, "reading response")
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return errors.Errorf("sending log records: %s: %s", resp.Status, bytes.TrimSpace(respBody))
+	}
+	if client.Format == FormatElasticsearch {
+		return errors.Trace(checkBulkResponse(respBody))
+	}
+	return nil
+}
+
+func (client *Client) encode(records []logfwd.Record) ([]byte, string, error) {
+	docs := make([]document, len(records))
+	for i, rec := range records {
+		docs[i] = documentFromRecord(rec)
+	}
+	switch client.Format {
+	case FormatElasticsearch:
+		var buf bytes.Buffer
+		encoder := json.NewEncoder(&buf)
+		for _, doc := range docs {
+			// The index is taken from the URL.
+			if err := encoder.Encode(bulkAction{Index: struct{}{}}); err != nil {
+				return nil, "", errors.Trace(err)
+			}
+			if err := encoder.Encode(doc); err != nil {
+				return nil, "", errors.Trace(err)
+			}
+		}
+		return buf.Bytes(), "application/x-ndjson", nil
+	default:
+		body, err := json.Marshal(docs)
+		return body, "application/json", errors.Trace(err)
+	}
+}
+
+type bulkAction struct {
+	Index struct{} `json:"index"`
+}
+
+type bulkResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Status int `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// checkBulkResponse returns an error if any of the documents in a
+// bulk request were rejected.
+func checkBulkResponse(body []byte) error {
+	var resp bulkResponse
+	if err := json.Unmarshal(body, &resp); err != nil {
+		// A truncated response can't be checked, but the request
+		// itself succeeded.
+		return nil
+	}
+	if !resp.Errors {
+		return nil
+	}
+	failed := 0
+	var reason string
+	for _, item := range resp.Items {
+		for _, result := range item {
+			if result.Error == nil {
+				continue
+			}
+			failed++
+			if reason == "" {
+				reason = result.Error.Type + ": " + result.Error.Reason
+			}
+		}
+	}
+	return errors.Errorf("%d of %d log records rejected (%s)", failed, len(resp.Items), reason)
+}
+
+// document is the JSON representation of a log record.
+type document struct {
+	ID             int64          `json:"id"`
+	Timestamp      time.Time      `json:"timestamp"`
+	ControllerUUID string         `json:"controller-uuid"`
+	ModelUUID      string         `json:"model-uuid"`
+	Hostname       string         `json:"hostname,omitempty"`
+	OriginType     string         `json:"origin-type"`
+	OriginName     string         `json:"origin-name"`
+	Software       string         `json:"software"`
+	Version        string         `json:"version"`
+	Level          string         `json:"level"`
+	Module         string         `json:"module"`
+	Location       string         `json:"location,omitempty"`
+	Message        string         `json:"message"`
+	Audit          *auditDocument `json:"audit,omitempty"`
+}
+
+type auditDocument struct {
+	ID            string                 `json:"id,omitempty"`
+	OriginType    string                 `json:"origin-type"`
+	Operation     string                 `json:"operation"`
+	RemoteAddress string                 `json:"remote-address,omitempty"`
+	Data          map[string]interface{} `json:"data,omitempty"`
+}
+
+func documentFromRecord(rec logfwd.Record) document {
+	doc := document{
+		ID:             rec.ID,
+		Timestamp:      rec.Timestamp.UTC(),
+		ControllerUUID: rec.Origin.ControllerUUID,
+		ModelUUID:      rec.Origin.ModelUUID,
+		Hostname:       rec.Origin.Hostname,
+		OriginType:     rec.Origin.Type.String(),
+		OriginName:     rec.Origin.Name,
+		Software:       rec.Origin.Software.Name,
+		Version:        rec.Origin.Software.Version.String(),
+		Level:          rec.Level.String(),
+		Module:         rec.Location.Module,
+		Location:       rec.Location.String(),
+		Message:        rec.Message,
+	}
+	if rec.Audit != nil {
+		doc.Audit = &auditDocument{
+			ID:            rec.Audit.ID,
+			OriginType:    rec.Audit.OriginType,
+			Operation:     rec.Audit.Operation,
+			RemoteAddress: rec.Audit.RemoteAddress,
+			Data:          rec.Audit.Data,
+		}
+	}
+	return doc
+}
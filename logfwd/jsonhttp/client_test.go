@@ -0,0 +1,238 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jsonhttp_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/jsonhttp"
+)
+
+type ClientSuite struct {
+	testing.IsolationSuite
+
+	stub *testing.Stub
+	doer *stubDoer
+}
+
+var _ = gc.Suite(&ClientSuite{})
+
+func (s *ClientSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+
+	s.stub = &testing.Stub{}
+	s.doer = &stubDoer{stub: s.stub, status: http.StatusOK}
+}
+
+func (s *ClientSuite) open(c *gc.C, format jsonhttp.Format) *jsonhttp.Client {
+	client, err := jsonhttp.OpenForDoer(jsonhttp.RawConfig{
+		URL:    "https://logs.example.com/juju",
+		Format: format,
+	}, s.doer)
+	c.Assert(err, jc.ErrorIsNil)
+	return client
+}
+
+func (s *ClientSuite) TestOpenDefaultsFormat(c *gc.C) {
+	client := s.open(c, "")
+
+	c.Check(client.Format, gc.Equals, jsonhttp.FormatJSON)
+	c.Check(client.URL, gc.Equals, "https://logs.example.com/juju")
+}
+
+func (s *ClientSuite) TestOpenInvalidConfig(c *gc.C) {
+	_, err := jsonhttp.OpenForDoer(jsonhttp.RawConfig{URL: "spam"}, s.doer)
+
+	c.Check(err, gc.ErrorMatches, `URL "spam" \(expected http or https\) not valid`)
+}
+
+func (s *ClientSuite) TestSendJSON(c *gc.C) {
+	client := s.open(c, jsonhttp.FormatJSON)
+	records := []logfwd.Record{newRecord(10, "one"), newRecord(11, "two")}
+
+	err := client.Send(records)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "Do")
+	c.Check(s.doer.method, gc.Equals, "POST")
+	c.Check(s.doer.url, gc.Equals, "https://logs.example.com/juju")
+	c.Check(s.doer.contentType, gc.Equals, "application/json")
+	var docs []map[string]interface{}
+	err = json.Unmarshal(s.doer.body, &docs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(docs, gc.HasLen, 2)
+	c.Check(docs[0], jc.DeepEquals, map[string]interface{}{
+		"id":              float64(10),
+		"timestamp":       "2017-03-01T10:00:00Z",
+		"controller-uuid": "9f484882-2f18-4fd2-967d-db9663db7bea",
+		"model-uuid":      "deadbeef-2f18-4fd2-967d-db9663db7bea",
+		"hostname":        "machine-99.deadbeef-2f18-4fd2-967d-db9663db7bea",
+		"origin-type":     "machine",
+		"origin-name":     "99",
+		"software":        "jujud-machine-agent",
+		"version":         "2.0.1",
+		"level":           "ERROR",
+		"module":          "juju.x.y",
+		"location":        "x/y/spam.go:42",
+		"message":         "one",
+	})
+	c.Check(docs[1]["message"], gc.Equals, "two")
+}
+
+func (s *ClientSuite) TestSendAudit(c *gc.C) {
+	client := s.open(c, jsonhttp.FormatJSON)
+	rec := newRecord(10, "audit")
+	rec.Audit = &logfwd.Audit{
+		ID:            "58be8510a8f5ba0b9e3c5a4e",
+		OriginType:    "API",
+		Operation:     "deploy",
+		RemoteAddress: "10.0.0.1",
+		Data:          map[string]interface{}{"application": "mysql"},
+	}
+
+	err := client.Send([]logfwd.Record{rec})
+	c.Assert(err, jc.ErrorIsNil)
+
+	var docs []map[string]interface{}
+	err = json.Unmarshal(s.doer.body, &docs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(docs[0]["audit"], jc.DeepEquals, map[string]interface{}{
+		"id":             "58be8510a8f5ba0b9e3c5a4e",
+		"origin-type":    "API",
+		"operation":      "deploy",
+		"remote-address": "10.0.0.1",
+		"data":           map[string]interface{}{"application": "mysql"},
+	})
+}
+
+func (s *ClientSuite) TestSendElasticsearch(c *gc.C) {
+	client := s.open(c, jsonhttp.FormatElasticsearch)
+	s.doer.response = `{"took":3,"errors":false,"items":[]}`
+
+	err := client.Send([]logfwd.Record{newRecord(10, "one"), newRecord(11, "two")})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.doer.contentType, gc.Equals, "application/x-ndjson")
+	lines := strings.Split(string(s.doer.body), "\n")
+	c.Assert(lines, gc.HasLen, 5)
+	c.Check(lines[0], gc.Equals, `{"index":{}}`)
+	c.Check(lines[2], gc.Equals, `{"index":{}}`)
+	c.Check(lines[4], gc.Equals, "")
+	var doc map[string]interface{}
+	err = json.Unmarshal([]byte(lines[3]), &doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(doc["message"], gc.Equals, "two")
+}
+
+func (s *ClientSuite) TestSendElasticsearchRejected(c *gc.C) {
+	client := s.open(c, jsonhttp.FormatElasticsearch)
+	s.doer.response = `{"errors":true,"items":[
+		{"index":{"status":201}},
+		{"index":{"status":400,"error":{"type":"mapper_parsing_exception","reason":"bad field"}}}
+	]}`
+
+	err := client.Send([]logfwd.Record{newRecord(10, "one"), newRecord(11, "two")})
+
+	c.Check(err, gc.ErrorMatches, `1 of 2 log records rejected \(mapper_parsing_exception: bad field\)`)
+}
+
+func (s *ClientSuite) TestSendBadStatus(c *gc.C) {
+	client := s.open(c, jsonhttp.FormatJSON)
+	s.doer.status = http.StatusServiceUnavailable
+	s.doer.response = "try later\n"
+
+	err := client.Send([]logfwd.Record{newRecord(10, "one")})
+
+	c.Check(err, gc.ErrorMatches, `sending log records: 503 Service Unavailable: try later`)
+}
+
+func (s *ClientSuite) TestSendError(c *gc.C) {
+	client := s.open(c, jsonhttp.FormatJSON)
+	s.stub.SetErrors(errors.New("boom"))
+
+	err := client.Send([]logfwd.Record{newRecord(10, "one")})
+
+	c.Check(err, gc.ErrorMatches, `sending log records: boom`)
+}
+
+func (s *ClientSuite) TestSendNothing(c *gc.C) {
+	client := s.open(c, jsonhttp.FormatJSON)
+
+	err := client.Send(nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckNoCalls(c)
+}
+
+func newRecord(id int64, msg string) logfwd.Record {
+	return logfwd.Record{
+		ID: id,
+		Origin: logfwd.Origin{
+			ControllerUUID: "9f484882-2f18-4fd2-967d-db9663db7bea",
+			ModelUUID:      "deadbeef-2f18-4fd2-967d-db9663db7bea",
+			Hostname:       "machine-99.deadbeef-2f18-4fd2-967d-db9663db7bea",
+			Type:           logfwd.OriginTypeMachine,
+			Name:           "99",
+			Software: logfwd.Software{
+				PrivateEnterpriseNumber: 28978,
+				Name:                    "jujud-machine-agent",
+				Version:                 version.MustParse("2.0.1"),
+			},
+		},
+		Timestamp: time.Date(2017, time.March, 1, 10, 0, 0, 0, time.UTC),
+		Level:     loggo.ERROR,
+		Location: logfwd.SourceLocation{
+			Module:   "juju.x.y",
+			Filename: "x/y/spam.go",
+			Line:     42,
+		},
+		Message: msg,
+	}
+}
+
+type stubDoer struct {
+	stub *testing.Stub
+
+	status   int
+	response string
+
+	method      string
+	url         string
+	contentType string
+	body        []byte
+}
+
+func (s *stubDoer) Do(req *http.Request) (*http.Response, error) {
+	s.stub.AddCall("Do", req)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	if err != nil {
+		return nil, err
+	}
+	s.method = req.Method
+	s.url = req.URL.String()
+	s.contentType = req.Header.Get("Content-Type")
+	s.body = body
+	return &http.Response{
+		Status:     fmt.Sprintf("%d %s", s.status, http.StatusText(s.status)),
+		StatusCode: s.status,
+		Body:       ioutil.NopCloser(bytes.NewBufferString(s.response)),
+	}, nil
+}
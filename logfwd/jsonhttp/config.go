@@ -0,0 +1,69 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package jsonhttp
+
+import (
+	"net/url"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// Format identifies how a batch of records is encoded in a request.
+type Format string
+
+const (
+	// FormatJSON encodes a batch as a JSON array of documents.
+	FormatJSON Format = "json"
+
+	// FormatElasticsearch encodes a batch in the newline-delimited
+	// format of the Elasticsearch bulk API, indexing each document.
+	// The target index is taken from the URL, e.g.
+	// https://es.example.com:9200/juju/_bulk.
+	FormatElasticsearch Format = "elasticsearch"
+)
+
+// DefaultTimeout is the time allowed for each request when the
+// config doesn't specify one.
+const DefaultTimeout = 30 * time.Second
+
+// RawConfig holds the raw configuration data for a connection to an
+// HTTP(S) log forwarding target.
+type RawConfig struct {
+	// URL is the http or https URL to which batches of records are
+	// POSTed. It may include user information for basic
+	// authentication.
+	URL string
+
+	// Format is the encoding used for batches of records. If it is
+	// empty, FormatJSON is used.
+	Format Format
+
+	// Timeout is the time allowed for each request. If it is zero,
+	// DefaultTimeout is used.
+	Timeout time.Duration
+}
+
+// Validate ensures that the config is currently valid.
+func (cfg RawConfig) Validate() error {
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return errors.Annotate(err, "parsing URL")
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.NotValidf("URL %q (expected http or https)", cfg.URL)
+	}
+	if u.Host == "" {
+		return errors.NotValidf("URL %q (missing host)", cfg.URL)
+	}
+	switch cfg.Format {
+	case "", FormatJSON, FormatElasticsearch:
+	default:
+		return errors.NotValidf("Format %q", cfg.Format)
+	}
+	if cfg.Timeout < 0 {
+		return errors.NotValidf("negative Timeout")
+	}
+	return nil
+}
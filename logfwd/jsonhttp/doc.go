@@ -0,0 +1,8 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The jsonhttp package holds the tools needed to perform log
+// forwarding from Juju to an HTTP(S) endpoint accepting batches of
+// JSON documents, such as an Elasticsearch bulk API endpoint or a
+// generic webhook.
+package jsonhttp
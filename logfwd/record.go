@@ -33,6 +33,44 @@ type Record struct {
 
 	// Message is the record's body. It may be empty.
 	Message string
+
+	// Audit holds the details of the audited operation the record
+	// describes. It is nil for ordinary log records.
+	Audit *Audit
+}
+
+// Audit holds the details of an audited operation, as recorded by
+// the controller.
+type Audit struct {
+	// ID uniquely identifies the audit entry. Entries may be sent
+	// more than once when forwarding resumes, and can be recognised
+	// by it.
+	ID string
+
+	// OriginType describes how the operation was triggered
+	// (e.g. "API request").
+	OriginType string
+
+	// Operation is the operation that was performed.
+	Operation string
+
+	// RemoteAddress is the address from which the operation was
+	// requested.
+	RemoteAddress string
+
+	// Data holds any additional details of the operation.
+	Data map[string]interface{}
+}
+
+// Validate ensures that the audit details are correct.
+func (a Audit) Validate() error {
+	if a.Operation == "" {
+		return errors.NewNotValid(nil, "empty Operation")
+	}
+
+	// The other fields may be anything, so we don't check them.
+
+	return nil
 }
 
 // Validate ensures that the record is correct.
@@ -53,6 +91,12 @@ func (rec Record) Validate() error {
 
 	// rec.Message may be anything, so we don't check it.
 
+	if rec.Audit != nil {
+		if err := rec.Audit.Validate(); err != nil {
+			return errors.Annotate(err, "invalid Audit")
+		}
+	}
+
 	return nil
 }
 
@@ -67,6 +67,29 @@ func (s *RecordSuite) TestValidateBadLocation(c *gc.C) {
 	c.Check(err, gc.ErrorMatches, `invalid Location: Line set but Filename empty`)
 }
 
+func (s *RecordSuite) TestValidateValidAudit(c *gc.C) {
+	rec := validRecord
+	rec.Audit = &logfwd.Audit{
+		OriginType:    "API request",
+		Operation:     "Application:v3 - Destroy",
+		RemoteAddress: "10.0.0.1",
+	}
+
+	err := rec.Validate()
+
+	c.Check(err, jc.ErrorIsNil)
+}
+
+func (s *RecordSuite) TestValidateBadAudit(c *gc.C) {
+	rec := validRecord
+	rec.Audit = &logfwd.Audit{}
+
+	err := rec.Validate()
+
+	c.Check(err, jc.Satisfies, errors.IsNotValid)
+	c.Check(err, gc.ErrorMatches, `invalid Audit: empty Operation`)
+}
+
 type LocationSuite struct {
 	testing.IsolationSuite
 }
@@ -0,0 +1,21 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logfwd
+
+// PartialSendError is returned by a sink that stopped part way through
+// sending a batch of records. The records before Sent reached the
+// target, so only the rest need to be resent.
+type PartialSendError struct {
+	// Sent is the number of records that were sent before the
+	// failure.
+	Sent int
+
+	// Err is the error that stopped the rest being sent.
+	Err error
+}
+
+// Error implements error.
+func (e *PartialSendError) Error() string {
+	return e.Err.Error()
+}
@@ -0,0 +1,142 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sinkconfig
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/gelf"
+	"github.com/juju/juju/logfwd/jsonhttp"
+	"github.com/juju/juju/logfwd/syslog"
+)
+
+// SinkType identifies the kind of log forwarding target.
+type SinkType string
+
+const (
+	// TypeSyslog forwards records to a syslog (RFC 5424) host over
+	// TLS.
+	TypeSyslog SinkType = "syslog"
+
+	// TypeHTTP forwards batches of records as JSON to an HTTP(S)
+	// endpoint.
+	TypeHTTP SinkType = "http"
+
+	// TypeGELF forwards records to a Graylog GELF TCP input.
+	TypeGELF SinkType = "gelf"
+)
+
+// Validate ensures that the sink type is known.
+func (t SinkType) Validate() error {
+	switch t {
+	case TypeSyslog, TypeHTTP, TypeGELF:
+		return nil
+	}
+	return errors.NotValidf("log forwarding sink type %q", t)
+}
+
+const (
+	// DefaultBatchSize is the number of records sent to the sink
+	// at a time when the config doesn't specify a batch size.
+	DefaultBatchSize = 1
+
+	// DefaultRetryDelay is the time waited before resending a
+	// failed batch when the config doesn't specify a delay.
+	DefaultRetryDelay = 5 * time.Second
+)
+
+// Config holds the configuration of a log forwarding target. Only
+// the sink-specific config matching Type is used.
+type Config struct {
+	// Enabled is true if the log forwarding feature is enabled.
+	Enabled bool
+
+	// Type is the kind of sink to which records are forwarded. If
+	// it is empty, TypeSyslog is used.
+	Type SinkType
+
+	// Syslog holds the config for a syslog sink.
+	Syslog syslog.RawConfig
+
+	// HTTP holds the config for an HTTP(S) sink.
+	HTTP jsonhttp.RawConfig
+
+	// GELF holds the config for a GELF sink.
+	GELF gelf.RawConfig
+
+	// TLS holds the TLS config shared by the HTTP and GELF sinks. The
+	// syslog sink has its own.
+	TLS logfwd.TLSConfig
+
+	// BatchSize is the maximum number of records sent to the sink
+	// at a time. If it is zero, DefaultBatchSize is used.
+	BatchSize int
+
+	// RetryAttempts is the number of times a failed batch is resent
+	// before the failure is reported.
+	RetryAttempts int
+
+	// RetryDelay is the time waited before resending a failed
+	// batch. If it is zero, DefaultRetryDelay is used.
+	RetryDelay time.Duration
+}
+
+// SinkType returns the configured sink type, or the default.
+func (cfg Config) SinkType() SinkType {
+	if cfg.Type == "" {
+		return TypeSyslog
+	}
+	return cfg.Type
+}
+
+// Validate ensures that the config is currently valid. The target of
+// a disabled config is only validated if it has been set.
+func (cfg Config) Validate() error {
+	sinkType := cfg.SinkType()
+	if err := sinkType.Validate(); err != nil {
+		return errors.Trace(err)
+	}
+	if cfg.BatchSize < 0 {
+		return errors.NotValidf("negative BatchSize")
+	}
+	if cfg.RetryAttempts < 0 {
+		return errors.NotValidf("negative RetryAttempts")
+	}
+	if cfg.RetryDelay < 0 {
+		return errors.NotValidf("negative RetryDelay")
+	}
+
+	switch sinkType {
+	case TypeSyslog:
+		syslogCfg := cfg.Syslog
+		syslogCfg.Enabled = cfg.Enabled
+		if err := syslogCfg.Validate(); err != nil {
+			return errors.Annotate(err, "invalid syslog forwarding config")
+		}
+	case TypeHTTP:
+		if !cfg.Enabled && cfg.HTTP.URL == "" {
+			return nil
+		}
+		if err := cfg.HTTP.Validate(); err != nil {
+			return errors.Annotate(err, "invalid HTTP forwarding config")
+		}
+		if err := cfg.TLS.Validate(); err != nil {
+			return errors.Annotate(err, "invalid log forwarding TLS config")
+		}
+	case TypeGELF:
+		if !cfg.Enabled && cfg.GELF.Address == "" {
+			return nil
+		}
+		if err := cfg.GELF.Validate(); err != nil {
+			return errors.Annotate(err, "invalid GELF forwarding config")
+		}
+		if err := cfg.TLS.Validate(); err != nil {
+			return errors.Annotate(err, "invalid log forwarding TLS config")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sinkconfig_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/gelf"
+	"github.com/juju/juju/logfwd/jsonhttp"
+	"github.com/juju/juju/logfwd/sinkconfig"
+	"github.com/juju/juju/logfwd/syslog"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type ConfigSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&ConfigSuite{})
+
+func (s *ConfigSuite) TestZeroValue(c *gc.C) {
+	var cfg sinkconfig.Config
+
+	c.Check(cfg.SinkType(), gc.Equals, sinkconfig.TypeSyslog)
+	c.Check(cfg.Validate(), jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestValidateSyslog(c *gc.C) {
+	cfg := sinkconfig.Config{
+		Enabled: true,
+		Syslog: syslog.RawConfig{
+			Host:       "a.b.c:9876",
+			CACert:     coretesting.CACert,
+			ClientCert: coretesting.ServerCert,
+			ClientKey:  coretesting.ServerKey,
+		},
+	}
+
+	c.Check(cfg.Validate(), jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestValidateSyslogMissingHost(c *gc.C) {
+	cfg := sinkconfig.Config{
+		Enabled: true,
+		Type:    sinkconfig.TypeSyslog,
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, gc.ErrorMatches, `invalid syslog forwarding config: Host "" not valid`)
+}
+
+func (s *ConfigSuite) TestValidateHTTP(c *gc.C) {
+	cfg := sinkconfig.Config{
+		Enabled:       true,
+		Type:          sinkconfig.TypeHTTP,
+		HTTP:          jsonhttp.RawConfig{URL: "https://es.example.com/juju/_bulk"},
+		BatchSize:     100,
+		RetryAttempts: 3,
+	}
+
+	c.Check(cfg.Validate(), jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestValidateHTTPMissingURL(c *gc.C) {
+	cfg := sinkconfig.Config{
+		Enabled: true,
+		Type:    sinkconfig.TypeHTTP,
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, gc.ErrorMatches, `invalid HTTP forwarding config: URL "" \(expected http or https\) not valid`)
+}
+
+func (s *ConfigSuite) TestValidateDisabledHTTPMissingURL(c *gc.C) {
+	cfg := sinkconfig.Config{
+		Type: sinkconfig.TypeHTTP,
+	}
+
+	c.Check(cfg.Validate(), jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestValidateGELF(c *gc.C) {
+	cfg := sinkconfig.Config{
+		Enabled: true,
+		Type:    sinkconfig.TypeGELF,
+		GELF:    gelf.RawConfig{Address: "graylog.example.com"},
+	}
+
+	c.Check(cfg.Validate(), jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestValidateGELFMissingAddress(c *gc.C) {
+	cfg := sinkconfig.Config{
+		Enabled: true,
+		Type:    sinkconfig.TypeGELF,
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, gc.ErrorMatches, `invalid GELF forwarding config: Address "" not valid`)
+}
+
+func (s *ConfigSuite) TestValidateTLS(c *gc.C) {
+	cfg := sinkconfig.Config{
+		Enabled: true,
+		Type:    sinkconfig.TypeGELF,
+		GELF:    gelf.RawConfig{Address: "graylog.example.com", TLS: true},
+		TLS: logfwd.TLSConfig{
+			CACert:     coretesting.CACert,
+			ClientCert: coretesting.ServerCert,
+			ClientKey:  coretesting.ServerKey,
+		},
+	}
+
+	c.Check(cfg.Validate(), jc.ErrorIsNil)
+}
+
+func (s *ConfigSuite) TestValidateBadTLS(c *gc.C) {
+	cfg := sinkconfig.Config{
+		Enabled: true,
+		Type:    sinkconfig.TypeHTTP,
+		HTTP:    jsonhttp.RawConfig{URL: "https://es.example.com/juju/_bulk"},
+		TLS:     logfwd.TLSConfig{CACert: "spam"},
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, gc.ErrorMatches, `invalid log forwarding TLS config: parsing CA certificate: .*`)
+}
+
+func (s *ConfigSuite) TestValidateUnknownType(c *gc.C) {
+	cfg := sinkconfig.Config{
+		Type: "carrier-pigeon",
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, gc.ErrorMatches, `log forwarding sink type "carrier-pigeon" not valid`)
+}
+
+func (s *ConfigSuite) TestValidateNegativeBatchSize(c *gc.C) {
+	cfg := sinkconfig.Config{
+		BatchSize: -1,
+	}
+
+	err := cfg.Validate()
+
+	c.Check(err, gc.ErrorMatches, `negative BatchSize not valid`)
+}
@@ -0,0 +1,6 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The sinkconfig package holds the configuration of a model's log
+// forwarding target, whichever kind of sink it is.
+package sinkconfig
@@ -5,6 +5,7 @@ package syslog
 
 import (
 	"crypto/tls"
+	"encoding/json"
 	"fmt"
 	"io"
 	"time"
@@ -123,7 +124,7 @@ func messageFromRecord(rec logfwd.Record) (rfc5424.Message, error) {
 			Hostname: rfc5424.Hostname{
 				FQDN: rec.Origin.Hostname,
 			},
-			AppName: rfc5424.AppName((rec.Origin.Software.Name + "-" + rec.Origin.ModelUUID)[:48]),
+			AppName: appName(rec.Origin),
 		},
 		StructuredData: rfc5424.StructuredData{
 			&sdelements.Origin{
@@ -159,6 +160,14 @@ func messageFromRecord(rec logfwd.Record) (rfc5424.Message, error) {
 		Msg: rec.Message,
 	}
 
+	if rec.Audit != nil {
+		auditElement, err := auditElementFromRecord(rec)
+		if err != nil {
+			return msg, errors.Trace(err)
+		}
+		msg.StructuredData = append(msg.StructuredData, auditElement)
+	}
+
 	switch rec.Level {
 	case loggo.ERROR:
 		msg.Priority.Severity = rfc5424.SeverityError
@@ -177,3 +186,39 @@ func messageFromRecord(rec logfwd.Record) (rfc5424.Message, error) {
 	}
 	return msg, nil
 }
+
+// maxAppNameLen is the maximum length of the APP-NAME header field,
+// as defined by RFC 5424.
+const maxAppNameLen = 48
+
+func appName(origin logfwd.Origin) rfc5424.AppName {
+	name := origin.Software.Name + "-" + origin.ModelUUID
+	if len(name) > maxAppNameLen {
+		name = name[:maxAppNameLen]
+	}
+	return rfc5424.AppName(name)
+}
+
+func auditElementFromRecord(rec logfwd.Record) (*sdelements.Private, error) {
+	data, err := json.Marshal(rec.Audit.Data)
+	if err != nil {
+		return nil, errors.Annotate(err, "marshalling audit data")
+	}
+	return &sdelements.Private{
+		Name: "audit",
+		PEN:  sdelements.PrivateEnterpriseNumber(rec.Origin.Software.PrivateEnterpriseNumber),
+		Data: []rfc5424.StructuredDataParam{{
+			Name:  "origin-type",
+			Value: rfc5424.StructuredDataParamValue(rec.Audit.OriginType),
+		}, {
+			Name:  "operation",
+			Value: rfc5424.StructuredDataParamValue(rec.Audit.Operation),
+		}, {
+			Name:  "remote-address",
+			Value: rfc5424.StructuredDataParamValue(rec.Audit.RemoteAddress),
+		}, {
+			Name:  "data",
+			Value: rfc5424.StructuredDataParamValue(data),
+		}},
+	}, nil
+}
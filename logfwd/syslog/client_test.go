@@ -148,6 +148,59 @@ func (s *ClientSuite) TestSendLogFull(c *gc.C) {
 	})
 }
 
+func (s *ClientSuite) TestSendAudit(c *gc.C) {
+	cID := "9f484882-2f18-4fd2-967d-db9663db7bea"
+	mID := "deadbeef-2f18-4fd2-967d-db9663db7bea"
+	ver := version.MustParse("1.2.3")
+	ts := time.Unix(12345, 0)
+	origin, err := logfwd.OriginForJuju(names.NewUserTag("bob"), cID, mID, ver)
+	c.Assert(err, jc.ErrorIsNil)
+	rec := logfwd.Record{
+		Origin:    origin,
+		Timestamp: ts,
+		Level:     loggo.INFO,
+		Location: logfwd.SourceLocation{
+			Module: "juju.audit",
+			Line:   -1,
+		},
+		Message: "deploy",
+		Audit: &logfwd.Audit{
+			OriginType:    "user",
+			Operation:     "deploy",
+			RemoteAddress: "10.0.0.1",
+			Data:          map[string]interface{}{"application": "mysql"},
+		},
+	}
+	client := syslog.Client{Sender: s.sender}
+
+	err = client.Send([]logfwd.Record{rec})
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.stub.CheckCallNames(c, "Send")
+	msg := s.stub.Calls()[0].Args[0].(rfc5424.Message)
+	c.Check(msg.Severity, gc.Equals, rfc5424.SeverityInformational)
+	c.Check(msg.AppName, gc.Equals, rfc5424.AppName("juju-deadbeef-2f18-4fd2-967d-db9663db7bea"))
+	c.Check(msg.Msg, gc.Equals, "deploy")
+	c.Assert(msg.StructuredData, gc.HasLen, 4)
+	c.Check(msg.StructuredData[3], jc.DeepEquals, &sdelements.Private{
+		Name: "audit",
+		PEN:  28978,
+		Data: []rfc5424.StructuredDataParam{{
+			Name:  "origin-type",
+			Value: "user",
+		}, {
+			Name:  "operation",
+			Value: "deploy",
+		}, {
+			Name:  "remote-address",
+			Value: "10.0.0.1",
+		}, {
+			Name:  "data",
+			Value: `{"application":"mysql"}`,
+		}},
+	})
+}
+
 func (s *ClientSuite) TestSendLogLevels(c *gc.C) {
 	tag := names.NewMachineTag("99")
 	cID := "9f484882-2f18-4fd2-967d-db9663db7bea"
@@ -0,0 +1,56 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logfwd
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/cert"
+)
+
+// TLSConfig holds the raw TLS configuration for a connection to a log
+// forwarding target. All of the fields are optional.
+type TLSConfig struct {
+	// CACert is the TLS CA certificate (x.509, PEM-encoded) to use
+	// for validating the server certificate when connecting. If it
+	// is empty, the host's root CA set is used.
+	CACert string
+
+	// ClientCert is the TLS certificate (x.509, PEM-encoded) to use
+	// when connecting. If it is set then ClientKey must be too.
+	ClientCert string
+
+	// ClientKey is the TLS private key (x.509, PEM-encoded) to use
+	// when connecting.
+	ClientKey string
+}
+
+// Validate ensures that the config is currently valid.
+func (cfg TLSConfig) Validate() error {
+	_, err := cfg.ClientConfig()
+	return errors.Trace(err)
+}
+
+// ClientConfig returns the TLS client configuration described by cfg.
+func (cfg TLSConfig) ClientConfig() (*tls.Config, error) {
+	tlsCfg := &tls.Config{}
+	if cfg.ClientCert != "" || cfg.ClientKey != "" {
+		clientCert, err := tls.X509KeyPair([]byte(cfg.ClientCert), []byte(cfg.ClientKey))
+		if err != nil {
+			return nil, errors.Annotate(err, "parsing client key pair")
+		}
+		tlsCfg.Certificates = []tls.Certificate{clientCert}
+	}
+	if cfg.CACert != "" {
+		caCert, err := cert.ParseCert(cfg.CACert)
+		if err != nil {
+			return nil, errors.Annotate(err, "parsing CA certificate")
+		}
+		tlsCfg.RootCAs = x509.NewCertPool()
+		tlsCfg.RootCAs.AddCert(caCert)
+	}
+	return tlsCfg, nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logfwd_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type TLSConfigSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&TLSConfigSuite{})
+
+func (s *TLSConfigSuite) TestZeroValue(c *gc.C) {
+	var cfg logfwd.TLSConfig
+	tlsCfg, err := cfg.ClientConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(tlsCfg.RootCAs, gc.IsNil)
+	c.Check(tlsCfg.Certificates, gc.HasLen, 0)
+}
+
+func (s *TLSConfigSuite) TestFull(c *gc.C) {
+	cfg := logfwd.TLSConfig{
+		CACert:     coretesting.CACert,
+		ClientCert: coretesting.ServerCert,
+		ClientKey:  coretesting.ServerKey,
+	}
+	tlsCfg, err := cfg.ClientConfig()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(tlsCfg.RootCAs, gc.NotNil)
+	c.Check(tlsCfg.Certificates, gc.HasLen, 1)
+}
+
+func (s *TLSConfigSuite) TestValidateBadCACert(c *gc.C) {
+	cfg := logfwd.TLSConfig{
+		CACert: "spam",
+	}
+	err := cfg.Validate()
+	c.Check(err, gc.ErrorMatches, `parsing CA certificate: .*`)
+}
+
+func (s *TLSConfigSuite) TestValidateMissingClientKey(c *gc.C) {
+	cfg := logfwd.TLSConfig{
+		ClientCert: coretesting.ServerCert,
+	}
+	err := cfg.Validate()
+	c.Check(err, gc.ErrorMatches, `parsing client key pair: .*`)
+}
@@ -0,0 +1,367 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package modelarchive reads and writes self-contained archives of a
+// single model: the model's serialized description, as exported for
+// migration, along with the charms, agent binaries and resources it
+// uses. Such an archive holds everything needed to import the model
+// into a controller again.
+package modelarchive
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/version"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/apiserver/params"
+)
+
+var logger = loggo.GetLogger("juju.migration.modelarchive")
+
+// FormatVersion is the version of the archive layout written by
+// Write. Archives with a later format version can't be read.
+const FormatVersion = 1
+
+const (
+	contentDir   = "juju-model-backup"
+	metadataFile = "metadata.json"
+	modelFile    = "model.yaml"
+	charmsDir    = "charms"
+	toolsDir     = "tools"
+	resourcesDir = "resources"
+)
+
+// Metadata describes the model held in an archive, and lists the
+// binaries stored with it.
+type Metadata struct {
+	FormatVersion int            `json:"format-version"`
+	ModelUUID     string         `json:"model-uuid"`
+	ModelName     string         `json:"model-name"`
+	Created       time.Time      `json:"created"`
+	JujuVersion   version.Number `json:"juju-version"`
+
+	Charms    []string                         `json:"charms"`
+	Tools     []params.SerializedModelTools    `json:"tools"`
+	Resources []params.SerializedModelResource `json:"resources"`
+}
+
+// Source supplies the binaries used by a model while it is written
+// to an archive. It has the same methods as the downloaders used for
+// migration, so an *api.Client can provide charms and tools.
+type Source interface {
+	OpenCharm(*charm.URL) (io.ReadCloser, error)
+	OpenURI(string, url.Values) (io.ReadCloser, error)
+	OpenResource(application, name string) (io.ReadCloser, error)
+}
+
+// Write writes a gzipped tar archive of the model to w. The model
+// bytes are the serialized model description, and every charm, tools
+// version and resource listed in the metadata is fetched from the
+// source and stored alongside it.
+func Write(w io.Writer, meta Metadata, model []byte, source Source) error {
+	meta.FormatVersion = FormatVersion
+	metaData, err := json.Marshal(meta)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	gzw := gzip.NewWriter(w)
+	tw := tar.NewWriter(gzw)
+	aw := &archiveWriter{tw: tw, dirs: make(map[string]bool)}
+	if err := aw.writeBytes(metadataFile, metaData); err != nil {
+		return errors.Trace(err)
+	}
+	if err := aw.writeBytes(modelFile, model); err != nil {
+		return errors.Trace(err)
+	}
+
+	for _, charmURL := range meta.Charms {
+		curl, err := charm.ParseURL(charmURL)
+		if err != nil {
+			return errors.Annotate(err, "bad charm URL")
+		}
+		logger.Debugf("archiving charm %s", curl)
+		if err := aw.writeFrom(charmPath(curl), func() (io.ReadCloser, error) {
+			return source.OpenCharm(curl)
+		}); err != nil {
+			return errors.Annotatef(err, "cannot archive charm %s", curl)
+		}
+	}
+
+	for _, tools := range meta.Tools {
+		logger.Debugf("archiving tools %s", tools.Version)
+		uri := tools.URI
+		if err := aw.writeFrom(toolsPath(tools.Version), func() (io.ReadCloser, error) {
+			return source.OpenURI(uri, nil)
+		}); err != nil {
+			return errors.Annotatef(err, "cannot archive tools %s", tools.Version)
+		}
+	}
+
+	for _, res := range meta.Resources {
+		if res.ApplicationRevision.Timestamp.IsZero() {
+			// Placeholder resources have no content to archive.
+			continue
+		}
+		logger.Debugf("archiving resource %s for %s", res.Name, res.Application)
+		app, name := res.Application, res.Name
+		if err := aw.writeFrom(resourcePath(app, name), func() (io.ReadCloser, error) {
+			return source.OpenResource(app, name)
+		}); err != nil {
+			return errors.Annotatef(err, "cannot archive resource %s for %s", name, app)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return errors.Annotate(err, "while closing archive")
+	}
+	return errors.Annotate(gzw.Close(), "while compressing archive")
+}
+
+// archiveWriter adds files to a tar archive under the content
+// directory, adding each parent directory the first time it's needed.
+type archiveWriter struct {
+	tw   *tar.Writer
+	dirs map[string]bool
+}
+
+func (aw *archiveWriter) writeHeader(name string, size int64) error {
+	name = path.Join(contentDir, name)
+	var parents []string
+	for dir := path.Dir(name); dir != "." && !aw.dirs[dir]; dir = path.Dir(dir) {
+		parents = append([]string{dir}, parents...)
+	}
+	now := time.Now()
+	for _, dir := range parents {
+		aw.dirs[dir] = true
+		if err := aw.tw.WriteHeader(&tar.Header{
+			Name:     dir + "/",
+			Typeflag: tar.TypeDir,
+			Mode:     0755,
+			ModTime:  now,
+		}); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(aw.tw.WriteHeader(&tar.Header{
+		Name:     name,
+		Typeflag: tar.TypeReg,
+		Mode:     0644,
+		Size:     size,
+		ModTime:  now,
+	}))
+}
+
+func (aw *archiveWriter) writeBytes(name string, data []byte) error {
+	if err := aw.writeHeader(name, int64(len(data))); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := aw.tw.Write(data)
+	return errors.Trace(err)
+}
+
+// writeFrom adds the content opened by the function to the archive.
+// The content is spooled to a temporary file first, since its size
+// must be known before it can be written.
+func (aw *archiveWriter) writeFrom(name string, open func() (io.ReadCloser, error)) error {
+	reader, err := open()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer reader.Close()
+
+	tempFile, err := ioutil.TempFile("", "juju-model-backup")
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer func() {
+		tempFile.Close()
+		os.Remove(tempFile.Name())
+	}()
+	size, err := io.Copy(tempFile, reader)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if _, err := tempFile.Seek(0, io.SeekStart); err != nil {
+		return errors.Trace(err)
+	}
+
+	if err := aw.writeHeader(name, size); err != nil {
+		return errors.Trace(err)
+	}
+	_, err = io.Copy(aw.tw, tempFile)
+	return errors.Trace(err)
+}
+
+// Archive is an unpacked model archive. Its methods supply the
+// archived binaries, with the same signatures as the downloaders
+// used by migration.UploadBinaries.
+type Archive struct {
+	// Metadata describes the archived model.
+	Metadata Metadata
+
+	// Model holds the serialized model description.
+	Model []byte
+
+	dir string
+}
+
+// Open unpacks the archive into a temporary directory and reads its
+// metadata and model description. The returned archive must be
+// closed to remove the temporary directory.
+func Open(r io.Reader) (_ *Archive, err error) {
+	dir, err := ioutil.TempDir("", "juju-model-backup")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer func() {
+		if err != nil {
+			os.RemoveAll(dir)
+		}
+	}()
+	if err := unpack(r, dir); err != nil {
+		return nil, errors.Annotate(err, "while unpacking archive")
+	}
+
+	a := &Archive{dir: dir}
+	metaData, err := ioutil.ReadFile(a.path(metadataFile))
+	if err != nil {
+		return nil, errors.Annotate(err, "while reading metadata")
+	}
+	if err := json.Unmarshal(metaData, &a.Metadata); err != nil {
+		return nil, errors.Annotate(err, "while reading metadata")
+	}
+	if a.Metadata.FormatVersion > FormatVersion {
+		return nil, errors.Errorf(
+			"archive format version %d not supported (latest supported is %d)",
+			a.Metadata.FormatVersion, FormatVersion,
+		)
+	}
+	if a.Model, err = ioutil.ReadFile(a.path(modelFile)); err != nil {
+		return nil, errors.Annotate(err, "while reading model")
+	}
+	return a, nil
+}
+
+// unpack extracts the regular files in the gzipped tar archive under
+// dir, refusing any which would land outside it.
+func unpack(r io.Reader, dir string) error {
+	gzr, err := gzip.NewReader(r)
+	if err != nil {
+		return errors.Annotate(err, "while uncompressing archive")
+	}
+	defer gzr.Close()
+
+	tr := tar.NewReader(gzr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if hdr.Typeflag != tar.TypeReg && hdr.Typeflag != tar.TypeRegA {
+			continue
+		}
+		name := path.Clean(hdr.Name)
+		if path.IsAbs(name) || name == ".." || strings.HasPrefix(name, "../") {
+			return errors.Errorf("archive path %q not valid", hdr.Name)
+		}
+		filename := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(filename), 0700); err != nil {
+			return errors.Trace(err)
+		}
+		file, err := os.OpenFile(filename, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		_, err = io.Copy(file, tr)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
+
+// SerializedModel returns the archived model, and the binaries it
+// uses, in the form returned by the ModelManager facade's
+// ExportModels call.
+func (a *Archive) SerializedModel() params.SerializedModel {
+	return params.SerializedModel{
+		Bytes:     a.Model,
+		Charms:    a.Metadata.Charms,
+		Tools:     a.Metadata.Tools,
+		Resources: a.Metadata.Resources,
+	}
+}
+
+// OpenCharm returns the archived charm with the given URL.
+func (a *Archive) OpenCharm(curl *charm.URL) (io.ReadCloser, error) {
+	return a.open(charmPath(curl), "charm %s", curl)
+}
+
+// OpenURI returns the archived tools exported with the given URI.
+func (a *Archive) OpenURI(uri string, query url.Values) (io.ReadCloser, error) {
+	for _, tools := range a.Metadata.Tools {
+		if tools.URI == uri {
+			return a.open(toolsPath(tools.Version), "tools %s", tools.Version)
+		}
+	}
+	return nil, errors.NotFoundf("tools for %q", uri)
+}
+
+// OpenResource returns the archived content of the named resource
+// for the application.
+func (a *Archive) OpenResource(application, name string) (io.ReadCloser, error) {
+	return a.open(resourcePath(application, name), "resource %s for %s", name, application)
+}
+
+// Close removes the unpacked archive.
+func (a *Archive) Close() error {
+	return errors.Trace(os.RemoveAll(a.dir))
+}
+
+func (a *Archive) open(name string, format string, args ...interface{}) (io.ReadCloser, error) {
+	file, err := os.Open(a.path(name))
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf(format+" in archive", args...)
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return file, nil
+}
+
+func (a *Archive) path(name string) string {
+	return filepath.Join(a.dir, contentDir, filepath.FromSlash(name))
+}
+
+// charmPath returns the path within the archive of the charm. The
+// URL is escaped so that it forms a single path element.
+func charmPath(curl *charm.URL) string {
+	return path.Join(charmsDir, url.QueryEscape(curl.String())+".zip")
+}
+
+func toolsPath(vers string) string {
+	return path.Join(toolsDir, url.QueryEscape(vers)+".tar.gz")
+}
+
+func resourcePath(application, name string) string {
+	return path.Join(resourcesDir, url.QueryEscape(application), url.QueryEscape(name))
+}
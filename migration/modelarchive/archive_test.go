@@ -0,0 +1,201 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package modelarchive_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/charm.v6-unstable"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/migration/modelarchive"
+	"github.com/juju/juju/testing"
+)
+
+type archiveSuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&archiveSuite{})
+
+// fakeSource supplies binaries whose content identifies them.
+type fakeSource struct {
+	opened []string
+}
+
+func (s *fakeSource) open(what string) (io.ReadCloser, error) {
+	s.opened = append(s.opened, what)
+	return ioutil.NopCloser(bytes.NewBufferString("<" + what + ">")), nil
+}
+
+func (s *fakeSource) OpenCharm(curl *charm.URL) (io.ReadCloser, error) {
+	return s.open("charm " + curl.String())
+}
+
+func (s *fakeSource) OpenURI(uri string, query url.Values) (io.ReadCloser, error) {
+	return s.open("uri " + uri)
+}
+
+func (s *fakeSource) OpenResource(application, name string) (io.ReadCloser, error) {
+	return s.open(fmt.Sprintf("resource %s/%s", application, name))
+}
+
+func testMetadata() modelarchive.Metadata {
+	return modelarchive.Metadata{
+		ModelUUID:   "deadbeef-0bad-400d-8000-4b1d0d06f00d",
+		ModelName:   "admin/mymodel",
+		Created:     time.Date(2017, 5, 1, 12, 0, 0, 0, time.UTC),
+		JujuVersion: version.MustParse("2.2.0"),
+		Charms:      []string{"cs:~user/xenial/mysql-3", "local:xenial/wordpress-0"},
+		Tools: []params.SerializedModelTools{{
+			Version: "2.2.0-xenial-amd64",
+			URI:     "/tools/2.2.0-xenial-amd64",
+		}},
+		Resources: []params.SerializedModelResource{{
+			Application: "mysql",
+			Name:        "backups",
+			ApplicationRevision: params.SerializedModelResourceRevision{
+				Revision:  1,
+				Timestamp: time.Date(2017, 4, 1, 12, 0, 0, 0, time.UTC),
+			},
+		}, {
+			Application: "wordpress",
+			Name:        "placeholder",
+		}},
+	}
+}
+
+func (s *archiveSuite) writeArchive(c *gc.C) (*bytes.Buffer, *fakeSource) {
+	var buf bytes.Buffer
+	source := &fakeSource{}
+	err := modelarchive.Write(&buf, testMetadata(), []byte("model: yaml\n"), source)
+	c.Assert(err, jc.ErrorIsNil)
+	return &buf, source
+}
+
+func readAll(c *gc.C, r io.ReadCloser, err error) string {
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	return string(data)
+}
+
+func (s *archiveSuite) TestWriteFetchesBinaries(c *gc.C) {
+	_, source := s.writeArchive(c)
+	c.Assert(source.opened, jc.DeepEquals, []string{
+		"charm cs:~user/xenial/mysql-3",
+		"charm local:xenial/wordpress-0",
+		"uri /tools/2.2.0-xenial-amd64",
+		"resource mysql/backups",
+	})
+}
+
+func (s *archiveSuite) TestRoundTrip(c *gc.C) {
+	buf, _ := s.writeArchive(c)
+	archive, err := modelarchive.Open(buf)
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+
+	expected := testMetadata()
+	expected.FormatVersion = modelarchive.FormatVersion
+	c.Check(archive.Metadata, jc.DeepEquals, expected)
+	c.Check(string(archive.Model), gc.Equals, "model: yaml\n")
+	c.Check(archive.SerializedModel(), jc.DeepEquals, params.SerializedModel{
+		Bytes:     []byte("model: yaml\n"),
+		Charms:    expected.Charms,
+		Tools:     expected.Tools,
+		Resources: expected.Resources,
+	})
+
+	r, err := archive.OpenCharm(charm.MustParseURL("cs:~user/xenial/mysql-3"))
+	c.Check(readAll(c, r, err), gc.Equals, "<charm cs:~user/xenial/mysql-3>")
+	r, err = archive.OpenURI("/tools/2.2.0-xenial-amd64", nil)
+	c.Check(readAll(c, r, err), gc.Equals, "<uri /tools/2.2.0-xenial-amd64>")
+	r, err = archive.OpenResource("mysql", "backups")
+	c.Check(readAll(c, r, err), gc.Equals, "<resource mysql/backups>")
+}
+
+func (s *archiveSuite) TestOpenMissingBinaries(c *gc.C) {
+	buf, _ := s.writeArchive(c)
+	archive, err := modelarchive.Open(buf)
+	c.Assert(err, jc.ErrorIsNil)
+	defer archive.Close()
+
+	_, err = archive.OpenCharm(charm.MustParseURL("cs:xenial/mysql-4"))
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	c.Check(err, gc.ErrorMatches, "charm cs:xenial/mysql-4 in archive not found")
+	_, err = archive.OpenURI("/tools/2.1.0-xenial-amd64", nil)
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+	_, err = archive.OpenResource("wordpress", "placeholder")
+	c.Check(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *archiveSuite) TestClose(c *gc.C) {
+	buf, _ := s.writeArchive(c)
+	archive, err := modelarchive.Open(buf)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(archive.Close(), jc.ErrorIsNil)
+
+	_, err = archive.OpenResource("mysql", "backups")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func makeTarball(c *gc.C, files map[string]string) *bytes.Buffer {
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	for name, content := range files {
+		err := tw.WriteHeader(&tar.Header{
+			Name:     name,
+			Typeflag: tar.TypeReg,
+			Mode:     0644,
+			Size:     int64(len(content)),
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = tw.Write([]byte(content))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(tw.Close(), jc.ErrorIsNil)
+	c.Assert(gzw.Close(), jc.ErrorIsNil)
+	return &buf
+}
+
+func (s *archiveSuite) TestOpenUnsupportedFormat(c *gc.C) {
+	_, err := modelarchive.Open(makeTarball(c, map[string]string{
+		"juju-model-backup/metadata.json": `{"format-version": 99}`,
+		"juju-model-backup/model.yaml":    "model: yaml\n",
+	}))
+	c.Assert(err, gc.ErrorMatches, `archive format version 99 not supported \(latest supported is 1\)`)
+}
+
+func (s *archiveSuite) TestOpenMissingModel(c *gc.C) {
+	_, err := modelarchive.Open(makeTarball(c, map[string]string{
+		"juju-model-backup/metadata.json": `{"format-version": 1}`,
+	}))
+	c.Assert(err, gc.ErrorMatches, "while reading model: .*")
+}
+
+func (s *archiveSuite) TestOpenRejectsPathsOutsideArchive(c *gc.C) {
+	_, err := modelarchive.Open(makeTarball(c, map[string]string{
+		"../../etc/evil": "<evil>",
+	}))
+	c.Assert(err, gc.ErrorMatches, `while unpacking archive: archive path "../../etc/evil" not valid`)
+}
+
+func (s *archiveSuite) TestOpenNotAnArchive(c *gc.C) {
+	_, err := modelarchive.Open(bytes.NewBufferString("not an archive"))
+	c.Assert(err, gc.ErrorMatches, "while unpacking archive: while uncompressing archive: .*")
+}
@@ -832,6 +832,9 @@ func (e *environ) Bootstrap(ctx environs.BootstrapContext, args environs.Bootstr
 					f("navel", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 						io.WriteString(w, "gazing")
 					}))
+					f("/metrics", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+						io.WriteString(w, "counting")
+					}))
 				},
 			})
 			if err != nil {
@@ -0,0 +1,318 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package compression compresses the messages sent on RPC websocket
+// connections.
+//
+// Clients offer compression by sending the Header with the value
+// Deflate when dialing; servers that accept send the same header back.
+// Each message on a compressed connection is then sent as a binary
+// websocket message holding the message DEFLATE-compressed (RFC 1951)
+// on its own, so that no compression state is kept between messages.
+//
+// Compression is agreed before the client has logged in, so a server
+// only accepts compressed messages once the client has logged in: until
+// then, clients send their messages uncompressed. Servers compress the
+// messages they send straight away.
+package compression
+
+import (
+	"compress/flate"
+	"errors"
+	"io"
+	"io/ioutil"
+	"strings"
+	"sync"
+
+	"github.com/gorilla/websocket"
+)
+
+const (
+	// Header is the HTTP header with which clients offer, and servers
+	// accept, compression of RPC websocket messages.
+	Header = "X-Juju-Websocket-Compression"
+
+	// Deflate is the value of Header for DEFLATE compression.
+	Deflate = "deflate"
+)
+
+// level is the compression level used for messages. Compression
+// happens on every message the controller sends, so speed matters
+// more than the last few bytes saved.
+const level = flate.BestSpeed
+
+// MaxMessageSize is the largest size, once decompressed, of a message
+// read from a Conn. It stops a small message from decompressing to an
+// unbounded amount of memory.
+const MaxMessageSize = 64 << 20
+
+// ErrMessageTooLarge is returned when reading a message that
+// decompresses to more than MaxMessageSize bytes.
+var ErrMessageTooLarge = errors.New("decompressed message too large")
+
+// Offered reports whether the given request header offers compression.
+func Offered(header map[string][]string) bool {
+	for _, value := range header[Header] {
+		for _, method := range strings.Split(value, ",") {
+			if strings.TrimSpace(method) == Deflate {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Counter is told the sizes of the messages sent and received on
+// compressed connections, before and after compression.
+type Counter interface {
+	// Sent is called when a message has been sent.
+	Sent(uncompressed, compressed int)
+
+	// Received is called when a message has been received.
+	Received(uncompressed, compressed int)
+}
+
+// Conn wraps a websocket connection, compressing the messages written
+// to it and decompressing the messages read from it. Like a websocket
+// connection, it supports one concurrent reader and one concurrent
+// writer.
+type Conn struct {
+	conn    *websocket.Conn
+	counter Counter
+
+	// mu guards the fields below it.
+	mu sync.Mutex
+
+	// compressReads and compressWrites hold whether the messages read
+	// from and written to the connection are compressed.
+	compressReads  bool
+	compressWrites bool
+
+	// reader holds the reader of the message last returned by
+	// NextReader, if it has not yet been finished.
+	reader *messageReader
+}
+
+// NewConn returns a Conn that compresses the messages on the given
+// websocket connection. If counter is not nil, it is told the size of
+// each message.
+func NewConn(conn *websocket.Conn, counter Counter) *Conn {
+	return &Conn{
+		conn:           conn,
+		counter:        counter,
+		compressReads:  true,
+		compressWrites: true,
+	}
+}
+
+// NewServerConn returns a Conn for the server end of a connection. It
+// compresses the messages it writes, but reads messages uncompressed
+// until LoggedIn is called.
+func NewServerConn(conn *websocket.Conn, counter Counter) *Conn {
+	c := NewConn(conn, counter)
+	c.compressReads = false
+	return c
+}
+
+// NewClientConn returns a Conn for the client end of a connection. It
+// decompresses the messages it reads, but writes messages uncompressed
+// until LoggedIn is called.
+func NewClientConn(conn *websocket.Conn, counter Counter) *Conn {
+	c := NewConn(conn, counter)
+	c.compressWrites = false
+	return c
+}
+
+// LoggedIn records that the client has logged in, after which messages
+// are compressed in both directions. A server must call it before it
+// sends its reply to the login request, and a client after it receives
+// that reply and before it sends any other request.
+func (c *Conn) LoggedIn() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.compressReads = true
+	c.compressWrites = true
+}
+
+func (c *Conn) compressed() (reads, writes bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.compressReads, c.compressWrites
+}
+
+var flateWriters = sync.Pool{
+	New: func() interface{} {
+		w, err := flate.NewWriter(nil, level)
+		if err != nil {
+			// This can only happen if level is invalid.
+			panic(err)
+		}
+		return w
+	},
+}
+
+var flateReaders = sync.Pool{
+	New: func() interface{} {
+		return flate.NewReader(nil)
+	},
+}
+
+// NextWriter returns a writer for the next message. The message is
+// compressed and sent as a binary message, whatever the given message
+// type, and is complete when the writer is closed.
+func (c *Conn) NextWriter(messageType int) (io.WriteCloser, error) {
+	if _, compress := c.compressed(); !compress {
+		return c.conn.NextWriter(messageType)
+	}
+	w, err := c.conn.NextWriter(websocket.BinaryMessage)
+	if err != nil {
+		return nil, err
+	}
+	wire := &countingWriter{w: w}
+	fw := flateWriters.Get().(*flate.Writer)
+	fw.Reset(wire)
+	return &messageWriter{
+		conn:    c,
+		message: w,
+		wire:    wire,
+		flate:   fw,
+	}, nil
+}
+
+// WriteMessage writes a message with the given type and data.
+// Control messages are written uncompressed.
+func (c *Conn) WriteMessage(messageType int, data []byte) error {
+	switch messageType {
+	case websocket.CloseMessage, websocket.PingMessage, websocket.PongMessage:
+		return c.conn.WriteMessage(messageType, data)
+	}
+	w, err := c.NextWriter(messageType)
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+// NextReader returns the type of the next message and a reader that
+// decompresses it. Reading fails with ErrMessageTooLarge if the message
+// decompresses to more than MaxMessageSize bytes.
+func (c *Conn) NextReader() (int, io.Reader, error) {
+	c.finishReader()
+	messageType, r, err := c.conn.NextReader()
+	if err != nil {
+		return messageType, nil, err
+	}
+	if compress, _ := c.compressed(); !compress {
+		return messageType, r, nil
+	}
+	wire := &countingReader{r: r}
+	fr := flateReaders.Get().(io.ReadCloser)
+	if err := fr.(flate.Resetter).Reset(wire, nil); err != nil {
+		return messageType, nil, err
+	}
+	c.reader = &messageReader{
+		wire:    wire,
+		flate:   fr,
+		limited: io.LimitReader(fr, MaxMessageSize+1),
+	}
+	return messageType, c.reader, nil
+}
+
+// finishReader finishes reading the last message returned by
+// NextReader, counting it and releasing its resources.
+func (c *Conn) finishReader() {
+	r := c.reader
+	if r == nil {
+		return
+	}
+	c.reader = nil
+	// Read any trailing data so that the whole message is counted.
+	io.Copy(ioutil.Discard, r)
+	flateReaders.Put(r.flate)
+	if c.counter != nil && r.err == io.EOF {
+		c.counter.Received(r.n, r.wire.n)
+	}
+}
+
+// Close closes the underlying websocket connection.
+func (c *Conn) Close() error {
+	return c.conn.Close()
+}
+
+type messageWriter struct {
+	conn    *Conn
+	message io.WriteCloser
+	wire    *countingWriter
+	flate   *flate.Writer
+	n       int
+	closed  bool
+}
+
+func (w *messageWriter) Write(p []byte) (int, error) {
+	n, err := w.flate.Write(p)
+	w.n += n
+	return n, err
+}
+
+func (w *messageWriter) Close() error {
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+	err := w.flate.Close()
+	flateWriters.Put(w.flate)
+	if closeErr := w.message.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil && w.conn.counter != nil {
+		w.conn.counter.Sent(w.n, w.wire.n)
+	}
+	return err
+}
+
+type messageReader struct {
+	wire    *countingReader
+	flate   io.ReadCloser
+	limited io.Reader
+	n       int
+	err     error
+}
+
+func (r *messageReader) Read(p []byte) (int, error) {
+	if r.err != nil {
+		return 0, r.err
+	}
+	n, err := r.limited.Read(p)
+	r.n += n
+	if r.n > MaxMessageSize {
+		err = ErrMessageTooLarge
+	}
+	r.err = err
+	return n, err
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += n
+	return n, err
+}
+
+type countingReader struct {
+	r io.Reader
+	n int
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += n
+	return n, err
+}
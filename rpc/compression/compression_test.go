@@ -0,0 +1,241 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package compression_test
+
+import (
+	"bytes"
+	"compress/flate"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	stdtesting "testing"
+
+	"github.com/gorilla/websocket"
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/compression"
+	"github.com/juju/juju/rpc/jsoncodec"
+)
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type compressionSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&compressionSuite{})
+
+func (*compressionSuite) TestOffered(c *gc.C) {
+	for i, test := range []struct {
+		header http.Header
+		expect bool
+	}{
+		{http.Header{}, false},
+		{http.Header{compression.Header: {"gzip"}}, false},
+		{http.Header{compression.Header: {"deflate"}}, true},
+		{http.Header{compression.Header: {"gzip, deflate"}}, true},
+		{http.Header{compression.Header: {"gzip", "deflate"}}, true},
+	} {
+		c.Logf("test %d: %v", i, test.header)
+		c.Check(compression.Offered(test.header), gc.Equals, test.expect)
+	}
+}
+
+func (s *compressionSuite) TestWireFormat(c *gc.C) {
+	client, server := s.dialPair(c)
+	message := strings.Repeat("compressible ", 100)
+	err := compression.NewConn(client, nil).WriteMessage(websocket.TextMessage, []byte(message))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Each message is sent as a binary message holding the
+	// message DEFLATE-compressed on its own.
+	messageType, data, err := server.ReadMessage()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(messageType, gc.Equals, websocket.BinaryMessage)
+	c.Assert(len(data) < len(message), jc.IsTrue)
+	decompressed, err := ioutil.ReadAll(flate.NewReader(bytes.NewReader(data)))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(decompressed), gc.Equals, message)
+}
+
+func (s *compressionSuite) TestCounter(c *gc.C) {
+	client, server := s.dialPair(c)
+	var clientCounter, serverCounter counter
+	clientConn := compression.NewConn(client, &clientCounter)
+	serverConn := compression.NewConn(server, &serverCounter)
+
+	messages := []string{"x", strings.Repeat("compressible ", 100)}
+	for _, message := range messages {
+		err := clientConn.WriteMessage(websocket.BinaryMessage, []byte(message))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	for _, message := range messages {
+		_, r, err := serverConn.NextReader()
+		c.Assert(err, jc.ErrorIsNil)
+		// Read only part of the message; the rest is counted
+		// when the next message is read.
+		buf := make([]byte, 1)
+		n, err := r.Read(buf)
+		if err != io.EOF {
+			c.Assert(err, jc.ErrorIsNil)
+		}
+		c.Assert(string(buf[:n]), gc.Equals, message[:1])
+	}
+	// Closing the connection makes the final read fail, after the
+	// last message has been counted.
+	client.Close()
+	_, _, err := serverConn.NextReader()
+	c.Assert(err, gc.NotNil)
+
+	c.Assert(clientCounter.received, gc.HasLen, 0)
+	c.Assert(serverCounter.sent, gc.HasLen, 0)
+	c.Assert(clientCounter.sent, gc.HasLen, 2)
+	c.Assert(serverCounter.received, jc.DeepEquals, clientCounter.sent)
+	for i, message := range messages {
+		c.Check(clientCounter.sent[i].uncompressed, gc.Equals, len(message))
+	}
+	c.Check(clientCounter.sent[1].compressed < len(messages[1]), jc.IsTrue)
+}
+
+func (s *compressionSuite) TestJSONCodec(c *gc.C) {
+	client, server := s.dialPair(c)
+	clientCodec := jsoncodec.NewWebsocket(compression.NewConn(client, nil))
+	serverCodec := jsoncodec.NewWebsocket(compression.NewConn(server, nil))
+
+	hdr := rpc.Header{
+		RequestId: 1,
+		Request: rpc.Request{
+			Type:    "Facade",
+			Version: 1,
+			Action:  "Method",
+		},
+		Version: 1,
+	}
+	body := map[string]string{"param": strings.Repeat("value", 100)}
+	err := clientCodec.WriteMessage(&hdr, body)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var readHdr rpc.Header
+	err = serverCodec.ReadHeader(&readHdr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readHdr, jc.DeepEquals, hdr)
+	var readBody map[string]string
+	err = serverCodec.ReadBody(&readBody, true)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(readBody, jc.DeepEquals, body)
+
+	err = clientCodec.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	err = serverCodec.ReadHeader(&readHdr)
+	c.Assert(errors.Cause(err), gc.Equals, io.EOF)
+}
+
+func (s *compressionSuite) TestMessageTooLarge(c *gc.C) {
+	client, server := s.dialPair(c)
+	// A message of zeros compresses to a tiny fraction of its size.
+	message := make([]byte, compression.MaxMessageSize+1)
+	go compression.NewConn(client, nil).WriteMessage(websocket.BinaryMessage, message)
+
+	_, r, err := compression.NewConn(server, nil).NextReader()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = ioutil.ReadAll(r)
+	c.Assert(err, gc.Equals, compression.ErrMessageTooLarge)
+}
+
+func (s *compressionSuite) TestCompressedAfterLogin(c *gc.C) {
+	client, server := s.dialPair(c)
+	clientConn := compression.NewClientConn(client, nil)
+	serverConn := compression.NewServerConn(server, nil)
+
+	// Before login, the client sends its messages uncompressed and
+	// the server reads them as they are.
+	err := clientConn.WriteMessage(websocket.TextMessage, []byte("login"))
+	c.Assert(err, jc.ErrorIsNil)
+	messageType, r, err := serverConn.NextReader()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(messageType, gc.Equals, websocket.TextMessage)
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "login")
+
+	// The server compresses its messages straight away.
+	serverConn.LoggedIn()
+	err = serverConn.WriteMessage(websocket.TextMessage, []byte("welcome"))
+	c.Assert(err, jc.ErrorIsNil)
+	_, r, err = clientConn.NextReader()
+	c.Assert(err, jc.ErrorIsNil)
+	data, err = ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "welcome")
+
+	// After login, the client's messages are compressed too.
+	clientConn.LoggedIn()
+	err = clientConn.WriteMessage(websocket.TextMessage, []byte("request"))
+	c.Assert(err, jc.ErrorIsNil)
+	messageType, r, err = serverConn.NextReader()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(messageType, gc.Equals, websocket.BinaryMessage)
+	data, err = ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "request")
+}
+
+func (s *compressionSuite) TestServerRejectsCompressedBeforeLogin(c *gc.C) {
+	client, server := s.dialPair(c)
+	message := strings.Repeat("compressible ", 100)
+	err := compression.NewConn(client, nil).WriteMessage(websocket.TextMessage, []byte(message))
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The compressed message is not decompressed, so it cannot be
+	// decoded as a request.
+	_, r, err := compression.NewServerConn(server, nil).NextReader()
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Not(gc.Equals), message)
+}
+
+// dialPair returns the two ends of a websocket connection.
+func (s *compressionSuite) dialPair(c *gc.C) (client, server *websocket.Conn) {
+	serverConns := make(chan *websocket.Conn, 1)
+	var upgrader websocket.Upgrader
+	httpServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		conn, err := upgrader.Upgrade(w, req, nil)
+		c.Check(err, jc.ErrorIsNil)
+		serverConns <- conn
+	}))
+	defer httpServer.Close()
+	client, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(httpServer.URL, "http"), nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) { client.Close() })
+	server = <-serverConns
+	s.AddCleanup(func(*gc.C) { server.Close() })
+	return client, server
+}
+
+type message struct {
+	uncompressed int
+	compressed   int
+}
+
+type counter struct {
+	sent     []message
+	received []message
+}
+
+func (c *counter) Sent(uncompressed, compressed int) {
+	c.sent = append(c.sent, message{uncompressed, compressed})
+}
+
+func (c *counter) Received(uncompressed, compressed int) {
+	c.received = append(c.received, message{uncompressed, compressed})
+}
@@ -0,0 +1,123 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package compressionmetrics
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/juju/juju/rpc/compression"
+)
+
+const (
+	directionLabel = "direction"
+
+	sent     = "sent"
+	received = "received"
+)
+
+var (
+	jujuAPIWebsocketUncompressedBytesDesc = prometheus.NewDesc(
+		"juju_api_websocket_uncompressed_bytes_total",
+		"Total size of the messages on compressed API connections before compression.",
+		[]string{directionLabel},
+		prometheus.Labels{},
+	)
+	jujuAPIWebsocketCompressedBytesDesc = prometheus.NewDesc(
+		"juju_api_websocket_compressed_bytes_total",
+		"Total size of the messages on compressed API connections after compression.",
+		[]string{directionLabel},
+		prometheus.Labels{},
+	)
+	jujuAPIWebsocketCompressionSavedBytesDesc = prometheus.NewDesc(
+		"juju_api_websocket_compression_saved_bytes",
+		"Number of bytes saved by compressing messages on API connections.",
+		[]string{directionLabel},
+		prometheus.Labels{},
+	)
+)
+
+// Collector is a prometheus.Collector that collects metrics about
+// the compression of messages on API connections. It implements
+// compression.Counter so that it can be told about each message.
+type Collector struct {
+	mu     sync.Mutex
+	totals map[string]*totals
+}
+
+type totals struct {
+	uncompressed int64
+	compressed   int64
+}
+
+var _ compression.Counter = (*Collector)(nil)
+
+// New returns a new Collector.
+func New() *Collector {
+	return &Collector{
+		totals: map[string]*totals{
+			sent:     {},
+			received: {},
+		},
+	}
+}
+
+// Sent is part of the compression.Counter interface.
+func (c *Collector) Sent(uncompressed, compressed int) {
+	c.add(sent, uncompressed, compressed)
+}
+
+// Received is part of the compression.Counter interface.
+func (c *Collector) Received(uncompressed, compressed int) {
+	c.add(received, uncompressed, compressed)
+}
+
+func (c *Collector) add(direction string, uncompressed, compressed int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	t := c.totals[direction]
+	t.uncompressed += int64(uncompressed)
+	t.compressed += int64(compressed)
+}
+
+// Describe is part of the prometheus.Collector interface.
+func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- jujuAPIWebsocketUncompressedBytesDesc
+	ch <- jujuAPIWebsocketCompressedBytesDesc
+	ch <- jujuAPIWebsocketCompressionSavedBytesDesc
+}
+
+// Collect is part of the prometheus.Collector interface.
+func (c *Collector) Collect(ch chan<- prometheus.Metric) {
+	c.mu.Lock()
+	snapshot := map[string]totals{
+		sent:     *c.totals[sent],
+		received: *c.totals[received],
+	}
+	c.mu.Unlock()
+	for _, direction := range []string{sent, received} {
+		t := snapshot[direction]
+		ch <- prometheus.MustNewConstMetric(
+			jujuAPIWebsocketUncompressedBytesDesc,
+			prometheus.CounterValue,
+			float64(t.uncompressed),
+			direction,
+		)
+		ch <- prometheus.MustNewConstMetric(
+			jujuAPIWebsocketCompressedBytesDesc,
+			prometheus.CounterValue,
+			float64(t.compressed),
+			direction,
+		)
+		// Very small messages may grow when compressed, so the
+		// number of bytes saved is a gauge rather than a counter.
+		ch <- prometheus.MustNewConstMetric(
+			jujuAPIWebsocketCompressionSavedBytesDesc,
+			prometheus.GaugeValue,
+			float64(t.uncompressed-t.compressed),
+			direction,
+		)
+	}
+}
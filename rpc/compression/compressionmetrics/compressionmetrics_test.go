@@ -0,0 +1,83 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package compressionmetrics_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/rpc/compression/compressionmetrics"
+)
+
+type collectorSuite struct {
+	testing.IsolationSuite
+	collector *compressionmetrics.Collector
+}
+
+var _ = gc.Suite(&collectorSuite{})
+
+func (s *collectorSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.collector = compressionmetrics.New()
+}
+
+func (s *collectorSuite) TestDescribe(c *gc.C) {
+	ch := make(chan *prometheus.Desc)
+	go func() {
+		defer close(ch)
+		s.collector.Describe(ch)
+	}()
+	var descs []*prometheus.Desc
+	for desc := range ch {
+		descs = append(descs, desc)
+	}
+	c.Assert(descs, gc.HasLen, 3)
+	c.Assert(descs[0].String(), gc.Matches, `.*fqName: "juju_api_websocket_uncompressed_bytes_total".*`)
+	c.Assert(descs[1].String(), gc.Matches, `.*fqName: "juju_api_websocket_compressed_bytes_total".*`)
+	c.Assert(descs[2].String(), gc.Matches, `.*fqName: "juju_api_websocket_compression_saved_bytes".*`)
+}
+
+func (s *collectorSuite) TestCollect(c *gc.C) {
+	s.collector.Sent(1000, 100)
+	s.collector.Sent(500, 50)
+	s.collector.Received(10, 12)
+
+	ch := make(chan prometheus.Metric)
+	go func() {
+		defer close(ch)
+		s.collector.Collect(ch)
+	}()
+
+	var metrics []prometheus.Metric
+	for metric := range ch {
+		metrics = append(metrics, metric)
+	}
+	c.Assert(metrics, gc.HasLen, 6)
+
+	var dtoMetrics [6]dto.Metric
+	for i, metric := range metrics {
+		err := metric.Write(&dtoMetrics[i])
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	float64ptr := func(v float64) *float64 {
+		return &v
+	}
+	stringptr := func(v string) *string {
+		return &v
+	}
+	sent := []*dto.LabelPair{{Name: stringptr("direction"), Value: stringptr("sent")}}
+	received := []*dto.LabelPair{{Name: stringptr("direction"), Value: stringptr("received")}}
+	c.Assert(dtoMetrics, jc.DeepEquals, [6]dto.Metric{
+		{Label: sent, Counter: &dto.Counter{Value: float64ptr(1500)}},
+		{Label: sent, Counter: &dto.Counter{Value: float64ptr(150)}},
+		{Label: sent, Gauge: &dto.Gauge{Value: float64ptr(1350)}},
+		{Label: received, Counter: &dto.Counter{Value: float64ptr(10)}},
+		{Label: received, Counter: &dto.Counter{Value: float64ptr(12)}},
+		{Label: received, Gauge: &dto.Gauge{Value: float64ptr(-2)}},
+	})
+}
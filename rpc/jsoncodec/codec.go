@@ -140,6 +140,7 @@ func (c *Codec) ReadHeader(hdr *rpc.Header) error {
 	hdr.Error = c.msg.Error
 	hdr.ErrorCode = c.msg.ErrorCode
 	hdr.Version = version
+	hdr.Size = len(m)
 	return nil
 }
 
@@ -216,15 +217,15 @@ func (c *Codec) WriteMessage(hdr *rpc.Header, body interface{}) error {
 	if err != nil {
 		return errors.Trace(err)
 	}
-	if logger.IsTraceEnabled() {
-		data, err := json.Marshal(msg)
-		if err != nil {
-			logger.Tracef("-> marshal error: %v", err)
-			return err
-		}
-		logger.Tracef("-> %s", data)
+	// The message is marshalled here, rather than by the connection,
+	// so that its size is known.
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return errors.Trace(err)
 	}
-	return c.conn.Send(msg)
+	logger.Tracef("-> %s", data)
+	hdr.Size = len(data)
+	return c.conn.Send(json.RawMessage(data))
 }
 
 func response(hdr *rpc.Header, body interface{}) (interface{}, error) {
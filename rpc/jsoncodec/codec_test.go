@@ -123,7 +123,9 @@ func (*suite) TestRead(c *gc.C) {
 		var hdr rpc.Header
 		err := codec.ReadHeader(&hdr)
 		c.Assert(err, jc.ErrorIsNil)
-		c.Assert(hdr, gc.DeepEquals, test.expectHdr)
+		expectHdr := test.expectHdr
+		expectHdr.Size = len(test.msg)
+		c.Assert(hdr, gc.DeepEquals, expectHdr)
 
 		c.Assert(hdr.IsRequest(), gc.Equals, test.expectHdr.IsRequest())
 
@@ -243,6 +245,7 @@ func (*suite) TestWrite(c *gc.C) {
 		err := codec.WriteMessage(test.hdr, test.body)
 		c.Assert(err, jc.ErrorIsNil)
 		c.Assert(conn.writeMsgs, gc.HasLen, 1)
+		c.Assert(test.hdr.Size, gc.Equals, len(conn.writeMsgs[0]))
 
 		assertJSONEqual(c, conn.writeMsgs[0], test.expect)
 	}
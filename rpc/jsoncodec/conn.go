@@ -13,14 +13,24 @@ import (
 	"github.com/juju/errors"
 )
 
+// WebsocketConn holds the websocket connection methods used by
+// NewWebsocket. It is implemented by *websocket.Conn, and by
+// *compression.Conn for connections that compress their messages.
+type WebsocketConn interface {
+	NextWriter(messageType int) (io.WriteCloser, error)
+	NextReader() (messageType int, r io.Reader, err error)
+	WriteMessage(messageType int, data []byte) error
+	Close() error
+}
+
 // NewWebsocket returns an rpc codec that uses the given websocket
 // connection to send and receive messages.
-func NewWebsocket(conn *websocket.Conn) *Codec {
+func NewWebsocket(conn WebsocketConn) *Codec {
 	return New(&wsJSONConn{conn: conn})
 }
 
 type wsJSONConn struct {
-	conn *websocket.Conn
+	conn WebsocketConn
 	// gorilla websockets can have at most one concurrent writer, and
 	// one concurrent reader.
 	writeMutex sync.Mutex
@@ -30,7 +40,15 @@ type wsJSONConn struct {
 func (conn *wsJSONConn) Send(msg interface{}) error {
 	conn.writeMutex.Lock()
 	defer conn.writeMutex.Unlock()
-	return conn.conn.WriteJSON(msg)
+	w, err := conn.conn.NextWriter(websocket.TextMessage)
+	if err != nil {
+		return err
+	}
+	err = json.NewEncoder(w).Encode(msg)
+	if closeErr := w.Close(); err == nil {
+		err = closeErr
+	}
+	return err
 }
 
 func (conn *wsJSONConn) Receive(msg interface{}) error {
@@ -38,7 +56,7 @@ func (conn *wsJSONConn) Receive(msg interface{}) error {
 	defer conn.readMutex.Unlock()
 	// When receiving a message, if error has been closed from the other
 	// side, wrap with io.EOF as this is the expected error.
-	err := conn.conn.ReadJSON(msg)
+	err := conn.readJSON(msg)
 	if err != nil {
 		if websocket.IsCloseError(err,
 			websocket.CloseNormalClosure,
@@ -51,6 +69,19 @@ func (conn *wsJSONConn) Receive(msg interface{}) error {
 	return err
 }
 
+func (conn *wsJSONConn) readJSON(msg interface{}) error {
+	_, r, err := conn.conn.NextReader()
+	if err != nil {
+		return err
+	}
+	err = json.NewDecoder(r).Decode(msg)
+	if err == io.EOF {
+		// One value is expected in the message.
+		err = io.ErrUnexpectedEOF
+	}
+	return err
+}
+
 func (conn *wsJSONConn) Close() error {
 	// Tell the other end we are closing.
 	conn.writeMutex.Lock()
@@ -0,0 +1,265 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// The msgpackcodec package provides a MessagePack codec for the rpc
+// package.
+//
+// Messages have the same structure as version 1 messages of the
+// jsoncodec package, and bodies are encoded following the same rules
+// as encoding/json, so that a value decodes to the same Go value
+// whichever codec carried it. The codec is more compact and cheaper
+// to encode than JSON, which matters for large responses.
+package msgpackcodec
+
+import (
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+
+	"github.com/juju/juju/rpc"
+)
+
+var logger = loggo.GetLogger("juju.rpc.msgpackcodec")
+
+// WebsocketSubprotocol is the websocket subprotocol with which clients
+// and servers negotiate the use of this codec.
+const WebsocketSubprotocol = "juju-rpc-msgpack"
+
+// MsgpackConn sends and receives MessagePack-encoded messages over
+// an underlying connection.
+type MsgpackConn interface {
+	// Send sends an encoded message.
+	Send(data []byte) error
+	// Receive receives an encoded message.
+	Receive() ([]byte, error)
+	Close() error
+}
+
+// Codec implements rpc.Codec for a connection.
+type Codec struct {
+	// msg holds the message that's just been read by ReadHeader, so
+	// that the body can be read by ReadBody.
+	msg     inMsg
+	conn    MsgpackConn
+	mu      sync.Mutex
+	closing bool
+}
+
+// New returns an rpc codec that uses conn to send and receive
+// messages.
+func New(conn MsgpackConn) *Codec {
+	return &Codec{
+		conn: conn,
+	}
+}
+
+// Message keys, as used by version 1 JSON messages.
+const (
+	keyRequestId = "request-id"
+	keyType      = "type"
+	keyVersion   = "version"
+	keyId        = "id"
+	keyRequest   = "request"
+	keyParams    = "params"
+	keyError     = "error"
+	keyErrorCode = "error-code"
+	keyResponse  = "response"
+)
+
+// inMsg holds an incoming message. We don't know the type of the
+// parameters or response yet, so we delay decoding by storing their
+// encoded values.
+type inMsg struct {
+	RequestId uint64
+	Type      string
+	Version   int
+	Id        string
+	Request   string
+	Params    []byte
+	Error     string
+	ErrorCode string
+	Response  []byte
+}
+
+func (c *Codec) Close() error {
+	c.mu.Lock()
+	c.closing = true
+	c.mu.Unlock()
+	return c.conn.Close()
+}
+
+func (c *Codec) isClosing() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closing
+}
+
+func (c *Codec) ReadHeader(hdr *rpc.Header) error {
+	data, err := c.conn.Receive()
+	if err == nil {
+		logger.Tracef("<- %d bytes", len(data))
+		c.msg, err = readMessage(data)
+	} else {
+		logger.Tracef("<- error: %v (closing %v)", err, c.isClosing())
+	}
+	if err != nil {
+		// If we've closed the connection, we may get a spurious error,
+		// so ignore it.
+		if c.isClosing() || errors.Cause(err) == io.EOF {
+			return io.EOF
+		}
+		return errors.Annotate(err, "error receiving message")
+	}
+	hdr.RequestId = c.msg.RequestId
+	hdr.Request = rpc.Request{
+		Type:    c.msg.Type,
+		Version: c.msg.Version,
+		Id:      c.msg.Id,
+		Action:  c.msg.Request,
+	}
+	hdr.Error = c.msg.Error
+	hdr.ErrorCode = c.msg.ErrorCode
+	hdr.Version = 1
+	hdr.Size = len(data)
+	return nil
+}
+
+// readMessage decodes a message, leaving its params and response
+// encoded.
+func readMessage(data []byte) (inMsg, error) {
+	var msg inMsg
+	d := decoder{data: data}
+	t, err := d.readToken()
+	if err != nil {
+		return msg, errors.Trace(err)
+	}
+	if t.kind != kindMap {
+		return msg, errors.Errorf("msgpack: expected message map, got %s", t.kind)
+	}
+	for i := 0; i < t.n; i++ {
+		key, err := d.readString()
+		if err != nil {
+			return msg, errors.Trace(err)
+		}
+		switch key {
+		case keyRequestId:
+			err = d.decodeInto(&msg.RequestId)
+		case keyType:
+			err = d.decodeInto(&msg.Type)
+		case keyVersion:
+			err = d.decodeInto(&msg.Version)
+		case keyId:
+			err = d.decodeInto(&msg.Id)
+		case keyRequest:
+			err = d.decodeInto(&msg.Request)
+		case keyParams:
+			msg.Params, err = d.raw()
+		case keyError:
+			err = d.decodeInto(&msg.Error)
+		case keyErrorCode:
+			err = d.decodeInto(&msg.ErrorCode)
+		case keyResponse:
+			msg.Response, err = d.raw()
+		default:
+			err = d.skip()
+		}
+		if err != nil {
+			return msg, errors.Annotatef(err, "reading %q", key)
+		}
+	}
+	if d.off != len(data) {
+		return msg, errors.New("msgpack: unexpected data after message")
+	}
+	return msg, nil
+}
+
+func (c *Codec) ReadBody(body interface{}, isRequest bool) error {
+	if body == nil {
+		return nil
+	}
+	var rawBody []byte
+	if isRequest {
+		rawBody = c.msg.Params
+	} else {
+		rawBody = c.msg.Response
+	}
+	if len(rawBody) == 0 {
+		// If the response or params are omitted, it's
+		// equivalent to an empty object.
+		return nil
+	}
+	return Unmarshal(rawBody, body)
+}
+
+func (c *Codec) WriteMessage(hdr *rpc.Header, body interface{}) error {
+	data, err := writeMessage(hdr, body)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	logger.Tracef("-> %d bytes", len(data))
+	hdr.Size = len(data)
+	return c.conn.Send(data)
+}
+
+// writeMessage encodes a message with the given header and body,
+// omitting empty fields.
+func writeMessage(hdr *rpc.Header, body interface{}) ([]byte, error) {
+	if hdr.Version != 1 {
+		return nil, errors.Errorf("unsupported version %d", hdr.Version)
+	}
+	bodyKey := keyResponse
+	if hdr.IsRequest() {
+		bodyKey = keyParams
+	}
+	var e encoder
+	e.writeMapLen(countNonEmpty(
+		hdr.RequestId != 0,
+		hdr.Request.Type != "",
+		hdr.Request.Version != 0,
+		hdr.Request.Id != "",
+		hdr.Request.Action != "",
+		hdr.Error != "",
+		hdr.ErrorCode != "",
+		body != nil,
+	))
+	if hdr.RequestId != 0 {
+		e.writeString(keyRequestId)
+		e.writeUint(hdr.RequestId)
+	}
+	writeNonEmptyString(&e, keyType, hdr.Request.Type)
+	if hdr.Request.Version != 0 {
+		e.writeString(keyVersion)
+		e.writeInt(int64(hdr.Request.Version))
+	}
+	writeNonEmptyString(&e, keyId, hdr.Request.Id)
+	writeNonEmptyString(&e, keyRequest, hdr.Request.Action)
+	writeNonEmptyString(&e, keyError, hdr.Error)
+	writeNonEmptyString(&e, keyErrorCode, hdr.ErrorCode)
+	if body != nil {
+		e.writeString(bodyKey)
+		if err := e.encode(reflect.ValueOf(body)); err != nil {
+			return nil, errors.Trace(err)
+		}
+	}
+	return e.buf, nil
+}
+
+func countNonEmpty(nonEmpty ...bool) int {
+	n := 0
+	for _, ok := range nonEmpty {
+		if ok {
+			n++
+		}
+	}
+	return n
+}
+
+func writeNonEmptyString(e *encoder, key, value string) {
+	if value != "" {
+		e.writeString(key)
+		e.writeString(value)
+	}
+}
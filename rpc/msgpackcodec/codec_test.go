@@ -0,0 +1,223 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package msgpackcodec_test
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	stdtesting "testing"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/rpc"
+	"github.com/juju/juju/rpc/jsoncodec"
+	"github.com/juju/juju/rpc/msgpackcodec"
+)
+
+type codecSuite struct {
+	testing.LoggingSuite
+}
+
+var _ = gc.Suite(&codecSuite{})
+
+func TestPackage(t *stdtesting.T) {
+	gc.TestingT(t)
+}
+
+type value struct {
+	X string
+}
+
+func (*codecSuite) TestWriteRead(c *gc.C) {
+	for i, test := range []struct {
+		hdr  rpc.Header
+		body interface{}
+	}{{
+		hdr: rpc.Header{
+			RequestId: 1,
+			Request: rpc.Request{
+				Type:   "foo",
+				Id:     "id",
+				Action: "frob",
+			},
+			Version: 1,
+		},
+		body: &value{X: "param"},
+	}, {
+		hdr: rpc.Header{
+			RequestId: 2,
+			Error:     "an error",
+			ErrorCode: "a code",
+			Version:   1,
+		},
+		body: &map[string]interface{}{},
+	}, {
+		hdr: rpc.Header{
+			RequestId: 3,
+			Version:   1,
+		},
+		body: &value{X: "result"},
+	}, {
+		hdr: rpc.Header{
+			RequestId: 4,
+			Request: rpc.Request{
+				Type:    "foo",
+				Version: 2,
+				Action:  "frob",
+			},
+			Version: 1,
+		},
+		body: &value{X: "param"},
+	}} {
+		c.Logf("test %d", i)
+		var conn testConn
+		err := msgpackcodec.New(&conn).WriteMessage(&test.hdr, test.body)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(conn.writeMsgs, gc.HasLen, 1)
+		c.Assert(test.hdr.Size, gc.Equals, len(conn.writeMsgs[0]))
+
+		codec := msgpackcodec.New(&testConn{
+			readMsgs: conn.writeMsgs,
+		})
+		var hdr rpc.Header
+		err = codec.ReadHeader(&hdr)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(hdr, gc.DeepEquals, test.hdr)
+
+		body := reflect.New(reflect.ValueOf(test.body).Type().Elem()).Interface()
+		err = codec.ReadBody(body, hdr.IsRequest())
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(body, gc.DeepEquals, test.body)
+
+		err = codec.ReadHeader(&hdr)
+		c.Assert(err, gc.Equals, io.EOF)
+	}
+}
+
+func (*codecSuite) TestReadOmittedBody(c *gc.C) {
+	msg, err := msgpackcodec.Marshal(map[string]interface{}{
+		"request-id": 1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	codec := msgpackcodec.New(&testConn{readMsgs: [][]byte{msg}})
+	var hdr rpc.Header
+	err = codec.ReadHeader(&hdr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hdr, gc.DeepEquals, rpc.Header{RequestId: 1, Version: 1, Size: len(msg)})
+
+	body := value{X: "unchanged"}
+	err = codec.ReadBody(&body, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(body, gc.Equals, value{X: "unchanged"})
+}
+
+func (*codecSuite) TestReadIgnoresUnknownKeys(c *gc.C) {
+	msg, err := msgpackcodec.Marshal(map[string]interface{}{
+		"request-id": 5,
+		"extra":      []interface{}{"x", map[string]int{"y": 1}},
+		"response":   value{X: "result"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	codec := msgpackcodec.New(&testConn{readMsgs: [][]byte{msg}})
+	var hdr rpc.Header
+	err = codec.ReadHeader(&hdr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hdr, gc.DeepEquals, rpc.Header{RequestId: 5, Version: 1, Size: len(msg)})
+
+	var body value
+	err = codec.ReadBody(&body, false)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(body, gc.Equals, value{X: "result"})
+}
+
+func (*codecSuite) TestReadInvalidMessage(c *gc.C) {
+	msg, err := msgpackcodec.Marshal([]string{"not", "a", "message"})
+	c.Assert(err, jc.ErrorIsNil)
+	codec := msgpackcodec.New(&testConn{readMsgs: [][]byte{msg}})
+	var hdr rpc.Header
+	err = codec.ReadHeader(&hdr)
+	c.Assert(err, gc.ErrorMatches, "error receiving message: msgpack: expected message map, got array")
+}
+
+func (*codecSuite) TestWriteUnsupportedVersion(c *gc.C) {
+	var conn testConn
+	err := msgpackcodec.New(&conn).WriteMessage(&rpc.Header{RequestId: 1}, nil)
+	c.Assert(err, gc.ErrorMatches, "unsupported version 0")
+	c.Assert(conn.writeMsgs, gc.HasLen, 0)
+}
+
+func (*codecSuite) TestWriteMoreCompactThanJSON(c *gc.C) {
+	type unitStatus struct {
+		Name    string            `json:"name"`
+		Status  string            `json:"status"`
+		Ports   []int             `json:"ports"`
+		Charm   string            `json:"charm"`
+		Workers map[string]string `json:"workers"`
+	}
+	units := make([]unitStatus, 20)
+	for i := range units {
+		units[i] = unitStatus{
+			Name:    "mysql/0",
+			Status:  "active",
+			Ports:   []int{3306, 33060},
+			Charm:   "cs:xenial/mysql-57",
+			Workers: map[string]string{"uniter": "started"},
+		}
+	}
+	hdr := &rpc.Header{RequestId: 1234, Version: 1}
+	var conn testConn
+	err := msgpackcodec.New(&conn).WriteMessage(hdr, units)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conn.writeMsgs, gc.HasLen, 1)
+	c.Assert(len(conn.writeMsgs[0]) < len(jsoncodec.DumpRequest(hdr, units)), jc.IsTrue)
+}
+
+func (*codecSuite) TestErrorAfterClose(c *gc.C) {
+	conn := &testConn{
+		err: errors.New("some error"),
+	}
+	codec := msgpackcodec.New(conn)
+	var hdr rpc.Header
+	err := codec.ReadHeader(&hdr)
+	c.Assert(err, gc.ErrorMatches, "error receiving message: some error")
+
+	err = codec.Close()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(conn.closed, jc.IsTrue)
+
+	err = codec.ReadHeader(&hdr)
+	c.Assert(err, gc.Equals, io.EOF)
+}
+
+type testConn struct {
+	readMsgs  [][]byte
+	err       error
+	writeMsgs [][]byte
+	closed    bool
+}
+
+func (c *testConn) Receive() ([]byte, error) {
+	if len(c.readMsgs) > 0 {
+		msg := c.readMsgs[0]
+		c.readMsgs = c.readMsgs[1:]
+		return msg, nil
+	}
+	if c.err != nil {
+		return nil, c.err
+	}
+	return nil, io.EOF
+}
+
+func (c *testConn) Send(data []byte) error {
+	c.writeMsgs = append(c.writeMsgs, data)
+	return nil
+}
+
+func (c *testConn) Close() error {
+	c.closed = true
+	return nil
+}
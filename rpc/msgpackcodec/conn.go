@@ -0,0 +1,81 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package msgpackcodec
+
+import (
+	"io"
+	"io/ioutil"
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/juju/errors"
+)
+
+// WebsocketConn holds the websocket connection methods used by
+// NewWebsocket. It is implemented by *websocket.Conn, and by
+// *compression.Conn for connections that compress their messages.
+type WebsocketConn interface {
+	NextReader() (messageType int, r io.Reader, err error)
+	WriteMessage(messageType int, data []byte) error
+	Close() error
+}
+
+// NewWebsocket returns an rpc codec that uses the given websocket
+// connection to send and receive messages as binary frames.
+func NewWebsocket(conn WebsocketConn) *Codec {
+	return New(&wsMsgpackConn{conn: conn})
+}
+
+type wsMsgpackConn struct {
+	conn WebsocketConn
+	// gorilla websockets can have at most one concurrent writer, and
+	// one concurrent reader.
+	writeMutex sync.Mutex
+	readMutex  sync.Mutex
+}
+
+func (conn *wsMsgpackConn) Send(data []byte) error {
+	conn.writeMutex.Lock()
+	defer conn.writeMutex.Unlock()
+	return conn.conn.WriteMessage(websocket.BinaryMessage, data)
+}
+
+func (conn *wsMsgpackConn) Receive() ([]byte, error) {
+	conn.readMutex.Lock()
+	defer conn.readMutex.Unlock()
+	// When receiving a message, if error has been closed from the other
+	// side, wrap with io.EOF as this is the expected error.
+	messageType, data, err := conn.readMessage()
+	if err != nil {
+		if websocket.IsCloseError(err,
+			websocket.CloseNormalClosure,
+			websocket.CloseGoingAway,
+			websocket.CloseNoStatusReceived,
+			websocket.CloseAbnormalClosure) {
+			err = errors.Wrap(err, io.EOF)
+		}
+		return nil, err
+	}
+	if messageType != websocket.BinaryMessage {
+		return nil, errors.Errorf("unexpected websocket message type %d", messageType)
+	}
+	return data, nil
+}
+
+func (conn *wsMsgpackConn) readMessage() (int, []byte, error) {
+	messageType, r, err := conn.conn.NextReader()
+	if err != nil {
+		return messageType, nil, err
+	}
+	data, err := ioutil.ReadAll(r)
+	return messageType, data, err
+}
+
+func (conn *wsMsgpackConn) Close() error {
+	// Tell the other end we are closing.
+	conn.writeMutex.Lock()
+	conn.conn.WriteMessage(websocket.CloseMessage, []byte{})
+	conn.writeMutex.Unlock()
+	return conn.conn.Close()
+}
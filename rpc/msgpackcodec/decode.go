@@ -0,0 +1,791 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package msgpackcodec
+
+import (
+	"encoding"
+	"encoding/base64"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+var errUnexpectedEnd = errors.New("msgpack: unexpected end of data")
+
+// ArrayUnmarshaler is implemented by types that unmarshal from an
+// array whose elements can be decoded directly, rather than by going
+// through the JSON their UnmarshalJSON methods accept.
+type ArrayUnmarshaler interface {
+	// UnmarshalArray is called with the number of elements in the
+	// array, and a function that decodes the next element into the
+	// value v points to. Elements that are not decoded are skipped.
+	UnmarshalArray(n int, next func(v interface{}) error) error
+}
+
+// Unmarshal decodes the MessagePack-encoded data into the value
+// pointed to by v. It follows the rules of encoding/json, so that
+// values decode to the same Go values as they would if they had been
+// sent as JSON: in particular, numbers decoded into an empty interface
+// value become float64, binary data becomes a base64-encoded string,
+// and values that implement json.Unmarshaler are passed the JSON
+// encoding of the value. As with Marshal, time.Time values are decoded
+// directly instead, as are the elements of values that implement
+// ArrayUnmarshaler.
+func Unmarshal(data []byte, v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return errors.Errorf("msgpack: cannot unmarshal into non-pointer %T", v)
+	}
+	d := decoder{data: data}
+	if err := d.decode(rv.Elem()); err != nil {
+		return errors.Trace(err)
+	}
+	if d.off != len(d.data) {
+		return errors.New("msgpack: unexpected data after top-level value")
+	}
+	return nil
+}
+
+type kind int
+
+const (
+	kindNil kind = iota
+	kindBool
+	kindInt
+	kindUint
+	kindFloat
+	kindString
+	kindBinary
+	kindArray
+	kindMap
+	kindExt
+)
+
+func (k kind) String() string {
+	switch k {
+	case kindNil:
+		return "nil"
+	case kindBool:
+		return "bool"
+	case kindInt, kindUint, kindFloat:
+		return "number"
+	case kindString:
+		return "string"
+	case kindBinary:
+		return "binary"
+	case kindArray:
+		return "array"
+	case kindMap:
+		return "map"
+	}
+	return "extension"
+}
+
+// token holds the type code of an encoded value, and its value if it
+// is a scalar or its length if it is not.
+type token struct {
+	kind kind
+	b    bool
+	i    int64
+	u    uint64
+	f    float64
+	n    int
+}
+
+// int64 returns the token's value as an int64, if it is an integer
+// that fits.
+func (t token) int64() (int64, bool) {
+	switch t.kind {
+	case kindInt:
+		return t.i, true
+	case kindUint:
+		return int64(t.u), t.u <= math.MaxInt64
+	case kindFloat:
+		ok := t.f == math.Trunc(t.f) && t.f >= math.MinInt64 && t.f < math.MaxInt64
+		return int64(t.f), ok
+	}
+	return 0, false
+}
+
+// uint64 returns the token's value as a uint64, if it is a
+// non-negative integer that fits.
+func (t token) uint64() (uint64, bool) {
+	switch t.kind {
+	case kindInt:
+		return uint64(t.i), t.i >= 0
+	case kindUint:
+		return t.u, true
+	case kindFloat:
+		ok := t.f == math.Trunc(t.f) && t.f >= 0 && t.f < math.MaxUint64
+		return uint64(t.f), ok
+	}
+	return 0, false
+}
+
+func (t token) float64() float64 {
+	switch t.kind {
+	case kindInt:
+		return float64(t.i)
+	case kindUint:
+		return float64(t.u)
+	}
+	return t.f
+}
+
+func (t token) number() json.Number {
+	switch t.kind {
+	case kindInt:
+		return json.Number(strconv.FormatInt(t.i, 10))
+	case kindUint:
+		return json.Number(strconv.FormatUint(t.u, 10))
+	}
+	return json.Number(strconv.FormatFloat(t.f, 'g', -1, 64))
+}
+
+type decoder struct {
+	data []byte
+	off  int
+}
+
+func (d *decoder) remaining() int {
+	return len(d.data) - d.off
+}
+
+func (d *decoder) readByte() (byte, error) {
+	if d.off >= len(d.data) {
+		return 0, errUnexpectedEnd
+	}
+	b := d.data[d.off]
+	d.off++
+	return b, nil
+}
+
+func (d *decoder) readBytes(n int) ([]byte, error) {
+	if n > d.remaining() {
+		return nil, errUnexpectedEnd
+	}
+	b := d.data[d.off : d.off+n]
+	d.off += n
+	return b, nil
+}
+
+// readUint reads a big-endian unsigned integer of the given size.
+func (d *decoder) readUint(size int) (uint64, error) {
+	b, err := d.readBytes(size)
+	if err != nil {
+		return 0, err
+	}
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u, nil
+}
+
+// readLen reads a length of the given size, checking that there are
+// at least min bytes remaining for each of the elements it counts.
+func (d *decoder) readLen(size, min int) (int, error) {
+	u, err := d.readUint(size)
+	if err != nil {
+		return 0, err
+	}
+	if u > uint64(d.remaining()/min) {
+		return 0, errUnexpectedEnd
+	}
+	return int(u), nil
+}
+
+// readToken reads the type code of the next value, and the value
+// itself if it is a scalar. The contents of strings, binary data
+// and extensions, and the elements of arrays and maps, are left to
+// be read.
+func (d *decoder) readToken() (token, error) {
+	code, err := d.readByte()
+	if err != nil {
+		return token{}, err
+	}
+	switch {
+	case code <= mpPosFixintMax:
+		return token{kind: kindUint, u: uint64(code)}, nil
+	case code >= mpNegFixintMin:
+		return token{kind: kindInt, i: int64(int8(code))}, nil
+	case code&0xf0 == mpFixmap:
+		return d.lenToken(kindMap, int(code&0x0f), 2)
+	case code&0xf0 == mpFixarray:
+		return d.lenToken(kindArray, int(code&0x0f), 1)
+	case code&0xe0 == mpFixstr:
+		return d.lenToken(kindString, int(code&0x1f), 1)
+	}
+
+	var t token
+	switch code {
+	case mpNil:
+		t.kind = kindNil
+	case mpFalse, mpTrue:
+		t.kind = kindBool
+		t.b = code == mpTrue
+	case mpBin8, mpBin16, mpBin32:
+		t.kind = kindBinary
+		t.n, err = d.readLen(1<<(code-mpBin8), 1)
+	case mpStr8, mpStr16, mpStr32:
+		t.kind = kindString
+		t.n, err = d.readLen(1<<(code-mpStr8), 1)
+	case mpArray16, mpArray32:
+		t.kind = kindArray
+		t.n, err = d.readLen(2<<(code-mpArray16), 1)
+	case mpMap16, mpMap32:
+		t.kind = kindMap
+		t.n, err = d.readLen(2<<(code-mpMap16), 2)
+	case mpExt8, mpExt16, mpExt32:
+		t.kind = kindExt
+		t.n, err = d.readLen(1<<(code-mpExt8), 1)
+	case mpFixext1, mpFixext2, mpFixext4, mpFixext8, mpFixext16:
+		t.kind = kindExt
+		t.n = 1 << (code - mpFixext1)
+	case mpFloat32:
+		var u uint64
+		u, err = d.readUint(4)
+		t.kind = kindFloat
+		t.f = float64(math.Float32frombits(uint32(u)))
+	case mpFloat64:
+		var u uint64
+		u, err = d.readUint(8)
+		t.kind = kindFloat
+		t.f = math.Float64frombits(u)
+	case mpUint8, mpUint16, mpUint32, mpUint64:
+		t.kind = kindUint
+		t.u, err = d.readUint(1 << (code - mpUint8))
+	case mpInt8, mpInt16, mpInt32, mpInt64:
+		size := 1 << (code - mpInt8)
+		var u uint64
+		u, err = d.readUint(size)
+		t.kind = kindInt
+		// Sign-extend the value read.
+		shift := uint(64 - 8*size)
+		t.i = int64(u<<shift) >> shift
+	default:
+		return token{}, errors.Errorf("msgpack: invalid type code 0x%x", code)
+	}
+	if err != nil {
+		return token{}, err
+	}
+	if t.kind == kindExt && t.n+1 > d.remaining() {
+		return token{}, errUnexpectedEnd
+	}
+	return t, nil
+}
+
+func (d *decoder) lenToken(k kind, n, min int) (token, error) {
+	if n > d.remaining()/min {
+		return token{}, errUnexpectedEnd
+	}
+	return token{kind: k, n: n}, nil
+}
+
+// skip skips over the next value.
+func (d *decoder) skip() error {
+	for n := 1; n > 0; n-- {
+		t, err := d.readToken()
+		if err != nil {
+			return err
+		}
+		switch t.kind {
+		case kindString, kindBinary:
+			d.off += t.n
+		case kindExt:
+			d.off += t.n + 1
+		case kindArray:
+			n += t.n
+		case kindMap:
+			n += 2 * t.n
+		}
+	}
+	return nil
+}
+
+// raw returns the encoding of the next value.
+func (d *decoder) raw() ([]byte, error) {
+	start := d.off
+	if err := d.skip(); err != nil {
+		return nil, err
+	}
+	return d.data[start:d.off], nil
+}
+
+// readString reads the next value, which must be a string.
+func (d *decoder) readString() (string, error) {
+	t, err := d.readToken()
+	if err != nil {
+		return "", err
+	}
+	if t.kind != kindString {
+		return "", errors.Errorf("msgpack: expected string, got %s", t.kind)
+	}
+	b, err := d.readBytes(t.n)
+	return string(b), err
+}
+
+// readKey reads a map key. Keys are encoded as strings, but integer
+// keys are also accepted.
+func (d *decoder) readKey() (string, error) {
+	t, err := d.readToken()
+	if err != nil {
+		return "", err
+	}
+	switch t.kind {
+	case kindString:
+		b, err := d.readBytes(t.n)
+		return string(b), err
+	case kindInt, kindUint:
+		return t.number().String(), nil
+	}
+	return "", errors.Errorf("msgpack: unsupported map key type %s", t.kind)
+}
+
+func (d *decoder) decode(v reflect.Value) error {
+	if d.off < len(d.data) && d.data[d.off] == mpNil {
+		d.off++
+		return decodeNil(v)
+	}
+	u, v := indirect(v)
+	switch u := u.(type) {
+	case *time.Time:
+		return d.decodeTime(u)
+	case ArrayUnmarshaler:
+		return d.decodeArrayUnmarshaler(u, v.Type().Elem())
+	}
+	if ju, ok := u.(json.Unmarshaler); ok {
+		value, err := d.decodeGeneric(true)
+		if err != nil {
+			return err
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return ju.UnmarshalJSON(data)
+	}
+	t, err := d.readToken()
+	if err != nil {
+		return err
+	}
+	if tu, ok := u.(encoding.TextUnmarshaler); ok {
+		if t.kind != kindString {
+			return typeError(t, v.Type())
+		}
+		b, err := d.readBytes(t.n)
+		if err != nil {
+			return err
+		}
+		return tu.UnmarshalText(b)
+	}
+	switch t.kind {
+	case kindBool:
+		switch v.Kind() {
+		case reflect.Bool:
+			v.SetBool(t.b)
+			return nil
+		case reflect.Interface:
+			return setInterface(v, t, t.b)
+		}
+	case kindInt, kindUint, kindFloat:
+		return decodeNumber(t, v)
+	case kindString:
+		b, err := d.readBytes(t.n)
+		if err != nil {
+			return err
+		}
+		switch v.Kind() {
+		case reflect.String:
+			v.SetString(string(b))
+			return nil
+		case reflect.Slice:
+			if v.Type().Elem().Kind() != reflect.Uint8 {
+				break
+			}
+			decoded := make([]byte, base64.StdEncoding.DecodedLen(len(b)))
+			n, err := base64.StdEncoding.Decode(decoded, b)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			v.SetBytes(decoded[:n])
+			return nil
+		case reflect.Interface:
+			return setInterface(v, t, string(b))
+		}
+	case kindBinary:
+		b, err := d.readBytes(t.n)
+		if err != nil {
+			return err
+		}
+		switch v.Kind() {
+		case reflect.Slice:
+			if v.Type().Elem().Kind() != reflect.Uint8 {
+				break
+			}
+			v.SetBytes(append([]byte(nil), b...))
+			return nil
+		case reflect.Interface:
+			return setInterface(v, t, base64.StdEncoding.EncodeToString(b))
+		}
+	case kindArray:
+		return d.decodeArray(t, v)
+	case kindMap:
+		return d.decodeMap(t, v)
+	}
+	return typeError(t, v.Type())
+}
+
+// decodeTime decodes an RFC 3339 string into *t, as its UnmarshalJSON
+// method would.
+func (d *decoder) decodeTime(t *time.Time) error {
+	s, err := d.readString()
+	if err != nil {
+		return err
+	}
+	parsed, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	*t = parsed
+	return nil
+}
+
+// decodeArrayUnmarshaler decodes an array by passing its elements to
+// the UnmarshalArray method of u, which unmarshals a value of type typ.
+func (d *decoder) decodeArrayUnmarshaler(u ArrayUnmarshaler, typ reflect.Type) error {
+	t, err := d.readToken()
+	if err != nil {
+		return err
+	}
+	if t.kind != kindArray {
+		return typeError(t, typ)
+	}
+	decoded := 0
+	next := func(v interface{}) error {
+		if decoded == t.n {
+			return errors.Errorf("msgpack: no more elements in array for %s", typ)
+		}
+		decoded++
+		return d.decodeInto(v)
+	}
+	if err := u.UnmarshalArray(t.n, next); err != nil {
+		return err
+	}
+	for ; decoded < t.n; decoded++ {
+		if err := d.skip(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// decodeInto decodes the next value into the value pointed to by v.
+func (d *decoder) decodeInto(v interface{}) error {
+	return d.decode(reflect.ValueOf(v).Elem())
+}
+
+// decodeNil decodes a nil value into v. As with encoding/json, it sets
+// pointers, interfaces, maps and slices to nil, passes "null" to
+// a json.Unmarshaler, and otherwise leaves v unchanged.
+func decodeNil(v reflect.Value) error {
+	if v.Kind() != reflect.Ptr && v.CanAddr() && reflect.PtrTo(v.Type()).Implements(jsonUnmarshalerType) {
+		return v.Addr().Interface().(json.Unmarshaler).UnmarshalJSON([]byte("null"))
+	}
+	switch v.Kind() {
+	case reflect.Interface, reflect.Ptr, reflect.Map, reflect.Slice:
+		v.Set(reflect.Zero(v.Type()))
+	}
+	return nil
+}
+
+// indirect walks down v, allocating pointers as needed, until it gets
+// to a non-pointer. If it encounters an ArrayUnmarshaler, a
+// json.Unmarshaler or an encoding.TextUnmarshaler on the way, it stops
+// and returns it.
+func indirect(v reflect.Value) (interface{}, reflect.Value) {
+	if v.Kind() != reflect.Ptr && v.Type().Name() != "" && v.CanAddr() {
+		v = v.Addr()
+	}
+	for {
+		if v.Kind() == reflect.Interface && !v.IsNil() {
+			e := v.Elem()
+			if e.Kind() == reflect.Ptr && !e.IsNil() {
+				v = e
+				continue
+			}
+		}
+		if v.Kind() != reflect.Ptr {
+			break
+		}
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
+		}
+		if v.Type().NumMethod() > 0 {
+			switch u := v.Interface().(type) {
+			case ArrayUnmarshaler, json.Unmarshaler, encoding.TextUnmarshaler:
+				return u, v
+			}
+		}
+		v = v.Elem()
+	}
+	return nil, v
+}
+
+func setInterface(v reflect.Value, t token, value interface{}) error {
+	if v.NumMethod() != 0 {
+		return typeError(t, v.Type())
+	}
+	v.Set(reflect.ValueOf(value))
+	return nil
+}
+
+func typeError(t token, typ reflect.Type) error {
+	return errors.Errorf("msgpack: cannot unmarshal %s into Go value of type %s", t.kind, typ)
+}
+
+func decodeNumber(t token, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, ok := t.int64()
+		if !ok || v.OverflowInt(i) {
+			return errors.Errorf("msgpack: cannot unmarshal number %s into Go value of type %s", t.number(), v.Type())
+		}
+		v.SetInt(i)
+		return nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, ok := t.uint64()
+		if !ok || v.OverflowUint(u) {
+			return errors.Errorf("msgpack: cannot unmarshal number %s into Go value of type %s", t.number(), v.Type())
+		}
+		v.SetUint(u)
+		return nil
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(t.float64())
+		return nil
+	case reflect.String:
+		if v.Type() == numberType {
+			v.SetString(t.number().String())
+			return nil
+		}
+	case reflect.Interface:
+		return setInterface(v, t, t.float64())
+	}
+	return typeError(t, v.Type())
+}
+
+func (d *decoder) decodeArray(t token, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			break
+		}
+		value, err := d.decodeGenericArray(t, false)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(value))
+		return nil
+	case reflect.Slice:
+		s := reflect.MakeSlice(v.Type(), t.n, t.n)
+		for i := 0; i < t.n; i++ {
+			if err := d.decode(s.Index(i)); err != nil {
+				return err
+			}
+		}
+		v.Set(s)
+		return nil
+	case reflect.Array:
+		for i := 0; i < t.n; i++ {
+			var err error
+			if i < v.Len() {
+				err = d.decode(v.Index(i))
+			} else {
+				err = d.skip()
+			}
+			if err != nil {
+				return err
+			}
+		}
+		zero := reflect.Zero(v.Type().Elem())
+		for i := t.n; i < v.Len(); i++ {
+			v.Index(i).Set(zero)
+		}
+		return nil
+	}
+	return typeError(t, v.Type())
+}
+
+func (d *decoder) decodeMap(t token, v reflect.Value) error {
+	switch v.Kind() {
+	case reflect.Interface:
+		if v.NumMethod() != 0 {
+			break
+		}
+		value, err := d.decodeGenericMap(t, false)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(value))
+		return nil
+	case reflect.Map:
+		if v.IsNil() {
+			v.Set(reflect.MakeMap(v.Type()))
+		}
+		keyType := v.Type().Key()
+		elemType := v.Type().Elem()
+		for i := 0; i < t.n; i++ {
+			key, err := d.readKey()
+			if err != nil {
+				return err
+			}
+			kv, err := convertKey(key, keyType)
+			if err != nil {
+				return err
+			}
+			ev := reflect.New(elemType).Elem()
+			if err := d.decode(ev); err != nil {
+				return err
+			}
+			v.SetMapIndex(kv, ev)
+		}
+		return nil
+	case reflect.Struct:
+		fields := cachedTypeFields(v.Type())
+		for i := 0; i < t.n; i++ {
+			key, err := d.readKey()
+			if err != nil {
+				return err
+			}
+			var fv reflect.Value
+			if f, ok := fields.lookup(key); ok {
+				fv = allocFieldByIndex(v, f.index)
+			}
+			if !fv.IsValid() {
+				err = d.skip()
+			} else {
+				err = d.decode(fv)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return typeError(t, v.Type())
+}
+
+// allocFieldByIndex returns the field of v with the given index,
+// allocating any nil embedded struct pointers on the way. It returns
+// the zero Value if it cannot allocate one.
+func allocFieldByIndex(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				if !v.CanSet() {
+					return reflect.Value{}
+				}
+				v.Set(reflect.New(v.Type().Elem()))
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// convertKey converts a map key to the given key type, as
+// encoding/json does.
+func convertKey(key string, keyType reflect.Type) (reflect.Value, error) {
+	if reflect.PtrTo(keyType).Implements(textUnmarshalerType) {
+		kv := reflect.New(keyType)
+		if err := kv.Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(key)); err != nil {
+			return reflect.Value{}, errors.Trace(err)
+		}
+		return kv.Elem(), nil
+	}
+	kv := reflect.New(keyType).Elem()
+	switch keyType.Kind() {
+	case reflect.String:
+		kv.SetString(key)
+		return kv, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i, err := strconv.ParseInt(key, 10, 64)
+		if err != nil || kv.OverflowInt(i) {
+			break
+		}
+		kv.SetInt(i)
+		return kv, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u, err := strconv.ParseUint(key, 10, 64)
+		if err != nil || kv.OverflowUint(u) {
+			break
+		}
+		kv.SetUint(u)
+		return kv, nil
+	}
+	return reflect.Value{}, errors.Errorf("msgpack: cannot unmarshal map key %q into Go value of type %s", key, keyType)
+}
+
+// decodeGeneric decodes the next value into the Go value that
+// encoding/json would produce when decoding into an empty interface
+// value. If useNumber is true, numbers are decoded as json.Number
+// rather than float64, so that no precision is lost.
+func (d *decoder) decodeGeneric(useNumber bool) (interface{}, error) {
+	t, err := d.readToken()
+	if err != nil {
+		return nil, err
+	}
+	switch t.kind {
+	case kindNil:
+		return nil, nil
+	case kindBool:
+		return t.b, nil
+	case kindInt, kindUint, kindFloat:
+		if useNumber {
+			return t.number(), nil
+		}
+		return t.float64(), nil
+	case kindString:
+		b, err := d.readBytes(t.n)
+		return string(b), err
+	case kindBinary:
+		b, err := d.readBytes(t.n)
+		return base64.StdEncoding.EncodeToString(b), err
+	case kindArray:
+		return d.decodeGenericArray(t, useNumber)
+	case kindMap:
+		return d.decodeGenericMap(t, useNumber)
+	}
+	return nil, errors.Errorf("msgpack: unsupported %s value", t.kind)
+}
+
+func (d *decoder) decodeGenericArray(t token, useNumber bool) ([]interface{}, error) {
+	a := make([]interface{}, t.n)
+	for i := range a {
+		var err error
+		if a[i], err = d.decodeGeneric(useNumber); err != nil {
+			return nil, err
+		}
+	}
+	return a, nil
+}
+
+func (d *decoder) decodeGenericMap(t token, useNumber bool) (map[string]interface{}, error) {
+	m := make(map[string]interface{}, t.n)
+	for i := 0; i < t.n; i++ {
+		key, err := d.readKey()
+		if err != nil {
+			return nil, err
+		}
+		if m[key], err = d.decodeGeneric(useNumber); err != nil {
+			return nil, err
+		}
+	}
+	return m, nil
+}
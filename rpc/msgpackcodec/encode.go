@@ -0,0 +1,487 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package msgpackcodec
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// ArrayMarshaler is implemented by types that marshal to an array
+// whose elements can be encoded directly, rather than by going
+// through the JSON their MarshalJSON methods produce.
+type ArrayMarshaler interface {
+	// MarshalArray returns the elements of the array.
+	MarshalArray() ([]interface{}, error)
+}
+
+var (
+	arrayMarshalerType  = reflect.TypeOf((*ArrayMarshaler)(nil)).Elem()
+	jsonMarshalerType   = reflect.TypeOf((*json.Marshaler)(nil)).Elem()
+	textMarshalerType   = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	jsonUnmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	numberType          = reflect.TypeOf(json.Number(""))
+	timeType            = reflect.TypeOf(time.Time{})
+)
+
+// Marshal returns the MessagePack encoding of v. Values are encoded
+// as encoding/json would encode them, but using the corresponding
+// MessagePack types: structs become maps keyed by their json field
+// names, byte slices become binary data, and values that implement
+// json.Marshaler or encoding.TextMarshaler are encoded from the JSON
+// or text they marshal to. The exceptions are time.Time, which is
+// common enough in API responses that it is encoded directly into the
+// form its MarshalJSON method would produce, and values that implement
+// ArrayMarshaler, whose elements are encoded directly.
+func Marshal(v interface{}) ([]byte, error) {
+	var e encoder
+	if err := e.encode(reflect.ValueOf(v)); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return e.buf, nil
+}
+
+type encoder struct {
+	buf []byte
+}
+
+func (e *encoder) encode(v reflect.Value) error {
+	if !v.IsValid() {
+		e.writeNil()
+		return nil
+	}
+	t := v.Type()
+	if t.Kind() == reflect.Ptr && t.Elem() == timeType {
+		if v.IsNil() {
+			e.writeNil()
+			return nil
+		}
+		v, t = v.Elem(), t.Elem()
+	}
+	if t == timeType {
+		return e.encodeTime(v.Interface().(time.Time))
+	}
+	if t.Implements(arrayMarshalerType) {
+		return e.encodeArrayMarshaler(v)
+	}
+	if t.Kind() != reflect.Ptr && v.CanAddr() && reflect.PtrTo(t).Implements(arrayMarshalerType) {
+		return e.encodeArrayMarshaler(v.Addr())
+	}
+	if t.Implements(jsonMarshalerType) {
+		return e.encodeJSONMarshaler(v)
+	}
+	if t.Kind() != reflect.Ptr && v.CanAddr() && reflect.PtrTo(t).Implements(jsonMarshalerType) {
+		return e.encodeJSONMarshaler(v.Addr())
+	}
+	if t.Implements(textMarshalerType) {
+		return e.encodeTextMarshaler(v)
+	}
+	if t.Kind() != reflect.Ptr && v.CanAddr() && reflect.PtrTo(t).Implements(textMarshalerType) {
+		return e.encodeTextMarshaler(v.Addr())
+	}
+	if t == numberType {
+		return e.encodeNumber(json.Number(v.String()))
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		e.writeBool(v.Bool())
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.writeInt(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.writeUint(v.Uint())
+	case reflect.Float32:
+		e.writeFloat32(float32(v.Float()))
+	case reflect.Float64:
+		e.writeFloat64(v.Float())
+	case reflect.String:
+		e.writeString(v.String())
+	case reflect.Slice:
+		if v.IsNil() {
+			e.writeNil()
+			return nil
+		}
+		if t.Elem().Kind() == reflect.Uint8 {
+			e.writeBinary(v.Bytes())
+			return nil
+		}
+		return e.encodeArray(v)
+	case reflect.Array:
+		return e.encodeArray(v)
+	case reflect.Map:
+		return e.encodeMap(v)
+	case reflect.Struct:
+		return e.encodeStruct(v)
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			e.writeNil()
+			return nil
+		}
+		return e.encode(v.Elem())
+	default:
+		return errors.Errorf("msgpack: unsupported type: %s", t)
+	}
+	return nil
+}
+
+// encodeTime encodes t as the RFC 3339 string that its MarshalJSON
+// method would produce.
+func (e *encoder) encodeTime(t time.Time) error {
+	if y := t.Year(); y < 0 || y >= 10000 {
+		return errors.Errorf("msgpack: time %v has year outside of range [0,9999]", t)
+	}
+	var buf [len(time.RFC3339Nano)]byte
+	e.writeStringBytes(t.AppendFormat(buf[:0], time.RFC3339Nano))
+	return nil
+}
+
+// encodeArrayMarshaler encodes the elements returned by the
+// MarshalArray method of v.
+func (e *encoder) encodeArrayMarshaler(v reflect.Value) error {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		e.writeNil()
+		return nil
+	}
+	elems, err := v.Interface().(ArrayMarshaler).MarshalArray()
+	if err != nil {
+		return errors.Annotatef(err, "msgpack: error calling MarshalArray for type %s", v.Type())
+	}
+	e.writeArrayLen(len(elems))
+	for _, elem := range elems {
+		if err := e.encode(reflect.ValueOf(elem)); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func (e *encoder) encodeJSONMarshaler(v reflect.Value) error {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		e.writeNil()
+		return nil
+	}
+	data, err := v.Interface().(json.Marshaler).MarshalJSON()
+	if err != nil {
+		return errors.Annotatef(err, "msgpack: error calling MarshalJSON for type %s", v.Type())
+	}
+	return e.encodeJSON(data)
+}
+
+// encodeJSON encodes the value represented by the given JSON data.
+func (e *encoder) encodeJSON(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var value interface{}
+	if err := dec.Decode(&value); err != nil {
+		return errors.Trace(err)
+	}
+	return e.encodeGeneric(value)
+}
+
+// encodeGeneric encodes a value produced by decoding JSON into an
+// empty interface value, without resorting to reflection.
+func (e *encoder) encodeGeneric(value interface{}) error {
+	switch value := value.(type) {
+	case nil:
+		e.writeNil()
+	case bool:
+		e.writeBool(value)
+	case json.Number:
+		return e.encodeNumber(value)
+	case float64:
+		e.writeFloat64(value)
+	case string:
+		e.writeString(value)
+	case []interface{}:
+		e.writeArrayLen(len(value))
+		for _, elem := range value {
+			if err := e.encodeGeneric(elem); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	case map[string]interface{}:
+		e.writeMapLen(len(value))
+		for key, elem := range value {
+			e.writeString(key)
+			if err := e.encodeGeneric(elem); err != nil {
+				return errors.Trace(err)
+			}
+		}
+	default:
+		return e.encode(reflect.ValueOf(value))
+	}
+	return nil
+}
+
+// encodeNumber encodes a JSON number as an integer if it is one,
+// and as a float otherwise.
+func (e *encoder) encodeNumber(n json.Number) error {
+	if n == "" {
+		n = "0"
+	}
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		e.writeInt(i)
+		return nil
+	}
+	if u, err := strconv.ParseUint(string(n), 10, 64); err == nil {
+		e.writeUint(u)
+		return nil
+	}
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil {
+		return errors.Errorf("msgpack: invalid number literal %q", n)
+	}
+	e.writeFloat64(f)
+	return nil
+}
+
+func (e *encoder) encodeTextMarshaler(v reflect.Value) error {
+	if (v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface) && v.IsNil() {
+		e.writeNil()
+		return nil
+	}
+	text, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return errors.Annotatef(err, "msgpack: error calling MarshalText for type %s", v.Type())
+	}
+	e.writeStringBytes(text)
+	return nil
+}
+
+func (e *encoder) encodeArray(v reflect.Value) error {
+	n := v.Len()
+	e.writeArrayLen(n)
+	for i := 0; i < n; i++ {
+		if err := e.encode(v.Index(i)); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+func (e *encoder) encodeMap(v reflect.Value) error {
+	if v.IsNil() {
+		e.writeNil()
+		return nil
+	}
+	e.writeMapLen(v.Len())
+	for _, key := range v.MapKeys() {
+		if err := e.encodeMapKey(key); err != nil {
+			return errors.Trace(err)
+		}
+		if err := e.encode(v.MapIndex(key)); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// encodeMapKey encodes a map key as a string, as encoding/json does.
+func (e *encoder) encodeMapKey(key reflect.Value) error {
+	if key.Kind() == reflect.String {
+		e.writeString(key.String())
+		return nil
+	}
+	if key.Type().Implements(textMarshalerType) {
+		return e.encodeTextMarshaler(key)
+	}
+	switch key.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		e.writeString(strconv.FormatInt(key.Int(), 10))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		e.writeString(strconv.FormatUint(key.Uint(), 10))
+	default:
+		return errors.Errorf("msgpack: unsupported map key type: %s", key.Type())
+	}
+	return nil
+}
+
+func (e *encoder) encodeStruct(v reflect.Value) error {
+	fields := cachedTypeFields(v.Type()).list
+	values := make([]reflect.Value, len(fields))
+	n := 0
+	for i := range fields {
+		fv, ok := fieldByIndex(v, fields[i].index)
+		if !ok || fields[i].omitEmpty && isEmptyValue(fv) {
+			continue
+		}
+		values[i] = fv
+		n++
+	}
+	e.writeMapLen(n)
+	for i, fv := range values {
+		if !fv.IsValid() {
+			continue
+		}
+		e.writeString(fields[i].name)
+		if err := e.encode(fv); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// fieldByIndex returns the field of v with the given index. It
+// returns false if the field is in an embedded struct reached through
+// a nil pointer.
+func fieldByIndex(v reflect.Value, index []int) (reflect.Value, bool) {
+	for i, x := range index {
+		if i > 0 && v.Kind() == reflect.Ptr {
+			if v.IsNil() {
+				return reflect.Value{}, false
+			}
+			v = v.Elem()
+		}
+		v = v.Field(x)
+	}
+	return v, true
+}
+
+func isEmptyValue(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Array, reflect.Map, reflect.Slice, reflect.String:
+		return v.Len() == 0
+	case reflect.Bool:
+		return !v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return v.Int() == 0
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return v.Uint() == 0
+	case reflect.Float32, reflect.Float64:
+		return v.Float() == 0
+	case reflect.Interface, reflect.Ptr:
+		return v.IsNil()
+	}
+	return false
+}
+
+func (e *encoder) writeNil() {
+	e.buf = append(e.buf, mpNil)
+}
+
+func (e *encoder) writeBool(b bool) {
+	if b {
+		e.buf = append(e.buf, mpTrue)
+	} else {
+		e.buf = append(e.buf, mpFalse)
+	}
+}
+
+func (e *encoder) writeInt(i int64) {
+	switch {
+	case i >= 0:
+		e.writeUint(uint64(i))
+	case i >= -32:
+		e.buf = append(e.buf, byte(i))
+	case i >= math.MinInt8:
+		e.buf = append(e.buf, mpInt8, byte(i))
+	case i >= math.MinInt16:
+		e.writeSized(mpInt16, uint64(i), 2)
+	case i >= math.MinInt32:
+		e.writeSized(mpInt32, uint64(i), 4)
+	default:
+		e.writeSized(mpInt64, uint64(i), 8)
+	}
+}
+
+func (e *encoder) writeUint(u uint64) {
+	switch {
+	case u <= mpPosFixintMax:
+		e.buf = append(e.buf, byte(u))
+	case u <= math.MaxUint8:
+		e.buf = append(e.buf, mpUint8, byte(u))
+	case u <= math.MaxUint16:
+		e.writeSized(mpUint16, u, 2)
+	case u <= math.MaxUint32:
+		e.writeSized(mpUint32, u, 4)
+	default:
+		e.writeSized(mpUint64, u, 8)
+	}
+}
+
+func (e *encoder) writeFloat32(f float32) {
+	e.writeSized(mpFloat32, uint64(math.Float32bits(f)), 4)
+}
+
+func (e *encoder) writeFloat64(f float64) {
+	e.writeSized(mpFloat64, math.Float64bits(f), 8)
+}
+
+func (e *encoder) writeString(s string) {
+	e.writeStringLen(len(s))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) writeStringBytes(s []byte) {
+	e.writeStringLen(len(s))
+	e.buf = append(e.buf, s...)
+}
+
+func (e *encoder) writeStringLen(n int) {
+	switch {
+	case n <= mpFixstrMaxLen:
+		e.buf = append(e.buf, mpFixstr|byte(n))
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, mpStr8, byte(n))
+	case n <= math.MaxUint16:
+		e.writeSized(mpStr16, uint64(n), 2)
+	default:
+		e.writeSized(mpStr32, uint64(n), 4)
+	}
+}
+
+func (e *encoder) writeBinary(b []byte) {
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		e.buf = append(e.buf, mpBin8, byte(n))
+	case n <= math.MaxUint16:
+		e.writeSized(mpBin16, uint64(n), 2)
+	default:
+		e.writeSized(mpBin32, uint64(n), 4)
+	}
+	e.buf = append(e.buf, b...)
+}
+
+func (e *encoder) writeArrayLen(n int) {
+	switch {
+	case n <= mpFixCollectionMaxLen:
+		e.buf = append(e.buf, mpFixarray|byte(n))
+	case n <= math.MaxUint16:
+		e.writeSized(mpArray16, uint64(n), 2)
+	default:
+		e.writeSized(mpArray32, uint64(n), 4)
+	}
+}
+
+func (e *encoder) writeMapLen(n int) {
+	switch {
+	case n <= mpFixCollectionMaxLen:
+		e.buf = append(e.buf, mpFixmap|byte(n))
+	case n <= math.MaxUint16:
+		e.writeSized(mpMap16, uint64(n), 2)
+	default:
+		e.writeSized(mpMap32, uint64(n), 4)
+	}
+}
+
+// writeSized writes the given type code followed by the low size
+// bytes of u, in big-endian order.
+func (e *encoder) writeSized(code byte, u uint64, size int) {
+	e.buf = append(e.buf, code)
+	for shift := uint(size-1) * 8; ; shift -= 8 {
+		e.buf = append(e.buf, byte(u>>shift))
+		if shift == 0 {
+			break
+		}
+	}
+}
@@ -0,0 +1,201 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package msgpackcodec
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// field describes a struct field as it is encoded. The names and
+// options are taken from the field's json tag, so that values encode
+// with the same keys as they do with encoding/json.
+type field struct {
+	name      string
+	index     []int
+	omitEmpty bool
+	tagged    bool
+}
+
+// structFields holds the encoded fields of a struct type, in field
+// order.
+type structFields struct {
+	list   []field
+	byName map[string]int
+}
+
+// lookup returns the field with the given name, preferring an exact
+// match but otherwise accepting a case-insensitive one, as
+// encoding/json does.
+func (f *structFields) lookup(name string) (*field, bool) {
+	if i, ok := f.byName[name]; ok {
+		return &f.list[i], true
+	}
+	for i := range f.list {
+		if strings.EqualFold(f.list[i].name, name) {
+			return &f.list[i], true
+		}
+	}
+	return nil, false
+}
+
+var fieldCache struct {
+	mu     sync.RWMutex
+	fields map[reflect.Type]*structFields
+}
+
+// cachedTypeFields returns the encoded fields of the given struct type.
+func cachedTypeFields(t reflect.Type) *structFields {
+	fieldCache.mu.RLock()
+	f := fieldCache.fields[t]
+	fieldCache.mu.RUnlock()
+	if f != nil {
+		return f
+	}
+	f = typeFields(t)
+	fieldCache.mu.Lock()
+	if fieldCache.fields == nil {
+		fieldCache.fields = make(map[reflect.Type]*structFields)
+	}
+	fieldCache.fields[t] = f
+	fieldCache.mu.Unlock()
+	return f
+}
+
+// typeFields returns the fields that encoding/json would encode for
+// the given struct type. The fields of untagged embedded structs are
+// promoted; where several fields have the same name, the shallowest
+// wins, and of those at the same depth a single tagged field wins.
+// Otherwise the conflicting fields are all dropped.
+func typeFields(t reflect.Type) *structFields {
+	type embedded struct {
+		typ   reflect.Type
+		index []int
+	}
+	var fields []field
+	seen := make(map[string]bool)
+	visited := make(map[reflect.Type]bool)
+	next := []embedded{{typ: t}}
+	for len(next) > 0 {
+		current := next
+		next = nil
+		var depthFields []field
+		for _, e := range current {
+			if visited[e.typ] {
+				continue
+			}
+			visited[e.typ] = true
+			for i := 0; i < e.typ.NumField(); i++ {
+				sf := e.typ.Field(i)
+				tag := sf.Tag.Get("json")
+				if tag == "-" {
+					continue
+				}
+				name, opts := parseTag(tag)
+				index := make([]int, len(e.index)+1)
+				copy(index, e.index)
+				index[len(e.index)] = i
+
+				ft := sf.Type
+				if ft.Name() == "" && ft.Kind() == reflect.Ptr {
+					ft = ft.Elem()
+				}
+				if sf.Anonymous && name == "" && ft.Kind() == reflect.Struct {
+					next = append(next, embedded{ft, index})
+					continue
+				}
+				if sf.PkgPath != "" {
+					// Unexported.
+					continue
+				}
+				tagged := name != ""
+				if !tagged {
+					name = sf.Name
+				}
+				depthFields = append(depthFields, field{
+					name:      name,
+					index:     index,
+					omitEmpty: opts.contains("omitempty"),
+					tagged:    tagged,
+				})
+			}
+		}
+		byName := make(map[string][]field)
+		for _, f := range depthFields {
+			byName[f.name] = append(byName[f.name], f)
+		}
+		for _, f := range depthFields {
+			if seen[f.name] {
+				continue
+			}
+			if dominant, ok := dominantField(byName[f.name]); ok {
+				fields = append(fields, dominant)
+			}
+			seen[f.name] = true
+		}
+	}
+	sort.Sort(byIndex(fields))
+	result := &structFields{
+		list:   fields,
+		byName: make(map[string]int, len(fields)),
+	}
+	for i, f := range fields {
+		result.byName[f.name] = i
+	}
+	return result
+}
+
+// dominantField returns the field that wins amongst fields of the same
+// name at the same depth, if any.
+func dominantField(fields []field) (field, bool) {
+	if len(fields) == 1 {
+		return fields[0], true
+	}
+	var dominant []field
+	for _, f := range fields {
+		if f.tagged {
+			dominant = append(dominant, f)
+		}
+	}
+	if len(dominant) == 1 {
+		return dominant[0], true
+	}
+	return field{}, false
+}
+
+type byIndex []field
+
+func (x byIndex) Len() int      { return len(x) }
+func (x byIndex) Swap(i, j int) { x[i], x[j] = x[j], x[i] }
+func (x byIndex) Less(i, j int) bool {
+	for k, xik := range x[i].index {
+		if k >= len(x[j].index) {
+			return false
+		}
+		if xik != x[j].index[k] {
+			return xik < x[j].index[k]
+		}
+	}
+	return len(x[i].index) < len(x[j].index)
+}
+
+type tagOptions string
+
+func parseTag(tag string) (string, tagOptions) {
+	if i := strings.Index(tag, ","); i != -1 {
+		return tag[:i], tagOptions(tag[i+1:])
+	}
+	return tag, ""
+}
+
+func (o tagOptions) contains(name string) bool {
+	for _, opt := range strings.Split(string(o), ",") {
+		if opt == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package msgpackcodec
+
+// MessagePack type codes, as defined by the specification at
+// https://github.com/msgpack/msgpack/blob/master/spec.md.
+const (
+	mpPosFixintMax = 0x7f
+	mpFixmap       = 0x80
+	mpFixarray     = 0x90
+	mpFixstr       = 0xa0
+	mpNil          = 0xc0
+	mpFalse        = 0xc2
+	mpTrue         = 0xc3
+	mpBin8         = 0xc4
+	mpBin16        = 0xc5
+	mpBin32        = 0xc6
+	mpExt8         = 0xc7
+	mpExt16        = 0xc8
+	mpExt32        = 0xc9
+	mpFloat32      = 0xca
+	mpFloat64      = 0xcb
+	mpUint8        = 0xcc
+	mpUint16       = 0xcd
+	mpUint32       = 0xce
+	mpUint64       = 0xcf
+	mpInt8         = 0xd0
+	mpInt16        = 0xd1
+	mpInt32        = 0xd2
+	mpInt64        = 0xd3
+	mpFixext1      = 0xd4
+	mpFixext2      = 0xd5
+	mpFixext4      = 0xd6
+	mpFixext8      = 0xd7
+	mpFixext16     = 0xd8
+	mpStr8         = 0xd9
+	mpStr16        = 0xda
+	mpStr32        = 0xdb
+	mpArray16      = 0xdc
+	mpArray32      = 0xdd
+	mpMap16        = 0xde
+	mpMap32        = 0xdf
+	mpNegFixintMin = 0xe0
+
+	mpFixstrMaxLen        = 31
+	mpFixCollectionMaxLen = 15
+)
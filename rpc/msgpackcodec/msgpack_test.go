@@ -0,0 +1,342 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package msgpackcodec_test
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/rpc/msgpackcodec"
+)
+
+type msgpackSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&msgpackSuite{})
+
+func (*msgpackSuite) TestMarshalEncoding(c *gc.C) {
+	for i, test := range []struct {
+		value  interface{}
+		expect string
+	}{
+		{nil, "c0"},
+		{false, "c2"},
+		{true, "c3"},
+		{0, "00"},
+		{127, "7f"},
+		{128, "cc80"},
+		{uint16(1000), "cd03e8"},
+		{70000, "ce00011170"},
+		{uint64(math.MaxUint64), "cfffffffffffffffff"},
+		{-1, "ff"},
+		{-32, "e0"},
+		{-33, "d0df"},
+		{-1000, "d1fc18"},
+		{int64(math.MinInt64), "d38000000000000000"},
+		{1.5, "cb3ff8000000000000"},
+		{float32(1.5), "ca3fc00000"},
+		{"", "a0"},
+		{"abc", "a3616263"},
+		{strings.Repeat("x", 32), "d920" + strings.Repeat("78", 32)},
+		{[]byte{1, 2}, "c4020102"},
+		{[]int{1, -1}, "9201ff"},
+		{[]string(nil), "c0"},
+		{map[string]bool{"a": true}, "81a161c3"},
+		{map[int]bool{2: false}, "81a132c2"},
+		{struct {
+			A int `json:"a"`
+			B int `json:"b,omitempty"`
+			C int `json:"-"`
+		}{A: 1}, "81a16101"},
+		{json.RawMessage(`{"x":[1,null]}`), "81a1789201c0"},
+		{json.Number("12"), "0c"},
+	} {
+		c.Logf("test %d: %#v", i, test.value)
+		data, err := msgpackcodec.Marshal(test.value)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(hex.EncodeToString(data), gc.Equals, test.expect)
+	}
+}
+
+type textValue struct {
+	a, b string
+}
+
+func (v textValue) MarshalText() ([]byte, error) {
+	return []byte(v.a + "/" + v.b), nil
+}
+
+func (v *textValue) UnmarshalText(text []byte) error {
+	parts := strings.SplitN(string(text), "/", 2)
+	if len(parts) != 2 {
+		return errors.Errorf("invalid text value %q", text)
+	}
+	v.a, v.b = parts[0], parts[1]
+	return nil
+}
+
+type pairValue struct {
+	name  string
+	count int
+}
+
+func (v pairValue) MarshalArray() ([]interface{}, error) {
+	return []interface{}{v.name, v.count}, nil
+}
+
+func (v *pairValue) UnmarshalArray(n int, next func(interface{}) error) error {
+	if n < 2 {
+		return errors.Errorf("expected at least 2 elements in pair but got %d", n)
+	}
+	if err := next(&v.name); err != nil {
+		return err
+	}
+	return next(&v.count)
+}
+
+type Embedded struct {
+	Promoted string `json:"promoted"`
+	Shadowed string `json:"shadowed"`
+}
+
+type inner struct {
+	Name  string `json:"name"`
+	Count int    `json:"count,omitempty"`
+}
+
+type outer struct {
+	Embedded
+	Shadowed   string `json:"shadowed"`
+	Skipped    string `json:"-"`
+	unexported string
+	Untagged   bool
+	Ptr        *inner                 `json:"ptr,omitempty"`
+	NilPtr     *inner                 `json:"nil-ptr"`
+	Slice      []inner                `json:"slice"`
+	Array      [2]string              `json:"array"`
+	Bytes      []byte                 `json:"bytes"`
+	IntMap     map[int]string         `json:"int-map"`
+	TextMap    map[textValue]int      `json:"text-map"`
+	Any        interface{}            `json:"any"`
+	AnyMap     map[string]interface{} `json:"any-map"`
+	Raw        json.RawMessage        `json:"raw"`
+	Time       time.Time              `json:"time"`
+	Text       textValue              `json:"text"`
+	Float      float64                `json:"float"`
+	Uint       uint64                 `json:"uint"`
+	Neg        int8                   `json:"neg"`
+	Duration   time.Duration          `json:"duration"`
+}
+
+func (*msgpackSuite) TestRoundTripMatchesJSON(c *gc.C) {
+	full := outer{
+		Embedded: Embedded{
+			Promoted: "promoted",
+			Shadowed: "hidden",
+		},
+		Shadowed:   "shadowed",
+		Skipped:    "skipped",
+		unexported: "unexported",
+		Untagged:   true,
+		Ptr:        &inner{Name: "ptr", Count: 3},
+		Slice:      []inner{{Name: "a"}, {Name: "b", Count: -1}},
+		Array:      [2]string{"x", "y"},
+		Bytes:      []byte("some bytes"),
+		IntMap:     map[int]string{-1: "minus one", 1000000: "million"},
+		TextMap:    map[textValue]int{{"a", "b"}: 1},
+		Any: map[string]interface{}{
+			"int":    42,
+			"list":   []interface{}{"x", true, nil, 1.25},
+			"bytes":  []byte{0xff},
+			"nested": map[string]string{"k": "v"},
+		},
+		AnyMap: map[string]interface{}{
+			"big": uint64(math.MaxUint32) + 1,
+		},
+		Raw:      json.RawMessage(`{"a":[1,2.5,"three"],"b":null}`),
+		Time:     time.Date(2017, 5, 1, 10, 0, 0, 123, time.UTC),
+		Text:     textValue{"left", "right"},
+		Float:    -0.5,
+		Uint:     math.MaxUint64,
+		Neg:      -100,
+		Duration: 90 * time.Second,
+	}
+	for i, test := range []struct {
+		about string
+		value interface{}
+		into  interface{}
+	}{{
+		about: "full struct",
+		value: full,
+		into:  new(outer),
+	}, {
+		about: "pointer to struct",
+		value: &full,
+		into:  new(*outer),
+	}, {
+		about: "zero struct",
+		value: outer{},
+		into:  new(outer),
+	}, {
+		about: "struct into generic value",
+		value: full,
+		into:  new(interface{}),
+	}, {
+		about: "struct into map",
+		value: struct{ A, B int }{1, -2},
+		into:  new(map[string]int64),
+	}, {
+		about: "map into struct",
+		value: map[string]interface{}{"NAME": "folded", "count": 7, "unknown": []int{1}},
+		into:  new(inner),
+	}, {
+		about: "integral float into int",
+		value: 2.0,
+		into:  new(int),
+	}, {
+		about: "base64 string into bytes",
+		value: "AQI=",
+		into:  new([]byte),
+	}, {
+		about: "short array",
+		value: []string{"a"},
+		into:  new([2]string),
+	}, {
+		about: "nil into slice",
+		value: nil,
+		into:  &[]int{1},
+	}, {
+		about: "number",
+		value: 123456789012,
+		into:  new(json.Number),
+	}} {
+		c.Logf("test %d: %s", i, test.about)
+		expect := reflect.New(reflect.TypeOf(test.into).Elem())
+		expect.Elem().Set(reflect.ValueOf(test.into).Elem())
+		data, err := json.Marshal(test.value)
+		c.Assert(err, jc.ErrorIsNil)
+		err = json.Unmarshal(data, expect.Interface())
+		c.Assert(err, jc.ErrorIsNil)
+
+		data, err = msgpackcodec.Marshal(test.value)
+		c.Assert(err, jc.ErrorIsNil)
+		err = msgpackcodec.Unmarshal(data, test.into)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(test.into, jc.DeepEquals, expect.Interface())
+	}
+}
+
+func (*msgpackSuite) TestArrayMarshaler(c *gc.C) {
+	data, err := msgpackcodec.Marshal([]pairValue{{"a", 1}})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hex.EncodeToString(data), gc.Equals, "9192a16101")
+
+	var pairs []pairValue
+	err = msgpackcodec.Unmarshal(data, &pairs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pairs, jc.DeepEquals, []pairValue{{"a", 1}})
+
+	var ptr *pairValue
+	data, err = msgpackcodec.Marshal(ptr)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(hex.EncodeToString(data), gc.Equals, "c0")
+}
+
+func (*msgpackSuite) TestArrayUnmarshalerSkipsUndecodedElements(c *gc.C) {
+	data, err := msgpackcodec.Marshal(map[string]interface{}{
+		"pair":  []interface{}{"b", 2, []int{3, 4}},
+		"after": "ok",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	var result struct {
+		Pair  pairValue `json:"pair"`
+		After string    `json:"after"`
+	}
+	err = msgpackcodec.Unmarshal(data, &result)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Pair, jc.DeepEquals, pairValue{"b", 2})
+	c.Assert(result.After, gc.Equals, "ok")
+
+	data, err = msgpackcodec.Marshal([]string{"c"})
+	c.Assert(err, jc.ErrorIsNil)
+	err = msgpackcodec.Unmarshal(data, &result.Pair)
+	c.Assert(err, gc.ErrorMatches, "expected at least 2 elements in pair but got 1")
+}
+
+func (*msgpackSuite) TestMarshalUnsupportedType(c *gc.C) {
+	_, err := msgpackcodec.Marshal(struct{ C chan int }{})
+	c.Assert(err, gc.ErrorMatches, "msgpack: unsupported type: chan int")
+}
+
+func (*msgpackSuite) TestUnmarshalErrors(c *gc.C) {
+	for i, test := range []struct {
+		data   string
+		into   interface{}
+		expect string
+	}{{
+		data:   "a3616263",
+		into:   new(int),
+		expect: "msgpack: cannot unmarshal string into Go value of type int",
+	}, {
+		data:   "cd03e8",
+		into:   new(int8),
+		expect: "msgpack: cannot unmarshal number 1000 into Go value of type int8",
+	}, {
+		data:   "ff",
+		into:   new(uint),
+		expect: "msgpack: cannot unmarshal number -1 into Go value of type uint",
+	}, {
+		data:   "cb3ff8000000000000",
+		into:   new(int),
+		expect: "msgpack: cannot unmarshal number 1.5 into Go value of type int",
+	}, {
+		data:   "a3616263",
+		into:   new(textValue),
+		expect: `invalid text value "abc"`,
+	}, {
+		data:   "01",
+		into:   new(textValue),
+		expect: `msgpack: cannot unmarshal number into Go value of type \*msgpackcodec_test.textValue`,
+	}, {
+		data:   "a36162",
+		into:   new(string),
+		expect: "msgpack: unexpected end of data",
+	}, {
+		data:   "dd7fffffff",
+		into:   new([]int),
+		expect: "msgpack: unexpected end of data",
+	}, {
+		data:   "0101",
+		into:   new(int),
+		expect: "msgpack: unexpected data after top-level value",
+	}, {
+		data:   "c1",
+		into:   new(interface{}),
+		expect: "msgpack: invalid type code 0xc1",
+	}, {
+		data:   "d40100",
+		into:   new(interface{}),
+		expect: `msgpack: cannot unmarshal extension into Go value of type interface \{\}`,
+	}, {
+		data:   "01",
+		into:   0,
+		expect: "msgpack: cannot unmarshal into non-pointer int",
+	}} {
+		c.Logf("test %d: %s", i, test.data)
+		data, err := hex.DecodeString(test.data)
+		c.Assert(err, jc.ErrorIsNil)
+		err = msgpackcodec.Unmarshal(data, test.into)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
@@ -0,0 +1,270 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package msgpackcodec_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/apiserver/params"
+	"github.com/juju/juju/constraints"
+	"github.com/juju/juju/rpc/msgpackcodec"
+	"github.com/juju/juju/state/multiwatcher"
+	"github.com/juju/juju/status"
+)
+
+type paramsSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&paramsSuite{})
+
+var (
+	since = time.Date(2017, 5, 1, 10, 0, 0, 123456789, time.FixedZone("", 2*60*60))
+
+	fullStatus = params.FullStatus{
+		Model: params.ModelStatusInfo{
+			Name:     "default",
+			CloudTag: "cloud-dummy",
+			Version:  "2.2-beta1",
+			ModelStatus: params.DetailedStatus{
+				Status: "available",
+				Since:  &since,
+			},
+		},
+		Machines: map[string]params.MachineStatus{
+			"0": {
+				AgentStatus: params.DetailedStatus{
+					Status:  "started",
+					Since:   &since,
+					Version: "2.2-beta1",
+				},
+				InstanceStatus: params.DetailedStatus{
+					Status: "running",
+					Data:   map[string]interface{}{"zone": "a", "attempt": 2},
+				},
+				DNSName:     "10.0.0.1",
+				IPAddresses: []string{"10.0.0.1"},
+				InstanceId:  "i-0",
+				Series:      "xenial",
+				Id:          "0",
+				NetworkInterfaces: map[string]params.NetworkInterface{
+					"eth0": {
+						IPAddresses: []string{"10.0.0.1"},
+						MACAddress:  "aa:bb:cc:dd:ee:ff",
+						IsUp:        true,
+					},
+				},
+				Containers: map[string]params.MachineStatus{
+					"0/lxd/0": {Id: "0/lxd/0", Series: "xenial"},
+				},
+				Jobs:    []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+				HasVote: true,
+			},
+		},
+		Applications: map[string]params.ApplicationStatus{
+			"mysql": {
+				Charm:     "cs:xenial/mysql-1",
+				Series:    "xenial",
+				Relations: map[string][]string{"db": {"wordpress"}},
+				Units: map[string]params.UnitStatus{
+					"mysql/0": {
+						AgentStatus:    params.DetailedStatus{Status: "idle", Since: &since},
+						WorkloadStatus: params.DetailedStatus{Status: "active", Info: "ready"},
+						Machine:        "0",
+						OpenedPorts:    []string{"3306/tcp"},
+						Leader:         true,
+					},
+				},
+				MeterStatuses: map[string]params.MeterStatus{
+					"mysql/0": {Color: "green"},
+				},
+				Status: params.DetailedStatus{Status: "active"},
+			},
+		},
+		RemoteApplications: map[string]params.RemoteApplicationStatus{
+			"remote": {
+				ApplicationURL:  "local:/u/me/remote",
+				ApplicationName: "remote",
+				Endpoints: []params.RemoteEndpoint{{
+					Name:      "db",
+					Role:      "provider",
+					Interface: "mysql",
+				}},
+			},
+		},
+		Relations: []params.RelationStatus{{
+			Id:        1,
+			Key:       "wordpress:db mysql:db",
+			Interface: "mysql",
+			Scope:     "global",
+			Endpoints: []params.EndpointStatus{{
+				ApplicationName: "mysql",
+				Name:            "db",
+				Role:            "provider",
+			}},
+		}},
+	}
+
+	mem = uint64(2048)
+
+	allWatcherNextResults = params.AllWatcherNextResults{
+		Deltas: []multiwatcher.Delta{{
+			Entity: &multiwatcher.MachineInfo{
+				ModelUUID:   "uuid",
+				Id:          "0",
+				AgentStatus: multiwatcher.StatusInfo{Current: status.Started, Since: &since},
+				Jobs:        []multiwatcher.MachineJob{multiwatcher.JobHostUnits},
+				Addresses:   []multiwatcher.Address{{Value: "10.0.0.1", Type: "ipv4"}},
+			},
+		}, {
+			Entity: &multiwatcher.ApplicationInfo{
+				ModelUUID:   "uuid",
+				Name:        "mysql",
+				CharmURL:    "cs:xenial/mysql-1",
+				Constraints: constraints.Value{Mem: &mem},
+				Config:      map[string]interface{}{"port": 3306},
+				Status:      multiwatcher.StatusInfo{Current: status.Active},
+			},
+		}, {
+			Entity: &multiwatcher.UnitInfo{
+				ModelUUID:      "uuid",
+				Name:           "mysql/0",
+				Application:    "mysql",
+				Ports:          []multiwatcher.Port{{Protocol: "tcp", Number: 3306}},
+				WorkloadStatus: multiwatcher.StatusInfo{Current: status.Active, Data: map[string]interface{}{"n": 1.5}},
+			},
+		}, {
+			Entity: &multiwatcher.ActionInfo{
+				ModelUUID: "uuid",
+				Id:        "1",
+				Receiver:  "unit-mysql-0",
+				Name:      "backup",
+				Results:   map[string]interface{}{"size": 100},
+				Enqueued:  since,
+				Started:   since.Add(time.Second),
+			},
+		}, {
+			Removed: true,
+			Entity:  &multiwatcher.RelationInfo{ModelUUID: "uuid", Key: "wordpress:db mysql:db"},
+		}},
+	}
+)
+
+// assertRoundTripMatchesJSON checks that value decodes into a value of
+// the type pointed to by into in the same way through MessagePack as
+// it does through JSON.
+func assertRoundTripMatchesJSON(c *gc.C, value, into interface{}) {
+	expect := reflect.New(reflect.TypeOf(into).Elem()).Interface()
+	data, err := json.Marshal(value)
+	c.Assert(err, jc.ErrorIsNil)
+	err = json.Unmarshal(data, expect)
+	c.Assert(err, jc.ErrorIsNil)
+
+	data, err = msgpackcodec.Marshal(value)
+	c.Assert(err, jc.ErrorIsNil)
+	err = msgpackcodec.Unmarshal(data, into)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(into, jc.DeepEquals, expect)
+}
+
+func (*paramsSuite) TestFullStatusRoundTrip(c *gc.C) {
+	var result params.FullStatus
+	assertRoundTripMatchesJSON(c, fullStatus, &result)
+	c.Assert(result.Model.ModelStatus.Since.Equal(since), jc.IsTrue)
+}
+
+func (*paramsSuite) TestFullStatusIntoGenericValue(c *gc.C) {
+	var result interface{}
+	assertRoundTripMatchesJSON(c, fullStatus, &result)
+}
+
+func (*paramsSuite) TestAllWatcherNextResultsRoundTrip(c *gc.C) {
+	var result params.AllWatcherNextResults
+	assertRoundTripMatchesJSON(c, allWatcherNextResults, &result)
+	c.Assert(result.Deltas, gc.HasLen, 5)
+	c.Assert(result.Deltas[3].Entity.(*multiwatcher.ActionInfo).Enqueued.Equal(since), jc.IsTrue)
+	c.Assert(result.Deltas[4].Removed, jc.IsTrue)
+}
+
+func (*paramsSuite) TestAllWatcherNextResultsIntoGenericValue(c *gc.C) {
+	var result interface{}
+	assertRoundTripMatchesJSON(c, allWatcherNextResults, &result)
+}
+
+func (*paramsSuite) TestDeltaPointerRoundTrip(c *gc.C) {
+	var result *multiwatcher.Delta
+	assertRoundTripMatchesJSON(c, &allWatcherNextResults.Deltas[2], &result)
+	assertRoundTripMatchesJSON(c, (*multiwatcher.Delta)(nil), &result)
+	c.Assert(result, gc.IsNil)
+}
+
+func (*paramsSuite) TestTimeEncodedAsRFC3339String(c *gc.C) {
+	data, err := msgpackcodec.Marshal(since)
+	c.Assert(err, jc.ErrorIsNil)
+	expect, err := msgpackcodec.Marshal(since.Format(time.RFC3339Nano))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data, jc.DeepEquals, expect)
+
+	_, err = msgpackcodec.Marshal(time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC))
+	c.Assert(err, gc.ErrorMatches, `msgpack: time .* has year outside of range \[0,9999\]`)
+}
+
+func (*paramsSuite) TestUnmarshalDeltaErrors(c *gc.C) {
+	for i, test := range []struct {
+		value  interface{}
+		expect string
+	}{{
+		value:  []string{"unit", "change"},
+		expect: "expected 3 elements in delta but got 2",
+	}, {
+		value:  "unit",
+		expect: "msgpack: cannot unmarshal string into Go value of type multiwatcher.Delta",
+	}, {
+		value:  []interface{}{"unit", "explode", map[string]string{}},
+		expect: `unexpected delta operation "explode"`,
+	}, {
+		value:  []interface{}{"widget", "change", map[string]string{}},
+		expect: `Unexpected entity name "widget"`,
+	}, {
+		value:  []interface{}{"unit", "change", "not a unit"},
+		expect: `msgpack: cannot unmarshal string into Go value of type multiwatcher.UnitInfo`,
+	}} {
+		c.Logf("test %d: %v", i, test.value)
+		data, err := msgpackcodec.Marshal(test.value)
+		c.Assert(err, jc.ErrorIsNil)
+		var delta multiwatcher.Delta
+		err = msgpackcodec.Unmarshal(data, &delta)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (*paramsSuite) TestUnmarshalCorruptData(c *gc.C) {
+	// The codec decodes data from untrusted peers, so check that no
+	// truncation or single byte change of a real message can make it
+	// succeed on a partial message or panic.
+	data, err := msgpackcodec.Marshal(allWatcherNextResults)
+	c.Assert(err, jc.ErrorIsNil)
+	for i := 0; i < len(data); i++ {
+		var result params.AllWatcherNextResults
+		err := msgpackcodec.Unmarshal(data[:i], &result)
+		c.Assert(err, gc.NotNil, gc.Commentf("truncated to %d bytes", i))
+	}
+	corrupt := make([]byte, len(data))
+	for i := range data {
+		for _, b := range []byte{0x00, 0x7f, 0x90, 0xc0, 0xc1, 0xc7, 0xd4, 0xdd, 0xdf, 0xff} {
+			copy(corrupt, data)
+			corrupt[i] = b
+			var result params.AllWatcherNextResults
+			msgpackcodec.Unmarshal(corrupt, &result)
+			var generic interface{}
+			msgpackcodec.Unmarshal(corrupt, &generic)
+		}
+	}
+}
@@ -28,6 +28,26 @@ type Observer interface {
 	ServerReply(req Request, hdr *Header, body interface{})
 }
 
+// ReplyWrittenObserver may be implemented by an Observer that wants
+// to know about replies once they have been written, for example to
+// find out their size.
+type ReplyWrittenObserver interface {
+	// ServerReplyWritten informs the Observer that the reply to a
+	// server request has been written. The given Request gives
+	// details of the call that was made; the given Header is the
+	// header sent as reply, and its Size holds the size of the
+	// reply as written by the Codec.
+	ServerReplyWritten(req Request, hdr *Header)
+}
+
+// replyWritten calls observer's ServerReplyWritten method, if it has
+// one.
+func replyWritten(observer Observer, req Request, hdr *Header) {
+	if observer, ok := observer.(ReplyWrittenObserver); ok {
+		observer.ServerReplyWritten(req, hdr)
+	}
+}
+
 // NewObserverMultiplexer returns a new ObserverMultiplexer
 // with the provided RequestNotifiers.
 func NewObserverMultiplexer(rpcObservers ...Observer) *ObserverMultiplexer {
@@ -52,6 +72,11 @@ func (m *ObserverMultiplexer) ServerRequest(hdr *Header, body interface{}) {
 	mapConcurrent(func(n Observer) { n.ServerRequest(hdr, body) }, m.rpcObservers)
 }
 
+// ServerReplyWritten implements ReplyWrittenObserver.
+func (m *ObserverMultiplexer) ServerReplyWritten(req Request, hdr *Header) {
+	mapConcurrent(func(n Observer) { replyWritten(n, req, hdr) }, m.rpcObservers)
+}
+
 // mapConcurrent calls fn on all observers concurrently and then waits
 // for all calls to exit before returning.
 func mapConcurrent(fn func(Observer), requestNotifiers []Observer) {
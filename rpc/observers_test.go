@@ -53,3 +53,28 @@ func (*multiplexerSuite) TestServerRequest_CallsAllObservers(c *gc.C) {
 		f.CheckCall(c, 0, "ServerRequest", &hdr, body)
 	}
 }
+
+type replyWrittenObserver struct {
+	*fakeobserver.RPCInstance
+}
+
+func (o replyWrittenObserver) ServerReplyWritten(req rpc.Request, hdr *rpc.Header) {
+	o.AddCall("ServerReplyWritten", req, hdr)
+}
+
+func (*multiplexerSuite) TestServerReplyWritten_CallsObserversWithMethod(c *gc.C) {
+	observers := []*fakeobserver.RPCInstance{
+		(&fakeobserver.Instance{}).RPCObserver().(*fakeobserver.RPCInstance),
+		(&fakeobserver.Instance{}).RPCObserver().(*fakeobserver.RPCInstance),
+	}
+
+	o := rpc.NewObserverMultiplexer(replyWrittenObserver{observers[0]}, observers[1])
+	var (
+		req rpc.Request
+		hdr rpc.Header
+	)
+	o.ServerReplyWritten(req, &hdr)
+
+	observers[0].CheckCall(c, 0, "ServerReplyWritten", req, &hdr)
+	observers[1].CheckNoCalls(c)
+}
@@ -244,6 +244,20 @@ func (a *ChangeAPIMethods) RemoveAPI() {
 	a.r.conn.Serve(nil, nil)
 }
 
+func (a *ChangeAPIMethods) LimitAPI() {
+	a.r.conn.SetRequestLimiter(simpleMethodsLimiter{})
+}
+
+// simpleMethodsLimiter rejects all requests to SimpleMethods.
+type simpleMethodsLimiter struct{}
+
+func (simpleMethodsLimiter) Allow(req rpc.Request) error {
+	if req.Type == "SimpleMethods" {
+		return &codedError{"too many requests", "rate limited"}
+	}
+	return nil
+}
+
 type changedAPIRoot struct{}
 
 func (r *changedAPIRoot) NewlyAvailable(string) (newlyAvailableMethods, error) {
@@ -504,10 +518,12 @@ func (root *Root) assertServerNotified(c *gc.C, p testCallParams, requestId uint
 	// Test that there was a notification for the request.
 	c.Assert(p.serverNotifier.serverRequests, gc.HasLen, 1)
 	serverReq := p.serverNotifier.serverRequests[0]
+	c.Assert(serverReq.hdr.Size, jc.GreaterThan, 0)
 	c.Assert(serverReq.hdr, gc.DeepEquals, rpc.Header{
 		RequestId: requestId,
 		Request:   p.request(),
 		Version:   1,
+		Size:      serverReq.hdr.Size,
 	})
 	if p.narg > 0 {
 		c.Assert(serverReq.body, gc.Equals, stringVal{"arg"})
@@ -907,11 +923,14 @@ func testBadCall(
 	if requestKnown {
 		expectBody = struct{}{}
 	}
-	c.Assert(serverNotifier.serverRequests[0], gc.DeepEquals, requestEvent{
+	serverReq := serverNotifier.serverRequests[0]
+	c.Assert(serverReq.hdr.Size, jc.GreaterThan, 0)
+	c.Assert(serverReq, gc.DeepEquals, requestEvent{
 		hdr: rpc.Header{
 			RequestId: client.ClientRequestID(),
 			Request:   req,
 			Version:   1,
+			Size:      serverReq.hdr.Size,
 		},
 		body: expectBody,
 	})
@@ -1003,6 +1022,25 @@ func (*rpcSuite) TestServerRequestWhenNotServing(c *gc.C) {
 	c.Assert(err, gc.ErrorMatches, "no service")
 }
 
+func (*rpcSuite) TestServerReplyWritten(c *gc.C) {
+	root := SimpleRoot()
+	client, srvDone, serverNotifier := newRPCClientServer(c, root, nil, false)
+	defer closeClient(c, client, srvDone)
+	written := serverNotifier.watchWritten()
+
+	var r stringVal
+	err := client.Call(rpc.Request{"SimpleMethods", 0, "a99", "Call0r1"}, nil, &r)
+	c.Assert(err, jc.ErrorIsNil)
+	select {
+	case hdr := <-written:
+		// The size is only known once the reply is written.
+		c.Assert(hdr.Size, jc.GreaterThan, 0)
+		c.Assert(hdr.RequestId, gc.Equals, client.ClientRequestID())
+	case <-time.After(testing.LongWait):
+		c.Fatalf("reply not written")
+	}
+}
+
 func (*rpcSuite) TestChangeAPI(c *gc.C) {
 	srvRoot := &Root{}
 	client, srvDone, _ := newRPCClientServer(c, srvRoot, nil, true)
@@ -1065,6 +1103,47 @@ func (*rpcSuite) TestChangeAPIWhileServingRequest(c *gc.C) {
 	}
 }
 
+func (*rpcSuite) TestRequestLimiter(c *gc.C) {
+	root := &Root{
+		simple: make(map[string]*SimpleMethods),
+	}
+	root.simple["a99"] = &SimpleMethods{root: root, id: "a99"}
+	transform := func(err error) error {
+		if e, ok := err.(*codedError); ok {
+			return &codedError{"transformed: " + e.m, e.code}
+		}
+		return err
+	}
+	client, srvDone, serverNotifier := newRPCClientServer(c, root, transform, false)
+	defer closeClient(c, client, srvDone)
+
+	err := client.Call(rpc.Request{"SimpleMethods", 0, "a99", "Call0r0"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	err = client.Call(rpc.Request{"ChangeAPIMethods", 0, "", "LimitAPI"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+	serverNotifier.reset()
+
+	err = client.Call(rpc.Request{"SimpleMethods", 0, "a99", "Call0r0"}, nil, nil)
+	c.Assert(errors.Cause(err), gc.DeepEquals, &rpc.RequestError{
+		Message: "transformed: too many requests",
+		Code:    "rate limited",
+	})
+	root.mu.Lock()
+	c.Check(root.calls, gc.HasLen, 1)
+	root.mu.Unlock()
+
+	// The request is still observed.
+	serverNotifier.mu.Lock()
+	c.Check(serverNotifier.serverRequests, gc.HasLen, 1)
+	c.Assert(serverNotifier.serverReplies, gc.HasLen, 1)
+	c.Check(serverNotifier.serverReplies[0].hdr.ErrorCode, gc.Equals, "rate limited")
+	serverNotifier.mu.Unlock()
+
+	// Other requests are dispatched.
+	err = client.Call(rpc.Request{"ChangeAPIMethods", 0, "", "LimitAPI"}, nil, nil)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
 func (*rpcSuite) TestCodeNotImplementedMatchesAPIserverParams(c *gc.C) {
 	c.Assert(rpc.CodeNotImplemented, gc.Equals, params.CodeNotImplemented)
 }
@@ -1223,6 +1302,7 @@ type notifier struct {
 	mu             sync.Mutex
 	serverRequests []requestEvent
 	serverReplies  []replyEvent
+	written        chan rpc.Header
 }
 
 func (n *notifier) RPCObserver() rpc.Observer {
@@ -1259,3 +1339,20 @@ func (n *notifier) ServerReply(req rpc.Request, hdr *rpc.Header, body interface{
 		body: body,
 	})
 }
+
+// watchWritten returns a channel on which the headers of replies are
+// sent once they have been written.
+func (n *notifier) watchWritten() <-chan rpc.Header {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.written = make(chan rpc.Header, 10)
+	return n.written
+}
+
+func (n *notifier) ServerReplyWritten(req rpc.Request, hdr *rpc.Header) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if n.written != nil {
+		n.written <- *hdr
+	}
+}
@@ -23,7 +23,8 @@ var logger = loggo.GetLogger("juju.rpc")
 // connection and calls ReadHeader and ReadBody in pairs to read
 // messages.
 type Codec interface {
-	// ReadHeader reads a message header into hdr.
+	// ReadHeader reads a message header into hdr, setting hdr.Size
+	// to the size of the whole message as read.
 	ReadHeader(hdr *Header) error
 
 	// ReadBody reads a message body into the given body value.  The
@@ -33,7 +34,8 @@ type Codec interface {
 	// should be read and discarded.
 	ReadBody(body interface{}, isRequest bool) error
 
-	// WriteMessage writes a message with the given header and body.
+	// WriteMessage writes a message with the given header and body,
+	// setting hdr.Size to the size of the message as written.
 	// The body will always be a struct. It may be called concurrently
 	// with ReadHeader and ReadBody, but will not be called
 	// concurrently with itself.
@@ -64,6 +66,10 @@ type Header struct {
 
 	// Version defines the wire format of the request and response structure.
 	Version int
+
+	// Size holds the size of the encoded message in bytes. It is set
+	// by the Codec when the message is read or written.
+	Size int
 }
 
 // Request represents an RPC to be performed, absent its parameters.
@@ -96,6 +102,15 @@ type ObserverFactory interface {
 	RPCObserver() Observer
 }
 
+// RequestLimiter decides whether server requests may be dispatched,
+// for example to limit the rate at which a client may make requests.
+type RequestLimiter interface {
+	// Allow is called before each server request is dispatched. If
+	// it returns an error, the request is not dispatched and the
+	// error, transformed as other errors are, is sent as the reply.
+	Allow(req Request) error
+}
+
 // Note that we use "client request" and "server request" to name
 // requests initiated locally and remotely respectively.
 
@@ -125,6 +140,10 @@ type Conn struct {
 	// transformErrors is used to transform returned errors.
 	transformErrors func(error) error
 
+	// limiter, if non-nil, is consulted before each server request
+	// is dispatched.
+	limiter RequestLimiter
+
 	// reqId holds the latest client request id.
 	reqId uint64
 
@@ -245,6 +264,16 @@ func (conn *Conn) serve(root Root, transformErrors func(error) error) {
 	conn.transformErrors = transformErrors
 }
 
+// SetRequestLimiter sets the limiter that is consulted before each
+// server request is dispatched. If limiter is nil, all requests are
+// dispatched. Like Serve, it has no effect on requests that are
+// currently being serviced.
+func (conn *Conn) SetRequestLimiter(limiter RequestLimiter) {
+	conn.mutex.Lock()
+	defer conn.mutex.Unlock()
+	conn.limiter = limiter
+}
+
 // noopTransform is used when transformErrors is not supplied to Serve.
 func noopTransform(err error) error {
 	return err
@@ -414,6 +443,14 @@ func (conn *Conn) handleRequest(hdr *Header) error {
 		observer.ServerRequest(hdr, struct{}{})
 	}
 	conn.mutex.Lock()
+	limiter := conn.limiter
+	conn.mutex.Unlock()
+	if limiter != nil {
+		if err := limiter.Allow(hdr.Request); err != nil {
+			return conn.writeErrorResponse(hdr, req.transformErrors(err), observer)
+		}
+	}
+	conn.mutex.Lock()
 	closing := conn.closing
 	if !closing {
 		conn.srvPending.Add(1)
@@ -442,7 +479,11 @@ func (conn *Conn) writeErrorResponse(reqHdr *Header, err error, observer Observe
 	hdr.Error = err.Error()
 	observer.ServerReply(reqHdr.Request, hdr, struct{}{})
 
-	return conn.codec.WriteMessage(hdr, struct{}{})
+	if err := conn.codec.WriteMessage(hdr, struct{}{}); err != nil {
+		return err
+	}
+	replyWritten(observer, reqHdr.Request, hdr)
+	return nil
 }
 
 // boundRequest represents an RPC request that is
@@ -505,6 +546,9 @@ func (conn *Conn) runRequest(req boundRequest, arg reflect.Value, version int, o
 		conn.sending.Lock()
 		err = conn.codec.WriteMessage(hdr, rvi)
 		conn.sending.Unlock()
+		if err == nil {
+			replyWritten(observer, req.hdr.Request, hdr)
+		}
 	}
 	if err != nil {
 		logger.Errorf("error writing response: %v", err)
@@ -8,7 +8,9 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	jujutxn "github.com/juju/txn"
 	"github.com/juju/utils"
+	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/names.v2"
 	"gopkg.in/mgo.v2"
 	"gopkg.in/mgo.v2/bson"
@@ -45,6 +47,14 @@ const (
 
 	// ActionRunning indicates that the Action is currently running.
 	ActionRunning ActionStatus = "running"
+
+	// ActionAborting indicates that the Action was cancelled while
+	// running, and that the unit agent has yet to stop it.
+	ActionAborting ActionStatus = "aborting"
+
+	// ActionTimedOut means that the Action was stopped because it ran
+	// for longer than its timeout.
+	ActionTimedOut ActionStatus = "timedout"
 )
 
 type actionNotificationDoc struct {
@@ -77,6 +87,11 @@ type actionDoc struct {
 	// which this Action is queued.
 	Receiver string `bson:"receiver"`
 
+	// Application is the name of the application whose unit receives
+	// the action, if the receiver is a unit. It is recorded so that
+	// actions can be queried by application.
+	Application string `bson:"application,omitempty"`
+
 	// Name identifies the action that should be run; it should
 	// match an action defined by the unit's charm.
 	Name string `bson:"name"`
@@ -88,6 +103,16 @@ type actionDoc struct {
 	// Enqueued is the time the action was added.
 	Enqueued time.Time `bson:"enqueued"`
 
+	// Timeout holds how long the action may run before the unit agent
+	// stops it. If none was given when the action was enqueued, it
+	// holds the action's default from the charm; if it is zero, the
+	// action may run indefinitely.
+	Timeout time.Duration `bson:"timeout,omitempty"`
+
+	// StreamOutput records whether the unit agent should log the
+	// output of the action as messages while it runs.
+	StreamOutput bool `bson:"stream-output,omitempty"`
+
 	// Started reflects the time the action began running.
 	Started time.Time `bson:"started"`
 
@@ -104,8 +129,33 @@ type actionDoc struct {
 
 	// Results are the structured results from the action.
 	Results map[string]interface{} `bson:"results"`
+
+	// Logs holds the progress messages logged by the action while
+	// it runs, oldest first.
+	Logs []ActionMessage `bson:"messages,omitempty"`
+
+	// MessageCount is the number of progress messages ever logged by
+	// the action, including any since dropped from Logs.
+	MessageCount int `bson:"message-count,omitempty"`
+
+	// Operation holds the id of the operation the action was
+	// enqueued as part of, if any.
+	Operation string `bson:"operation,omitempty"`
+}
+
+// ActionMessage is a progress message logged by a running action.
+type ActionMessage struct {
+	// Sequence numbers the action's messages from 1, in the order
+	// they were logged.
+	Sequence  int       `bson:"seq"`
+	Timestamp time.Time `bson:"timestamp"`
+	Message   string    `bson:"message"`
 }
 
+// maxActionMessages is the number of messages kept for an action;
+// older messages are dropped as new ones are logged.
+const maxActionMessages = 1000
+
 // action represents an instruction to do some "action" and is expected
 // to match an action definition in a charm.
 type action struct {
@@ -142,6 +192,30 @@ func (a *action) Enqueued() time.Time {
 	return a.doc.Enqueued
 }
 
+// Timeout returns how long the action may run before it is stopped,
+// or zero if it may run indefinitely.
+func (a *action) Timeout() time.Duration {
+	return a.doc.Timeout
+}
+
+// StreamOutput returns whether the output of the action should be
+// logged as messages while it runs.
+func (a *action) StreamOutput() bool {
+	return a.doc.StreamOutput
+}
+
+// Messages returns the progress messages logged by the action,
+// oldest first.
+func (a *action) Messages() []ActionMessage {
+	return a.doc.Logs
+}
+
+// Operation returns the id of the operation the action is part of,
+// or "" if it was enqueued on its own.
+func (a *action) Operation() string {
+	return a.doc.Operation
+}
+
 // Started returns the time that the Action execution began.
 func (a *action) Started() time.Time {
 	return a.doc.Started
@@ -207,37 +281,196 @@ func (a *action) Finish(results ActionResults) (Action, error) {
 	return a.removeAndLog(results.Status, results.Results, results.Message)
 }
 
+// Cancel cancels the action. A pending action is finished straight away
+// as cancelled, with the given message. A running action is marked as
+// aborting; the unit agent running it stops it and then finishes it as
+// cancelled, keeping any results it recorded before it was stopped.
+func (a *action) Cancel(message string) (Action, error) {
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		doc := a.doc
+		if attempt > 0 {
+			current, err := a.st.Action(a.Id())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			doc = current.(*action).doc
+		}
+		switch doc.Status {
+		case ActionPending:
+			return a.finishOps(bson.D{{"status", ActionPending}}, ActionCancelled, nil, message)
+		case ActionRunning:
+			return []txn.Op{{
+				C:      actionsC,
+				Id:     a.doc.DocId,
+				Assert: bson.D{{"status", ActionRunning}},
+				Update: bson.D{{"$set", bson.D{
+					{"status", ActionAborting},
+					{"message", message},
+				}}},
+			}}, nil
+		case ActionAborting:
+			return nil, jujutxn.ErrNoOperations
+		}
+		return nil, errors.Errorf("action %s is already %s", a.Id(), doc.Status)
+	}
+	if err := a.st.run(buildTxn); err != nil {
+		return nil, errors.Annotate(err, "cannot cancel action")
+	}
+	return a.st.Action(a.Id())
+}
+
+// Log adds timestamped progress messages to the action, numbering
+// them after those already logged. It fails if the action is not
+// running.
+func (a *action) Log(messages ...string) error {
+	if len(messages) == 0 {
+		return nil
+	}
+	now := a.st.clock.Now().UTC()
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		current, err := a.st.Action(a.Id())
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		if status := current.Status(); status != ActionRunning && status != ActionAborting {
+			return nil, errors.Errorf("action is %s", status)
+		}
+		count := current.(*action).doc.MessageCount
+		msgs := make([]ActionMessage, len(messages))
+		for i, message := range messages {
+			msgs[i] = ActionMessage{
+				Sequence:  count + i + 1,
+				Timestamp: now,
+				Message:   message,
+			}
+		}
+		return []txn.Op{{
+			C:  actionsC,
+			Id: a.doc.DocId,
+			Assert: bson.D{
+				{"status", bson.D{{"$in", []interface{}{ActionRunning, ActionAborting}}}},
+				{"message-count", bson.D{{"$in", messageCountValues(count)}}},
+			},
+			Update: bson.D{
+				{"$push", bson.D{{"messages", bson.D{
+					{"$each", msgs},
+					{"$slice", -maxActionMessages},
+				}}}},
+				{"$inc", bson.D{{"message-count", len(msgs)}}},
+			},
+		}}, nil
+	}
+	if err := a.st.run(buildTxn); err != nil {
+		return errors.Annotatef(err, "cannot log message to action %s", a.Id())
+	}
+	return nil
+}
+
+// messageCountValues returns the values of an action document's
+// message-count field that mean count messages have been logged; the
+// field is missing until the first message is.
+func messageCountValues(count int) []interface{} {
+	if count == 0 {
+		return []interface{}{0, nil}
+	}
+	return []interface{}{count}
+}
+
 // removeAndLog takes the action off of the pending queue, and creates
 // an actionresult to capture the outcome of the action. It asserts that
 // the action is not already completed.
 func (a *action) removeAndLog(finalStatus ActionStatus, results map[string]interface{}, message string) (Action, error) {
-	err := a.st.runTransaction([]txn.Op{
-		{
-			C:  actionsC,
-			Id: a.doc.DocId,
-			Assert: bson.D{{"status", bson.D{
-				{"$nin", []interface{}{
-					ActionCompleted,
-					ActionCancelled,
-					ActionFailed,
-				}}}}},
-			Update: bson.D{{"$set", bson.D{
-				{"status", finalStatus},
-				{"message", message},
-				{"results", results},
-				{"completed", a.st.NowToTheSecond()},
-			}}},
-		}, {
-			C:      actionNotificationsC,
-			Id:     a.st.docID(ensureActionMarker(a.Receiver()) + a.Id()),
-			Remove: true,
-		}})
-	if err != nil {
+	notFinished := bson.D{{"status", bson.D{
+		{"$nin", []interface{}{
+			ActionCompleted,
+			ActionCancelled,
+			ActionFailed,
+			ActionTimedOut,
+		}}}}}
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		if attempt > 0 {
+			current, err := a.st.Action(a.Id())
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			switch status := current.Status(); status {
+			case ActionCompleted, ActionCancelled, ActionFailed, ActionTimedOut:
+				return nil, errors.Errorf("action %s is already %s", a.Id(), status)
+			}
+		}
+		return a.finishOps(notFinished, finalStatus, results, message)
+	}
+	if err := a.st.run(buildTxn); err != nil {
 		return nil, err
 	}
 	return a.st.Action(a.Id())
 }
 
+// finishOps returns the operations that record the outcome of the
+// action and take it off the pending queue, asserting that the action's
+// document matches assert. If the action is part of an operation, they
+// also release the next action the operation holds back.
+func (a *action) finishOps(assert bson.D, finalStatus ActionStatus, results map[string]interface{}, message string) ([]txn.Op, error) {
+	ops := []txn.Op{a.recordOutcomeOp(assert, finalStatus, results, message)}
+	if a.doc.Operation == "" {
+		return append(ops, a.removeNotificationOp()), nil
+	}
+	op, err := a.st.Operation(a.doc.Operation)
+	if errors.IsNotFound(err) {
+		// The operation has been pruned; it holds nothing back.
+		return append(ops, a.removeNotificationOp()), nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if op.held(a.Id()) {
+		// The action was never queued on its unit.
+		return append(ops, op.unholdOp(a.Id())), nil
+	}
+	releaseOps, err := op.releaseNextOps()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	ops = append(ops, a.removeNotificationOp())
+	return append(ops, releaseOps...), nil
+}
+
+// recordOutcomeOp returns the operation that records the outcome of
+// the action, asserting that the action's document matches assert.
+func (a *action) recordOutcomeOp(assert bson.D, finalStatus ActionStatus, results map[string]interface{}, message string) txn.Op {
+	return txn.Op{
+		C:      actionsC,
+		Id:     a.doc.DocId,
+		Assert: assert,
+		Update: bson.D{{"$set", bson.D{
+			{"status", finalStatus},
+			{"message", message},
+			{"results", results},
+			{"completed", a.st.NowToTheSecond()},
+		}}},
+	}
+}
+
+// notificationDoc returns the notification that queues the action on
+// its receiver.
+func (a *action) notificationDoc() actionNotificationDoc {
+	return actionNotificationDoc{
+		DocId:     a.st.docID(ensureActionMarker(a.Receiver()) + a.Id()),
+		ModelUUID: a.st.ModelUUID(),
+		Receiver:  a.Receiver(),
+		ActionID:  a.Id(),
+	}
+}
+
+// removeNotificationOp returns the operation that removes the action's
+// notification, taking it off the receiver's queue.
+func (a *action) removeNotificationOp() txn.Op {
+	return txn.Op{
+		C:      actionNotificationsC,
+		Id:     a.st.docID(ensureActionMarker(a.Receiver()) + a.Id()),
+		Remove: true,
+	}
+}
+
 // newAction builds an Action for the given State and actionDoc.
 func newAction(st *State, adoc actionDoc) Action {
 	return &action{
@@ -246,8 +479,9 @@ func newAction(st *State, adoc actionDoc) Action {
 	}
 }
 
-// newActionDoc builds the actionDoc with the given name and parameters.
-func newActionDoc(st *State, receiverTag names.Tag, actionName string, parameters map[string]interface{}) (actionDoc, actionNotificationDoc, error) {
+// newActionDoc builds the actionDoc with the given name, parameters
+// and options.
+func newActionDoc(st *State, receiverTag names.Tag, actionName string, parameters map[string]interface{}, options ActionOptions) (actionDoc, actionNotificationDoc, error) {
 	prefix := ensureActionMarker(receiverTag.Id())
 	actionId, err := NewUUID()
 	if err != nil {
@@ -256,13 +490,16 @@ func newActionDoc(st *State, receiverTag names.Tag, actionName string, parameter
 	actionLogger.Debugf("newActionDoc name: '%s', receiver: '%s', actionId: '%s'", actionName, receiverTag, actionId)
 	modelUUID := st.ModelUUID()
 	return actionDoc{
-			DocId:      st.docID(actionId.String()),
-			ModelUUID:  modelUUID,
-			Receiver:   receiverTag.Id(),
-			Name:       actionName,
-			Parameters: parameters,
-			Enqueued:   st.NowToTheSecond(),
-			Status:     ActionPending,
+			DocId:        st.docID(actionId.String()),
+			ModelUUID:    modelUUID,
+			Receiver:     receiverTag.Id(),
+			Application:  actionApplication(receiverTag.Id()),
+			Name:         actionName,
+			Parameters:   parameters,
+			Enqueued:     st.NowToTheSecond(),
+			Timeout:      options.Timeout,
+			StreamOutput: options.StreamOutput,
+			Status:       ActionPending,
 		}, actionNotificationDoc{
 			DocId:     st.docID(prefix + actionId.String()),
 			ModelUUID: modelUUID,
@@ -273,6 +510,19 @@ func newActionDoc(st *State, receiverTag names.Tag, actionName string, parameter
 
 var ensureActionMarker = ensureSuffixFn(actionMarker)
 
+// actionApplication returns the name of the application whose unit
+// receives an action, or "" if the receiver is not a unit.
+func actionApplication(receiver string) string {
+	if !names.IsValidUnit(receiver) {
+		return ""
+	}
+	application, err := names.UnitApplication(receiver)
+	if err != nil {
+		return ""
+	}
+	return application
+}
+
 // Action returns an Action by Id, which is a UUID.
 func (st *State) Action(id string) (Action, error) {
 	actionLogger.Tracef("Action() %q", id)
@@ -354,18 +604,70 @@ func (st *State) FindActionsByName(name string) ([]Action, error) {
 	return results, errors.Trace(iter.Close())
 }
 
-// EnqueueAction
+// ActionOptions holds the optional attributes of an action being
+// enqueued.
+type ActionOptions struct {
+	// Timeout, if non-zero, holds how long the action may run before
+	// the unit agent stops it.
+	Timeout time.Duration
+
+	// StreamOutput, if true, has the unit agent log the output of the
+	// action as messages while it runs.
+	StreamOutput bool
+}
+
+// Validate returns an error if the options are not valid.
+func (o ActionOptions) Validate() error {
+	if o.Timeout < 0 {
+		return errors.NotValidf("negative timeout %v", o.Timeout)
+	}
+	return nil
+}
+
+// actionSpecTimeout returns the default timeout of an action, given by
+// the "timeout" field of its entry in the charm's actions.yaml, or zero
+// if there is none. The charm keeps the field in the action's schema.
+func actionSpecTimeout(spec charm.ActionSpec) (time.Duration, error) {
+	value, ok := spec.Params["timeout"]
+	if !ok {
+		return 0, nil
+	}
+	text, ok := value.(string)
+	if !ok {
+		return 0, errors.NotValidf("timeout %v", value)
+	}
+	timeout, err := time.ParseDuration(text)
+	if err != nil {
+		return 0, errors.NewNotValid(err, "invalid timeout")
+	}
+	if timeout < 0 {
+		return 0, errors.NotValidf("negative timeout %v", timeout)
+	}
+	return timeout, nil
+}
+
+// EnqueueAction queues an action with the given name and payload for
+// the given receiver.
 func (st *State) EnqueueAction(receiver names.Tag, actionName string, payload map[string]interface{}) (Action, error) {
+	return st.EnqueueActionWithOptions(receiver, actionName, payload, ActionOptions{})
+}
+
+// EnqueueActionWithOptions queues an action with the given name,
+// payload and options for the given receiver.
+func (st *State) EnqueueActionWithOptions(receiver names.Tag, actionName string, payload map[string]interface{}, options ActionOptions) (Action, error) {
 	if len(actionName) == 0 {
 		return nil, errors.New("action name required")
 	}
+	if err := options.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
 
 	receiverCollectionName, receiverId, err := st.tagToCollectionAndId(receiver)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
 
-	doc, ndoc, err := newActionDoc(st, receiver, actionName, payload)
+	doc, ndoc, err := newActionDoc(st, receiver, actionName, payload, options)
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
@@ -432,7 +734,7 @@ func (st *State) matchingActionsPending(ar ActionReceiver) ([]Action, error) {
 // matchingActionsRunning finds actions that match ActionReceiver and
 // that are running.
 func (st *State) matchingActionsRunning(ar ActionReceiver) ([]Action, error) {
-	completed := bson.D{{"status", ActionRunning}}
+	completed := bson.D{{"status", bson.D{{"$in", []ActionStatus{ActionRunning, ActionAborting}}}}}
 	return st.matchingActionsByReceiverAndStatus(ar.Tag(), completed)
 }
 
@@ -443,6 +745,7 @@ func (st *State) matchingActionsCompleted(ar ActionReceiver) ([]Action, error) {
 		{{"status", ActionCompleted}},
 		{{"status", ActionCancelled}},
 		{{"status", ActionFailed}},
+		{{"status", ActionTimedOut}},
 	}}}
 	return st.matchingActionsByReceiverAndStatus(ar.Tag(), completed)
 }
@@ -7,6 +7,7 @@ import (
 	"encoding/hex"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
@@ -463,6 +464,225 @@ func (s *ActionSuite) TestComplete(c *gc.C) {
 	c.Assert(len(actions), gc.Equals, 0)
 }
 
+func (s *ActionSuite) TestAddActionWithTimeout(c *gc.C) {
+	a, err := s.unit.AddActionWithOptions("snapshot", nil, state.ActionOptions{
+		Timeout: 10 * time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(a.Timeout(), gc.Equals, 10*time.Minute)
+
+	action, err := s.State.Action(a.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Timeout(), gc.Equals, 10*time.Minute)
+
+	a, err = s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(a.Timeout(), gc.Equals, time.Duration(0))
+}
+
+func (s *ActionSuite) TestAddActionWithNegativeTimeout(c *gc.C) {
+	_, err := s.unit.AddActionWithOptions("snapshot", nil, state.ActionOptions{
+		Timeout: -time.Second,
+	})
+	c.Assert(err, gc.ErrorMatches, "negative timeout -1s not valid")
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ActionSuite) addActionsUnit(c *gc.C, actionsYaml string) *state.Unit {
+	ch := s.AddActionsCharm(c, "dummy", actionsYaml, 2)
+	svc := s.AddTestingService(c, "timeout", ch)
+	curl, _ := svc.CharmURL()
+	unit, err := svc.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetCharmURL(curl)
+	c.Assert(err, jc.ErrorIsNil)
+	return unit
+}
+
+func (s *ActionSuite) TestAddActionWithCharmTimeout(c *gc.C) {
+	unit := s.addActionsUnit(c, "snapshot:\n  timeout: 90s\n")
+
+	a, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(a.Timeout(), gc.Equals, 90*time.Second)
+
+	// A timeout given when the action is enqueued overrides the
+	// charm's default.
+	a, err = unit.AddActionWithOptions("snapshot", nil, state.ActionOptions{
+		Timeout: 10 * time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(a.Timeout(), gc.Equals, 10*time.Minute)
+}
+
+func (s *ActionSuite) TestAddActionWithBadCharmTimeout(c *gc.C) {
+	unit := s.addActionsUnit(c, "snapshot:\n  timeout: soon\n")
+
+	_, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, gc.ErrorMatches, `action "snapshot": invalid timeout: .*`)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ActionSuite) TestTimedOut(c *gc.C) {
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, unit)
+
+	a, err := unit.AddActionWithOptions("snapshot", nil, state.ActionOptions{
+		Timeout: time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	action, err := a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := action.Finish(state.ActionResults{
+		Status:  state.ActionTimedOut,
+		Message: "action timed out after 1m0s",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Status(), gc.Equals, state.ActionTimedOut)
+
+	// A timed out action is complete, and cannot be finished again.
+	results, err := unit.CompletedActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(results, gc.HasLen, 1)
+	c.Assert(results[0].Id(), gc.Equals, a.Id())
+	running, err := unit.RunningActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(running, gc.HasLen, 0)
+
+	_, err = action.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, gc.ErrorMatches, "action .* is already timedout")
+}
+
+func (s *ActionSuite) TestCancelPending(c *gc.C) {
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, unit)
+
+	a, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	result, err := a.Cancel("no longer needed")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Status(), gc.Equals, state.ActionCancelled)
+	_, message := result.Results()
+	c.Assert(message, gc.Equals, "no longer needed")
+
+	pending, err := unit.PendingActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(pending, gc.HasLen, 0)
+
+	_, err = result.Cancel("again")
+	c.Assert(err, gc.ErrorMatches, `cannot cancel action: action .* is already cancelled`)
+}
+
+func (s *ActionSuite) TestCancelRunning(c *gc.C) {
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, unit)
+
+	a, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err := a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Cancelling a running action leaves it to the unit agent to stop.
+	aborting, err := unit.CancelAction(action)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(aborting.Status(), gc.Equals, state.ActionAborting)
+	running, err := unit.RunningActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(running, gc.HasLen, 1)
+
+	// Cancelling it again changes nothing.
+	aborting, err = aborting.Cancel("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(aborting.Status(), gc.Equals, state.ActionAborting)
+
+	// The unit agent finishes it once it has stopped it.
+	output := map[string]interface{}{"progress": "half done"}
+	result, err := aborting.Finish(state.ActionResults{
+		Status:  state.ActionCancelled,
+		Results: output,
+		Message: "action cancelled",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(result.Status(), gc.Equals, state.ActionCancelled)
+	res, _ := result.Results()
+	c.Assert(res, jc.DeepEquals, output)
+	running, err = unit.RunningActions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(running, gc.HasLen, 0)
+}
+
+func (s *ActionSuite) TestLog(c *gc.C) {
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, unit)
+
+	a, err := unit.AddActionWithOptions("snapshot", nil, state.ActionOptions{
+		StreamOutput: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(a.StreamOutput(), jc.IsTrue)
+
+	// Only running actions can log messages.
+	err = a.Log("too soon")
+	c.Assert(err, gc.ErrorMatches, "cannot log message to action .*: action is pending")
+
+	action, err := a.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	err = action.Log("first")
+	c.Assert(err, jc.ErrorIsNil)
+	err = action.Log("second", "third")
+	c.Assert(err, jc.ErrorIsNil)
+
+	action, err = s.State.Action(a.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	messages := action.Messages()
+	c.Assert(messages, gc.HasLen, 3)
+	for i, expect := range []string{"first", "second", "third"} {
+		c.Check(messages[i].Sequence, gc.Equals, i+1)
+		c.Check(messages[i].Message, gc.Equals, expect)
+		c.Check(messages[i].Timestamp.IsZero(), jc.IsFalse)
+	}
+
+	_, err = action.Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	err = action.Log("too late")
+	c.Assert(err, gc.ErrorMatches, "cannot log message to action .*: action is completed")
+}
+
+func (s *ActionSuite) TestUnitWatchActionCancellations(c *gc.C) {
+	unit, err := s.State.Unit(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	preventUnitDestroyRemove(c, unit)
+
+	a1, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	a2, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	running, err := a1.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	w := unit.WatchActionCancellations()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange()
+	wc.AssertNoChange()
+
+	// Cancelling a pending action does not involve the unit agent.
+	_, err = a2.Cancel("")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertNoChange()
+
+	_, err = running.Cancel("")
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertChange(a1.Id())
+	wc.AssertNoChange()
+}
+
 func (s *ActionSuite) TestFindActionTagsByPrefix(c *gc.C) {
 	prefix := "feedbeef"
 	uuidMock := uuidMockHelper{}
@@ -890,6 +1110,9 @@ var _ state.ActionReceiver = (*mockAR)(nil)
 func (r mockAR) AddAction(name string, payload map[string]interface{}) (state.Action, error) {
 	return nil, nil
 }
+func (r mockAR) AddActionWithOptions(string, map[string]interface{}, state.ActionOptions) (state.Action, error) {
+	return nil, nil
+}
 func (r mockAR) CancelAction(state.Action) (state.Action, error) { return nil, nil }
 func (r mockAR) WatchActionNotifications() state.StringsWatcher  { return nil }
 func (r mockAR) Actions() ([]state.Action, error)                { return nil, nil }
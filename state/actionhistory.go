@@ -0,0 +1,289 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/dustin/go-humanize"
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+const (
+	// actionPruneBatchSize is the number of documents removed by each
+	// transaction when pruning actions and operations.
+	actionPruneBatchSize = 100
+
+	// defaultActionQueryLimit is the number of actions QueryActions
+	// returns when the query does not limit them.
+	defaultActionQueryLimit = 100
+
+	// maxActionQueryLimit is the most actions QueryActions returns at
+	// once; more are read a page at a time using ActionQuery.After.
+	maxActionQueryLimit = 1000
+)
+
+// finishedActionStatuses holds the statuses of actions that will not
+// change again.
+var finishedActionStatuses = []ActionStatus{
+	ActionCompleted,
+	ActionCancelled,
+	ActionFailed,
+	ActionTimedOut,
+}
+
+// PruneActions removes the model's finished actions until only those
+// that finished less than maxHistoryTime ago remain, and until the
+// model's actions are estimated to use no more than maxHistoryMB.
+// Pending and running actions are never removed. Operations are
+// removed once all of their actions have been.
+func PruneActions(st *State, maxHistoryTime time.Duration, maxHistoryMB int) error {
+	if maxHistoryMB < 0 {
+		return errors.NotValidf("non-positive maxHistoryMB")
+	}
+	if maxHistoryTime < 0 {
+		return errors.NotValidf("non-positive maxHistoryTime")
+	}
+	if maxHistoryMB == 0 && maxHistoryTime == 0 {
+		return errors.NotValidf("backlog size and time constraints are both 0")
+	}
+
+	// NOTE: we require a raw collection to obtain the size of the
+	// collection. Take care to include model-uuid in queries.
+	actions, closer := st.getRawCollection(actionsC)
+	defer closer()
+	finished := bson.D{
+		{"model-uuid", st.ModelUUID()},
+		{"status", bson.D{{"$in", finishedActionStatuses}}},
+	}
+
+	if maxHistoryTime > 0 {
+		t := st.clock.Now().Add(-maxHistoryTime)
+		var docs []bson.M
+		err := actions.Find(append(finished, bson.DocElem{
+			"completed", bson.D{{"$lt", t}},
+		})).Select(bson.D{{"_id", 1}}).All(&docs)
+		if err != nil {
+			return errors.Annotate(err, "pruning actions by age")
+		}
+		if err := runPruneOps(st, removeFinishedActionsOps(docs)); err != nil {
+			return errors.Annotate(err, "pruning actions by age")
+		}
+	}
+	if maxHistoryMB > 0 {
+		err := pruneActionsBySize(st, actions, finished, maxHistoryMB)
+		if err != nil {
+			return errors.Annotate(err, "pruning actions by size")
+		}
+	}
+	return errors.Annotate(pruneOperations(st), "pruning operations")
+}
+
+// pruneActionsBySize removes the oldest of a model's finished actions
+// until its actions are estimated to use no more than maxHistoryMB.
+func pruneActionsBySize(st *State, actions *mgo.Collection, finished bson.D, maxHistoryMB int) error {
+	count, err := actions.Find(bson.D{{"model-uuid", st.ModelUUID()}}).Count()
+	if err != nil {
+		return errors.Trace(err)
+	}
+	avgSize, err := getAverageDocSize(actions)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if avgSize <= 0 {
+		return nil
+	}
+	maxCount := int(float64(maxHistoryMB) * humanize.MiByte / avgSize)
+	if count <= maxCount {
+		return nil
+	}
+
+	var docs []bson.M
+	err = actions.Find(finished).Sort("completed").Limit(count - maxCount).Select(bson.D{{"_id", 1}}).All(&docs)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(runPruneOps(st, removeFinishedActionsOps(docs)))
+}
+
+// removeFinishedActionsOps returns the operations that remove the
+// actions with the given documents' ids, asserting that they have
+// finished.
+func removeFinishedActionsOps(docs []bson.M) []txn.Op {
+	ops := make([]txn.Op, len(docs))
+	for i, doc := range docs {
+		ops[i] = txn.Op{
+			C:      actionsC,
+			Id:     doc["_id"],
+			Assert: bson.D{{"status", bson.D{{"$in", finishedActionStatuses}}}},
+			Remove: true,
+		}
+	}
+	return ops
+}
+
+// pruneOperations removes the model's operations none of whose actions
+// remain. Only finished actions are pruned, so those operations have
+// finished too.
+func pruneOperations(st *State) error {
+	operations, closer := st.getCollection(operationsC)
+	defer closer()
+	actions, closer := st.getCollection(actionsC)
+	defer closer()
+
+	var ops []txn.Op
+	var doc operationDoc
+	iter := operations.Find(nil).Select(bson.D{{"action-ids", 1}}).Iter()
+	for iter.Next(&doc) {
+		ids := make([]string, len(doc.ActionIds))
+		for i, id := range doc.ActionIds {
+			ids[i] = st.docID(id)
+		}
+		remaining, err := actions.Find(bson.D{{"_id", bson.D{{"$in", ids}}}}).Count()
+		if err != nil {
+			iter.Close()
+			return errors.Trace(err)
+		}
+		if remaining > 0 {
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:      operationsC,
+			Id:     doc.DocId,
+			Assert: txn.DocExists,
+			Remove: true,
+		})
+	}
+	if err := iter.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	return errors.Trace(runPruneOps(st, ops))
+}
+
+// runPruneOps runs the given removals in batches of
+// actionPruneBatchSize. A batch is skipped if any of its documents
+// changed concurrently; they are considered again when next pruned.
+func runPruneOps(st *State, ops []txn.Op) error {
+	for len(ops) > 0 {
+		batch := ops
+		if len(batch) > actionPruneBatchSize {
+			batch = batch[:actionPruneBatchSize]
+		}
+		ops = ops[len(batch):]
+		if err := st.runTransaction(batch); err != nil && err != txn.ErrAborted {
+			return errors.Trace(err)
+		}
+	}
+	return nil
+}
+
+// ActionQuery holds the filters for QueryActions. Empty fields match
+// every action.
+type ActionQuery struct {
+	// Applications holds the names of the applications whose units'
+	// actions match.
+	Applications []string
+
+	// ActionNames holds the names of the actions that match.
+	ActionNames []string
+
+	// Statuses holds the statuses of the actions that match.
+	Statuses []ActionStatus
+
+	// EnqueuedAfter, if not zero, matches actions enqueued at or after
+	// that time.
+	EnqueuedAfter time.Time
+
+	// EnqueuedBefore, if not zero, matches actions enqueued before that
+	// time.
+	EnqueuedBefore time.Time
+
+	// After, if set, is the id of the last action of the previous page
+	// of results; only the matching actions that follow it are
+	// returned.
+	After string
+
+	// Limit is the maximum number of actions returned. If it is zero,
+	// at most 100 are returned; it may not be more than 1000.
+	Limit int
+}
+
+// Validate returns an error if the query is not valid.
+func (q ActionQuery) Validate() error {
+	if q.Limit < 0 {
+		return errors.NotValidf("negative limit %d", q.Limit)
+	}
+	if q.Limit > maxActionQueryLimit {
+		return errors.NotValidf("limit %d greater than %d", q.Limit, maxActionQueryLimit)
+	}
+	return nil
+}
+
+// selector returns the MongoDB selector for the query's filters.
+func (q ActionQuery) selector() bson.D {
+	sel := bson.D{}
+	if len(q.Applications) > 0 {
+		sel = append(sel, bson.DocElem{"application", bson.D{{"$in", q.Applications}}})
+	}
+	if len(q.ActionNames) > 0 {
+		sel = append(sel, bson.DocElem{"name", bson.D{{"$in", q.ActionNames}}})
+	}
+	if len(q.Statuses) > 0 {
+		sel = append(sel, bson.DocElem{"status", bson.D{{"$in", q.Statuses}}})
+	}
+	enqueued := bson.D{}
+	if !q.EnqueuedAfter.IsZero() {
+		enqueued = append(enqueued, bson.DocElem{"$gte", q.EnqueuedAfter})
+	}
+	if !q.EnqueuedBefore.IsZero() {
+		enqueued = append(enqueued, bson.DocElem{"$lt", q.EnqueuedBefore})
+	}
+	if len(enqueued) > 0 {
+		sel = append(sel, bson.DocElem{"enqueued", enqueued})
+	}
+	return sel
+}
+
+// QueryActions returns a page of the model's actions that match the
+// query, most recently enqueued first.
+func (st *State) QueryActions(query ActionQuery) ([]Action, error) {
+	if err := query.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	sel := query.selector()
+	if query.After != "" {
+		after, err := st.Action(query.After)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		doc := after.(*action).doc
+		sel = append(sel, bson.DocElem{"$or", []bson.D{
+			{{"enqueued", bson.D{{"$lt", doc.Enqueued}}}},
+			{{"enqueued", doc.Enqueued}, {"_id", bson.D{{"$lt", doc.DocId}}}},
+		}})
+	}
+	limit := query.Limit
+	if limit == 0 {
+		limit = defaultActionQueryLimit
+	}
+
+	actions, closer := st.getCollection(actionsC)
+	defer closer()
+
+	// Actions are enqueued to the second, so they are ordered by id
+	// too, giving each page a well defined place to start from.
+	iter := actions.Find(sel).Sort("-enqueued", "-_id").Limit(limit).Iter()
+	var results []Action
+	var doc actionDoc
+	for iter.Next(&doc) {
+		results = append(results, newAction(st, doc))
+	}
+	if err := iter.Close(); err != nil {
+		return nil, errors.Annotate(err, "cannot query actions")
+	}
+	return results, nil
+}
@@ -0,0 +1,297 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"strings"
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+)
+
+type ActionHistorySuite struct {
+	ConnSuite
+	unit      *state.Unit
+	otherUnit *state.Unit
+}
+
+var _ = gc.Suite(&ActionHistorySuite{})
+
+func (s *ActionHistorySuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+
+	charm := s.AddTestingCharm(c, "dummy")
+	s.unit = s.addUnit(c, s.AddTestingService(c, "dummy", charm))
+	s.otherUnit = s.addUnit(c, s.AddTestingService(c, "dummy2", charm))
+}
+
+func (s *ActionHistorySuite) addUnit(c *gc.C, application *state.Application) *state.Unit {
+	curl, _ := application.CharmURL()
+	unit, err := application.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+	err = unit.SetCharmURL(curl)
+	c.Assert(err, jc.ErrorIsNil)
+	return unit
+}
+
+func (s *ActionHistorySuite) finishedAction(c *gc.C, unit *state.Unit, status state.ActionStatus, results map[string]interface{}) state.Action {
+	action, err := unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err = action.Finish(state.ActionResults{Status: status, Results: results})
+	c.Assert(err, jc.ErrorIsNil)
+	return action
+}
+
+func (s *ActionHistorySuite) assertActionIds(c *gc.C, actions []state.Action, expect ...state.Action) {
+	ids := make([]string, len(actions))
+	for i, action := range actions {
+		ids[i] = action.Id()
+	}
+	expectIds := make([]string, len(expect))
+	for i, action := range expect {
+		expectIds[i] = action.Id()
+	}
+	c.Assert(ids, jc.DeepEquals, expectIds)
+}
+
+func (s *ActionHistorySuite) TestPruneActionsValidation(c *gc.C) {
+	err := state.PruneActions(s.State, 0, 0)
+	c.Assert(err, gc.ErrorMatches, "backlog size and time constraints are both 0 not valid")
+	err = state.PruneActions(s.State, -time.Hour, 0)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+	err = state.PruneActions(s.State, 0, -1)
+	c.Assert(err, jc.Satisfies, errors.IsNotValid)
+}
+
+func (s *ActionHistorySuite) TestPruneActionsByAge(c *gc.C) {
+	old := s.finishedAction(c, s.unit, state.ActionCompleted, nil)
+	oldPending, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	s.Clock.Advance(2 * time.Hour)
+	recent := s.finishedAction(c, s.unit, state.ActionFailed, nil)
+
+	err = state.PruneActions(s.State, time.Hour, 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.Action(old.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.Action(oldPending.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Action(recent.Id())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ActionHistorySuite) TestPruneActionsByAgeRemovesOperations(c *gc.C) {
+	op, err := s.State.EnqueueOperation(state.OperationArgs{
+		Application: "dummy",
+		Units:       []*state.Unit{s.unit},
+		ActionName:  "snapshot",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	actions, err := op.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = actions[0].Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	s.Clock.Advance(2 * time.Hour)
+
+	err = state.PruneActions(s.State, time.Hour, 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.Operation(op.Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.Action(actions[0].Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ActionHistorySuite) TestPruneActionsKeepsOperationsHoldingActions(c *gc.C) {
+	application, err := s.unit.Application()
+	c.Assert(err, jc.ErrorIsNil)
+	op, err := s.State.EnqueueOperation(state.OperationArgs{
+		Application: "dummy",
+		Units:       []*state.Unit{s.unit, s.addUnit(c, application)},
+		ActionName:  "snapshot",
+		BatchSize:   1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.Clock.Advance(2 * time.Hour)
+
+	err = state.PruneActions(s.State, time.Hour, 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	op, err = s.State.Operation(op.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	actions, err := op.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actions, gc.HasLen, 2)
+}
+
+func (s *ActionHistorySuite) TestPruneActionsKeepsOperationsWithRunningActions(c *gc.C) {
+	op, err := s.State.EnqueueOperation(state.OperationArgs{
+		Application: "dummy",
+		Units:       []*state.Unit{s.unit},
+		ActionName:  "snapshot",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	actions, err := op.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = actions[0].Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	s.Clock.Advance(2 * time.Hour)
+
+	err = state.PruneActions(s.State, time.Hour, 0)
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.Operation(op.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.Action(actions[0].Id())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ActionHistorySuite) TestPruneActionsBySizeRemovesOperations(c *gc.C) {
+	results := map[string]interface{}{"data": strings.Repeat("x", 16*1024)}
+	var ops []*state.Operation
+	for i := 0; i < 100; i++ {
+		op, err := s.State.EnqueueOperation(state.OperationArgs{
+			Application: "dummy",
+			Units:       []*state.Unit{s.unit},
+			ActionName:  "snapshot",
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		actions, err := op.Actions()
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = actions[0].Finish(state.ActionResults{Status: state.ActionCompleted, Results: results})
+		c.Assert(err, jc.ErrorIsNil)
+		ops = append(ops, op)
+		s.Clock.Advance(time.Second)
+	}
+
+	err := state.PruneActions(s.State, 0, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The oldest operation's action was pruned, and so was it; the
+	// newest operation's action remains, and so does it.
+	_, err = s.State.Operation(ops[0].Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = s.State.Operation(ops[99].Id())
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *ActionHistorySuite) TestPruneActionsBySize(c *gc.C) {
+	results := map[string]interface{}{"data": strings.Repeat("x", 16*1024)}
+	var actions []state.Action
+	for i := 0; i < 200; i++ {
+		actions = append(actions, s.finishedAction(c, s.unit, state.ActionCompleted, results))
+		s.Clock.Advance(time.Second)
+	}
+	pending, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = state.PruneActions(s.State, 0, 1)
+	c.Assert(err, jc.ErrorIsNil)
+
+	remaining, err := s.State.QueryActions(state.ActionQuery{Limit: 1000})
+	c.Assert(err, jc.ErrorIsNil)
+	// The size of the documents is only estimated, so just check that
+	// the oldest actions went and the newest and pending ones stayed.
+	c.Assert(len(remaining), jc.LessThan, 100)
+	c.Assert(remaining[0].Id(), gc.Equals, pending.Id())
+	c.Assert(remaining[1].Id(), gc.Equals, actions[199].Id())
+	_, err = s.State.Action(actions[0].Id())
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ActionHistorySuite) TestQueryActions(c *gc.C) {
+	first := s.finishedAction(c, s.unit, state.ActionCompleted, nil)
+	s.Clock.Advance(time.Minute)
+	second := s.finishedAction(c, s.otherUnit, state.ActionFailed, nil)
+	s.Clock.Advance(time.Minute)
+	third, err := s.unit.AddAction("snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	enqueued := first.Enqueued()
+
+	for i, test := range []struct {
+		query  state.ActionQuery
+		expect []state.Action
+	}{{
+		query:  state.ActionQuery{},
+		expect: []state.Action{third, second, first},
+	}, {
+		query:  state.ActionQuery{Applications: []string{"dummy"}},
+		expect: []state.Action{third, first},
+	}, {
+		query:  state.ActionQuery{Applications: []string{"dummy", "dummy2"}},
+		expect: []state.Action{third, second, first},
+	}, {
+		query:  state.ActionQuery{ActionNames: []string{"other"}},
+		expect: []state.Action{},
+	}, {
+		query:  state.ActionQuery{Statuses: []state.ActionStatus{state.ActionCompleted, state.ActionFailed}},
+		expect: []state.Action{second, first},
+	}, {
+		query:  state.ActionQuery{EnqueuedAfter: enqueued.Add(time.Minute)},
+		expect: []state.Action{third, second},
+	}, {
+		query:  state.ActionQuery{EnqueuedBefore: enqueued.Add(time.Minute)},
+		expect: []state.Action{first},
+	}, {
+		query:  state.ActionQuery{Limit: 1},
+		expect: []state.Action{third},
+	}, {
+		query:  state.ActionQuery{After: third.Id(), Limit: 1},
+		expect: []state.Action{second},
+	}, {
+		query:  state.ActionQuery{After: second.Id()},
+		expect: []state.Action{first},
+	}} {
+		c.Logf("test %d: %+v", i, test.query)
+		actions, err := s.State.QueryActions(test.query)
+		c.Assert(err, jc.ErrorIsNil)
+		s.assertActionIds(c, actions, test.expect...)
+	}
+}
+
+func (s *ActionHistorySuite) TestQueryActionsPages(c *gc.C) {
+	// Actions enqueued within the same second are still paged through
+	// in a consistent order.
+	var all []state.Action
+	for i := 0; i < 150; i++ {
+		action, err := s.unit.AddAction("snapshot", nil)
+		c.Assert(err, jc.ErrorIsNil)
+		all = append(all, action)
+	}
+
+	// At most 100 actions are returned by default.
+	page, err := s.State.QueryActions(state.ActionQuery{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(page, gc.HasLen, 100)
+
+	seen := make(map[string]bool)
+	var after string
+	for {
+		page, err := s.State.QueryActions(state.ActionQuery{After: after, Limit: 40})
+		c.Assert(err, jc.ErrorIsNil)
+		for _, action := range page {
+			c.Assert(seen[action.Id()], jc.IsFalse)
+			seen[action.Id()] = true
+		}
+		if len(page) < 40 {
+			break
+		}
+		after = page[len(page)-1].Id()
+	}
+	c.Assert(seen, gc.HasLen, len(all))
+}
+
+func (s *ActionHistorySuite) TestQueryActionsInvalid(c *gc.C) {
+	_, err := s.State.QueryActions(state.ActionQuery{Limit: -1})
+	c.Assert(err, gc.ErrorMatches, "negative limit -1 not valid")
+	_, err = s.State.QueryActions(state.ActionQuery{Limit: 1001})
+	c.Assert(err, gc.ErrorMatches, "limit 1001 greater than 1000 not valid")
+	_, err = s.State.QueryActions(state.ActionQuery{After: "missing"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
@@ -19,6 +19,14 @@ var (
 	txnLogSizeTests = 1000000
 )
 
+// The capped collection used for audit entries defaults to 500MB; once
+// full, the oldest entries are discarded. As with the transaction log,
+// it's reduced in export_test.go.
+var (
+	auditLogSize      = 500000000
+	auditLogSizeTests = 1000000
+)
+
 // allCollections should be the single source of truth for information about
 // any collection we use. It's broken up into 4 main sections:
 //
@@ -343,9 +351,18 @@ func allCollections() collectionSchema {
 		actionsC: {
 			indexes: []mgo.Index{{
 				Key: []string{"model-uuid", "name"},
+			}, {
+				// These indexes include _id so that they can be
+				// used when paging through actions.
+				Key: []string{"model-uuid", "enqueued", "_id"},
+			}, {
+				Key: []string{"model-uuid", "application", "enqueued", "_id"},
+			}, {
+				Key: []string{"model-uuid", "status", "completed"},
 			}},
 		},
 		actionNotificationsC: {},
+		operationsC:          {},
 
 		// -----
 
@@ -408,9 +425,23 @@ func allCollections() collectionSchema {
 
 		// metrics; status-history; logs; ..?
 
+		// This collection holds audit entries recorded by the API
+		// server for all models. It is capped so that it can't grow
+		// without bound.
 		auditingC: {
 			global:    true,
 			rawAccess: true,
+			explicitCreate: &mgo.CollectionInfo{
+				Capped:   true,
+				MaxBytes: auditLogSize,
+			},
+			indexes: []mgo.Index{{
+				Key: []string{"model-uuid", "time"},
+			}, {
+				Key: []string{"origin-name", "time"},
+			}, {
+				Key: []string{"time"},
+			}},
 		},
 	}
 	if featureflag.Enabled(feature.CrossModelRelations) {
@@ -492,6 +523,7 @@ const (
 	modelsC                  = "models"
 	modelEntityRefsC         = "modelEntityRefs"
 	openedPortsC             = "openedPorts"
+	operationsC              = "operations"
 	payloadsC                = "payloads"
 	permissionsC             = "permissions"
 	providerIDsC             = "providerIDs"
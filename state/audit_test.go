@@ -0,0 +1,102 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"time"
+
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/state"
+)
+
+type AuditSuite struct {
+	ConnSuite
+}
+
+var _ = gc.Suite(&AuditSuite{})
+
+func (s *AuditSuite) putEntries(c *gc.C, t0 time.Time) []audit.AuditEntry {
+	put := s.State.PutAuditEntryFn()
+	var entries []audit.AuditEntry
+	for i, op := range []string{
+		"Client:v1 - FullStatus",
+		"Application:v3 - Destroy",
+		"Application:v3 - Deploy",
+	} {
+		entry := audit.AuditEntry{
+			JujuServerVersion: version.MustParse("2.2.0"),
+			ModelUUID:         s.State.ModelUUID(),
+			Timestamp:         t0.Add(time.Duration(i) * time.Minute),
+			RemoteAddress:     "10.0.0.1",
+			OriginType:        "API request",
+			OriginName:        "user-bob",
+			Operation:         op,
+			Data:              map[string]interface{}{"request-body": op},
+		}
+		c.Assert(put(entry), jc.ErrorIsNil)
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+// assertEntries checks that the found entries match those expected,
+// and have been given distinct IDs.
+func (s *AuditSuite) assertEntries(c *gc.C, found []audit.AuditEntry, expected []audit.AuditEntry) {
+	ids := make(map[string]bool)
+	for i, entry := range found {
+		c.Check(entry.ID, gc.Not(gc.Equals), "")
+		c.Check(ids[entry.ID], jc.IsFalse)
+		ids[entry.ID] = true
+		found[i].ID = ""
+	}
+	c.Assert(found, jc.DeepEquals, expected)
+}
+
+func (s *AuditSuite) TestAuditEntriesAll(c *gc.C) {
+	t0 := time.Date(2017, time.March, 7, 10, 0, 0, 0, time.UTC)
+	entries := s.putEntries(c, t0)
+
+	found, err := s.State.AuditEntries(state.AuditEntryFilter{})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEntries(c, found, entries)
+}
+
+func (s *AuditSuite) TestAuditEntriesFiltered(c *gc.C) {
+	t0 := time.Date(2017, time.March, 7, 10, 0, 0, 0, time.UTC)
+	entries := s.putEntries(c, t0)
+
+	found, err := s.State.AuditEntries(state.AuditEntryFilter{
+		ModelUUID:  s.State.ModelUUID(),
+		OriginName: "user-bob",
+		Operation:  "Application:",
+		After:      t0.Add(time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEntries(c, found, entries[1:])
+
+	found, err = s.State.AuditEntries(state.AuditEntryFilter{
+		Before: t0.Add(time.Minute),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEntries(c, found, entries[:1])
+
+	found, err = s.State.AuditEntries(state.AuditEntryFilter{
+		OriginName: "user-mary",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(found, gc.HasLen, 0)
+}
+
+func (s *AuditSuite) TestAuditEntriesLimitPrefersRecent(c *gc.C) {
+	t0 := time.Date(2017, time.March, 7, 10, 0, 0, 0, time.UTC)
+	entries := s.putEntries(c, t0)
+
+	found, err := s.State.AuditEntries(state.AuditEntryFilter{Limit: 2})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertEntries(c, found, entries[1:])
+}
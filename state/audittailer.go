@@ -0,0 +1,189 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
+	"gopkg.in/tomb.v1"
+
+	"github.com/juju/juju/audit"
+)
+
+// auditTailerBatchSize is the maximum number of audit entries read
+// from the database at a time by an AuditTailer.
+const auditTailerBatchSize = 1000
+
+// defaultAuditTailerPollInterval is how long an AuditTailer waits
+// before checking for new entries, if not otherwise specified.
+const defaultAuditTailerPollInterval = 5 * time.Second
+
+// AuditTailer allows for retrieval of the audit entries recorded by
+// the controller. It first returns any matching entries already
+// recorded and then polls for additional entries as they appear.
+type AuditTailer interface {
+	// Entries returns the channel through which the AuditTailer
+	// returns audit entries. It will be closed when the tailer
+	// stops.
+	Entries() <-chan audit.AuditEntry
+
+	// Dying returns a channel which will be closed as the
+	// AuditTailer stops.
+	Dying() <-chan struct{}
+
+	// Stop is used to request that the AuditTailer stops. It blocks
+	// until the AuditTailer has stopped.
+	Stop() error
+
+	// Err returns the error that caused the AuditTailer to stop. If
+	// it hasn't stopped or stopped without error nil will be
+	// returned.
+	Err() error
+}
+
+// AuditTailerParams specifies which audit entries an AuditTailer
+// should return.
+type AuditTailerParams struct {
+	// StartTime is the time of the earliest entry to return.
+	StartTime time.Time
+
+	// AllModels indicates that entries for all models should be
+	// returned, rather than just those of the tailer's model.
+	AllModels bool
+
+	// Clock is used to wait between polls of the database.
+	Clock clock.Clock
+
+	// PollInterval is how long to wait before checking for new
+	// entries once all those recorded have been returned.
+	PollInterval time.Duration
+}
+
+// AuditTailerState describes the methods on State required for tailing
+// audit entries.
+type AuditTailerState interface {
+	// ModelUUID returns the UUID of the model the state is for.
+	ModelUUID() string
+
+	// IsController indicates whether or not the model is the admin model.
+	IsController() bool
+
+	// AuditEntries returns the audit entries matching the filter.
+	AuditEntries(AuditEntryFilter) ([]audit.AuditEntry, error)
+}
+
+// NewAuditTailer returns an AuditTailer which returns entries
+// according to the parameters given.
+func NewAuditTailer(st AuditTailerState, params AuditTailerParams) (AuditTailer, error) {
+	if !st.IsController() && params.AllModels {
+		return nil, errors.NewNotValid(nil, "not allowed to tail audit entries from all models: not a controller")
+	}
+	if params.Clock == nil {
+		return nil, errors.NotValidf("nil Clock")
+	}
+	if params.PollInterval <= 0 {
+		params.PollInterval = defaultAuditTailerPollInterval
+	}
+	t := &auditTailer{
+		st:      st,
+		params:  params,
+		entryCh: make(chan audit.AuditEntry),
+	}
+	go func() {
+		err := t.loop()
+		t.tomb.Kill(errors.Cause(err))
+		close(t.entryCh)
+		t.tomb.Done()
+	}()
+	return t, nil
+}
+
+type auditTailer struct {
+	tomb    tomb.Tomb
+	st      AuditTailerState
+	params  AuditTailerParams
+	entryCh chan audit.AuditEntry
+}
+
+// Entries implements the AuditTailer interface.
+func (t *auditTailer) Entries() <-chan audit.AuditEntry {
+	return t.entryCh
+}
+
+// Dying implements the AuditTailer interface.
+func (t *auditTailer) Dying() <-chan struct{} {
+	return t.tomb.Dying()
+}
+
+// Stop implements the AuditTailer interface.
+func (t *auditTailer) Stop() error {
+	t.tomb.Kill(nil)
+	return t.tomb.Wait()
+}
+
+// Err implements the AuditTailer interface.
+func (t *auditTailer) Err() error {
+	return t.tomb.Err()
+}
+
+func (t *auditTailer) loop() error {
+	filter := AuditEntryFilter{
+		After:  t.params.StartTime,
+		Limit:  auditTailerBatchSize,
+		Oldest: true,
+	}
+	if !t.params.AllModels {
+		filter.ModelUUID = t.st.ModelUUID()
+	}
+	// Each query starts at the timestamp of the last entry returned,
+	// so that entries recorded later with the same timestamp are not
+	// missed; sent holds the IDs of the entries already returned with
+	// that timestamp, so that they are not returned twice.
+	sent := make(map[string]bool)
+	for {
+		entries, err := t.st.AuditEntries(filter)
+		if err != nil {
+			return errors.Annotate(err, "reading audit entries")
+		}
+		var returned int
+		for _, entry := range entries {
+			if entry.Timestamp.Equal(filter.After) && sent[entry.ID] {
+				continue
+			}
+			select {
+			case <-t.tomb.Dying():
+				return errors.Trace(tomb.ErrDying)
+			case t.entryCh <- entry:
+			}
+			returned++
+			// Entries recorded later with an earlier timestamp (for
+			// example due to clock skew between controllers) will
+			// not be returned.
+			if entry.Timestamp.After(filter.After) {
+				filter.After = entry.Timestamp
+				sent = make(map[string]bool)
+			}
+			if entry.Timestamp.Equal(filter.After) {
+				sent[entry.ID] = true
+			}
+		}
+		if len(entries) == auditTailerBatchSize {
+			if returned == 0 {
+				// A whole batch shares the same timestamp, so the
+				// rest of the entries with it can't be read.
+				filter.After = filter.After.Add(time.Nanosecond)
+				sent = make(map[string]bool)
+			}
+			// There are probably more entries waiting.
+			continue
+		}
+		select {
+		case <-t.tomb.Dying():
+			return errors.Trace(tomb.ErrDying)
+		case <-t.params.Clock.After(t.params.PollInterval):
+		}
+	}
+}
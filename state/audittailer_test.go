@@ -0,0 +1,195 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/audit"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+)
+
+type AuditTailerSuite struct {
+	testing.IsolationSuite
+
+	clock *testing.Clock
+	st    *stubAuditTailerState
+	t0    time.Time
+}
+
+var _ = gc.Suite(&AuditTailerSuite{})
+
+func (s *AuditTailerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.t0 = time.Date(2017, time.March, 7, 10, 0, 0, 0, time.UTC)
+	s.clock = testing.NewClock(s.t0)
+	s.st = &stubAuditTailerState{controller: true}
+}
+
+func (s *AuditTailerSuite) entry(offset time.Duration) audit.AuditEntry {
+	return audit.AuditEntry{
+		ID:                offset.String(),
+		JujuServerVersion: version.MustParse("2.2.0"),
+		ModelUUID:         coretesting.ModelTag.Id(),
+		Timestamp:         s.t0.Add(offset),
+		RemoteAddress:     "10.0.0.1",
+		OriginType:        "API request",
+		OriginName:        "user-bob",
+		Operation:         "Client:v1 - FullStatus",
+	}
+}
+
+func (s *AuditTailerSuite) newTailer(c *gc.C, allModels bool) state.AuditTailer {
+	tailer, err := state.NewAuditTailer(s.st, state.AuditTailerParams{
+		StartTime:    s.t0,
+		AllModels:    allModels,
+		Clock:        s.clock,
+		PollInterval: time.Second,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return tailer
+}
+
+func (s *AuditTailerSuite) assertEntry(c *gc.C, tailer state.AuditTailer, expected audit.AuditEntry) {
+	select {
+	case entry, ok := <-tailer.Entries():
+		c.Assert(ok, jc.IsTrue)
+		c.Assert(entry, jc.DeepEquals, expected)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for audit entry")
+	}
+}
+
+func (s *AuditTailerSuite) TestAllModelsRequiresController(c *gc.C) {
+	s.st.controller = false
+	_, err := state.NewAuditTailer(s.st, state.AuditTailerParams{
+		AllModels: true,
+		Clock:     s.clock,
+	})
+	c.Assert(err, gc.ErrorMatches, "not allowed to tail audit entries from all models: not a controller")
+}
+
+func (s *AuditTailerSuite) TestTailing(c *gc.C) {
+	first := s.entry(0)
+	second := s.entry(time.Minute)
+	s.st.addEntries(first)
+
+	tailer := s.newTailer(c, true)
+	defer tailer.Stop()
+
+	s.assertEntry(c, tailer, first)
+	s.st.addEntries(second)
+	c.Assert(s.clock.WaitAdvance(time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.assertEntry(c, tailer, second)
+
+	c.Assert(tailer.Stop(), jc.ErrorIsNil)
+	filters := s.st.getFilters()
+	c.Assert(len(filters) >= 2, jc.IsTrue)
+	c.Check(filters[0], jc.DeepEquals, state.AuditEntryFilter{
+		After:  s.t0,
+		Limit:  1000,
+		Oldest: true,
+	})
+	c.Check(filters[1], jc.DeepEquals, state.AuditEntryFilter{
+		After:  first.Timestamp,
+		Limit:  1000,
+		Oldest: true,
+	})
+}
+
+func (s *AuditTailerSuite) TestTailingSameTimestamp(c *gc.C) {
+	first := s.entry(0)
+	second := s.entry(0)
+	second.ID = "second"
+	third := s.entry(time.Minute)
+	s.st.addEntries(first)
+
+	tailer := s.newTailer(c, true)
+	defer tailer.Stop()
+
+	s.assertEntry(c, tailer, first)
+	// An entry recorded later with the same timestamp is returned,
+	// but the first is not returned again.
+	s.st.addEntries(second, third)
+	c.Assert(s.clock.WaitAdvance(time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	s.assertEntry(c, tailer, second)
+	s.assertEntry(c, tailer, third)
+	c.Assert(s.clock.WaitAdvance(time.Second, coretesting.LongWait, 1), jc.ErrorIsNil)
+	select {
+	case entry := <-tailer.Entries():
+		c.Fatalf("unexpected entry %+v", entry)
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+func (s *AuditTailerSuite) TestSingleModel(c *gc.C) {
+	tailer := s.newTailer(c, false)
+	c.Assert(s.clock.WaitAdvance(0, coretesting.LongWait, 1), jc.ErrorIsNil)
+	c.Assert(tailer.Stop(), jc.ErrorIsNil)
+	c.Assert(s.st.getFilters()[0].ModelUUID, gc.Equals, coretesting.ModelTag.Id())
+}
+
+func (s *AuditTailerSuite) TestError(c *gc.C) {
+	s.st.err = errors.New("boom")
+	tailer := s.newTailer(c, true)
+	select {
+	case _, ok := <-tailer.Entries():
+		c.Assert(ok, jc.IsFalse)
+	case <-time.After(coretesting.LongWait):
+		c.Fatalf("timed out waiting for tailer to stop")
+	}
+	c.Assert(tailer.Err(), gc.ErrorMatches, "reading audit entries: boom")
+}
+
+type stubAuditTailerState struct {
+	mu         sync.Mutex
+	controller bool
+	entries    []audit.AuditEntry
+	filters    []state.AuditEntryFilter
+	err        error
+}
+
+func (s *stubAuditTailerState) addEntries(entries ...audit.AuditEntry) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries = append(s.entries, entries...)
+}
+
+func (s *stubAuditTailerState) getFilters() []state.AuditEntryFilter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]state.AuditEntryFilter(nil), s.filters...)
+}
+
+func (s *stubAuditTailerState) ModelUUID() string {
+	return coretesting.ModelTag.Id()
+}
+
+func (s *stubAuditTailerState) IsController() bool {
+	return s.controller
+}
+
+func (s *stubAuditTailerState) AuditEntries(filter state.AuditEntryFilter) ([]audit.AuditEntry, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.filters = append(s.filters, filter)
+	if s.err != nil {
+		return nil, s.err
+	}
+	var result []audit.AuditEntry
+	for _, entry := range s.entries {
+		if !entry.Timestamp.Before(filter.After) {
+			result = append(result, entry)
+		}
+	}
+	return result, nil
+}
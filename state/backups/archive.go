@@ -22,6 +22,7 @@ const (
 	filesBundle  = "root.tar"
 	dbDumpDir    = "dump"
 	metadataFile = "metadata.json"
+	manifestFile = "manifest.json"
 )
 
 var legacyVersion = version.Number{Major: 1, Minor: 20}
@@ -47,6 +48,10 @@ type ArchivePaths struct {
 
 	// MetadataFile is the path to the metadata file.
 	MetadataFile string
+
+	// ManifestFile is the path to the manifest of content checksums.
+	// Archives created before format version 1 have no manifest.
+	ManifestFile string
 }
 
 // NewCanonicalArchivePaths composes a new ArchivePaths with default
@@ -59,6 +64,7 @@ func NewCanonicalArchivePaths() ArchivePaths {
 		FilesBundle:  path.Join(contentDir, filesBundle),
 		DBDumpDir:    path.Join(contentDir, dbDumpDir),
 		MetadataFile: path.Join(contentDir, metadataFile),
+		ManifestFile: path.Join(contentDir, manifestFile),
 	}
 }
 
@@ -73,6 +79,7 @@ func NewNonCanonicalArchivePaths(rootDir string) ArchivePaths {
 		FilesBundle:  filepath.Join(rootDir, contentDir, filesBundle),
 		DBDumpDir:    filepath.Join(rootDir, contentDir, dbDumpDir),
 		MetadataFile: filepath.Join(rootDir, contentDir, metadataFile),
+		ManifestFile: filepath.Join(rootDir, contentDir, manifestFile),
 	}
 }
 
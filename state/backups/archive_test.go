@@ -24,6 +24,7 @@ func (s *archiveSuite) TestNewCanonoicalArchivePaths(c *gc.C) {
 	c.Check(ap.FilesBundle, gc.Equals, "juju-backup/root.tar")
 	c.Check(ap.DBDumpDir, gc.Equals, "juju-backup/dump")
 	c.Check(ap.MetadataFile, gc.Equals, "juju-backup/metadata.json")
+	c.Check(ap.ManifestFile, gc.Equals, "juju-backup/manifest.json")
 }
 
 func (s *archiveSuite) TestNewNonCanonicalArchivePaths(c *gc.C) {
@@ -33,4 +34,5 @@ func (s *archiveSuite) TestNewNonCanonicalArchivePaths(c *gc.C) {
 	c.Check(ap.FilesBundle, jc.SamePath, "/tmp/juju-backup/root.tar")
 	c.Check(ap.DBDumpDir, jc.SamePath, "/tmp/juju-backup/dump")
 	c.Check(ap.MetadataFile, jc.SamePath, "/tmp/juju-backup/metadata.json")
+	c.Check(ap.ManifestFile, jc.SamePath, "/tmp/juju-backup/manifest.json")
 }
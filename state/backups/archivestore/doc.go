@@ -0,0 +1,8 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package archivestore provides places outside of the controller's
+// database in which backup archives may be kept: a directory on the
+// controller machine (typically an NFS mount), or an S3-compatible
+// object store.
+package archivestore
@@ -0,0 +1,105 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package archivestore
+
+import (
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+)
+
+// tempPrefix is the prefix of the files archives are written to
+// before they are complete.
+const tempPrefix = ".tmp-"
+
+// NewLocalStore returns a store which keeps archives in the given
+// directory, creating it if necessary.
+func NewLocalStore(dir string) Store {
+	return &localStore{dir: dir}
+}
+
+type localStore struct {
+	dir string
+}
+
+// Put is part of the Store interface.
+func (s *localStore) Put(name string, archive io.Reader, size int64) error {
+	if err := validateName(name); err != nil {
+		return errors.Trace(err)
+	}
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return errors.Trace(err)
+	}
+	// Write to a temporary file first so that a partial archive is
+	// never seen under the real name.
+	f, err := ioutil.TempFile(s.dir, tempPrefix)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	defer os.Remove(f.Name())
+	n, err := io.Copy(f, archive)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return errors.Annotatef(err, "writing archive %q", name)
+	}
+	if n != size {
+		return errors.Errorf("writing archive %q: wrote %d bytes, expected %d", name, n, size)
+	}
+	if err := os.Rename(f.Name(), filepath.Join(s.dir, name)); err != nil {
+		return errors.Trace(err)
+	}
+	return nil
+}
+
+// Get is part of the Store interface.
+func (s *localStore) Get(name string) (io.ReadCloser, error) {
+	if err := validateName(name); err != nil {
+		return nil, errors.Trace(err)
+	}
+	f, err := os.Open(filepath.Join(s.dir, name))
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf("archive %q", name)
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return f, nil
+}
+
+// List is part of the Store interface.
+func (s *localStore) List() ([]string, error) {
+	infos, err := ioutil.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.Trace(err)
+	}
+	var names []string
+	for _, info := range infos {
+		if info.IsDir() || strings.HasPrefix(info.Name(), tempPrefix) {
+			continue
+		}
+		names = append(names, info.Name())
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Remove is part of the Store interface.
+func (s *localStore) Remove(name string) error {
+	if err := validateName(name); err != nil {
+		return errors.Trace(err)
+	}
+	err := os.Remove(filepath.Join(s.dir, name))
+	if os.IsNotExist(err) {
+		return nil
+	}
+	return errors.Trace(err)
+}
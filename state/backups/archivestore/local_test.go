@@ -0,0 +1,110 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package archivestore_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups/archivestore"
+)
+
+type localStoreSuite struct {
+	testing.IsolationSuite
+	dir   string
+	store archivestore.Store
+}
+
+var _ = gc.Suite(&localStoreSuite{})
+
+func (s *localStoreSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.dir = filepath.Join(c.MkDir(), "backups")
+	s.store = archivestore.NewLocalStore(s.dir)
+}
+
+func (s *localStoreSuite) TestPutGet(c *gc.C) {
+	err := s.store.Put("juju-backup-1.tar.gz", bytes.NewBufferString("archive"), 7)
+	c.Assert(err, jc.ErrorIsNil)
+
+	r, err := s.store.Get("juju-backup-1.tar.gz")
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "archive")
+
+	info, err := os.Stat(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info.Mode().Perm(), gc.Equals, os.FileMode(0700))
+}
+
+func (s *localStoreSuite) TestPutSizeMismatch(c *gc.C) {
+	err := s.store.Put("juju-backup-1.tar.gz", bytes.NewBufferString("arch"), 7)
+	c.Assert(err, gc.ErrorMatches, `writing archive "juju-backup-1.tar.gz": wrote 4 bytes, expected 7`)
+
+	// Nothing is left behind.
+	names, err := s.store.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, gc.HasLen, 0)
+	infos, err := ioutil.ReadDir(s.dir)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(infos, gc.HasLen, 0)
+}
+
+func (s *localStoreSuite) TestGetNotFound(c *gc.C) {
+	_, err := s.store.Get("juju-backup-1.tar.gz")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *localStoreSuite) TestInvalidName(c *gc.C) {
+	err := s.store.Put("../escape", bytes.NewBufferString(""), 0)
+	c.Assert(err, gc.ErrorMatches, `archive name "../escape" not valid`)
+	_, err = s.store.Get("..")
+	c.Assert(err, gc.ErrorMatches, `archive name ".." not valid`)
+	err = s.store.Remove("a/b")
+	c.Assert(err, gc.ErrorMatches, `archive name "a/b" not valid`)
+}
+
+func (s *localStoreSuite) TestListMissingDir(c *gc.C) {
+	names, err := s.store.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, gc.HasLen, 0)
+}
+
+func (s *localStoreSuite) TestListSorted(c *gc.C) {
+	for _, name := range []string{"c", "a", "b"} {
+		err := s.store.Put(name, bytes.NewBufferString(name), 1)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	// Directories and incomplete archives are ignored.
+	err := os.Mkdir(filepath.Join(s.dir, "subdir"), 0700)
+	c.Assert(err, jc.ErrorIsNil)
+	err = ioutil.WriteFile(filepath.Join(s.dir, ".tmp-123"), nil, 0600)
+	c.Assert(err, jc.ErrorIsNil)
+
+	names, err := s.store.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"a", "b", "c"})
+}
+
+func (s *localStoreSuite) TestRemove(c *gc.C) {
+	err := s.store.Put("a", bytes.NewBufferString("a"), 1)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.store.Remove("a")
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.store.Get("a")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+
+	// Removing a missing archive is fine.
+	err = s.store.Remove("a")
+	c.Assert(err, jc.ErrorIsNil)
+}
@@ -0,0 +1,162 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package archivestore
+
+import (
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"gopkg.in/amz.v3/aws"
+	"gopkg.in/amz.v3/s3"
+)
+
+// S3Config holds the details needed to talk to an S3-compatible
+// object store.
+type S3Config struct {
+	// Endpoint is the http or https URL of the service. Buckets are
+	// addressed by path, so that services without DNS-style bucket
+	// addressing work too.
+	Endpoint string
+
+	// Region is the region used to sign requests.
+	Region string
+
+	// Bucket is the bucket in which archives are kept.
+	Bucket string
+
+	// Prefix, if not empty, is prepended (with a "/") to archive
+	// names to give their object keys.
+	Prefix string
+
+	// AccessKey and SecretKey are the credentials used to sign
+	// requests.
+	AccessKey string
+	SecretKey string
+}
+
+// s3ListPageSize is the number of objects requested by each request
+// made to list a bucket.
+const s3ListPageSize = 1000
+
+// NewS3Store returns a store which keeps archives as objects in an
+// S3-compatible object store.
+func NewS3Store(cfg S3Config) (Store, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.NotValidf("empty Bucket")
+	}
+	auth := aws.Auth{
+		AccessKey: cfg.AccessKey,
+		SecretKey: cfg.SecretKey,
+	}
+	region := aws.Region{
+		Name:       cfg.Region,
+		S3Endpoint: strings.TrimSuffix(cfg.Endpoint, "/"),
+	}
+	bucket, err := s3.New(auth, region).Bucket(cfg.Bucket)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	store := &s3Store{
+		bucket: bucket,
+		prefix: strings.Trim(cfg.Prefix, "/"),
+	}
+	if store.prefix != "" {
+		store.prefix += "/"
+	}
+	return store, nil
+}
+
+type s3Store struct {
+	bucket *s3.Bucket
+	prefix string
+}
+
+// Put is part of the Store interface.
+func (s *s3Store) Put(name string, archive io.Reader, size int64) error {
+	if err := validateName(name); err != nil {
+		return errors.Trace(err)
+	}
+	// Requests are signed with the hash of their body, so the
+	// archive is read twice; spool it to a file if it can't be
+	// rewound.
+	body, ok := archive.(io.ReadSeeker)
+	if !ok {
+		f, err := ioutil.TempFile("", "juju-backup-")
+		if err != nil {
+			return errors.Trace(err)
+		}
+		defer os.Remove(f.Name())
+		defer f.Close()
+		if _, err := io.Copy(f, archive); err != nil {
+			return errors.Annotatef(err, "spooling archive %q", name)
+		}
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			return errors.Trace(err)
+		}
+		body = f
+	}
+	err := s.bucket.PutReader(s.prefix+name, body, size, "application/x-gzip", s3.Private)
+	return errors.Annotatef(err, "storing archive %q", name)
+}
+
+// Get is part of the Store interface.
+func (s *s3Store) Get(name string) (io.ReadCloser, error) {
+	if err := validateName(name); err != nil {
+		return nil, errors.Trace(err)
+	}
+	r, err := s.bucket.GetReader(s.prefix + name)
+	if isNotFound(err) {
+		return nil, errors.NotFoundf("archive %q", name)
+	} else if err != nil {
+		return nil, errors.Annotatef(err, "fetching archive %q", name)
+	}
+	return r, nil
+}
+
+// List is part of the Store interface.
+func (s *s3Store) List() ([]string, error) {
+	var names []string
+	var marker string
+	for {
+		resp, err := s.bucket.List(s.prefix, "", marker, s3ListPageSize)
+		if err != nil {
+			return nil, errors.Annotate(err, "listing archives")
+		}
+		for _, object := range resp.Contents {
+			name := strings.TrimPrefix(object.Key, s.prefix)
+			if validateName(name) == nil {
+				names = append(names, name)
+			}
+		}
+		if !resp.IsTruncated || len(resp.Contents) == 0 {
+			break
+		}
+		marker = resp.Contents[len(resp.Contents)-1].Key
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// Remove is part of the Store interface.
+func (s *s3Store) Remove(name string) error {
+	if err := validateName(name); err != nil {
+		return errors.Trace(err)
+	}
+	err := s.bucket.Del(s.prefix + name)
+	if err != nil && !isNotFound(err) {
+		return errors.Annotatef(err, "removing archive %q", name)
+	}
+	return nil
+}
+
+// isNotFound reports whether err is an S3 error for a missing object.
+func isNotFound(err error) bool {
+	s3err, ok := err.(*s3.Error)
+	return ok && s3err.StatusCode == http.StatusNotFound
+}
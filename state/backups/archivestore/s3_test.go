@@ -0,0 +1,211 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package archivestore_test
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups/archivestore"
+)
+
+type s3StoreSuite struct {
+	testing.IsolationSuite
+	server *fakeS3
+	http   *httptest.Server
+}
+
+var _ = gc.Suite(&s3StoreSuite{})
+
+func (s *s3StoreSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.server = &fakeS3{
+		bucket:  "backups",
+		objects: make(map[string][]byte),
+		pageLen: 2,
+	}
+	s.http = httptest.NewServer(s.server)
+	s.AddCleanup(func(*gc.C) { s.http.Close() })
+}
+
+func (s *s3StoreSuite) newStore(c *gc.C, prefix string) archivestore.Store {
+	store, err := archivestore.NewS3Store(archivestore.S3Config{
+		Endpoint:  s.http.URL,
+		Region:    "us-east-1",
+		Bucket:    "backups",
+		Prefix:    prefix,
+		AccessKey: "access",
+		SecretKey: "secret",
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	return store
+}
+
+func (s *s3StoreSuite) TestPutGet(c *gc.C) {
+	store := s.newStore(c, "juju/ctrl")
+	err := store.Put("juju-backup-1.tar.gz", bytes.NewBufferString("archive"), 7)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(s.server.objects["juju/ctrl/juju-backup-1.tar.gz"]), gc.Equals, "archive")
+
+	r, err := store.Get("juju-backup-1.tar.gz")
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "archive")
+
+	// Every request was signed.
+	c.Assert(s.server.authorization, gc.HasLen, 2)
+	for _, auth := range s.server.authorization {
+		c.Check(auth, gc.Matches, `AWS4-HMAC-SHA256 Credential=access/\d{8}/us-east-1/s3/aws4_request,.*`)
+	}
+}
+
+func (s *s3StoreSuite) TestGetNotFound(c *gc.C) {
+	store := s.newStore(c, "")
+	_, err := store.Get("juju-backup-1.tar.gz")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, `archive "juju-backup-1.tar.gz" not found`)
+}
+
+func (s *s3StoreSuite) TestListPaged(c *gc.C) {
+	for _, key := range []string{"juju/e", "juju/c", "juju/a", "juju/d", "juju/b", "other/f"} {
+		s.server.objects[key] = []byte(key)
+	}
+	store := s.newStore(c, "/juju/")
+	names, err := store.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"a", "b", "c", "d", "e"})
+}
+
+func (s *s3StoreSuite) TestRemove(c *gc.C) {
+	s.server.objects["a"] = []byte("a")
+	store := s.newStore(c, "")
+	err := store.Remove("a")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(s.server.objects, gc.HasLen, 0)
+
+	// Removing a missing archive is fine.
+	err = store.Remove("a")
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *s3StoreSuite) TestErrorResponse(c *gc.C) {
+	s.server.bucket = "elsewhere"
+	store := s.newStore(c, "")
+	err := store.Put("a", bytes.NewBufferString("a"), 1)
+	c.Assert(err, gc.ErrorMatches, `storing archive "a": .*Access Denied.*`)
+}
+
+// fakeS3 is a minimal stand-in for an S3-compatible service, holding
+// objects for a single bucket in memory.
+type fakeS3 struct {
+	mu            sync.Mutex
+	bucket        string
+	objects       map[string][]byte
+	pageLen       int
+	authorization []string
+}
+
+func (f *fakeS3) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.authorization = append(f.authorization, req.Header.Get("Authorization"))
+
+	path := strings.TrimPrefix(req.URL.Path, "/")
+	parts := strings.SplitN(path, "/", 2)
+	if parts[0] != f.bucket {
+		writeS3Error(w, http.StatusForbidden, "AccessDenied", "Access Denied")
+		return
+	}
+	if len(parts) == 1 || parts[1] == "" {
+		f.list(w, req)
+		return
+	}
+	key := parts[1]
+	switch req.Method {
+	case "PUT":
+		data, err := ioutil.ReadAll(req.Body)
+		if err != nil {
+			writeS3Error(w, http.StatusBadRequest, "IncompleteBody", err.Error())
+			return
+		}
+		f.objects[key] = data
+	case "GET":
+		data, ok := f.objects[key]
+		if !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+			return
+		}
+		w.Write(data)
+	case "DELETE":
+		if _, ok := f.objects[key]; !ok {
+			writeS3Error(w, http.StatusNotFound, "NoSuchKey", "The specified key does not exist.")
+			return
+		}
+		delete(f.objects, key)
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		writeS3Error(w, http.StatusMethodNotAllowed, "MethodNotAllowed", req.Method)
+	}
+}
+
+func (f *fakeS3) list(w http.ResponseWriter, req *http.Request) {
+	query := req.URL.Query()
+	pageLen := f.pageLen
+	if maxKeys, err := strconv.Atoi(query.Get("max-keys")); err == nil && maxKeys < pageLen {
+		pageLen = maxKeys
+	}
+	var keys []string
+	for key := range f.objects {
+		if strings.HasPrefix(key, query.Get("prefix")) && key > query.Get("marker") {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	type object struct {
+		Key string
+	}
+	var result struct {
+		XMLName     xml.Name `xml:"ListBucketResult"`
+		Name        string
+		Prefix      string
+		Marker      string
+		MaxKeys     int
+		IsTruncated bool
+		Contents    []object
+	}
+	result.Name = f.bucket
+	result.Prefix = query.Get("prefix")
+	result.Marker = query.Get("marker")
+	result.MaxKeys = pageLen
+	for i := 0; i < len(keys) && i < pageLen; i++ {
+		result.Contents = append(result.Contents, object{keys[i]})
+	}
+	result.IsTruncated = len(keys) > pageLen
+	w.Header().Set("Content-Type", "application/xml")
+	xml.NewEncoder(w).Encode(result)
+}
+
+func writeS3Error(w http.ResponseWriter, status int, code, message string) {
+	w.Header().Set("Content-Type", "application/xml")
+	w.WriteHeader(status)
+	xml.NewEncoder(w).Encode(struct {
+		XMLName xml.Name `xml:"Error"`
+		Code    string
+		Message string
+	}{Code: code, Message: message})
+}
@@ -0,0 +1,84 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package archivestore
+
+import (
+	"io"
+	"net/url"
+	"strings"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/controller"
+)
+
+// Store is a place, outside of the controller's database, where
+// backup archives are kept. Archives are identified by name; the
+// backup archive filenames sort in the order they were created.
+type Store interface {
+	// Put stores the archive under the given name, replacing any
+	// archive already stored with that name. The size of the
+	// archive must be given.
+	Put(name string, archive io.Reader, size int64) error
+
+	// Get returns the named archive. An error satisfying
+	// errors.IsNotFound is returned if there is no such archive.
+	Get(name string) (io.ReadCloser, error)
+
+	// List returns the names of all of the stored archives, sorted.
+	List() ([]string, error)
+
+	// Remove removes the named archive. Removing an archive that
+	// doesn't exist is not an error.
+	Remove(name string) error
+}
+
+const (
+	// DefaultS3Endpoint is the endpoint used for s3 stores when the
+	// config doesn't specify one.
+	DefaultS3Endpoint = "https://s3.amazonaws.com"
+
+	// DefaultS3Region is the region used to sign s3 requests when
+	// the config doesn't specify one.
+	DefaultS3Region = "us-east-1"
+)
+
+// Open returns the archive store described by the config.
+func Open(cfg controller.BackupStoreConfig) (Store, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	u, err := url.Parse(cfg.URL)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if u.Scheme == "file" {
+		return NewLocalStore(u.Path), nil
+	}
+	endpoint := cfg.S3Endpoint
+	if endpoint == "" {
+		endpoint = DefaultS3Endpoint
+	}
+	region := cfg.S3Region
+	if region == "" {
+		region = DefaultS3Region
+	}
+	store, err := NewS3Store(S3Config{
+		Endpoint:  endpoint,
+		Region:    region,
+		Bucket:    u.Host,
+		Prefix:    strings.TrimPrefix(u.Path, "/"),
+		AccessKey: cfg.S3AccessKey,
+		SecretKey: cfg.S3SecretKey,
+	})
+	return store, errors.Trace(err)
+}
+
+// validateName ensures that an archive name can't escape the store.
+func validateName(name string) error {
+	if name == "" || name == "." || name == ".." || strings.ContainsAny(name, "/\\") {
+		return errors.NotValidf("archive name %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,34 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package archivestore_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/controller"
+	"github.com/juju/juju/state/backups/archivestore"
+)
+
+type configSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&configSuite{})
+
+func (s *configSuite) TestOpenLocal(c *gc.C) {
+	dir := c.MkDir()
+	store, err := archivestore.Open(controller.BackupStoreConfig{URL: "file://" + dir})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(store, gc.NotNil)
+	names, err := store.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, gc.HasLen, 0)
+}
+
+func (s *configSuite) TestOpenInvalid(c *gc.C) {
+	_, err := archivestore.Open(controller.BackupStoreConfig{URL: "s3://bucket"})
+	c.Assert(err, gc.ErrorMatches, `s3 store without credentials not valid`)
+}
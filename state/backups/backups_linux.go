@@ -76,6 +76,10 @@ func (b *backups) Restore(backupId string, dbInfo *DBInfo, args RestoreArgs) (na
 
 	defer backupReader.Close()
 
+	if meta.Encrypted {
+		return nil, errors.Errorf("backup %q is encrypted and cannot be restored in place; restore from the decrypted archive instead", backupId)
+	}
+
 	workspace, err := NewArchiveWorkspaceReader(backupReader)
 	if err != nil {
 		return nil, errors.Annotate(err, "cannot unpack backup file")
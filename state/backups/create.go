@@ -16,6 +16,8 @@ import (
 	"github.com/juju/loggo"
 	"github.com/juju/utils/hash"
 	"github.com/juju/utils/tar"
+
+	"github.com/juju/juju/state/backups/encryption"
 )
 
 // TODO(ericsnow) One concern is files that get out of date by the time
@@ -31,6 +33,8 @@ type createArgs struct {
 	filesToBackUp  []string
 	db             DBDumper
 	metadataReader io.Reader
+	// key, if not nil, is used to encrypt the archive.
+	key *encryption.Key
 }
 
 type createResult struct {
@@ -47,6 +51,7 @@ func create(args *createArgs) (_ *createResult, err error) {
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	builder.key = args.key
 	defer func() {
 		if cerr := builder.cleanUp(); cerr != nil {
 			cerr.Log(logger)
@@ -103,6 +108,8 @@ type builder struct {
 	// bundleFile is the inner archive file containing all the juju
 	// state-related files gathered during backup.
 	bundleFile io.WriteCloser
+	// key, if not nil, is used to encrypt the archive file.
+	key *encryption.Key
 }
 
 // newBuilder returns a new backup archive builder.  It creates the temp
@@ -308,13 +315,27 @@ func (b *builder) buildArchiveAndChecksum() error {
 	logger.Infof("building archive file %q", b.filename)
 
 	// Build the tarball, writing out to both the archive file and a
-	// SHA1 hash.  The hash will correspond to the gzipped file rather
-	// than to the uncompressed contents of the tarball.  This is so
-	// that users can compare the published checksum against the
-	// checksum of the file without having to decompress it first.
+	// SHA1 hash.  The hash will correspond to the gzipped (and, if
+	// requested, encrypted) file rather than to the uncompressed
+	// contents of the tarball.  This is so that users can compare the
+	// published checksum against the checksum of the file without
+	// having to decompress it first.
 	hasher := hash.NewHashingWriter(b.archiveFile, sha1.New())
-	if err := b.buildArchive(hasher); err != nil {
-		return errors.Trace(err)
+	if b.key == nil {
+		if err := b.buildArchive(hasher); err != nil {
+			return errors.Trace(err)
+		}
+	} else {
+		encrypter, err := encryption.NewWriter(hasher, b.key)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if err := b.buildArchive(encrypter); err != nil {
+			return errors.Trace(err)
+		}
+		if err := encrypter.Close(); err != nil {
+			return errors.Annotate(err, "while encrypting archive")
+		}
 	}
 
 	// Save the SHA1 checksum.
@@ -337,6 +358,14 @@ func (b *builder) buildAll() error {
 		return errors.Trace(err)
 	}
 
+	// Record checksums of everything gathered so far.
+	if err := b.closeBundleFile(); err != nil {
+		return errors.Trace(err)
+	}
+	if err := writeManifest(b.archivePaths); err != nil {
+		return errors.Trace(err)
+	}
+
 	// Bundle it all into a tarball.
 	if err := b.buildArchiveAndChecksum(); err != nil {
 		return errors.Trace(err)
@@ -4,13 +4,17 @@
 package backups_test
 
 import (
+	"bytes"
+	"io/ioutil"
 	"os"
+	"path/filepath"
 	"runtime"
 
 	jc "github.com/juju/testing/checkers"
 	gc "gopkg.in/check.v1"
 
 	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/backups/encryption"
 	backupstesting "github.com/juju/juju/state/backups/testing"
 )
 
@@ -29,6 +33,20 @@ func (d *TestDBDumper) Dump(dumpDir string) error {
 	return nil
 }
 
+// jujuDBDumper dumps a juju database with a single collection.
+type jujuDBDumper struct{}
+
+func (jujuDBDumper) Dump(dumpDir string) error {
+	dbDir := filepath.Join(dumpDir, "juju")
+	if err := os.MkdirAll(dbDir, 0700); err != nil {
+		return err
+	}
+	if err := ioutil.WriteFile(filepath.Join(dbDir, "machines.bson"), []byte("<machines>"), 0600); err != nil {
+		return err
+	}
+	return ioutil.WriteFile(filepath.Join(dbDir, "machines.metadata.json"), []byte("{}"), 0600)
+}
+
 func (s *createSuite) TestLegacy(c *gc.C) {
 	if runtime.GOOS == "windows" {
 		c.Skip("bug 1403084: Currently does not work on windows, see comments inside backups.create function")
@@ -56,6 +74,44 @@ func (s *createSuite) TestLegacy(c *gc.C) {
 	s.checkArchive(c, file, expected)
 }
 
+func (s *createSuite) TestEncrypted(c *gc.C) {
+	if runtime.GOOS == "windows" {
+		c.Skip("bug 1403084: Currently does not work on windows, see comments inside backups.create function")
+	}
+	meta := backupstesting.NewMetadataStarted()
+	metadataFile, err := meta.AsJSONBuffer()
+	c.Assert(err, jc.ErrorIsNil)
+	_, testFiles, expected := s.createTestFiles(c)
+	key, err := encryption.ParseKey([]byte("correct horse battery staple"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	args := backups.NewTestCreateArgs(testFiles, jujuDBDumper{}, metadataFile)
+	backups.SetTestCreateArgsKey(args, key)
+	result, err := backups.Create(args)
+	c.Assert(err, jc.ErrorIsNil)
+
+	archiveFile, size, checksum := backups.ExposeCreateResult(result)
+	file := archiveFile.(*os.File)
+	s.checkSize(c, file, size)
+	s.checkChecksum(c, file, checksum)
+
+	// The archive is only readable once decrypted.
+	decrypted, err := encryption.NewReader(file, key)
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := ioutil.ReadAll(decrypted)
+	c.Assert(err, jc.ErrorIsNil)
+	plainFile, err := os.Create(filepath.Join(c.MkDir(), "plain.tar.gz"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer plainFile.Close()
+	_, err = plainFile.Write(data)
+	c.Assert(err, jc.ErrorIsNil)
+	resetFile(c, plainFile)
+	s.checkArchive(c, plainFile, expected)
+
+	_, err = backups.VerifyArchive(bytes.NewReader(data))
+	c.Check(err, jc.ErrorIsNil)
+}
+
 func (s *createSuite) TestMetadataFileMissing(c *gc.C) {
 	var testFiles []string
 	dumper := &TestDBDumper{}
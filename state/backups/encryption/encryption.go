@@ -0,0 +1,355 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package encryption encrypts and decrypts backup archives, so that
+// the credentials they contain are protected at rest.
+package encryption
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/pem"
+	"io"
+
+	"github.com/juju/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+// Encrypted archives start with encryptedMagic, followed by a byte
+// identifying how the data key is protected:
+//
+//   - secretKeyMode: a 16 byte salt follows; the data key is derived
+//     from the secret and the salt with scrypt.
+//   - publicKeyMode: a 2 byte big-endian length and the data key,
+//     encrypted with RSA-OAEP (SHA-256), follow.
+//
+// The rest of the archive is a sequence of chunks, each of which is a
+// 1 byte flag (lastChunk for the final chunk, 0 otherwise), a 4 byte
+// big-endian length, and that many bytes of AES-256-GCM ciphertext.
+// Chunk nonces are a counter, and the flag and length are
+// authenticated, so chunks can't be reordered, dropped or truncated
+// without detection.
+const (
+	encryptedMagic = "JUJUENC1"
+	secretKeyMode  = 'k'
+	publicKeyMode  = 'r'
+	lastChunk      = 1
+
+	saltSize      = 16
+	dataKeySize   = 32
+	chunkSize     = 64 * 1024
+	chunkHeaderSz = 5
+)
+
+// scrypt parameters used to derive data keys from secrets.
+var scryptN, scryptR, scryptP = 1 << 15, 8, 1
+
+// minSecretSize is the shortest secret accepted as an encryption key.
+const minSecretSize = 16
+
+// Key is used to encrypt or decrypt backup archives. It is either a
+// secret, used for both encryption and decryption, or an RSA key
+// pair, of which only the public key is needed to encrypt and the
+// private key is needed to decrypt.
+type Key struct {
+	secret     []byte
+	publicKey  *rsa.PublicKey
+	privateKey *rsa.PrivateKey
+}
+
+// ParseKey returns the key held in data, which is a PEM-encoded RSA
+// public key ("PUBLIC KEY") or private key ("RSA PRIVATE KEY" or
+// "PRIVATE KEY"), or otherwise a secret of at least 16 bytes. Leading
+// and trailing white space is ignored.
+func ParseKey(data []byte) (*Key, error) {
+	data = bytes.TrimSpace(data)
+	block, _ := pem.Decode(data)
+	if block == nil {
+		if bytes.HasPrefix(data, []byte("-----BEGIN")) {
+			return nil, errors.NotValidf("PEM encoded key")
+		}
+		if len(data) < minSecretSize {
+			return nil, errors.Errorf("encryption key too short (need at least %d bytes)", minSecretSize)
+		}
+		return &Key{secret: data}, nil
+	}
+	switch block.Type {
+	case "PUBLIC KEY":
+		key, err := x509.ParsePKIXPublicKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Annotate(err, "parsing public key")
+		}
+		rsaKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return nil, errors.Errorf("public key is %T, not RSA", key)
+		}
+		return &Key{publicKey: rsaKey}, nil
+	case "RSA PRIVATE KEY":
+		key, err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Annotate(err, "parsing private key")
+		}
+		return &Key{publicKey: &key.PublicKey, privateKey: key}, nil
+	case "PRIVATE KEY":
+		key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+		if err != nil {
+			return nil, errors.Annotate(err, "parsing private key")
+		}
+		rsaKey, ok := key.(*rsa.PrivateKey)
+		if !ok {
+			return nil, errors.Errorf("private key is %T, not RSA", key)
+		}
+		return &Key{publicKey: &rsaKey.PublicKey, privateKey: rsaKey}, nil
+	}
+	return nil, errors.NotSupportedf("PEM block type %q", block.Type)
+}
+
+// CanDecrypt reports whether the key can be used to decrypt archives.
+func (k *Key) CanDecrypt() bool {
+	return k.secret != nil || k.privateKey != nil
+}
+
+// IsPrivateKey reports whether the key is the private key of a key
+// pair. Such keys shouldn't be given to a controller, which only
+// needs the public key to encrypt backups.
+func (k *Key) IsPrivateKey() bool {
+	return k.privateKey != nil
+}
+
+// EncryptionOnly returns the part of the key needed to encrypt
+// archives, in a form accepted by ParseKey: the public key
+// of a key pair, or the secret.
+func (k *Key) EncryptionOnly() ([]byte, error) {
+	if k.secret != nil {
+		return k.secret, nil
+	}
+	der, err := x509.MarshalPKIXPublicKey(k.publicKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}), nil
+}
+
+// IsEncrypted reports whether the archive read by r is encrypted,
+// without consuming any of it.
+func IsEncrypted(r *bufio.Reader) bool {
+	header, _ := r.Peek(len(encryptedMagic))
+	return string(header) == encryptedMagic
+}
+
+// NewWriter returns a writer which encrypts what is written to it
+// with the given key, writing the encrypted archive to w. The writer
+// must be closed to complete the archive; closing it does not close w.
+func NewWriter(w io.Writer, key *Key) (io.WriteCloser, error) {
+	dataKey := make([]byte, dataKeySize)
+	header := bytes.NewBufferString(encryptedMagic)
+	if key.secret != nil {
+		salt := make([]byte, saltSize)
+		if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+			return nil, errors.Trace(err)
+		}
+		var err error
+		dataKey, err = scrypt.Key(key.secret, salt, scryptN, scryptR, scryptP, dataKeySize)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		header.WriteByte(secretKeyMode)
+		header.Write(salt)
+	} else {
+		if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+			return nil, errors.Trace(err)
+		}
+		wrapped, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, key.publicKey, dataKey, nil)
+		if err != nil {
+			return nil, errors.Annotate(err, "encrypting data key")
+		}
+		header.WriteByte(publicKeyMode)
+		binary.Write(header, binary.BigEndian, uint16(len(wrapped)))
+		header.Write(wrapped)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	if _, err := w.Write(header.Bytes()); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &encryptingWriter{w: w, aead: aead}, nil
+}
+
+type encryptingWriter struct {
+	w       io.Writer
+	aead    cipher.AEAD
+	counter uint64
+	buf     []byte
+	closed  bool
+}
+
+// Write is part of the io.Writer interface.
+func (e *encryptingWriter) Write(p []byte) (int, error) {
+	if e.closed {
+		return 0, errors.New("write to closed encrypting writer")
+	}
+	e.buf = append(e.buf, p...)
+	// Only write a chunk once we know it isn't the last one.
+	for len(e.buf) > chunkSize {
+		if err := e.writeChunk(e.buf[:chunkSize], false); err != nil {
+			return 0, errors.Trace(err)
+		}
+		e.buf = e.buf[chunkSize:]
+	}
+	return len(p), nil
+}
+
+// Close writes the final chunk of the archive.
+func (e *encryptingWriter) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+	return errors.Trace(e.writeChunk(e.buf, true))
+}
+
+func (e *encryptingWriter) writeChunk(plaintext []byte, last bool) error {
+	header := make([]byte, chunkHeaderSz)
+	if last {
+		header[0] = lastChunk
+	}
+	binary.BigEndian.PutUint32(header[1:], uint32(len(plaintext)+e.aead.Overhead()))
+	ciphertext := e.aead.Seal(nil, chunkNonce(e.aead, e.counter), plaintext, header)
+	e.counter++
+	if _, err := e.w.Write(header); err != nil {
+		return errors.Trace(err)
+	}
+	_, err := e.w.Write(ciphertext)
+	return errors.Trace(err)
+}
+
+// NewReader returns a reader which decrypts the encrypted archive
+// read from r. An error is returned by Read if the archive
+// has been tampered with or is incomplete.
+func NewReader(r io.Reader, key *Key) (io.Reader, error) {
+	if !key.CanDecrypt() {
+		return nil, errors.New("a public key cannot decrypt backups; the private key is needed")
+	}
+	magic := make([]byte, len(encryptedMagic)+1)
+	if _, err := io.ReadFull(r, magic); err != nil || string(magic[:len(encryptedMagic)]) != encryptedMagic {
+		return nil, errors.New("backup archive is not encrypted")
+	}
+	var dataKey []byte
+	switch mode := magic[len(encryptedMagic)]; {
+	case mode == secretKeyMode && key.secret != nil:
+		salt := make([]byte, saltSize)
+		if _, err := io.ReadFull(r, salt); err != nil {
+			return nil, errors.Annotate(err, "reading salt")
+		}
+		var err error
+		dataKey, err = scrypt.Key(key.secret, salt, scryptN, scryptR, scryptP, dataKeySize)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+	case mode == publicKeyMode && key.privateKey != nil:
+		var size uint16
+		if err := binary.Read(r, binary.BigEndian, &size); err != nil {
+			return nil, errors.Annotate(err, "reading data key")
+		}
+		wrapped := make([]byte, size)
+		if _, err := io.ReadFull(r, wrapped); err != nil {
+			return nil, errors.Annotate(err, "reading data key")
+		}
+		var err error
+		dataKey, err = rsa.DecryptOAEP(sha256.New(), nil, key.privateKey, wrapped, nil)
+		if err != nil {
+			return nil, errors.New("cannot decrypt backup archive: wrong private key")
+		}
+	case mode == secretKeyMode:
+		return nil, errors.New("backup archive was encrypted with a secret key, not a key pair")
+	case mode == publicKeyMode:
+		return nil, errors.New("backup archive was encrypted with a public key, not a secret key")
+	default:
+		return nil, errors.Errorf("unknown backup encryption mode %q", mode)
+	}
+	aead, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &decryptingReader{r: r, aead: aead}, nil
+}
+
+type decryptingReader struct {
+	r       io.Reader
+	aead    cipher.AEAD
+	counter uint64
+	buf     []byte
+	done    bool
+}
+
+// Read is part of the io.Reader interface.
+func (d *decryptingReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.readChunk(); err != nil {
+			return 0, errors.Trace(err)
+		}
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+func (d *decryptingReader) readChunk() error {
+	header := make([]byte, chunkHeaderSz)
+	if _, err := io.ReadFull(d.r, header); err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errors.New("encrypted backup archive is truncated")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	size := binary.BigEndian.Uint32(header[1:])
+	if size > chunkSize+uint32(d.aead.Overhead()) {
+		return errors.New("encrypted backup archive is corrupt")
+	}
+	ciphertext := make([]byte, size)
+	if _, err := io.ReadFull(d.r, ciphertext); err == io.EOF || err == io.ErrUnexpectedEOF {
+		return errors.New("encrypted backup archive is truncated")
+	} else if err != nil {
+		return errors.Trace(err)
+	}
+	plaintext, err := d.aead.Open(nil, chunkNonce(d.aead, d.counter), ciphertext, header)
+	if err != nil {
+		return errors.New("cannot decrypt backup archive: wrong key or corrupt archive")
+	}
+	d.counter++
+	d.buf = plaintext
+	if header[0] == lastChunk {
+		d.done = true
+		// Nothing may follow the last chunk.
+		if _, err := io.ReadFull(d.r, make([]byte, 1)); err == nil {
+			return errors.New("encrypted backup archive has trailing data")
+		}
+	}
+	return nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	return aead, errors.Trace(err)
+}
+
+func chunkNonce(aead cipher.AEAD, counter uint64) []byte {
+	nonce := make([]byte, aead.NonceSize())
+	binary.BigEndian.PutUint64(nonce[len(nonce)-8:], counter)
+	return nonce
+}
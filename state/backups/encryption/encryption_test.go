@@ -0,0 +1,168 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package encryption_test
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"io/ioutil"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups/encryption"
+)
+
+type encryptionSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&encryptionSuite{})
+
+// privateKeyPEM is generated once, as it's slow.
+var privateKeyPEM = func() []byte {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		panic(err)
+	}
+	return pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PRIVATE KEY",
+		Bytes: x509.MarshalPKCS1PrivateKey(key),
+	})
+}()
+
+func (s *encryptionSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	// Keep the tests quick.
+	s.PatchValue(encryption.ScryptN, 1<<10)
+}
+
+func (s *encryptionSuite) parseKey(c *gc.C, data []byte) *encryption.Key {
+	key, err := encryption.ParseKey(data)
+	c.Assert(err, jc.ErrorIsNil)
+	return key
+}
+
+func encrypt(c *gc.C, key *encryption.Key, plaintext []byte) []byte {
+	var buf bytes.Buffer
+	w, err := encryption.NewWriter(&buf, key)
+	c.Assert(err, jc.ErrorIsNil)
+	// Write in odd sizes to exercise the chunking.
+	for len(plaintext) > 0 {
+		n := 1000
+		if n > len(plaintext) {
+			n = len(plaintext)
+		}
+		_, err := w.Write(plaintext[:n])
+		c.Assert(err, jc.ErrorIsNil)
+		plaintext = plaintext[n:]
+	}
+	c.Assert(w.Close(), jc.ErrorIsNil)
+	return buf.Bytes()
+}
+
+func decrypt(key *encryption.Key, ciphertext []byte) ([]byte, error) {
+	r, err := encryption.NewReader(bytes.NewReader(ciphertext), key)
+	if err != nil {
+		return nil, err
+	}
+	return ioutil.ReadAll(r)
+}
+
+func randomBytes(c *gc.C, n int) []byte {
+	data := make([]byte, n)
+	_, err := rand.Read(data)
+	c.Assert(err, jc.ErrorIsNil)
+	return data
+}
+
+func (s *encryptionSuite) TestRoundTripSecret(c *gc.C) {
+	key := s.parseKey(c, []byte("correct horse battery staple\n"))
+	for _, size := range []int{0, 1, encryption.ChunkSize, 3*encryption.ChunkSize + 17} {
+		c.Logf("size %d", size)
+		plaintext := randomBytes(c, size)
+		ciphertext := encrypt(c, key, plaintext)
+		c.Assert(encryption.IsEncrypted(bufio.NewReader(bytes.NewReader(ciphertext))), jc.IsTrue)
+
+		decrypted, err := decrypt(key, ciphertext)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(decrypted, jc.DeepEquals, plaintext)
+	}
+}
+
+func (s *encryptionSuite) TestRoundTripKeyPair(c *gc.C) {
+	privateKey := s.parseKey(c, privateKeyPEM)
+	publicPEM, err := privateKey.EncryptionOnly()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(publicPEM), gc.Matches, "-----BEGIN PUBLIC KEY-----\n(.|\n)*")
+	publicKey := s.parseKey(c, publicPEM)
+	c.Assert(publicKey.CanDecrypt(), jc.IsFalse)
+	c.Assert(publicKey.IsPrivateKey(), jc.IsFalse)
+	c.Assert(privateKey.IsPrivateKey(), jc.IsTrue)
+
+	plaintext := randomBytes(c, 2*encryption.ChunkSize+1)
+	ciphertext := encrypt(c, publicKey, plaintext)
+
+	_, err = decrypt(publicKey, ciphertext)
+	c.Assert(err, gc.ErrorMatches, "a public key cannot decrypt backups; the private key is needed")
+	decrypted, err := decrypt(privateKey, ciphertext)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(decrypted, jc.DeepEquals, plaintext)
+}
+
+func (s *encryptionSuite) TestWrongKey(c *gc.C) {
+	ciphertext := encrypt(c, s.parseKey(c, []byte("correct horse battery staple")), []byte("archive"))
+	_, err := decrypt(s.parseKey(c, []byte("incorrect horse battery staple")), ciphertext)
+	c.Assert(err, gc.ErrorMatches, "cannot decrypt backup archive: wrong key or corrupt archive")
+
+	_, err = decrypt(s.parseKey(c, privateKeyPEM), ciphertext)
+	c.Assert(err, gc.ErrorMatches, "backup archive was encrypted with a secret key, not a key pair")
+}
+
+func (s *encryptionSuite) TestTampering(c *gc.C) {
+	key := s.parseKey(c, []byte("correct horse battery staple"))
+	ciphertext := encrypt(c, key, randomBytes(c, 2*encryption.ChunkSize+1))
+
+	// Flipping a bit anywhere after the header is detected.
+	corrupt := append([]byte(nil), ciphertext...)
+	corrupt[len(corrupt)/2] ^= 1
+	_, err := decrypt(key, corrupt)
+	c.Assert(err, gc.ErrorMatches, "cannot decrypt backup archive: wrong key or corrupt archive")
+
+	// Dropping the final chunk is detected.
+	lastChunk := 5 + 1 + 16
+	_, err = decrypt(key, ciphertext[:len(ciphertext)-lastChunk])
+	c.Assert(err, gc.ErrorMatches, "encrypted backup archive is truncated")
+
+	// As is a partial chunk.
+	_, err = decrypt(key, ciphertext[:len(ciphertext)-3])
+	c.Assert(err, gc.ErrorMatches, "encrypted backup archive is truncated")
+
+	// And anything appended.
+	_, err = decrypt(key, append(ciphertext, 0))
+	c.Assert(err, gc.ErrorMatches, "encrypted backup archive has trailing data")
+}
+
+func (s *encryptionSuite) TestNotEncrypted(c *gc.C) {
+	key := s.parseKey(c, []byte("correct horse battery staple"))
+	c.Assert(encryption.IsEncrypted(bufio.NewReader(bytes.NewBufferString("\x1f\x8b..."))), jc.IsFalse)
+	_, err := decrypt(key, []byte("\x1f\x8b plain gzip data"))
+	c.Assert(err, gc.ErrorMatches, "backup archive is not encrypted")
+}
+
+func (s *encryptionSuite) TestParseKeyErrors(c *gc.C) {
+	_, err := encryption.ParseKey([]byte("short"))
+	c.Assert(err, gc.ErrorMatches, `encryption key too short \(need at least 16 bytes\)`)
+
+	_, err = encryption.ParseKey([]byte("-----BEGIN PUBLIC KEY-----\nnonsense"))
+	c.Assert(err, gc.ErrorMatches, "PEM encoded key not valid")
+
+	_, err = encryption.ParseKey(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: []byte("x")}))
+	c.Assert(err, gc.ErrorMatches, `PEM block type "CERTIFICATE" not supported`)
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/juju/utils/filestorage"
 
 	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups/encryption"
 )
 
 var (
@@ -109,6 +110,17 @@ func ExposeCreateArgs(args *createArgs) ([]string, DBDumper) {
 	return args.filesToBackUp, args.db
 }
 
+// ExposeCreateArgsKey extracts the encryption key in a create() args
+// value.
+func ExposeCreateArgsKey(args *createArgs) *encryption.Key {
+	return args.key
+}
+
+// SetTestCreateArgsKey sets the encryption key in a create() args value.
+func SetTestCreateArgsKey(args *createArgs, key *encryption.Key) {
+	args.key = key
+}
+
 // NewTestCreateResult builds a new create() result.
 func NewTestCreateResult(file io.ReadCloser, size int64, checksum string) *createResult {
 	result := createResult{
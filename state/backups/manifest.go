@@ -0,0 +1,102 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/juju/errors"
+)
+
+// manifest records a checksum of each file in a backup archive, so
+// that the contents can be checked without restoring them.
+type manifest struct {
+	// Files maps the path of each file, relative to the content
+	// directory and "/"-separated, to its hex-encoded SHA-256 sum.
+	Files map[string]string `json:"files"`
+}
+
+// buildManifest returns the manifest of every regular file under the
+// content directory, other than the manifest itself.
+func buildManifest(paths ArchivePaths) (*manifest, error) {
+	m := manifest{Files: make(map[string]string)}
+	err := filepath.Walk(paths.ContentDir, func(filename string, info os.FileInfo, err error) error {
+		if err != nil {
+			return errors.Trace(err)
+		}
+		if !info.Mode().IsRegular() || filename == paths.ManifestFile {
+			return nil
+		}
+		rel, err := filepath.Rel(paths.ContentDir, filename)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		sum, err := sha256File(filename)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		m.Files[filepath.ToSlash(rel)] = sum
+		return nil
+	})
+	if err != nil {
+		return nil, errors.Annotate(err, "while building manifest")
+	}
+	return &m, nil
+}
+
+// writeManifest writes the manifest of the content directory to the
+// manifest file.
+func writeManifest(paths ArchivePaths) error {
+	m, err := buildManifest(paths)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	file, err := os.Create(paths.ManifestFile)
+	if err != nil {
+		return errors.Annotate(err, "while creating manifest file")
+	}
+	if err := json.NewEncoder(file).Encode(m); err != nil {
+		file.Close()
+		return errors.Annotate(err, "while writing manifest file")
+	}
+	return errors.Trace(file.Close())
+}
+
+// readManifest reads the manifest file. If the archive has none,
+// errors.NotFound is returned.
+func readManifest(paths ArchivePaths) (*manifest, error) {
+	file, err := os.Open(paths.ManifestFile)
+	if os.IsNotExist(err) {
+		return nil, errors.NotFoundf("manifest")
+	}
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	defer file.Close()
+
+	var m manifest
+	if err := json.NewDecoder(file).Decode(&m); err != nil {
+		return nil, errors.Annotate(err, "while reading manifest file")
+	}
+	return &m, nil
+}
+
+func sha256File(filename string) (string, error) {
+	file, err := os.Open(filename)
+	if err != nil {
+		return "", errors.Trace(err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return "", errors.Trace(err)
+	}
+	return hex.EncodeToString(hasher.Sum(nil)), nil
+}
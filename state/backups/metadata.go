@@ -23,6 +23,11 @@ import (
 // generated with this version of juju.
 const checksumFormat = "SHA-1, base64 encoded"
 
+// FormatVersion is the version of the backup archive layout produced
+// by this version of juju. Version 1 added the manifest of content
+// checksums; archives without a recorded format version predate it.
+const FormatVersion = 1
+
 // Origin identifies where a backup archive came from.  While it is
 // more about where and Metadata about what and when, that distinction
 // does not merit special consideration.  Instead, Origin exists
@@ -68,6 +73,12 @@ type Metadata struct {
 	// Notes is an optional user-supplied annotation.
 	Notes string
 
+	// FormatVersion is the version of the archive layout.
+	FormatVersion int
+
+	// Encrypted records whether the stored archive is encrypted.
+	Encrypted bool
+
 	// TODO(wallyworld) - remove these ASAP
 	// These are only used by the restore CLI when re-bootstrapping.
 	// We will use a better solution but the way restore currently
@@ -92,6 +103,7 @@ func NewMetadata() *Metadata {
 		Origin: Origin{
 			Version: jujuversion.Current,
 		},
+		FormatVersion: FormatVersion,
 	}
 }
 
@@ -170,6 +182,8 @@ type flatMetadata struct {
 
 	CACert       string
 	CAPrivateKey string
+
+	FormatVersion int `json:",omitempty"`
 }
 
 // TODO(ericsnow) Move AsJSONBuffer to filestorage.Metadata.
@@ -192,6 +206,8 @@ func (m *Metadata) AsJSONBuffer() (io.Reader, error) {
 		Series:       m.Origin.Series,
 		CACert:       m.CACert,
 		CAPrivateKey: m.CAPrivateKey,
+
+		FormatVersion: m.FormatVersion,
 	}
 
 	stored := m.Stored()
@@ -234,6 +250,7 @@ func NewMetadataJSONReader(in io.Reader) (*Metadata, error) {
 		meta.Finished = &flat.Finished
 	}
 	meta.Notes = flat.Notes
+	meta.FormatVersion = flat.FormatVersion
 	meta.Origin = Origin{
 		Model:    flat.Environment,
 		Machine:  flat.Machine,
@@ -284,6 +301,7 @@ func BuildMetadata(file *os.File) (*Metadata, error) {
 	meta := NewMetadata()
 	meta.Started = time.Time{}
 	meta.Origin = UnknownOrigin()
+	meta.FormatVersion = 0
 	err = meta.MarkComplete(size, checksum)
 	if err != nil {
 		return nil, errors.Trace(err)
@@ -62,7 +62,8 @@ func (s *metadataSuite) TestAsJSONBuffer(c *gc.C) {
 		`"Version":"1.21-alpha3",`+
 		`"Series":"trusty",`+
 		`"CACert":"ca-cert",`+
-		`"CAPrivateKey":"ca-private-key"`+
+		`"CAPrivateKey":"ca-private-key",`+
+		`"FormatVersion":1`+
 		`}`+"\n")
 }
 
@@ -79,7 +80,8 @@ func (s *metadataSuite) TestNewMetadataJSONReader(c *gc.C) {
 		`"Environment":"asdf-zxcv-qwe",` +
 		`"Machine":"0",` +
 		`"Hostname":"myhost",` +
-		`"Version":"1.21-alpha3"` +
+		`"Version":"1.21-alpha3",` +
+		`"FormatVersion":1` +
 		`}` + "\n")
 	meta, err := backups.NewMetadataJSONReader(file)
 	c.Assert(err, jc.ErrorIsNil)
@@ -96,6 +98,7 @@ func (s *metadataSuite) TestNewMetadataJSONReader(c *gc.C) {
 	c.Check(meta.Origin.Machine, gc.Equals, "0")
 	c.Check(meta.Origin.Hostname, gc.Equals, "myhost")
 	c.Check(meta.Origin.Version.String(), gc.Equals, "1.21-alpha3")
+	c.Check(meta.FormatVersion, gc.Equals, 1)
 }
 
 func (s *metadataSuite) TestBuildMetadata(c *gc.C) {
@@ -48,6 +48,9 @@ type storageMetaDoc struct {
 	Finished int64  `bson:"finished,minsize"`
 	Notes    string `bson:"notes,omitempty"`
 
+	FormatVersion int  `bson:"formatversion,omitempty"`
+	Encrypted     bool `bson:"encrypted,omitempty"`
+
 	// origin
 
 	Model    string         `bson:"model"`
@@ -124,6 +127,8 @@ func docAsMetadata(doc *storageMetaDoc) *Metadata {
 	meta := NewMetadata()
 	meta.Started = metadocUnixToTime(doc.Started)
 	meta.Notes = doc.Notes
+	meta.FormatVersion = doc.FormatVersion
+	meta.Encrypted = doc.Encrypted
 
 	meta.Origin.Model = doc.Model
 	meta.Origin.Machine = doc.Machine
@@ -175,6 +180,8 @@ func newStorageMetaDoc(meta *Metadata) storageMetaDoc {
 		doc.Finished = metadocTimeToUnix(*meta.Finished)
 	}
 	doc.Notes = meta.Notes
+	doc.FormatVersion = meta.FormatVersion
+	doc.Encrypted = meta.Encrypted
 
 	doc.Model = meta.Origin.Model
 	doc.Machine = meta.Origin.Machine
@@ -74,6 +74,8 @@ func (s *storageSuite) checkMeta(c *gc.C, meta, expected *backups.Metadata, id s
 	c.Check(meta.Origin.Machine, gc.Equals, expected.Origin.Machine)
 	c.Check(meta.Origin.Hostname, gc.Equals, expected.Origin.Hostname)
 	c.Check(meta.Origin.Version, gc.Equals, expected.Origin.Version)
+	c.Check(meta.FormatVersion, gc.Equals, expected.FormatVersion)
+	c.Check(meta.Encrypted, gc.Equals, expected.Encrypted)
 	if meta.Stored() != nil && expected.Stored() != nil {
 		c.Check(meta.Stored().Unix(), gc.Equals, expected.Stored().Unix())
 	} else {
@@ -118,6 +120,19 @@ func (s *storageSuite) TestAddBackupMetadataSuccess(c *gc.C) {
 	s.checkMeta(c, meta, original, id)
 }
 
+func (s *storageSuite) TestAddBackupMetadataEncrypted(c *gc.C) {
+	original := s.metadata(c)
+	original.Encrypted = true
+	id, err := backups.AddBackupMetadata(s.State, original)
+	c.Assert(err, jc.ErrorIsNil)
+
+	meta, err := backups.GetBackupMetadata(s.State, id)
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(meta.Encrypted, jc.IsTrue)
+	s.checkMeta(c, meta, original, id)
+}
+
 func (s *storageSuite) TestAddBackupMetadataGeneratedID(c *gc.C) {
 	original := s.metadata(c)
 	original.SetID("spam")
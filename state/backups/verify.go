@@ -0,0 +1,160 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/version"
+
+	"github.com/juju/juju/state/backups/encryption"
+)
+
+// VerifyArchive unpacks the backup archive into a temporary workspace
+// and checks it without restoring anything: the metadata must be
+// readable and of a supported format version, the files bundle and
+// juju database dump must be present and complete, and, for archives
+// which have one, every file must match the manifest. The archive's
+// metadata is returned if it passes.
+func VerifyArchive(archive io.Reader) (*Metadata, error) {
+	r := bufio.NewReader(archive)
+	if encryption.IsEncrypted(r) {
+		return nil, errors.New("backup archive is encrypted; decrypt it first")
+	}
+	ws, err := NewArchiveWorkspaceReader(r)
+	if ws != nil {
+		defer ws.Close()
+	}
+	if err != nil {
+		return nil, errors.Annotate(err, "while unpacking archive")
+	}
+
+	meta, err := ws.Metadata()
+	if err != nil {
+		return nil, errors.Annotate(err, "while reading metadata")
+	}
+	if meta.FormatVersion > FormatVersion {
+		return nil, errors.Errorf(
+			"archive format version %d not supported (latest supported is %d)",
+			meta.FormatVersion, FormatVersion,
+		)
+	}
+	if meta.Origin.Version == version.Zero {
+		return nil, errors.New("metadata has no juju version")
+	}
+
+	if _, err := os.Stat(ws.FilesBundle); err != nil {
+		return nil, errors.Annotate(err, "files bundle missing")
+	}
+	if err := verifyDBDump(ws.DBDumpDir); err != nil {
+		return nil, errors.Trace(err)
+	}
+
+	if meta.FormatVersion < 1 {
+		logger.Debugf("archive predates manifests; not checking content checksums")
+		return meta, nil
+	}
+	if err := verifyManifest(ws.ArchivePaths); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return meta, nil
+}
+
+// verifyDBDump checks that the dump directory holds the juju database,
+// and that every collection dumped has both its data and metadata.
+func verifyDBDump(dumpDir string) error {
+	dbs, err := ioutil.ReadDir(dumpDir)
+	if err != nil {
+		return errors.Annotate(err, "database dump missing")
+	}
+	var foundJuju bool
+	for _, db := range dbs {
+		if !db.IsDir() {
+			continue
+		}
+		if db.Name() == "juju" {
+			foundJuju = true
+		}
+		if err := verifyDBDumpCollections(filepath.Join(dumpDir, db.Name())); err != nil {
+			return errors.Annotatef(err, "database %q", db.Name())
+		}
+	}
+	if !foundJuju {
+		return errors.New(`database dump incomplete: "juju" database missing`)
+	}
+	return nil
+}
+
+func verifyDBDumpCollections(dbDir string) error {
+	files, err := ioutil.ReadDir(dbDir)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	data := make(map[string]bool)
+	metadata := make(map[string]bool)
+	for _, file := range files {
+		name := file.Name()
+		switch {
+		case strings.HasSuffix(name, ".metadata.json"):
+			metadata[strings.TrimSuffix(name, ".metadata.json")] = true
+		case strings.HasSuffix(name, ".bson"):
+			data[strings.TrimSuffix(name, ".bson")] = true
+		}
+	}
+	for coll := range data {
+		if !metadata[coll] {
+			return errors.Errorf("dump of collection %q has no metadata", coll)
+		}
+	}
+	for coll := range metadata {
+		if !data[coll] {
+			return errors.Errorf("dump of collection %q has no data", coll)
+		}
+	}
+	return nil
+}
+
+// verifyManifest checks the archive contents against the manifest.
+func verifyManifest(paths ArchivePaths) error {
+	expected, err := readManifest(paths)
+	if errors.IsNotFound(err) {
+		return errors.New("archive has no manifest")
+	}
+	if err != nil {
+		return errors.Trace(err)
+	}
+	actual, err := buildManifest(paths)
+	if err != nil {
+		return errors.Trace(err)
+	}
+
+	var problems []string
+	for name, sum := range expected.Files {
+		actualSum, ok := actual.Files[name]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%s missing", name))
+		case actualSum != sum:
+			problems = append(problems, fmt.Sprintf("%s checksum mismatch", name))
+		}
+	}
+	for name := range actual.Files {
+		if _, ok := expected.Files[name]; !ok {
+			problems = append(problems, fmt.Sprintf("%s not in manifest", name))
+		}
+	}
+	if len(problems) > 0 {
+		sort.Strings(problems)
+		return errors.Errorf("archive contents do not match manifest: %s", strings.Join(problems, ", "))
+	}
+	return nil
+}
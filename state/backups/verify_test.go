@@ -0,0 +1,197 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backups_test
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"path"
+	"sort"
+	"strings"
+
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/backups/encryption"
+	backupstesting "github.com/juju/juju/state/backups/testing"
+	"github.com/juju/juju/testing"
+)
+
+type verifySuite struct {
+	testing.BaseSuite
+}
+
+var _ = gc.Suite(&verifySuite{})
+
+// archiveFiles returns the contents of a complete backup archive,
+// keyed by path relative to the content directory.
+func archiveFiles(c *gc.C) map[string]string {
+	meta := backupstesting.NewMetadataStarted()
+	metaFile, err := meta.AsJSONBuffer()
+	c.Assert(err, jc.ErrorIsNil)
+	metaData, err := ioutil.ReadAll(metaFile)
+	c.Assert(err, jc.ErrorIsNil)
+	return map[string]string{
+		"metadata.json":                         string(metaData),
+		"root.tar":                              "<files bundle>",
+		"dump/juju/machines.bson":               "<machines>",
+		"dump/juju/machines.metadata.json":      "{}",
+		"dump/admin/system.users.bson":          "<users>",
+		"dump/admin/system.users.metadata.json": "{}",
+	}
+}
+
+// addManifest adds a manifest of the files as they are now.
+func addManifest(files map[string]string) {
+	manifest := struct {
+		Files map[string]string `json:"files"`
+	}{make(map[string]string)}
+	for name, content := range files {
+		sum := sha256.Sum256([]byte(content))
+		manifest.Files[name] = hex.EncodeToString(sum[:])
+	}
+	data, _ := json.Marshal(manifest)
+	files["manifest.json"] = string(data)
+}
+
+// makeArchive returns a gzipped tarball of the files under the
+// content directory.
+func makeArchive(c *gc.C, files map[string]string) *bytes.Buffer {
+	var names []string
+	for name := range files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	gzw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gzw)
+	dirs := make(map[string]bool)
+	addDir := func(dir string) {
+		if dirs[dir] {
+			return
+		}
+		dirs[dir] = true
+		err := tw.WriteHeader(&tar.Header{
+			Name:     dir + "/",
+			Typeflag: tar.TypeDir,
+			Mode:     0755,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	addDir("juju-backup")
+	for _, name := range names {
+		full := path.Join("juju-backup", name)
+		parts := strings.Split(path.Dir(full), "/")
+		for i := range parts {
+			addDir(strings.Join(parts[:i+1], "/"))
+		}
+		err := tw.WriteHeader(&tar.Header{
+			Name:     full,
+			Typeflag: tar.TypeReg,
+			Mode:     0644,
+			Size:     int64(len(files[name])),
+		})
+		c.Assert(err, jc.ErrorIsNil)
+		_, err = tw.Write([]byte(files[name]))
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	c.Assert(tw.Close(), jc.ErrorIsNil)
+	c.Assert(gzw.Close(), jc.ErrorIsNil)
+	return &buf
+}
+
+func (s *verifySuite) TestVerifyArchive(c *gc.C) {
+	files := archiveFiles(c)
+	addManifest(files)
+
+	meta, err := backups.VerifyArchive(makeArchive(c, files))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.FormatVersion, gc.Equals, backups.FormatVersion)
+}
+
+func (s *verifySuite) TestVerifyArchiveWithoutManifest(c *gc.C) {
+	// Archives from before manifests were added only get the
+	// structural checks.
+	files := archiveFiles(c)
+	files["metadata.json"] = strings.Replace(files["metadata.json"], `,"FormatVersion":1`, "", 1)
+
+	meta, err := backups.VerifyArchive(makeArchive(c, files))
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(meta.FormatVersion, gc.Equals, 0)
+}
+
+func (s *verifySuite) TestVerifyArchiveManifestMissing(c *gc.C) {
+	_, err := backups.VerifyArchive(makeArchive(c, archiveFiles(c)))
+	c.Assert(err, gc.ErrorMatches, "archive has no manifest")
+}
+
+func (s *verifySuite) TestVerifyArchiveContentMismatch(c *gc.C) {
+	files := archiveFiles(c)
+	addManifest(files)
+	files["root.tar"] = "<tampered>"
+	delete(files, "dump/admin/system.users.bson")
+	delete(files, "dump/admin/system.users.metadata.json")
+	files["extra"] = "<extra>"
+
+	_, err := backups.VerifyArchive(makeArchive(c, files))
+	c.Assert(err, gc.ErrorMatches, "archive contents do not match manifest: "+
+		"dump/admin/system.users.bson missing, "+
+		"dump/admin/system.users.metadata.json missing, "+
+		"extra not in manifest, "+
+		"root.tar checksum mismatch")
+}
+
+func (s *verifySuite) TestVerifyArchiveUnsupportedFormat(c *gc.C) {
+	files := archiveFiles(c)
+	files["metadata.json"] = strings.Replace(files["metadata.json"], `"FormatVersion":1`, `"FormatVersion":99`, 1)
+
+	_, err := backups.VerifyArchive(makeArchive(c, files))
+	c.Assert(err, gc.ErrorMatches, `archive format version 99 not supported \(latest supported is 1\)`)
+}
+
+func (s *verifySuite) TestVerifyArchiveDBDumpIncomplete(c *gc.C) {
+	files := archiveFiles(c)
+	delete(files, "dump/juju/machines.metadata.json")
+	_, err := backups.VerifyArchive(makeArchive(c, files))
+	c.Check(err, gc.ErrorMatches, `database "juju": dump of collection "machines" has no metadata`)
+
+	files = archiveFiles(c)
+	delete(files, "dump/juju/machines.bson")
+	delete(files, "dump/juju/machines.metadata.json")
+	_, err = backups.VerifyArchive(makeArchive(c, files))
+	c.Check(err, gc.ErrorMatches, `database dump incomplete: "juju" database missing`)
+}
+
+func (s *verifySuite) TestVerifyArchiveFilesBundleMissing(c *gc.C) {
+	files := archiveFiles(c)
+	delete(files, "root.tar")
+	_, err := backups.VerifyArchive(makeArchive(c, files))
+	c.Assert(err, gc.ErrorMatches, "files bundle missing: .*")
+}
+
+func (s *verifySuite) TestVerifyArchiveEncrypted(c *gc.C) {
+	key, err := encryption.ParseKey([]byte("correct horse battery staple"))
+	c.Assert(err, jc.ErrorIsNil)
+	var buf bytes.Buffer
+	w, err := encryption.NewWriter(&buf, key)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = w.Write(makeArchive(c, archiveFiles(c)).Bytes())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w.Close(), jc.ErrorIsNil)
+
+	_, err = backups.VerifyArchive(&buf)
+	c.Assert(err, gc.ErrorMatches, "backup archive is encrypted; decrypt it first")
+}
+
+func (s *verifySuite) TestVerifyArchiveNotAnArchive(c *gc.C) {
+	_, err := backups.VerifyArchive(bytes.NewBufferString("not an archive"))
+	c.Assert(err, gc.ErrorMatches, "while unpacking archive: while uncompressing archive file: .*")
+}
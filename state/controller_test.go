@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/juju/controller"
 	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
 )
 
 type ControllerConfigSuite struct {
@@ -24,12 +25,30 @@ func (s *ControllerConfigSuite) TestControllerAndModelConfigInitialisation(c *gc
 	c.Assert(err, jc.ErrorIsNil)
 
 	optional := map[string]bool{
-		controller.IdentityURL:         true,
-		controller.IdentityPublicKey:   true,
-		controller.AutocertURLKey:      true,
-		controller.AutocertDNSNameKey:  true,
-		controller.AllowModelAccessKey: true,
-		controller.MongoMemoryProfile:  true,
+		controller.IdentityURL:             true,
+		controller.IdentityPublicKey:       true,
+		controller.AutocertURLKey:          true,
+		controller.AutocertDNSNameKey:      true,
+		controller.AllowModelAccessKey:     true,
+		controller.MongoMemoryProfile:      true,
+		controller.AuditLogMaxSize:         true,
+		controller.AuditLogMaxBackups:      true,
+		controller.AuditLogRotateInterval:  true,
+		controller.BackupSchedule:          true,
+		controller.BackupRetentionCount:    true,
+		controller.BackupStore:             true,
+		controller.BackupStoreS3Endpoint:   true,
+		controller.BackupStoreS3Region:     true,
+		controller.BackupStoreS3AccessKey:  true,
+		controller.BackupStoreS3SecretKey:  true,
+		controller.BackupEncryptionKey:     true,
+		controller.APITracingEnabled:       true,
+		controller.APIWebsocketCompression: true,
+		controller.APIUserRequestRate:      true,
+		controller.APIUserRequestBurst:     true,
+		controller.APIAgentRequestRate:     true,
+		controller.APIAgentRequestBurst:    true,
+		controller.APIFacadeRequestLimits:  true,
 	}
 	for _, controllerAttr := range controller.ControllerOnlyConfigAttributes {
 		v, ok := controllerSettings.Get(controllerAttr)
@@ -47,3 +66,17 @@ func (s *ControllerConfigSuite) TestControllerConfig(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 	c.Assert(cfg["controller-uuid"], gc.Equals, m.ControllerUUID())
 }
+
+func (s *ControllerConfigSuite) TestWatchControllerConfig(c *gc.C) {
+	w := s.State.WatchControllerConfig()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewNotifyWatcherC(c, s.State, w)
+	wc.AssertOneChange()
+
+	settings, err := s.State.ReadSettings(state.ControllersC, "controllerSettings")
+	c.Assert(err, jc.ErrorIsNil)
+	settings.Set(controller.BackupSchedule, "@daily")
+	_, err = settings.Write()
+	c.Assert(err, jc.ErrorIsNil)
+	wc.AssertOneChange()
+}
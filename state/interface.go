@@ -142,8 +142,13 @@ type ActionReceiver interface {
 	// ActionReceiver.
 	AddAction(name string, payload map[string]interface{}) (Action, error)
 
+	// AddActionWithOptions queues an action with the given name,
+	// payload and options for this ActionReceiver.
+	AddActionWithOptions(name string, payload map[string]interface{}, options ActionOptions) (Action, error)
+
 	// CancelAction removes a pending Action from the queue for this
-	// ActionReceiver and marks it as cancelled.
+	// ActionReceiver and marks it as cancelled; a running Action is
+	// marked as aborting.
 	CancelAction(action Action) (Action, error)
 
 	// WatchActionNotifications returns a StringsWatcher that will notify
@@ -197,6 +202,22 @@ type Action interface {
 	// Action.
 	Enqueued() time.Time
 
+	// Timeout returns how long the action may run before it is
+	// stopped, or zero if it may run indefinitely.
+	Timeout() time.Duration
+
+	// StreamOutput returns whether the output of the action should be
+	// logged as messages while it runs.
+	StreamOutput() bool
+
+	// Messages returns the progress messages logged by the action,
+	// oldest first.
+	Messages() []ActionMessage
+
+	// Operation returns the id of the operation the action is part
+	// of, or "" if it was enqueued on its own.
+	Operation() string
+
 	// Started returns the time that the Action execution began.
 	Started() time.Time
 
@@ -220,6 +241,15 @@ type Action interface {
 	// Finish removes action from the pending queue and captures the output
 	// and end state of the action.
 	Finish(results ActionResults) (Action, error)
+
+	// Cancel finishes a pending action as cancelled, or marks a running
+	// action as aborting so that the unit agent stops it.
+	Cancel(message string) (Action, error)
+
+	// Log adds timestamped progress messages to the action, numbering
+	// them after those already logged. It fails if the action is not
+	// running.
+	Log(messages ...string) error
 }
 
 // ApplicationEntity represents a local or remote application.
@@ -4,8 +4,12 @@
 package audit
 
 import (
+	"regexp"
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/version"
+	"gopkg.in/mgo.v2/bson"
 
 	"github.com/juju/juju/audit"
 	"github.com/juju/juju/mongo/utils"
@@ -14,6 +18,9 @@ import (
 // auditEntryDoc is the doc that is persisted to the audit collection.
 type auditEntryDoc struct {
 
+	// ID is assigned by the database when the entry is written.
+	ID bson.ObjectId `bson:"_id,omitempty"`
+
 	// JujuServerVersion is the version of jujud that recorded this
 	// entry.
 	JujuServerVersion version.Number `bson:"juju-server-version"`
@@ -26,6 +33,10 @@ type auditEntryDoc struct {
 	// unmarshaled via time.Time::UnmarshalText.
 	Timestamp string `bson:"timestamp"`
 
+	// Time is the Timestamp expressed as nanoseconds since the
+	// epoch. It is used to query entries by time range.
+	Time int64 `bson:"time"`
+
 	// RemoteAddress is the IP of the machine from which the
 	// audit-event was triggered.
 	RemoteAddress string `bson:"remote-address"`
@@ -75,6 +86,7 @@ func auditEntryDocFromAuditEntry(auditEntry audit.AuditEntry) (auditEntryDoc, er
 		JujuServerVersion: auditEntry.JujuServerVersion,
 		ModelUUID:         auditEntry.ModelUUID,
 		Timestamp:         string(timeAsBlob),
+		Time:              auditEntry.Timestamp.UnixNano(),
 		RemoteAddress:     auditEntry.RemoteAddress,
 		OriginType:        auditEntry.OriginType,
 		OriginName:        auditEntry.OriginName,
@@ -82,3 +94,116 @@ func auditEntryDocFromAuditEntry(auditEntry audit.AuditEntry) (auditEntryDoc, er
 		Data:              utils.EscapeKeys(auditEntry.Data),
 	}, nil
 }
+
+// Filter holds the criteria used to select audit entries. Zero
+// valued fields do not restrict the result.
+type Filter struct {
+	// ModelUUID restricts the entries to those recorded against
+	// the given model.
+	ModelUUID string
+
+	// OriginName restricts the entries to those triggered by the
+	// given origin, e.g. "user-bob".
+	OriginName string
+
+	// Operation restricts the entries to those whose operation
+	// contains the given text.
+	Operation string
+
+	// After restricts the entries to those recorded at or after
+	// the given time.
+	After time.Time
+
+	// Before restricts the entries to those recorded before the
+	// given time.
+	Before time.Time
+
+	// Limit is the maximum number of entries to return. The most
+	// recent entries are preferred, unless Oldest is set.
+	Limit int
+
+	// Oldest indicates that when more entries match than Limit,
+	// the oldest entries should be returned.
+	Oldest bool
+}
+
+// FindAuditEntriesFn creates a closure which when passed a Filter
+// will return the matching entries from the audit collection, oldest
+// first. The findDocs function is expected to return at most limit
+// documents (if limit is positive) matching the query, in the given
+// sort order, decoded into the supplied slice pointer.
+func FindAuditEntriesFn(
+	collectionName string,
+	findDocs func(collectionName string, query bson.D, sort string, limit int, docs interface{}) error,
+) func(Filter) ([]audit.AuditEntry, error) {
+	return func(filter Filter) ([]audit.AuditEntry, error) {
+		// Sort on the recorded time rather than the natural order,
+		// so that the time indexes can be used and the order does
+		// not depend on how the collection was created.
+		sort := "-time"
+		if filter.Oldest {
+			sort = "time"
+		}
+		var docs []auditEntryDoc
+		if err := findDocs(collectionName, filterQuery(filter), sort, filter.Limit, &docs); err != nil {
+			return nil, errors.Trace(err)
+		}
+		entries := make([]audit.AuditEntry, len(docs))
+		for i, doc := range docs {
+			entry, err := auditEntryFromAuditEntryDoc(doc)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			if filter.Oldest {
+				entries[i] = entry
+			} else {
+				entries[len(docs)-1-i] = entry
+			}
+		}
+		return entries, nil
+	}
+}
+
+func filterQuery(filter Filter) bson.D {
+	var query bson.D
+	if filter.ModelUUID != "" {
+		query = append(query, bson.DocElem{"model-uuid", filter.ModelUUID})
+	}
+	if filter.OriginName != "" {
+		query = append(query, bson.DocElem{"origin-name", filter.OriginName})
+	}
+	if filter.Operation != "" {
+		query = append(query, bson.DocElem{"operation", bson.RegEx{
+			Pattern: regexp.QuoteMeta(filter.Operation),
+		}})
+	}
+	timeRange := bson.M{}
+	if !filter.After.IsZero() {
+		timeRange["$gte"] = filter.After.UnixNano()
+	}
+	if !filter.Before.IsZero() {
+		timeRange["$lt"] = filter.Before.UnixNano()
+	}
+	if len(timeRange) > 0 {
+		query = append(query, bson.DocElem{"time", timeRange})
+	}
+	return query
+}
+
+func auditEntryFromAuditEntryDoc(doc auditEntryDoc) (audit.AuditEntry, error) {
+	var timestamp time.Time
+	if err := timestamp.UnmarshalText([]byte(doc.Timestamp)); err != nil {
+		return audit.AuditEntry{}, errors.Annotatef(err, "parsing audit entry timestamp %q", doc.Timestamp)
+	}
+	return audit.AuditEntry{
+		ID:                doc.ID.Hex(),
+		JujuServerVersion: doc.JujuServerVersion,
+		ModelUUID:         doc.ModelUUID,
+		Timestamp:         timestamp.UTC(),
+		RemoteAddress:     doc.RemoteAddress,
+		OriginType:        doc.OriginType,
+		OriginName:        doc.OriginName,
+		Operation:         doc.Operation,
+		Data:              utils.UnescapeKeys(doc.Data),
+	}, nil
+}
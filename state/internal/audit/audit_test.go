@@ -4,6 +4,8 @@
 package audit_test
 
 import (
+	"time"
+
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
@@ -58,6 +60,7 @@ func (*AuditSuite) TestPutAuditEntry_PersistAuditEntry(c *gc.C) {
 			"juju-server-version": requested.JujuServerVersion,
 			"model-uuid":          requested.ModelUUID,
 			"timestamp":           string(requestedTimeBlob),
+			"time":                requested.Timestamp.UnixNano(),
 			"remote-address":      "8.8.8.8",
 			"origin-type":         requested.OriginType,
 			"origin-name":         requested.OriginName,
@@ -111,3 +114,99 @@ func (*AuditSuite) TestPutAuditEntry_ValidateAuditEntry(c *gc.C) {
 	err := putAuditEntry(auditEntry)
 	c.Check(err, gc.ErrorMatches, validationErr.Error())
 }
+
+func (*AuditSuite) TestFindAuditEntries_BuildsQuery(c *gc.C) {
+	after := time.Date(2017, time.March, 1, 0, 0, 0, 0, time.UTC)
+	before := after.Add(time.Hour)
+	var findDocsCalled bool
+	findDocs := func(collectionName string, query bson.D, sort string, limit int, docs interface{}) error {
+		findDocsCalled = true
+		c.Check(collectionName, gc.Equals, "audit.log")
+		c.Check(sort, gc.Equals, "-time")
+		c.Check(limit, gc.Equals, 10)
+		c.Check(query, jc.DeepEquals, bson.D{
+			{"model-uuid", coretesting.ModelTag.Id()},
+			{"origin-name", "user-bob"},
+			{"operation", bson.RegEx{Pattern: `Application:v3 - Destroy\(\)`}},
+			{"time", bson.M{"$gte": after.UnixNano(), "$lt": before.UnixNano()}},
+		})
+		return nil
+	}
+
+	findAuditEntries := stateaudit.FindAuditEntriesFn("audit.log", findDocs)
+	entries, err := findAuditEntries(stateaudit.Filter{
+		ModelUUID:  coretesting.ModelTag.Id(),
+		OriginName: "user-bob",
+		Operation:  "Application:v3 - Destroy()",
+		After:      after,
+		Before:     before,
+		Limit:      10,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(entries, gc.HasLen, 0)
+	c.Assert(findDocsCalled, jc.IsTrue)
+}
+
+func (*AuditSuite) TestFindAuditEntries_EmptyFilter(c *gc.C) {
+	findDocs := func(_ string, query bson.D, _ string, limit int, _ interface{}) error {
+		c.Check(query, gc.HasLen, 0)
+		c.Check(limit, gc.Equals, 0)
+		return nil
+	}
+	findAuditEntries := stateaudit.FindAuditEntriesFn("audit.log", findDocs)
+	_, err := findAuditEntries(stateaudit.Filter{})
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (*AuditSuite) TestFindAuditEntries_RoundTrip(c *gc.C) {
+	first := audit.AuditEntry{
+		JujuServerVersion: version.MustParse("1.0.0"),
+		ModelUUID:         coretesting.ModelTag.Id(),
+		Timestamp:         coretesting.NonZeroTime().UTC(),
+		RemoteAddress:     "8.8.8.8",
+		OriginType:        "API request",
+		OriginName:        "user-bob",
+		Operation:         "Client:v1 - FullStatus",
+		Data: map[string]interface{}{
+			"$a.b": "c",
+		},
+	}
+	second := first
+	second.Timestamp = first.Timestamp.Add(time.Second)
+	second.Operation = "Application:v3 - Destroy"
+
+	var stored []interface{}
+	insertDocs := func(_ string, docs ...interface{}) error {
+		stored = append(stored, docs...)
+		return nil
+	}
+	putAuditEntry := stateaudit.PutAuditEntryFn("audit.log", insertDocs)
+	c.Assert(putAuditEntry(first), jc.ErrorIsNil)
+	c.Assert(putAuditEntry(second), jc.ErrorIsNil)
+
+	findDocs := func(_ string, _ bson.D, sort string, _ int, docs interface{}) error {
+		// Emulate mongo returning the documents in the requested order.
+		ordered := stored
+		if sort == "-time" {
+			ordered = nil
+			for i := len(stored) - 1; i >= 0; i-- {
+				ordered = append(ordered, stored[i])
+			}
+		}
+		data, err := bson.Marshal(bson.M{"docs": ordered})
+		c.Assert(err, jc.ErrorIsNil)
+		var wrapper struct {
+			Docs bson.Raw `bson:"docs"`
+		}
+		c.Assert(bson.Unmarshal(data, &wrapper), jc.ErrorIsNil)
+		return wrapper.Docs.Unmarshal(docs)
+	}
+	findAuditEntries := stateaudit.FindAuditEntriesFn("audit.log", findDocs)
+	entries, err := findAuditEntries(stateaudit.Filter{})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, jc.DeepEquals, []audit.AuditEntry{first, second})
+
+	entries, err = findAuditEntries(stateaudit.Filter{Oldest: true})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, jc.DeepEquals, []audit.AuditEntry{first, second})
+}
@@ -15,6 +15,8 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils"
+	"github.com/juju/utils/clock"
 	"github.com/juju/utils/deque"
 	"github.com/juju/utils/set"
 	"github.com/juju/version"
@@ -23,6 +25,7 @@ import (
 	"gopkg.in/mgo.v2/bson"
 	"gopkg.in/tomb.v1"
 
+	"github.com/juju/juju/environs/config"
 	"github.com/juju/juju/mongo"
 )
 
@@ -333,6 +336,7 @@ type LogRecord struct {
 type LogTailerParams struct {
 	StartID       int64
 	StartTime     time.Time
+	EndTime       time.Time // Records from this time on are excluded.
 	MinLevel      loggo.Level
 	InitialLines  int
 	NoTail        bool
@@ -340,8 +344,15 @@ type LogTailerParams struct {
 	ExcludeEntity []string
 	IncludeModule []string
 	ExcludeModule []string
-	Oplog         *mgo.Collection // For testing only
-	AllModels     bool
+	// IncludeMessage and ExcludeMessage are regular expressions
+	// matched against log messages.
+	IncludeMessage []string
+	ExcludeMessage []string
+	Oplog          *mgo.Collection // For testing only
+	AllModels      bool
+	// Clock is used to decide when no more records before EndTime
+	// can arrive. It defaults to the wall clock.
+	Clock clock.Clock
 }
 
 // oplogOverlap is used to decide on the initial oplog timestamp to
@@ -361,6 +372,12 @@ const oplogOverlap = time.Minute
 // output of large broken models with logging at DEBUG.
 var maxRecentLogIds = int(oplogOverlap.Minutes() * 150000)
 
+// endTimeOverlap is how long the LogTailer keeps tailing the oplog
+// after the end time has passed, so that records timestamped before
+// the end time are still reported if their writes are delayed, or the
+// clocks of the Juju cluster hosts are skewed.
+const endTimeOverlap = oplogOverlap
+
 // LogTailerState describes the methods on State required for logging to
 // the database.
 type LogTailerState interface {
@@ -377,6 +394,10 @@ func NewLogTailer(st LogTailerState, params *LogTailerParams) (LogTailer, error)
 		return nil, errors.NewNotValid(nil, "not allowed to tail logs from all models: not a controller")
 	}
 
+	clk := params.Clock
+	if clk == nil {
+		clk = clock.WallClock
+	}
 	session := st.MongoSession().Copy()
 	t := &logTailer{
 		modelUUID: st.ModelUUID(),
@@ -385,6 +406,7 @@ func NewLogTailer(st LogTailerState, params *LogTailerParams) (LogTailer, error)
 		params:    params,
 		logCh:     make(chan *LogRecord),
 		recentIds: newRecentIdTracker(maxRecentLogIds),
+		clock:     clk,
 	}
 	go func() {
 		err := t.loop()
@@ -406,6 +428,7 @@ type logTailer struct {
 	lastID    int64
 	lastTime  time.Time
 	recentIds *recentIdTracker
+	clock     clock.Clock
 }
 
 // Logs implements the LogTailer interface.
@@ -438,6 +461,10 @@ func (t *logTailer) loop() error {
 	if t.params.NoTail {
 		return nil
 	}
+	if !t.params.EndTime.IsZero() && !t.clock.Now().Before(t.tailEndTime()) {
+		// No more records can be logged before the end time.
+		return nil
+	}
 
 	err = t.tailOplog()
 	return errors.Trace(err)
@@ -511,11 +538,19 @@ func (t *logTailer) tailOplog() error {
 	logger.Tracef("LogTailer starting oplog tailing: recent id count=%d, lastTime=%s, minOplogTs=%s",
 		recentIds.Length(), t.lastTime, minOplogTs)
 
+	var endReached <-chan time.Time
+	if !t.params.EndTime.IsZero() {
+		endReached = t.clock.After(t.tailEndTime().Sub(t.clock.Now()))
+	}
+
 	skipCount := 0
 	for {
 		select {
 		case <-t.tomb.Dying():
 			return errors.Trace(tomb.ErrDying)
+		case <-endReached:
+			logger.Tracef("LogTailer end time %s reached", t.params.EndTime)
+			return nil
 		case oplogDoc, ok := <-oplogTailer.Out():
 			if !ok {
 				return errors.Annotate(oplogTailer.Err(), "oplog tailer died")
@@ -548,10 +583,23 @@ func (t *logTailer) tailOplog() error {
 	}
 }
 
+// tailEndTime returns the time after which no more records before the
+// end time are expected to arrive.
+func (t *logTailer) tailEndTime() time.Time {
+	return t.params.EndTime.Add(endTimeOverlap)
+}
+
 func (t *logTailer) paramsToSelector(params *LogTailerParams, prefix string) bson.D {
 	sel := bson.D{}
-	if !params.StartTime.IsZero() {
-		sel = append(sel, bson.DocElem{"t", bson.M{"$gte": params.StartTime.UnixNano()}})
+	if !params.StartTime.IsZero() || !params.EndTime.IsZero() {
+		timeSel := bson.M{}
+		if !params.StartTime.IsZero() {
+			timeSel["$gte"] = params.StartTime.UnixNano()
+		}
+		if !params.EndTime.IsZero() {
+			timeSel["$lt"] = params.EndTime.UnixNano()
+		}
+		sel = append(sel, bson.DocElem{"t", timeSel})
 	}
 	if !params.AllModels {
 		sel = append(sel, bson.DocElem{"e", t.modelUUID})
@@ -575,6 +623,14 @@ func (t *logTailer) paramsToSelector(params *LogTailerParams, prefix string) bso
 		sel = append(sel,
 			bson.DocElem{"m", bson.M{"$not": bson.RegEx{Pattern: makeModulePattern(params.ExcludeModule)}}})
 	}
+	if len(params.IncludeMessage) > 0 {
+		sel = append(sel,
+			bson.DocElem{"x", bson.RegEx{Pattern: makeMessagePattern(params.IncludeMessage)}})
+	}
+	if len(params.ExcludeMessage) > 0 {
+		sel = append(sel,
+			bson.DocElem{"x", bson.M{"$not": bson.RegEx{Pattern: makeMessagePattern(params.ExcludeMessage)}}})
+	}
 	if prefix != "" {
 		for i, elem := range sel {
 			sel[i].Name = prefix + elem.Name
@@ -601,6 +657,10 @@ func makeModulePattern(modules []string) string {
 	return `^(` + strings.Join(patterns, "|") + `)(\..+)?$`
 }
 
+func makeMessagePattern(patterns []string) string {
+	return `(` + strings.Join(patterns, ")|(") + `)`
+}
+
 func newRecentIdTracker(maxLen int) *recentIdTracker {
 	return &recentIdTracker{
 		ids: deque.NewWithMaxLen(maxLen),
@@ -685,11 +745,87 @@ func logDocToRecord(doc *logDoc) (*LogRecord, error) {
 	return rec, nil
 }
 
+// ModelLogRetention overrides the controller-wide log pruning limits
+// for a single model.
+type ModelLogRetention struct {
+	// MinLogTime, if not zero, is the minimum log time for the
+	// model. It can only shorten how long the model's logs are
+	// kept: the later of it and the controller-wide minimum log
+	// time applies.
+	MinLogTime time.Time
+
+	// MaxLogsMB, if not zero, is the most space the model's logs
+	// may use. The oldest of the model's logs are removed to bring
+	// it back under the limit.
+	MaxLogsMB int
+}
+
+// AllModelLogRetention returns the log retention limits that models
+// have set with the logs-max-age and logs-max-size config settings,
+// keyed by model UUID, with maximum ages counted back from now. The
+// settings of all models are read with a single query.
+func (st *State) AllModelLogRetention(now time.Time) (map[string]ModelLogRetention, error) {
+	settings, closer := st.getRawCollection(settingsC)
+	defer closer()
+
+	maxAgeField := "settings." + config.LogsMaxAgeKey
+	maxSizeField := "settings." + config.LogsMaxSizeKey
+	var docs []struct {
+		DocID     string `bson:"_id"`
+		ModelUUID string `bson:"model-uuid"`
+		Settings  struct {
+			MaxAge  string `bson:"logs-max-age"`
+			MaxSize string `bson:"logs-max-size"`
+		} `bson:"settings"`
+	}
+	err := settings.Find(bson.D{{"$or", []bson.D{
+		{{maxAgeField, bson.D{{"$exists", true}}}},
+		{{maxSizeField, bson.D{{"$exists", true}}}},
+	}}}).Select(bson.D{
+		{"model-uuid", 1},
+		{maxAgeField, 1},
+		{maxSizeField, 1},
+	}).All(&docs)
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read model log retention settings")
+	}
+
+	retention := make(map[string]ModelLogRetention)
+	for _, doc := range docs {
+		if doc.DocID != ensureModelUUID(doc.ModelUUID, modelGlobalKey) {
+			// Charm settings can have the same names.
+			continue
+		}
+		// The values were validated when the model config was set;
+		// zero values mean the setting is disabled.
+		var modelRetention ModelLogRetention
+		if maxAge, err := time.ParseDuration(doc.Settings.MaxAge); err == nil && maxAge > 0 {
+			modelRetention.MinLogTime = now.Add(-maxAge)
+		}
+		if maxSizeMB, err := utils.ParseSize(doc.Settings.MaxSize); err == nil && maxSizeMB > 0 {
+			modelRetention.MaxLogsMB = int(maxSizeMB)
+		}
+		if modelRetention != (ModelLogRetention{}) {
+			retention[doc.ModelUUID] = modelRetention
+		}
+	}
+	return retention, nil
+}
+
 // PruneLogs removes old log documents in order to control the size of
 // logs collection. All logs older than minLogTime are
 // removed. Further removal is also performed if the logs collection
 // size is greater than maxLogsMB.
 func PruneLogs(st MongoSessioner, minLogTime time.Time, maxLogsMB int) error {
+	return PruneLogsWithRetention(st, minLogTime, maxLogsMB, nil)
+}
+
+// PruneLogsWithRetention removes old log documents like PruneLogs,
+// but first applies the retention limits for each model in retention,
+// keyed by model UUID. Each model's limits are enforced independently
+// of the others, and a model's logs are removed if either its own or
+// the controller-wide limits say so.
+func PruneLogsWithRetention(st MongoSessioner, minLogTime time.Time, maxLogsMB int, retention map[string]ModelLogRetention) error {
 	session, logsColl := initLogsSession(st)
 	defer session.Close()
 
@@ -703,14 +839,27 @@ func PruneLogs(st MongoSessioner, minLogTime time.Time, maxLogsMB int) error {
 	// Remove old log entries (per model UUID to take advantage
 	// of indexes on the logs collection).
 	for _, modelUUID := range modelUUIDs {
+		modelRetention := retention[modelUUID]
+		modelMinLogTime := minLogTime
+		if modelRetention.MinLogTime.After(modelMinLogTime) {
+			modelMinLogTime = modelRetention.MinLogTime
+		}
 		removeInfo, err := logsColl.RemoveAll(bson.M{
 			"e": modelUUID,
-			"t": bson.M{"$lt": minLogTime.UnixNano()},
+			"t": bson.M{"$lt": modelMinLogTime.UnixNano()},
 		})
 		if err != nil {
 			return errors.Annotate(err, "failed to prune logs by time")
 		}
 		pruneCounts[modelUUID] = removeInfo.Removed
+
+		if modelRetention.MaxLogsMB > 0 {
+			removed, err := pruneModelLogsBySize(logsColl, modelUUID, modelRetention.MaxLogsMB)
+			if err != nil {
+				return errors.Annotatef(err, "failed to prune logs for model %s by size", modelUUID)
+			}
+			pruneCounts[modelUUID] += removed
+		}
 	}
 
 	// Do further pruning if the logs collection is over the maximum size.
@@ -733,30 +882,11 @@ func PruneLogs(st MongoSessioner, minLogTime time.Time, maxLogsMB int) error {
 
 		// Remove the oldest 1% of log records for the model.
 		toRemove := int(float64(count) * 0.01)
-
-		// Find the threshold timestammp to start removing from.
-		// NOTE: this assumes that there are no more logs being added
-		// for the time range being pruned (which should be true for
-		// any realistic minimum log collection size).
-		tsQuery := logsColl.Find(bson.M{"e": modelUUID}).Sort("e", "t")
-		tsQuery = tsQuery.Skip(toRemove)
-		tsQuery = tsQuery.Select(bson.M{"t": 1})
-		var doc bson.M
-		err = tsQuery.One(&doc)
-		if err != nil {
-			return errors.Annotate(err, "log pruning timestamp query failed")
-		}
-		thresholdTs := doc["t"]
-
-		// Remove old records.
-		removeInfo, err := logsColl.RemoveAll(bson.M{
-			"e": modelUUID,
-			"t": bson.M{"$lt": thresholdTs},
-		})
+		removed, err := removeOldestLogs(logsColl, modelUUID, toRemove)
 		if err != nil {
-			return errors.Annotate(err, "log pruning failed")
+			return errors.Trace(err)
 		}
-		pruneCounts[modelUUID] += removeInfo.Removed
+		pruneCounts[modelUUID] += removed
 	}
 
 	for modelUUID, count := range pruneCounts {
@@ -767,6 +897,59 @@ func PruneLogs(st MongoSessioner, minLogTime time.Time, maxLogsMB int) error {
 	return nil
 }
 
+// pruneModelLogsBySize removes the oldest log records for a model
+// until its logs are estimated to use no more than maxLogsMB. It
+// returns the number of records removed.
+func pruneModelLogsBySize(logsColl *mgo.Collection, modelUUID string, maxLogsMB int) (int, error) {
+	count, err := getLogCountForEnv(logsColl, modelUUID)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	avgSize, err := getAverageDocSize(logsColl)
+	if err != nil {
+		return 0, errors.Annotate(err, "failed to retrieve average log size")
+	}
+	if avgSize <= 0 {
+		return 0, nil
+	}
+	maxCount := int(float64(maxLogsMB) * humanize.MiByte / avgSize)
+	if count <= maxCount {
+		return 0, nil
+	}
+	removed, err := removeOldestLogs(logsColl, modelUUID, count-maxCount)
+	return removed, errors.Trace(err)
+}
+
+// removeOldestLogs removes approximately the oldest toRemove log
+// records for a model, returning the number actually removed.
+func removeOldestLogs(logsColl *mgo.Collection, modelUUID string, toRemove int) (int, error) {
+	// Find the threshold timestammp to start removing from.
+	// NOTE: this assumes that there are no more logs being added
+	// for the time range being pruned (which should be true for
+	// any realistic minimum log collection size).
+	tsQuery := logsColl.Find(bson.M{"e": modelUUID}).Sort("e", "t")
+	tsQuery = tsQuery.Skip(toRemove)
+	tsQuery = tsQuery.Select(bson.M{"t": 1})
+	var doc bson.M
+	selector := bson.M{"e": modelUUID}
+	err := tsQuery.One(&doc)
+	switch err {
+	case nil:
+		selector["t"] = bson.M{"$lt": doc["t"]}
+	case mgo.ErrNotFound:
+		// All of the model's records are to be removed.
+	default:
+		return 0, errors.Annotate(err, "log pruning timestamp query failed")
+	}
+
+	// Remove old records.
+	removeInfo, err := logsColl.RemoveAll(selector)
+	if err != nil {
+		return 0, errors.Annotate(err, "log pruning failed")
+	}
+	return removeInfo.Removed, nil
+}
+
 // initLogsSession creates a new session suitable for logging updates,
 // returning the session and a logs mgo.Collection connected to that
 // session.
@@ -796,6 +979,28 @@ func getCollectionMB(coll *mgo.Collection) (int, error) {
 	return result["size"].(int), nil
 }
 
+// getAverageDocSize returns the average size, in bytes, of the
+// documents in a MongoDB collection.
+func getAverageDocSize(coll *mgo.Collection) (float64, error) {
+	var result bson.M
+	err := coll.Database.Run(bson.D{
+		{"collStats", coll.Name},
+	}, &result)
+	if err != nil {
+		return 0, errors.Trace(err)
+	}
+	switch size := result["avgObjSize"].(type) {
+	case int:
+		return float64(size), nil
+	case int64:
+		return float64(size), nil
+	case float64:
+		return size, nil
+	}
+	// The collection is empty.
+	return 0, nil
+}
+
 // getEnvsInLogs returns the unique model UUIDs that exist in
 // the logs collection. This uses the one of the indexes on the
 // collection and should be fast.
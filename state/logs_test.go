@@ -10,6 +10,7 @@ import (
 	"time"
 
 	"github.com/juju/loggo"
+	jujutesting "github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/version"
 	gc "gopkg.in/check.v1"
@@ -19,6 +20,7 @@ import (
 
 	"github.com/juju/juju/state"
 	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
 	jujuversion "github.com/juju/juju/version"
 )
 
@@ -300,6 +302,115 @@ func (s *LogsSuite) TestPruneLogsBySize(c *gc.C) {
 	assertLatestTs(s2)
 }
 
+func (s *LogsSuite) TestPruneLogsWithRetentionByTime(c *gc.C) {
+	now := truncateDBTime(coretesting.NonZeroTime())
+	s0 := s.State
+	s.generateLogs(c, s0, now.Add(-2*time.Hour), 10)
+	s1 := s.Factory.MakeModel(c, nil)
+	defer s1.Close()
+	s.generateLogs(c, s1, now.Add(-2*time.Hour), 10)
+	s2 := s.Factory.MakeModel(c, nil)
+	defer s2.Close()
+	s.generateLogs(c, s2, now.Add(-2*time.Hour), 10)
+
+	// The controller-wide limit keeps logs for three hours. The
+	// second model keeps its logs for just an hour, but the third
+	// model cannot keep its logs for longer than the controller.
+	noPruneMB := 100
+	err := state.PruneLogsWithRetention(s.State, now.Add(-3*time.Hour), noPruneMB, map[string]state.ModelLogRetention{
+		s1.ModelUUID(): {MinLogTime: now.Add(-time.Hour)},
+		s2.ModelUUID(): {MinLogTime: now.Add(-24 * time.Hour)},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.countLogs(c, s0), gc.Equals, 10)
+	c.Check(s.countLogs(c, s1), gc.Equals, 0)
+	c.Check(s.countLogs(c, s2), gc.Equals, 10)
+
+	err = state.PruneLogsWithRetention(s.State, now.Add(-time.Hour), noPruneMB, map[string]state.ModelLogRetention{
+		s2.ModelUUID(): {MinLogTime: now.Add(-24 * time.Hour)},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(s.countLogs(c, s2), gc.Equals, 0)
+}
+
+func (s *LogsSuite) TestPruneLogsWithRetentionBySize(c *gc.C) {
+	now := truncateDBTime(coretesting.NonZeroTime())
+	s0 := s.State
+	startingLogsS0 := 10000
+	s.generateLogs(c, s0, now, startingLogsS0)
+	s1 := s.Factory.MakeModel(c, nil)
+	defer s1.Close()
+	startingLogsS1 := 10000
+	s.generateLogs(c, s1, now, startingLogsS1)
+
+	// The noisy first model is limited to 1 MiB of logs; the second
+	// model's logs are left alone even though it has as many.
+	tsNoPrune := now.Add(-3 * 24 * time.Hour)
+	noPruneMB := 100
+	err := state.PruneLogsWithRetention(s.State, tsNoPrune, noPruneMB, map[string]state.ModelLogRetention{
+		s0.ModelUUID(): {MaxLogsMB: 1},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	c.Check(s.countLogs(c, s0), jc.LessThan, startingLogsS0)
+	c.Check(s.countLogs(c, s1), gc.Equals, startingLogsS1)
+
+	// The latest log records are kept.
+	var doc bson.M
+	err = s.logsColl.Find(bson.M{"e": s0.ModelUUID()}).Sort("-t").One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(doc["t"], gc.Equals, now.UnixNano())
+}
+
+func (s *LogsSuite) TestPruneLogsWithRetentionBySizeSingleModel(c *gc.C) {
+	now := truncateDBTime(coretesting.NonZeroTime())
+	startingLogs := 10000
+	s.generateLogs(c, s.State, now, startingLogs)
+
+	tsNoPrune := now.Add(-3 * 24 * time.Hour)
+	noPruneMB := 100
+	err := state.PruneLogsWithRetention(s.State, tsNoPrune, noPruneMB, map[string]state.ModelLogRetention{
+		s.State.ModelUUID(): {MaxLogsMB: 1},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The model's logs are pruned to an estimated 1 MiB, newest first.
+	avgSize, err := state.GetAverageDocSize(s.logsColl)
+	c.Assert(err, jc.ErrorIsNil)
+	count := s.countLogs(c, s.State)
+	c.Check(count, jc.LessThan, startingLogs)
+	c.Check(float64(count)*avgSize, jc.LessThan, float64(1024*1024)+avgSize)
+
+	var doc bson.M
+	err = s.logsColl.Find(bson.M{"e": s.State.ModelUUID()}).Sort("-t").One(&doc)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Check(doc["t"], gc.Equals, now.UnixNano())
+}
+
+func (s *LogsSuite) TestAllModelLogRetention(c *gc.C) {
+	now := coretesting.NonZeroTime()
+	st1 := s.Factory.MakeModel(c, &factory.ModelParams{
+		ConfigAttrs: coretesting.Attrs{"logs-max-age": "1h"},
+	})
+	defer st1.Close()
+	st2 := s.Factory.MakeModel(c, &factory.ModelParams{
+		ConfigAttrs: coretesting.Attrs{"logs-max-age": "0s", "logs-max-size": "2M"},
+	})
+	defer st2.Close()
+	st3 := s.Factory.MakeModel(c, &factory.ModelParams{
+		ConfigAttrs: coretesting.Attrs{"logs-max-age": "0s"},
+	})
+	defer st3.Close()
+
+	retention, err := s.State.AllModelLogRetention(now)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(retention, jc.DeepEquals, map[string]state.ModelLogRetention{
+		st1.ModelUUID(): {MinLogTime: now.Add(-time.Hour)},
+		st2.ModelUUID(): {MaxLogsMB: 2},
+	})
+}
+
 func (s *LogsSuite) generateLogs(c *gc.C, st *state.State, endTime time.Time, count int) {
 	dbLogger := state.NewEntityDbLogger(st, names.NewMachineTag("0"), jujuversion.Current)
 	defer dbLogger.Close()
@@ -374,6 +485,69 @@ func (s *LogTailerSuite) TestTimeFiltering(c *gc.C) {
 
 }
 
+func (s *LogTailerSuite) TestEndTimeInPast(c *gc.C) {
+	startT := coretesting.NonZeroTime()
+	endT := startT.Add(5 * time.Second)
+	s.writeLogsT(c,
+		startT.Add(-5*time.Second), startT.Add(-time.Millisecond), 5,
+		logTemplate{Message: "too early"},
+	)
+	want := logTemplate{Message: "want"}
+	s.writeLogsT(c, startT, endT, 5, want)
+	s.writeLogsT(c, endT, endT.Add(5*time.Second), 5, logTemplate{Message: "too late"})
+
+	tailer, err := state.NewLogTailer(s.otherState, &state.LogTailerParams{
+		StartTime: startT,
+		EndTime:   endT,
+		Oplog:     s.oplogColl,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer tailer.Stop()
+	s.assertTailer(c, tailer, 5, want)
+	s.assertTailerStops(c, tailer)
+}
+
+func (s *LogTailerSuite) TestEndTimeReachedWhileTailing(c *gc.C) {
+	endT := coretesting.NonZeroTime().Add(time.Hour)
+	clock := jujutesting.NewClock(endT.Add(-time.Second))
+	tailer, err := state.NewLogTailer(s.otherState, &state.LogTailerParams{
+		EndTime: endT,
+		Oplog:   s.oplogColl,
+		Clock:   clock,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer tailer.Stop()
+
+	want := logTemplate{Message: "want"}
+	s.writeLogsT(c, endT.Add(-time.Second), endT.Add(-time.Second), 1, want)
+	s.assertTailer(c, tailer, 1, want)
+
+	// Records timestamped before the end time are still reported
+	// if they arrive shortly after it.
+	err = clock.WaitAdvance(2*time.Second, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	late := logTemplate{Message: "late"}
+	s.writeLogsT(c, endT.Add(-time.Millisecond), endT.Add(-time.Millisecond), 1, late)
+	s.writeLogsT(c, endT, endT, 1, logTemplate{Message: "too late"})
+	s.assertTailer(c, tailer, 1, late)
+
+	err = clock.WaitAdvance(time.Minute, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertTailerStops(c, tailer)
+}
+
+func (s *LogTailerSuite) assertTailerStops(c *gc.C, tailer state.LogTailer) {
+	select {
+	case _, ok := <-tailer.Logs():
+		if ok {
+			c.Fatal("shouldn't be any further logs")
+		}
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for logs channel to close")
+	}
+	c.Assert(tailer.Err(), jc.ErrorIsNil)
+}
+
 func (s *LogTailerSuite) TestOplogTransition(c *gc.C) {
 	// Ensure that logs aren't repeated as the log tailer moves from
 	// reading from the logs collection to tailing the oplog.
@@ -780,6 +954,28 @@ func (s *LogTailerSuite) TestIncludeExcludeModule(c *gc.C) {
 	s.checkLogTailerFiltering(c, s.otherState, params, writeLogs, assert)
 }
 
+func (s *LogTailerSuite) TestIncludeExcludeMessage(c *gc.C) {
+	started := logTemplate{Message: "worker started"}
+	stopped := logTemplate{Message: "worker stopped"}
+	failed := logTemplate{Message: "worker failed: boom"}
+	multiline := logTemplate{Message: "hook failed:\nexit status 1"}
+	writeLogs := func() {
+		s.writeLogs(c, 1, started)
+		s.writeLogs(c, 1, stopped)
+		s.writeLogs(c, 1, failed)
+		s.writeLogs(c, 1, multiline)
+	}
+	params := &state.LogTailerParams{
+		IncludeMessage: []string{"^worker", "exit status [0-9]+"},
+		ExcludeMessage: []string{"stopped$", "boom"},
+	}
+	assert := func(tailer state.LogTailer) {
+		s.assertTailer(c, tailer, 1, started)
+		s.assertTailer(c, tailer, 1, multiline)
+	}
+	s.checkLogTailerFiltering(c, s.otherState, params, writeLogs, assert)
+}
+
 func (s *LogTailerSuite) checkLogTailerFiltering(
 	c *gc.C,
 	st *state.State,
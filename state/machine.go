@@ -1758,6 +1758,11 @@ func (m *Machine) VolumeAttachments() ([]VolumeAttachment, error) {
 
 // AddAction is part of the ActionReceiver interface.
 func (m *Machine) AddAction(name string, payload map[string]interface{}) (Action, error) {
+	return m.AddActionWithOptions(name, payload, ActionOptions{})
+}
+
+// AddActionWithOptions is part of the ActionReceiver interface.
+func (m *Machine) AddActionWithOptions(name string, payload map[string]interface{}, options ActionOptions) (Action, error) {
 	spec, ok := actions.PredefinedActionsSpec[name]
 	if !ok {
 		return nil, errors.Errorf("cannot add action %q to a machine; only predefined actions allowed", name)
@@ -1772,7 +1777,7 @@ func (m *Machine) AddAction(name string, payload map[string]interface{}) (Action
 	if err != nil {
 		return nil, err
 	}
-	return m.st.EnqueueAction(m.Tag(), name, payloadWithDefaults)
+	return m.st.EnqueueActionWithOptions(m.Tag(), name, payloadWithDefaults, options)
 }
 
 // CancelAction is part of the ActionReceiver interface.
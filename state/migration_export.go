@@ -980,6 +980,11 @@ func (e *exporter) cloudimagemetadata() error {
 }
 
 func (e *exporter) actions() error {
+	// Operations are not migrated, so the actions they hold back
+	// could not be released in turn once imported.
+	if err := e.st.checkNoHeldActions(); err != nil {
+		return errors.Trace(err)
+	}
 	actions, err := e.st.AllActions()
 	if err != nil {
 		return errors.Trace(err)
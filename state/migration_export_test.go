@@ -760,6 +760,36 @@ func (s *MigrationExportSuite) TestActions(c *gc.C) {
 	c.Check(action.Message(), gc.Equals, "")
 }
 
+func (s *MigrationExportSuite) TestActionsHeldByOperation(c *gc.C) {
+	application := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "dummy"}),
+	})
+	units := []*state.Unit{
+		s.Factory.MakeUnit(c, &factory.UnitParams{Application: application}),
+		s.Factory.MakeUnit(c, &factory.UnitParams{Application: application}),
+	}
+	op, err := s.State.EnqueueOperation(state.OperationArgs{
+		Application: application.Name(),
+		Units:       units,
+		ActionName:  "snapshot",
+		BatchSize:   1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = s.State.Export()
+	c.Assert(err, gc.ErrorMatches, `operation \d+ still holds back 1 of its actions; wait for it to finish`)
+
+	actions, err := op.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	for _, action := range actions {
+		_, err := action.Finish(state.ActionResults{Status: state.ActionCompleted})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+	model, err := s.State.Export()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(model.Actions(), gc.HasLen, 2)
+}
+
 type goodToken struct{}
 
 // Check implements leadership.Token
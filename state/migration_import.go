@@ -1300,17 +1300,18 @@ func (i *importer) actions() error {
 func (i *importer) addAction(action description.Action) error {
 	modelUUID := i.st.ModelUUID()
 	newDoc := &actionDoc{
-		DocId:      i.st.docID(action.Id()),
-		ModelUUID:  modelUUID,
-		Receiver:   action.Receiver(),
-		Name:       action.Name(),
-		Parameters: action.Parameters(),
-		Enqueued:   action.Enqueued(),
-		Results:    action.Results(),
-		Message:    action.Message(),
-		Started:    action.Started(),
-		Completed:  action.Completed(),
-		Status:     ActionStatus(action.Status()),
+		DocId:       i.st.docID(action.Id()),
+		ModelUUID:   modelUUID,
+		Receiver:    action.Receiver(),
+		Application: actionApplication(action.Receiver()),
+		Name:        action.Name(),
+		Parameters:  action.Parameters(),
+		Enqueued:    action.Enqueued(),
+		Results:     action.Results(),
+		Message:     action.Message(),
+		Started:     action.Started(),
+		Completed:   action.Completed(),
+		Status:      ActionStatus(action.Status()),
 	}
 	prefix := ensureActionMarker(action.Receiver())
 	notificationDoc := &actionNotificationDoc{
@@ -158,6 +158,12 @@ func (s *MigrationSuite) TestKnownCollections(c *gc.C) {
 		// Recreated whilst migrating actions.
 		actionNotificationsC,
 
+		// Operations group the actions run on an application's
+		// units. The actions themselves are migrated; operations are
+		// not, so a model is not exported while an operation still
+		// holds back any of its actions.
+		operationsC,
+
 		// Global settings store controller specific configuration settings
 		// and are not to be migrated.
 		globalSettingsC,
@@ -656,6 +662,18 @@ func (s *MigrationSuite) TestSSHHostKeyDocFields(c *gc.C) {
 func (s *MigrationSuite) TestActionDocFields(c *gc.C) {
 	ignored := set.NewStrings(
 		"ModelUUID",
+		// The model description has no timeout for actions; a
+		// migrated action may run indefinitely.
+		"Timeout",
+		// Nor does it have progress messages, or whether the output
+		// of an action is logged as it runs.
+		"StreamOutput",
+		"Logs",
+		"MessageCount",
+		// Nor does it have operations; see operationsC.
+		"Operation",
+		// The application is recreated from the receiver.
+		"Application",
 	)
 	migrated := set.NewStrings(
 		"DocId",
@@ -0,0 +1,105 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/status"
+)
+
+// ModelStatuses holds the statuses of a model's machines and units,
+// and the presence of their agents, as read at one time. It saves
+// reading them entity by entity when the statuses of the whole model
+// are wanted.
+type ModelStatuses struct {
+	statuses    map[string]status.StatusInfo
+	aliveAgents map[string]bool
+}
+
+// LoadModelStatuses reads the statuses of the model's entities, and
+// the presence of its agents.
+func (st *State) LoadModelStatuses() (*ModelStatuses, error) {
+	statuses, closer := st.getCollection(statusesC)
+	defer closer()
+
+	var docs []struct {
+		DocID     string `bson:"_id"`
+		statusDoc `bson:",inline"`
+	}
+	if err := statuses.Find(nil).All(&docs); err != nil {
+		return nil, errors.Annotate(err, "cannot read statuses")
+	}
+	result := &ModelStatuses{
+		statuses:    make(map[string]status.StatusInfo, len(docs)),
+		aliveAgents: make(map[string]bool),
+	}
+	for _, doc := range docs {
+		result.statuses[st.localID(doc.DocID)] = doc.statusDoc.asStatusInfo()
+	}
+
+	keys, err := st.workers.PresenceWatcher().AliveKeys()
+	if err != nil {
+		return nil, errors.Annotate(err, "cannot read agent presence")
+	}
+	for _, key := range keys {
+		result.aliveAgents[key] = true
+	}
+	return result, nil
+}
+
+func (s *ModelStatuses) get(globalKey, badge string) (status.StatusInfo, error) {
+	info, ok := s.statuses[globalKey]
+	if !ok {
+		return status.StatusInfo{}, errors.Annotate(errors.NotFoundf(badge), "cannot get status")
+	}
+	return info, nil
+}
+
+// MachineAgent returns the status of the agent of the machine with
+// the given id, as Machine.Status does.
+func (s *ModelStatuses) MachineAgent(id string) (status.StatusInfo, error) {
+	return s.get(machineGlobalKey(id), "machine")
+}
+
+// MachineInstance returns the status of the instance of the machine
+// with the given id, as Machine.InstanceStatus does.
+func (s *ModelStatuses) MachineInstance(id string) (status.StatusInfo, error) {
+	return s.get(machineGlobalInstanceKey(id), "instance")
+}
+
+// MachineAgentAlive returns whether the agent of the machine with the
+// given id is alive, as Machine.AgentPresence does.
+func (s *ModelStatuses) MachineAgentAlive(id string) bool {
+	return s.aliveAgents[machineGlobalKey(id)]
+}
+
+// UnitAgent returns the status of the agent of the named unit, as
+// UnitAgent.Status does.
+func (s *ModelStatuses) UnitAgent(name string) (status.StatusInfo, error) {
+	info, err := s.get(unitAgentGlobalKey(name), "agent")
+	if err != nil {
+		return status.StatusInfo{}, errors.Trace(err)
+	}
+	return unitAgentStatus(info), nil
+}
+
+// UnitWorkload returns the status of the workload of the named unit,
+// as Unit.Status does.
+func (s *ModelStatuses) UnitWorkload(name string) (status.StatusInfo, error) {
+	info, err := s.get(unitAgentGlobalKey(name), "unit")
+	if err != nil {
+		return status.StatusInfo{}, errors.Trace(err)
+	}
+	if info.Status == status.Error {
+		return info, nil
+	}
+	return s.get(unitGlobalKey(name), "unit")
+}
+
+// UnitAgentAlive returns whether the agent of the named unit is alive,
+// as Unit.AgentPresence does.
+func (s *ModelStatuses) UnitAgentAlive(name string) bool {
+	return s.aliveAgents[unitAgentGlobalKey(name)]
+}
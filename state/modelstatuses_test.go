@@ -0,0 +1,113 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	worker "gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+	"github.com/juju/juju/testing"
+)
+
+type ModelStatusesSuite struct {
+	ConnSuite
+	machine *state.Machine
+	unit    *state.Unit
+}
+
+var _ = gc.Suite(&ModelStatusesSuite{})
+
+func (s *ModelStatusesSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+	s.machine = s.Factory.MakeMachine(c, nil)
+	s.unit = s.Factory.MakeUnit(c, nil)
+}
+
+func (s *ModelStatusesSuite) TestStatuses(c *gc.C) {
+	now := testing.ZeroTime()
+	err := s.machine.SetStatus(status.StatusInfo{Status: status.Started, Since: &now})
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.unit.SetStatus(status.StatusInfo{Status: status.Active, Message: "ready", Since: &now})
+	c.Assert(err, jc.ErrorIsNil)
+
+	statuses, err := s.State.LoadModelStatuses()
+	c.Assert(err, jc.ErrorIsNil)
+
+	machineStatus, err := s.machine.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	info, err := statuses.MachineAgent(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, machineStatus)
+
+	instanceStatus, err := s.machine.InstanceStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	info, err = statuses.MachineInstance(s.machine.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, instanceStatus)
+
+	agentStatus, err := s.unit.AgentStatus()
+	c.Assert(err, jc.ErrorIsNil)
+	info, err = statuses.UnitAgent(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, agentStatus)
+
+	workloadStatus, err := s.unit.Status()
+	c.Assert(err, jc.ErrorIsNil)
+	info, err = statuses.UnitWorkload(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(info, jc.DeepEquals, workloadStatus)
+	c.Assert(info.Message, gc.Equals, "ready")
+}
+
+func (s *ModelStatusesSuite) TestUnitAgentError(c *gc.C) {
+	now := testing.ZeroTime()
+	err := s.unit.Agent().SetStatus(status.StatusInfo{
+		Status:  status.Error,
+		Message: "hook failed",
+		Since:   &now,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+
+	statuses, err := s.State.LoadModelStatuses()
+	c.Assert(err, jc.ErrorIsNil)
+
+	agent, err := statuses.UnitAgent(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(agent.Status, gc.Equals, status.Idle)
+	workload, err := statuses.UnitWorkload(s.unit.Name())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(workload.Status, gc.Equals, status.Error)
+	c.Assert(workload.Message, gc.Equals, "hook failed")
+}
+
+func (s *ModelStatusesSuite) TestNotFound(c *gc.C) {
+	statuses, err := s.State.LoadModelStatuses()
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = statuses.MachineAgent("42")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	c.Assert(err, gc.ErrorMatches, "cannot get status: machine not found")
+	_, err = statuses.UnitAgent("foo/42")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+	_, err = statuses.UnitWorkload("foo/42")
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *ModelStatusesSuite) TestAgentPresence(c *gc.C) {
+	pinger, err := s.unit.SetAgentPresence()
+	c.Assert(err, jc.ErrorIsNil)
+	defer func() {
+		c.Assert(worker.Stop(pinger), jc.ErrorIsNil)
+	}()
+	s.State.StartSync()
+
+	statuses, err := s.State.LoadModelStatuses()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(statuses.UnitAgentAlive(s.unit.Name()), jc.IsTrue)
+	c.Assert(statuses.MachineAgentAlive(s.machine.Id()), jc.IsFalse)
+}
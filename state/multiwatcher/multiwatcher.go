@@ -86,29 +86,84 @@ func (d *Delta) UnmarshalJSON(data []byte) error {
 	} else if operation != "change" {
 		return fmt.Errorf("Unexpected operation %q", operation)
 	}
-	switch entityKind {
+	entity, err := NewEntityInfo(entityKind)
+	if err != nil {
+		return err
+	}
+	d.Entity = entity
+	return json.Unmarshal(elements[2], &d.Entity)
+}
+
+// MarshalArray returns the [kind, "change" or "remove", entity] elements
+// of the array that MarshalJSON produces, so that codecs able to encode
+// the entity directly need not go through JSON.
+func (d Delta) MarshalArray() ([]interface{}, error) {
+	if d.Entity == nil {
+		return nil, errors.New("cannot marshal delta with no entity")
+	}
+	operation := "change"
+	if d.Removed {
+		operation = "remove"
+	}
+	return []interface{}{d.Entity.EntityId().Kind, operation, d.Entity}, nil
+}
+
+// UnmarshalArray sets *d from the n elements of an array of the form
+// produced by MarshalArray, decoding each element in turn with next.
+func (d *Delta) UnmarshalArray(n int, next func(v interface{}) error) error {
+	if n != 3 {
+		return errors.Errorf("expected 3 elements in delta but got %d", n)
+	}
+	var kind, operation string
+	if err := next(&kind); err != nil {
+		return err
+	}
+	if err := next(&operation); err != nil {
+		return err
+	}
+	switch operation {
+	case "change":
+		d.Removed = false
+	case "remove":
+		d.Removed = true
+	default:
+		return errors.Errorf("unexpected delta operation %q", operation)
+	}
+	entity, err := NewEntityInfo(kind)
+	if err != nil {
+		return errors.Trace(err)
+	}
+	if err := next(entity); err != nil {
+		return err
+	}
+	d.Entity = entity
+	return nil
+}
+
+// NewEntityInfo returns a new zero EntityInfo of the given kind, as
+// found in the first element of a marshaled Delta.
+func NewEntityInfo(kind string) (EntityInfo, error) {
+	switch kind {
 	case "model":
-		d.Entity = new(ModelInfo)
+		return new(ModelInfo), nil
 	case "machine":
-		d.Entity = new(MachineInfo)
+		return new(MachineInfo), nil
 	case "application":
-		d.Entity = new(ApplicationInfo)
+		return new(ApplicationInfo), nil
 	case "remoteApplication":
-		d.Entity = new(RemoteApplicationInfo)
+		return new(RemoteApplicationInfo), nil
 	case "unit":
-		d.Entity = new(UnitInfo)
+		return new(UnitInfo), nil
 	case "relation":
-		d.Entity = new(RelationInfo)
+		return new(RelationInfo), nil
 	case "annotation":
-		d.Entity = new(AnnotationInfo)
+		return new(AnnotationInfo), nil
 	case "block":
-		d.Entity = new(BlockInfo)
+		return new(BlockInfo), nil
 	case "action":
-		d.Entity = new(ActionInfo)
-	default:
-		return errors.Errorf("Unexpected entity name %q", entityKind)
+		return new(ActionInfo), nil
 	}
-	return json.Unmarshal(elements[2], &d.Entity)
+	return nil, errors.Errorf("Unexpected entity name %q", kind)
 }
 
 // Address describes a network address.
@@ -0,0 +1,330 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/mgo.v2"
+	"gopkg.in/mgo.v2/bson"
+	"gopkg.in/mgo.v2/txn"
+)
+
+// operationDoc records an action enqueued on several units of an
+// application at once.
+type operationDoc struct {
+	// DocId is the key for this document; its local part is a number
+	// from the "operation" sequence.
+	DocId string `bson:"_id"`
+
+	// ModelUUID is the model identifier.
+	ModelUUID string `bson:"model-uuid"`
+
+	// Application is the name of the application whose units run the
+	// operation's actions.
+	Application string `bson:"application"`
+
+	// ActionName is the name of the action run on each unit.
+	ActionName string `bson:"action-name"`
+
+	// Enqueued is the time the operation was added.
+	Enqueued time.Time `bson:"enqueued"`
+
+	// BatchSize holds how many of the operation's actions may be
+	// queued on their units at once. If it is zero, they are all
+	// queued straight away.
+	BatchSize int `bson:"batch-size,omitempty"`
+
+	// ActionIds holds the ids of the operation's actions, one for
+	// each unit.
+	ActionIds []string `bson:"action-ids"`
+
+	// Held holds the ids of the actions that are not yet queued on
+	// their units, in the order they will be released. An action is
+	// released each time another of the operation's actions finishes.
+	Held []string `bson:"held,omitempty"`
+}
+
+// Operation is an action enqueued on several units of an application
+// at once.
+type Operation struct {
+	st  *State
+	doc operationDoc
+}
+
+// Id returns the operation's id.
+func (op *Operation) Id() string {
+	return op.st.localID(op.doc.DocId)
+}
+
+// Application returns the name of the application whose units run the
+// operation's actions.
+func (op *Operation) Application() string {
+	return op.doc.Application
+}
+
+// ActionName returns the name of the action run on each unit.
+func (op *Operation) ActionName() string {
+	return op.doc.ActionName
+}
+
+// Enqueued returns the time the operation was added.
+func (op *Operation) Enqueued() time.Time {
+	return op.doc.Enqueued
+}
+
+// BatchSize returns how many of the operation's actions may be queued
+// on their units at once, or zero if there is no limit.
+func (op *Operation) BatchSize() int {
+	return op.doc.BatchSize
+}
+
+// ActionIds returns the ids of the operation's actions.
+func (op *Operation) ActionIds() []string {
+	return op.doc.ActionIds
+}
+
+// Actions returns the operation's actions. Actions that have been
+// pruned are left out.
+func (op *Operation) Actions() ([]Action, error) {
+	actions := make([]Action, 0, len(op.doc.ActionIds))
+	for _, id := range op.doc.ActionIds {
+		action, err := op.st.Action(id)
+		if errors.IsNotFound(err) {
+			continue
+		} else if err != nil {
+			return nil, errors.Trace(err)
+		}
+		actions = append(actions, action)
+	}
+	return actions, nil
+}
+
+// held reports whether the action with the given id is held back.
+func (op *Operation) held(actionId string) bool {
+	for _, id := range op.doc.Held {
+		if id == actionId {
+			return true
+		}
+	}
+	return false
+}
+
+// unholdOp returns the operation that stops holding back the actions
+// with the given ids, asserting that they are all still held.
+func (op *Operation) unholdOp(actionIds ...string) txn.Op {
+	return txn.Op{
+		C:      operationsC,
+		Id:     op.doc.DocId,
+		Assert: bson.D{{"held", bson.D{{"$all", actionIds}}}},
+		Update: bson.D{{"$pullAll", bson.D{{"held", actionIds}}}},
+	}
+}
+
+// releaseNextOps returns the operations that queue the next held back
+// action on its unit, if there is one, asserting that the unit is not
+// dead. Held actions whose units are dead or removed would never run
+// and so never release the actions after them; they are cancelled
+// instead, and the next action is released in their place.
+func (op *Operation) releaseNextOps() ([]txn.Op, error) {
+	var ops []txn.Op
+	var unheld []string
+	for _, id := range op.doc.Held {
+		next, err := op.st.Action(id)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		unitDocId := op.st.docID(next.Receiver())
+		notDead, err := isNotDead(op.st, unitsC, unitDocId)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		unheld = append(unheld, id)
+		if !notDead {
+			ops = append(ops, next.(*action).recordOutcomeOp(
+				bson.D{{"status", ActionPending}}, ActionCancelled, nil, "unit removed",
+			))
+			continue
+		}
+		ndoc := next.(*action).notificationDoc()
+		ops = append(ops, txn.Op{
+			C:      unitsC,
+			Id:     unitDocId,
+			Assert: notDeadDoc,
+		}, txn.Op{
+			C:      actionNotificationsC,
+			Id:     ndoc.DocId,
+			Assert: txn.DocMissing,
+			Insert: ndoc,
+		})
+		break
+	}
+	if len(unheld) == 0 {
+		return nil, nil
+	}
+	return append(ops, op.unholdOp(unheld...)), nil
+}
+
+// checkNoHeldActions returns an error naming one of the model's operations
+// that still holds back actions, if there is one.
+func (st *State) checkNoHeldActions() error {
+	operations, closer := st.getCollection(operationsC)
+	defer closer()
+
+	var doc operationDoc
+	err := operations.Find(bson.D{{"held.0", bson.D{{"$exists", true}}}}).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil
+	} else if err != nil {
+		return errors.Annotate(err, "cannot read operations")
+	}
+	return errors.Errorf(
+		"operation %s still holds back %d of its actions; wait for it to finish",
+		st.localID(doc.DocId), len(doc.Held),
+	)
+}
+
+// OperationArgs holds the arguments to EnqueueOperation.
+type OperationArgs struct {
+	// Application is the name of the application the units belong to.
+	Application string
+
+	// Units holds the units to run the action on.
+	Units []*Unit
+
+	// ActionName is the name of the action to run.
+	ActionName string
+
+	// Parameters holds the action's parameters, if any.
+	Parameters map[string]interface{}
+
+	// Options holds the options of each action.
+	Options ActionOptions
+
+	// BatchSize, if non-zero, limits how many of the actions may be
+	// queued on their units at once; the rest are held back, and one
+	// is released each time an action finishes.
+	BatchSize int
+}
+
+// Validate returns an error if the arguments are not valid.
+func (args OperationArgs) Validate() error {
+	if args.Application == "" {
+		return errors.NotValidf("empty application name")
+	}
+	if len(args.Units) == 0 {
+		return errors.NotValidf("operation with no units")
+	}
+	if args.ActionName == "" {
+		return errors.NotValidf("empty action name")
+	}
+	if args.BatchSize < 0 {
+		return errors.NotValidf("negative batch size %d", args.BatchSize)
+	}
+	for _, unit := range args.Units {
+		if unit.ApplicationName() != args.Application {
+			return errors.NotValidf("unit %q of application %q", unit.Name(), args.Application)
+		}
+	}
+	return args.Options.Validate()
+}
+
+// EnqueueOperation enqueues the named action on each of the given units
+// of an application, and records them as an operation. If a batch size
+// is given, only that many of the actions are queued on their units
+// straight away.
+func (st *State) EnqueueOperation(args OperationArgs) (*Operation, error) {
+	if err := args.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	n, err := st.sequence("operation")
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	opDoc := operationDoc{
+		DocId:       st.docID(strconv.Itoa(n)),
+		ModelUUID:   st.ModelUUID(),
+		Application: args.Application,
+		ActionName:  args.ActionName,
+		Enqueued:    st.NowToTheSecond(),
+		BatchSize:   args.BatchSize,
+	}
+
+	var ops []txn.Op
+	for i, unit := range args.Units {
+		payload, options, err := unit.prepareAction(args.ActionName, args.Parameters, args.Options)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		doc, ndoc, err := newActionDoc(st, unit.Tag(), args.ActionName, payload, options)
+		if err != nil {
+			return nil, errors.Trace(err)
+		}
+		doc.Operation = st.localID(opDoc.DocId)
+		actionId := st.localID(doc.DocId)
+		opDoc.ActionIds = append(opDoc.ActionIds, actionId)
+		ops = append(ops, txn.Op{
+			C:      unitsC,
+			Id:     unit.doc.DocID,
+			Assert: notDeadDoc,
+		}, txn.Op{
+			C:      actionsC,
+			Id:     doc.DocId,
+			Assert: txn.DocMissing,
+			Insert: doc,
+		})
+		if args.BatchSize > 0 && i >= args.BatchSize {
+			opDoc.Held = append(opDoc.Held, actionId)
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:      actionNotificationsC,
+			Id:     ndoc.DocId,
+			Assert: txn.DocMissing,
+			Insert: ndoc,
+		})
+	}
+	ops = append(ops, txn.Op{
+		C:      operationsC,
+		Id:     opDoc.DocId,
+		Assert: txn.DocMissing,
+		Insert: opDoc,
+	})
+
+	buildTxn := func(attempt int) ([]txn.Op, error) {
+		for _, unit := range args.Units {
+			if notDead, err := isNotDead(st, unitsC, unit.doc.DocID); err != nil {
+				return nil, err
+			} else if !notDead {
+				return nil, errors.Annotatef(ErrDead, "unit %q", unit.Name())
+			}
+		}
+		if attempt != 0 {
+			return nil, errors.Errorf("unexpected attempt number '%d'", attempt)
+		}
+		return ops, nil
+	}
+	if err := st.run(buildTxn); err != nil {
+		return nil, errors.Annotate(err, "cannot enqueue operation")
+	}
+	return &Operation{st: st, doc: opDoc}, nil
+}
+
+// Operation returns the operation with the given id.
+func (st *State) Operation(id string) (*Operation, error) {
+	operations, closer := st.getCollection(operationsC)
+	defer closer()
+
+	var doc operationDoc
+	err := operations.FindId(id).One(&doc)
+	if err == mgo.ErrNotFound {
+		return nil, errors.NotFoundf("operation %q", id)
+	}
+	if err != nil {
+		return nil, errors.Annotatef(err, "cannot get operation %q", id)
+	}
+	return &Operation{st: st, doc: doc}, nil
+}
@@ -0,0 +1,204 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package state_test
+
+import (
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/state"
+	statetesting "github.com/juju/juju/state/testing"
+)
+
+type OperationSuite struct {
+	ConnSuite
+	units []*state.Unit
+}
+
+var _ = gc.Suite(&OperationSuite{})
+
+func (s *OperationSuite) SetUpTest(c *gc.C) {
+	s.ConnSuite.SetUpTest(c)
+
+	charm := s.AddTestingCharm(c, "dummy")
+	application := s.AddTestingService(c, "dummy", charm)
+	curl, _ := application.CharmURL()
+	s.units = nil
+	for i := 0; i < 3; i++ {
+		unit, err := application.AddUnit()
+		c.Assert(err, jc.ErrorIsNil)
+		err = unit.SetCharmURL(curl)
+		c.Assert(err, jc.ErrorIsNil)
+		s.units = append(s.units, unit)
+	}
+}
+
+func (s *OperationSuite) assertQueued(c *gc.C, unit *state.Unit, ids ...string) {
+	w := unit.WatchActionNotifications()
+	defer statetesting.AssertStop(c, w)
+	wc := statetesting.NewStringsWatcherC(c, s.State, w)
+	wc.AssertChange(ids...)
+	wc.AssertNoChange()
+}
+
+func (s *OperationSuite) TestEnqueueOperation(c *gc.C) {
+	op, err := s.State.EnqueueOperation(state.OperationArgs{
+		Application: "dummy",
+		Units:       s.units,
+		ActionName:  "snapshot",
+		Parameters:  map[string]interface{}{"outfile": "out.tar.bz2"},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.Application(), gc.Equals, "dummy")
+	c.Assert(op.ActionName(), gc.Equals, "snapshot")
+	c.Assert(op.BatchSize(), gc.Equals, 0)
+	c.Assert(op.ActionIds(), gc.HasLen, 3)
+
+	actions, err := op.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	for i, action := range actions {
+		c.Check(action.Receiver(), gc.Equals, s.units[i].Name())
+		c.Check(action.Operation(), gc.Equals, op.Id())
+		c.Check(action.Status(), gc.Equals, state.ActionPending)
+		c.Check(action.Parameters(), jc.DeepEquals, map[string]interface{}{"outfile": "out.tar.bz2"})
+		s.assertQueued(c, s.units[i], action.Id())
+	}
+
+	op2, err := s.State.Operation(op.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op2.Application(), gc.Equals, "dummy")
+	c.Assert(op2.ActionIds(), jc.DeepEquals, op.ActionIds())
+	c.Assert(op2.Enqueued(), gc.Equals, op.Enqueued())
+}
+
+func (s *OperationSuite) TestEnqueueOperationBatch(c *gc.C) {
+	op, err := s.State.EnqueueOperation(state.OperationArgs{
+		Application: "dummy",
+		Units:       s.units,
+		ActionName:  "snapshot",
+		BatchSize:   1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(op.BatchSize(), gc.Equals, 1)
+	actions, err := op.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// Only the first action is queued straight away.
+	s.assertQueued(c, s.units[0], actions[0].Id())
+	s.assertQueued(c, s.units[1])
+	s.assertQueued(c, s.units[2])
+
+	// Cancelling a held back action does not release another.
+	cancelled, err := actions[2].Cancel("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(cancelled.Status(), gc.Equals, state.ActionCancelled)
+	s.assertQueued(c, s.units[1])
+
+	// Finishing an action releases the next one.
+	_, err = actions[0].Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertQueued(c, s.units[0])
+	s.assertQueued(c, s.units[1], actions[1].Id())
+
+	_, err = actions[1].Finish(state.ActionResults{Status: state.ActionFailed})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertQueued(c, s.units[1])
+	s.assertQueued(c, s.units[2])
+}
+
+func (s *OperationSuite) TestEnqueueOperationInvalid(c *gc.C) {
+	otherCharm := s.AddTestingCharm(c, "mysql")
+	other := s.AddTestingService(c, "mysql", otherCharm)
+	otherUnit, err := other.AddUnit()
+	c.Assert(err, jc.ErrorIsNil)
+
+	for i, test := range []struct {
+		args   state.OperationArgs
+		expect string
+	}{{
+		args:   state.OperationArgs{Application: "dummy", ActionName: "snapshot"},
+		expect: "operation with no units not valid",
+	}, {
+		args:   state.OperationArgs{Application: "dummy", Units: s.units},
+		expect: "empty action name not valid",
+	}, {
+		args:   state.OperationArgs{Application: "dummy", Units: s.units, ActionName: "snapshot", BatchSize: -1},
+		expect: "negative batch size -1 not valid",
+	}, {
+		args:   state.OperationArgs{Application: "dummy", Units: []*state.Unit{otherUnit}, ActionName: "snapshot"},
+		expect: `unit "mysql/0" of application "dummy" not valid`,
+	}, {
+		args:   state.OperationArgs{Application: "dummy", Units: s.units, ActionName: "missing"},
+		expect: `action "missing" not defined on unit "dummy/0"`,
+	}} {
+		c.Logf("test %d", i)
+		_, err := s.State.EnqueueOperation(test.args)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (s *OperationSuite) TestEnqueueOperationDeadUnit(c *gc.C) {
+	err := s.units[1].EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = s.State.EnqueueOperation(state.OperationArgs{
+		Application: "dummy",
+		Units:       s.units,
+		ActionName:  "snapshot",
+	})
+	c.Assert(err, gc.ErrorMatches, `cannot enqueue operation: unit "dummy/1": not found or dead`)
+}
+
+func (s *OperationSuite) TestOperationNotFound(c *gc.C) {
+	_, err := s.State.Operation("42")
+	c.Assert(err, gc.ErrorMatches, `operation "42" not found`)
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *OperationSuite) enqueueBatch(c *gc.C) []state.Action {
+	op, err := s.State.EnqueueOperation(state.OperationArgs{
+		Application: "dummy",
+		Units:       s.units,
+		ActionName:  "snapshot",
+		BatchSize:   1,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	actions, err := op.Actions()
+	c.Assert(err, jc.ErrorIsNil)
+	return actions
+}
+
+func (s *OperationSuite) assertStatus(c *gc.C, action state.Action, status state.ActionStatus) {
+	action, err := s.State.Action(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Status(), gc.Equals, status)
+}
+
+func (s *OperationSuite) TestReleaseSkipsDeadUnits(c *gc.C) {
+	actions := s.enqueueBatch(c)
+	err := s.units[1].EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The action held back for the dead unit would never run, so it
+	// is cancelled and the next one released in its place.
+	_, err = actions[0].Finish(state.ActionResults{Status: state.ActionCompleted})
+	c.Assert(err, jc.ErrorIsNil)
+	s.assertStatus(c, actions[1], state.ActionCancelled)
+	s.assertQueued(c, s.units[1])
+	s.assertQueued(c, s.units[2], actions[2].Id())
+}
+
+func (s *OperationSuite) TestRemovingUnitReleasesNext(c *gc.C) {
+	actions := s.enqueueBatch(c)
+	err := s.units[0].EnsureDead()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.units[0].Remove()
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.State.Cleanup()
+	c.Assert(err, jc.ErrorIsNil)
+
+	s.assertStatus(c, actions[0], state.ActionCancelled)
+	s.assertQueued(c, s.units[1], actions[1].Id())
+	s.assertQueued(c, s.units[2])
+}
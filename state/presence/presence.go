@@ -204,6 +204,10 @@ type reqAlive struct {
 	result chan bool
 }
 
+type reqAliveKeys struct {
+	result chan []string
+}
+
 func (w *Watcher) sendReq(req interface{}) {
 	select {
 	case w.request <- req:
@@ -256,6 +260,20 @@ func (w *Watcher) Alive(key string) (bool, error) {
 	return alive, nil
 }
 
+// AliveKeys returns the keys currently considered alive by w, or an
+// error in case the watcher is dying. It saves asking about each key
+// in turn when the liveness of many is wanted.
+func (w *Watcher) AliveKeys() ([]string, error) {
+	result := make(chan []string, 1)
+	w.sendReq(reqAliveKeys{result})
+	select {
+	case keys := <-result:
+		return keys, nil
+	case <-w.tomb.Dying():
+		return nil, errors.Errorf("cannot check liveness: watcher is dying")
+	}
+}
+
 // period is the length of each time slot in seconds.
 // It's not a time.Duration because the code is more convenient like
 // this and also because sub-second timings don't work as the slot
@@ -354,6 +372,12 @@ func (w *Watcher) handle(req interface{}) {
 	case reqAlive:
 		_, alive := w.beingSeq[r.key]
 		r.result <- alive
+	case reqAliveKeys:
+		keys := make([]string, 0, len(w.beingSeq))
+		for key := range w.beingSeq {
+			keys = append(keys, key)
+		}
+		r.result <- keys
 	default:
 		panic(fmt.Errorf("unknown request: %T", req))
 	}
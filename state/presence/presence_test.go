@@ -130,6 +130,42 @@ func (s *PresenceSuite) TestAliveError(c *gc.C) {
 	w.Wait()
 }
 
+func (s *PresenceSuite) TestAliveKeys(c *gc.C) {
+	w := presence.NewWatcher(s.presence, s.modelTag)
+	pa := presence.NewPinger(s.presence, s.modelTag, "a")
+	pb := presence.NewPinger(s.presence, s.modelTag, "b")
+	defer assertStopped(c, w)
+	defer assertStopped(c, pa)
+	defer assertStopped(c, pb)
+
+	keys, err := w.AliveKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, gc.HasLen, 0)
+
+	c.Assert(pa.Start(), gc.IsNil)
+	c.Assert(pb.Start(), gc.IsNil)
+	w.Sync()
+	keys, err = w.AliveKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.SameContents, []string{"a", "b"})
+
+	c.Assert(pb.KillForTesting(), gc.IsNil)
+	w.Sync()
+	keys, err = w.AliveKeys()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(keys, jc.DeepEquals, []string{"a"})
+}
+
+func (s *PresenceSuite) TestAliveKeysError(c *gc.C) {
+	w := presence.NewWatcher(s.presence, s.modelTag)
+	c.Assert(w.Stop(), gc.IsNil)
+
+	keys, err := w.AliveKeys()
+	c.Assert(err, gc.ErrorMatches, ".*: watcher is dying")
+	c.Assert(keys, gc.IsNil)
+	w.Wait()
+}
+
 func (s *PresenceSuite) TestWorkflow(c *gc.C) {
 	w := presence.NewWatcher(s.presence, s.modelTag)
 	pa := presence.NewPinger(s.presence, s.modelTag, "a")
@@ -2163,6 +2163,65 @@ func (st *State) PutAuditEntryFn() func(audit.AuditEntry) error {
 	return stateaudit.PutAuditEntryFn(auditingC, insert)
 }
 
+// AuditEntryFilter holds the criteria used to select audit entries
+// with State.AuditEntries. Zero valued fields do not restrict the
+// result.
+type AuditEntryFilter struct {
+	// ModelUUID restricts the entries to those recorded against
+	// the given model.
+	ModelUUID string
+
+	// OriginName restricts the entries to those triggered by the
+	// given origin, e.g. "user-bob".
+	OriginName string
+
+	// Operation restricts the entries to those whose operation
+	// contains the given text.
+	Operation string
+
+	// After restricts the entries to those recorded at or after
+	// the given time.
+	After time.Time
+
+	// Before restricts the entries to those recorded before the
+	// given time.
+	Before time.Time
+
+	// Limit is the maximum number of entries to return. When
+	// more entries match, the most recent are returned unless
+	// Oldest is set.
+	Limit int
+
+	// Oldest indicates that when more entries match than Limit,
+	// the oldest entries should be returned.
+	Oldest bool
+}
+
+// AuditEntries returns the audit entries matching the filter, oldest
+// first.
+func (st *State) AuditEntries(filter AuditEntryFilter) ([]audit.AuditEntry, error) {
+	find := func(collectionName string, query bson.D, sort string, limit int, docs interface{}) error {
+		collection, closeCollection := st.getRawCollection(collectionName)
+		defer closeCollection()
+
+		q := collection.Find(query).Sort(sort)
+		if limit > 0 {
+			q = q.Limit(limit)
+		}
+		return errors.Trace(q.All(docs))
+	}
+	entries, err := stateaudit.FindAuditEntriesFn(auditingC, find)(stateaudit.Filter{
+		ModelUUID:  filter.ModelUUID,
+		OriginName: filter.OriginName,
+		Operation:  filter.Operation,
+		After:      filter.After,
+		Before:     filter.Before,
+		Limit:      filter.Limit,
+		Oldest:     filter.Oldest,
+	})
+	return entries, errors.Trace(err)
+}
+
 var tagPrefix = map[byte]string{
 	'm': names.MachineTagKind + "-",
 	'a': names.ApplicationTagKind + "-",
@@ -4,7 +4,9 @@
 package statemetrics_test
 
 import (
+	"github.com/juju/errors"
 	"github.com/juju/testing"
+	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/permission"
@@ -94,17 +96,117 @@ func (m mockModelState) AllMachines() ([]statemetrics.Machine, error) {
 	return out, nil
 }
 
+func (m mockModelState) AllApplications() ([]statemetrics.Application, error) {
+	m.MethodCall(m, "AllApplications")
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	out := make([]statemetrics.Application, len(m.applications))
+	for i, a := range m.applications {
+		out[i] = a
+	}
+	return out, nil
+}
+
+func (m mockModelState) AgentVersion() (version.Number, error) {
+	m.MethodCall(m, "AgentVersion")
+	if err := m.NextErr(); err != nil {
+		return version.Zero, err
+	}
+	return m.agentVersion, nil
+}
+
+func (m mockModelState) LoadModelStatuses() (statemetrics.ModelStatuses, error) {
+	m.MethodCall(m, "LoadModelStatuses")
+	if err := m.NextErr(); err != nil {
+		return nil, err
+	}
+	return mockModelStatuses{m.mockModel}, nil
+}
+
 func (m mockModelState) Close() error {
 	m.MethodCall(m, "Close")
 	return m.NextErr()
 }
 
+// mockModelStatuses returns the statuses recorded on a mockModel's
+// machines and units.
+type mockModelStatuses struct {
+	*mockModel
+}
+
+func (m mockModelStatuses) machine(id string) *mockMachine {
+	for _, machine := range m.machines {
+		if machine.id == id {
+			return machine
+		}
+	}
+	return nil
+}
+
+func (m mockModelStatuses) unit(name string) *mockUnit {
+	for _, a := range m.applications {
+		for _, u := range a.units {
+			if u.name == name {
+				return u
+			}
+		}
+	}
+	return nil
+}
+
+func (m mockModelStatuses) MachineAgent(id string) (status.StatusInfo, error) {
+	if machine := m.machine(id); machine != nil {
+		return machine.agentStatus, nil
+	}
+	return status.StatusInfo{}, errors.NotFoundf("machine")
+}
+
+func (m mockModelStatuses) MachineAgentAlive(id string) bool {
+	machine := m.machine(id)
+	return machine != nil && machine.agentAlive
+}
+
+func (m mockModelStatuses) MachineInstance(id string) (status.StatusInfo, error) {
+	if machine := m.machine(id); machine != nil {
+		return machine.instanceStatus, nil
+	}
+	return status.StatusInfo{}, errors.NotFoundf("instance")
+}
+
+func (m mockModelStatuses) UnitAgent(name string) (status.StatusInfo, error) {
+	if u := m.unit(name); u != nil {
+		return u.agentStatus, nil
+	}
+	return status.StatusInfo{}, errors.NotFoundf("agent")
+}
+
+func (m mockModelStatuses) UnitAgentAlive(name string) bool {
+	u := m.unit(name)
+	return u != nil && u.agentAlive
+}
+
+func (m mockModelStatuses) UnitWorkload(name string) (status.StatusInfo, error) {
+	if u := m.unit(name); u != nil {
+		return u.workloadStatus, nil
+	}
+	return status.StatusInfo{}, errors.NotFoundf("unit")
+}
+
 type mockModel struct {
 	testing.Stub
-	tag      names.ModelTag
-	life     state.Life
-	status   status.StatusInfo
-	machines []*mockMachine
+	tag          names.ModelTag
+	name         string
+	life         state.Life
+	status       status.StatusInfo
+	agentVersion version.Number
+	machines     []*mockMachine
+	applications []*mockApplication
+}
+
+func (m *mockModel) Name() string {
+	m.MethodCall(m, "Name")
+	return m.name
 }
 
 func (m *mockModel) Life() state.Life {
@@ -150,28 +252,70 @@ func (u *mockUser) IsDisabled() bool {
 
 type mockMachine struct {
 	testing.Stub
+	id             string
 	instanceStatus status.StatusInfo
 	agentStatus    status.StatusInfo
+	agentAlive     bool
 	life           state.Life
 }
 
+func (m *mockMachine) Id() string {
+	m.MethodCall(m, "Id")
+	return m.id
+}
+
 func (m *mockMachine) Life() state.Life {
 	m.MethodCall(m, "Life")
 	return m.life
 }
 
-func (m *mockMachine) InstanceStatus() (status.StatusInfo, error) {
-	m.MethodCall(m, "InstanceStatus")
-	if err := m.NextErr(); err != nil {
+type mockApplication struct {
+	testing.Stub
+	name   string
+	status status.StatusInfo
+	units  []*mockUnit
+}
+
+func (a *mockApplication) Name() string {
+	a.MethodCall(a, "Name")
+	return a.name
+}
+
+func (a *mockApplication) Status() (status.StatusInfo, error) {
+	a.MethodCall(a, "Status")
+	if err := a.NextErr(); err != nil {
 		return status.StatusInfo{}, err
 	}
-	return m.instanceStatus, nil
+	return a.status, nil
 }
 
-func (m *mockMachine) Status() (status.StatusInfo, error) {
-	m.MethodCall(m, "Status")
-	if err := m.NextErr(); err != nil {
-		return status.StatusInfo{}, err
+func (a *mockApplication) AllUnits() ([]statemetrics.Unit, error) {
+	a.MethodCall(a, "AllUnits")
+	if err := a.NextErr(); err != nil {
+		return nil, err
 	}
-	return m.agentStatus, nil
+	out := make([]statemetrics.Unit, len(a.units))
+	for i, u := range a.units {
+		out[i] = u
+	}
+	return out, nil
+}
+
+type mockUnit struct {
+	testing.Stub
+	name           string
+	life           state.Life
+	agentStatus    status.StatusInfo
+	workloadStatus status.StatusInfo
+	agentAlive     bool
+}
+
+func (u *mockUnit) Name() string {
+	u.MethodCall(u, "Name")
+	return u.name
+}
+
+func (u *mockUnit) Life() state.Life {
+	u.MethodCall(u, "Life")
+	return u.life
 }
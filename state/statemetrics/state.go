@@ -4,6 +4,7 @@ package statemetrics
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/version"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/permission"
@@ -13,11 +14,14 @@ import (
 
 // State represents the global state managed by the Juju controller.
 type State interface {
+	AgentVersion() (version.Number, error)
+	AllApplications() ([]Application, error)
 	AllMachines() ([]Machine, error)
 	AllModels() ([]Model, error)
 	AllUsers() ([]User, error)
 	ControllerTag() names.ControllerTag
 	ForModel(names.ModelTag) (StateCloser, error)
+	LoadModelStatuses() (ModelStatuses, error)
 	UserAccess(names.UserTag, names.Tag) (permission.UserAccess, error)
 }
 
@@ -27,20 +31,44 @@ type StateCloser interface {
 	Close() error
 }
 
+// Application represents an application in a Juju model.
+type Application interface {
+	AllUnits() ([]Unit, error)
+	Name() string
+	Status() (status.StatusInfo, error)
+}
+
 // Machine represents a machine in a Juju model.
 type Machine interface {
-	InstanceStatus() (status.StatusInfo, error)
+	Id() string
 	Life() state.Life
-	Status() (status.StatusInfo, error)
 }
 
 // Model represents a Juju model.
 type Model interface {
 	Life() state.Life
 	ModelTag() names.ModelTag
+	Name() string
 	Status() (status.StatusInfo, error)
 }
 
+// ModelStatuses represents the statuses of a Juju model's machines
+// and units, and the presence of their agents.
+type ModelStatuses interface {
+	MachineAgent(id string) (status.StatusInfo, error)
+	MachineAgentAlive(id string) bool
+	MachineInstance(id string) (status.StatusInfo, error)
+	UnitAgent(name string) (status.StatusInfo, error)
+	UnitAgentAlive(name string) bool
+	UnitWorkload(name string) (status.StatusInfo, error)
+}
+
+// Unit represents a unit of an application in a Juju model.
+type Unit interface {
+	Life() state.Life
+	Name() string
+}
+
 // User represents a user known to the Juju controller.
 type User interface {
 	IsDeleted() bool
@@ -57,6 +85,33 @@ type stateShim struct {
 	*state.State
 }
 
+// AgentVersion returns the agent version from the model's config.
+func (s stateShim) AgentVersion() (version.Number, error) {
+	cfg, err := s.State.ModelConfig()
+	if err != nil {
+		return version.Zero, errors.Trace(err)
+	}
+	v, ok := cfg.AgentVersion()
+	if !ok {
+		return version.Zero, errors.NotFoundf("agent version")
+	}
+	return v, nil
+}
+
+func (s stateShim) AllApplications() ([]Application, error) {
+	applications, err := s.State.AllApplications()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	out := make([]Application, len(applications))
+	for i, a := range applications {
+		if a != nil {
+			out[i] = applicationShim{a}
+		}
+	}
+	return out, nil
+}
+
 func (s stateShim) AllMachines() ([]Machine, error) {
 	machines, err := s.State.AllMachines()
 	if err != nil {
@@ -106,3 +161,29 @@ func (s stateShim) ForModel(tag names.ModelTag) (StateCloser, error) {
 	}
 	return stateShim{st}, nil
 }
+
+func (s stateShim) LoadModelStatuses() (ModelStatuses, error) {
+	statuses, err := s.State.LoadModelStatuses()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return statuses, nil
+}
+
+type applicationShim struct {
+	*state.Application
+}
+
+func (a applicationShim) AllUnits() ([]Unit, error) {
+	units, err := a.Application.AllUnits()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	out := make([]Unit, len(units))
+	for i, u := range units {
+		if u != nil {
+			out[i] = u
+		}
+	}
+	return out, nil
+}
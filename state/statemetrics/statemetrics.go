@@ -7,6 +7,10 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/status"
+	jujuversion "github.com/juju/juju/version"
 )
 
 const (
@@ -20,6 +24,11 @@ const (
 	domainLabel           = "domain"
 	agentStatusLabel      = "agent_status"
 	machineStatusLabel    = "machine_status"
+	workloadStatusLabel   = "workload_status"
+	modelUUIDLabel        = "model_uuid"
+	modelNameLabel        = "model_name"
+	agentKindLabel        = "agent_kind"
+	versionLabel          = "version"
 )
 
 var (
@@ -41,6 +50,33 @@ var (
 		domainLabel,
 	}
 
+	perModelLabelNames = []string{
+		modelUUIDLabel,
+		modelNameLabel,
+	}
+
+	modelMachineLabelNames = append([]string{
+		agentStatusLabel,
+		machineStatusLabel,
+	}, perModelLabelNames...)
+
+	modelApplicationLabelNames = append([]string{
+		statusLabel,
+	}, perModelLabelNames...)
+
+	modelUnitLabelNames = append([]string{
+		agentStatusLabel,
+		workloadStatusLabel,
+	}, perModelLabelNames...)
+
+	modelAgentsLostLabelNames = append([]string{
+		agentKindLabel,
+	}, perModelLabelNames...)
+
+	modelAgentVersionLabelNames = append([]string{
+		versionLabel,
+	}, perModelLabelNames...)
+
 	logger = loggo.GetLogger("juju.state.statemetrics")
 )
 
@@ -55,6 +91,17 @@ type Collector struct {
 	models   *prometheus.GaugeVec
 	machines *prometheus.GaugeVec
 	users    *prometheus.GaugeVec
+
+	controllerAgentVersion *prometheus.GaugeVec
+
+	// The model_* metrics describe the contents of each model.
+	modelMachines       *prometheus.GaugeVec
+	modelApplications   *prometheus.GaugeVec
+	modelUnits          *prometheus.GaugeVec
+	modelUnitsInError   *prometheus.GaugeVec
+	modelAgentsLost     *prometheus.GaugeVec
+	modelUnitsExecuting *prometheus.GaugeVec
+	modelAgentVersions  *prometheus.GaugeVec
 }
 
 // New returns a new Collector.
@@ -100,6 +147,72 @@ func New(st State) *Collector {
 			},
 			userLabelNames,
 		),
+
+		controllerAgentVersion: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: metricsNamespace,
+				Name:      "controller_agent_version",
+				Help:      "Agent version of the controller collecting the metrics, with a value of 1.",
+			},
+			[]string{versionLabel},
+		),
+
+		modelMachines: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: metricsNamespace,
+				Name:      "model_machines",
+				Help:      "Number of machines in each model.",
+			},
+			modelMachineLabelNames,
+		),
+		modelApplications: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: metricsNamespace,
+				Name:      "model_applications",
+				Help:      "Number of applications in each model.",
+			},
+			modelApplicationLabelNames,
+		),
+		modelUnits: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: metricsNamespace,
+				Name:      "model_units",
+				Help:      "Number of units in each model.",
+			},
+			modelUnitLabelNames,
+		),
+		modelUnitsInError: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: metricsNamespace,
+				Name:      "model_units_in_error",
+				Help:      "Number of units in each model with an error workload status.",
+			},
+			perModelLabelNames,
+		),
+		modelAgentsLost: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: metricsNamespace,
+				Name:      "model_agents_lost",
+				Help:      "Number of machine and unit agents in each model that are not communicating with the controller.",
+			},
+			modelAgentsLostLabelNames,
+		),
+		modelUnitsExecuting: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: metricsNamespace,
+				Name:      "model_units_executing",
+				Help:      "Number of units in each model whose agents are executing a hook or action.",
+			},
+			perModelLabelNames,
+		),
+		modelAgentVersions: prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Namespace: metricsNamespace,
+				Name:      "model_agent_version",
+				Help:      "Agent version of each model, with a value of 1.",
+			},
+			modelAgentVersionLabelNames,
+		),
 	}
 }
 
@@ -109,6 +222,15 @@ func (c *Collector) Describe(ch chan<- *prometheus.Desc) {
 	c.models.Describe(ch)
 	c.users.Describe(ch)
 
+	c.controllerAgentVersion.Describe(ch)
+	c.modelMachines.Describe(ch)
+	c.modelApplications.Describe(ch)
+	c.modelUnits.Describe(ch)
+	c.modelUnitsInError.Describe(ch)
+	c.modelAgentsLost.Describe(ch)
+	c.modelUnitsExecuting.Describe(ch)
+	c.modelAgentVersions.Describe(ch)
+
 	c.scrapeErrors.Describe(ch)
 	c.scrapeDuration.Describe(ch)
 }
@@ -124,18 +246,38 @@ func (c *Collector) Collect(ch chan<- prometheus.Metric) {
 	c.machines.Reset()
 	c.models.Reset()
 	c.users.Reset()
+	c.controllerAgentVersion.Reset()
+	c.modelMachines.Reset()
+	c.modelApplications.Reset()
+	c.modelUnits.Reset()
+	c.modelUnitsInError.Reset()
+	c.modelAgentsLost.Reset()
+	c.modelUnitsExecuting.Reset()
+	c.modelAgentVersions.Reset()
 
 	c.updateMetrics()
 
 	c.machines.Collect(ch)
 	c.models.Collect(ch)
 	c.users.Collect(ch)
+	c.controllerAgentVersion.Collect(ch)
+	c.modelMachines.Collect(ch)
+	c.modelApplications.Collect(ch)
+	c.modelUnits.Collect(ch)
+	c.modelUnitsInError.Collect(ch)
+	c.modelAgentsLost.Collect(ch)
+	c.modelUnitsExecuting.Collect(ch)
+	c.modelAgentVersions.Collect(ch)
 }
 
 func (c *Collector) updateMetrics() {
 	logger.Tracef("updating state metrics")
 	defer logger.Tracef("updated state metrics")
 
+	c.controllerAgentVersion.With(prometheus.Labels{
+		versionLabel: jujuversion.Current.String(),
+	}).Set(1)
+
 	models, err := c.st.AllModels()
 	if err != nil {
 		logger.Debugf("error getting models: %v", err)
@@ -203,6 +345,18 @@ func (c *Collector) updateModelMetrics(model Model) {
 	}
 	defer st.Close()
 
+	modelLabels := prometheus.Labels{
+		modelUUIDLabel: modelTag.Id(),
+		modelNameLabel: model.Name(),
+	}
+	statuses, err := st.LoadModelStatuses()
+	if err != nil {
+		c.scrapeErrors.Inc()
+		logger.Debugf("error getting model statuses: %v", err)
+		return
+	}
+
+	var machinesLost int
 	machines, err := st.AllMachines()
 	if err != nil {
 		c.scrapeErrors.Inc()
@@ -210,7 +364,8 @@ func (c *Collector) updateModelMetrics(model Model) {
 		machines = nil
 	}
 	for _, m := range machines {
-		agentStatus, err := m.Status()
+		id := m.Id()
+		agentStatus, err := statuses.MachineAgent(id)
 		if errors.IsNotFound(err) {
 			continue // Machine removed
 		} else if err != nil {
@@ -219,11 +374,9 @@ func (c *Collector) updateModelMetrics(model Model) {
 			continue
 		}
 
-		machineStatus, err := m.InstanceStatus()
+		machineStatus, err := statuses.MachineInstance(id)
 		if errors.IsNotFound(err) {
 			continue // Machine removed
-		} else if errors.IsNotProvisioned(err) {
-			machineStatus.Status = ""
 		} else if err != nil {
 			c.scrapeErrors.Inc()
 			logger.Debugf("error getting machine status: %v", err)
@@ -235,10 +388,116 @@ func (c *Collector) updateModelMetrics(model Model) {
 			lifeLabel:          m.Life().String(),
 			machineStatusLabel: string(machineStatus.Status),
 		}).Inc()
+
+		// The model metrics report the agent status as "juju status"
+		// does, taking the agent's presence into account.
+		agentStatus = status.MachineStatusWithPresence(
+			agentStatus, m.Life() == state.Dead,
+			func() bool { return statuses.MachineAgentAlive(id) },
+		)
+		if agentStatus.Status == status.Down {
+			machinesLost++
+		}
+		c.modelMachines.With(withModelLabels(modelLabels, prometheus.Labels{
+			agentStatusLabel:   string(agentStatus.Status),
+			machineStatusLabel: string(machineStatus.Status),
+		})).Inc()
 	}
 
 	c.models.With(prometheus.Labels{
 		lifeLabel:   model.Life().String(),
 		statusLabel: string(modelStatus.Status),
 	}).Inc()
+
+	agentVersion, err := st.AgentVersion()
+	if err != nil {
+		c.scrapeErrors.Inc()
+		logger.Debugf("error getting model agent version: %v", err)
+	} else {
+		c.modelAgentVersions.With(withModelLabels(modelLabels, prometheus.Labels{
+			versionLabel: agentVersion.String(),
+		})).Set(1)
+	}
+
+	var unitsInError, unitsLost, unitsExecuting int
+	applications, err := st.AllApplications()
+	if err != nil {
+		c.scrapeErrors.Inc()
+		logger.Debugf("error getting applications: %v", err)
+		applications = nil
+	}
+	for _, a := range applications {
+		applicationStatus, err := a.Status()
+		if errors.IsNotFound(err) {
+			continue // Application removed
+		} else if err != nil {
+			c.scrapeErrors.Inc()
+			logger.Debugf("error getting application status: %v", err)
+			continue
+		}
+		c.modelApplications.With(withModelLabels(modelLabels, prometheus.Labels{
+			statusLabel: string(applicationStatus.Status),
+		})).Inc()
+
+		units, err := a.AllUnits()
+		if err != nil {
+			c.scrapeErrors.Inc()
+			logger.Debugf("error getting units of application %q: %v", a.Name(), err)
+			continue
+		}
+		for _, u := range units {
+			name := u.Name()
+			agent, err := statuses.UnitAgent(name)
+			if errors.IsNotFound(err) {
+				continue // Unit removed
+			} else if err != nil {
+				c.scrapeErrors.Inc()
+				logger.Debugf("error getting status of unit %q: %v", name, err)
+				continue
+			}
+			workload, err := statuses.UnitWorkload(name)
+			if errors.IsNotFound(err) {
+				continue // Unit removed
+			} else if err != nil {
+				c.scrapeErrors.Inc()
+				logger.Debugf("error getting status of unit %q: %v", name, err)
+				continue
+			}
+			agent, workload = status.UnitStatusWithPresence(
+				name, agent, workload, u.Life() == state.Dead,
+				func() bool { return statuses.UnitAgentAlive(name) },
+			)
+			switch agent.Status {
+			case status.Lost:
+				unitsLost++
+			case status.Executing:
+				unitsExecuting++
+			}
+			if workload.Status == status.Error {
+				unitsInError++
+			}
+			c.modelUnits.With(withModelLabels(modelLabels, prometheus.Labels{
+				agentStatusLabel:    string(agent.Status),
+				workloadStatusLabel: string(workload.Status),
+			})).Inc()
+		}
+	}
+
+	c.modelUnitsInError.With(modelLabels).Set(float64(unitsInError))
+	c.modelUnitsExecuting.With(modelLabels).Set(float64(unitsExecuting))
+	c.modelAgentsLost.With(withModelLabels(modelLabels, prometheus.Labels{
+		agentKindLabel: "machine",
+	})).Set(float64(machinesLost))
+	c.modelAgentsLost.With(withModelLabels(modelLabels, prometheus.Labels{
+		agentKindLabel: "unit",
+	})).Set(float64(unitsLost))
+}
+
+// withModelLabels returns the given labels combined with the labels
+// identifying a model.
+func withModelLabels(modelLabels, labels prometheus.Labels) prometheus.Labels {
+	for k, v := range modelLabels {
+		labels[k] = v
+	}
+	return labels
 }
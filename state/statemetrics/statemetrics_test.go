@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/version"
 	"github.com/prometheus/client_golang/prometheus"
 	dto "github.com/prometheus/client_model/go"
 	gc "gopkg.in/check.v1"
@@ -18,6 +19,7 @@ import (
 	"github.com/juju/juju/state"
 	"github.com/juju/juju/state/statemetrics"
 	"github.com/juju/juju/status"
+	jujuversion "github.com/juju/juju/version"
 )
 
 type collectorSuite struct {
@@ -48,19 +50,61 @@ func (s *collectorSuite) SetUpTest(c *gc.C) {
 	}}
 
 	models := []*mockModel{{
-		tag:    names.NewModelTag("b266dff7-eee8-4297-b03a-4692796ec193"),
-		life:   state.Alive,
-		status: status.StatusInfo{Status: status.Available},
+		tag:          names.NewModelTag(webUUID),
+		name:         "web",
+		life:         state.Alive,
+		status:       status.StatusInfo{Status: status.Available},
+		agentVersion: version.MustParse("2.2.0"),
 		machines: []*mockMachine{{
+			id:             "0",
 			life:           state.Alive,
 			agentStatus:    status.StatusInfo{Status: status.Started},
+			agentAlive:     true,
 			instanceStatus: status.StatusInfo{Status: status.Running},
 		}},
+		applications: []*mockApplication{{
+			name:   "mysql",
+			status: status.StatusInfo{Status: status.Error},
+			units: []*mockUnit{{
+				name:           "mysql/0",
+				life:           state.Alive,
+				agentStatus:    status.StatusInfo{Status: status.Idle},
+				workloadStatus: status.StatusInfo{Status: status.Active},
+				agentAlive:     true,
+			}, {
+				name:           "mysql/1",
+				life:           state.Alive,
+				agentStatus:    status.StatusInfo{Status: status.Idle},
+				workloadStatus: status.StatusInfo{Status: status.Error},
+				agentAlive:     true,
+			}},
+		}, {
+			name:   "wordpress",
+			status: status.StatusInfo{Status: status.Active},
+			units: []*mockUnit{{
+				name: "wordpress/0",
+				life: state.Alive,
+				agentStatus: status.StatusInfo{
+					Status:  status.Executing,
+					Message: "running config-changed hook",
+				},
+				workloadStatus: status.StatusInfo{Status: status.Active},
+				agentAlive:     true,
+			}, {
+				name:           "wordpress/1",
+				life:           state.Alive,
+				agentStatus:    status.StatusInfo{Status: status.Idle},
+				workloadStatus: status.StatusInfo{Status: status.Active},
+			}},
+		}},
 	}, {
-		tag:    names.NewModelTag("1ab5799e-e72d-4de7-b70d-499edfab0e5c"),
-		life:   state.Dying,
-		status: status.StatusInfo{Status: status.Destroying},
+		tag:          names.NewModelTag(oldUUID),
+		name:         "old",
+		life:         state.Dying,
+		status:       status.StatusInfo{Status: status.Destroying},
+		agentVersion: version.MustParse("2.1.2"),
 		machines: []*mockMachine{{
+			id:             "0",
 			life:           state.Alive,
 			agentStatus:    status.StatusInfo{Status: status.Error},
 			instanceStatus: status.StatusInfo{Status: status.ProvisioningError},
@@ -88,6 +132,14 @@ func (s *collectorSuite) TestDescribe(c *gc.C) {
 		`.*fqName: "juju_state_machines".*`,
 		`.*fqName: "juju_state_models".*`,
 		`.*fqName: "juju_state_users".*`,
+		`.*fqName: "juju_state_controller_agent_version".*`,
+		`.*fqName: "juju_state_model_machines".*`,
+		`.*fqName: "juju_state_model_applications".*`,
+		`.*fqName: "juju_state_model_units".*`,
+		`.*fqName: "juju_state_model_units_in_error".*`,
+		`.*fqName: "juju_state_model_agents_lost".*`,
+		`.*fqName: "juju_state_model_units_executing".*`,
+		`.*fqName: "juju_state_model_agent_version".*`,
 		`.*fqName: "juju_state_scrape_errors".*`,
 		`.*fqName: "juju_state_scrape_duration_seconds".*`,
 	}
@@ -133,6 +185,11 @@ func (s *collectorSuite) checkExpected(c *gc.C, actual, expected []dto.Metric) {
 	}
 }
 
+const (
+	webUUID = "b266dff7-eee8-4297-b03a-4692796ec193"
+	oldUUID = "1ab5799e-e72d-4de7-b70d-499edfab0e5c"
+)
+
 func float64ptr(v float64) *float64 {
 	return &v
 }
@@ -213,6 +270,174 @@ func (s *collectorSuite) TestCollect(c *gc.C) {
 			},
 		},
 
+		// juju_state_controller_agent_version
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(1)},
+			Label: []*dto.LabelPair{
+				labelpair("version", jujuversion.Current.String()),
+			},
+		},
+
+		// juju_state_model_machines
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(1)},
+			Label: []*dto.LabelPair{
+				labelpair("agent_status", "started"),
+				labelpair("machine_status", "running"),
+				labelpair("model_name", "web"),
+				labelpair("model_uuid", webUUID),
+			},
+		},
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(1)},
+			Label: []*dto.LabelPair{
+				labelpair("agent_status", "down"),
+				labelpair("machine_status", "provisioning error"),
+				labelpair("model_name", "old"),
+				labelpair("model_uuid", oldUUID),
+			},
+		},
+
+		// juju_state_model_applications
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(1)},
+			Label: []*dto.LabelPair{
+				labelpair("model_name", "web"),
+				labelpair("model_uuid", webUUID),
+				labelpair("status", "error"),
+			},
+		},
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(1)},
+			Label: []*dto.LabelPair{
+				labelpair("model_name", "web"),
+				labelpair("model_uuid", webUUID),
+				labelpair("status", "active"),
+			},
+		},
+
+		// juju_state_model_units
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(1)},
+			Label: []*dto.LabelPair{
+				labelpair("agent_status", "idle"),
+				labelpair("model_name", "web"),
+				labelpair("model_uuid", webUUID),
+				labelpair("workload_status", "active"),
+			},
+		},
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(1)},
+			Label: []*dto.LabelPair{
+				labelpair("agent_status", "idle"),
+				labelpair("model_name", "web"),
+				labelpair("model_uuid", webUUID),
+				labelpair("workload_status", "error"),
+			},
+		},
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(1)},
+			Label: []*dto.LabelPair{
+				labelpair("agent_status", "executing"),
+				labelpair("model_name", "web"),
+				labelpair("model_uuid", webUUID),
+				labelpair("workload_status", "active"),
+			},
+		},
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(1)},
+			Label: []*dto.LabelPair{
+				labelpair("agent_status", "lost"),
+				labelpair("model_name", "web"),
+				labelpair("model_uuid", webUUID),
+				labelpair("workload_status", "unknown"),
+			},
+		},
+
+		// juju_state_model_units_in_error
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(1)},
+			Label: []*dto.LabelPair{
+				labelpair("model_name", "web"),
+				labelpair("model_uuid", webUUID),
+			},
+		},
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(0)},
+			Label: []*dto.LabelPair{
+				labelpair("model_name", "old"),
+				labelpair("model_uuid", oldUUID),
+			},
+		},
+
+		// juju_state_model_agents_lost
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(0)},
+			Label: []*dto.LabelPair{
+				labelpair("agent_kind", "machine"),
+				labelpair("model_name", "web"),
+				labelpair("model_uuid", webUUID),
+			},
+		},
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(1)},
+			Label: []*dto.LabelPair{
+				labelpair("agent_kind", "unit"),
+				labelpair("model_name", "web"),
+				labelpair("model_uuid", webUUID),
+			},
+		},
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(1)},
+			Label: []*dto.LabelPair{
+				labelpair("agent_kind", "machine"),
+				labelpair("model_name", "old"),
+				labelpair("model_uuid", oldUUID),
+			},
+		},
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(0)},
+			Label: []*dto.LabelPair{
+				labelpair("agent_kind", "unit"),
+				labelpair("model_name", "old"),
+				labelpair("model_uuid", oldUUID),
+			},
+		},
+
+		// juju_state_model_units_executing
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(1)},
+			Label: []*dto.LabelPair{
+				labelpair("model_name", "web"),
+				labelpair("model_uuid", webUUID),
+			},
+		},
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(0)},
+			Label: []*dto.LabelPair{
+				labelpair("model_name", "old"),
+				labelpair("model_uuid", oldUUID),
+			},
+		},
+
+		// juju_state_model_agent_version
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(1)},
+			Label: []*dto.LabelPair{
+				labelpair("model_name", "web"),
+				labelpair("model_uuid", webUUID),
+				labelpair("version", "2.2.0"),
+			},
+		},
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(1)},
+			Label: []*dto.LabelPair{
+				labelpair("model_name", "old"),
+				labelpair("model_uuid", oldUUID),
+				labelpair("version", "2.1.2"),
+			},
+		},
+
 		// juju_state_scrape_errors
 		{
 			Gauge: &dto.Gauge{Value: float64ptr(0)},
@@ -238,7 +463,18 @@ func (s *collectorSuite) TestCollectErrors(c *gc.C) {
 	scrapeDurationMetric := dtoMetrics[len(dtoMetrics)-1]
 	c.Assert(scrapeDurationMetric.Gauge.GetValue(), gc.Not(gc.Equals), 0)
 
+	labelpair := func(n, v string) *dto.LabelPair {
+		return &dto.LabelPair{Name: &n, Value: &v}
+	}
 	s.checkExpected(c, dtoMetrics, []dto.Metric{
+		// juju_state_controller_agent_version
+		{
+			Gauge: &dto.Gauge{Value: float64ptr(1)},
+			Label: []*dto.LabelPair{
+				labelpair("version", jujuversion.Current.String()),
+			},
+		},
+
 		// juju_state_scrape_errors
 		{
 			Gauge: &dto.Gauge{Value: float64ptr(2)},
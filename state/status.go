@@ -4,6 +4,10 @@
 package state
 
 import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/juju/errors"
@@ -62,12 +66,17 @@ func getStatus(st *State, globalKey, badge string) (_ status.StatusInfo, err err
 		return status.StatusInfo{}, errors.Trace(err)
 	}
 
+	return doc.asStatusInfo(), nil
+}
+
+// asStatusInfo converts the document to a StatusInfo.
+func (doc statusDoc) asStatusInfo() status.StatusInfo {
 	return status.StatusInfo{
 		Status:  doc.Status,
 		Message: doc.StatusInfo,
 		Data:    utils.UnescapeKeys(doc.StatusData),
 		Since:   unixNanoToTime(doc.Updated),
-	}, nil
+	}
 }
 
 // setStatusParams configures a setStatus call. All parameters are presumed to
@@ -161,6 +170,7 @@ func removeStatusOp(st *State, globalKey string) txn.Op {
 }
 
 type historicalStatusDoc struct {
+	Id         bson.ObjectId          `bson:"_id,omitempty"`
 	ModelUUID  string                 `bson:"model-uuid"`
 	GlobalKey  string                 `bson:"globalkey"`
 	Status     status.Status          `bson:"status"`
@@ -259,6 +269,177 @@ func statusHistory(args *statusHistoryArgs) ([]status.StatusInfo, error) {
 	return results, nil
 }
 
+// QueryStatusHistory returns a page of the status history entries of
+// the unit agents and workloads, and the machine agents and instances,
+// in the model that match the query, oldest first. If the query names
+// an application, only the entries for its units are returned. If the
+// query has a FromDate, the first page starts with each entity's latest
+// entry from before it. If there are more entries, the Id to query
+// after for the next page is returned too.
+func (st *State) QueryStatusHistory(query status.HistoryQuery) ([]status.HistoryEntry, string, error) {
+	if err := query.Validate(); err != nil {
+		return nil, "", errors.Annotate(err, "validating query")
+	}
+	keyPattern := `^(u#[^#]+(#charm)?|m#[^#]+(#instance)?)$`
+	if query.Application != "" {
+		keyPattern = fmt.Sprintf(`^u#%s/[0-9]+(#charm)?$`, regexp.QuoteMeta(query.Application))
+	}
+	sel := bson.D{{"globalkey", bson.RegEx{Pattern: keyPattern}}}
+	if !query.Exclude.IsEmpty() {
+		sel = append(sel, bson.DocElem{"statusinfo", bson.D{{"$nin", query.Exclude.Values()}}})
+	}
+	updated := bson.D{}
+	if query.FromDate != nil {
+		updated = append(updated, bson.DocElem{"$gte", query.FromDate.UnixNano()})
+	}
+	if query.ToDate != nil {
+		updated = append(updated, bson.DocElem{"$lte", query.ToDate.UnixNano()})
+	}
+	if len(updated) > 0 {
+		sel = append(sel, bson.DocElem{"updated", updated})
+	}
+
+	history, closer := st.getCollection(statusesHistoryC)
+	defer closer()
+
+	var docs []historicalStatusDoc
+	if query.After == "" {
+		if query.FromDate != nil {
+			seeds, err := latestStatusHistoryBefore(history, keyPattern, query.Exclude.Values(), *query.FromDate)
+			if err != nil {
+				return nil, "", errors.Annotate(err, "cannot get status history")
+			}
+			docs = seeds
+		}
+	} else {
+		if !bson.IsObjectIdHex(query.After) {
+			return nil, "", errors.NotValidf("status history entry id %q", query.After)
+		}
+		var after historicalStatusDoc
+		err := history.FindId(bson.ObjectIdHex(query.After)).One(&after)
+		if err == mgo.ErrNotFound {
+			return nil, "", errors.NotFoundf("status history entry %q", query.After)
+		} else if err != nil {
+			return nil, "", errors.Annotate(err, "cannot get status history")
+		}
+		sel = append(sel, bson.DocElem{"$or", []bson.D{
+			{{"updated", bson.D{{"$gt", after.Updated}}}},
+			{{"updated", after.Updated}, {"_id", bson.D{{"$gt", after.Id}}}},
+		}})
+	}
+
+	limit := query.Limit
+	if limit == 0 {
+		limit = status.DefaultHistoryQueryLimit
+	}
+	// Read one more entry than the page holds, to tell whether there
+	// is another page. Entries with the same time are ordered by id,
+	// giving each page a well defined place to start from.
+	var page []historicalStatusDoc
+	err := history.Find(sel).Sort("updated", "_id").Limit(limit + 1).All(&page)
+	if err != nil {
+		return nil, "", errors.Annotate(err, "cannot get status history")
+	}
+	var next string
+	if len(page) > limit {
+		page = page[:limit]
+		next = page[limit-1].Id.Hex()
+	}
+	docs = append(docs, page...)
+
+	entries := make([]status.HistoryEntry, 0, len(docs))
+	for _, doc := range docs {
+		entity, kind, ok := historyEntityForGlobalKey(doc.GlobalKey)
+		if !ok {
+			continue
+		}
+		entries = append(entries, status.HistoryEntry{
+			Id:      doc.Id.Hex(),
+			Entity:  entity,
+			Kind:    kind,
+			Status:  doc.Status,
+			Message: doc.StatusInfo,
+			Since:   *unixNanoToTime(doc.Updated),
+		})
+	}
+	return entries, next, nil
+}
+
+// latestStatusHistoryBefore returns, oldest first, the latest status
+// history entry from before the given time of each entity whose global
+// key matches the pattern, skipping entries with excluded messages.
+func latestStatusHistoryBefore(history mongo.Collection, keyPattern string, exclude []string, t time.Time) ([]historicalStatusDoc, error) {
+	selector := func(globalKey interface{}) bson.D {
+		sel := bson.D{
+			{"globalkey", globalKey},
+			{"updated", bson.D{{"$lt", t.UnixNano()}}},
+		}
+		if len(exclude) > 0 {
+			sel = append(sel, bson.DocElem{"statusinfo", bson.D{{"$nin", exclude}}})
+		}
+		return sel
+	}
+	var keys []string
+	err := history.Find(selector(bson.RegEx{Pattern: keyPattern})).Distinct("globalkey", &keys)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	docs := make([]historicalStatusDoc, 0, len(keys))
+	for _, key := range keys {
+		// Each of these queries is served by the index on
+		// model-uuid, globalkey and updated.
+		var doc historicalStatusDoc
+		if err := history.Find(selector(key)).Sort("-updated", "-_id").One(&doc); err != nil {
+			return nil, errors.Trace(err)
+		}
+		docs = append(docs, doc)
+	}
+	sort.Sort(historicalStatusDocsByTime(docs))
+	return docs, nil
+}
+
+type historicalStatusDocsByTime []historicalStatusDoc
+
+func (d historicalStatusDocsByTime) Len() int      { return len(d) }
+func (d historicalStatusDocsByTime) Swap(i, j int) { d[i], d[j] = d[j], d[i] }
+func (d historicalStatusDocsByTime) Less(i, j int) bool {
+	if d[i].Updated != d[j].Updated {
+		return d[i].Updated < d[j].Updated
+	}
+	return d[i].GlobalKey < d[j].GlobalKey
+}
+
+// historyEntityForGlobalKey returns the name of the unit or machine,
+// and the kind of status history, recorded under the given key. It
+// returns false if the key is not recognized.
+func historyEntityForGlobalKey(key string) (string, status.HistoryKind, bool) {
+	if len(key) < 3 || key[1] != '#' {
+		return "", "", false
+	}
+	id := key[2:]
+	switch key[0] {
+	case 'u':
+		if name := strings.TrimSuffix(id, "#charm"); name != id {
+			return name, status.KindWorkload, true
+		}
+		return id, status.KindUnitAgent, true
+	case 'm':
+		machineId := strings.TrimSuffix(id, "#instance")
+		instance := machineId != id
+		container := strings.Contains(machineId, "/")
+		switch {
+		case instance && container:
+			return machineId, status.KindContainerInstance, true
+		case instance:
+			return machineId, status.KindMachineInstance, true
+		case container:
+			return machineId, status.KindContainer, true
+		}
+		return machineId, status.KindMachine, true
+	}
+	return "", "", false
+}
+
 // PruneStatusHistory removes status history entries until
 // only logs newer than <maxLogTime> remain and also ensures
 // that the collection is smaller than <maxLogsMB> after the
@@ -4,9 +4,11 @@
 package state_test
 
 import (
+	"fmt"
 	"regexp"
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
 	"github.com/juju/utils/set"
@@ -266,3 +268,168 @@ func (s *StatusHistorySuite) TestStatusHistoryFiltersByDateAndDelta(c *gc.C) {
 	c.Assert(history[1].Message, gc.Equals, "waiting for machine")
 	c.Assert(history[2].Message, gc.Equals, "2 days ago")
 }
+
+func (s *StatusHistorySuite) TestQueryStatusHistory(c *gc.C) {
+	mysql := s.Factory.MakeApplication(c, nil)
+	mysqlUnit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: mysql})
+	wordpress := s.Factory.MakeApplication(c, &factory.ApplicationParams{
+		Charm: s.Factory.MakeCharm(c, &factory.CharmParams{Name: "wordpress"}),
+	})
+	wordpressUnit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: wordpress})
+
+	now := time.Now()
+	twoDaysAgo := now.Add(-48 * time.Hour)
+	oneDayAgo := now.Add(-24 * time.Hour)
+	hourAgo := now.Add(-time.Hour)
+	for _, update := range []struct {
+		unit *state.Unit
+		info status.StatusInfo
+	}{
+		{mysqlUnit, status.StatusInfo{Status: status.Error, Message: "hook failed", Since: &twoDaysAgo}},
+		{mysqlUnit, status.StatusInfo{Status: status.Active, Message: "ready", Since: &oneDayAgo}},
+		{wordpressUnit, status.StatusInfo{Status: status.Active, Message: "ready", Since: &oneDayAgo}},
+	} {
+		err := update.unit.SetStatus(update.info)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	entries, next, err := s.State.QueryStatusHistory(status.HistoryQuery{
+		Application: mysql.Name(),
+		ToDate:      &hourAgo,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(next, gc.Equals, "")
+	c.Assert(entries, gc.HasLen, 2)
+	for i, expect := range []status.StatusInfo{
+		{Status: status.Error, Message: "hook failed", Since: &twoDaysAgo},
+		{Status: status.Active, Message: "ready", Since: &oneDayAgo},
+	} {
+		c.Check(entries[i].Entity, gc.Equals, mysqlUnit.Name())
+		c.Check(entries[i].Kind, gc.Equals, status.KindWorkload)
+		c.Check(entries[i].Status, gc.Equals, expect.Status)
+		c.Check(entries[i].Message, gc.Equals, expect.Message)
+		c.Check(entries[i].Since.Equal(*expect.Since), jc.IsTrue)
+	}
+
+	entries, _, err = s.State.QueryStatusHistory(status.HistoryQuery{FromDate: &oneDayAgo})
+	c.Assert(err, jc.ErrorIsNil)
+	entities := set.NewStrings()
+	for i, entry := range entries {
+		entities.Add(entry.Entity)
+		if i > 0 {
+			c.Check(entry.Since.Before(entries[i-1].Since), jc.IsFalse)
+		}
+	}
+	c.Assert(entities.Contains(mysqlUnit.Name()), jc.IsTrue)
+	c.Assert(entities.Contains(wordpressUnit.Name()), jc.IsTrue)
+
+	entries, _, err = s.State.QueryStatusHistory(status.HistoryQuery{
+		Application: wordpress.Name(),
+		FromDate:    &oneDayAgo,
+		ToDate:      &hourAgo,
+		Exclude:     set.NewStrings("ready"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(entries, gc.HasLen, 0)
+}
+
+func (s *StatusHistorySuite) TestQueryStatusHistoryInvalid(c *gc.C) {
+	now := time.Now()
+	hourAgo := now.Add(-time.Hour)
+	_, _, err := s.State.QueryStatusHistory(status.HistoryQuery{FromDate: &now, ToDate: &hourAgo})
+	c.Assert(err, gc.ErrorMatches, "validating query: ToDate before FromDate not valid")
+	_, _, err = s.State.QueryStatusHistory(status.HistoryQuery{After: "invalid"})
+	c.Assert(err, gc.ErrorMatches, `status history entry id "invalid" not valid`)
+	_, _, err = s.State.QueryStatusHistory(status.HistoryQuery{After: "58fde9b5e1382e0a6f2a5d1c"})
+	c.Assert(err, jc.Satisfies, errors.IsNotFound)
+}
+
+func (s *StatusHistorySuite) TestQueryStatusHistoryFromDateStartsWithLatestBefore(c *gc.C) {
+	mysql := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: mysql})
+
+	now := time.Now()
+	twoDaysAgo := now.Add(-48 * time.Hour)
+	dayAgo := now.Add(-24 * time.Hour)
+	hourAgo := now.Add(-time.Hour)
+	halfHourAgo := now.Add(-30 * time.Minute)
+	for _, info := range []status.StatusInfo{
+		{Status: status.Maintenance, Message: "installing", Since: &twoDaysAgo},
+		{Status: status.Error, Message: "hook failed", Since: &dayAgo},
+		{Status: status.Active, Message: "ready", Since: &halfHourAgo},
+	} {
+		err := unit.SetStatus(info)
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	// The unit was in error at the start of the query, and has been
+	// since a day ago.
+	entries, _, err := s.State.QueryStatusHistory(status.HistoryQuery{
+		Application: mysql.Name(),
+		FromDate:    &hourAgo,
+		ToDate:      &halfHourAgo,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	var workload []status.HistoryEntry
+	for _, entry := range entries {
+		if entry.Kind == status.KindWorkload {
+			workload = append(workload, entry)
+		}
+	}
+	c.Assert(workload, gc.HasLen, 2)
+	c.Check(workload[0].Message, gc.Equals, "hook failed")
+	c.Check(workload[0].Since.Equal(dayAgo), jc.IsTrue)
+	c.Check(workload[1].Message, gc.Equals, "ready")
+
+	// The latest entry that is not excluded gives the status.
+	entries, _, err = s.State.QueryStatusHistory(status.HistoryQuery{
+		Application: mysql.Name(),
+		FromDate:    &hourAgo,
+		ToDate:      &halfHourAgo,
+		Exclude:     set.NewStrings("hook failed"),
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	workload = nil
+	for _, entry := range entries {
+		if entry.Kind == status.KindWorkload {
+			workload = append(workload, entry)
+		}
+	}
+	c.Assert(workload, gc.HasLen, 2)
+	c.Check(workload[0].Message, gc.Equals, "installing")
+}
+
+func (s *StatusHistorySuite) TestQueryStatusHistoryPages(c *gc.C) {
+	mysql := s.Factory.MakeApplication(c, nil)
+	unit := s.Factory.MakeUnit(c, &factory.UnitParams{Application: mysql})
+	// Entries with the same time are still paged through in a
+	// consistent order.
+	since := time.Now()
+	for i := 0; i < 25; i++ {
+		err := unit.SetStatus(status.StatusInfo{
+			Status:  status.Active,
+			Message: fmt.Sprintf("step %d", i),
+			Since:   &since,
+		})
+		c.Assert(err, jc.ErrorIsNil)
+	}
+
+	all, next, err := s.State.QueryStatusHistory(status.HistoryQuery{Application: mysql.Name()})
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(next, gc.Equals, "")
+
+	var paged []status.HistoryEntry
+	query := status.HistoryQuery{Application: mysql.Name(), Limit: 10}
+	for {
+		entries, next, err := s.State.QueryStatusHistory(query)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Assert(len(entries) <= 10, jc.IsTrue)
+		paged = append(paged, entries...)
+		if next == "" {
+			break
+		}
+		c.Assert(next, gc.Equals, entries[len(entries)-1].Id)
+		query.After = next
+	}
+	c.Assert(paged, jc.DeepEquals, all)
+}
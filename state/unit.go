@@ -2216,8 +2216,25 @@ type ActionSpecsByName map[string]charm.ActionSpec
 // this Unit, and returns its ID.  Note that the use of spec.InsertDefaults
 // mutates payload.
 func (u *Unit) AddAction(name string, payload map[string]interface{}) (Action, error) {
+	return u.AddActionWithOptions(name, payload, ActionOptions{})
+}
+
+// AddActionWithOptions is like AddAction, but also takes the options
+// of the action.
+func (u *Unit) AddActionWithOptions(name string, payload map[string]interface{}, options ActionOptions) (Action, error) {
+	payloadWithDefaults, options, err := u.prepareAction(name, payload, options)
+	if err != nil {
+		return nil, err
+	}
+	return u.st.EnqueueActionWithOptions(u.Tag(), name, payloadWithDefaults, options)
+}
+
+// prepareAction validates the payload of the named action against the
+// action's spec, and returns it with any defaults inserted, along with
+// the given options completed with the action's default timeout.
+func (u *Unit) prepareAction(name string, payload map[string]interface{}, options ActionOptions) (map[string]interface{}, ActionOptions, error) {
 	if len(name) == 0 {
-		return nil, errors.New("no action name given")
+		return nil, options, errors.New("no action name given")
 	}
 
 	// If the action is predefined inside juju, get spec from map
@@ -2225,23 +2242,29 @@ func (u *Unit) AddAction(name string, payload map[string]interface{}) (Action, e
 	if !ok {
 		specs, err := u.ActionSpecs()
 		if err != nil {
-			return nil, err
+			return nil, options, err
 		}
 		spec, ok = specs[name]
 		if !ok {
-			return nil, errors.Errorf("action %q not defined on unit %q", name, u.Name())
+			return nil, options, errors.Errorf("action %q not defined on unit %q", name, u.Name())
 		}
 	}
 	// Reject bad payloads before attempting to insert defaults.
 	err := spec.ValidateParams(payload)
 	if err != nil {
-		return nil, err
+		return nil, options, err
 	}
 	payloadWithDefaults, err := spec.InsertDefaults(payload)
 	if err != nil {
-		return nil, err
+		return nil, options, err
 	}
-	return u.st.EnqueueAction(u.Tag(), name, payloadWithDefaults)
+	if options.Timeout == 0 {
+		options.Timeout, err = actionSpecTimeout(spec)
+		if err != nil {
+			return nil, options, errors.Annotatef(err, "action %q", name)
+		}
+	}
+	return payloadWithDefaults, options, nil
 }
 
 // ActionSpecs gets the ActionSpec map for the Unit's charm.
@@ -2271,9 +2294,10 @@ func (u *Unit) ActionSpecs() (ActionSpecsByName, error) {
 }
 
 // CancelAction removes a pending Action from the queue for this
-// ActionReceiver and marks it as cancelled.
+// ActionReceiver and marks it as cancelled. A running Action is marked
+// as aborting, and is cancelled once the unit agent has stopped it.
 func (u *Unit) CancelAction(action Action) (Action, error) {
-	return action.Finish(ActionResults{Status: ActionCancelled})
+	return action.Cancel("")
 }
 
 // WatchActionNotifications starts and returns a StringsWatcher that
@@ -2282,6 +2306,13 @@ func (u *Unit) WatchActionNotifications() StringsWatcher {
 	return u.st.watchEnqueuedActionsFilteredBy(u)
 }
 
+// WatchActionCancellations starts and returns a StringsWatcher that
+// notifies with the ids of this Unit's running Actions whose
+// cancellation has been requested.
+func (u *Unit) WatchActionCancellations() StringsWatcher {
+	return newActionStatusWatcher(u.st, []ActionReceiver{u}, ActionAborting)
+}
+
 // Actions returns a list of actions pending or completed for this unit.
 func (u *Unit) Actions() ([]Action, error) {
 	return u.st.matchingActions(u)
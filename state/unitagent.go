@@ -39,6 +39,12 @@ func (u *UnitAgent) Status() (status.StatusInfo, error) {
 	if err != nil {
 		return status.StatusInfo{}, errors.Trace(err)
 	}
+	return unitAgentStatus(info), nil
+}
+
+// unitAgentStatus returns the status of a unit agent as reported,
+// given the status recorded for it.
+func unitAgentStatus(info status.StatusInfo) status.StatusInfo {
 	// The current health spec says when a hook error occurs, the workload should
 	// be in error state, but the state model more correctly records the agent
 	// itself as being in error. So we'll do that model translation here.
@@ -49,9 +55,9 @@ func (u *UnitAgent) Status() (status.StatusInfo, error) {
 			Message: "",
 			Data:    map[string]interface{}{},
 			Since:   info.Since,
-		}, nil
+		}
 	}
-	return info, nil
+	return info
 }
 
 // SetStatus sets the status of the unit agent. The optional values
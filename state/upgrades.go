@@ -6,6 +6,7 @@ package state
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
@@ -245,6 +246,115 @@ func DropOldLogIndex(st *State) error {
 	return errors.Trace(err)
 }
 
+// ConvertAuditLogToCapped converts the audit log collection into a
+// capped collection if it was created before the collection was
+// capped. The oldest entries are discarded if they do not all fit.
+// Entries written before the audit log was capped have no time field,
+// so it is first filled in from their timestamps; documents in a
+// capped collection cannot grow, so this must be done beforehand.
+func ConvertAuditLogToCapped(st *State) error {
+	db := st.MongoSession().DB(jujuDB)
+	var stats struct {
+		Capped bool `bson:"capped"`
+	}
+	if err := db.Run(bson.D{{"collStats", auditingC}}, &stats); err != nil {
+		return errors.Annotate(err, "cannot get audit log stats")
+	}
+	if stats.Capped {
+		return nil
+	}
+	if err := addAuditLogTimes(db.C(auditingC)); err != nil {
+		return errors.Annotate(err, "cannot add audit log entry times")
+	}
+	err := db.Run(bson.D{
+		{"convertToCapped", auditingC},
+		{"size", auditLogSize},
+	}, nil)
+	if err != nil {
+		return errors.Annotate(err, "cannot convert audit log to capped collection")
+	}
+	// Converting the collection drops its indexes, so recreate them.
+	coll := db.C(auditingC)
+	for _, index := range allCollections()[auditingC].indexes {
+		if err := coll.EnsureIndex(index); err != nil {
+			return errors.Annotate(err, "cannot create audit log index")
+		}
+	}
+	return nil
+}
+
+// addAuditLogTimes sets the time field, which audit log queries filter
+// and sort on, of the audit log entries that lack one. It is the entry's
+// timestamp in nanoseconds since the epoch.
+func addAuditLogTimes(coll *mgo.Collection) error {
+	query := coll.Find(bson.D{{"time", bson.D{{"$exists", false}}}})
+	iter := query.Select(bson.D{{"timestamp", 1}}).Iter()
+	defer iter.Close()
+	var doc struct {
+		DocId     interface{} `bson:"_id"`
+		Timestamp string      `bson:"timestamp"`
+	}
+	for iter.Next(&doc) {
+		var timestamp time.Time
+		if err := timestamp.UnmarshalText([]byte(doc.Timestamp)); err != nil {
+			upgradesLogger.Warningf("audit entry %v has invalid timestamp %q (skipping)", doc.DocId, doc.Timestamp)
+			continue
+		}
+		update := bson.D{{"$set", bson.D{{"time", timestamp.UnixNano()}}}}
+		if err := coll.UpdateId(doc.DocId, update); err != nil {
+			return errors.Annotatef(err, "updating audit entry %v", doc.DocId)
+		}
+	}
+	return errors.Trace(iter.Close())
+}
+
+// addActionApplicationsBatchSize is the number of actions updated by
+// each transaction run by AddActionApplications.
+const addActionApplicationsBatchSize = 1000
+
+// AddActionApplications records the application of each action whose
+// receiver is a unit, so that actions can be queried by application.
+// Models can hold very many actions, so they are updated in batches
+// rather than by one transaction.
+func AddActionApplications(st *State) error {
+	coll, closer := st.getRawCollection(actionsC)
+	defer closer()
+
+	query := coll.Find(bson.D{{"application", bson.D{{"$exists", false}}}})
+	iter := query.Select(bson.D{{"receiver", 1}}).Iter()
+	defer iter.Close()
+	var ops []txn.Op
+	var doc struct {
+		DocId    string `bson:"_id"`
+		Receiver string `bson:"receiver"`
+	}
+	for iter.Next(&doc) {
+		application := actionApplication(doc.Receiver)
+		if application == "" {
+			continue
+		}
+		ops = append(ops, txn.Op{
+			C:      actionsC,
+			Id:     doc.DocId,
+			Assert: txn.DocExists,
+			Update: bson.D{{"$set", bson.D{{"application", application}}}},
+		})
+		if len(ops) >= addActionApplicationsBatchSize {
+			if err := st.runRawTransaction(ops); err != nil {
+				return errors.Trace(err)
+			}
+			ops = nil
+		}
+	}
+	if err := iter.Close(); err != nil {
+		return errors.Trace(err)
+	}
+	if len(ops) > 0 {
+		return errors.Trace(st.runRawTransaction(ops))
+	}
+	return nil
+}
+
 // AddMigrationAttempt adds an "attempt" field to migration documents
 // which are missing one.
 func AddMigrationAttempt(st *State) error {
@@ -4,6 +4,7 @@
 package state
 
 import (
+	"fmt"
 	"reflect"
 	"time"
 
@@ -398,6 +399,122 @@ func (s *upgradesSuite) TestDropOldIndexWhenNoIndex(c *gc.C) {
 	c.Assert(err, jc.ErrorIsNil)
 }
 
+func (s *upgradesSuite) TestConvertAuditLogToCapped(c *gc.C) {
+	db := s.state.MongoSession().DB(jujuDB)
+	coll := db.C(auditingC)
+	// Recreate the audit log as it was before it was capped.
+	err := coll.DropCollection()
+	c.Assert(err, jc.ErrorIsNil)
+	err = coll.Insert(bson.M{"_id": "1", "model-uuid": "uuid", "time": "t"})
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = ConvertAuditLogToCapped(s.state)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var stats struct {
+		Capped bool `bson:"capped"`
+	}
+	err = db.Run(bson.D{{"collStats", auditingC}}, &stats)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(stats.Capped, jc.IsTrue)
+	count, err := coll.Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(count, gc.Equals, 1)
+	for _, index := range allCollections()[auditingC].indexes {
+		exists, err := hasIndex(coll, index.Key)
+		c.Assert(err, jc.ErrorIsNil)
+		c.Check(exists, jc.IsTrue, gc.Commentf("index %v", index.Key))
+	}
+
+	// Sanity check for idempotency.
+	err = ConvertAuditLogToCapped(s.state)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+func (s *upgradesSuite) TestConvertAuditLogToCappedAddsTimes(c *gc.C) {
+	db := s.state.MongoSession().DB(jujuDB)
+	coll := db.C(auditingC)
+	err := coll.DropCollection()
+	c.Assert(err, jc.ErrorIsNil)
+	// Entries written before the audit log was capped have a
+	// timestamp but no time.
+	t0 := time.Date(2017, 5, 1, 10, 0, 0, 0, time.UTC)
+	t1 := t0.Add(time.Second)
+	err = coll.Insert(
+		bson.M{"_id": "1", "model-uuid": "uuid", "timestamp": "2017-05-01T10:00:00Z"},
+		bson.M{"_id": "2", "model-uuid": "uuid", "timestamp": "2017-05-01T10:00:01Z"},
+		bson.M{"_id": "3", "model-uuid": "uuid", "timestamp": "2017-05-01T10:00:02Z", "time": int64(42)},
+		bson.M{"_id": "4", "model-uuid": "uuid", "timestamp": "garbage"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = ConvertAuditLogToCapped(s.state)
+	c.Assert(err, jc.ErrorIsNil)
+
+	var docs []bson.M
+	err = coll.Find(nil).Sort("_id").Select(bson.M{"time": 1}).All(&docs)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(docs, jc.DeepEquals, []bson.M{
+		{"_id": "1", "time": t0.UnixNano()},
+		{"_id": "2", "time": t1.UnixNano()},
+		{"_id": "3", "time": int64(42)},
+		{"_id": "4"},
+	})
+}
+
+func (s *upgradesSuite) TestAddActionApplications(c *gc.C) {
+	coll, closer := s.state.getRawCollection(actionsC)
+	defer closer()
+
+	uuid := s.state.ModelUUID()
+	err := coll.Insert(
+		bson.M{"_id": uuid + ":1", "model-uuid": uuid, "receiver": "mysql/0"},
+		bson.M{"_id": uuid + ":2", "model-uuid": uuid, "receiver": "0"},
+		bson.M{"_id": uuid + ":3", "model-uuid": uuid, "receiver": "wordpress/1", "application": "wordpress"},
+	)
+	c.Assert(err, jc.ErrorIsNil)
+
+	expected := []bson.M{{
+		"_id":         uuid + ":1",
+		"model-uuid":  uuid,
+		"receiver":    "mysql/0",
+		"application": "mysql",
+	}, {
+		"_id":        uuid + ":2",
+		"model-uuid": uuid,
+		"receiver":   "0",
+	}, {
+		"_id":         uuid + ":3",
+		"model-uuid":  uuid,
+		"receiver":    "wordpress/1",
+		"application": "wordpress",
+	}}
+	s.assertUpgradedData(c, AddActionApplications,
+		expectUpgradedData{coll, expected},
+	)
+}
+
+func (s *upgradesSuite) TestAddActionApplicationsBatches(c *gc.C) {
+	coll, closer := s.state.getRawCollection(actionsC)
+	defer closer()
+
+	uuid := s.state.ModelUUID()
+	count := 2*addActionApplicationsBatchSize + 1
+	var docs []interface{}
+	for i := 0; i < count; i++ {
+		id := fmt.Sprintf("%s:%d", uuid, i)
+		docs = append(docs, bson.M{"_id": id, "model-uuid": uuid, "receiver": "mysql/0"})
+	}
+	err := coll.Insert(docs...)
+	c.Assert(err, jc.ErrorIsNil)
+
+	err = AddActionApplications(s.state)
+	c.Assert(err, jc.ErrorIsNil)
+	n, err := coll.Find(bson.D{{"application", "mysql"}}).Count()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(n, gc.Equals, count)
+}
+
 func (s *upgradesSuite) TestAddMigrationAttempt(c *gc.C) {
 	coll, closer := s.state.getRawCollection(migrationsC)
 	defer closer()
@@ -89,6 +89,7 @@ type PresenceWatcher interface {
 
 	// Presence-reading and -watching.
 	Alive(key string) (bool, error)
+	AliveKeys() ([]string, error)
 	Watch(key string, ch chan<- presence.Change)
 	Unwatch(key string, ch chan<- presence.Change)
 }
@@ -0,0 +1,205 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import (
+	"sort"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/utils/set"
+)
+
+const (
+	// DefaultHistoryQueryLimit is the number of entries a status
+	// history query returns when it does not limit them.
+	DefaultHistoryQueryLimit = 1000
+
+	// MaxHistoryQueryLimit is the most entries a status history
+	// query returns at once; more are read a page at a time.
+	MaxHistoryQueryLimit = 10000
+)
+
+// HistoryQuery holds the arguments for a status history query that
+// spans the units and machines of a model.
+type HistoryQuery struct {
+	// Application restricts the query to the units of the named
+	// application. If empty, all units and machines in the model
+	// are queried.
+	Application string
+	// FromDate, if set, excludes entries older than it, except that
+	// each entity's latest entry from before it is returned first, to
+	// give the entity's status at FromDate.
+	FromDate *time.Time
+	// ToDate, if set, excludes entries newer than it.
+	ToDate *time.Time
+	// Exclude indicates the status messages that should be excluded
+	// from the returned result.
+	Exclude set.Strings
+	// After, if set, is the Id of the last entry of the previous page
+	// of results; only the entries that follow it are returned.
+	After string
+	// Limit is the maximum number of entries returned, not counting
+	// those from before FromDate. If it is zero, at most
+	// DefaultHistoryQueryLimit are returned; it may not be more than
+	// MaxHistoryQueryLimit.
+	Limit int
+}
+
+// Validate checks that the query is consistent.
+func (q *HistoryQuery) Validate() error {
+	if q.FromDate != nil && q.ToDate != nil && q.ToDate.Before(*q.FromDate) {
+		return errors.NotValidf("ToDate before FromDate")
+	}
+	if q.Limit < 0 {
+		return errors.NotValidf("negative limit %d", q.Limit)
+	}
+	if q.Limit > MaxHistoryQueryLimit {
+		return errors.NotValidf("limit %d greater than %d", q.Limit, MaxHistoryQueryLimit)
+	}
+	return nil
+}
+
+// HistoryEntry is a status history entry for a particular entity.
+type HistoryEntry struct {
+	// Id identifies the entry, for use as HistoryQuery.After.
+	Id string
+	// Entity is the name of the unit or id of the machine.
+	Entity  string
+	Kind    HistoryKind
+	Status  Status
+	Message string
+	Since   time.Time
+}
+
+// HistoryAggregate summarises the status history of a single entity
+// and kind.
+type HistoryAggregate struct {
+	Entity string
+	Kind   HistoryKind
+	// Durations holds the time spent in each status.
+	Durations map[Status]time.Duration
+	// Transitions counts the changes from one status to another.
+	Transitions int
+	// ErrorTransitions counts the changes into the error status,
+	// including an initial error status.
+	ErrorTransitions int
+}
+
+type historyKey struct {
+	entity string
+	kind   HistoryKind
+}
+
+// AggregateHistory summarises the given entries for each entity and
+// kind. Each entry's status is held until the next entry's time, and
+// the last status is held until end; no time before start is counted.
+// The results are ordered by entity and kind.
+func AggregateHistory(entries []HistoryEntry, start, end time.Time) []HistoryAggregate {
+	sorted := make([]HistoryEntry, len(entries))
+	copy(sorted, entries)
+	sort.Stable(historyEntriesByTime(sorted))
+	aggregator := NewHistoryAggregator(start)
+	for _, entry := range sorted {
+		aggregator.Add(entry)
+	}
+	return aggregator.Results(end)
+}
+
+// HistoryAggregator summarises status history entries as they are
+// added, holding only the latest entry for each entity and kind, so
+// that a long history can be summarised a page at a time.
+type HistoryAggregator struct {
+	start   time.Time
+	last    map[historyKey]HistoryEntry
+	results map[historyKey]*HistoryAggregate
+}
+
+// NewHistoryAggregator returns a HistoryAggregator that counts no time
+// before start.
+func NewHistoryAggregator(start time.Time) *HistoryAggregator {
+	return &HistoryAggregator{
+		start:   start,
+		last:    make(map[historyKey]HistoryEntry),
+		results: make(map[historyKey]*HistoryAggregate),
+	}
+}
+
+// Add adds an entry to the summary. Each entity's entries must be added
+// oldest first.
+func (a *HistoryAggregator) Add(entry HistoryEntry) {
+	key := historyKey{entry.Entity, entry.Kind}
+	result, ok := a.results[key]
+	if !ok {
+		result = &HistoryAggregate{
+			Entity:    key.entity,
+			Kind:      key.kind,
+			Durations: make(map[Status]time.Duration),
+		}
+		a.results[key] = result
+	}
+	last, seen := a.last[key]
+	if seen {
+		a.hold(result, last, entry.Since)
+	}
+	changed := !seen || last.Status != entry.Status
+	if seen && changed {
+		result.Transitions++
+	}
+	if changed && entry.Status == Error {
+		result.ErrorTransitions++
+	}
+	a.last[key] = entry
+}
+
+// Results returns the summary of the entries added, holding each
+// entity's last status until end. The results are ordered by entity
+// and kind.
+func (a *HistoryAggregator) Results(end time.Time) []HistoryAggregate {
+	keys := make([]historyKey, 0, len(a.results))
+	for key := range a.results {
+		keys = append(keys, key)
+	}
+	sort.Sort(historyKeys(keys))
+	results := make([]HistoryAggregate, len(keys))
+	for i, key := range keys {
+		result := *a.results[key]
+		result.Durations = make(map[Status]time.Duration)
+		for s, d := range a.results[key].Durations {
+			result.Durations[s] = d
+		}
+		a.hold(&result, a.last[key], end)
+		results[i] = result
+	}
+	return results
+}
+
+// hold adds the time from the entry until the given time, but not
+// before the aggregator's start, to the entry's status.
+func (a *HistoryAggregator) hold(result *HistoryAggregate, entry HistoryEntry, until time.Time) {
+	from := entry.Since
+	if from.Before(a.start) {
+		from = a.start
+	}
+	if until.After(from) {
+		result.Durations[entry.Status] += until.Sub(from)
+	}
+}
+
+type historyKeys []historyKey
+
+func (k historyKeys) Len() int      { return len(k) }
+func (k historyKeys) Swap(i, j int) { k[i], k[j] = k[j], k[i] }
+func (k historyKeys) Less(i, j int) bool {
+	if k[i].entity != k[j].entity {
+		return k[i].entity < k[j].entity
+	}
+	return k[i].kind < k[j].kind
+}
+
+type historyEntriesByTime []HistoryEntry
+
+func (h historyEntriesByTime) Len() int           { return len(h) }
+func (h historyEntriesByTime) Swap(i, j int)      { h[i], h[j] = h[j], h[i] }
+func (h historyEntriesByTime) Less(i, j int) bool { return h[i].Since.Before(h[j].Since) }
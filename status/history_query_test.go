@@ -0,0 +1,120 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status_test
+
+import (
+	"time"
+
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/status"
+)
+
+type historyQuerySuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&historyQuerySuite{})
+
+func (s *historyQuerySuite) TestValidate(c *gc.C) {
+	from := time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	query := status.HistoryQuery{FromDate: &from, ToDate: &to}
+	c.Assert(query.Validate(), jc.ErrorIsNil)
+	query = status.HistoryQuery{FromDate: &to, ToDate: &from}
+	c.Assert(query.Validate(), gc.ErrorMatches, "ToDate before FromDate not valid")
+	query = status.HistoryQuery{Limit: -1}
+	c.Assert(query.Validate(), gc.ErrorMatches, "negative limit -1 not valid")
+	query = status.HistoryQuery{Limit: status.MaxHistoryQueryLimit + 1}
+	c.Assert(query.Validate(), gc.ErrorMatches, "limit 10001 greater than 10000 not valid")
+}
+
+func (s *historyQuerySuite) TestAggregateHistory(c *gc.C) {
+	start := time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC)
+	at := func(minutes int) time.Time {
+		return start.Add(time.Duration(minutes) * time.Minute)
+	}
+	entries := []status.HistoryEntry{
+		{Entity: "mysql/0", Kind: status.KindWorkload, Status: status.Maintenance, Since: at(0)},
+		{Entity: "mysql/0", Kind: status.KindUnitAgent, Status: status.Executing, Since: at(0)},
+		{Entity: "mysql/0", Kind: status.KindWorkload, Status: status.Error, Since: at(10)},
+		{Entity: "mysql/0", Kind: status.KindUnitAgent, Status: status.Idle, Since: at(12)},
+		{Entity: "mysql/0", Kind: status.KindWorkload, Status: status.Maintenance, Since: at(15)},
+		// Entries need not be ordered.
+		{Entity: "mysql/0", Kind: status.KindWorkload, Status: status.Active, Since: at(30)},
+		{Entity: "mysql/0", Kind: status.KindWorkload, Status: status.Maintenance, Since: at(20)},
+		{Entity: "0", Kind: status.KindMachine, Status: status.Error, Since: at(5)},
+		{Entity: "0", Kind: status.KindMachine, Status: status.Error, Since: at(25)},
+	}
+	results := status.AggregateHistory(entries, time.Time{}, at(60))
+	c.Assert(results, jc.DeepEquals, []status.HistoryAggregate{{
+		Entity: "0",
+		Kind:   status.KindMachine,
+		Durations: map[status.Status]time.Duration{
+			status.Error: 55 * time.Minute,
+		},
+		ErrorTransitions: 1,
+	}, {
+		Entity: "mysql/0",
+		Kind:   status.KindUnitAgent,
+		Durations: map[status.Status]time.Duration{
+			status.Executing: 12 * time.Minute,
+			status.Idle:      48 * time.Minute,
+		},
+		Transitions: 1,
+	}, {
+		Entity: "mysql/0",
+		Kind:   status.KindWorkload,
+		Durations: map[status.Status]time.Duration{
+			status.Maintenance: 25 * time.Minute,
+			status.Error:       5 * time.Minute,
+			status.Active:      30 * time.Minute,
+		},
+		Transitions:      3,
+		ErrorTransitions: 1,
+	}})
+}
+
+func (s *historyQuerySuite) TestAggregateHistoryEndBeforeLastEntry(c *gc.C) {
+	start := time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC)
+	results := status.AggregateHistory([]status.HistoryEntry{
+		{Entity: "mysql/0", Kind: status.KindWorkload, Status: status.Active, Since: start},
+	}, time.Time{}, start.Add(-time.Minute))
+	c.Assert(results, jc.DeepEquals, []status.HistoryAggregate{{
+		Entity:    "mysql/0",
+		Kind:      status.KindWorkload,
+		Durations: map[status.Status]time.Duration{},
+	}})
+}
+
+func (s *historyQuerySuite) TestAggregateHistoryStart(c *gc.C) {
+	// An entry from before the start gives the status at the start.
+	start := time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC)
+	results := status.AggregateHistory([]status.HistoryEntry{
+		{Entity: "mysql/0", Kind: status.KindWorkload, Status: status.Error, Since: start.Add(-time.Hour)},
+		{Entity: "mysql/0", Kind: status.KindWorkload, Status: status.Active, Since: start.Add(10 * time.Minute)},
+	}, start, start.Add(time.Hour))
+	c.Assert(results, jc.DeepEquals, []status.HistoryAggregate{{
+		Entity: "mysql/0",
+		Kind:   status.KindWorkload,
+		Durations: map[status.Status]time.Duration{
+			status.Error:  10 * time.Minute,
+			status.Active: 50 * time.Minute,
+		},
+		Transitions:      1,
+		ErrorTransitions: 1,
+	}})
+}
+
+func (s *historyQuerySuite) TestHistoryAggregatorResultsRepeatable(c *gc.C) {
+	start := time.Date(2017, 5, 1, 0, 0, 0, 0, time.UTC)
+	aggregator := status.NewHistoryAggregator(start)
+	aggregator.Add(status.HistoryEntry{Entity: "0", Kind: status.KindMachine, Status: status.Started, Since: start})
+	first := aggregator.Results(start.Add(time.Hour))
+	second := aggregator.Results(start.Add(time.Hour))
+	c.Assert(second, jc.DeepEquals, first)
+	c.Assert(first[0].Durations[status.Started], gc.Equals, time.Hour)
+}
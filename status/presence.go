@@ -0,0 +1,93 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status
+
+import "fmt"
+
+const (
+	// MessageAgentNotCommunicating is the message of the status of an
+	// agent that ought to be alive, but is not.
+	MessageAgentNotCommunicating = "agent is not communicating with the server"
+
+	// MessageRunningInstallHook is the message of the status of a unit
+	// agent running the install hook. It matches the message the
+	// uniter sets when running a hook.
+	MessageRunningInstallHook = "running install hook"
+)
+
+// MachineStatusWithPresence returns the status of a machine agent as
+// users see it, given the status recorded for it. The agent of a
+// machine that is not dead, and that has been provisioned and not
+// stopped, is reported down if it is not alive. agentAlive is only
+// called when its answer matters.
+func MachineStatusWithPresence(machine StatusInfo, dead bool, agentAlive func() bool) StatusInfo {
+	switch machine.Status {
+	case Pending, Stopped:
+		// The machine is still being provisioned, or is finished
+		// with; there's no point in enquiring about the agent
+		// liveness.
+		return machine
+	}
+	if !dead && !agentAlive() {
+		machine.Status = Down
+		machine.Message = MessageAgentNotCommunicating
+	}
+	return machine
+}
+
+// UnitStatusWithPresence returns the agent and workload statuses of
+// the named unit as users see them, given the statuses recorded for
+// it. The agent of a unit that is not dead, and that has finished
+// installing, is reported lost if it is not alive. agentAlive is only
+// called when its answer matters.
+func UnitStatusWithPresence(unitName string, agent, workload StatusInfo, dead bool, agentAlive func() bool) (StatusInfo, StatusInfo) {
+	if !unitCanBeLost(agent, workload) {
+		// The unit is allocating or installing - there's no point in
+		// enquiring about the agent liveness.
+		return agent, workload
+	}
+	if dead || agentAlive() {
+		return agent, workload
+	}
+	// If the unit is in error, it would be bad to throw away the error
+	// information as when the agent reconnects, that error information
+	// would then be lost.
+	if workload.Status != Error {
+		workload.Status = Unknown
+		workload.Message = fmt.Sprintf("agent lost, see 'juju show-status-log %s'", unitName)
+	}
+	agent.Status = Lost
+	agent.Message = MessageAgentNotCommunicating
+	return agent, workload
+}
+
+func unitCanBeLost(agent, workload StatusInfo) bool {
+	switch agent.Status {
+	case Allocating:
+		return false
+	case Executing:
+		return agent.Message != MessageRunningInstallHook
+	}
+
+	// TODO(fwereade/wallyworld): we should have an explicit place in the model
+	// to tell us when we've hit this point, instead of piggybacking on top of
+	// status and/or status history.
+
+	return isWorkloadInstalled(workload)
+}
+
+func isWorkloadInstalled(workload StatusInfo) bool {
+	switch workload.Status {
+	case Maintenance:
+		return workload.Message != MessageInstallingCharm
+	case Waiting:
+		switch workload.Message {
+		case MessageWaitForMachine:
+		case MessageInstallingAgent:
+		case MessageInitializingAgent:
+			return false
+		}
+	}
+	return true
+}
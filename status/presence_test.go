@@ -0,0 +1,91 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package status_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/status"
+)
+
+type presenceSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&presenceSuite{})
+
+func alive(alive bool) func() bool {
+	return func() bool { return alive }
+}
+
+func notAsked(c *gc.C) func() bool {
+	return func() bool {
+		c.Fatalf("agent presence asked about unexpectedly")
+		return false
+	}
+}
+
+func (*presenceSuite) TestMachineStatusWithPresence(c *gc.C) {
+	started := status.StatusInfo{Status: status.Started, Message: "ok"}
+	down := status.StatusInfo{Status: status.Down, Message: status.MessageAgentNotCommunicating}
+
+	c.Check(status.MachineStatusWithPresence(started, false, alive(true)), jc.DeepEquals, started)
+	c.Check(status.MachineStatusWithPresence(started, false, alive(false)), jc.DeepEquals, down)
+	c.Check(status.MachineStatusWithPresence(started, true, alive(false)), jc.DeepEquals, started)
+
+	for _, s := range []status.Status{status.Pending, status.Stopped} {
+		info := status.StatusInfo{Status: s}
+		c.Check(status.MachineStatusWithPresence(info, false, notAsked(c)), jc.DeepEquals, info)
+	}
+}
+
+func (*presenceSuite) TestUnitStatusWithPresence(c *gc.C) {
+	idle := status.StatusInfo{Status: status.Idle}
+	active := status.StatusInfo{Status: status.Active, Message: "ready"}
+
+	agent, workload := status.UnitStatusWithPresence("foo/0", idle, active, false, alive(true))
+	c.Check(agent, jc.DeepEquals, idle)
+	c.Check(workload, jc.DeepEquals, active)
+
+	agent, workload = status.UnitStatusWithPresence("foo/0", idle, active, false, alive(false))
+	c.Check(agent, jc.DeepEquals, status.StatusInfo{
+		Status:  status.Lost,
+		Message: status.MessageAgentNotCommunicating,
+	})
+	c.Check(workload, jc.DeepEquals, status.StatusInfo{
+		Status:  status.Unknown,
+		Message: "agent lost, see 'juju show-status-log foo/0'",
+	})
+
+	agent, workload = status.UnitStatusWithPresence("foo/0", idle, active, true, alive(false))
+	c.Check(agent, jc.DeepEquals, idle)
+	c.Check(workload, jc.DeepEquals, active)
+}
+
+func (*presenceSuite) TestUnitStatusWithPresenceKeepsError(c *gc.C) {
+	failed := status.StatusInfo{Status: status.Error, Message: "hook failed"}
+	agent, workload := status.UnitStatusWithPresence("foo/0", status.StatusInfo{Status: status.Idle}, failed, false, alive(false))
+	c.Check(agent.Status, gc.Equals, status.Lost)
+	c.Check(workload, jc.DeepEquals, failed)
+}
+
+func (*presenceSuite) TestUnitStatusWithPresenceInstalling(c *gc.C) {
+	for i, test := range []struct {
+		agent, workload status.StatusInfo
+	}{{
+		agent: status.StatusInfo{Status: status.Allocating},
+	}, {
+		agent: status.StatusInfo{Status: status.Executing, Message: status.MessageRunningInstallHook},
+	}, {
+		agent:    status.StatusInfo{Status: status.Idle},
+		workload: status.StatusInfo{Status: status.Maintenance, Message: status.MessageInstallingCharm},
+	}} {
+		c.Logf("test %d", i)
+		agent, workload := status.UnitStatusWithPresence("foo/0", test.agent, test.workload, false, notAsked(c))
+		c.Check(agent, jc.DeepEquals, test.agent)
+		c.Check(workload, jc.DeepEquals, test.workload)
+	}
+}
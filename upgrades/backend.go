@@ -26,6 +26,8 @@ type StateBackend interface {
 	UpgradeNoProxyDefaults() error
 	AddNonDetachableStorageMachineId() error
 	RemoveNilValueApplicationSettings() error
+	ConvertAuditLogToCapped() error
+	AddActionApplications() error
 }
 
 // Model is an interface providing access to the details of a model within the
@@ -92,6 +94,14 @@ func (s stateBackend) RemoveNilValueApplicationSettings() error {
 	return state.RemoveNilValueApplicationSettings(s.st)
 }
 
+func (s stateBackend) ConvertAuditLogToCapped() error {
+	return state.ConvertAuditLogToCapped(s.st)
+}
+
+func (s stateBackend) AddActionApplications() error {
+	return state.AddActionApplications(s.st)
+}
+
 type modelShim struct {
 	st *state.State
 	m  *state.Model
@@ -20,5 +20,19 @@ func stateStepsFor22() []Step {
 				return context.State().RemoveNilValueApplicationSettings()
 			},
 		},
+		&upgradeStep{
+			description: "convert the audit log to a capped collection",
+			targets:     []Target{DatabaseMaster},
+			run: func(context Context) error {
+				return context.State().ConvertAuditLogToCapped()
+			},
+		},
+		&upgradeStep{
+			description: "record the application of actions run on units",
+			targets:     []Target{DatabaseMaster},
+			run: func(context Context) error {
+				return context.State().AddActionApplications()
+			},
+		},
 	}
 }
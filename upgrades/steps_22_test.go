@@ -25,3 +25,15 @@ func (s *steps22Suite) TestAddNonDetachableStorageMachineId(c *gc.C) {
 	// Logic for step itself is tested in state package.
 	c.Assert(step.Targets(), jc.DeepEquals, []upgrades.Target{upgrades.DatabaseMaster})
 }
+
+func (s *steps22Suite) TestConvertAuditLogToCapped(c *gc.C) {
+	step := findStateStep(c, v220, "convert the audit log to a capped collection")
+	// Logic for step itself is tested in state package.
+	c.Assert(step.Targets(), jc.DeepEquals, []upgrades.Target{upgrades.DatabaseMaster})
+}
+
+func (s *steps22Suite) TestAddActionApplications(c *gc.C) {
+	step := findStateStep(c, v220, "record the application of actions run on units")
+	// Logic for step itself is tested in state package.
+	c.Assert(step.Targets(), jc.DeepEquals, []upgrades.Target{upgrades.DatabaseMaster})
+}
@@ -0,0 +1,53 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionpruner
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/api/actionpruner"
+	"github.com/juju/juju/api/base"
+	jworker "github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/dependency"
+)
+
+// ManifoldConfig describes the resources and configuration on which the
+// actionpruner worker depends.
+type ManifoldConfig struct {
+	APICallerName  string
+	MaxHistoryTime time.Duration
+	MaxHistoryMB   uint
+	PruneInterval  time.Duration
+	NewTimer       jworker.NewTimerFunc
+}
+
+// Manifold returns a Manifold that encapsulates the actionpruner worker.
+func Manifold(config ManifoldConfig) dependency.Manifold {
+	return dependency.Manifold{
+		Inputs: []string{config.APICallerName},
+		Start: func(context dependency.Context) (worker.Worker, error) {
+			var apiCaller base.APICaller
+			if err := context.Get(config.APICallerName, &apiCaller); err != nil {
+				return nil, errors.Trace(err)
+			}
+
+			facade := actionpruner.NewFacade(apiCaller)
+			prunerConfig := Config{
+				Facade:         facade,
+				MaxHistoryTime: config.MaxHistoryTime,
+				MaxHistoryMB:   config.MaxHistoryMB,
+				PruneInterval:  config.PruneInterval,
+				NewTimer:       config.NewTimer,
+			}
+			w, err := New(prunerConfig)
+			if err != nil {
+				return nil, errors.Trace(err)
+			}
+			return w, nil
+		},
+	}
+}
@@ -0,0 +1,75 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionpruner
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/environs/config"
+	jworker "github.com/juju/juju/worker"
+)
+
+// Facade represents an API that implements action pruning.
+type Facade interface {
+	Prune(time.Duration, int) error
+	ModelConfig() (*config.Config, error)
+}
+
+// Config holds all necessary attributes to start a pruner worker.
+// MaxHistoryTime and MaxHistoryMB are the defaults used when the
+// model's config does not set actions-max-age or actions-max-size.
+type Config struct {
+	Facade         Facade
+	MaxHistoryTime time.Duration
+	MaxHistoryMB   uint
+	PruneInterval  time.Duration
+	NewTimer       jworker.NewTimerFunc
+}
+
+// Validate will err unless basic requirements for a valid
+// config are met.
+func (c *Config) Validate() error {
+	if c.Facade == nil {
+		return errors.New("missing Facade")
+	}
+	if c.NewTimer == nil {
+		return errors.New("missing Timer")
+	}
+	if c.MaxHistoryMB <= 0 && c.MaxHistoryTime <= 0 {
+		return errors.New("missing prune criteria, no size or date limit provided")
+	}
+	return nil
+}
+
+// New returns a worker.Worker that prunes the model's finished
+// actions.
+func New(conf Config) (worker.Worker, error) {
+	if err := conf.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	doPruning := func(stop <-chan struct{}) error {
+		modelConfig, err := conf.Facade.ModelConfig()
+		if err != nil {
+			return errors.Annotate(err, "cannot read model config")
+		}
+		maxHistoryTime := conf.MaxHistoryTime
+		if maxAge, ok := modelConfig.ActionsMaxAge(); ok {
+			maxHistoryTime = maxAge
+		}
+		maxHistoryMB := int(conf.MaxHistoryMB)
+		if maxSize, ok := modelConfig.ActionsMaxSizeMB(); ok {
+			maxHistoryMB = maxSize
+		}
+		err = conf.Facade.Prune(maxHistoryTime, maxHistoryMB)
+		if err != nil {
+			return errors.Trace(err)
+		}
+		return nil
+	}
+
+	return jworker.NewPeriodicWorker(doPruning, conf.PruneInterval, conf.NewTimer), nil
+}
@@ -0,0 +1,182 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package actionpruner_test
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/environs/config"
+	coretesting "github.com/juju/juju/testing"
+	jworker "github.com/juju/juju/worker"
+	"github.com/juju/juju/worker/actionpruner"
+)
+
+type actionPrunerSuite struct {
+	coretesting.BaseSuite
+}
+
+var _ = gc.Suite(&actionPrunerSuite{})
+
+func (s *actionPrunerSuite) startPruner(c *gc.C, facade *fakeFacade, timer *mockTimer) {
+	conf := actionpruner.Config{
+		Facade:         facade,
+		MaxHistoryTime: 336 * time.Hour,
+		MaxHistoryMB:   5120,
+		PruneInterval:  coretesting.ShortWait,
+		NewTimer: func(d time.Duration) jworker.PeriodicTimer {
+			// The pruner runs once before waiting.
+			c.Assert(d, gc.Equals, time.Duration(0))
+			return timer
+		},
+	}
+	pruner, err := actionpruner.New(conf)
+	c.Assert(err, jc.ErrorIsNil)
+	s.AddCleanup(func(*gc.C) {
+		c.Assert(worker.Stop(pruner), jc.ErrorIsNil)
+	})
+}
+
+func (s *actionPrunerSuite) TestValidate(c *gc.C) {
+	newTimer := func(time.Duration) jworker.PeriodicTimer { return nil }
+	for i, test := range []struct {
+		config actionpruner.Config
+		expect string
+	}{{
+		config: actionpruner.Config{NewTimer: newTimer, MaxHistoryMB: 1},
+		expect: "missing Facade",
+	}, {
+		config: actionpruner.Config{Facade: newFakeFacade(c, nil), MaxHistoryMB: 1},
+		expect: "missing Timer",
+	}, {
+		config: actionpruner.Config{Facade: newFakeFacade(c, nil), NewTimer: newTimer},
+		expect: "missing prune criteria, no size or date limit provided",
+	}} {
+		c.Logf("test %d", i)
+		_, err := actionpruner.New(test.config)
+		c.Check(err, gc.ErrorMatches, test.expect)
+	}
+}
+
+func (s *actionPrunerSuite) TestWorkerCallsPruneWithDefaults(c *gc.C) {
+	timer := newMockTimer()
+	facade := newFakeFacade(c, nil)
+	s.startPruner(c, facade, timer)
+
+	err := timer.fire()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(facade.nextPrune(c), jc.DeepEquals, pruneArgs{336 * time.Hour, 5120})
+
+	// Reset will have been called with the actual PruneInterval.
+	select {
+	case period := <-timer.period:
+		c.Assert(period, gc.Equals, coretesting.ShortWait)
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for period reset by pruner")
+	}
+}
+
+func (s *actionPrunerSuite) TestWorkerUsesModelConfig(c *gc.C) {
+	timer := newMockTimer()
+	facade := newFakeFacade(c, coretesting.Attrs{
+		"actions-max-age":  "24h",
+		"actions-max-size": "100M",
+	})
+	s.startPruner(c, facade, timer)
+
+	err := timer.fire()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(facade.nextPrune(c), jc.DeepEquals, pruneArgs{24 * time.Hour, 100})
+}
+
+func (s *actionPrunerSuite) TestWorkerWontCallPruneBeforeFiringTimer(c *gc.C) {
+	facade := newFakeFacade(c, nil)
+	s.startPruner(c, facade, newMockTimer())
+
+	select {
+	case <-facade.prunes:
+		c.Fatal("called before firing timer.")
+	case <-time.After(coretesting.ShortWait):
+	}
+}
+
+type mockTimer struct {
+	period chan time.Duration
+	c      chan time.Time
+}
+
+func newMockTimer() *mockTimer {
+	return &mockTimer{
+		period: make(chan time.Duration, 1),
+		c:      make(chan time.Time),
+	}
+}
+
+func (t *mockTimer) Reset(d time.Duration) bool {
+	select {
+	case t.period <- d:
+	case <-time.After(coretesting.LongWait):
+		panic("timed out waiting for timer to reset")
+	}
+	return true
+}
+
+func (t *mockTimer) CountDown() <-chan time.Time {
+	return t.c
+}
+
+func (t *mockTimer) fire() error {
+	select {
+	case t.c <- time.Time{}:
+	case <-time.After(coretesting.LongWait):
+		return errors.New("timed out waiting for pruner to run")
+	}
+	return nil
+}
+
+type pruneArgs struct {
+	maxHistoryTime time.Duration
+	maxHistoryMB   int
+}
+
+type fakeFacade struct {
+	config *config.Config
+	prunes chan pruneArgs
+}
+
+func newFakeFacade(c *gc.C, attrs coretesting.Attrs) *fakeFacade {
+	return &fakeFacade{
+		config: coretesting.CustomModelConfig(c, attrs),
+		prunes: make(chan pruneArgs, 1),
+	}
+}
+
+func (f *fakeFacade) nextPrune(c *gc.C) pruneArgs {
+	select {
+	case args := <-f.prunes:
+		return args
+	case <-time.After(coretesting.LongWait):
+		c.Fatal("timed out waiting for facade call Prune")
+	}
+	panic("unreachable")
+}
+
+// ModelConfig implements Facade.
+func (f *fakeFacade) ModelConfig() (*config.Config, error) {
+	return f.config, nil
+}
+
+// Prune implements Facade.
+func (f *fakeFacade) Prune(maxHistoryTime time.Duration, maxHistoryMB int) error {
+	select {
+	case f.prunes <- pruneArgs{maxHistoryTime, maxHistoryMB}:
+	case <-time.After(coretesting.LongWait):
+		return errors.New("timed out waiting for facade call Prune to run")
+	}
+	return nil
+}
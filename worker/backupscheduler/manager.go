@@ -0,0 +1,97 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"github.com/juju/errors"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/worker/catacomb"
+)
+
+// ConfigSource supplies the configuration of scheduled backups, which
+// may change while the controller is running.
+type ConfigSource interface {
+	// BackupConfig returns the current configuration, and whether
+	// backups are scheduled at all.
+	BackupConfig() (Config, bool, error)
+
+	// WatchBackupConfig returns a watcher that notifies when the
+	// configuration may have changed.
+	WatchBackupConfig() state.NotifyWatcher
+}
+
+// NewManager returns a worker which runs a backup scheduler with the
+// configuration from the source, and restarts it whenever that
+// configuration changes, so that changes to the schedule, the store
+// and the retention count take effect without restarting the agent.
+func NewManager(source ConfigSource) (worker.Worker, error) {
+	if source == nil {
+		return nil, errors.NotValidf("nil ConfigSource")
+	}
+	m := &manager{source: source}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &m.catacomb,
+		Work: m.loop,
+	})
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return m, nil
+}
+
+type manager struct {
+	catacomb catacomb.Catacomb
+	source   ConfigSource
+}
+
+// Kill is part of the worker.Worker interface.
+func (m *manager) Kill() {
+	m.catacomb.Kill(nil)
+}
+
+// Wait is part of the worker.Worker interface.
+func (m *manager) Wait() error {
+	return m.catacomb.Wait()
+}
+
+func (m *manager) loop() error {
+	configWatcher := m.source.WatchBackupConfig()
+	if err := m.catacomb.Add(configWatcher); err != nil {
+		return errors.Trace(err)
+	}
+	var current worker.Worker
+	for {
+		select {
+		case <-m.catacomb.Dying():
+			return m.catacomb.ErrDying()
+		case _, ok := <-configWatcher.Changes():
+			if !ok {
+				return errors.New("backup config watch closed")
+			}
+		}
+		if current != nil {
+			if err := worker.Stop(current); err != nil {
+				return errors.Annotate(err, "stopping backup scheduler")
+			}
+			current = nil
+		}
+		config, scheduled, err := m.source.BackupConfig()
+		if err != nil {
+			return errors.Annotate(err, "cannot read backup config")
+		}
+		if !scheduled {
+			logger.Debugf("no backups scheduled")
+			continue
+		}
+		current, err = NewWorker(config)
+		if err != nil {
+			return errors.Annotate(err, "cannot start backup scheduler")
+		}
+		if err := m.catacomb.Add(current); err != nil {
+			return errors.Trace(err)
+		}
+	}
+}
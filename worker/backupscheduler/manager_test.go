@@ -0,0 +1,149 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/schedule"
+	"github.com/juju/juju/state"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/backupscheduler"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type managerSuite struct {
+	testing.IsolationSuite
+	clock   *testing.Clock
+	backups *fakeBackups
+	watcher workertest.NotAWatcher
+	source  *fakeConfigSource
+}
+
+var _ = gc.Suite(&managerSuite{})
+
+func (s *managerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Date(2017, time.March, 1, 10, 15, 0, 0, time.UTC))
+	s.backups = &fakeBackups{clock: s.clock}
+	s.watcher = workertest.NewFakeWatcher(1, 1)
+	s.source = &fakeConfigSource{watcher: s.watcher}
+	s.setSchedule(c, "@hourly")
+}
+
+func (s *managerSuite) setSchedule(c *gc.C, spec string) {
+	config := backupscheduler.Config{
+		Backups:        s.backups,
+		RetentionCount: 2,
+		Clock:          s.clock,
+	}
+	if spec != "" {
+		sched, err := schedule.Parse(spec)
+		c.Assert(err, jc.ErrorIsNil)
+		config.Schedule = sched
+	}
+	s.source.set(config, nil)
+}
+
+func (s *managerSuite) TestNilSource(c *gc.C) {
+	_, err := backupscheduler.NewManager(nil)
+	c.Assert(err, gc.ErrorMatches, "nil ConfigSource not valid")
+}
+
+func (s *managerSuite) TestRestartsOnChange(c *gc.C) {
+	w, err := backupscheduler.NewManager(s.source)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.advance(c, 45*time.Minute, 1)
+	c.Assert(s.backups.ids(), jc.DeepEquals, []string{"backup-0"})
+
+	// The stopped scheduler's timer remains, alongside the new one.
+	s.setSchedule(c, "@daily")
+	s.watcher.Ping()
+	s.advance(c, time.Hour, 2)
+	c.Assert(s.backups.ids(), jc.DeepEquals, []string{"backup-0"})
+}
+
+func (s *managerSuite) TestStartsWhenScheduled(c *gc.C) {
+	s.setSchedule(c, "")
+	w, err := backupscheduler.NewManager(s.source)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.source.waitForRead(c, 1)
+	workertest.CheckAlive(c, w)
+
+	s.setSchedule(c, "@hourly")
+	s.watcher.Ping()
+	s.advance(c, 45*time.Minute, 1)
+	c.Assert(s.backups.ids(), jc.DeepEquals, []string{"backup-0"})
+}
+
+func (s *managerSuite) TestConfigError(c *gc.C) {
+	s.source.set(backupscheduler.Config{}, errors.New("boom"))
+	w, err := backupscheduler.NewManager(s.source)
+	c.Assert(err, jc.ErrorIsNil)
+	err = workertest.CheckKilled(c, w)
+	c.Assert(err, gc.ErrorMatches, "cannot read backup config: boom")
+}
+
+// advance moves the clock on by d once there are the given number of
+// timers, and then waits for the scheduler to finish any backup and
+// wait again.
+func (s *managerSuite) advance(c *gc.C, d time.Duration, timers int) {
+	err := s.clock.WaitAdvance(d, coretesting.LongWait, timers)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.clock.WaitAdvance(0, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// fakeConfigSource is a backupscheduler.ConfigSource whose config is
+// set by the test.
+type fakeConfigSource struct {
+	mu      sync.Mutex
+	watcher workertest.NotAWatcher
+	config  backupscheduler.Config
+	err     error
+	reads   int
+}
+
+func (f *fakeConfigSource) set(config backupscheduler.Config, err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.config = config
+	f.err = err
+}
+
+func (f *fakeConfigSource) waitForRead(c *gc.C, n int) {
+	for a := coretesting.LongAttempt.Start(); a.Next(); {
+		f.mu.Lock()
+		reads := f.reads
+		f.mu.Unlock()
+		if reads >= n {
+			return
+		}
+	}
+	c.Fatalf("config not read")
+}
+
+// BackupConfig is part of the backupscheduler.ConfigSource interface.
+func (f *fakeConfigSource) BackupConfig() (backupscheduler.Config, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.reads++
+	return f.config, f.config.Schedule != nil, f.err
+}
+
+// WatchBackupConfig is part of the backupscheduler.ConfigSource
+// interface.
+func (f *fakeConfigSource) WatchBackupConfig() state.NotifyWatcher {
+	return f.watcher
+}
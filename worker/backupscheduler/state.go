@@ -0,0 +1,164 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler
+
+import (
+	"io"
+
+	"github.com/juju/errors"
+	"github.com/juju/replicaset"
+	"github.com/juju/utils/clock"
+
+	"github.com/juju/juju/mongo"
+	"github.com/juju/juju/state"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/backups/archivestore"
+	"github.com/juju/juju/state/backups/encryption"
+)
+
+// NewStateConfigSource returns a ConfigSource which reads the
+// configuration of scheduled backups from the controller config, for
+// backups of the controller machine with the given ID.
+func NewStateConfigSource(st *state.State, machineID string, paths backups.Paths) ConfigSource {
+	return &stateConfigSource{
+		st:        st,
+		machineID: machineID,
+		paths:     paths,
+	}
+}
+
+type stateConfigSource struct {
+	st        *state.State
+	machineID string
+	paths     backups.Paths
+}
+
+// BackupConfig is part of the ConfigSource interface.
+func (s *stateConfigSource) BackupConfig() (Config, bool, error) {
+	controllerConfig, err := s.st.ControllerConfig()
+	if err != nil {
+		return Config{}, false, errors.Annotate(err, "cannot fetch the controller config")
+	}
+	sched := controllerConfig.BackupSchedule()
+	if sched == nil {
+		return Config{}, false, nil
+	}
+	var store archivestore.Store
+	if storeConfig, ok := controllerConfig.BackupStoreConfig(); ok {
+		store, err = archivestore.Open(storeConfig)
+		if err != nil {
+			return Config{}, false, errors.Annotate(err, "cannot open backup store")
+		}
+	}
+	return Config{
+		Backups:        NewStateBackups(s.st, s.machineID, s.paths, controllerConfig.BackupEncryptionKey()),
+		Store:          store,
+		Schedule:       sched,
+		RetentionCount: controllerConfig.BackupRetentionCount(),
+		Clock:          clock.WallClock,
+	}, true, nil
+}
+
+// WatchBackupConfig is part of the ConfigSource interface.
+func (s *stateConfigSource) WatchBackupConfig() state.NotifyWatcher {
+	return s.st.WatchControllerConfig()
+}
+
+// NewStateBackups returns a Backups which backs up the controller
+// machine with the given ID, storing archives in state. If key is
+// not nil, the archives are encrypted with it.
+func NewStateBackups(st *state.State, machineID string, paths backups.Paths, key *encryption.Key) Backups {
+	return &stateBackups{
+		st:        st,
+		machineID: machineID,
+		paths:     paths,
+		key:       key,
+	}
+}
+
+type stateBackups struct {
+	st        *state.State
+	machineID string
+	paths     backups.Paths
+	key       *encryption.Key
+}
+
+// Create is part of the Backups interface.
+func (b *stateBackups) Create(notes string) (*backups.Metadata, error) {
+	session := b.st.MongoSession().Copy()
+	defer session.Close()
+
+	// Don't go if HA isn't ready.
+	if err := replicaset.WaitUntilReady(session, 60); err != nil {
+		return nil, errors.Annotatef(err, "HA not ready")
+	}
+	v, err := b.st.MongoVersion()
+	if err != nil {
+		return nil, errors.Annotatef(err, "discovering mongo version")
+	}
+	mongoVersion, err := mongo.NewVersion(v)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	dbInfo, err := backups.NewDBInfo(b.st.MongoConnectionInfo(), session, mongoVersion)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	machine, err := b.st.Machine(b.machineID)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	meta, err := backups.NewMetadataState(b.st, b.machineID, machine.Series())
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	meta.Notes = notes
+
+	stor := backups.NewStorage(b.st)
+	defer stor.Close()
+	if err := backups.NewBackups(stor).Create(meta, &b.paths, dbInfo, b.key); err != nil {
+		return nil, errors.Trace(err)
+	}
+	return meta, nil
+}
+
+// Get is part of the Backups interface.
+func (b *stateBackups) Get(id string) (*backups.Metadata, io.ReadCloser, error) {
+	stor := backups.NewStorage(b.st)
+	meta, archive, err := backups.NewBackups(stor).Get(id)
+	if err != nil {
+		stor.Close()
+		return nil, nil, errors.Trace(err)
+	}
+	return meta, &storageReader{archive, stor}, nil
+}
+
+// List is part of the Backups interface.
+func (b *stateBackups) List() ([]*backups.Metadata, error) {
+	stor := backups.NewStorage(b.st)
+	defer stor.Close()
+	metaList, err := backups.NewBackups(stor).List()
+	return metaList, errors.Trace(err)
+}
+
+// Remove is part of the Backups interface.
+func (b *stateBackups) Remove(id string) error {
+	stor := backups.NewStorage(b.st)
+	defer stor.Close()
+	return errors.Trace(backups.NewBackups(stor).Remove(id))
+}
+
+// storageReader closes the backup storage along with the archive
+// read from it.
+type storageReader struct {
+	io.ReadCloser
+	stor io.Closer
+}
+
+// Close is part of the io.Closer interface.
+func (r *storageReader) Close() error {
+	err := r.ReadCloser.Close()
+	r.stor.Close()
+	return errors.Trace(err)
+}
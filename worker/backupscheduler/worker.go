@@ -0,0 +1,215 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+// Package backupscheduler provides a worker which creates backups of
+// the controller on a schedule, copies them to an archive store, and
+// removes old scheduled backups.
+package backupscheduler
+
+import (
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/juju/errors"
+	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
+	"gopkg.in/juju/worker.v1"
+
+	"github.com/juju/juju/core/schedule"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/backups/archivestore"
+	jworker "github.com/juju/juju/worker"
+)
+
+var logger = loggo.GetLogger("juju.worker.backupscheduler")
+
+// Notes is recorded in the metadata of backups created by the worker.
+// Only backups with these notes are removed by the worker.
+const Notes = "scheduled backup"
+
+// Backups exposes the backup operations needed by the worker.
+type Backups interface {
+	// Create creates and stores a new backup with the given notes,
+	// returning its metadata.
+	Create(notes string) (*backups.Metadata, error)
+
+	// Get returns the metadata and archive of the identified backup.
+	Get(id string) (*backups.Metadata, io.ReadCloser, error)
+
+	// List returns the metadata of all stored backups.
+	List() ([]*backups.Metadata, error)
+
+	// Remove removes the identified backup.
+	Remove(id string) error
+}
+
+// Config holds the dependencies and configuration for a backup
+// scheduler worker.
+type Config struct {
+	// Backups creates and manages the controller's backups.
+	Backups Backups
+
+	// Store, if not nil, is where each scheduled backup is copied.
+	Store archivestore.Store
+
+	// Schedule determines when backups are created.
+	Schedule schedule.Schedule
+
+	// RetentionCount is the number of scheduled backups to keep,
+	// both in the controller and in the store.
+	RetentionCount int
+
+	// Clock is used to wait for the next scheduled backup.
+	Clock clock.Clock
+}
+
+// Validate returns an error if the config cannot be expected to
+// drive a functional worker.
+func (config Config) Validate() error {
+	if config.Backups == nil {
+		return errors.NotValidf("nil Backups")
+	}
+	if config.Schedule == nil {
+		return errors.NotValidf("nil Schedule")
+	}
+	if config.RetentionCount < 1 {
+		return errors.NotValidf("RetentionCount %d", config.RetentionCount)
+	}
+	if config.Clock == nil {
+		return errors.NotValidf("nil Clock")
+	}
+	return nil
+}
+
+// NewWorker returns a worker which creates a backup at each time
+// given by the schedule. A backup which fails is logged and retried
+// at the next scheduled time.
+func NewWorker(config Config) (worker.Worker, error) {
+	if err := config.Validate(); err != nil {
+		return nil, errors.Trace(err)
+	}
+	w := &backupWorker{config: config}
+	return jworker.NewSimpleWorker(w.loop), nil
+}
+
+type backupWorker struct {
+	config Config
+}
+
+func (w *backupWorker) loop(stopCh <-chan struct{}) error {
+	for {
+		now := w.config.Clock.Now()
+		next := w.config.Schedule.Next(now)
+		if next.IsZero() {
+			logger.Warningf("backup schedule has no future times")
+			<-stopCh
+			return nil
+		}
+		logger.Debugf("next scheduled backup at %s", next)
+		select {
+		case <-stopCh:
+			return nil
+		case <-w.config.Clock.After(next.Sub(now)):
+			if err := w.backup(); err != nil {
+				logger.Errorf("scheduled backup failed: %v", err)
+			}
+		}
+	}
+}
+
+// backup creates a new backup, copies it to the store, and then
+// removes any scheduled backups beyond the retention count.
+func (w *backupWorker) backup() error {
+	meta, err := w.config.Backups.Create(Notes)
+	if err != nil {
+		return errors.Annotate(err, "creating backup")
+	}
+	logger.Infof("created scheduled backup %s", meta.ID())
+	if w.config.Store != nil {
+		if err := w.copyToStore(meta.ID()); err != nil {
+			return errors.Trace(err)
+		}
+		if err := w.pruneStore(); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	return errors.Trace(w.pruneBackups())
+}
+
+func (w *backupWorker) copyToStore(id string) error {
+	meta, archive, err := w.config.Backups.Get(id)
+	if err != nil {
+		return errors.Annotatef(err, "reading backup %s", id)
+	}
+	defer archive.Close()
+	name := ArchiveName(meta)
+	if err := w.config.Store.Put(name, archive, meta.Size()); err != nil {
+		return errors.Annotatef(err, "copying backup %s to store", id)
+	}
+	logger.Infof("copied backup %s to store as %s", id, name)
+	return nil
+}
+
+// pruneStore removes the oldest backup archives from the store,
+// leaving RetentionCount of them. Archives which weren't named by
+// the worker are left alone.
+func (w *backupWorker) pruneStore() error {
+	names, err := w.config.Store.List()
+	if err != nil {
+		return errors.Annotate(err, "listing stored backups")
+	}
+	var archives []string
+	for _, name := range names {
+		if strings.HasPrefix(name, backups.FilenamePrefix) {
+			archives = append(archives, name)
+		}
+	}
+	// The names include the time the backup was started, so they
+	// sort oldest first.
+	for len(archives) > w.config.RetentionCount {
+		if err := w.config.Store.Remove(archives[0]); err != nil {
+			return errors.Annotatef(err, "removing stored backup %s", archives[0])
+		}
+		logger.Infof("removed stored backup %s", archives[0])
+		archives = archives[1:]
+	}
+	return nil
+}
+
+// pruneBackups removes the oldest scheduled backups from the
+// controller, leaving RetentionCount of them.
+func (w *backupWorker) pruneBackups() error {
+	all, err := w.config.Backups.List()
+	if err != nil {
+		return errors.Annotate(err, "listing backups")
+	}
+	var scheduled []*backups.Metadata
+	for _, meta := range all {
+		if meta.Notes == Notes {
+			scheduled = append(scheduled, meta)
+		}
+	}
+	sort.Sort(byStarted(scheduled))
+	for len(scheduled) > w.config.RetentionCount {
+		id := scheduled[0].ID()
+		if err := w.config.Backups.Remove(id); err != nil {
+			return errors.Annotatef(err, "removing backup %s", id)
+		}
+		logger.Infof("removed scheduled backup %s", id)
+		scheduled = scheduled[1:]
+	}
+	return nil
+}
+
+// ArchiveName returns the name under which the backup's archive is
+// kept in the store.
+func ArchiveName(meta *backups.Metadata) string {
+	return meta.Started.UTC().Format(backups.FilenameTemplate)
+}
+
+type byStarted []*backups.Metadata
+
+func (b byStarted) Len() int           { return len(b) }
+func (b byStarted) Less(i, j int) bool { return b[i].Started.Before(b[j].Started) }
+func (b byStarted) Swap(i, j int)      { b[i], b[j] = b[j], b[i] }
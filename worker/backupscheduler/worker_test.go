@@ -0,0 +1,249 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package backupscheduler_test
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"sync"
+	"time"
+
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/core/schedule"
+	"github.com/juju/juju/state/backups"
+	"github.com/juju/juju/state/backups/archivestore"
+	coretesting "github.com/juju/juju/testing"
+	"github.com/juju/juju/worker/backupscheduler"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type workerSuite struct {
+	testing.IsolationSuite
+	clock   *testing.Clock
+	backups *fakeBackups
+	store   archivestore.Store
+	config  backupscheduler.Config
+}
+
+var _ = gc.Suite(&workerSuite{})
+
+func (s *workerSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.clock = testing.NewClock(time.Date(2017, time.March, 1, 10, 15, 0, 0, time.UTC))
+	s.backups = &fakeBackups{clock: s.clock}
+	s.store = archivestore.NewLocalStore(c.MkDir())
+	sched, err := schedule.Parse("@hourly")
+	c.Assert(err, jc.ErrorIsNil)
+	s.config = backupscheduler.Config{
+		Backups:        s.backups,
+		Store:          s.store,
+		Schedule:       sched,
+		RetentionCount: 2,
+		Clock:          s.clock,
+	}
+}
+
+func (s *workerSuite) TestValidate(c *gc.C) {
+	for i, test := range []struct {
+		mutate func(*backupscheduler.Config)
+		err    string
+	}{{
+		mutate: func(cfg *backupscheduler.Config) { cfg.Backups = nil },
+		err:    "nil Backups not valid",
+	}, {
+		mutate: func(cfg *backupscheduler.Config) { cfg.Schedule = nil },
+		err:    "nil Schedule not valid",
+	}, {
+		mutate: func(cfg *backupscheduler.Config) { cfg.RetentionCount = 0 },
+		err:    "RetentionCount 0 not valid",
+	}, {
+		mutate: func(cfg *backupscheduler.Config) { cfg.Clock = nil },
+		err:    "nil Clock not valid",
+	}, {
+		mutate: func(cfg *backupscheduler.Config) { cfg.Store = nil },
+	}} {
+		c.Logf("test %d", i)
+		config := s.config
+		test.mutate(&config)
+		err := config.Validate()
+		if test.err == "" {
+			c.Check(err, jc.ErrorIsNil)
+		} else {
+			c.Check(err, gc.ErrorMatches, test.err)
+			_, err := backupscheduler.NewWorker(config)
+			c.Check(err, gc.ErrorMatches, test.err)
+		}
+	}
+}
+
+func (s *workerSuite) TestBackupsOnSchedule(c *gc.C) {
+	w, err := backupscheduler.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	// The first backup is at the top of the hour.
+	s.advance(c, 45*time.Minute)
+	c.Assert(s.backups.ids(), jc.DeepEquals, []string{"backup-0"})
+	names, err := s.store.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{"juju-backup-20170301-110000.tar.gz"})
+
+	r, err := s.store.Get(names[0])
+	c.Assert(err, jc.ErrorIsNil)
+	defer r.Close()
+	data, err := ioutil.ReadAll(r)
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(string(data), gc.Equals, "archive backup-0")
+}
+
+func (s *workerSuite) TestRetention(c *gc.C) {
+	// Backups and archives which weren't created by the worker are
+	// left alone.
+	manual := s.backups.add("manual", "")
+	err := s.store.Put("README", bytes.NewBufferString("hello"), 5)
+	c.Assert(err, jc.ErrorIsNil)
+
+	w, err := backupscheduler.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.advance(c, 45*time.Minute)
+	s.advance(c, time.Hour)
+	s.advance(c, time.Hour)
+
+	c.Assert(s.backups.ids(), jc.DeepEquals, []string{manual.ID(), "backup-1", "backup-2"})
+	names, err := s.store.List()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(names, jc.DeepEquals, []string{
+		"README",
+		"juju-backup-20170301-120000.tar.gz",
+		"juju-backup-20170301-130000.tar.gz",
+	})
+}
+
+func (s *workerSuite) TestNoStore(c *gc.C) {
+	s.config.Store = nil
+	w, err := backupscheduler.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.advance(c, 45*time.Minute)
+	s.advance(c, time.Hour)
+	s.advance(c, time.Hour)
+	c.Assert(s.backups.ids(), jc.DeepEquals, []string{"backup-1", "backup-2"})
+}
+
+func (s *workerSuite) TestFailedBackupRetried(c *gc.C) {
+	s.backups.createErr = errors.New("HA not ready")
+	w, err := backupscheduler.NewWorker(s.config)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.CleanKill(c, w)
+
+	s.advance(c, 45*time.Minute)
+	c.Assert(s.backups.ids(), gc.HasLen, 0)
+
+	s.backups.setCreateErr(nil)
+	s.advance(c, time.Hour)
+	c.Assert(s.backups.ids(), jc.DeepEquals, []string{"backup-0"})
+}
+
+// advance moves the clock on by d once the worker is waiting, and
+// then waits for the worker to finish any backup and wait again.
+func (s *workerSuite) advance(c *gc.C, d time.Duration) {
+	err := s.clock.WaitAdvance(d, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	err = s.clock.WaitAdvance(0, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+}
+
+// fakeBackups is an in-memory implementation of
+// backupscheduler.Backups.
+type fakeBackups struct {
+	mu        sync.Mutex
+	clock     *testing.Clock
+	created   int
+	backups   []*backups.Metadata
+	createErr error
+}
+
+func (f *fakeBackups) add(id, notes string) *backups.Metadata {
+	meta := backups.NewMetadata()
+	meta.SetID(id)
+	meta.Started = f.clock.Now()
+	meta.Notes = notes
+	meta.MarkComplete(int64(len("archive "+id)), "checksum")
+	f.backups = append(f.backups, meta)
+	return meta
+}
+
+func (f *fakeBackups) ids() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var ids []string
+	for _, meta := range f.backups {
+		ids = append(ids, meta.ID())
+	}
+	return ids
+}
+
+func (f *fakeBackups) setCreateErr(err error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.createErr = err
+}
+
+// Create is part of the backupscheduler.Backups interface.
+func (f *fakeBackups) Create(notes string) (*backups.Metadata, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.createErr != nil {
+		return nil, f.createErr
+	}
+	meta := f.add(fmt.Sprintf("backup-%d", f.created), notes)
+	f.created++
+	return meta, nil
+}
+
+// Get is part of the backupscheduler.Backups interface.
+func (f *fakeBackups) Get(id string) (*backups.Metadata, io.ReadCloser, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, meta := range f.backups {
+		if meta.ID() == id {
+			return meta, ioutil.NopCloser(bytes.NewBufferString("archive " + id)), nil
+		}
+	}
+	return nil, nil, errors.NotFoundf("backup %q", id)
+}
+
+// List is part of the backupscheduler.Backups interface.
+func (f *fakeBackups) List() ([]*backups.Metadata, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	// Return them newest first, to show that the worker sorts them.
+	result := make([]*backups.Metadata, len(f.backups))
+	for i, meta := range f.backups {
+		result[len(result)-1-i] = meta
+	}
+	return result, nil
+}
+
+// Remove is part of the backupscheduler.Backups interface.
+func (f *fakeBackups) Remove(id string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for i, meta := range f.backups {
+		if meta.ID() == id {
+			f.backups = append(f.backups[:i], f.backups[i+1:]...)
+			return nil
+		}
+	}
+	return errors.NotFoundf("backup %q", id)
+}
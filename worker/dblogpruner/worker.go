@@ -14,7 +14,9 @@ import (
 	jworker "github.com/juju/juju/worker"
 )
 
-// LogPruneParams specifies how logs should be pruned.
+// LogPruneParams specifies how logs should be pruned. Models may
+// shorten MaxLogAge, and set their own size limit, with the
+// logs-max-age and logs-max-size model config settings.
 type LogPruneParams struct {
 	MaxLogAge       time.Duration
 	MaxCollectionMB int
@@ -36,8 +38,9 @@ func NewLogPruneParams() *LogPruneParams {
 }
 
 // New returns a worker which periodically wakes up to remove old log
-// entries stored in MongoDB. This worker is intended to run just
-// once, on the MongoDB master.
+// entries stored in MongoDB. Each model's log retention settings are
+// applied before the controller-wide limits. This worker is intended
+// to run just once, on the MongoDB master.
 func New(st *state.State, params *LogPruneParams) worker.Worker {
 	w := &pruneWorker{
 		st:     st,
@@ -59,8 +62,13 @@ func (w *pruneWorker) loop(stopCh <-chan struct{}) error {
 			return tomb.ErrDying
 		case <-time.After(p.PruneInterval):
 			// TODO(fwereade): 2016-03-17 lp:1558657
-			minLogTime := time.Now().Add(-p.MaxLogAge)
-			err := state.PruneLogs(w.st, minLogTime, p.MaxCollectionMB)
+			now := time.Now()
+			retention, err := w.st.AllModelLogRetention(now)
+			if err != nil {
+				return errors.Trace(err)
+			}
+			minLogTime := now.Add(-p.MaxLogAge)
+			err = state.PruneLogsWithRetention(w.st, minLogTime, p.MaxCollectionMB, retention)
 			if err != nil {
 				return errors.Trace(err)
 			}
@@ -18,6 +18,7 @@ import (
 	"github.com/juju/juju/state"
 	statetesting "github.com/juju/juju/state/testing"
 	"github.com/juju/juju/testing"
+	"github.com/juju/juju/testing/factory"
 	"github.com/juju/juju/version"
 	"github.com/juju/juju/worker/dblogpruner"
 )
@@ -107,8 +108,62 @@ func (s *suite) TestPrunesLogsBySize(c *gc.C) {
 	c.Fatal("pruning didn't happen as expected")
 }
 
+func (s *suite) TestPrunesLogsByModelMaxAge(c *gc.C) {
+	maxLogAge := 24 * time.Hour
+	noPruneMB := int(1e9)
+	st := s.Factory.MakeModel(c, &factory.ModelParams{
+		ConfigAttrs: testing.Attrs{"logs-max-age": "1h"},
+	})
+	defer st.Close()
+	s.StartWorker(c, maxLogAge, noPruneMB)
+
+	// Logs older than the model's own limit are pruned for that
+	// model only.
+	now := time.Now()
+	s.addLogsForModel(c, s.State, now.Add(-2*time.Hour), "keep", 5)
+	s.addLogsForModel(c, st, now.Add(-2*time.Hour), "prune", 5)
+	s.addLogsForModel(c, st, now, "keep", 5)
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		pruneRemaining, err := s.logsColl.Find(bson.M{"x": "prune"}).Count()
+		c.Assert(err, jc.ErrorIsNil)
+		if pruneRemaining == 0 {
+			keepCount, err := s.logsColl.Find(bson.M{"x": "keep"}).Count()
+			c.Assert(err, jc.ErrorIsNil)
+			c.Assert(keepCount, gc.Equals, 10)
+			return
+		}
+	}
+	c.Fatal("pruning didn't happen as expected")
+}
+
+func (s *suite) TestPrunesLogsByModelMaxSize(c *gc.C) {
+	noPruneAge := 999 * time.Hour
+	noPruneMB := int(1e9)
+	st := s.Factory.MakeModel(c, &factory.ModelParams{
+		ConfigAttrs: testing.Attrs{"logs-max-size": "1M"},
+	})
+	defer st.Close()
+	startingLogCount := 25000
+	s.addLogsForModel(c, st, time.Now(), "stuff", startingLogCount)
+	s.StartWorker(c, noPruneAge, noPruneMB)
+
+	for attempt := testing.LongAttempt.Start(); attempt.Next(); {
+		count, err := s.logsColl.Find(bson.M{"e": st.ModelUUID()}).Count()
+		c.Assert(err, jc.ErrorIsNil)
+		if count < startingLogCount {
+			return
+		}
+	}
+	c.Fatal("pruning didn't happen as expected")
+}
+
 func (s *suite) addLogs(c *gc.C, t0 time.Time, text string, count int) {
-	dbLogger := state.NewEntityDbLogger(s.State, names.NewMachineTag("0"), version.Current)
+	s.addLogsForModel(c, s.State, t0, text, count)
+}
+
+func (s *suite) addLogsForModel(c *gc.C, st *state.State, t0 time.Time, text string, count int) {
+	dbLogger := state.NewEntityDbLogger(st, names.NewMachineTag("0"), version.Current)
 	defer dbLogger.Close()
 
 	for offset := 0; offset < count; offset++ {
@@ -9,6 +9,7 @@ import (
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
 	"github.com/juju/utils/set"
 	"gopkg.in/juju/worker.v1"
 	"gopkg.in/tomb.v1"
@@ -41,6 +42,15 @@ type EngineConfig struct {
 	// a worker that was deliberately stopped because its dependencies
 	// changed. It must not be negative.
 	BounceDelay time.Duration
+
+	// Clock is used to delay the starting of workers, and to time the
+	// changes recorded for history reports. It must not be nil.
+	Clock clock.Clock
+
+	// HistorySize controls how many changes to the state of each
+	// manifold's worker the engine remembers, for use in history
+	// reports. It must not be negative; if zero, no history is kept.
+	HistorySize int
 }
 
 // Validate returns an error if any field is invalid.
@@ -51,12 +61,18 @@ func (config *EngineConfig) Validate() error {
 	if config.WorstError == nil {
 		return errors.New("WorstError not specified")
 	}
+	if config.Clock == nil {
+		return errors.New("Clock not specified")
+	}
 	if config.ErrorDelay < 0 {
 		return errors.New("ErrorDelay is negative")
 	}
 	if config.BounceDelay < 0 {
 		return errors.New("BounceDelay is negative")
 	}
+	if config.HistorySize < 0 {
+		return errors.New("HistorySize is negative")
+	}
 	return nil
 }
 
@@ -75,6 +91,7 @@ func NewEngine(config EngineConfig) (*Engine, error) {
 		manifolds:  Manifolds{},
 		dependents: map[string][]string{},
 		current:    map[string]workerInfo{},
+		history:    map[string]*transitionLog{},
 
 		install: make(chan installTicket),
 		started: make(chan startedTicket),
@@ -116,6 +133,9 @@ type Engine struct {
 	// current holds the active worker information for each installed manifold.
 	current map[string]workerInfo
 
+	// history holds the recent worker state transitions for each manifold.
+	history map[string]*transitionLog
+
 	// install, started, report and stopped each communicate requests and changes into
 	// the loop goroutine.
 	install chan installTicket
@@ -142,8 +162,13 @@ func (engine *Engine) loop() error {
 				engine.requestStop(name)
 			}
 		case ticket := <-engine.report:
-			// This is safe so long as the Report method reads the result.
-			ticket.result <- engine.liveReport()
+			// This is safe so long as the Report and HistoryReport
+			// methods read the result.
+			if ticket.history {
+				ticket.result <- engine.historyReport()
+			} else {
+				ticket.result <- engine.liveReport()
+			}
 		case ticket := <-engine.install:
 			// This is safe so long as the Install method reads the result.
 			ticket.result <- engine.gotInstall(ticket.name, ticket.manifold)
@@ -181,7 +206,7 @@ func (engine *Engine) Wait() error {
 func (engine *Engine) Report() map[string]interface{} {
 	report := make(chan map[string]interface{})
 	select {
-	case engine.report <- reportTicket{report}:
+	case engine.report <- reportTicket{result: report}:
 		// This is safe so long as the loop sends a result.
 		return <-report
 	case <-engine.tomb.Dead():
@@ -200,6 +225,54 @@ func (engine *Engine) Report() map[string]interface{} {
 	}
 }
 
+// HistoryReport returns a map describing the most recent changes to the
+// state of each manifold's worker, with the time and any error of each.
+func (engine *Engine) HistoryReport() map[string]interface{} {
+	report := make(chan map[string]interface{})
+	select {
+	case engine.report <- reportTicket{result: report, history: true}:
+		// This is safe so long as the loop sends a result.
+		return <-report
+	case <-engine.tomb.Dead():
+		// As in Report, once the loop has exited it's safe to
+		// read the history directly.
+		return engine.historyReport()
+	}
+}
+
+// historyReport collects and returns the recent history of the engine's
+// manifolds. Until the tomb is Dead, it should only be called from the
+// loop goroutine; after that, it's goroutine-safe.
+func (engine *Engine) historyReport() map[string]interface{} {
+	manifolds := map[string]interface{}{}
+	for name, log := range engine.history {
+		manifolds[name] = map[string]interface{}{
+			KeyHistory: log.report(),
+		}
+	}
+	return map[string]interface{}{
+		KeyManifolds: manifolds,
+	}
+}
+
+// recordTransition adds a change in the state of the named manifold's
+// worker to its history. It must only be called from the loop goroutine.
+func (engine *Engine) recordTransition(name, state string, err error) {
+	if engine.config.HistorySize == 0 {
+		return
+	}
+	log, ok := engine.history[name]
+	if !ok {
+		log = newTransitionLog(engine.config.HistorySize)
+		engine.history[name] = log
+	}
+	log.add(transition{
+		time:  engine.config.Clock.Now(),
+		state: state,
+		err:   err,
+	})
+}
+
 // liveReport collects and returns information about the engine, its manifolds,
 // and their workers. It must only be called from the loop goroutine.
 func (engine *Engine) liveReport() map[string]interface{} {
@@ -424,8 +497,7 @@ func (engine *Engine) runWorker(name string, delay time.Duration, start StartFun
 			return nil, errAborted
 		case <-context.Abort():
 			return nil, errAborted
-		// TODO(fwereade): 2016-03-17 lp:1558657
-		case <-time.After(delay):
+		case <-engine.config.Clock.After(delay):
 		}
 		logger.Tracef("starting %q manifold worker", name)
 		return start(context)
@@ -487,6 +559,7 @@ func (engine *Engine) gotStarted(name string, worker worker.Worker, resourceLog
 			worker:      worker,
 			resourceLog: resourceLog,
 		}
+		engine.recordTransition(name, historyStarted, nil)
 
 		// Any manifold that declares this one as an input needs to be restarted.
 		engine.bounceDependents(name)
@@ -510,6 +583,12 @@ func (engine *Engine) gotStopped(name string, err error, resourceLog []resourceA
 		engine.tomb.Kill(nil)
 	}
 
+	// Record the change, unless an unstarted worker was aborted.
+	if err != nil || info.worker != nil {
+		state, recordErr := stoppedState(err)
+		engine.recordTransition(name, state, recordErr)
+	}
+
 	// Reset engine info; and bail out if we can be sure there's no need to bounce.
 	engine.current[name] = workerInfo{
 		err:         err,
@@ -677,4 +756,8 @@ type stoppedTicket struct {
 // should be generated.
 type reportTicket struct {
 	result chan map[string]interface{}
+
+	// history is true if the report should describe the recent
+	// history of the manifolds rather than their current state.
+	history bool
 }
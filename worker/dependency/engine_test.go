@@ -9,6 +9,7 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
 	gc "gopkg.in/check.v1"
 	worker "gopkg.in/juju/worker.v1"
 
@@ -687,6 +688,14 @@ func (s *EngineSuite) TestConfigValidate(c *gc.C) {
 		func(config *dependency.EngineConfig) {
 			config.BounceDelay = -time.Second
 		}, "BounceDelay is negative",
+	}, {
+		func(config *dependency.EngineConfig) {
+			config.Clock = nil
+		}, "Clock not specified",
+	}, {
+		func(config *dependency.EngineConfig) {
+			config.HistorySize = -1
+		}, "HistorySize is negative",
 	}}
 
 	for i, test := range tests {
@@ -696,6 +705,7 @@ func (s *EngineSuite) TestConfigValidate(c *gc.C) {
 			WorstError:  firstError,
 			ErrorDelay:  time.Second,
 			BounceDelay: time.Second,
+			Clock:       clock.WallClock,
 		}
 		test.breakConfig(&config)
 
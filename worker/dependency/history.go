@@ -0,0 +1,98 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package dependency
+
+import (
+	"time"
+
+	"github.com/juju/errors"
+)
+
+// The states recorded in the history of a manifold's worker.
+const (
+	historyStarted     = "started"
+	historyStopped     = "stopped"
+	historyBounced     = "bounced"
+	historyMissing     = "missing"
+	historyUninstalled = "uninstalled"
+	historyError       = "error"
+)
+
+// stoppedState returns the state recorded for a worker that stopped
+// with the given error, and the error to record with it, if any. The
+// errors by which workers ask the engine to act aren't failures, so
+// they're recorded as states of their own.
+func stoppedState(err error) (string, error) {
+	switch errors.Cause(err) {
+	case nil:
+		return historyStopped, nil
+	case ErrBounce:
+		return historyBounced, nil
+	case ErrMissing:
+		return historyMissing, nil
+	case ErrUninstall:
+		return historyUninstalled, nil
+	}
+	return historyError, err
+}
+
+// transition records a change in the state of a manifold's worker.
+type transition struct {
+	time  time.Time
+	state string
+	err   error
+}
+
+// report returns a map describing the transition, for use in history
+// reports.
+func (t transition) report() map[string]interface{} {
+	report := map[string]interface{}{
+		KeyTime:  t.time.UTC().Format(time.RFC3339Nano),
+		KeyState: t.state,
+	}
+	if t.err != nil {
+		report[KeyError] = t.err.Error()
+	}
+	return report
+}
+
+// transitionLog is a ring buffer holding the most recent transitions of
+// a manifold's worker.
+type transitionLog struct {
+	transitions []transition
+	next        int
+	full        bool
+}
+
+// newTransitionLog returns a transitionLog that holds at most size
+// transitions.
+func newTransitionLog(size int) *transitionLog {
+	return &transitionLog{transitions: make([]transition, size)}
+}
+
+// add records the transition, discarding the oldest one if the log is
+// full.
+func (log *transitionLog) add(t transition) {
+	log.transitions[log.next] = t
+	log.next++
+	if log.next == len(log.transitions) {
+		log.next = 0
+		log.full = true
+	}
+}
+
+// report returns a description of each transition in the log, oldest
+// first.
+func (log *transitionLog) report() []map[string]interface{} {
+	var transitions []transition
+	if log.full {
+		transitions = append(transitions, log.transitions[log.next:]...)
+	}
+	transitions = append(transitions, log.transitions[:log.next]...)
+	report := make([]map[string]interface{}, len(transitions))
+	for i, t := range transitions {
+		report[i] = t.report()
+	}
+	return report
+}
@@ -57,6 +57,14 @@ const (
 	// error encountered.
 	KeyResourceLog = "resource-log"
 
+	// KeyHistory holds a slice describing the most recent changes to the
+	// state of a manifold's worker, oldest first.
+	KeyHistory = "history"
+
+	// KeyTime holds the time at which something happened, in RFC3339
+	// format.
+	KeyTime = "time"
+
 	// KeyName holds the name of some resource.
 	KeyName = "name"
 
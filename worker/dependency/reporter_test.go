@@ -6,8 +6,10 @@ package dependency_test
 import (
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/testing"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
 	gc "gopkg.in/check.v1"
 
 	coretesting "github.com/juju/juju/testing"
@@ -170,3 +172,86 @@ func (s *ReportSuite) TestReportError(c *gc.C) {
 		})
 	})
 }
+
+// fixedClock is a wall clock that always reports the same time.
+type fixedClock struct {
+	clock.Clock
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+func (s *ReportSuite) historyReport(engine *dependency.Engine, name string) []map[string]interface{} {
+	report := engine.HistoryReport()
+	manifolds := report["manifolds"].(map[string]interface{})
+	return manifolds[name].(map[string]interface{})["history"].([]map[string]interface{})
+}
+
+func (s *ReportSuite) TestHistoryReport(c *gc.C) {
+	s.fix.historySize = 3
+	s.fix.clock = fixedClock{clock.WallClock, time.Date(2017, time.May, 1, 10, 0, 0, 0, time.UTC)}
+	s.fix.run(c, func(engine *dependency.Engine) {
+		mh1 := newManifoldHarness()
+		err := engine.Install("task", mh1.Manifold())
+		c.Assert(err, jc.ErrorIsNil)
+		mh1.AssertOneStart(c)
+
+		mh1.InjectError(c, errors.New("boom"))
+		mh1.AssertOneStart(c)
+
+		workertest.CleanKill(c, engine)
+		// The first start has been discarded.
+		c.Check(s.historyReport(engine, "task"), jc.DeepEquals, []map[string]interface{}{
+			{"time": "2017-05-01T10:00:00Z", "state": "error", "error": "boom"},
+			{"time": "2017-05-01T10:00:00Z", "state": "started"},
+			{"time": "2017-05-01T10:00:00Z", "state": "stopped"},
+		})
+	})
+}
+
+func (s *ReportSuite) TestHistoryReportEngineErrors(c *gc.C) {
+	s.fix.historySize = 10
+	s.fix.run(c, func(engine *dependency.Engine) {
+		mh1 := newManifoldHarness()
+		err := engine.Install("task", mh1.Manifold())
+		c.Assert(err, jc.ErrorIsNil)
+		mh1.AssertOneStart(c)
+		mh1.InjectError(c, dependency.ErrBounce)
+		mh1.AssertOneStart(c)
+
+		mh2 := newManifoldHarness("absent")
+		err = engine.Install("needy", mh2.Manifold())
+		c.Assert(err, jc.ErrorIsNil)
+		mh2.AssertNoStart(c)
+
+		workertest.CleanKill(c, engine)
+		for name, expect := range map[string][]string{
+			"task":  {"started", "bounced", "started", "stopped"},
+			"needy": {"missing"},
+		} {
+			history := s.historyReport(engine, name)
+			states := make([]string, len(history))
+			for i, transition := range history {
+				states[i] = transition["state"].(string)
+				c.Check(transition["error"], gc.IsNil)
+			}
+			c.Check(states, jc.DeepEquals, expect)
+		}
+	})
+}
+
+func (s *ReportSuite) TestHistoryReportDisabled(c *gc.C) {
+	s.fix.run(c, func(engine *dependency.Engine) {
+		mh1 := newManifoldHarness()
+		err := engine.Install("task", mh1.Manifold())
+		c.Assert(err, jc.ErrorIsNil)
+		mh1.AssertOneStart(c)
+
+		report := engine.HistoryReport()
+		c.Check(report, jc.DeepEquals, map[string]interface{}{
+			"manifolds": map[string]interface{}{},
+		})
+	})
+}
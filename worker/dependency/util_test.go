@@ -8,6 +8,7 @@ import (
 
 	"github.com/juju/errors"
 	jc "github.com/juju/testing/checkers"
+	"github.com/juju/utils/clock"
 	gc "gopkg.in/check.v1"
 	worker "gopkg.in/juju/worker.v1"
 	"gopkg.in/tomb.v1"
@@ -18,10 +19,12 @@ import (
 )
 
 type engineFixture struct {
-	isFatal    dependency.IsFatalFunc
-	worstError dependency.WorstErrorFunc
-	filter     dependency.FilterFunc
-	dirty      bool
+	isFatal     dependency.IsFatalFunc
+	worstError  dependency.WorstErrorFunc
+	filter      dependency.FilterFunc
+	clock       clock.Clock
+	historySize int
+	dirty       bool
 }
 
 func (fix *engineFixture) isFatalFunc() dependency.IsFatalFunc {
@@ -38,6 +41,13 @@ func (fix *engineFixture) worstErrorFunc() dependency.WorstErrorFunc {
 	return firstError
 }
 
+func (fix *engineFixture) clockFunc() clock.Clock {
+	if fix.clock != nil {
+		return fix.clock
+	}
+	return clock.WallClock
+}
+
 func (fix *engineFixture) run(c *gc.C, test func(*dependency.Engine)) {
 	config := dependency.EngineConfig{
 		IsFatal:     fix.isFatalFunc(),
@@ -45,6 +55,8 @@ func (fix *engineFixture) run(c *gc.C, test func(*dependency.Engine)) {
 		Filter:      fix.filter, // can be nil anyway
 		ErrorDelay:  coretesting.ShortWait / 2,
 		BounceDelay: coretesting.ShortWait / 10,
+		Clock:       fix.clockFunc(),
+		HistorySize: fix.historySize,
 	}
 
 	engine, err := dependency.NewEngine(config)
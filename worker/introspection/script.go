@@ -74,6 +74,13 @@ juju-heap-profile () {
 }
 
 juju-engine-report () {
+  # With --history, report the recent starts, stops and errors of the
+  # workers rather than their current state.
+  if [ "$1" = "--history" ]; then
+    shift
+    jujuMachineOrUnit "depengine/?history" $@
+    return
+  fi
   jujuMachineOrUnit depengine/ $@
 }
 
@@ -81,6 +88,10 @@ juju-statepool-report () {
   jujuMachineOrUnit statepool/ $@
 }
 
+juju-rpctrace-report () {
+  jujuMachineOrUnit rpctrace/ $@
+}
+
 juju-statetracker-report () {
   jujuMachineOrUnit debug/pprof/juju/state/tracker?debug=1 $@
 }
@@ -92,5 +103,6 @@ export -f juju-goroutines
 export -f juju-heap-profile
 export -f juju-engine-report
 export -f juju-statepool-report
+export -f juju-rpctrace-report
 export -f juju-statetracker-report
 `
@@ -29,6 +29,16 @@ type DepEngineReporter interface {
 	Report() map[string]interface{}
 }
 
+// DepEngineHistoryReporter provides the recent history of the workers
+// run by the dependency engine of the agent. A DepEngineReporter may
+// also implement this interface.
+type DepEngineHistoryReporter interface {
+	// HistoryReport returns a map describing the recent changes to
+	// the state of the engine's workers. It is expected to be
+	// goroutine-safe.
+	HistoryReport() map[string]interface{}
+}
+
 // IntrospectionReporter provides a simple method that the introspection
 // worker will output for the entity.
 type IntrospectionReporter interface {
@@ -40,6 +50,7 @@ type Config struct {
 	SocketName         string
 	DepEngine          DepEngineReporter
 	StatePool          IntrospectionReporter
+	RPCTracer          IntrospectionReporter
 	PrometheusGatherer prometheus.Gatherer
 }
 
@@ -60,6 +71,7 @@ type socketListener struct {
 	listener           *net.UnixListener
 	depEngine          DepEngineReporter
 	statePool          IntrospectionReporter
+	rpcTracer          IntrospectionReporter
 	prometheusGatherer prometheus.Gatherer
 	done               chan struct{}
 }
@@ -90,6 +102,7 @@ func NewWorker(config Config) (worker.Worker, error) {
 		listener:           l,
 		depEngine:          config.DepEngine,
 		statePool:          config.StatePool,
+		rpcTracer:          config.RPCTracer,
 		prometheusGatherer: config.PrometheusGatherer,
 		done:               make(chan struct{}),
 	}
@@ -104,6 +117,7 @@ func (w *socketListener) serve() {
 		ReportSources{
 			DependencyEngine:   w.depEngine,
 			StatePool:          w.statePool,
+			RPCTracer:          w.rpcTracer,
 			PrometheusGatherer: w.prometheusGatherer,
 		}, mux.Handle)
 
@@ -139,6 +153,7 @@ func (w *socketListener) Wait() error {
 type ReportSources struct {
 	DependencyEngine   DepEngineReporter
 	StatePool          IntrospectionReporter
+	RPCTracer          IntrospectionReporter
 	PrometheusGatherer prometheus.Gatherer
 }
 
@@ -159,6 +174,10 @@ func RegisterHTTPHandlers(
 		name:     "State Pool Report",
 		reporter: sources.StatePool,
 	})
+	handle("/rpctrace/", introspectionReporterHandler{
+		name:     "RPC Trace Report",
+		reporter: sources.RPCTracer,
+	})
 	handle("/metrics", promhttp.HandlerFor(sources.PrometheusGatherer, promhttp.HandlerOpts{}))
 }
 
@@ -166,14 +185,28 @@ type depengineHandler struct {
 	reporter DepEngineReporter
 }
 
-// ServeHTTP is part of the http.Handler interface.
+// ServeHTTP is part of the http.Handler interface. If the "history"
+// query parameter is present, the recent history of the engine's
+// workers is reported instead of their current state.
 func (h depengineHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 	if h.reporter == nil {
 		w.WriteHeader(http.StatusNotFound)
 		fmt.Fprintln(w, "missing dependency engine reporter")
 		return
 	}
-	bytes, err := yaml.Marshal(h.reporter.Report())
+	title := "Dependency Engine Report"
+	report := h.reporter.Report
+	if _, ok := r.URL.Query()["history"]; ok {
+		historyReporter, ok := h.reporter.(DepEngineHistoryReporter)
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			fmt.Fprintln(w, "dependency engine history not available")
+			return
+		}
+		title = "Dependency Engine History"
+		report = historyReporter.HistoryReport
+	}
+	bytes, err := yaml.Marshal(report())
 	if err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		fmt.Fprintf(w, "error: %v\n", err)
@@ -182,7 +215,7 @@ func (h depengineHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
 
 	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
 
-	fmt.Fprint(w, "Dependency Engine Report\n\n")
+	fmt.Fprintf(w, "%s\n\n", title)
 	w.Write(bytes)
 }
 
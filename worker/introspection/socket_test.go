@@ -53,10 +53,11 @@ func (s *suite) TestStartStop(c *gc.C) {
 type introspectionSuite struct {
 	testing.IsolationSuite
 
-	name     string
-	worker   worker.Worker
-	reporter introspection.DepEngineReporter
-	gatherer prometheus.Gatherer
+	name      string
+	worker    worker.Worker
+	reporter  introspection.DepEngineReporter
+	rpcTracer introspection.IntrospectionReporter
+	gatherer  prometheus.Gatherer
 }
 
 var _ = gc.Suite(&introspectionSuite{})
@@ -67,6 +68,7 @@ func (s *introspectionSuite) SetUpTest(c *gc.C) {
 	}
 	s.IsolationSuite.SetUpTest(c)
 	s.reporter = nil
+	s.rpcTracer = nil
 	s.worker = nil
 	s.gatherer = newPrometheusGatherer()
 	s.startWorker(c)
@@ -77,6 +79,7 @@ func (s *introspectionSuite) startWorker(c *gc.C) {
 	w, err := introspection.NewWorker(introspection.Config{
 		SocketName:         s.name,
 		DepEngine:          s.reporter,
+		RPCTracer:          s.rpcTracer,
 		PrometheusGatherer: s.gatherer,
 	})
 	c.Assert(err, jc.ErrorIsNil)
@@ -124,6 +127,23 @@ func (s *introspectionSuite) TestMissingStatePoolReporter(c *gc.C) {
 	matches(c, buf, "State Pool Report: missing reporter")
 }
 
+func (s *introspectionSuite) TestMissingRPCTraceReporter(c *gc.C) {
+	buf := s.call(c, "/rpctrace/")
+	matches(c, buf, "404 Not Found")
+	matches(c, buf, "RPC Trace Report: missing reporter")
+}
+
+func (s *introspectionSuite) TestRPCTraceReporter(c *gc.C) {
+	workertest.CheckKill(c, s.worker)
+	s.rpcTracer = textReporter("Slowest 10 calls in the last 1h0m0s\n")
+	s.startWorker(c)
+	buf := s.call(c, "/rpctrace/")
+
+	matches(c, buf, "200 OK")
+	matches(c, buf, "RPC Trace Report:")
+	matches(c, buf, "Slowest 10 calls in the last 1h0m0s")
+}
+
 func (s *introspectionSuite) TestStateTrackerReporter(c *gc.C) {
 	buf := s.call(c, "/debug/pprof/juju/state/tracker")
 	matches(c, buf, "200 OK")
@@ -146,6 +166,31 @@ func (s *introspectionSuite) TestEngineReporter(c *gc.C) {
 	matches(c, buf, "working: true")
 }
 
+func (s *introspectionSuite) TestEngineHistoryReporter(c *gc.C) {
+	workertest.CheckKill(c, s.worker)
+	s.reporter = &historyReporter{
+		history: map[string]interface{}{
+			"bouncing": true,
+		},
+	}
+	s.startWorker(c)
+	buf := s.call(c, "/depengine/?history")
+
+	matches(c, buf, "200 OK")
+	matches(c, buf, "Dependency Engine History")
+	matches(c, buf, "bouncing: true")
+}
+
+func (s *introspectionSuite) TestEngineHistoryNotAvailable(c *gc.C) {
+	workertest.CheckKill(c, s.worker)
+	s.reporter = &reporter{}
+	s.startWorker(c)
+	buf := s.call(c, "/depengine/?history")
+
+	matches(c, buf, "404 Not Found")
+	matches(c, buf, "dependency engine history not available")
+}
+
 func (s *introspectionSuite) TestPrometheusMetrics(c *gc.C) {
 	buf := s.call(c, "/metrics")
 	c.Assert(buf, gc.NotNil)
@@ -178,6 +223,21 @@ func (r *reporter) Report() map[string]interface{} {
 	return r.values
 }
 
+type historyReporter struct {
+	reporter
+	history map[string]interface{}
+}
+
+func (r *historyReporter) HistoryReport() map[string]interface{} {
+	return r.history
+}
+
+type textReporter string
+
+func (r textReporter) IntrospectionReport() string {
+	return string(r)
+}
+
 func newPrometheusGatherer() prometheus.Gatherer {
 	counter := prometheus.NewCounter(prometheus.CounterOpts{Name: "tau", Help: "Tau."})
 	counter.Add(6.283185)
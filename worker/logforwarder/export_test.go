@@ -0,0 +1,18 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder
+
+import (
+	worker "gopkg.in/juju/worker.v1"
+)
+
+// NewOrchestratorForController exposes the orchestrator started by the
+// manifold, for testing.
+func NewOrchestratorForController(args OrchestratorArgs) (worker.Worker, error) {
+	o, err := newOrchestratorForController(args)
+	if o == nil {
+		return nil, err
+	}
+	return o, err
+}
@@ -6,19 +6,26 @@ package logforwarder
 import (
 	"io"
 	"sync"
+	"time"
 
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
+	"github.com/juju/utils/clock"
 	"gopkg.in/tomb.v1"
 
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/sinkconfig"
 	"github.com/juju/juju/worker/catacomb"
 )
 
 var logger = loggo.GetLogger("juju.worker.logforwarder")
 
+// batchFlushInterval is the longest time a record is held back while
+// a batch of records is collected.
+const batchFlushInterval = time.Second
+
 // LogStream streams log entries from a log source (e.g. the Juju controller).
 type LogStream interface {
 	// Next returns the next batch of log records from the stream.
@@ -51,6 +58,13 @@ type LogForwarder struct {
 	enabledCh chan bool
 	mu        sync.Mutex
 	enabled   bool
+
+	// batchSize, retryAttempts and retryDelay control how records
+	// are sent to the current sink; they are only accessed by the
+	// loop goroutine.
+	batchSize     int
+	retryAttempts int
+	retryDelay    time.Duration
 }
 
 // OpenLogForwarderArgs holds the info needed to open a LogForwarder.
@@ -58,6 +72,10 @@ type OpenLogForwarderArgs struct {
 	// AllModels indicates that the tracker is handling all models.
 	AllModels bool
 
+	// Audit indicates that audit log entries, rather than debug log
+	// records, are forwarded.
+	Audit bool
+
 	// ControllerUUID identifies the controller.
 	ControllerUUID string
 
@@ -77,9 +95,13 @@ type OpenLogForwarderArgs struct {
 	// OpenLogStream is the function that will be used to for the
 	// log stream.
 	OpenLogStream LogStreamFn
+
+	// Clock is used to time batching and retries. If it is nil, the
+	// wall clock is used.
+	Clock clock.Clock
 }
 
-// processNewConfig acts on a new syslog forward config change.
+// processNewConfig acts on a new log forward config change.
 func (lf *LogForwarder) processNewConfig(currentSender SendCloser) (SendCloser, error) {
 	lf.mu.Lock()
 	defer lf.mu.Unlock()
@@ -126,10 +148,44 @@ func (lf *LogForwarder) processNewConfig(currentSender SendCloser) (SendCloser,
 	if err != nil {
 		return nil, errors.Trace(err)
 	}
+	lf.setSendConfig(cfg)
 	lf.enabledCh <- true
 	return sink, nil
 }
 
+func (lf *LogForwarder) setSendConfig(cfg *sinkconfig.Config) {
+	lf.batchSize = cfg.BatchSize
+	if lf.batchSize == 0 {
+		lf.batchSize = sinkconfig.DefaultBatchSize
+	}
+	lf.retryAttempts = cfg.RetryAttempts
+	lf.retryDelay = cfg.RetryDelay
+	if lf.retryDelay == 0 {
+		lf.retryDelay = sinkconfig.DefaultRetryDelay
+	}
+}
+
+// send sends the records to the sender, resending them after a delay
+// if that fails, up to the configured number of retries. Records the
+// sender reports as sent before a failure are not resent.
+func (lf *LogForwarder) send(sender SendCloser, records []logfwd.Record) error {
+	for attempt := 0; ; attempt++ {
+		err := sender.Send(records)
+		if err == nil || attempt >= lf.retryAttempts {
+			return errors.Trace(err)
+		}
+		if partial, ok := errors.Cause(err).(*logfwd.PartialSendError); ok {
+			records = records[partial.Sent:]
+		}
+		logger.Warningf("sending %d log records failed (retrying in %v): %v", len(records), lf.retryDelay, err)
+		select {
+		case <-lf.catacomb.Dying():
+			return lf.catacomb.ErrDying()
+		case <-lf.args.Clock.After(lf.retryDelay):
+		}
+	}
+}
+
 // waitForEnabled returns true if streaming is enabled.
 // Otherwise if blocks and waits for enabled to be true.
 func (lf *LogForwarder) waitForEnabled() (bool, error) {
@@ -149,7 +205,7 @@ func (lf *LogForwarder) waitForEnabled() (bool, error) {
 	defer lf.mu.Unlock()
 
 	if !lf.enabled && enabled {
-		logger.Infof("log forward enabled, starting to stream logs to syslog sink")
+		logger.Infof("log forward enabled, starting to stream logs to %s sink", lf.args.Name)
 	}
 	lf.enabled = enabled
 	return enabled, nil
@@ -158,6 +214,9 @@ func (lf *LogForwarder) waitForEnabled() (bool, error) {
 // NewLogForwarder returns a worker that forwards logs received from
 // the stream to the sender.
 func NewLogForwarder(args OpenLogForwarderArgs) (*LogForwarder, error) {
+	if args.Clock == nil {
+		args.Clock = clock.WallClock
+	}
 	lf := &LogForwarder{
 		args:      args,
 		enabledCh: make(chan bool, 1),
@@ -199,6 +258,7 @@ func (lf *LogForwarder) loop() error {
 				streamCfg := params.LogStreamConfig{
 					AllModels: lf.args.AllModels,
 					Sink:      lf.args.Name,
+					Audit:     lf.args.Audit,
 					// TODO(wallyworld) - this should be configurable via lf.args.LogForwardConfig
 					MaxLookbackRecords: 100,
 				}
@@ -229,13 +289,32 @@ func (lf *LogForwarder) loop() error {
 		}
 	}()
 
+	// Records are collected into batches, which are sent once they
+	// are full or the oldest record has waited long enough.
+	var pending []logfwd.Record
+	var flush <-chan time.Time
+	sendPending := func() error {
+		flush = nil
+		if len(pending) == 0 {
+			return nil
+		}
+		batch := pending
+		pending = nil
+		return errors.Trace(lf.send(sender, batch))
+	}
+
 	for {
 		select {
 		case <-lf.catacomb.Dying():
 			return lf.catacomb.ErrDying()
 		case _, ok := <-configWatcher.Changes():
 			if !ok {
-				return errors.New("syslog configuration watcher closed")
+				return errors.New("log forward configuration watcher closed")
+			}
+			if sender != nil {
+				if err := sendPending(); err != nil {
+					return errors.Trace(err)
+				}
 			}
 			if sender, err = lf.processNewConfig(sender); err != nil {
 				return errors.Trace(err)
@@ -244,7 +323,18 @@ func (lf *LogForwarder) loop() error {
 			if sender == nil {
 				continue
 			}
-			if err := sender.Send(rec); err != nil {
+			pending = append(pending, rec...)
+			if len(pending) < lf.batchSize {
+				if flush == nil {
+					flush = lf.args.Clock.After(batchFlushInterval)
+				}
+				continue
+			}
+			if err := sendPending(); err != nil {
+				return errors.Trace(err)
+			}
+		case <-flush:
+			if err := sendPending(); err != nil {
 				return errors.Trace(err)
 			}
 		}
@@ -15,6 +15,7 @@ import (
 	"github.com/juju/juju/api/base"
 	"github.com/juju/juju/apiserver/params"
 	"github.com/juju/juju/logfwd"
+	"github.com/juju/juju/logfwd/sinkconfig"
 	"github.com/juju/juju/logfwd/syslog"
 	coretesting "github.com/juju/juju/testing"
 	"github.com/juju/juju/version"
@@ -86,8 +87,8 @@ func (s *LogForwarderSuite) newLogForwarderArgsWithAPI(
 		LogForwardConfig: configAPI,
 		AllModels:        true,
 		ControllerUUID:   "feebdaed-2f18-4fd2-967d-db9663db7bea",
-		OpenSink: func(cfg *syslog.RawConfig) (*logforwarder.LogSink, error) {
-			sender.host = cfg.Host
+		OpenSink: func(cfg *sinkconfig.Config) (*logforwarder.LogSink, error) {
+			sender.host = cfg.Syslog.Host
 			sink := &logforwarder.LogSink{
 				sender,
 			}
@@ -207,10 +208,116 @@ func (s *LogForwarderSuite) TestSenderError(c *gc.C) {
 	})
 }
 
+func (s *LogForwarderSuite) TestBatching(c *gc.C) {
+	rec0 := s.rec
+	rec1 := s.rec
+	rec1.ID = 11
+	rec2 := s.rec
+	rec2.ID = 12
+
+	clock := testing.NewClock(time.Now())
+	api := &mockLogForwardConfig{
+		enabled:   true,
+		host:      "10.0.0.1",
+		batchSize: 2,
+	}
+	args := s.newLogForwarderArgsWithAPI(c, api, s.stream, s.sender)
+	args.Clock = clock
+	lf, err := logforwarder.NewLogForwarder(args)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, lf)
+
+	// A partial batch is sent once the flush interval has passed.
+	s.stream.addRecords(c, rec0)
+	err = clock.WaitAdvance(time.Second, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.sender.waitForSend(c)
+
+	// A full batch is sent straight away.
+	s.stream.addRecords(c, rec1, rec2)
+	s.sender.waitForSend(c)
+
+	workertest.CleanKill(c, lf)
+	s.sender.stub.CheckCalls(c, []testing.StubCall{
+		{"Send", []interface{}{[]logfwd.Record{rec0}}},
+		{"Send", []interface{}{[]logfwd.Record{rec1, rec2}}},
+		{"Close", nil},
+	})
+}
+
+func (s *LogForwarderSuite) TestSenderRetry(c *gc.C) {
+	s.sender.stub.SetErrors(errors.New("<failure>"))
+
+	clock := testing.NewClock(time.Now())
+	api := &mockLogForwardConfig{
+		enabled:       true,
+		host:          "10.0.0.1",
+		retryAttempts: 1,
+	}
+	args := s.newLogForwarderArgsWithAPI(c, api, s.stream, s.sender)
+	args.Clock = clock
+	lf, err := logforwarder.NewLogForwarder(args)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, lf)
+
+	s.stream.addRecords(c, s.rec)
+	s.sender.waitForSend(c)
+	err = clock.WaitAdvance(sinkconfig.DefaultRetryDelay, coretesting.LongWait, 1)
+	c.Assert(err, jc.ErrorIsNil)
+	s.sender.waitForSend(c)
+
+	workertest.CleanKill(c, lf)
+	s.sender.stub.CheckCalls(c, []testing.StubCall{
+		{"Send", []interface{}{[]logfwd.Record{s.rec}}},
+		{"Send", []interface{}{[]logfwd.Record{s.rec}}},
+		{"Close", nil},
+	})
+}
+
+func (s *LogForwarderSuite) TestSenderRetryPartial(c *gc.C) {
+	rec0 := s.rec
+	rec1 := s.rec
+	rec1.ID = 11
+	s.sender.stub.SetErrors(&logfwd.PartialSendError{
+		Sent: 1,
+		Err:  errors.New("<failure>"),
+	})
+
+	clock := testing.NewClock(time.Now())
+	api := &mockLogForwardConfig{
+		enabled:       true,
+		host:          "10.0.0.1",
+		batchSize:     2,
+		retryAttempts: 1,
+	}
+	args := s.newLogForwarderArgsWithAPI(c, api, s.stream, s.sender)
+	args.Clock = clock
+	lf, err := logforwarder.NewLogForwarder(args)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, lf)
+
+	s.stream.addRecords(c, rec0, rec1)
+	s.sender.waitForSend(c)
+	// Both the batch's flush timer and the retry delay are waiting.
+	err = clock.WaitAdvance(sinkconfig.DefaultRetryDelay, coretesting.LongWait, 2)
+	c.Assert(err, jc.ErrorIsNil)
+	s.sender.waitForSend(c)
+
+	// Only the record that wasn't sent is resent.
+	workertest.CleanKill(c, lf)
+	s.sender.stub.CheckCalls(c, []testing.StubCall{
+		{"Send", []interface{}{[]logfwd.Record{rec0, rec1}}},
+		{"Send", []interface{}{[]logfwd.Record{rec1}}},
+		{"Close", nil},
+	})
+}
+
 type mockLogForwardConfig struct {
-	enabled bool
-	host    string
-	changes chan struct{}
+	enabled       bool
+	host          string
+	batchSize     int
+	retryAttempts int
+	changes       chan struct{}
 }
 
 type mockWatcher struct {
@@ -249,13 +356,17 @@ func (c *mockLogForwardConfig) WatchForLogForwardConfigChanges() (watcher.Notify
 	}, nil
 }
 
-func (c *mockLogForwardConfig) LogForwardConfig() (*syslog.RawConfig, bool, error) {
-	return &syslog.RawConfig{
-		Enabled:    c.enabled,
-		Host:       c.host,
-		CACert:     coretesting.CACert,
-		ClientCert: coretesting.ServerCert,
-		ClientKey:  coretesting.ServerKey,
+func (c *mockLogForwardConfig) LogForwardConfig() (*sinkconfig.Config, bool, error) {
+	return &sinkconfig.Config{
+		Enabled: c.enabled,
+		Syslog: syslog.RawConfig{
+			Host:       c.host,
+			CACert:     coretesting.CACert,
+			ClientCert: coretesting.ServerCert,
+			ClientKey:  coretesting.ServerKey,
+		},
+		BatchSize:     c.batchSize,
+		RetryAttempts: c.retryAttempts,
 	}, true, nil
 }
 
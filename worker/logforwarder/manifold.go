@@ -5,6 +5,7 @@ package logforwarder
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
 	worker "gopkg.in/juju/worker.v1"
 
 	apiagent "github.com/juju/juju/api/agent"
@@ -31,6 +32,10 @@ type ManifoldConfig struct {
 
 	// OpenLogForwarder opens each log forwarder that will be used.
 	OpenLogForwarder func(OpenLogForwarderArgs) (*LogForwarder, error)
+
+	// Clock is used by the log forwarders to time batching and
+	// retries.
+	Clock clock.Clock
 }
 
 // Manifold returns a dependency manifold that runs a log forwarding
@@ -70,8 +75,10 @@ func Manifold(config ManifoldConfig) dependency.Manifold {
 				LogForwardConfig: agentFacade,
 				Caller:           apiCaller,
 				Sinks:            config.Sinks,
+				ForwardAudit:     controllerCfg.AuditingEnabled(),
 				OpenLogStream:    openLogStream,
 				OpenLogForwarder: openForwarder,
+				Clock:            config.Clock,
 			})
 			return orchestrator, errors.Annotate(err, "creating log forwarding orchestrator")
 		},
@@ -5,12 +5,19 @@ package logforwarder
 
 import (
 	"github.com/juju/errors"
+	"github.com/juju/utils/clock"
 
 	"github.com/juju/juju/api/base"
+	"github.com/juju/juju/worker/catacomb"
 )
 
+// auditSinkSuffix is appended to a sink's name to give the name under
+// which forwarded audit entries are tracked.
+const auditSinkSuffix = "-audit"
+
 type orchestrator struct {
-	*LogForwarder // For now its just a single forwarder.
+	catacomb   catacomb.Catacomb
+	forwarders []*LogForwarder
 }
 
 // OrchestratorArgs holds the info needed to open a log forwarding
@@ -29,16 +36,24 @@ type OrchestratorArgs struct {
 	// to which log records will be forwarded.
 	Sinks []LogSinkSpec
 
+	// ForwardAudit indicates that audit log entries should also be
+	// forwarded to the log sinks.
+	ForwardAudit bool
+
 	// OpenLogStream is the function that will be used to for the
 	// log stream.
 	OpenLogStream LogStreamFn
 
 	// OpenLogForwarder opens each log forwarder that will be used.
 	OpenLogForwarder func(OpenLogForwarderArgs) (*LogForwarder, error)
+
+	// Clock is used by the log forwarders to time batching and
+	// retries.
+	Clock clock.Clock
 }
 
 func newOrchestratorForController(args OrchestratorArgs) (*orchestrator, error) {
-	// For now we work with only 1 forwarder. Later we can have a proper
+	// For now we work with only 1 sink. Later we can have a proper
 	// orchestrator that spawns a sub-worker for each log sink.
 	if len(args.Sinks) == 0 {
 		return nil, nil
@@ -46,7 +61,7 @@ func newOrchestratorForController(args OrchestratorArgs) (*orchestrator, error)
 	if len(args.Sinks) > 1 {
 		return nil, errors.Errorf("multiple log forwarding targets not supported (yet)")
 	}
-	lf, err := args.OpenLogForwarder(OpenLogForwarderArgs{
+	forwarderArgs := []OpenLogForwarderArgs{{
 		AllModels:        true,
 		ControllerUUID:   args.ControllerUUID,
 		LogForwardConfig: args.LogForwardConfig,
@@ -54,6 +69,60 @@ func newOrchestratorForController(args OrchestratorArgs) (*orchestrator, error)
 		Name:             args.Sinks[0].Name,
 		OpenSink:         args.Sinks[0].OpenFn,
 		OpenLogStream:    args.OpenLogStream,
+		Clock:            args.Clock,
+	}}
+	if args.ForwardAudit {
+		auditArgs := forwarderArgs[0]
+		auditArgs.Name += auditSinkSuffix
+		auditArgs.Audit = true
+		forwarderArgs = append(forwarderArgs, auditArgs)
+	}
+
+	o := &orchestrator{}
+	for _, lfArgs := range forwarderArgs {
+		lf, err := args.OpenLogForwarder(lfArgs)
+		if err != nil {
+			o.stopForwarders()
+			return nil, errors.Annotate(err, "opening log forwarder")
+		}
+		o.forwarders = append(o.forwarders, lf)
+	}
+	err := catacomb.Invoke(catacomb.Plan{
+		Site: &o.catacomb,
+		Work: o.loop,
 	})
-	return &orchestrator{lf}, errors.Annotate(err, "opening log forwarder")
+	if err != nil {
+		o.stopForwarders()
+		return nil, errors.Trace(err)
+	}
+	return o, nil
+}
+
+func (o *orchestrator) loop() error {
+	for _, lf := range o.forwarders {
+		if err := o.catacomb.Add(lf); err != nil {
+			return errors.Trace(err)
+		}
+	}
+	<-o.catacomb.Dying()
+	return o.catacomb.ErrDying()
+}
+
+func (o *orchestrator) stopForwarders() {
+	for _, lf := range o.forwarders {
+		lf.Kill()
+		if err := lf.Wait(); err != nil {
+			logger.Errorf("stopping log forwarder %q: %v", lf.args.Name, err)
+		}
+	}
+}
+
+// Kill implements Worker.Kill()
+func (o *orchestrator) Kill() {
+	o.catacomb.Kill(nil)
+}
+
+// Wait implements Worker.Wait()
+func (o *orchestrator) Wait() error {
+	return o.catacomb.Wait()
 }
@@ -0,0 +1,148 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package logforwarder_test
+
+import (
+	"github.com/juju/errors"
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd/sinkconfig"
+	"github.com/juju/juju/watcher"
+	"github.com/juju/juju/worker/logforwarder"
+	"github.com/juju/juju/worker/workertest"
+)
+
+type OrchestratorSuite struct {
+	testing.IsolationSuite
+
+	stub       *testing.Stub
+	config     logforwarder.LogForwardConfig
+	forwarders []*logforwarder.LogForwarder
+}
+
+var _ = gc.Suite(&OrchestratorSuite{})
+
+func (s *OrchestratorSuite) SetUpTest(c *gc.C) {
+	s.IsolationSuite.SetUpTest(c)
+	s.stub = &testing.Stub{}
+	s.config = &mockLogForwardConfig{}
+	s.forwarders = nil
+}
+
+func (s *OrchestratorSuite) args(sinkNames ...string) logforwarder.OrchestratorArgs {
+	var sinks []logforwarder.LogSinkSpec
+	for _, name := range sinkNames {
+		sinks = append(sinks, logforwarder.LogSinkSpec{
+			Name: name,
+			OpenFn: func(*sinkconfig.Config) (*logforwarder.LogSink, error) {
+				return nil, errors.New("unexpected sink")
+			},
+		})
+	}
+	return logforwarder.OrchestratorArgs{
+		ControllerUUID:   "feebdaed-2f18-4fd2-967d-db9663db7bea",
+		LogForwardConfig: s.config,
+		Caller:           &mockCaller{},
+		Sinks:            sinks,
+		OpenLogForwarder: s.openLogForwarder,
+	}
+}
+
+func (s *OrchestratorSuite) openLogForwarder(args logforwarder.OpenLogForwarderArgs) (*logforwarder.LogForwarder, error) {
+	s.stub.AddCall("OpenLogForwarder", args.Name, args.Audit, args.AllModels)
+	if err := s.stub.NextErr(); err != nil {
+		return nil, err
+	}
+	lf, err := logforwarder.NewLogForwarder(args)
+	if err != nil {
+		return nil, err
+	}
+	s.forwarders = append(s.forwarders, lf)
+	return lf, nil
+}
+
+func (s *OrchestratorSuite) TestNoSinks(c *gc.C) {
+	w, err := logforwarder.NewOrchestratorForController(s.args())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(w, gc.IsNil)
+	s.stub.CheckNoCalls(c)
+}
+
+func (s *OrchestratorSuite) TestMultipleSinks(c *gc.C) {
+	_, err := logforwarder.NewOrchestratorForController(s.args("spam", "eggs"))
+	c.Assert(err, gc.ErrorMatches, `multiple log forwarding targets not supported \(yet\)`)
+	s.stub.CheckNoCalls(c)
+}
+
+func (s *OrchestratorSuite) TestStartsForwarder(c *gc.C) {
+	w, err := logforwarder.NewOrchestratorForController(s.args("spam"))
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{"OpenLogForwarder", []interface{}{"spam", false, true}},
+	})
+	workertest.CheckAlive(c, w)
+	workertest.CleanKill(c, w)
+	c.Assert(s.forwarders, gc.HasLen, 1)
+	c.Assert(workertest.CheckKilled(c, s.forwarders[0]), jc.ErrorIsNil)
+}
+
+func (s *OrchestratorSuite) TestStartsAuditForwarder(c *gc.C) {
+	args := s.args("spam")
+	args.ForwardAudit = true
+	w, err := logforwarder.NewOrchestratorForController(args)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	s.stub.CheckCalls(c, []testing.StubCall{
+		{"OpenLogForwarder", []interface{}{"spam", false, true}},
+		{"OpenLogForwarder", []interface{}{"spam-audit", true, true}},
+	})
+	workertest.CheckAlive(c, w)
+	workertest.CleanKill(c, w)
+	c.Assert(s.forwarders, gc.HasLen, 2)
+	for _, lf := range s.forwarders {
+		c.Check(workertest.CheckKilled(c, lf), jc.ErrorIsNil)
+	}
+}
+
+func (s *OrchestratorSuite) TestOpenForwarderError(c *gc.C) {
+	s.stub.SetErrors(nil, errors.New("boom"))
+	args := s.args("spam")
+	args.ForwardAudit = true
+	_, err := logforwarder.NewOrchestratorForController(args)
+	c.Assert(err, gc.ErrorMatches, "opening log forwarder: boom")
+
+	// The forwarder already opened is stopped.
+	c.Assert(s.forwarders, gc.HasLen, 1)
+	c.Assert(workertest.CheckKilled(c, s.forwarders[0]), jc.ErrorIsNil)
+}
+
+func (s *OrchestratorSuite) TestForwarderError(c *gc.C) {
+	failure := errors.New("<failure>")
+	s.config = &failingLogForwardConfig{err: failure}
+	args := s.args("spam")
+	args.ForwardAudit = true
+	w, err := logforwarder.NewOrchestratorForController(args)
+	c.Assert(err, jc.ErrorIsNil)
+	defer workertest.DirtyKill(c, w)
+
+	err = workertest.CheckKilled(c, w)
+	c.Assert(errors.Cause(err), gc.Equals, failure)
+	for _, lf := range s.forwarders {
+		workertest.CheckKilled(c, lf)
+	}
+}
+
+type failingLogForwardConfig struct {
+	mockLogForwardConfig
+	err error
+}
+
+func (c *failingLogForwardConfig) WatchForLogForwardConfigChanges() (watcher.NotifyWatcher, error) {
+	return nil, c.err
+}
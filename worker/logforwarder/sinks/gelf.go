@@ -0,0 +1,25 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sinks
+
+import (
+	"crypto/tls"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/logfwd/gelf"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+// OpenGELF returns a sink which sends log messages to a Graylog GELF
+// TCP input, using the given TLS config if the config asks for TLS.
+func OpenGELF(cfg *gelf.RawConfig, tlsCfg *tls.Config) (*logforwarder.LogSink, error) {
+	client, err := gelf.Open(*cfg, tlsCfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &logforwarder.LogSink{
+		SendCloser: client,
+	}, nil
+}
@@ -0,0 +1,25 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sinks
+
+import (
+	"crypto/tls"
+
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/logfwd/jsonhttp"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+// OpenHTTP returns a sink which posts batches of log messages to an
+// HTTP(S) endpoint, using the given TLS config for https URLs.
+func OpenHTTP(cfg *jsonhttp.RawConfig, tlsCfg *tls.Config) (*logforwarder.LogSink, error) {
+	client, err := jsonhttp.Open(*cfg, tlsCfg)
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	return &logforwarder.LogSink{
+		SendCloser: client,
+	}, nil
+}
@@ -0,0 +1,41 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sinks
+
+import (
+	"github.com/juju/errors"
+
+	"github.com/juju/juju/logfwd/sinkconfig"
+	"github.com/juju/juju/worker/logforwarder"
+)
+
+// Open returns a sink for the kind of log forwarding target described
+// by the config.
+func Open(cfg *sinkconfig.Config) (*logforwarder.LogSink, error) {
+	if !cfg.Enabled {
+		return nil, errors.New("log forwarding not enabled")
+	}
+	sinkType := cfg.SinkType()
+	if sinkType == sinkconfig.TypeSyslog {
+		syslogCfg := cfg.Syslog
+		syslogCfg.Enabled = true
+		sink, err := OpenSyslog(&syslogCfg)
+		return sink, errors.Trace(err)
+	}
+	// The other sinks share the one TLS config.
+	tlsCfg, err := cfg.TLS.ClientConfig()
+	if err != nil {
+		return nil, errors.Annotate(err, "constructing TLS config")
+	}
+	switch sinkType {
+	case sinkconfig.TypeHTTP:
+		sink, err := OpenHTTP(&cfg.HTTP, tlsCfg)
+		return sink, errors.Trace(err)
+	case sinkconfig.TypeGELF:
+		sink, err := OpenGELF(&cfg.GELF, tlsCfg)
+		return sink, errors.Trace(err)
+	default:
+		return nil, errors.NotSupportedf("log forwarding to %q", sinkType)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package sinks_test
+
+import (
+	"github.com/juju/testing"
+	jc "github.com/juju/testing/checkers"
+	gc "gopkg.in/check.v1"
+
+	"github.com/juju/juju/logfwd/jsonhttp"
+	"github.com/juju/juju/logfwd/sinkconfig"
+	"github.com/juju/juju/worker/logforwarder/sinks"
+)
+
+type SinksSuite struct {
+	testing.IsolationSuite
+}
+
+var _ = gc.Suite(&SinksSuite{})
+
+func (s *SinksSuite) TestOpenNotEnabled(c *gc.C) {
+	_, err := sinks.Open(&sinkconfig.Config{
+		Type: sinkconfig.TypeHTTP,
+		HTTP: jsonhttp.RawConfig{URL: "https://logs.example.com"},
+	})
+
+	c.Check(err, gc.ErrorMatches, "log forwarding not enabled")
+}
+
+func (s *SinksSuite) TestOpenHTTP(c *gc.C) {
+	sink, err := sinks.Open(&sinkconfig.Config{
+		Enabled: true,
+		Type:    sinkconfig.TypeHTTP,
+		HTTP: jsonhttp.RawConfig{
+			URL:    "https://es.example.com/juju/_bulk",
+			Format: jsonhttp.FormatElasticsearch,
+		},
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	defer sink.Close()
+
+	client, ok := sink.SendCloser.(*jsonhttp.Client)
+	c.Assert(ok, jc.IsTrue)
+	c.Check(client.URL, gc.Equals, "https://es.example.com/juju/_bulk")
+	c.Check(client.Format, gc.Equals, jsonhttp.FormatElasticsearch)
+}
+
+func (s *SinksSuite) TestOpenUnknownType(c *gc.C) {
+	_, err := sinks.Open(&sinkconfig.Config{
+		Enabled: true,
+		Type:    "carrier-pigeon",
+	})
+
+	c.Check(err, gc.ErrorMatches, `log forwarding to "carrier-pigeon" not supported`)
+}
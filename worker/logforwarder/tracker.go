@@ -10,7 +10,7 @@ import (
 	"github.com/juju/juju/api/base"
 	logfwdapi "github.com/juju/juju/api/logfwd"
 	"github.com/juju/juju/logfwd"
-	"github.com/juju/juju/logfwd/syslog"
+	"github.com/juju/juju/logfwd/sinkconfig"
 )
 
 // TrackingSinkArgs holds the args to OpenTrackingSender.
@@ -19,7 +19,7 @@ type TrackingSinkArgs struct {
 	AllModels bool
 
 	// Config is the logging config that will be used.
-	Config *syslog.RawConfig
+	Config *sinkconfig.Config
 
 	// Caller is the API caller that will be used.
 	Caller base.APICaller
@@ -12,8 +12,6 @@ import (
 	"github.com/juju/errors"
 	"github.com/juju/loggo"
 	"github.com/juju/utils/clock"
-	"github.com/juju/version"
-	"gopkg.in/juju/charm.v6-unstable"
 	"gopkg.in/juju/names.v2"
 
 	"github.com/juju/juju/api"
@@ -22,8 +20,6 @@ import (
 	"github.com/juju/juju/apiserver/params"
 	coremigration "github.com/juju/juju/core/migration"
 	"github.com/juju/juju/migration"
-	"github.com/juju/juju/resource"
-	"github.com/juju/juju/tools"
 	"github.com/juju/juju/watcher"
 	"github.com/juju/juju/worker/catacomb"
 	"github.com/juju/juju/worker/fortress"
@@ -363,36 +359,6 @@ func (w *Worker) doIMPORT(targetInfo coremigration.TargetInfo, modelUUID string)
 	return coremigration.VALIDATION, nil
 }
 
-type uploadWrapper struct {
-	client    *migrationtarget.Client
-	modelUUID string
-}
-
-// UploadTools prepends the model UUID to the args passed to the migration client.
-func (w *uploadWrapper) UploadTools(r io.ReadSeeker, vers version.Binary, additionalSeries ...string) (tools.List, error) {
-	return w.client.UploadTools(w.modelUUID, r, vers, additionalSeries...)
-}
-
-// UploadCharm prepends the model UUID to the args passed to the migration client.
-func (w *uploadWrapper) UploadCharm(curl *charm.URL, content io.ReadSeeker) (*charm.URL, error) {
-	return w.client.UploadCharm(w.modelUUID, curl, content)
-}
-
-// UploadResource prepends the model UUID to the args passed to the migration client.
-func (w *uploadWrapper) UploadResource(res resource.Resource, content io.ReadSeeker) error {
-	return w.client.UploadResource(w.modelUUID, res, content)
-}
-
-// SetPlaceholderResource prepends the model UUID to the args passed to the migration client.
-func (w *uploadWrapper) SetPlaceholderResource(res resource.Resource) error {
-	return w.client.SetPlaceholderResource(w.modelUUID, res)
-}
-
-// SetUnitResource prepends the model UUID to the args passed to the migration client.
-func (w *uploadWrapper) SetUnitResource(unitName string, res resource.Resource) error {
-	return w.client.SetUnitResource(w.modelUUID, unitName, res)
-}
-
 func (w *Worker) transferModel(targetInfo coremigration.TargetInfo, modelUUID string) error {
 	w.setInfoStatus("exporting model")
 	serialized, err := w.config.Facade.Export()
@@ -413,7 +379,7 @@ func (w *Worker) transferModel(targetInfo coremigration.TargetInfo, modelUUID st
 	}
 
 	w.setInfoStatus("uploading model binaries into target controller")
-	wrapper := &uploadWrapper{targetClient, modelUUID}
+	wrapper := migrationtarget.NewUploader(targetClient, modelUUID)
 	err = w.config.UploadBinaries(migration.UploadBinariesConfig{
 		Charms:          serialized.Charms,
 		CharmDownloader: w.config.CharmDownloader,
@@ -104,6 +104,16 @@ func (opc *operationCallbacks) FailAction(actionId, message string) error {
 	return err
 }
 
+// ActionCancelled is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) ActionCancelled(actionId string) <-chan struct{} {
+	return opc.u.actionCancellations.Cancelled(actionId)
+}
+
+// ActionFinished is part of the operation.Callbacks interface.
+func (opc *operationCallbacks) ActionFinished(actionId string) {
+	opc.u.actionCancellations.Finished(actionId)
+}
+
 // GetArchiveInfo is part of the operation.Callbacks interface.
 func (opc *operationCallbacks) GetArchiveInfo(charmURL *corecharm.URL) (charm.BundleInfo, error) {
 	ch, err := opc.u.st.Charm(charmURL)
@@ -148,6 +148,16 @@ type Callbacks interface {
 	// RunActions operations.
 	FailAction(actionId, message string) error
 
+	// ActionCancelled returns a channel that is closed when the supplied
+	// action is cancelled while it runs. It's only used by RunActions
+	// operations.
+	ActionCancelled(actionId string) <-chan struct{}
+
+	// ActionFinished releases the resources used to watch for the
+	// cancellation of the supplied action once it has stopped running.
+	// It's only used by RunActions operations.
+	ActionFinished(actionId string)
+
 	// GetArchiveInfo is used to find out how to download a charm archive. It's
 	// only used by Deploy operations.
 	GetArchiveInfo(charmURL *corecharm.URL) (charm.BundleInfo, error)
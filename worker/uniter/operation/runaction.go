@@ -72,7 +72,13 @@ func (ra *runAction) Execute(state State) (*State, error) {
 		return nil, err
 	}
 
-	err := ra.runner.RunAction(ra.name)
+	actionData, err := ra.runner.Context().ActionData()
+	if err != nil {
+		return nil, errors.Trace(err)
+	}
+	actionData.Cancel = ra.callbacks.ActionCancelled(ra.actionId)
+
+	err = ra.runner.RunAction(ra.name)
 	if err != nil {
 		// This indicates an actual error -- an action merely failing should
 		// be handled inside the Runner, and returned as nil.
@@ -89,6 +95,7 @@ func (ra *runAction) Execute(state State) (*State, error) {
 // Commit preserves the recorded hook, and returns a neutral state.
 // Commit is part of the Operation interface.
 func (ra *runAction) Commit(state State) (*State, error) {
+	ra.callbacks.ActionFinished(ra.actionId)
 	return stateChange{
 		Kind: continuationKind(state),
 		Step: Pending,
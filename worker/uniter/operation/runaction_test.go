@@ -227,6 +227,25 @@ func (s *RunActionSuite) TestExecuteSuccess(c *gc.C) {
 	}
 }
 
+func (s *RunActionSuite) TestExecuteWatchesCancellation(c *gc.C) {
+	runnerFactory := NewRunActionRunnerFactory(nil)
+	callbacks := &RunActionCallbacks{cancelled: make(chan struct{})}
+	factory := operation.NewFactory(operation.FactoryParams{
+		RunnerFactory: runnerFactory,
+		Callbacks:     callbacks,
+	})
+	op, err := factory.NewAction(someActionId)
+	c.Assert(err, jc.ErrorIsNil)
+	midState, err := op.Prepare(operation.State{})
+	c.Assert(err, jc.ErrorIsNil)
+
+	_, err = op.Execute(*midState)
+	c.Assert(err, jc.ErrorIsNil)
+	actionData, err := runnerFactory.MockNewActionRunner.runner.Context().ActionData()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(actionData.Cancel, gc.Equals, (<-chan struct{})(callbacks.cancelled))
+}
+
 func (s *RunActionSuite) TestCommit(c *gc.C) {
 	var stateChangeTests = []struct {
 		description string
@@ -272,12 +291,16 @@ func (s *RunActionSuite) TestCommit(c *gc.C) {
 
 	for i, test := range stateChangeTests {
 		c.Logf("test %d: %s", i, test.description)
-		factory := operation.NewFactory(operation.FactoryParams{})
+		callbacks := &RunActionCallbacks{}
+		factory := operation.NewFactory(operation.FactoryParams{
+			Callbacks: callbacks,
+		})
 		op, err := factory.NewAction(someActionId)
 		c.Assert(err, jc.ErrorIsNil)
 
 		newState, err := op.Commit(test.before)
 		c.Assert(newState, jc.DeepEquals, &test.after)
+		c.Assert(callbacks.finished, jc.DeepEquals, []string{someActionId})
 	}
 }
 
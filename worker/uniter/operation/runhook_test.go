@@ -10,6 +10,7 @@ import (
 	gc "gopkg.in/check.v1"
 	"gopkg.in/juju/charm.v6-unstable/hooks"
 
+	"github.com/juju/juju/status"
 	"github.com/juju/juju/worker/uniter/hook"
 	"github.com/juju/juju/worker/uniter/operation"
 	"github.com/juju/juju/worker/uniter/runner/context"
@@ -24,6 +25,12 @@ var _ = gc.Suite(&RunHookSuite{})
 
 type newHook func(operation.Factory, hook.Info) (operation.Operation, error)
 
+func (s *RunHookSuite) TestRunningInstallHookMessage(c *gc.C) {
+	// The status package recognises units installing by this message.
+	message := operation.RunningHookMessage(string(hooks.Install))
+	c.Assert(message, gc.Equals, status.MessageRunningInstallHook)
+}
+
 func (s *RunHookSuite) testPrepareHookError(
 	c *gc.C, newHook newHook, expectClearResolvedFlag, expectSkip bool,
 ) {
@@ -116,12 +116,22 @@ type RunActionCallbacks struct {
 	operation.Callbacks
 	*MockFailAction
 	executingMessage string
+	cancelled        chan struct{}
+	finished         []string
 }
 
 func (cb *RunActionCallbacks) FailAction(actionId, message string) error {
 	return cb.MockFailAction.Call(actionId, message)
 }
 
+func (cb *RunActionCallbacks) ActionCancelled(actionId string) <-chan struct{} {
+	return cb.cancelled
+}
+
+func (cb *RunActionCallbacks) ActionFinished(actionId string) {
+	cb.finished = append(cb.finished, actionId)
+}
+
 func (cb *RunActionCallbacks) SetExecutingStatus(message string) error {
 	cb.executingMessage = message
 	return nil
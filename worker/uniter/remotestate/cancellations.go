@@ -0,0 +1,71 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package remotestate
+
+import "sync"
+
+// ActionCancellations records requests to cancel a unit's running
+// actions, and tells the actions about them. It is shared by all of a
+// uniter's RemoteStateWatchers, so that a cancellation seen by a
+// restarted watcher still closes the channels handed out before.
+type ActionCancellations struct {
+	mu        sync.Mutex
+	channels  map[string]chan struct{}
+	cancelled map[string]bool
+}
+
+// NewActionCancellations returns a new, empty ActionCancellations.
+func NewActionCancellations() *ActionCancellations {
+	return &ActionCancellations{
+		channels:  make(map[string]chan struct{}),
+		cancelled: make(map[string]bool),
+	}
+}
+
+// Cancelled returns a channel that is closed when cancellation of the
+// running action with the given id is requested.
+func (c *ActionCancellations) Cancelled(actionId string) <-chan struct{} {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch, ok := c.channels[actionId]
+	if !ok {
+		ch = make(chan struct{})
+		c.channels[actionId] = ch
+		if c.cancelled[actionId] {
+			close(ch)
+		}
+	}
+	return ch
+}
+
+// Finished forgets about the action with the given id, which has
+// stopped running. Only one action runs at a time, so any other
+// cancellations that no running action has asked about are stale, and
+// are forgotten too.
+func (c *ActionCancellations) Finished(actionId string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.channels, actionId)
+	for id := range c.cancelled {
+		if _, ok := c.channels[id]; !ok {
+			delete(c.cancelled, id)
+		}
+	}
+}
+
+// cancel records that cancellation of the actions with the given ids
+// has been requested, closing any channels returned for them.
+func (c *ActionCancellations) cancel(actionIds []string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, id := range actionIds {
+		if c.cancelled[id] {
+			continue
+		}
+		c.cancelled[id] = true
+		if ch, ok := c.channels[id]; ok {
+			close(ch)
+		}
+	}
+}
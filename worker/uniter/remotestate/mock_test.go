@@ -191,6 +191,8 @@ type mockUnit struct {
 	configSettingsWatcher *mockNotifyWatcher
 	storageWatcher        *mockStringsWatcher
 	actionWatcher         *mockStringsWatcher
+
+	actionCancellationsWatcher *mockStringsWatcher
 }
 
 func (u *mockUnit) Life() params.Life {
@@ -233,6 +235,10 @@ func (u *mockUnit) WatchActionNotifications() (watcher.StringsWatcher, error) {
 	return u.actionWatcher, nil
 }
 
+func (u *mockUnit) WatchActionCancellations() (watcher.StringsWatcher, error) {
+	return u.actionCancellationsWatcher, nil
+}
+
 type mockService struct {
 	tag                   names.ApplicationTag
 	life                  params.Life
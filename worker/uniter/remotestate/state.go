@@ -32,6 +32,7 @@ type Unit interface {
 	WatchConfigSettings() (watcher.NotifyWatcher, error)
 	WatchStorage() (watcher.StringsWatcher, error)
 	WatchActionNotifications() (watcher.StringsWatcher, error)
+	WatchActionCancellations() (watcher.StringsWatcher, error)
 }
 
 type Application interface {
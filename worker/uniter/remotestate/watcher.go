@@ -37,6 +37,8 @@ type RemoteStateWatcher struct {
 	commandChannel            <-chan string
 	retryHookChannel          <-chan struct{}
 
+	actionCancellations *ActionCancellations
+
 	catacomb catacomb.Catacomb
 
 	out     chan struct{}
@@ -53,6 +55,10 @@ type WatcherConfig struct {
 	CommandChannel      <-chan string
 	RetryHookChannel    <-chan struct{}
 	UnitTag             names.UnitTag
+
+	// ActionCancellations, if not nil, is told about requests to
+	// cancel running actions. It should outlive the watcher.
+	ActionCancellations *ActionCancellations
 }
 
 // NewWatcher returns a RemoteStateWatcher that handles state changes pertaining to the
@@ -68,6 +74,7 @@ func NewWatcher(config WatcherConfig) (*RemoteStateWatcher, error) {
 		updateStatusChannel:       config.UpdateStatusChannel,
 		commandChannel:            config.CommandChannel,
 		retryHookChannel:          config.RetryHookChannel,
+		actionCancellations:       config.ActionCancellations,
 		// Note: it is important that the out channel be buffered!
 		// The remote state watcher will perform a non-blocking send
 		// on the channel to wake up the observer. It is non-blocking
@@ -78,6 +85,9 @@ func NewWatcher(config WatcherConfig) (*RemoteStateWatcher, error) {
 			Storage:   make(map[names.StorageTag]StorageSnapshot),
 		},
 	}
+	if w.actionCancellations == nil {
+		w.actionCancellations = NewActionCancellations()
+	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &w.catacomb,
 		Work: func() error {
@@ -257,6 +267,24 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 	}
 	requiredEvents++
 
+	var (
+		seenActionCancellationsChange bool
+		actionCancellationsChanges    watcher.StringsChannel
+	)
+	actionCancellationsw, err := w.unit.WatchActionCancellations()
+	if errors.IsNotImplemented(err) {
+		// The controller is too old to cancel running actions.
+		logger.Debugf("not watching action cancellations: %v", err)
+	} else if err != nil {
+		return errors.Trace(err)
+	} else {
+		if err := w.catacomb.Add(actionCancellationsw); err != nil {
+			return errors.Trace(err)
+		}
+		actionCancellationsChanges = actionCancellationsw.Changes()
+		requiredEvents++
+	}
+
 	var seenLeadershipChange bool
 	// There's no watcher for this per se; we wait on a channel
 	// returned by the leadership tracker.
@@ -365,6 +393,16 @@ func (w *RemoteStateWatcher) loop(unitTag names.UnitTag) (err error) {
 			}
 			observedEvent(&seenActionsChange)
 
+		case actions, ok := <-actionCancellationsChanges:
+			logger.Debugf("got action cancellations change: %v ok=%t", actions, ok)
+			if !ok {
+				return errors.New("action cancellations watcher closed")
+			}
+			if err := w.actionCancellationsChanged(actions); err != nil {
+				return errors.Trace(err)
+			}
+			observedEvent(&seenActionCancellationsChange)
+
 		case keys, ok := <-relationsw.Changes():
 			logger.Debugf("got relations change: ok=%t", ok)
 			if !ok {
@@ -639,6 +677,13 @@ func (w *RemoteStateWatcher) actionsChanged(actions []string) error {
 	return nil
 }
 
+// actionCancellationsChanged responds to requests to cancel running
+// actions, closing the channels returned by ActionCancellations for them.
+func (w *RemoteStateWatcher) actionCancellationsChanged(actions []string) error {
+	w.actionCancellations.cancel(actions)
+	return nil
+}
+
 // storageChanged responds to unit storage changes.
 func (w *RemoteStateWatcher) storageChanged(keys []string) error {
 	tags := make([]names.StorageTag, len(keys))
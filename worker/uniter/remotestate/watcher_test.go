@@ -21,10 +21,11 @@ import (
 type WatcherSuite struct {
 	coretesting.BaseSuite
 
-	st         *mockState
-	leadership *mockLeadershipTracker
-	watcher    *remotestate.RemoteStateWatcher
-	clock      *testing.Clock
+	st            *mockState
+	leadership    *mockLeadershipTracker
+	watcher       *remotestate.RemoteStateWatcher
+	clock         *testing.Clock
+	cancellations *remotestate.ActionCancellations
 }
 
 // Duration is arbitrary, we'll trigger the ticker
@@ -53,6 +54,8 @@ func (s *WatcherSuite) SetUpTest(c *gc.C) {
 			configSettingsWatcher: newMockNotifyWatcher(),
 			storageWatcher:        newMockStringsWatcher(),
 			actionWatcher:         newMockStringsWatcher(),
+
+			actionCancellationsWatcher: newMockStringsWatcher(),
 		},
 		relations:                 make(map[names.RelationTag]*mockRelation),
 		storageAttachment:         make(map[params.StorageAttachmentId]params.StorageAttachment),
@@ -67,18 +70,23 @@ func (s *WatcherSuite) SetUpTest(c *gc.C) {
 	}
 
 	s.clock = testing.NewClock(time.Now())
+	s.cancellations = remotestate.NewActionCancellations()
+	s.watcher = s.newWatcher(c)
+}
+
+func (s *WatcherSuite) newWatcher(c *gc.C) *remotestate.RemoteStateWatcher {
 	statusTicker := func() <-chan time.Time {
 		return s.clock.After(statusTickDuration)
 	}
-
 	w, err := remotestate.NewWatcher(remotestate.WatcherConfig{
 		State:               s.st,
 		LeadershipTracker:   s.leadership,
 		UnitTag:             s.st.unit.tag,
 		UpdateStatusChannel: statusTicker,
+		ActionCancellations: s.cancellations,
 	})
 	c.Assert(err, jc.ErrorIsNil)
-	s.watcher = w
+	return w
 }
 
 func (s *WatcherSuite) TearDownTest(c *gc.C) {
@@ -107,6 +115,7 @@ func (s *WatcherSuite) TestInitialSignal(c *gc.C) {
 	s.st.unit.configSettingsWatcher.changes <- struct{}{}
 	s.st.unit.storageWatcher.changes <- []string{}
 	s.st.unit.actionWatcher.changes <- []string{}
+	s.st.unit.actionCancellationsWatcher.changes <- []string{}
 	s.st.unit.service.serviceWatcher.changes <- struct{}{}
 	s.st.unit.service.leaderSettingsWatcher.changes <- struct{}{}
 	s.st.unit.service.relationsWatcher.changes <- []string{}
@@ -120,6 +129,7 @@ func signalAll(st *mockState, l *mockLeadershipTracker) {
 	st.unit.configSettingsWatcher.changes <- struct{}{}
 	st.unit.storageWatcher.changes <- []string{}
 	st.unit.actionWatcher.changes <- []string{}
+	st.unit.actionCancellationsWatcher.changes <- []string{}
 	st.unit.service.serviceWatcher.changes <- struct{}{}
 	st.unit.service.leaderSettingsWatcher.changes <- struct{}{}
 	st.unit.service.relationsWatcher.changes <- []string{}
@@ -196,6 +206,62 @@ func (s *WatcherSuite) TestActionsReceived(c *gc.C) {
 	c.Assert(s.watcher.Snapshot().Actions, gc.DeepEquals, []string{"an-action"})
 }
 
+func (s *WatcherSuite) TestActionsCancelled(c *gc.C) {
+	signalAll(s.st, s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+
+	cancelled := s.cancellations.Cancelled("an-action")
+	assertNoNotifyEvent(c, cancelled, "action cancelled")
+
+	s.st.unit.actionCancellationsWatcher.changes <- []string{"an-action"}
+	assertNotifyEvent(c, cancelled, "waiting for action cancellation")
+
+	// Cancellations are remembered for later callers, and reported once.
+	assertNotifyEvent(c, s.cancellations.Cancelled("an-action"), "waiting for action cancellation")
+	s.st.unit.actionCancellationsWatcher.changes <- []string{"an-action"}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+}
+
+func (s *WatcherSuite) TestActionsCancelledBeforeRunning(c *gc.C) {
+	signalAll(s.st, s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+
+	s.st.unit.actionCancellationsWatcher.changes <- []string{"an-action"}
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	assertNotifyEvent(c, s.cancellations.Cancelled("an-action"), "waiting for action cancellation")
+}
+
+func (s *WatcherSuite) TestActionsCancelledAfterRestart(c *gc.C) {
+	signalAll(s.st, s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	cancelled := s.cancellations.Cancelled("an-action")
+
+	s.watcher.Kill()
+	c.Assert(s.watcher.Wait(), jc.ErrorIsNil)
+	s.watcher = s.newWatcher(c)
+	signalAll(s.st, s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+	assertNoNotifyEvent(c, cancelled, "action cancelled")
+
+	s.st.unit.actionCancellationsWatcher.changes <- []string{"an-action"}
+	assertNotifyEvent(c, cancelled, "waiting for action cancellation")
+}
+
+func (s *WatcherSuite) TestActionCancellationsFinished(c *gc.C) {
+	signalAll(s.st, s.leadership)
+	assertNotifyEvent(c, s.watcher.RemoteStateChanged(), "waiting for remote state change")
+
+	cancelled := s.cancellations.Cancelled("an-action")
+	s.st.unit.actionCancellationsWatcher.changes <- []string{"an-action", "stale-action"}
+	assertNotifyEvent(c, cancelled, "waiting for action cancellation")
+
+	// Once the action finishes, neither its cancellation nor the
+	// stale one for an action that is not running are remembered.
+	s.cancellations.Finished("an-action")
+	assertNoNotifyEvent(c, s.cancellations.Cancelled("an-action"), "action cancelled")
+	assertNoNotifyEvent(c, s.cancellations.Cancelled("stale-action"), "action cancelled")
+}
+
 func (s *WatcherSuite) TestClearResolvedMode(c *gc.C) {
 	s.st.unit.resolved = params.ResolvedRetryHooks
 	signalAll(s.st, s.leadership)
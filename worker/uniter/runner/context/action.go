@@ -4,6 +4,8 @@
 package context
 
 import (
+	"time"
+
 	"gopkg.in/juju/names.v2"
 )
 
@@ -15,6 +17,26 @@ type ActionData struct {
 	Failed         bool
 	ResultsMessage string
 	ResultsMap     map[string]interface{}
+
+	// Timeout is the time the Action may run for before it is
+	// killed; zero means it may run for as long as it likes.
+	Timeout time.Duration
+
+	// TimedOut records that the Action was killed because it ran
+	// for longer than Timeout.
+	TimedOut bool
+
+	// StreamOutput records that the output of the Action should be
+	// logged as messages while it runs.
+	StreamOutput bool
+
+	// Cancel, if not nil, is closed when the Action is cancelled
+	// while it runs.
+	Cancel <-chan struct{}
+
+	// Cancelled records that the Action was stopped because it was
+	// cancelled.
+	Cancelled bool
 }
 
 // NewActionData builds a suitable ActionData struct with no nil members.
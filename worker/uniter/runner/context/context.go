@@ -500,6 +500,25 @@ func (ctx *HookContext) SetActionMessage(message string) error {
 	return nil
 }
 
+// LogActionMessage records a progress message for the running action.
+// Unlike results, which are only sent when the action finishes, the
+// message is sent straight away so that it can be followed live.
+func (ctx *HookContext) LogActionMessage(message string) error {
+	if ctx.actionData == nil {
+		return errors.New("not running an action")
+	}
+	return ctx.state.LogActionMessage(ctx.actionData.Tag, message)
+}
+
+// LogActionMessages records progress messages for the running action,
+// in a single call.
+func (ctx *HookContext) LogActionMessages(messages []string) error {
+	if ctx.actionData == nil {
+		return errors.New("not running an action")
+	}
+	return ctx.state.LogActionMessages(ctx.actionData.Tag, messages)
+}
+
 // SetActionFailed sets the fail state of the action.
 func (ctx *HookContext) SetActionFailed() error {
 	if ctx.actionData == nil {
@@ -746,6 +765,14 @@ func (ctx *HookContext) finalizeAction(err, unhandledErr error) error {
 		}
 		status = params.ActionFailed
 	}
+	if ctx.actionData.TimedOut {
+		message = fmt.Sprintf("action timed out after %v", ctx.actionData.Timeout)
+		status = params.ActionTimedOut
+	}
+	if ctx.actionData.Cancelled {
+		message = "action cancelled"
+		status = params.ActionCancelled
+	}
 
 	callErr := ctx.state.ActionFinish(tag, status, results, message)
 	if callErr != nil {
@@ -247,6 +247,90 @@ func (s *ContextFactorySuite) TestActionContext(c *gc.C) {
 	s.AssertNotStorageContext(c, ctx)
 }
 
+func (s *ContextFactorySuite) TestActionContextLogActionMessage(c *gc.C) {
+	s.SetCharm(c, "dummy")
+	action, err := s.State.EnqueueAction(s.unit.Tag(), "snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	_, err = action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+
+	actionData := &context.ActionData{
+		Name:       action.Name(),
+		Tag:        names.NewActionTag(action.Id()),
+		Params:     action.Parameters(),
+		ResultsMap: map[string]interface{}{},
+	}
+	ctx, err := s.factory.ActionContext(actionData)
+	c.Assert(err, jc.ErrorIsNil)
+
+	// The message is recorded straight away, before the action finishes.
+	err = ctx.LogActionMessage("working on it")
+	c.Assert(err, jc.ErrorIsNil)
+	action, err = s.State.Action(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	messages := action.Messages()
+	c.Assert(messages, gc.HasLen, 1)
+	c.Assert(messages[0].Message, gc.Equals, "working on it")
+}
+
+func (s *ContextFactorySuite) TestActionContextTimedOut(c *gc.C) {
+	s.SetCharm(c, "dummy")
+	action, err := s.State.EnqueueAction(s.unit.Tag(), "snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+
+	actionData := &context.ActionData{
+		Name:       action.Name(),
+		Tag:        names.NewActionTag(action.Id()),
+		Params:     action.Parameters(),
+		ResultsMap: map[string]interface{}{"partial": "result"},
+		Timeout:    time.Minute,
+	}
+	ctx, err := s.factory.ActionContext(actionData)
+	c.Assert(err, jc.ErrorIsNil)
+
+	actionData.TimedOut = true
+	err = ctx.Flush("snapshot", errors.New("signal: killed"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	action, err = s.State.Action(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Status(), gc.Equals, state.ActionTimedOut)
+	results, message := action.Results()
+	c.Assert(results, jc.DeepEquals, map[string]interface{}{"partial": "result"})
+	c.Assert(message, gc.Equals, "action timed out after 1m0s")
+}
+
+func (s *ContextFactorySuite) TestActionContextCancelled(c *gc.C) {
+	s.SetCharm(c, "dummy")
+	action, err := s.State.EnqueueAction(s.unit.Tag(), "snapshot", nil)
+	c.Assert(err, jc.ErrorIsNil)
+	action, err = action.Begin()
+	c.Assert(err, jc.ErrorIsNil)
+	action, err = action.Cancel("")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Status(), gc.Equals, state.ActionAborting)
+
+	actionData := &context.ActionData{
+		Name:       action.Name(),
+		Tag:        names.NewActionTag(action.Id()),
+		Params:     action.Parameters(),
+		ResultsMap: map[string]interface{}{"partial": "result"},
+	}
+	ctx, err := s.factory.ActionContext(actionData)
+	c.Assert(err, jc.ErrorIsNil)
+
+	actionData.Cancelled = true
+	err = ctx.Flush("snapshot", errors.New("signal: terminated"))
+	c.Assert(err, jc.ErrorIsNil)
+
+	action, err = s.State.Action(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(action.Status(), gc.Equals, state.ActionCancelled)
+	results, message := action.Results()
+	c.Assert(results, jc.DeepEquals, map[string]interface{}{"partial": "result"})
+	c.Assert(message, gc.Equals, "action cancelled")
+}
+
 func (s *ContextFactorySuite) TestCommandContext(c *gc.C) {
 	ctx, err := s.factory.CommandContext(context.CommandInfo{RelationId: -1})
 	c.Assert(err, jc.ErrorIsNil)
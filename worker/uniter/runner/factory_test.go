@@ -252,6 +252,32 @@ func (s *FactorySuite) TestNewActionRunnerGood(c *gc.C) {
 	}
 }
 
+func (s *FactorySuite) TestNewActionRunnerTimeout(c *gc.C) {
+	s.SetCharm(c, "dummy")
+	action, err := s.State.EnqueueActionWithOptions(s.unit.Tag(), "snapshot", nil, state.ActionOptions{
+		Timeout: 10 * time.Minute,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	rnr, err := s.factory.NewActionRunner(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := rnr.Context().ActionData()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.Timeout, gc.Equals, 10*time.Minute)
+}
+
+func (s *FactorySuite) TestNewActionRunnerStreamOutput(c *gc.C) {
+	s.SetCharm(c, "dummy")
+	action, err := s.State.EnqueueActionWithOptions(s.unit.Tag(), "snapshot", nil, state.ActionOptions{
+		StreamOutput: true,
+	})
+	c.Assert(err, jc.ErrorIsNil)
+	rnr, err := s.factory.NewActionRunner(action.Id())
+	c.Assert(err, jc.ErrorIsNil)
+	data, err := rnr.Context().ActionData()
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(data.StreamOutput, jc.IsTrue)
+}
+
 func (s *FactorySuite) TestNewActionRunnerBadCharm(c *gc.C) {
 	rnr, err := s.factory.NewActionRunner("irrelevant")
 	c.Assert(rnr, gc.IsNil)
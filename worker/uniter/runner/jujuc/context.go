@@ -97,6 +97,10 @@ type actionHookContext interface {
 
 	// SetActionFailed sets a failure state for the Action.
 	SetActionFailed() error
+
+	// LogActionMessage records a progress message for the Action
+	// while it runs.
+	LogActionMessage(string) error
 }
 
 // ContextUnit is the part of a hook context related to the unit.
@@ -6,18 +6,58 @@ package runner
 import (
 	"bufio"
 	"io"
+	"strings"
 	"sync"
 	"time"
 
+	"github.com/juju/errors"
 	"github.com/juju/loggo"
 )
 
+const (
+	// maxOutputLines is the number of lines of output kept by a
+	// hookLogger, to be recorded against an action that is stopped
+	// before it finishes.
+	maxOutputLines = 100
+
+	// maxForwardLines is the number of lines of output a hookLogger
+	// holds while waiting to forward them; if forwarding falls further
+	// behind, the oldest lines are dropped.
+	maxForwardLines = 1000
+)
+
+// forwardInterval is the least time between the batches of output a
+// hookLogger forwards, so that a hook that writes a lot of output
+// costs no more than one API call per interval.
+var forwardInterval = time.Second
+
 type hookLogger struct {
 	r       io.ReadCloser
 	done    chan struct{}
 	mu      sync.Mutex
 	stopped bool
 	logger  loggo.Logger
+	lines   []string
+
+	// forward, if not nil, is called with batches of the lines of
+	// output read, from a goroutine of its own.
+	forward   func(lines []string) error
+	pending   []string
+	dropped   int
+	wake      chan struct{}
+	stopc     chan struct{}
+	forwarded chan struct{}
+}
+
+// start starts reading output, and forwarding it if required.
+func (l *hookLogger) start() {
+	if l.forward != nil {
+		l.wake = make(chan struct{}, 1)
+		l.stopc = make(chan struct{})
+		l.forwarded = make(chan struct{})
+		go l.forwardLoop()
+	}
+	go l.run()
 }
 
 func (l *hookLogger) run() {
@@ -38,8 +78,88 @@ func (l *hookLogger) run() {
 			return
 		}
 		l.logger.Infof("%s", line)
+		if len(l.lines) == maxOutputLines {
+			l.lines = l.lines[1:]
+		}
+		l.lines = append(l.lines, string(line))
+		if l.forward != nil {
+			l.queue(string(line))
+			select {
+			case l.wake <- struct{}{}:
+			default:
+			}
+		}
+		l.mu.Unlock()
+	}
+}
+
+// queue adds lines to those waiting to be forwarded, dropping the
+// oldest if there are too many. It must be called with l.mu held.
+func (l *hookLogger) queue(lines ...string) {
+	l.pending = append(l.pending, lines...)
+	if n := len(l.pending) - maxForwardLines; n > 0 {
+		l.pending = l.pending[n:]
+		l.dropped += n
+	}
+}
+
+// forwardLoop forwards the lines of output read, in batches at most
+// one forwardInterval apart, until the logger is stopped.
+func (l *hookLogger) forwardLoop() {
+	defer close(l.forwarded)
+	for {
+		select {
+		case <-l.wake:
+		case <-l.stopc:
+			l.flush()
+			return
+		}
+		if !l.flush() {
+			return
+		}
+		select {
+		case <-time.After(forwardInterval):
+		case <-l.stopc:
+			l.flush()
+			return
+		}
+	}
+}
+
+// flush forwards the lines waiting to be. Lines that cannot be
+// forwarded are kept to be tried again with the next batch. It returns
+// false if forwarding can never succeed.
+func (l *hookLogger) flush() bool {
+	l.mu.Lock()
+	lines, dropped := l.pending, l.dropped
+	l.pending, l.dropped = nil, 0
+	l.mu.Unlock()
+	if dropped > 0 {
+		logger.Warningf("dropped %d lines of hook output not yet forwarded", dropped)
+	}
+	if len(lines) == 0 {
+		return true
+	}
+	err := l.forward(lines)
+	if errors.IsNotImplemented(err) {
+		logger.Warningf("not forwarding hook output: %v", err)
+		return false
+	}
+	if err != nil {
+		logger.Warningf("cannot forward hook output, will retry: %v", err)
+		l.mu.Lock()
+		l.pending, lines = lines, l.pending
+		l.queue(lines...)
 		l.mu.Unlock()
 	}
+	return true
+}
+
+// output returns the last lines of output logged.
+func (l *hookLogger) output() string {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return strings.Join(l.lines, "\n")
 }
 
 func (l *hookLogger) stop() {
@@ -57,4 +177,10 @@ func (l *hookLogger) stop() {
 	l.mu.Lock()
 	l.stopped = true
 	l.mu.Unlock()
+	if l.forward != nil {
+		// Forward what is left before the action finishes, after
+		// which no more messages can be logged against it.
+		close(l.stopc)
+		<-l.forwarded
+	}
 }
@@ -0,0 +1,32 @@
+// Copyright 2017 Canonical Ltd.
+// Licensed under the AGPLv3, see LICENCE file for details.
+
+package runner
+
+import (
+	"os"
+	"os/exec"
+	"strconv"
+
+	"github.com/juju/errors"
+)
+
+// setProcessGroup does nothing on Windows, where killProcessTree finds
+// the children of a process without the help of a process group.
+func setProcessGroup(cmd *exec.Cmd) {}
+
+// terminateProcessTree asks the given process and all of its children
+// to stop. Windows has no equivalent of SIGTERM for console processes,
+// so they are killed straight away.
+func terminateProcessTree(process *os.Process) error {
+	return killProcessTree(process)
+}
+
+// killProcessTree kills the given process and all of its children.
+func killProcessTree(process *os.Process) error {
+	output, err := exec.Command("taskkill", "/F", "/T", "/PID", strconv.Itoa(process.Pid)).CombinedOutput()
+	if err != nil {
+		return errors.Annotatef(err, "taskkill failed: %s", output)
+	}
+	return nil
+}
@@ -49,6 +49,7 @@ type Context interface {
 	Id() string
 	HookVars(paths context.Paths) ([]string, error)
 	ActionData() (*context.ActionData, error)
+	LogActionMessages(messages []string) error
 	SetProcess(process context.HookProcess)
 	HasExecutionSetUnitStatus() bool
 	ResetExecutionSetUnitStatus()
@@ -237,10 +238,23 @@ func (runner *runner) runCharmHook(hookName string, env []string, charmLocation
 	if err != nil {
 		return err
 	}
+	var actionData *context.ActionData
+	if charmLocation == "actions" {
+		if actionData, err = runner.context.ActionData(); err != nil {
+			return errors.Trace(err)
+		}
+	}
 	hookCmd := hookCommand(hook)
 	ps := exec.Command(hookCmd[0], hookCmd[1:]...)
 	ps.Env = env
 	ps.Dir = charmDir
+	stoppable := actionData != nil && (actionData.Timeout > 0 || actionData.Cancel != nil)
+	if stoppable {
+		// The process is stopped along with any children it
+		// starts if it runs for too long or is cancelled, so
+		// give it a process group of its own.
+		setProcessGroup(ps)
+	}
 	outReader, outWriter, err := os.Pipe()
 	if err != nil {
 		return errors.Errorf("cannot make logging pipe: %v", err)
@@ -252,19 +266,79 @@ func (runner *runner) runCharmHook(hookName string, env []string, charmLocation
 		done:   make(chan struct{}),
 		logger: runner.getLogger(hookName),
 	}
-	go hookLogger.run()
+	if actionData != nil && actionData.StreamOutput {
+		hookLogger.forward = runner.context.LogActionMessages
+	}
+	hookLogger.start()
 	err = ps.Start()
 	outWriter.Close()
 	if err == nil {
 		// Record the *os.Process of the hook
 		runner.context.SetProcess(hookProcess{ps.Process})
 		// Block until execution finishes
-		err = ps.Wait()
+		if stoppable {
+			err = waitForAction(ps, actionData, clock.WallClock)
+		} else {
+			err = ps.Wait()
+		}
 	}
 	hookLogger.stop()
+	if actionData != nil && (actionData.TimedOut || actionData.Cancelled) {
+		// Keep the output of the action as far as it got, alongside
+		// any results it set before it was stopped.
+		if logErr := runner.context.UpdateActionResults([]string{"Log"}, hookLogger.output()); logErr != nil {
+			logger.Warningf("cannot record output of action %q: %v", actionData.Name, logErr)
+		}
+	}
 	return errors.Trace(err)
 }
 
+// actionCancelGracePeriod is the time a cancelled action process is
+// given to stop after being asked to, before it is killed.
+var actionCancelGracePeriod = 10 * time.Second
+
+// waitForAction waits for the action process to finish. If it runs for
+// longer than the action's timeout, the process and any children it has
+// started are killed, and the action is marked as timed out. If the
+// action is cancelled, they are asked to stop, and killed if they have
+// not done so after actionCancelGracePeriod; the action is marked as
+// cancelled.
+func waitForAction(ps *exec.Cmd, actionData *context.ActionData, clock clock.Clock) error {
+	done := make(chan error, 1)
+	go func() {
+		done <- ps.Wait()
+	}()
+	var timeout <-chan time.Time
+	if actionData.Timeout > 0 {
+		timeout = clock.After(actionData.Timeout)
+	}
+	select {
+	case err := <-done:
+		return err
+	case <-timeout:
+		logger.Infof("action %q timed out after %v, killing process %d",
+			actionData.Name, actionData.Timeout, ps.Process.Pid)
+		actionData.TimedOut = true
+	case <-actionData.Cancel:
+		logger.Infof("action %q cancelled, stopping process %d", actionData.Name, ps.Process.Pid)
+		actionData.Cancelled = true
+		if err := terminateProcessTree(ps.Process); err != nil {
+			logger.Warningf("cannot stop action %q: %v", actionData.Name, err)
+		}
+		select {
+		case err := <-done:
+			return err
+		case <-clock.After(actionCancelGracePeriod):
+			logger.Infof("action %q did not stop after %v, killing process %d",
+				actionData.Name, actionCancelGracePeriod, ps.Process.Pid)
+		}
+	}
+	if err := killProcessTree(ps.Process); err != nil {
+		logger.Warningf("cannot kill action %q: %v", actionData.Name, err)
+	}
+	return <-done
+}
+
 func (runner *runner) startJujucServer() (*jujuc.Server, error) {
 	// Prepare server.
 	getCmd := func(ctxId, cmdName string) (cmd.Command, error) {
@@ -151,6 +151,8 @@ type MockContext struct {
 	actionParams    map[string]interface{}
 	actionParamsErr error
 	actionResults   map[string]interface{}
+	actionMessages  []string
+	logActionErrs   []error
 	expectPid       int
 	flushBadge      string
 	flushFailure    error
@@ -197,6 +199,22 @@ func (ctx *MockContext) UpdateActionResults(keys []string, value string) error {
 	return nil
 }
 
+func (ctx *MockContext) LogActionMessage(message string) error {
+	return ctx.LogActionMessages([]string{message})
+}
+
+func (ctx *MockContext) LogActionMessages(messages []string) error {
+	if len(ctx.logActionErrs) > 0 {
+		err := ctx.logActionErrs[0]
+		ctx.logActionErrs = ctx.logActionErrs[1:]
+		if err != nil {
+			return err
+		}
+	}
+	ctx.actionMessages = append(ctx.actionMessages, messages...)
+	return nil
+}
+
 type RunMockContextSuite struct {
 	envtesting.IsolationSuite
 	paths runnertesting.RealPaths
@@ -289,6 +307,127 @@ func (s *RunMockContextSuite) TestRunActionFlushFailure(c *gc.C) {
 	s.assertRecordedPid(c, ctx.expectPid)
 }
 
+func (s *RunMockContextSuite) TestRunActionTimedOut(c *gc.C) {
+	ctx := &MockContext{
+		actionData: &context.ActionData{
+			Name:    "something-happened",
+			Timeout: 100 * time.Millisecond,
+		},
+		actionResults: map[string]interface{}{},
+	}
+	makeCharm(c, hookSpec{
+		dir:    "actions",
+		name:   hookName,
+		perm:   0700,
+		stdout: "started",
+		sleep:  10,
+	}, s.paths.GetCharmDir())
+	start := time.Now()
+	err := runner.NewRunner(ctx, s.paths).RunAction("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(time.Since(start) < 5*time.Second, jc.IsTrue)
+	c.Assert(ctx.flushBadge, gc.Equals, "something-happened")
+	c.Assert(ctx.flushFailure, gc.NotNil)
+	c.Assert(ctx.actionData.TimedOut, jc.IsTrue)
+	c.Assert(ctx.actionResults["Log"], gc.Equals, "started")
+	s.assertRecordedPid(c, ctx.expectPid)
+}
+
+func (s *RunMockContextSuite) TestRunActionCancelled(c *gc.C) {
+	cancel := make(chan struct{})
+	ctx := &MockContext{
+		actionData: &context.ActionData{
+			Name:   "something-happened",
+			Cancel: cancel,
+		},
+		actionResults: map[string]interface{}{},
+	}
+	makeCharm(c, hookSpec{
+		dir:    "actions",
+		name:   hookName,
+		perm:   0700,
+		stdout: "started",
+		sleep:  10,
+	}, s.paths.GetCharmDir())
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		close(cancel)
+	}()
+	start := time.Now()
+	err := runner.NewRunner(ctx, s.paths).RunAction("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(time.Since(start) < 5*time.Second, jc.IsTrue)
+	c.Assert(ctx.flushBadge, gc.Equals, "something-happened")
+	c.Assert(ctx.flushFailure, gc.NotNil)
+	c.Assert(ctx.actionData.Cancelled, jc.IsTrue)
+	c.Assert(ctx.actionData.TimedOut, jc.IsFalse)
+	c.Assert(ctx.actionResults["Log"], gc.Equals, "started")
+	s.assertRecordedPid(c, ctx.expectPid)
+}
+
+func (s *RunMockContextSuite) TestRunActionWithinTimeout(c *gc.C) {
+	ctx := &MockContext{
+		actionData: &context.ActionData{
+			Name:    "something-happened",
+			Timeout: time.Minute,
+		},
+	}
+	makeCharm(c, hookSpec{
+		dir:  "actions",
+		name: hookName,
+		perm: 0700,
+	}, s.paths.GetCharmDir())
+	err := runner.NewRunner(ctx, s.paths).RunAction("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.flushFailure, gc.IsNil)
+	c.Assert(ctx.actionData.TimedOut, jc.IsFalse)
+	c.Assert(ctx.actionResults, gc.HasLen, 0)
+}
+
+func (s *RunMockContextSuite) TestRunActionStreamOutput(c *gc.C) {
+	ctx := &MockContext{
+		actionData: &context.ActionData{
+			Name:         "something-happened",
+			StreamOutput: true,
+		},
+	}
+	makeCharm(c, hookSpec{
+		dir:    "actions",
+		name:   hookName,
+		perm:   0700,
+		stdout: "out",
+		stderr: "err",
+	}, s.paths.GetCharmDir())
+	err := runner.NewRunner(ctx, s.paths).RunAction("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	c.Assert(ctx.flushFailure, gc.IsNil)
+	c.Assert(ctx.actionMessages, jc.SameContents, []string{"out", "err"})
+}
+
+func (s *RunMockContextSuite) TestRunActionStreamOutputRetries(c *gc.C) {
+	s.PatchValue(runner.ForwardInterval, time.Millisecond)
+	ctx := &MockContext{
+		actionData: &context.ActionData{
+			Name:         "something-happened",
+			StreamOutput: true,
+		},
+		logActionErrs: []error{errors.New("connection is shut down")},
+	}
+	makeCharm(c, hookSpec{
+		dir:    "actions",
+		name:   hookName,
+		perm:   0700,
+		stdout: "out",
+		stderr: "err",
+	}, s.paths.GetCharmDir())
+	err := runner.NewRunner(ctx, s.paths).RunAction("something-happened")
+	c.Assert(err, jc.ErrorIsNil)
+	// The output that could not be forwarded at first was forwarded
+	// later, along with any that followed it.
+	c.Assert(ctx.logActionErrs, gc.HasLen, 0)
+	c.Assert(ctx.actionMessages, jc.SameContents, []string{"out", "err"})
+}
+
 func (s *RunMockContextSuite) TestRunActionParamsFailure(c *gc.C) {
 	expectErr := errors.New("stork")
 	ctx := &MockContext{
@@ -102,6 +102,11 @@ type Uniter struct {
 	// downloader is the downloader that should be used to get the charm
 	// archive.
 	downloader charm.Downloader
+
+	// actionCancellations tells running actions that they have been
+	// cancelled. It is shared by each remote state watcher the uniter
+	// starts.
+	actionCancellations *remotestate.ActionCancellations
 }
 
 // UniterParams hold all the necessary parameters for a new Uniter.
@@ -148,6 +153,7 @@ func NewUniter(uniterParams *UniterParams) (*Uniter, error) {
 		observer:             uniterParams.Observer,
 		clock:                uniterParams.Clock,
 		downloader:           uniterParams.Downloader,
+		actionCancellations:  remotestate.NewActionCancellations(),
 	}
 	err := catacomb.Invoke(catacomb.Plan{
 		Site: &u.catacomb,
@@ -246,6 +252,7 @@ func (u *Uniter) loop(unitTag names.UnitTag) (err error) {
 				UpdateStatusChannel: u.updateStatusAt,
 				CommandChannel:      u.commandChannel,
 				RetryHookChannel:    retryHookChan,
+				ActionCancellations: u.actionCancellations,
 			})
 		if err != nil {
 			return errors.Trace(err)